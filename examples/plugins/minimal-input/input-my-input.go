@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters/plugin_manager"
+	"github.com/openconfig/gnmic/pkg/inputs"
+	"github.com/openconfig/gnmic/pkg/inputs/input_plugin"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	// TODO: Choose a name for your input,
+	// it must match the plugin binary name.
+	inputType = "input-my-input"
+)
+
+type myInput struct {
+	// TODO: Add your config struct fields here
+
+	outputs []outputs.Output
+}
+
+func (i *myInput) Start(ctx context.Context, name string, cfg map[string]interface{}, opts ...inputs.Option) error {
+	// decode the plugin config
+	err := outputs.DecodeConfig(cfg, i)
+	if err != nil {
+		return err
+	}
+	// apply options
+	for _, opt := range opts {
+		if err := opt(i); err != nil {
+			return err
+		}
+	}
+	// TODO: Start receiving messages and write them
+	// to i.outputs using Write or WriteEvent.
+	return nil
+}
+
+func (i *myInput) Close() error {
+	return nil
+}
+
+func (i *myInput) SetOutputs(outs map[string]outputs.Output) {
+	for _, o := range outs {
+		i.outputs = append(i.outputs, o)
+	}
+}
+
+func (i *myInput) SetLogger(*log.Logger) {}
+
+// event processors are applied in gNMIc's main process.
+func (i *myInput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (i *myInput) SetName(string) {}
+
+func main() {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Output:      os.Stderr,
+		DisableTime: true,
+	})
+
+	logger.Info("starting plugin input", "name", inputType)
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: plugin_manager.HandshakeConfig,
+		Plugins: map[string]plugin.Plugin{
+			inputType: &input_plugin.InputPlugin{Impl: &myInput{}},
+		},
+		Logger: logger,
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/formatters/plugin_manager"
+	"github.com/openconfig/gnmic/pkg/outputs"
+	"github.com/openconfig/gnmic/pkg/outputs/output_plugin"
+)
+
+const (
+	// TODO: Choose a name for your output,
+	// it must match the plugin binary name.
+	outputType = "output-my-output"
+)
+
+type myOutput struct {
+	// TODO: Add your config struct fields here
+}
+
+func (o *myOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	// decode the plugin config
+	err := outputs.DecodeConfig(cfg, o)
+	if err != nil {
+		return err
+	}
+	// TODO: Other initialization steps...
+	return nil
+}
+
+func (o *myOutput) Write(ctx context.Context, msg proto.Message, meta outputs.Meta) {
+	// TODO: Write the proto message
+}
+
+func (o *myOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	// TODO: Write the event message
+}
+
+func (o *myOutput) Close() error {
+	return nil
+}
+
+func (o *myOutput) String() string {
+	return outputType
+}
+
+// the below methods are called in gNMIc's main process only.
+func (o *myOutput) RegisterMetrics(*prometheus.Registry) {}
+
+func (o *myOutput) SetLogger(*log.Logger) {}
+
+func (o *myOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (o *myOutput) SetName(string) {}
+
+func (o *myOutput) SetClusterName(string) {}
+
+func (o *myOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+
+func main() {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Output:      os.Stderr,
+		DisableTime: true,
+	})
+
+	logger.Info("starting plugin output", "name", outputType)
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: plugin_manager.HandshakeConfig,
+		Plugins: map[string]plugin.Plugin{
+			outputType: &output_plugin.OutputPlugin{Impl: &myOutput{}},
+		},
+		Logger: logger,
+	})
+}
@@ -49,6 +49,7 @@ type Context struct {
 
 var ActionTypes = []string{
 	"gnmi",
+	"gnoi",
 	"http",
 	"script",
 	"template",
@@ -10,6 +10,7 @@ package all
 
 import (
 	_ "github.com/openconfig/gnmic/pkg/actions/gnmi_action"
+	_ "github.com/openconfig/gnmic/pkg/actions/gnoi_action"
 	_ "github.com/openconfig/gnmic/pkg/actions/http_action"
 	_ "github.com/openconfig/gnmic/pkg/actions/script_action"
 	_ "github.com/openconfig/gnmic/pkg/actions/template_action"
@@ -0,0 +1,234 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// executions statuses
+const (
+	// the actions are run
+	ExecutionRunning = "running"
+	// the actions ran successfully
+	ExecutionSucceeded = "succeeded"
+	// one of the actions failed
+	ExecutionFailed = "failed"
+	// the actions were not run, the trigger is in dry-run mode
+	ExecutionDryRun = "dry-run"
+	// the actions were not run, an execution with the same key
+	// is pending or in its cooldown period
+	ExecutionSuppressed = "suppressed"
+	// the actions are waiting to be approved
+	ExecutionPending = "pending"
+	// the actions were rejected
+	ExecutionRejected = "rejected"
+	// the actions were not approved in time
+	ExecutionExpired = "expired"
+)
+
+// maximum number of executions kept in the audit trail
+const maxAuditTrailSize = 1000
+
+var (
+	ErrExecutionNotFound   = errors.New("execution not found")
+	ErrExecutionNotPending = errors.New("execution is not pending approval")
+)
+
+// Execution is a run of the actions of a trigger.
+type Execution struct {
+	ID      string                 `json:"id,omitempty"`
+	Trigger string                 `json:"trigger,omitempty"`
+	Key     string                 `json:"key,omitempty"`
+	Actions []string               `json:"actions,omitempty"`
+	Input   interface{}            `json:"input,omitempty"`
+	Status  string                 `json:"status,omitempty"`
+	Created time.Time              `json:"created,omitempty"`
+	Updated time.Time              `json:"updated,omitempty"`
+	Expires *time.Time             `json:"expires,omitempty"`
+	User    string                 `json:"user,omitempty"`
+	Results map[string]interface{} `json:"results,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+
+	// runs the actions once approved
+	run func(*Execution)
+	// called with a copy of the execution each time it changes
+	notify func(*Execution)
+}
+
+// executions holds the audit trail of the triggers executions,
+// the executions pending approval and the cooldowns of the executions keys.
+var executions = struct {
+	sync.Mutex
+	seq     uint64
+	trail   []*Execution
+	pending map[string]*Execution
+	// end of the cooldown period, by trigger and key
+	cooldowns map[string]time.Time
+}{
+	pending:   make(map[string]*Execution),
+	cooldowns: make(map[string]time.Time),
+}
+
+// Claim reports whether the actions of trigger can run for key.
+// If they can, they can't run again for the same key during the cooldown,
+// nor while an execution for that key is pending approval.
+func Claim(trigger, key string, cooldown time.Duration, now time.Time) bool {
+	ck := trigger + "\x00" + key
+	executions.Lock()
+	defer executions.Unlock()
+	for _, ex := range executions.pending {
+		if ex.Trigger == trigger && ex.Key == key {
+			return false
+		}
+	}
+	if now.Before(executions.cooldowns[ck]) {
+		return false
+	}
+	if cooldown > 0 {
+		executions.cooldowns[ck] = now.Add(cooldown)
+	} else {
+		delete(executions.cooldowns, ck)
+	}
+	return true
+}
+
+// Record adds execution ex to the audit trail.
+// If its status is pending, it is run by fn once approved.
+// notify, if not nil, is called with a copy of the execution each time it changes.
+func Record(ex *Execution, fn func(*Execution), notify func(*Execution)) {
+	executions.Lock()
+	defer executions.Unlock()
+	executions.seq++
+	ex.ID = fmt.Sprintf("%d-%d", ex.Created.UnixNano(), executions.seq)
+	ex.Updated = ex.Created
+	ex.run = fn
+	ex.notify = notify
+	if ex.Status == ExecutionPending {
+		executions.pending[ex.ID] = ex
+	}
+	executions.trail = append(executions.trail, ex)
+	if n := len(executions.trail); n > maxAuditTrailSize {
+		executions.trail = executions.trail[n-maxAuditTrailSize:]
+	}
+	ex.changed()
+}
+
+// Complete sets the status of execution ex, once its actions ran,
+// given their results and error.
+func Complete(ex *Execution, results map[string]interface{}, err error) {
+	executions.Lock()
+	defer executions.Unlock()
+	ex.Results = results
+	ex.Status = ExecutionSucceeded
+	if err != nil {
+		ex.Status = ExecutionFailed
+		ex.Error = err.Error()
+	}
+	ex.Updated = time.Now()
+	ex.changed()
+}
+
+// Approve runs the pending execution id, approved by user.
+func Approve(id, user string) (*Execution, error) {
+	ex, snap, err := decide(id, user, ExecutionRunning)
+	if err != nil {
+		return nil, err
+	}
+	go ex.run(ex)
+	return snap, nil
+}
+
+// Reject cancels the pending execution id, rejected by user.
+func Reject(id, user string) (*Execution, error) {
+	_, snap, err := decide(id, user, ExecutionRejected)
+	return snap, err
+}
+
+// decide sets the status of the pending execution id.
+// It returns the execution and a copy of it.
+func decide(id, user, status string) (*Execution, *Execution, error) {
+	executions.Lock()
+	defer executions.Unlock()
+	expirePending(time.Now())
+	ex, ok := executions.pending[id]
+	if !ok {
+		for _, e := range executions.trail {
+			if e.ID == id {
+				return nil, nil, fmt.Errorf("%w: %s", ErrExecutionNotPending, e.Status)
+			}
+		}
+		return nil, nil, ErrExecutionNotFound
+	}
+	delete(executions.pending, id)
+	ex.Status = status
+	ex.User = user
+	ex.Updated = time.Now()
+	ex.changed()
+	return ex, ex.snapshot(), nil
+}
+
+// AuditTrail returns the executions with status, all of them if status is empty,
+// oldest first.
+func AuditTrail(status string) []*Execution {
+	executions.Lock()
+	defer executions.Unlock()
+	expirePending(time.Now())
+	rs := make([]*Execution, 0, len(executions.trail))
+	for _, ex := range executions.trail {
+		if status != "" && ex.Status != status {
+			continue
+		}
+		rs = append(rs, ex.snapshot())
+	}
+	return rs
+}
+
+// GetExecution returns the execution id from the audit trail.
+func GetExecution(id string) (*Execution, error) {
+	executions.Lock()
+	defer executions.Unlock()
+	expirePending(time.Now())
+	for _, ex := range executions.trail {
+		if ex.ID == id {
+			return ex.snapshot(), nil
+		}
+	}
+	return nil, ErrExecutionNotFound
+}
+
+// expirePending sets the status of the pending executions
+// not approved in time. It is called with the lock held.
+func expirePending(now time.Time) {
+	for id, ex := range executions.pending {
+		if ex.Expires == nil || now.Before(*ex.Expires) {
+			continue
+		}
+		delete(executions.pending, id)
+		ex.Status = ExecutionExpired
+		ex.Updated = now
+		ex.changed()
+	}
+}
+
+func (ex *Execution) changed() {
+	if ex.notify != nil {
+		ex.notify(ex.snapshot())
+	}
+}
+
+func (ex *Execution) snapshot() *Execution {
+	c := *ex
+	c.run = nil
+	c.notify = nil
+	return &c
+}
@@ -0,0 +1,116 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package actions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClaim(t *testing.T) {
+	now := time.Now()
+	if !Claim("claim", "k1", time.Minute, now) {
+		t.Fatal("expected the first claim to succeed")
+	}
+	if Claim("claim", "k1", time.Minute, now.Add(30*time.Second)) {
+		t.Error("expected a claim within the cooldown to fail")
+	}
+	if !Claim("claim", "k2", time.Minute, now.Add(30*time.Second)) {
+		t.Error("expected a claim for another key to succeed")
+	}
+	if !Claim("claim", "k1", time.Minute, now.Add(time.Minute)) {
+		t.Error("expected a claim after the cooldown to succeed")
+	}
+	if !Claim("claim-no-cooldown", "", 0, now) || !Claim("claim-no-cooldown", "", 0, now) {
+		t.Error("expected the claims without cooldown to succeed")
+	}
+}
+
+func TestApproveReject(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	ran := make(chan *Execution, 1)
+	var notified []string
+	ex := &Execution{
+		Trigger: "approve",
+		Key:     "k1",
+		Status:  ExecutionPending,
+		Created: now,
+		Expires: &expires,
+	}
+	Record(ex, func(ex *Execution) {
+		Complete(ex, map[string]interface{}{"a1": "ok"}, nil)
+		ran <- ex
+	}, func(ex *Execution) { notified = append(notified, ex.Status) })
+
+	if Claim("approve", "k1", 0, now) {
+		t.Error("expected a claim to fail while an execution is pending")
+	}
+	if len(AuditTrail(ExecutionPending)) == 0 {
+		t.Fatal("expected a pending execution")
+	}
+	rex, err := Approve(ex.ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rex.User != "alice" || rex.Status != ExecutionRunning {
+		t.Errorf("unexpected execution %+v", rex)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected the execution to run once approved")
+	}
+	if _, err := Approve(ex.ID, "alice"); !errors.Is(err, ErrExecutionNotPending) {
+		t.Errorf("expected an already approved execution not to be approved again, got %v", err)
+	}
+	got, err := GetExecution(ex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != ExecutionSucceeded {
+		t.Errorf("unexpected status %q", got.Status)
+	}
+	want := []string{ExecutionPending, ExecutionRunning, ExecutionSucceeded}
+	if len(notified) != len(want) {
+		t.Fatalf("unexpected notifications %v", notified)
+	}
+	for i := range want {
+		if notified[i] != want[i] {
+			t.Errorf("unexpected notifications %v", notified)
+		}
+	}
+
+	ex2 := &Execution{Trigger: "approve", Key: "k2", Status: ExecutionPending, Created: now}
+	Record(ex2, func(*Execution) { t.Error("a rejected execution must not run") }, nil)
+	if _, err := Reject(ex2.ID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reject("unknown", "bob"); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestExpirePending(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(-time.Second)
+	ex := &Execution{Trigger: "expire", Status: ExecutionPending, Created: now, Expires: &expires}
+	Record(ex, func(*Execution) { t.Error("an expired execution must not run") }, nil)
+	if _, err := Approve(ex.ID, ""); !errors.Is(err, ErrExecutionNotPending) {
+		t.Errorf("expected an expired execution not to be approved, got %v", err)
+	}
+	got, err := GetExecution(ex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != ExecutionExpired {
+		t.Errorf("unexpected status %q", got.Status)
+	}
+}
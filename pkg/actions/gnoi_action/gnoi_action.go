@@ -0,0 +1,305 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnoi_action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"text/template"
+
+	// register the gNOI services usable by the action
+	_ "github.com/openconfig/gnoi/bgp"
+	_ "github.com/openconfig/gnoi/factory_reset"
+	_ "github.com/openconfig/gnoi/healthz"
+	_ "github.com/openconfig/gnoi/layer2"
+	_ "github.com/openconfig/gnoi/mpls"
+	_ "github.com/openconfig/gnoi/system"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/openconfig/gnmic/pkg/actions"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+const (
+	loggingPrefix  = "[gnoi_action] "
+	actionType     = "gnoi"
+	defaultTarget  = `{{ index .Input.Tags "source" }}`
+	defaultRequest = "{}"
+)
+
+func init() {
+	actions.Register(actionType, func() actions.Action {
+		return &gnoiAction{
+			logger:         log.New(io.Discard, "", 0),
+			m:              new(sync.RWMutex),
+			targetsConfigs: make(map[string]*types.TargetConfig),
+		}
+	})
+}
+
+type gnoiAction struct {
+	// action name
+	Name string `mapstructure:"name,omitempty"`
+	// target of the gNOI RPC, it can be a Go template
+	Target string `mapstructure:"target,omitempty"`
+	// gNOI RPC full name: `<service>/<method>`,
+	// e.g `gnoi.system.System/Reboot`
+	RPC string `mapstructure:"rpc,omitempty"`
+	// request message in JSON format, it can be a Go template
+	Request string `mapstructure:"request,omitempty"`
+	// Debug
+	Debug bool `mapstructure:"debug,omitempty"`
+
+	target  *template.Template
+	request *template.Template
+
+	method  string
+	stream  bool
+	reqType protoreflect.MessageType
+	rspType protoreflect.MessageType
+
+	logger *log.Logger
+
+	m              *sync.RWMutex
+	targetsConfigs map[string]*types.TargetConfig
+}
+
+func (g *gnoiAction) Init(cfg map[string]interface{}, opts ...actions.Option) error {
+	err := actions.DecodeConfig(cfg, g)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.Name == "" {
+		return fmt.Errorf("action type %q missing name field", actionType)
+	}
+	g.setDefaults()
+	err = g.parseTemplates()
+	if err != nil {
+		return err
+	}
+	err = g.resolveRPC()
+	if err != nil {
+		return err
+	}
+	g.logger.Printf("action name %q of type %q initialized: %v", g.Name, actionType, g)
+	return nil
+}
+
+func (g *gnoiAction) Run(ctx context.Context, aCtx *actions.Context) (interface{}, error) {
+	g.m.Lock()
+	for n, tc := range aCtx.Targets {
+		g.targetsConfigs[n] = tc
+	}
+	g.m.Unlock()
+	in := &actions.Context{
+		Input: aCtx.Input,
+		Env:   aCtx.Env,
+		Vars:  aCtx.Vars,
+	}
+	b := new(bytes.Buffer)
+	err := g.target.Execute(b, in)
+	if err != nil {
+		return nil, err
+	}
+	targetsConfigs := g.selectTargets(b.String())
+	b.Reset()
+	err = g.request.Execute(b, in)
+	if err != nil {
+		return nil, fmt.Errorf("request template exec error: %v", err)
+	}
+	req := g.reqType.New().Interface()
+	err = protojson.Unmarshal(b.Bytes(), req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s request: %v", g.RPC, err)
+	}
+
+	result := make(map[string]interface{})
+	errs := make([]error, 0)
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+	wg.Add(len(targetsConfigs))
+	for _, tc := range targetsConfigs {
+		go func(tc *types.TargetConfig) {
+			defer wg.Done()
+			res, err := g.runRPC(ctx, tc, req)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				g.logger.Printf("gnoi action error: %v", err)
+				errs = append(errs, err)
+				return
+			}
+			result[tc.Name] = res
+		}(tc)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		// return only the first errors
+		return nil, errs[0]
+	}
+	return result, nil
+}
+
+func (g *gnoiAction) NName() string { return g.Name }
+
+func (g *gnoiAction) setDefaults() {
+	if g.Target == "" {
+		g.Target = defaultTarget
+	}
+	if g.Request == "" {
+		g.Request = defaultRequest
+	}
+}
+
+func (g *gnoiAction) parseTemplates() error {
+	var err error
+	g.target, err = gtemplate.CreateTemplate(fmt.Sprintf("%s-target", g.Name), g.Target)
+	if err != nil {
+		return err
+	}
+	g.request, err = gtemplate.CreateTemplate(fmt.Sprintf("%s-request", g.Name), g.Request)
+	return err
+}
+
+// resolveRPC looks up the gNOI method and its messages types.
+func (g *gnoiAction) resolveRPC() error {
+	if g.RPC == "" {
+		return errors.New("rpc field is required")
+	}
+	svc, method, ok := strings.Cut(strings.TrimPrefix(g.RPC, "/"), "/")
+	if !ok {
+		return fmt.Errorf("invalid rpc %q, expected <service>/<method>", g.RPC)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return fmt.Errorf("unknown gNOI service %q", svc)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return fmt.Errorf("%q is not a gNOI service", svc)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return fmt.Errorf("unknown method %q in gNOI service %q", method, svc)
+	}
+	if md.IsStreamingClient() {
+		return fmt.Errorf("gNOI rpc %q is not supported: client streaming", g.RPC)
+	}
+	g.reqType, err = protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return err
+	}
+	g.rspType, err = protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return err
+	}
+	g.method = fmt.Sprintf("/%s/%s", svc, method)
+	g.stream = md.IsStreamingServer()
+	return nil
+}
+
+func (g *gnoiAction) selectTargets(tName string) []*types.TargetConfig {
+	if tName == "" {
+		return nil
+	}
+	targets := make([]*types.TargetConfig, 0, len(g.targetsConfigs))
+	g.m.RLock()
+	defer g.m.RUnlock()
+	// select all targets
+	if tName == "all" {
+		for _, tc := range g.targetsConfigs {
+			targets = append(targets, tc)
+		}
+		return targets
+	}
+	// select a few targets
+	for _, name := range strings.Split(tName, ",") {
+		if tc, ok := g.targetsConfigs[name]; ok {
+			targets = append(targets, tc)
+		}
+	}
+	return targets
+}
+
+// runRPC sends the request req to target tc.
+// It returns the response, or the list of responses of a server streaming RPC.
+func (g *gnoiAction) runRPC(ctx context.Context, tc *types.TargetConfig, req proto.Message) (interface{}, error) {
+	t := target.NewTarget(tc)
+	err := t.CreateGNMIClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("target %q: %v", tc.Name, err)
+	}
+	defer t.Close()
+	ctx = t.RequestContext(ctx)
+	if !g.stream {
+		rsp := g.rspType.New().Interface()
+		err = t.Conn().Invoke(ctx, g.method, req, rsp, t.CallOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %s failed: %v", tc.Name, g.RPC, err)
+		}
+		return toInterface(rsp)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := t.Conn().NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, g.method, t.CallOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("target %q: %s failed: %v", tc.Name, g.RPC, err)
+	}
+	err = stream.SendMsg(req)
+	if err != nil {
+		return nil, fmt.Errorf("target %q: %s failed: %v", tc.Name, g.RPC, err)
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		return nil, fmt.Errorf("target %q: %s failed: %v", tc.Name, g.RPC, err)
+	}
+	rsps := make([]interface{}, 0)
+	for {
+		rsp := g.rspType.New().Interface()
+		err = stream.RecvMsg(rsp)
+		if errors.Is(err, io.EOF) {
+			return rsps, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %s failed: %v", tc.Name, g.RPC, err)
+		}
+		v, err := toInterface(rsp)
+		if err != nil {
+			return nil, err
+		}
+		rsps = append(rsps, v)
+	}
+}
+
+// toInterface converts message m to the value of its JSON representation.
+func toInterface(m proto.Message) (interface{}, error) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
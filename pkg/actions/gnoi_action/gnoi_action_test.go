@@ -0,0 +1,142 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnoi_action
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnoi/system"
+	"google.golang.org/grpc"
+
+	"github.com/openconfig/gnmic/pkg/actions"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type systemServer struct {
+	system.UnimplementedSystemServer
+}
+
+func (s *systemServer) Time(context.Context, *system.TimeRequest) (*system.TimeResponse, error) {
+	return &system.TimeResponse{Time: 42}, nil
+}
+
+func (s *systemServer) Ping(req *system.PingRequest, stream system.System_PingServer) error {
+	for i := int32(0); i < req.GetCount(); i++ {
+		err := stream.Send(&system.PingResponse{Source: req.GetDestination(), Sequence: i + 1})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startSystemServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	system.RegisterSystemServer(s, &systemServer{})
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+	return l.Addr().String()
+}
+
+func TestGnoiActionInit(t *testing.T) {
+	for name, tt := range map[string]struct {
+		cfg     map[string]interface{}
+		wantErr bool
+	}{
+		"valid": {
+			cfg: map[string]interface{}{"name": "a1", "rpc": "gnoi.system.System/Reboot"},
+		},
+		"leading_slash": {
+			cfg: map[string]interface{}{"name": "a1", "rpc": "/gnoi.bgp.BGP/ClearBGPNeighbor"},
+		},
+		"missing_rpc": {
+			cfg:     map[string]interface{}{"name": "a1"},
+			wantErr: true,
+		},
+		"unknown_service": {
+			cfg:     map[string]interface{}{"name": "a1", "rpc": "gnoi.foo.Foo/Bar"},
+			wantErr: true,
+		},
+		"unknown_method": {
+			cfg:     map[string]interface{}{"name": "a1", "rpc": "gnoi.system.System/Foo"},
+			wantErr: true,
+		},
+		"client_streaming": {
+			cfg:     map[string]interface{}{"name": "a1", "rpc": "gnoi.system.System/SetPackage"},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := actions.Actions[actionType]().Init(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGnoiActionRun(t *testing.T) {
+	addr := startSystemServer(t)
+	insecure := true
+	targets := map[string]*types.TargetConfig{
+		"t1": {Name: "t1", Address: addr, Insecure: &insecure, Timeout: 2 * time.Second},
+	}
+	in := &formatters.EventMsg{
+		Tags:   map[string]string{"source": "t1"},
+		Values: map[string]interface{}{"peer": "10.0.0.1"},
+	}
+
+	a := actions.Actions[actionType]()
+	err := a.Init(map[string]interface{}{
+		"name": "time",
+		"rpc":  "gnoi.system.System/Time",
+	}, actions.WithTargets(targets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := a.Run(context.Background(), &actions.Context{Input: in})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"t1": map[string]interface{}{"time": "42"}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("unexpected result: %v", res)
+	}
+
+	a = actions.Actions[actionType]()
+	err = a.Init(map[string]interface{}{
+		"name":    "ping",
+		"rpc":     "gnoi.system.System/Ping",
+		"request": `{"destination": "{{ index .Input.Values "peer" }}", "count": 2}`,
+	}, actions.WithTargets(targets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = a.Run(context.Background(), &actions.Context{Input: in})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]interface{}{"t1": []interface{}{
+		map[string]interface{}{"source": "10.0.0.1", "sequence": float64(1)},
+		map[string]interface{}{"source": "10.0.0.1", "sequence": float64(2)},
+	}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("unexpected result: %v", res)
+	}
+}
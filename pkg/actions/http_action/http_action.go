@@ -26,6 +26,7 @@ import (
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/openconfig/gnmic/pkg/api/utils"
 	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
 )
 
 const (
@@ -75,11 +76,17 @@ func (h *httpAction) Init(cfg map[string]interface{}, opts ...actions.Option) er
 		return err
 	}
 
-	h.body, err = template.New("body").Funcs(funcMap).Parse(h.Body)
+	h.body, err = template.New("body").
+		Funcs(gtemplate.NewTemplateEngine().CreateFuncs()).
+		Funcs(funcMap).
+		Parse(h.Body)
 	if err != nil {
 		return err
 	}
-	h.url, err = template.New("url").Funcs(funcMap).Parse(h.URL)
+	h.url, err = template.New("url").
+		Funcs(gtemplate.NewTemplateEngine().CreateFuncs()).
+		Funcs(funcMap).
+		Parse(h.URL)
 	return err
 }
 
@@ -0,0 +1,85 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultCapabilitiesTargetsExtensionID is the registered extension ID used by default
+// for the Capabilities targets extension.
+const DefaultCapabilitiesTargetsExtensionID = gnmi_ext.ExtensionID_EID_EXPERIMENTAL
+
+// CapabilitiesTargets is the content of the extension gnmic attaches to the
+// CapabilityResponses it returns when aggregating the capabilities of its targets.
+// It is carried JSON encoded in a gNMI registered extension.
+type CapabilitiesTargets struct {
+	Models []*ModelTargets `json:"models,omitempty"`
+}
+
+// ModelTargets lists the targets supporting a model.
+type ModelTargets struct {
+	Name         string   `json:"name,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Targets      []string `json:"targets,omitempty"`
+}
+
+// Extension_CapabilitiesTargets creates a GNMIOption that adds a gNMI registered extension
+// with the supplied ID carrying the models targets ct.
+func Extension_CapabilitiesTargets(id gnmi_ext.ExtensionID, ct *CapabilitiesTargets) func(msg proto.Message) error {
+	return func(msg proto.Message) error {
+		if msg == nil {
+			return ErrInvalidMsgType
+		}
+		switch msg := msg.ProtoReflect().Interface().(type) {
+		case *gnmi.CapabilityResponse:
+			b, err := json.Marshal(ct)
+			if err != nil {
+				return err
+			}
+			fn := Extension(
+				&gnmi_ext.Extension{
+					Ext: &gnmi_ext.Extension_RegisteredExt{
+						RegisteredExt: &gnmi_ext.RegisteredExtension{
+							Id:  id,
+							Msg: b,
+						},
+					},
+				},
+			)
+			return fn(msg)
+		default:
+			return fmt.Errorf("option Extension_CapabilitiesTargets: %w: %T", ErrInvalidMsgType, msg)
+		}
+	}
+}
+
+// CapabilitiesTargetsFromExtensions returns the models targets carried by the registered
+// extension with the supplied ID, or nil if none of the extensions exts has that ID.
+func CapabilitiesTargetsFromExtensions(id gnmi_ext.ExtensionID, exts []*gnmi_ext.Extension) (*CapabilitiesTargets, error) {
+	for _, ext := range exts {
+		rext := ext.GetRegisteredExt()
+		if rext == nil || rext.GetId() != id {
+			continue
+		}
+		ct := new(CapabilitiesTargets)
+		err := json.Unmarshal(rext.GetMsg(), ct)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Capabilities targets extension: %w", err)
+		}
+		return ct, nil
+	}
+	return nil, nil
+}
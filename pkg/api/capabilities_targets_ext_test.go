@@ -0,0 +1,53 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+func TestCapabilitiesTargetsExtension(t *testing.T) {
+	ct := &CapabilitiesTargets{
+		Models: []*ModelTargets{
+			{
+				Name:         "openconfig-interfaces",
+				Organization: "OpenConfig working group",
+				Version:      "3.0.0",
+				Targets:      []string{"router1", "router2"},
+			},
+		},
+	}
+	rsp := new(gnmi.CapabilityResponse)
+	err := Extension_CapabilitiesTargets(DefaultCapabilitiesTargetsExtensionID, ct)(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := CapabilitiesTargetsFromExtensions(DefaultCapabilitiesTargetsExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ct) {
+		t.Errorf("got %+v, expected %+v", got, ct)
+	}
+	got, err = CapabilitiesTargetsFromExtensions(gnmi_ext.ExtensionID_EID_UNSET, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("expected no Capabilities targets, got %+v", got)
+	}
+	err = Extension_CapabilitiesTargets(DefaultCapabilitiesTargetsExtensionID, ct)(new(gnmi.GetResponse))
+	if err == nil {
+		t.Errorf("expected an error for a GetResponse")
+	}
+}
@@ -0,0 +1,83 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultCollectorExtensionID is the registered extension ID used by default
+// for the collector extension.
+const DefaultCollectorExtensionID = gnmi_ext.ExtensionID_EID_EXPERIMENTAL
+
+// CollectorInfo is the content of the collector extension
+// gnmic attaches to the SubscribeResponses it forwards.
+// It is carried JSON encoded in a gNMI registered extension.
+type CollectorInfo struct {
+	// name of the gnmic instance that collected the data
+	CollectorID string `json:"collector-id,omitempty"`
+	// name of the target the data was collected from
+	Target string `json:"target,omitempty"`
+	// time (nanoseconds since Unix epoch) at which the collector
+	// last received data from the target
+	ReceiveTimestamp int64 `json:"receive-timestamp,omitempty"`
+}
+
+// Extension_CollectorInfo creates a GNMIOption that adds a gNMI registered extension
+// with the supplied ID carrying the collector info ci.
+func Extension_CollectorInfo(id gnmi_ext.ExtensionID, ci *CollectorInfo) func(msg proto.Message) error {
+	return func(msg proto.Message) error {
+		if msg == nil {
+			return ErrInvalidMsgType
+		}
+		switch msg := msg.ProtoReflect().Interface().(type) {
+		case *gnmi.SubscribeResponse:
+			b, err := json.Marshal(ci)
+			if err != nil {
+				return err
+			}
+			fn := Extension(
+				&gnmi_ext.Extension{
+					Ext: &gnmi_ext.Extension_RegisteredExt{
+						RegisteredExt: &gnmi_ext.RegisteredExtension{
+							Id:  id,
+							Msg: b,
+						},
+					},
+				},
+			)
+			return fn(msg)
+		default:
+			return fmt.Errorf("option Extension_CollectorInfo: %w: %T", ErrInvalidMsgType, msg)
+		}
+	}
+}
+
+// CollectorInfoFromExtensions returns the collector info carried by the registered
+// extension with the supplied ID, or nil if none of the extensions exts has that ID.
+func CollectorInfoFromExtensions(id gnmi_ext.ExtensionID, exts []*gnmi_ext.Extension) (*CollectorInfo, error) {
+	for _, ext := range exts {
+		rext := ext.GetRegisteredExt()
+		if rext == nil || rext.GetId() != id {
+			continue
+		}
+		ci := new(CollectorInfo)
+		err := json.Unmarshal(rext.GetMsg(), ci)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode collector extension: %w", err)
+		}
+		return ci, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+func TestCollectorInfoExtension(t *testing.T) {
+	ci := &CollectorInfo{
+		CollectorID:      "gnmic1",
+		Target:           "router1",
+		ReceiveTimestamp: 42,
+	}
+	rsp := new(gnmi.SubscribeResponse)
+	err := Extension_CollectorInfo(DefaultCollectorExtensionID, ci)(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := CollectorInfoFromExtensions(DefaultCollectorExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ci) {
+		t.Errorf("got %+v, expected %+v", got, ci)
+	}
+	got, err = CollectorInfoFromExtensions(gnmi_ext.ExtensionID_EID_UNSET, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("expected no collector info, got %+v", got)
+	}
+	err = Extension_CollectorInfo(DefaultCollectorExtensionID, ci)(new(gnmi.GetRequest))
+	if err == nil {
+		t.Errorf("expected an error for a GetRequest")
+	}
+}
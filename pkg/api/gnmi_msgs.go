@@ -256,6 +256,11 @@ func Extension(ext *gnmi_ext.Extension) func(msg proto.Message) error {
 				msg.Extension = make([]*gnmi_ext.Extension, 0)
 			}
 			msg.Extension = append(msg.Extension, ext)
+		case *gnmi.CapabilityResponse:
+			if len(msg.Extension) == 0 {
+				msg.Extension = make([]*gnmi_ext.Extension, 0)
+			}
+			msg.Extension = append(msg.Extension, ext)
 		case *gnmi.GetRequest:
 			if len(msg.Extension) == 0 {
 				msg.Extension = make([]*gnmi_ext.Extension, 0)
@@ -9,6 +9,7 @@ require (
 	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0
 	github.com/jhump/protoreflect v1.16.0
 	github.com/juju/ratelimit v1.0.2
+	github.com/klauspost/compress v1.17.7
 	github.com/openconfig/gnmi v0.11.0
 	github.com/openconfig/grpctunnel v0.1.0
 	github.com/pkg/errors v0.9.1
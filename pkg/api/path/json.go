@@ -0,0 +1,173 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package path
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ErrUnknownListKeys is returned by FromJSON when
+// the key names of a list cannot be determined.
+var ErrUnknownListKeys = errors.New("unknown list keys")
+
+// ListKeysFunc returns the key names of the list at path p.
+// p is the path of the list without the keys of its last element.
+type ListKeysFunc func(p *gnmi.Path) []string
+
+// FromJSON decodes the JSON value b and returns the list of leaf updates
+// setting b at path base would result in.
+// A JSON array of objects is considered a YANG list, the keys of each entry are
+// added to the entry path using the key names returned by listKeys.
+// Other JSON arrays are considered leaf-lists and returned as a single update.
+// Empty objects are returned as an update with an empty JSON object value.
+// The updates values are JSON encoded.
+func FromJSON(base *gnmi.Path, b []byte, listKeys ListKeysFunc) ([]*gnmi.Update, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON value: %w", err)
+	}
+	if base == nil {
+		base = new(gnmi.Path)
+	}
+	w := &jsonWalker{listKeys: listKeys}
+	// a list given as a JSON array at the base path
+	if items, ok := v.([]any); ok && isObjectList(items) {
+		if len(base.GetElem()) == 0 {
+			return nil, fmt.Errorf("a JSON array of objects requires a base path")
+		}
+		parent := &gnmi.Path{Origin: base.GetOrigin(), Target: base.GetTarget(), Elem: base.GetElem()[:len(base.GetElem())-1]}
+		err := w.walkList(parent, base.GetElem()[len(base.GetElem())-1].GetName(), items)
+		if err != nil {
+			return nil, err
+		}
+		return w.updates, nil
+	}
+	err := w.walk(base, v)
+	if err != nil {
+		return nil, err
+	}
+	return w.updates, nil
+}
+
+type jsonWalker struct {
+	listKeys ListKeysFunc
+	updates  []*gnmi.Update
+}
+
+func (w *jsonWalker) walk(p *gnmi.Path, v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			return w.add(p, v)
+		}
+		names := make([]string, 0, len(v))
+		for k := range v {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if items, ok := v[name].([]any); ok && isObjectList(items) {
+				if err := w.walkList(p, name, items); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := w.walk(appendElem(p, &gnmi.PathElem{Name: name}), v[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// leaf or leaf-list
+		return w.add(p, v)
+	}
+}
+
+func (w *jsonWalker) walkList(parent *gnmi.Path, name string, items []any) error {
+	listPath := appendElem(parent, &gnmi.PathElem{Name: name})
+	var keyNames []string
+	if w.listKeys != nil {
+		keyNames = w.listKeys(listPath)
+	}
+	if len(keyNames) == 0 {
+		return fmt.Errorf("%w for list %q", ErrUnknownListKeys, GnmiPathToXPath(listPath, false))
+	}
+	for i, item := range items {
+		entry := item.(map[string]any)
+		keys := make(map[string]string, len(keyNames))
+		for _, kn := range keyNames {
+			kv, ok := entry[kn]
+			if !ok {
+				return fmt.Errorf("list %q entry %d is missing key %q", GnmiPathToXPath(listPath, false), i, kn)
+			}
+			s, err := jsonKeyValue(kv)
+			if err != nil {
+				return fmt.Errorf("list %q entry %d key %q: %w", GnmiPathToXPath(listPath, false), i, kn, err)
+			}
+			keys[kn] = s
+		}
+		ep := appendElem(parent, &gnmi.PathElem{Name: name, Key: keys})
+		if err := w.walk(ep, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonWalker) add(p *gnmi.Path, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.updates = append(w.updates, &gnmi.Update{
+		Path: p,
+		Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: b}},
+	})
+	return nil
+}
+
+func appendElem(p *gnmi.Path, pe *gnmi.PathElem) *gnmi.Path {
+	elems := make([]*gnmi.PathElem, 0, len(p.GetElem())+1)
+	elems = append(elems, p.GetElem()...)
+	elems = append(elems, pe)
+	return &gnmi.Path{Origin: p.GetOrigin(), Target: p.GetTarget(), Elem: elems}
+}
+
+// isObjectList returns true if items is a non empty list of JSON objects.
+func isObjectList(items []any) bool {
+	if len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonKeyValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprintf("%t", v), nil
+	}
+	return "", fmt.Errorf("unsupported key value type %T", v)
+}
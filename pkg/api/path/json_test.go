@@ -0,0 +1,146 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package path
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func testListKeys(p *gnmi.Path) []string {
+	elems := p.GetElem()
+	switch elems[len(elems)-1].GetName() {
+	case "interface":
+		return []string{"name"}
+	case "subinterface":
+		return []string{"index"}
+	case "neighbor":
+		return []string{"peer-address", "vrf"}
+	}
+	return nil
+}
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "leaves",
+			base: "/system",
+			in:   `{"hostname":"r1","mtu":1500,"enabled":true,"ratio":0.5}`,
+			want: map[string]string{
+				"system/hostname": `"r1"`,
+				"system/mtu":      `1500`,
+				"system/enabled":  `true`,
+				"system/ratio":    `0.5`,
+			},
+		},
+		{
+			name: "root_base",
+			in:   `{"system":{"hostname":"r1"}}`,
+			want: map[string]string{
+				"system/hostname": `"r1"`,
+			},
+		},
+		{
+			name: "nested_lists",
+			base: "/interfaces",
+			in:   `{"interface":[{"name":"e1","mtu":9000,"subinterface":[{"index":0,"vlan":10}]}]}`,
+			want: map[string]string{
+				"interfaces/interface[name=e1]/name":                        `"e1"`,
+				"interfaces/interface[name=e1]/mtu":                         `9000`,
+				"interfaces/interface[name=e1]/subinterface[index=0]/index": `0`,
+				"interfaces/interface[name=e1]/subinterface[index=0]/vlan":  `10`,
+			},
+		},
+		{
+			name: "list_at_base_path",
+			base: "/interfaces/interface",
+			in:   `[{"name":"e1"},{"name":"e2","description":"uplink"}]`,
+			want: map[string]string{
+				"interfaces/interface[name=e1]/name":        `"e1"`,
+				"interfaces/interface[name=e2]/name":        `"e2"`,
+				"interfaces/interface[name=e2]/description": `"uplink"`,
+			},
+		},
+		{
+			name: "multiple_keys",
+			base: "/bgp",
+			in:   `{"neighbor":[{"peer-address":"10.0.0.1","vrf":"default","peer-as":65000}]}`,
+			want: map[string]string{
+				"bgp/neighbor[peer-address=10.0.0.1][vrf=default]/peer-address": `"10.0.0.1"`,
+				"bgp/neighbor[peer-address=10.0.0.1][vrf=default]/vrf":          `"default"`,
+				"bgp/neighbor[peer-address=10.0.0.1][vrf=default]/peer-as":      `65000`,
+			},
+		},
+		{
+			name: "leaf_list_and_empty",
+			base: "/system",
+			in:   `{"dns":["1.1.1.1","8.8.8.8"],"empty":[null],"presence":{}}`,
+			want: map[string]string{
+				"system/dns":      `["1.1.1.1","8.8.8.8"]`,
+				"system/empty":    `[null]`,
+				"system/presence": `{}`,
+			},
+		},
+		{
+			name:    "unknown_list_keys",
+			base:    "/network-instances",
+			in:      `{"network-instance":[{"name":"default"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing_key",
+			base:    "/interfaces",
+			in:      `{"interface":[{"mtu":1500}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_json",
+			base:    "/interfaces",
+			in:      `{"interface":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := ParsePath(tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			upds, err := FromJSON(base, []byte(tt.in), testListKeys)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", upds)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make(map[string]string, len(upds))
+			for _, upd := range upds {
+				got[GnmiPathToXPath(upd.GetPath(), false)] = string(upd.GetVal().GetJsonVal())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d updates, got %d: %v", len(tt.want), len(got), got)
+			}
+			for p, v := range tt.want {
+				if got[p] != v {
+					t.Errorf("path %q: expected value %s, got %s", p, v, got[p])
+				}
+			}
+		})
+	}
+}
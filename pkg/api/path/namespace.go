@@ -0,0 +1,142 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package path
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Namespace modes, they define how the module-qualified
+// path elements names (`module:name`) are sent to a target.
+const (
+	// the elements names are sent as written.
+	NamespaceAsIs = ""
+	// the module prefixes are removed.
+	NamespaceStrip = "strip"
+	// the module prefix is kept on the first element and on the elements
+	// which module differs from their parent's one, it is removed from the others.
+	NamespaceFirst = "first"
+	// the module prefix of the first element is moved to the path origin,
+	// the other module prefixes are removed.
+	NamespaceOrigin = "origin"
+	// the path origin is moved to the first element as its module prefix.
+	NamespacePrefix = "prefix"
+)
+
+// ValidateNamespace checks that mode is a known namespace mode.
+func ValidateNamespace(mode string) error {
+	switch mode {
+	case NamespaceAsIs, NamespaceStrip, NamespaceFirst, NamespaceOrigin, NamespacePrefix:
+		return nil
+	}
+	return fmt.Errorf("unknown path namespace mode %q, must be one of %q, %q, %q or %q",
+		mode, NamespaceStrip, NamespaceFirst, NamespaceOrigin, NamespacePrefix)
+}
+
+// SplitModule splits a path element name into its module prefix and its name.
+// The module is empty if the name is not module-qualified.
+func SplitModule(name string) (string, string) {
+	if idx := strings.Index(name, ":"); idx > 0 && idx < len(name)-1 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// ApplyNamespace rewrites, in place, the origins and elements names of prefix
+// and of the paths relative to it, according to the namespace mode.
+// The origin and the first element of prefix, if it has any, apply to all the paths.
+func ApplyNamespace(mode string, prefix *gnmi.Path, paths ...*gnmi.Path) {
+	switch mode {
+	case NamespaceStrip:
+		for _, p := range append([]*gnmi.Path{prefix}, paths...) {
+			for _, pe := range p.GetElem() {
+				_, pe.Name = SplitModule(pe.GetName())
+			}
+		}
+	case NamespaceFirst:
+		parent := unqualifyRedundant("", prefix)
+		for _, p := range paths {
+			unqualifyRedundant(parent, p)
+		}
+	case NamespaceOrigin:
+		switch {
+		case len(prefix.GetElem()) > 0:
+			moduleToOrigin(prefix)
+		case prefix.GetOrigin() == "":
+			for _, p := range paths {
+				moduleToOrigin(p)
+			}
+		}
+		ApplyNamespace(NamespaceStrip, prefix, paths...)
+	case NamespacePrefix:
+		if len(prefix.GetElem()) > 0 {
+			qualifyFirst(prefix.GetOrigin(), prefix)
+			prefix.Origin = ""
+			return
+		}
+		qualified := 0
+		for _, p := range paths {
+			if len(p.GetElem()) == 0 {
+				continue
+			}
+			origin := p.GetOrigin()
+			if origin == "" {
+				origin = prefix.GetOrigin()
+			}
+			qualifyFirst(origin, p)
+			p.Origin = ""
+			qualified++
+		}
+		// the prefix origin is kept for the paths without elements
+		if prefix != nil && qualified == len(paths) {
+			prefix.Origin = ""
+		}
+	}
+}
+
+// unqualifyRedundant removes the module prefix of the elements of p
+// which module is the same as their parent's one, starting with module parent.
+// It returns the module of the last element.
+func unqualifyRedundant(parent string, p *gnmi.Path) string {
+	for _, pe := range p.GetElem() {
+		module, name := SplitModule(pe.GetName())
+		switch module {
+		case "":
+		case parent:
+			pe.Name = name
+		default:
+			parent = module
+		}
+	}
+	return parent
+}
+
+// moduleToOrigin sets the origin of p to the module of its first element,
+// if p has no origin.
+func moduleToOrigin(p *gnmi.Path) {
+	if p.GetOrigin() != "" || len(p.GetElem()) == 0 {
+		return
+	}
+	p.Origin, _ = SplitModule(p.GetElem()[0].GetName())
+}
+
+// qualifyFirst prefixes the first element of p with module,
+// if the element is not module-qualified.
+func qualifyFirst(module string, p *gnmi.Path) {
+	if module == "" || len(p.GetElem()) == 0 {
+		return
+	}
+	pe := p.GetElem()[0]
+	if m, _ := SplitModule(pe.GetName()); m == "" {
+		pe.Name = module + ":" + pe.GetName()
+	}
+}
@@ -0,0 +1,134 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package path
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestApplyNamespace(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		prefix     string
+		paths      []string
+		wantPrefix string
+		wantPaths  []string
+	}{
+		{
+			name:      "as_is",
+			mode:      NamespaceAsIs,
+			paths:     []string{"/oc-if:interfaces/oc-if:interface[name=1/1:1]/oc-ip:ipv4"},
+			wantPaths: []string{"oc-if:interfaces/oc-if:interface[name=1/1:1]/oc-ip:ipv4"},
+		},
+		{
+			name:       "strip",
+			mode:       NamespaceStrip,
+			prefix:     "/oc-if:interfaces",
+			paths:      []string{"/oc-if:interface[name=1/1:1]/oc-ip:ipv4", "/oc-if:interface/state"},
+			wantPrefix: "interfaces",
+			wantPaths:  []string{"interface[name=1/1:1]/ipv4", "interface/state"},
+		},
+		{
+			name:      "first",
+			mode:      NamespaceFirst,
+			paths:     []string{"/oc-if:interfaces/oc-if:interface/oc-ip:ipv4/oc-ip:addresses", "/oc-sys:system/name"},
+			wantPaths: []string{"oc-if:interfaces/interface/oc-ip:ipv4/addresses", "oc-sys:system/name"},
+		},
+		{
+			name:       "first_with_prefix",
+			mode:       NamespaceFirst,
+			prefix:     "/oc-if:interfaces",
+			paths:      []string{"/oc-if:interface/oc-ip:ipv4"},
+			wantPrefix: "oc-if:interfaces",
+			wantPaths:  []string{"interface/oc-ip:ipv4"},
+		},
+		{
+			name:      "origin",
+			mode:      NamespaceOrigin,
+			paths:     []string{"/Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces", "openconfig:/oc-sys:system"},
+			wantPaths: []string{"Cisco-IOS-XR-infra-statsd-oper:/infra-statistics/interfaces", "openconfig:/system"},
+		},
+		{
+			name:       "origin_from_prefix",
+			mode:       NamespaceOrigin,
+			prefix:     "/Cisco-IOS-XR-infra-statsd-oper:infra-statistics",
+			paths:      []string{"/interfaces", "/Cisco-IOS-XR-infra-statsd-oper:summary"},
+			wantPrefix: "Cisco-IOS-XR-infra-statsd-oper:/infra-statistics",
+			wantPaths:  []string{"interfaces", "summary"},
+		},
+		{
+			name:      "prefix",
+			mode:      NamespacePrefix,
+			paths:     []string{"Cisco-IOS-XR-infra-statsd-oper:/infra-statistics/interfaces", "/sys:system"},
+			wantPaths: []string{"Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces", "sys:system"},
+		},
+		{
+			name:      "prefix_from_prefix_origin",
+			mode:      NamespacePrefix,
+			prefix:    "srl_nokia-interfaces:/",
+			paths:     []string{"/interface/statistics", "/srl_nokia-if:interface"},
+			wantPaths: []string{"srl_nokia-interfaces:interface/statistics", "srl_nokia-if:interface"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := mustParsePath(t, tt.prefix)
+			paths := make([]*gnmi.Path, 0, len(tt.paths))
+			for _, p := range tt.paths {
+				paths = append(paths, mustParsePath(t, p))
+			}
+			ApplyNamespace(tt.mode, prefix, paths...)
+			if got := pathString(prefix); got != tt.wantPrefix {
+				t.Errorf("got prefix %q, expected %q", got, tt.wantPrefix)
+			}
+			for i, p := range paths {
+				if got := pathString(p); got != tt.wantPaths[i] {
+					t.Errorf("path %d: got %q, expected %q", i, got, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, mode := range []string{NamespaceAsIs, NamespaceStrip, NamespaceFirst, NamespaceOrigin, NamespacePrefix} {
+		if err := ValidateNamespace(mode); err != nil {
+			t.Errorf("unexpected error for mode %q: %v", mode, err)
+		}
+	}
+	if err := ValidateNamespace("qualify"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func mustParsePath(t *testing.T, s string) *gnmi.Path {
+	t.Helper()
+	if s == "" {
+		return nil
+	}
+	p, err := ParsePath(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func pathString(p *gnmi.Path) string {
+	if p == nil {
+		return ""
+	}
+	s := GnmiPathToXPath(&gnmi.Path{Elem: p.GetElem()}, false)
+	if p.GetOrigin() != "" {
+		return p.GetOrigin() + ":/" + s
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package recorder
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryClientInterceptor records the unary RPCs requests and responses.
+func (r *Recorder) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		id := r.nextRPCID()
+		r.record(r.clientRecord(id, method, cc, DirectionSent), req, nil)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			r.record(r.clientRecord(id, method, cc, DirectionReceived), nil, err)
+			return err
+		}
+		r.record(r.clientRecord(id, method, cc, DirectionReceived), reply, nil)
+		return nil
+	}
+}
+
+// StreamClientInterceptor records the messages sent and received over client streams.
+func (r *Recorder) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		id := r.nextRPCID()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			r.record(r.clientRecord(id, method, cc, DirectionReceived), nil, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, r: r, id: id, method: method, cc: cc}, nil
+	}
+}
+
+func (r *Recorder) clientRecord(id uint64, method string, cc *grpc.ClientConn, dir string) *Record {
+	rec := &Record{
+		Side:      SideClient,
+		Direction: dir,
+		Method:    method,
+		RPCID:     id,
+	}
+	if cc != nil {
+		rec.Peer = cc.Target()
+	}
+	return rec
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	r      *Recorder
+	id     uint64
+	method string
+	cc     *grpc.ClientConn
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	s.r.record(s.r.clientRecord(s.id, s.method, s.cc, DirectionSent), m, err)
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.r.record(s.r.clientRecord(s.id, s.method, s.cc, DirectionReceived), m, nil)
+	case !errors.Is(err, io.EOF):
+		s.r.record(s.r.clientRecord(s.id, s.method, s.cc, DirectionReceived), nil, err)
+	}
+	return err
+}
+
+// UnaryServerInterceptor records the unary RPCs requests and responses.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := r.nextRPCID()
+		p := peerAddr(ctx)
+		r.record(serverRecord(id, info.FullMethod, p, DirectionReceived), req, nil)
+		rsp, err := handler(ctx, req)
+		if err != nil {
+			r.record(serverRecord(id, info.FullMethod, p, DirectionSent), nil, err)
+			return rsp, err
+		}
+		r.record(serverRecord(id, info.FullMethod, p, DirectionSent), rsp, nil)
+		return rsp, nil
+	}
+}
+
+// StreamServerInterceptor records the messages sent and received over server streams.
+func (r *Recorder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		s := &serverStream{
+			ServerStream: ss,
+			r:            r,
+			id:           r.nextRPCID(),
+			method:       info.FullMethod,
+			peer:         peerAddr(ss.Context()),
+		}
+		err := handler(srv, s)
+		if err != nil {
+			r.record(serverRecord(s.id, s.method, s.peer, DirectionSent), nil, err)
+		}
+		return err
+	}
+}
+
+func serverRecord(id uint64, method, peer, dir string) *Record {
+	return &Record{
+		Side:      SideServer,
+		Direction: dir,
+		Method:    method,
+		Peer:      peer,
+		RPCID:     id,
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	r      *Recorder
+	id     uint64
+	method string
+	peer   string
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	s.r.record(serverRecord(s.id, s.method, s.peer, DirectionSent), m, err)
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.r.record(serverRecord(s.id, s.method, s.peer, DirectionReceived), m, nil)
+	}
+	return err
+}
+
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,155 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package recorder records the gNMI messages exchanged over
+// gRPC client connections or by a gRPC server to a file.
+//
+// The records are written as JSON lines, each record holds
+// the binary encoded protobuf message along with metadata such as
+// the time, the RPC method and the message direction.
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	SideClient = "client"
+	SideServer = "server"
+
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// Record is a single recorded message.
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	// client or server
+	Side string `json:"side"`
+	// sent or received
+	Direction string `json:"direction"`
+	// full gRPC method name, e.g: /gnmi.gNMI/Get
+	Method string `json:"method"`
+	// the dialed target on the client side,
+	// the peer address on the server side.
+	Peer string `json:"peer,omitempty"`
+	// identifies the RPC the message belongs to.
+	RPCID uint64 `json:"rpc-id"`
+	// protobuf message full name, e.g: gnmi.GetRequest
+	Type string `json:"type,omitempty"`
+	// binary encoded protobuf message
+	Data []byte `json:"data,omitempty"`
+	// the RPC error, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Message decodes the record data into a protobuf message
+// of the record type.
+func (r *Record) Message() (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(r.Type))
+	if err != nil {
+		return nil, err
+	}
+	msg := mt.New().Interface()
+	err = proto.Unmarshal(r.Data, msg)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Recorder writes records to an io.Writer.
+type Recorder struct {
+	m      *sync.Mutex
+	w      io.Writer
+	enc    *json.Encoder
+	rpcID  *atomic.Uint64
+	closer io.Closer
+}
+
+// New creates a Recorder writing to w.
+func New(w io.Writer) *Recorder {
+	return &Recorder{
+		m:     new(sync.Mutex),
+		w:     w,
+		enc:   json.NewEncoder(w),
+		rpcID: new(atomic.Uint64),
+	}
+}
+
+// NewFile creates a Recorder appending to the file at path.
+func NewFile(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	r := New(f)
+	r.closer = f
+	return r, nil
+}
+
+// Close closes the underlying file, if the Recorder was created with NewFile.
+func (r *Recorder) Close() error {
+	if r == nil || r.closer == nil {
+		return nil
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.closer.Close()
+}
+
+func (r *Recorder) nextRPCID() uint64 {
+	return r.rpcID.Add(1)
+}
+
+func (r *Recorder) record(rec *Record, m any, err error) {
+	rec.Timestamp = time.Now()
+	if msg, ok := m.(proto.Message); ok && msg != nil {
+		rec.Type = string(msg.ProtoReflect().Descriptor().FullName())
+		rec.Data, _ = proto.Marshal(msg)
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.enc.Encode(rec)
+}
+
+// Reader reads records written by a Recorder.
+type Reader struct {
+	dec *json.Decoder
+}
+
+// NewReader creates a Reader reading records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next record, or io.EOF if there are no more records.
+func (r *Reader) Next() (*Record, error) {
+	rec := new(Record)
+	err := r.dec.Decode(rec)
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	return rec, nil
+}
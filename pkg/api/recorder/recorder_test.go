@@ -0,0 +1,147 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testServer struct {
+	gnmi.UnimplementedGNMIServer
+}
+
+func (s *testServer) Capabilities(context.Context, *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	return &gnmi.CapabilityResponse{GNMIVersion: "0.10.0"}, nil
+}
+
+func (s *testServer) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
+	_, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}})
+	if err != nil {
+		return err
+	}
+	return errors.New("done")
+}
+
+func TestRecorder(t *testing.T) {
+	clientBuf := new(bytes.Buffer)
+	serverBuf := new(bytes.Buffer)
+	cr := New(clientBuf)
+	sr := New(serverBuf)
+
+	l := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(sr.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(sr.StreamServerInterceptor()),
+	)
+	gnmi.RegisterGNMIServer(srv, new(testServer))
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(cr.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(cr.StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := gnmi.NewGNMIClient(conn)
+	ctx := context.Background()
+	_, err = client.Capabilities(ctx, &gnmi.CapabilityRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subReq := &gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{Mode: gnmi.SubscriptionList_ONCE},
+		},
+	}
+	err = stream.Send(subReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+
+	type exp struct {
+		dir, typ string
+		err      bool
+	}
+	check := func(name string, buf *bytes.Buffer, side string, want []exp) {
+		r := NewReader(buf)
+		var i int
+		for {
+			rec, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: failed to read record: %v", name, err)
+			}
+			if i >= len(want) {
+				t.Fatalf("%s: unexpected record %+v", name, rec)
+			}
+			if rec.Side != side || rec.Direction != want[i].dir || rec.Type != want[i].typ || (rec.Error != "") != want[i].err {
+				t.Errorf("%s: record %d: got %s/%s/%s/%q, want %+v", name, i, rec.Side, rec.Direction, rec.Type, rec.Error, want[i])
+			}
+			if rec.Type == "gnmi.SubscribeRequest" {
+				msg, err := rec.Message()
+				if err != nil {
+					t.Fatalf("%s: failed to decode message: %v", name, err)
+				}
+				if !proto.Equal(msg, subReq) {
+					t.Errorf("%s: decoded message mismatch: %v", name, msg)
+				}
+			}
+			i++
+		}
+		if i != len(want) {
+			t.Errorf("%s: got %d records, want %d", name, i, len(want))
+		}
+	}
+	check("client", clientBuf, SideClient, []exp{
+		{dir: DirectionSent, typ: "gnmi.CapabilityRequest"},
+		{dir: DirectionReceived, typ: "gnmi.CapabilityResponse"},
+		{dir: DirectionSent, typ: "gnmi.SubscribeRequest"},
+		{dir: DirectionReceived, typ: "gnmi.SubscribeResponse"},
+		{dir: DirectionReceived, err: true},
+	})
+	check("server", serverBuf, SideServer, []exp{
+		{dir: DirectionReceived, typ: "gnmi.CapabilityRequest"},
+		{dir: DirectionSent, typ: "gnmi.CapabilityResponse"},
+		{dir: DirectionReceived, typ: "gnmi.SubscribeRequest"},
+		{dir: DirectionSent, typ: "gnmi.SubscribeResponse"},
+		{dir: DirectionSent, err: true},
+	})
+}
@@ -0,0 +1,114 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestZstdCompressorRoundTrip(t *testing.T) {
+	c := encoding.GetCompressor(types.CompressionZstd)
+	if c == nil {
+		t.Fatal("zstd compressor is not registered")
+	}
+	payload := []byte(strings.Repeat(`{"openconfig-interfaces:state":{"counters":{"in-octets":"42"}}}`, 100))
+	// run twice to exercise the pooled encoders and decoders
+	for i := 0; i < 2; i++ {
+		buf := new(bytes.Buffer)
+		w, err := c.Compress(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(payload); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() >= len(payload) {
+			t.Errorf("compressed size %d is not smaller than %d", buf.Len(), len(payload))
+		}
+		r, err := c.Decompress(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("round %d: decompressed payload does not match", i)
+		}
+	}
+}
+
+func TestCompressionInterceptor(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(compressionUnaryInterceptor(types.CompressionZstd)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	// the client does not compress its requests but advertises
+	// the registered compressors, the server picks zstd for the response.
+	sh := new(inHeaderRecorder)
+	conn, err := grpc.Dial(l.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(sh),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sh.compression != types.CompressionZstd {
+		t.Errorf("unexpected response compression %q", sh.compression)
+	}
+}
+
+// inHeaderRecorder records the compression of the received responses.
+type inHeaderRecorder struct {
+	compression string
+}
+
+func (h *inHeaderRecorder) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *inHeaderRecorder) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if ih, ok := s.(*stats.InHeader); ok {
+		h.compression = ih.Compression
+	}
+}
+
+func (h *inHeaderRecorder) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *inHeaderRecorder) HandleConn(context.Context, stats.ConnStats) {}
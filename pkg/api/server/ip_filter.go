@@ -0,0 +1,54 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"log"
+	"net"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+type ipFilterListener struct {
+	net.Listener
+	filter *types.IPFilter
+	logger *log.Logger
+}
+
+// NewIPFilterListener wraps l, the accepted connections from a client address
+// rejected by the filter are closed before any data is exchanged.
+// Connections without an IP address, e.g unix sockets, are always accepted.
+// If logger is not nil, the rejected connections are logged.
+func NewIPFilterListener(l net.Listener, filter *types.IPFilter, logger *log.Logger) net.Listener {
+	if filter == nil {
+		return l
+	}
+	return &ipFilterListener{
+		Listener: l,
+		filter:   filter,
+		logger:   logger,
+	}
+}
+
+func (l *ipFilterListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := c.RemoteAddr().(*net.TCPAddr)
+		if !ok || l.filter.Allowed(addr.AddrPort().Addr()) {
+			return c, nil
+		}
+		if l.logger != nil {
+			l.logger.Printf("connection from %s rejected by the ip-filter", addr)
+		}
+		c.Close()
+	}
+}
@@ -0,0 +1,123 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestIPFilterAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *types.IPFilter
+		allowed map[string]bool
+	}{
+		{
+			name: "nil",
+			allowed: map[string]bool{
+				"10.0.0.1": true,
+			},
+		},
+		{
+			name:   "allow_only",
+			filter: &types.IPFilter{Allow: []string{"10.0.0.0/8", "2001:db8::/32"}},
+			allowed: map[string]bool{
+				"10.1.2.3":        true,
+				"::ffff:10.1.2.3": true,
+				"192.168.1.1":     false,
+				"2001:db8::1":     true,
+				"2001:db9::1":     false,
+			},
+		},
+		{
+			name:   "deny_only",
+			filter: &types.IPFilter{Deny: []string{"192.168.1.1"}},
+			allowed: map[string]bool{
+				"192.168.1.1": false,
+				"192.168.1.2": true,
+			},
+		},
+		{
+			name: "deny_before_allow",
+			filter: &types.IPFilter{
+				Allow: []string{"10.0.0.0/8"},
+				Deny:  []string{"10.0.0.0/24"},
+			},
+			allowed: map[string]bool{
+				"10.0.0.1": false,
+				"10.0.1.1": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			for addr, want := range tt.allowed {
+				a := netip.MustParseAddr(addr)
+				if got := tt.filter.Allowed(a); got != want {
+					t.Errorf("%s: got allowed=%v, expected %v", addr, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIPFilterValidate(t *testing.T) {
+	for _, f := range []*types.IPFilter{
+		{Allow: []string{"10.0.0.0/33"}},
+		{Deny: []string{"not-an-ip"}},
+	} {
+		if err := f.Validate(); err == nil {
+			t.Errorf("expected an error for %+v", f)
+		}
+	}
+}
+
+func TestIPFilterListener(t *testing.T) {
+	filter := &types.IPFilter{Deny: []string{"127.0.0.0/8"}}
+	if err := filter.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl := NewIPFilterListener(l, filter, nil)
+	defer fl.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := fl.Accept()
+		if err == nil {
+			accepted <- c
+		}
+	}()
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// the rejected connection is closed by the listener
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err = c.Read(make([]byte, 1)); err == nil {
+		t.Error("expected the connection to be closed")
+	}
+	select {
+	case <-accepted:
+		t.Error("expected the connection to be rejected")
+	default:
+	}
+}
@@ -0,0 +1,133 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// first file descriptor passed by systemd socket activation
+	listenFDsStart = 3
+	// name of the socket used by the gNMI server
+	// when systemd passes several ones
+	SocketActivationName = "gnmi-server"
+)
+
+// listen returns the server listener: the socket passed by systemd
+// if socket activation is enabled, a new listener on the configured address otherwise.
+// A stale unix socket file left by a previous run is removed before listening.
+func (s *gNMIServer) listen(ctx context.Context) (net.Listener, error) {
+	if s.config.SocketActivation {
+		return activationListener(os.Getenv, SocketActivationName)
+	}
+	var networkType = "tcp"
+	var addr = s.config.Address
+	if indx := strings.Index(addr, "://"); indx > 0 {
+		networkType = addr[:indx]
+		addr = addr[indx+3:]
+	}
+	if networkType == "unix" {
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, err
+		}
+	}
+	lc := &net.ListenConfig{
+		KeepAlive: s.config.TCPKeepalive,
+		Control:   s.config.SocketOptions.Control,
+	}
+	for {
+		l, err := lc.Listen(ctx, networkType, addr)
+		if err == nil {
+			if networkType != "unix" {
+				return l, nil
+			}
+			if err = s.config.UnixSocket.Apply(addr); err != nil {
+				l.Close()
+				return nil, errors.Wrap(err, "cannot set unix socket permissions")
+			}
+			return l, nil
+		}
+		s.logger.Print(errors.Wrap(err, "cannot listen"))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// removeStaleSocket removes the unix socket file at path
+// if no server accepts connections on it.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %q: file exists and is not a socket", path)
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		// in use, listening fails until it is released.
+		conn.Close()
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// activationListener returns the listener passed by systemd socket activation.
+// If several sockets are passed, the one named name (FileDescriptorName=) is used.
+func activationListener(getenv func(string) string, name string) (net.Listener, error) {
+	fd, err := activationFD(getenv, name)
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), name)
+	defer f.Close()
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: fd %d: %w", fd, err)
+	}
+	return l, nil
+}
+
+// activationFD returns the file descriptor to listen on,
+// from the LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES environment variables.
+func activationFD(getenv func(string) string, name string) (int, error) {
+	pid, err := strconv.Atoi(getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return 0, errors.New("socket activation: no socket passed to this process")
+	}
+	n, err := strconv.Atoi(getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return 0, errors.New("socket activation: no socket passed to this process")
+	}
+	if n == 1 {
+		return listenFDsStart, nil
+	}
+	names := strings.Split(getenv("LISTEN_FDNAMES"), ":")
+	for i, fdName := range names {
+		if fdName == name && i < n {
+			return listenFDsStart + i, nil
+		}
+	}
+	return 0, fmt.Errorf("socket activation: %d sockets passed, none named %q", n, name)
+}
@@ -0,0 +1,117 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket file modes are not supported on windows")
+	}
+	sock := filepath.Join(t.TempDir(), "gnmi.sock")
+	// leave a stale socket file behind
+	stale, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	s, err := New(Config{
+		Address:    "unix://" + sock,
+		UnixSocket: &types.UnixSocket{Mode: "0600"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.listen(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	fi, err := os.Stat(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("unexpected socket mode %v", fi.Mode().Perm())
+	}
+
+	// a regular file is never removed
+	file := filepath.Join(t.TempDir(), "file")
+	if err = os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = removeStaleSocket(file); err == nil {
+		t.Errorf("expected an error for a regular file")
+	}
+}
+
+func TestActivationFD(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "not_activated",
+			env:     map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "other_process",
+			env:     map[string]string{"LISTEN_PID": "1", "LISTEN_FDS": "1"},
+			wantErr: true,
+		},
+		{
+			name: "single_socket",
+			env:  map[string]string{"LISTEN_PID": pid, "LISTEN_FDS": "1", "LISTEN_FDNAMES": "gnmic.socket"},
+			want: 3,
+		},
+		{
+			name: "named_socket",
+			env:  map[string]string{"LISTEN_PID": pid, "LISTEN_FDS": "2", "LISTEN_FDNAMES": "api:gnmi-server"},
+			want: 4,
+		},
+		{
+			name:    "unnamed_sockets",
+			env:     map[string]string{"LISTEN_PID": pid, "LISTEN_FDS": "2"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd, err := activationFD(func(k string) string { return tt.env[k] }, SocketActivationName)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got fd %d", fd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fd != tt.want {
+				t.Errorf("got fd %d, expected %d", fd, tt.want)
+			}
+		})
+	}
+}
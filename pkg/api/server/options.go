@@ -63,6 +63,14 @@ func (s *gNMIServer) interceptorsOpts() []grpc.ServerOption {
 		ui = append(ui, grpc_ratelimit.UnaryServerInterceptor(limiter))
 		si = append(si, grpc_ratelimit.StreamServerInterceptor(limiter))
 	}
+	if s.config.Compression != "" {
+		ui = append(ui, compressionUnaryInterceptor(s.config.Compression))
+		si = append(si, compressionStreamInterceptor(s.config.Compression))
+	}
+	if s.recorder != nil {
+		ui = append(ui, s.recorder.UnaryServerInterceptor())
+		si = append(si, s.recorder.StreamServerInterceptor())
+	}
 	return []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(ui...),
 		grpc.ChainStreamInterceptor(si...),
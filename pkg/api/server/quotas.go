@@ -0,0 +1,153 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// SubscriptionQuotas limits the number of concurrent Subscribe RPCs per client.
+// The client identities are case insensitive.
+type SubscriptionQuotas struct {
+	// default max subscriptions per client.
+	// if negative or unset, there is no limit.
+	defaultMax int64
+	// per client identity max subscriptions,
+	// overrides defaultMax.
+	max map[string]int64
+
+	m        *sync.Mutex
+	active   map[string]int64
+	rejected map[string]uint64
+}
+
+// ClientUsage is the subscriptions usage of a single client.
+type ClientUsage struct {
+	Client           string `json:"client,omitempty"`
+	Subscriptions    int64  `json:"subscriptions"`
+	MaxSubscriptions int64  `json:"max-subscriptions,omitempty"`
+	Rejected         uint64 `json:"rejected,omitempty"`
+}
+
+func NewSubscriptionQuotas(defaultMax int64, max map[string]int64) *SubscriptionQuotas {
+	q := &SubscriptionQuotas{
+		defaultMax: defaultMax,
+		max:        make(map[string]int64, len(max)),
+		m:          new(sync.Mutex),
+		active:     make(map[string]int64),
+		rejected:   make(map[string]uint64),
+	}
+	for c, v := range max {
+		q.max[strings.ToLower(c)] = v
+	}
+	return q
+}
+
+func (q *SubscriptionQuotas) limit(client string) int64 {
+	if v, ok := q.max[client]; ok {
+		return v
+	}
+	return q.defaultMax
+}
+
+func (q *SubscriptionQuotas) acquire(client string) error {
+	client = strings.ToLower(client)
+	q.m.Lock()
+	defer q.m.Unlock()
+	limit := q.limit(client)
+	if limit > 0 && q.active[client] >= limit {
+		q.rejected[client]++
+		return status.Errorf(codes.ResourceExhausted, "client %q reached its max number of subscriptions (%d)", client, limit)
+	}
+	q.active[client]++
+	return nil
+}
+
+func (q *SubscriptionQuotas) release(client string) {
+	client = strings.ToLower(client)
+	q.m.Lock()
+	defer q.m.Unlock()
+	q.active[client]--
+	if q.active[client] <= 0 {
+		delete(q.active, client)
+	}
+}
+
+// Usage returns the subscriptions usage of the clients with active subscriptions,
+// rejected subscriptions or a configured quota, sorted by client identity.
+func (q *SubscriptionQuotas) Usage() []*ClientUsage {
+	q.m.Lock()
+	defer q.m.Unlock()
+	clients := make(map[string]struct{})
+	for c := range q.max {
+		clients[c] = struct{}{}
+	}
+	for c := range q.active {
+		clients[c] = struct{}{}
+	}
+	for c := range q.rejected {
+		clients[c] = struct{}{}
+	}
+	usage := make([]*ClientUsage, 0, len(clients))
+	for c := range clients {
+		cu := &ClientUsage{
+			Client:        c,
+			Subscriptions: q.active[c],
+			Rejected:      q.rejected[c],
+		}
+		if l := q.limit(c); l > 0 {
+			cu.MaxSubscriptions = l
+		}
+		usage = append(usage, cu)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Client < usage[j].Client
+	})
+	return usage
+}
+
+// ClientIdentity returns the identity of the gRPC client:
+// the common name of its verified TLS certificate if any,
+// otherwise the `username` metadata value if any,
+// otherwise its host address.
+func ClientIdentity(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if ok {
+		if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			for _, chain := range tlsInfo.State.VerifiedChains {
+				if len(chain) > 0 && chain[0].Subject.CommonName != "" {
+					return chain[0].Subject.CommonName
+				}
+			}
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if u := md.Get("username"); len(u) > 0 && u[0] != "" {
+			return u[0]
+		}
+	}
+	if pr == nil || pr.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return pr.Addr.String()
+	}
+	return host
+}
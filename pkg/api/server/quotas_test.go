@@ -0,0 +1,80 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestSubscriptionQuotas(t *testing.T) {
+	q := NewSubscriptionQuotas(1, map[string]int64{"Automation": 2, "unlimited": 0})
+
+	// default quota
+	if err := q.acquire("client1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := q.acquire("client1")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected a ResourceExhausted error, got %v", err)
+	}
+	// per client quota, case insensitive
+	for i := 0; i < 2; i++ {
+		if err := q.acquire("automation"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := q.acquire("AUTOMATION"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	// no limit
+	for i := 0; i < 5; i++ {
+		if err := q.acquire("unlimited"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	usage := q.Usage()
+	want := []ClientUsage{
+		{Client: "automation", Subscriptions: 2, MaxSubscriptions: 2, Rejected: 1},
+		{Client: "client1", Subscriptions: 1, MaxSubscriptions: 1, Rejected: 1},
+		{Client: "unlimited", Subscriptions: 5},
+	}
+	if len(usage) != len(want) {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+	for i := range want {
+		if *usage[i] != want[i] {
+			t.Errorf("usage[%d]: got %+v, want %+v", i, *usage[i], want[i])
+		}
+	}
+
+	q.release("client1")
+	if err := q.acquire("client1"); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.1.1.1"), Port: 50000}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	if id := ClientIdentity(ctx); id != "10.1.1.1" {
+		t.Errorf("expected the peer address, got %q", id)
+	}
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("username", "admin"))
+	if id := ClientIdentity(ctx); id != "admin" {
+		t.Errorf("expected the username, got %q", id)
+	}
+}
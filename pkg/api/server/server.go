@@ -13,12 +13,11 @@ import (
 	"crypto/tls"
 	"io"
 	"log"
-	"net"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmic/pkg/api/recorder"
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,6 +33,9 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// GNMIVersion is the gNMI version returned by the default Capabilities handler.
+const GNMIVersion = "0.10.0"
+
 type Config struct {
 	// gRPC server address
 	Address string
@@ -71,6 +73,19 @@ type Config struct {
 	RateLimit int64
 	// TLS config
 	TLS *types.TLSConfig
+	// listener socket options
+	SocketOptions *types.SocketOptions
+	// clients addresses allow/deny lists
+	IPFilter *types.IPFilter
+	// compressor used for responses when the client
+	// advertises support for it, gzip or zstd.
+	Compression string
+	// unix socket file permissions,
+	// applies if Address is a unix socket.
+	UnixSocket *types.UnixSocket
+	// listen on the socket passed by systemd
+	// instead of the Address.
+	SocketActivation bool
 }
 
 type gNMIServer struct {
@@ -79,9 +94,13 @@ type gNMIServer struct {
 	config Config
 	logger *log.Logger
 	reg    *prometheus.Registry
+	// records the exchanged messages if not nil
+	recorder *recorder.Recorder
 	//
 	unarySem  *semaphore.Weighted
 	streamSem *semaphore.Weighted
+	// per client subscriptions quotas, no quotas if nil
+	quotas *SubscriptionQuotas
 	// gnmi handlers
 	capabilitiesHandler CapabilitiesHandler
 	getHandler          GetHandler
@@ -107,18 +126,18 @@ type Option func(*gNMIServer)
 
 func defaultCapabilitiesHandlerFunc(ctx context.Context, req *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
 	return &gnmi.CapabilityResponse{
-		GNMIVersion: "0.10.0",
+		GNMIVersion: GNMIVersion,
 	}, nil
 }
 
 func (c *Config) setDefaults() error {
-	if c.Address == "" {
+	if c.Address == "" && !c.SocketActivation {
 		return errors.New("missing address")
 	}
 	if c.Timeout <= 0 {
 		c.Timeout = 2 * time.Minute
 	}
-	return nil
+	return types.ValidateCompression(c.Compression)
 }
 
 func New(c Config, opts ...Option) (*gNMIServer, error) {
@@ -146,27 +165,11 @@ func New(c Config, opts ...Option) (*gNMIServer, error) {
 }
 
 func (s *gNMIServer) Start(ctx context.Context) error {
-	var networkType = "tcp"
-	var addr = s.config.Address
-	if indx := strings.Index(addr, "://"); indx > 0 {
-		networkType = addr[:indx]
-		addr = addr[indx+3:]
-	}
-	lc := &net.ListenConfig{
-		KeepAlive: s.config.TCPKeepalive,
-	}
-	var l net.Listener
-	var err error
-	for {
-		l, err = lc.Listen(ctx, networkType, addr)
-		if err != nil {
-			err = errors.Wrap(err, "cannot listen")
-			s.logger.Print(err)
-			time.Sleep(time.Second)
-			continue
-		}
-		break
+	l, err := s.listen(ctx)
+	if err != nil {
+		return err
 	}
+	l = NewIPFilterListener(l, s.config.IPFilter, s.logger)
 	opts, err := s.serverOpts()
 	if err != nil {
 		return err
@@ -240,13 +243,22 @@ func (s *gNMIServer) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
 		return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 	}
 	ctx := stream.Context()
+	pr, _ := peer.FromContext(ctx)
+	if s.quotas != nil {
+		client := ClientIdentity(ctx)
+		err := s.quotas.acquire(client)
+		if err != nil {
+			s.logger.Printf("rejected subscribe request from peer %s: %v", pr.Addr, err)
+			return err
+		}
+		defer s.quotas.release(client)
+	}
 	err := s.acquireStreamSem(ctx)
 	if err != nil {
 		return err
 	}
 	defer s.releaseStreamSem()
 	//
-	pr, _ := peer.FromContext(ctx)
 	s.logger.Printf("received subscribe request from peer %s", pr.Addr)
 
 	req, err := stream.Recv()
@@ -335,3 +347,15 @@ func WithSubscribeHandler(h SubscribeHandler) func(*gNMIServer) {
 		s.subscribeHandler = h
 	}
 }
+
+func WithRecorder(r *recorder.Recorder) func(*gNMIServer) {
+	return func(s *gNMIServer) {
+		s.recorder = r
+	}
+}
+
+func WithSubscriptionQuotas(q *SubscriptionQuotas) func(*gNMIServer) {
+	return func(s *gNMIServer) {
+		s.quotas = q
+	}
+}
@@ -0,0 +1,89 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultSetAttemptsExtensionID is the registered extension ID used by default
+// for the Set attempts extension.
+const DefaultSetAttemptsExtensionID = gnmi_ext.ExtensionID_EID_EXPERIMENTAL
+
+// SetAttempts is the content of the extension gnmic attaches to the SetResponses
+// it returns when the Set RPCs sent to the targets are retried.
+// It is carried JSON encoded in a gNMI registered extension.
+type SetAttempts struct {
+	Targets []*TargetSetAttempts `json:"targets,omitempty"`
+}
+
+// TargetSetAttempts describes the Set RPCs sent to a single target.
+type TargetSetAttempts struct {
+	// name of the target
+	Target string `json:"target,omitempty"`
+	// number of Set RPCs sent to the target
+	Attempts int `json:"attempts,omitempty"`
+	// true if one of the failed attempts may have been applied by the target
+	MaybeApplied bool `json:"maybe-applied,omitempty"`
+	// errors returned by the failed attempts
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Extension_SetAttempts creates a GNMIOption that adds a gNMI registered extension
+// with the supplied ID carrying the Set attempts sa.
+func Extension_SetAttempts(id gnmi_ext.ExtensionID, sa *SetAttempts) func(msg proto.Message) error {
+	return func(msg proto.Message) error {
+		if msg == nil {
+			return ErrInvalidMsgType
+		}
+		switch msg := msg.ProtoReflect().Interface().(type) {
+		case *gnmi.SetResponse:
+			b, err := json.Marshal(sa)
+			if err != nil {
+				return err
+			}
+			fn := Extension(
+				&gnmi_ext.Extension{
+					Ext: &gnmi_ext.Extension_RegisteredExt{
+						RegisteredExt: &gnmi_ext.RegisteredExtension{
+							Id:  id,
+							Msg: b,
+						},
+					},
+				},
+			)
+			return fn(msg)
+		default:
+			return fmt.Errorf("option Extension_SetAttempts: %w: %T", ErrInvalidMsgType, msg)
+		}
+	}
+}
+
+// SetAttemptsFromExtensions returns the Set attempts carried by the registered
+// extension with the supplied ID, or nil if none of the extensions exts has that ID.
+func SetAttemptsFromExtensions(id gnmi_ext.ExtensionID, exts []*gnmi_ext.Extension) (*SetAttempts, error) {
+	for _, ext := range exts {
+		rext := ext.GetRegisteredExt()
+		if rext == nil || rext.GetId() != id {
+			continue
+		}
+		sa := new(SetAttempts)
+		err := json.Unmarshal(rext.GetMsg(), sa)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Set attempts extension: %w", err)
+		}
+		return sa, nil
+	}
+	return nil, nil
+}
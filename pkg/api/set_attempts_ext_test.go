@@ -0,0 +1,54 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+func TestSetAttemptsExtension(t *testing.T) {
+	sa := &SetAttempts{
+		Targets: []*TargetSetAttempts{
+			{Target: "router1", Attempts: 1},
+			{
+				Target:       "router2",
+				Attempts:     2,
+				MaybeApplied: true,
+				Errors:       []string{"rpc error: code = DeadlineExceeded desc = context deadline exceeded"},
+			},
+		},
+	}
+	rsp := new(gnmi.SetResponse)
+	err := Extension_SetAttempts(DefaultSetAttemptsExtensionID, sa)(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := SetAttemptsFromExtensions(DefaultSetAttemptsExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, sa) {
+		t.Errorf("got %+v, expected %+v", got, sa)
+	}
+	got, err = SetAttemptsFromExtensions(gnmi_ext.ExtensionID_EID_UNSET, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("expected no Set attempts, got %+v", got)
+	}
+	err = Extension_SetAttempts(DefaultSetAttemptsExtensionID, sa)(new(gnmi.GetResponse))
+	if err == nil {
+		t.Errorf("expected an error for a GetResponse")
+	}
+}
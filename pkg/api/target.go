@@ -199,3 +199,12 @@ func Token(token string) TargetOption {
 		return nil
 	}
 }
+
+// DNS sets the target DNS config, used to resolve
+// the target host names.
+func DNS(cfg *types.DNSConfig) TargetOption {
+	return func(t *target.Target) error {
+		t.Config.DNS = cfg
+		return nil
+	}
+}
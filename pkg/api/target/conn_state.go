@@ -0,0 +1,64 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+)
+
+// HandshakeHook is called after each transport (TLS) handshake of the target connection,
+// with the server authority and the handshake error, nil if it succeeded.
+type HandshakeHook func(authority string, err error)
+
+// SetHandshakeHook sets the function called after each transport handshake,
+// it must be set before CreateGNMIClient.
+func (t *Target) SetHandshakeHook(h HandshakeHook) {
+	t.handshakeHook = h
+}
+
+// hookedCredentials calls hook after each client handshake.
+type hookedCredentials struct {
+	credentials.TransportCredentials
+	hook HandshakeHook
+}
+
+func (c *hookedCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, ai, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	c.hook(authority, err)
+	return conn, ai, err
+}
+
+func (c *hookedCredentials) Clone() credentials.TransportCredentials {
+	return &hookedCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		hook:                 c.hook,
+	}
+}
+
+// WatchConnState calls fn on each state change of the target gRPC connection,
+// until ctx is done or the connection is shut down.
+func (t *Target) WatchConnState(ctx context.Context, fn func(from, to connectivity.State)) {
+	conn := t.conn
+	if conn == nil {
+		return
+	}
+	s := conn.GetState()
+	for conn.WaitForStateChange(ctx, s) {
+		ns := conn.GetState()
+		fn(s, ns)
+		if ns == connectivity.Shutdown {
+			return
+		}
+		s = ns
+	}
+}
@@ -0,0 +1,203 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+)
+
+const defaultDNSPort = "53"
+
+// resolver resolves the target host names using the target DNS config.
+// It keeps track of the connections established to each host name
+// so that they can be closed when the host name resolves to a different
+// set of addresses.
+type resolver struct {
+	cfg *types.DNSConfig
+	r   *net.Resolver
+
+	m     *sync.Mutex
+	addrs map[string][]netip.Addr              // host name to last resolved addresses
+	conns map[string]map[*trackedConn]struct{} // host name to open connections
+}
+
+func newResolver(cfg *types.DNSConfig) *resolver {
+	r := &resolver{
+		cfg:   cfg,
+		r:     net.DefaultResolver,
+		m:     new(sync.Mutex),
+		addrs: make(map[string][]netip.Addr),
+		conns: make(map[string]map[*trackedConn]struct{}),
+	}
+	if len(cfg.Servers) == 0 {
+		return r
+	}
+	servers := make([]string, 0, len(cfg.Servers))
+	for _, s := range cfg.Servers {
+		if pa, err := utils.ParseAddress(s, defaultDNSPort); err == nil {
+			s = pa.String()
+		}
+		servers = append(servers, s)
+	}
+	var next uint32
+	r.r = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			// round robin over the configured servers
+			i := atomic.AddUint32(&next, 1) - 1
+			var d net.Dialer
+			return d.DialContext(ctx, network, servers[int(i)%len(servers)])
+		},
+	}
+	return r
+}
+
+// resolve looks up the host name addresses, sorts them according to
+// the preferred address family and reports if they changed since the last lookup.
+func (r *resolver) resolve(ctx context.Context, host string) ([]netip.Addr, bool, error) {
+	addrs, err := r.r.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range addrs {
+		addrs[i] = addrs[i].Unmap()
+	}
+	sortAddrs(addrs, r.cfg.Prefer)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	prev, ok := r.addrs[host]
+	r.addrs[host] = addrs
+	return addrs, ok && !sameAddrs(prev, addrs), nil
+}
+
+// dial resolves the host name and tries the resulting addresses in order.
+func (r *resolver) dial(ctx context.Context, d *net.Dialer, host, port string) (net.Conn, error) {
+	addrs, _, err := r.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, addr := range addrs {
+		conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addr.String(), port))
+		if err != nil {
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		return r.track(host, conn), nil
+	}
+	if len(errs) == 0 {
+		return nil, &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (r *resolver) track(host string, conn net.Conn) net.Conn {
+	tc := &trackedConn{Conn: conn}
+	tc.onClose = func() {
+		r.m.Lock()
+		defer r.m.Unlock()
+		delete(r.conns[host], tc)
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.conns[host] == nil {
+		r.conns[host] = make(map[*trackedConn]struct{})
+	}
+	r.conns[host][tc] = struct{}{}
+	return tc
+}
+
+// watch re-resolves the host name every TTL and closes the connections
+// established to it when its addresses change.
+// The gRPC client reconnects using the new addresses.
+func (r *resolver) watch(ctx context.Context, host string, timeout time.Duration) {
+	ticker := time.NewTicker(r.cfg.TTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rctx, cancel := context.WithTimeout(ctx, timeout)
+			_, changed, err := r.resolve(rctx, host)
+			cancel()
+			if err != nil || !changed {
+				continue
+			}
+			r.closeConns(host)
+		}
+	}
+}
+
+func (r *resolver) closeConns(host string) {
+	r.m.Lock()
+	conns := make([]*trackedConn, 0, len(r.conns[host]))
+	for c := range r.conns[host] {
+		conns = append(conns, c)
+	}
+	r.m.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.onClose)
+	return c.Conn.Close()
+}
+
+func sortAddrs(addrs []netip.Addr, prefer string) {
+	switch prefer {
+	case types.DNSPreferIPv4:
+		sort.SliceStable(addrs, func(i, j int) bool {
+			return addrs[i].Is4() && !addrs[j].Is4()
+		})
+	case types.DNSPreferIPv6:
+		sort.SliceStable(addrs, func(i, j int) bool {
+			return addrs[i].Is6() && !addrs[j].Is6()
+		})
+	}
+}
+
+// sameAddrs compares two sets of addresses regardless of their order.
+func sameAddrs(a, b []netip.Addr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[netip.Addr]struct{}, len(a))
+	for _, addr := range a {
+		set[addr] = struct{}{}
+	}
+	for _, addr := range b {
+		if _, ok := set[addr]; !ok {
+			return false
+		}
+	}
+	return true
+}
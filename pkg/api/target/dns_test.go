@@ -0,0 +1,79 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestSortAddrs(t *testing.T) {
+	v4 := netip.MustParseAddr("192.0.2.1")
+	v6 := netip.MustParseAddr("2001:db8::1")
+	tests := []struct {
+		name   string
+		prefer string
+		want   []netip.Addr
+	}{
+		{name: "none", prefer: "", want: []netip.Addr{v6, v4}},
+		{name: "ipv4", prefer: types.DNSPreferIPv4, want: []netip.Addr{v4, v6}},
+		{name: "ipv6", prefer: types.DNSPreferIPv6, want: []netip.Addr{v6, v4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs := []netip.Addr{v6, v4}
+			sortAddrs(addrs, tt.prefer)
+			for i := range addrs {
+				if addrs[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", addrs, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestResolverClosesConnsOnChange(t *testing.T) {
+	r := newResolver(&types.DNSConfig{TTL: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, changed, err := r.resolve(ctx, "localhost")
+	if err != nil {
+		t.Skipf("failed to resolve localhost: %v", err)
+	}
+	if changed {
+		t.Fatal("first lookup reported a change")
+	}
+	_, changed, _ = r.resolve(ctx, "localhost")
+	if changed {
+		t.Fatal("unchanged lookup reported a change")
+	}
+
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	r.track("localhost", c1)
+	// simulate a record change
+	r.addrs["localhost"] = []netip.Addr{netip.MustParseAddr("192.0.2.1")}
+	_, changed, _ = r.resolve(ctx, "localhost")
+	if !changed {
+		t.Fatal("expected a change")
+	}
+	r.closeConns("localhost")
+	if len(r.conns["localhost"]) != 0 {
+		t.Errorf("expected no tracked connections, got %d", len(r.conns["localhost"]))
+	}
+	if _, err := c1.Write([]byte("x")); err == nil {
+		t.Error("expected the tracked connection to be closed")
+	}
+}
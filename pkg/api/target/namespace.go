@@ -0,0 +1,59 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+// namespaceGetRequest returns a copy of req with its paths rewritten according
+// to the target path-namespace mode, or req itself if the mode is not set.
+// The request is copied since it may be shared by several targets.
+func (t *Target) namespaceGetRequest(req *gnmi.GetRequest) *gnmi.GetRequest {
+	if t.Config.PathNamespace == path.NamespaceAsIs {
+		return req
+	}
+	req = proto.Clone(req).(*gnmi.GetRequest)
+	path.ApplyNamespace(t.Config.PathNamespace, req.GetPrefix(), req.GetPath()...)
+	return req
+}
+
+// namespaceSetRequest is the SetRequest version of namespaceGetRequest.
+func (t *Target) namespaceSetRequest(req *gnmi.SetRequest) *gnmi.SetRequest {
+	if t.Config.PathNamespace == path.NamespaceAsIs {
+		return req
+	}
+	req = proto.Clone(req).(*gnmi.SetRequest)
+	paths := append([]*gnmi.Path{}, req.GetDelete()...)
+	for _, upds := range [][]*gnmi.Update{req.GetReplace(), req.GetUpdate(), req.GetUnionReplace()} {
+		for _, upd := range upds {
+			paths = append(paths, upd.GetPath())
+		}
+	}
+	path.ApplyNamespace(t.Config.PathNamespace, req.GetPrefix(), paths...)
+	return req
+}
+
+// namespaceSubscribeRequest is the SubscribeRequest version of namespaceGetRequest.
+func (t *Target) namespaceSubscribeRequest(req *gnmi.SubscribeRequest) *gnmi.SubscribeRequest {
+	if t.Config.PathNamespace == path.NamespaceAsIs || req.GetSubscribe() == nil {
+		return req
+	}
+	req = proto.Clone(req).(*gnmi.SubscribeRequest)
+	subs := req.GetSubscribe().GetSubscription()
+	paths := make([]*gnmi.Path, 0, len(subs))
+	for _, sub := range subs {
+		paths = append(paths, sub.GetPath())
+	}
+	path.ApplyNamespace(t.Config.PathNamespace, req.GetSubscribe().GetPrefix(), paths...)
+	return req
+}
@@ -65,7 +65,7 @@ SUBSC_NODELAY:
 	subConfig := t.Subscriptions[subscriptionName]
 	t.m.Unlock()
 
-	err = subscribeClient.Send(req)
+	err = subscribeClient.Send(t.namespaceSubscribeRequest(req))
 	if err != nil {
 		t.errors <- &TargetError{
 			SubscriptionName: subscriptionName,
@@ -79,6 +79,9 @@ SUBSC_NODELAY:
 	case gnmi.SubscriptionList_STREAM:
 		err = t.handleStreamSubscriptionRcv(nctx, subscribeClient, subscriptionName, subConfig)
 		if err != nil {
+			if t.replaced(subscriptionName, subscribeClient) {
+				return
+			}
 			t.errors <- &TargetError{
 				SubscriptionName: subscriptionName,
 				Err:              err,
@@ -171,7 +174,7 @@ func (t *Target) SubscribeStreamChan(ctx context.Context, req *gnmi.SubscribeReq
 		t.subscribeCancelFn[subscriptionName] = cancel
 		t.m.Unlock()
 
-		err = subscribeClient.Send(req)
+		err = subscribeClient.Send(t.namespaceSubscribeRequest(req))
 		if err != nil {
 			errCh <- fmt.Errorf("target '%s' send error, retry in %d. err=%v", t.Config.Name, t.Config.RetryTimer, err)
 			cancel()
@@ -209,7 +212,7 @@ func (t *Target) SubscribeOnceChan(ctx context.Context, req *gnmi.SubscribeReque
 			errCh <- err
 			return
 		}
-		err = subscribeClient.Send(req)
+		err = subscribeClient.Send(t.namespaceSubscribeRequest(req))
 		if err != nil {
 			errCh <- err
 			return
@@ -284,20 +287,30 @@ func (t *Target) DecodeProtoBytes(resp *gnmi.SubscribeResponse) error {
 	}
 	switch resp := resp.Response.(type) {
 	case *gnmi.SubscribeResponse_Update:
-		for _, update := range resp.Update.Update {
-			switch update.Val.Value.(type) {
-			case *gnmi.TypedValue_ProtoBytes:
-				m := dynamic.NewMessage(t.RootDesc.GetFile().FindMessage("Nokia.SROS.root"))
-				err := m.Unmarshal(update.Val.GetProtoBytes())
-				if err != nil {
-					return err
-				}
-				jsondata, err := m.MarshalJSON()
-				if err != nil {
-					return err
-				}
-				update.Val.Value = &gnmi.TypedValue_JsonVal{JsonVal: jsondata}
+		return t.DecodeNotificationProtoBytes(resp.Update)
+	}
+	return nil
+}
+
+// DecodeNotificationProtoBytes replaces the ProtoBytes values of the notification
+// updates with their JSON representation, using the target RootDesc.
+func (t *Target) DecodeNotificationProtoBytes(n *gnmi.Notification) error {
+	if t.RootDesc == nil {
+		return nil
+	}
+	for _, update := range n.GetUpdate() {
+		switch update.Val.Value.(type) {
+		case *gnmi.TypedValue_ProtoBytes:
+			m := dynamic.NewMessage(t.RootDesc.GetFile().FindMessage("Nokia.SROS.root"))
+			err := m.Unmarshal(update.Val.GetProtoBytes())
+			if err != nil {
+				return err
+			}
+			jsondata, err := m.MarshalJSON()
+			if err != nil {
+				return err
 			}
+			update.Val.Value = &gnmi.TypedValue_JsonVal{JsonVal: jsondata}
 		}
 	}
 	return nil
@@ -306,7 +319,9 @@ func (t *Target) DecodeProtoBytes(resp *gnmi.SubscribeResponse) error {
 func (t *Target) DeleteSubscription(name string) {
 	t.m.Lock()
 	defer t.m.Unlock()
-	t.subscribeCancelFn[name]()
+	if cfn, ok := t.subscribeCancelFn[name]; ok {
+		cfn()
+	}
 	delete(t.subscribeCancelFn, name)
 	delete(t.SubscribeClients, name)
 	delete(t.Subscriptions, name)
@@ -323,6 +338,34 @@ func (t *Target) StopSubscription(name string) {
 	delete(t.SubscribeClients, name)
 }
 
+// SetSubscription sets the configuration of subscription name,
+// it is used the next time the subscription is established.
+func (t *Target) SetSubscription(name string, sc *types.SubscriptionConfig) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.Subscriptions[name] = sc
+}
+
+// SubscriptionConfigs returns a copy of the target subscriptions configurations, by name.
+func (t *Target) SubscriptionConfigs() map[string]*types.SubscriptionConfig {
+	t.m.Lock()
+	defer t.m.Unlock()
+	scs := make(map[string]*types.SubscriptionConfig, len(t.Subscriptions))
+	for name, sc := range t.Subscriptions {
+		scs[name] = sc
+	}
+	return scs
+}
+
+// replaced reports whether subscription name was re-established
+// using a subscribe client other than c.
+func (t *Target) replaced(name string, c gnmi.GNMI_SubscribeClient) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	sc, ok := t.SubscribeClients[name]
+	return ok && sc != c
+}
+
 func (t *Target) listenPolls(ctx context.Context) {
 	for {
 		select {
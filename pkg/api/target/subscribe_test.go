@@ -0,0 +1,45 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestSubscriptionConfigs(t *testing.T) {
+	tg := NewTarget(&types.TargetConfig{Name: "router1"})
+	tg.SetSubscription("sub1", &types.SubscriptionConfig{Name: "sub1"})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			name := fmt.Sprintf("sub-%d", i)
+			tg.SetSubscription(name, &types.SubscriptionConfig{Name: name})
+			tg.DeleteSubscription(name)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		scs := tg.SubscriptionConfigs()
+		if scs["sub1"] == nil {
+			t.Fatalf("missing subscription sub1")
+		}
+		// the returned map is a copy
+		scs["other"] = nil
+	}
+	wg.Wait()
+	if _, ok := tg.SubscriptionConfigs()["other"]; ok {
+		t.Errorf("the target subscriptions were modified through the returned map")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 	"sync"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/gnmi/proto/gnmi_ext"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
 	"golang.org/x/net/proxy"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
@@ -56,6 +58,11 @@ type Target struct {
 	StopChan           chan struct{}      `json:"-"`
 	Cfn                context.CancelFunc `json:"-"`
 	RootDesc           desc.Descriptor    `json:"-"`
+
+	dns       *resolver
+	dnsCancel context.CancelFunc
+
+	handshakeHook HandshakeHook
 }
 
 // NewTarget //
@@ -81,7 +88,20 @@ func (t *Target) CreateGNMIClient(ctx context.Context, opts ...grpc.DialOption)
 		return err
 	}
 	opts = append(opts, tOpts...)
+	if t.handshakeHook != nil {
+		creds, err := t.Config.TransportCredentials()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(&hookedCredentials{
+			TransportCredentials: creds,
+			hook:                 t.handshakeHook,
+		}))
+	}
 	opts = append(opts, grpc.WithBlock())
+	if t.Config.DNS != nil && t.Config.TunnelTargetType == "" && t.dns == nil {
+		t.dns = newResolver(t.Config.DNS)
+	}
 	// create a gRPC connection
 	addrs := strings.Split(t.Config.Address, ",")
 	numAddrs := len(addrs)
@@ -99,7 +119,7 @@ func (t *Target) CreateGNMIClient(ctx context.Context, opts ...grpc.DialOption)
 			if t.Config.TunnelTargetType == "" {
 				opts = append(opts, grpc.WithContextDialer(t.createDialer(addr)))
 			}
-			conn, err := grpc.DialContext(timeoutCtx, addr, opts...)
+			conn, err := grpc.DialContext(timeoutCtx, utils.GRPCTarget(addr), opts...)
 			if err != nil {
 				errC <- fmt.Errorf("%s: %v", addr, err)
 				return
@@ -120,6 +140,7 @@ func (t *Target) CreateGNMIClient(ctx context.Context, opts ...grpc.DialOption)
 			close(done)
 			t.conn = conn
 			t.Client = gnmi.NewGNMIClient(conn)
+			t.watchDNS(addrs)
 			return nil
 		case err := <-errC:
 			errs = append(errs, err.Error())
@@ -151,6 +172,8 @@ func (t *Target) createProxyDialer(addr string) func(context.Context, string) (n
 			&net.Dialer{
 				Timeout:   t.Config.Timeout,
 				KeepAlive: t.Config.TCPKeepalive,
+				LocalAddr: t.Config.SocketOptions.LocalAddr(),
+				Control:   t.Config.SocketOptions.Control,
 			},
 		)
 		if err != nil {
@@ -162,9 +185,13 @@ func (t *Target) createProxyDialer(addr string) func(context.Context, string) (n
 
 func (t *Target) createCustomDialer(addr string) func(context.Context, string) (net.Conn, error) {
 	return func(ctx context.Context, _ string) (net.Conn, error) {
+		// when the address host name resolves to both IPv4 and IPv6 addresses,
+		// the dialer races the two families (RFC 6555 Happy Eyeballs).
 		dialer := net.Dialer{
 			Timeout:   t.Config.Timeout,
 			KeepAlive: t.Config.TCPKeepalive,
+			LocalAddr: t.Config.SocketOptions.LocalAddr(),
+			Control:   t.Config.SocketOptions.Control,
 		}
 		ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
 		defer cancel()
@@ -176,10 +203,43 @@ func (t *Target) createCustomDialer(addr string) func(context.Context, string) (
 				addr = addr[indx+3:]
 			}
 		}
+		if t.dns != nil && networkType == "tcp" {
+			if host, port, ok := hostName(addr); ok {
+				return t.dns.dial(ctx, &dialer, host, port)
+			}
+		}
 		return dialer.DialContext(ctx, networkType, addr)
 	}
 }
 
+// watchDNS starts a DNS watcher for each host name in addrs,
+// if the target DNS config has a TTL.
+func (t *Target) watchDNS(addrs []string) {
+	if t.dns == nil || t.Config.DNS.TTL <= 0 || t.dnsCancel != nil {
+		return
+	}
+	var ctx context.Context
+	ctx, t.dnsCancel = context.WithCancel(context.Background())
+	for _, addr := range addrs {
+		if host, _, ok := hostName(addr); ok {
+			go t.dns.watch(ctx, host, t.Config.Timeout)
+		}
+	}
+}
+
+// hostName splits addr into host and port,
+// it returns false if the host is not a DNS name.
+func hostName(addr string) (string, string, bool) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", false
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return "", "", false
+	}
+	return host, port, true
+}
+
 func (t *Target) callOpts() []grpc.CallOption {
 	if t.Config.AuthScheme == "" {
 		return nil
@@ -245,12 +305,12 @@ func (t *Target) Capabilities(ctx context.Context, ext ...*gnmi_ext.Extension) (
 
 // Get sends a gnmi.GetRequest to the target *t and returns a gnmi.GetResponse and an error
 func (t *Target) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
-	return t.Client.Get(t.appendRequestMetadata(ctx), req, t.callOpts()...)
+	return t.Client.Get(t.appendRequestMetadata(ctx), t.namespaceGetRequest(req), t.callOpts()...)
 }
 
 // Set sends a gnmi.SetRequest to the target *t and returns a gnmi.SetResponse and an error
 func (t *Target) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
-	return t.Client.Set(t.appendRequestMetadata(ctx), req, t.callOpts()...)
+	return t.Client.Set(t.appendRequestMetadata(ctx), t.namespaceSetRequest(req), t.callOpts()...)
 }
 
 func (t *Target) StopSubscriptions() {
@@ -270,6 +330,9 @@ func (t *Target) StopSubscriptions() {
 
 func (t *Target) Close() error {
 	t.StopSubscriptions()
+	if t.dnsCancel != nil {
+		t.dnsCancel()
+	}
 	if t.conn != nil {
 		return t.conn.Close()
 	}
@@ -282,3 +345,21 @@ func (t *Target) ConnState() string {
 	}
 	return t.conn.GetState().String()
 }
+
+// Conn returns the target gRPC client connection,
+// it is nil until CreateGNMIClient succeeds.
+// It allows calling non gNMI services (e.g gNOI) on the same connection.
+func (t *Target) Conn() *grpc.ClientConn {
+	return t.conn
+}
+
+// RequestContext returns a copy of ctx carrying the target credentials
+// and metadata, as sent with the target gNMI RPCs.
+func (t *Target) RequestContext(ctx context.Context) context.Context {
+	return t.appendRequestMetadata(ctx)
+}
+
+// CallOptions returns the per RPC call options derived from the target config.
+func (t *Target) CallOptions() []grpc.CallOption {
+	return t.callOpts()
+}
@@ -9,9 +9,14 @@
 package api
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
 
 	"github.com/openconfig/gnmic/pkg/api/types"
 )
@@ -153,3 +158,57 @@ func TestNewTarget(t *testing.T) {
 		})
 	}
 }
+
+type capabilitiesServer struct {
+	gnmi.UnimplementedGNMIServer
+}
+
+func (s *capabilitiesServer) Capabilities(context.Context, *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	return &gnmi.CapabilityResponse{GNMIVersion: DefaultGNMIVersion}, nil
+}
+
+func TestTargetIPv6ZoneAddress(t *testing.T) {
+	l, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	var zone string
+	ifaces, _ := net.Interfaces()
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			zone = iface.Name
+			break
+		}
+	}
+	if zone == "" {
+		t.Skip("loopback interface not found")
+	}
+	srv := grpc.NewServer()
+	gnmi.RegisterGNMIServer(srv, new(capabilitiesServer))
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	tg, err := NewTarget(
+		Address(net.JoinHostPort("::1%"+zone, port)),
+		Insecure(true),
+		Timeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = tg.CreateGNMIClient(ctx)
+	if err != nil {
+		t.Fatalf("failed to create gNMI client: %v", err)
+	}
+	defer tg.Close()
+	rsp, err := tg.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("capabilities failed: %v", err)
+	}
+	if rsp.GetGNMIVersion() != DefaultGNMIVersion {
+		t.Errorf("unexpected capabilities response: %v", rsp)
+	}
+}
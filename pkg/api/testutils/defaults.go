@@ -0,0 +1,123 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package testutils
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// DefaultTagsMismatches compares the scalar fields of the config struct cfg,
+// once its defaults are set, to their `default` tag.
+// A field without a `default` tag is expected to be left unset.
+// The fields are identified by their `mapstructure` name, the ones listed in ignore are skipped.
+// It returns a description of each mismatch.
+func DefaultTagsMismatches(cfg any, ignore ...string) []string {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return []string{fmt.Sprintf("%T is not a struct", cfg)}
+	}
+	skip := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		skip[name] = true
+	}
+	return structMismatches(v, "", skip)
+}
+
+func structMismatches(v reflect.Value, prefix string, skip map[string]bool) []string {
+	var mismatches []string
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("mapstructure")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if strings.Contains(flags, "squash") {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				mismatches = append(mismatches, structMismatches(fv, prefix, skip)...)
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = prefix + name
+		if skip[name] {
+			continue
+		}
+		for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			mismatches = append(mismatches, structMismatches(fv, name+"/", skip)...)
+			continue
+		}
+		got, ok := scalarString(fv)
+		if !ok {
+			continue
+		}
+		def := f.Tag.Get("default")
+		want := def
+		if def != "" && fv.Type() == durationType {
+			d, err := time.ParseDuration(def)
+			if err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s: invalid default tag %q: %v", name, def, err))
+				continue
+			}
+			want = d.String()
+		}
+		if def == "" {
+			want, _ = scalarString(reflect.Zero(fv.Type()))
+		}
+		if got != want {
+			mismatches = append(mismatches, fmt.Sprintf("%s: default value %q, default tag %q", name, got, def))
+		}
+	}
+	return mismatches
+}
+
+// scalarString formats v if it is a string, bool, number or duration.
+func scalarString(v reflect.Value) (string, bool) {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	BudgetActionLog           = "log"
+	BudgetActionTag           = "tag"
+	BudgetActionDrop          = "drop"
+	BudgetActionRaiseInterval = "raise-interval"
+
+	defaultBudgetIntervalFactor = 2
+)
+
+// BudgetConfig caps the rate of subscribe responses received from a target
+// and sets the action taken when the caps are exceeded.
+type BudgetConfig struct {
+	// maximum number of subscribe responses per second, 0 means no limit.
+	MessagesPerSecond float64 `mapstructure:"messages-per-second,omitempty" yaml:"messages-per-second,omitempty" json:"messages-per-second,omitempty"`
+	// maximum number of subscribe responses bytes per second, 0 means no limit.
+	BytesPerSecond float64 `mapstructure:"bytes-per-second,omitempty" yaml:"bytes-per-second,omitempty" json:"bytes-per-second,omitempty"`
+	// one of log, tag, drop or raise-interval, defaults to log.
+	Action string `mapstructure:"action,omitempty" yaml:"action,omitempty" json:"action,omitempty"`
+	// factor the sample intervals are multiplied by when the action is raise-interval.
+	IntervalFactor float64 `mapstructure:"interval-factor,omitempty" yaml:"interval-factor,omitempty" json:"interval-factor,omitempty"`
+	// upper bound of the raised sample intervals, 0 means no bound.
+	MaxSampleInterval time.Duration `mapstructure:"max-sample-interval,omitempty" yaml:"max-sample-interval,omitempty" json:"max-sample-interval,omitempty"`
+}
+
+// Validate checks the budget values and sets the defaults.
+func (bc *BudgetConfig) Validate() error {
+	if bc == nil {
+		return nil
+	}
+	if bc.MessagesPerSecond < 0 || bc.BytesPerSecond < 0 {
+		return fmt.Errorf("messages-per-second and bytes-per-second cannot be negative")
+	}
+	if bc.MessagesPerSecond == 0 && bc.BytesPerSecond == 0 {
+		return fmt.Errorf("one of messages-per-second or bytes-per-second must be set")
+	}
+	switch bc.Action {
+	case "":
+		bc.Action = BudgetActionLog
+	case BudgetActionLog, BudgetActionTag, BudgetActionDrop, BudgetActionRaiseInterval:
+	default:
+		return fmt.Errorf("unknown action %q", bc.Action)
+	}
+	if bc.IntervalFactor == 0 {
+		bc.IntervalFactor = defaultBudgetIntervalFactor
+	}
+	if bc.IntervalFactor <= 1 {
+		return fmt.Errorf("interval-factor must be greater than 1")
+	}
+	if bc.MaxSampleInterval < 0 {
+		return fmt.Errorf("max-sample-interval cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// CollectorExtensionConfig enables the collector extension on the
+// SubscribeResponses sent by gnmic's gNMI servers.
+type CollectorExtensionConfig struct {
+	// registered extension ID, defaults to 999 (EID_EXPERIMENTAL)
+	ID int32 `mapstructure:"id,omitempty" json:"id,omitempty"`
+	// the collector ID, defaults to the instance name or the hostname
+	CollectorID string `mapstructure:"collector-id,omitempty" json:"collector-id,omitempty"`
+}
@@ -0,0 +1,30 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "time"
+
+const (
+	DNSPreferIPv4 = "ipv4"
+	DNSPreferIPv6 = "ipv6"
+)
+
+// DNSConfig controls how a target's host name is resolved.
+type DNSConfig struct {
+	// preferred address family, ipv4 or ipv6.
+	// addresses of the other family are tried next.
+	// if empty, the addresses are tried in the order returned by the resolver.
+	Prefer string `mapstructure:"prefer,omitempty" yaml:"prefer,omitempty" json:"prefer,omitempty"`
+	// DNS servers addresses to use instead of the system resolver.
+	// the default port 53 is added if missing.
+	Servers []string `mapstructure:"servers,omitempty" yaml:"servers,omitempty" json:"servers,omitempty"`
+	// if set, the target host name is re-resolved every ttl,
+	// the gRPC connection is re-established if the resolved addresses changed.
+	TTL time.Duration `mapstructure:"ttl,omitempty" yaml:"ttl,omitempty" json:"ttl,omitempty"`
+}
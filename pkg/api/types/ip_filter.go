@@ -0,0 +1,89 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPFilter is a CIDR based allow/deny list of client addresses.
+// The deny list is evaluated first, an address matching it is rejected.
+// If the allow list is not empty, only the addresses matching it are accepted.
+type IPFilter struct {
+	Allow []string `mapstructure:"allow,omitempty" yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `mapstructure:"deny,omitempty" yaml:"deny,omitempty" json:"deny,omitempty"`
+
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// Validate parses the allow and deny lists,
+// an entry is either a prefix in CIDR notation or a single IP address.
+func (f *IPFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	var err error
+	f.allow, err = parsePrefixes(f.Allow)
+	if err != nil {
+		return fmt.Errorf("allow: %w", err)
+	}
+	f.deny, err = parsePrefixes(f.Deny)
+	if err != nil {
+		return fmt.Errorf("deny: %w", err)
+	}
+	return nil
+}
+
+// Allowed returns true if the address is accepted by the filter.
+// Validate must be called before Allowed.
+func (f *IPFilter) Allowed(addr netip.Addr) bool {
+	if f == nil {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, p := range f.deny {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, p := range f.allow {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if !strings.Contains(e, "/") {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", e, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix %q: %w", e, err)
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,43 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const defaultResponseValidationMaxClockSkew = 5 * time.Minute
+
+// ResponseValidationConfig enables the validation of the subscribe responses
+// received from a target against the gNMI specification.
+type ResponseValidationConfig struct {
+	// maximum difference between a notification timestamp
+	// and the local clock, defaults to 5m.
+	MaxClockSkew time.Duration `mapstructure:"max-clock-skew,omitempty" yaml:"max-clock-skew,omitempty" json:"max-clock-skew,omitempty"`
+	// name of the events reporting the violations,
+	// no events are exported if empty.
+	EventName string `mapstructure:"event-name,omitempty" yaml:"event-name,omitempty" json:"event-name,omitempty"`
+	// if true, the responses with violations are dropped.
+	Drop bool `mapstructure:"drop,omitempty" yaml:"drop,omitempty" json:"drop,omitempty"`
+}
+
+// Validate checks the response validation values and sets the defaults.
+func (vc *ResponseValidationConfig) Validate() error {
+	if vc == nil {
+		return nil
+	}
+	if vc.MaxClockSkew < 0 {
+		return fmt.Errorf("max-clock-skew cannot be negative")
+	}
+	if vc.MaxClockSkew == 0 {
+		vc.MaxClockSkew = defaultResponseValidationMaxClockSkew
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleProfileFull is the name of the built-in profile
+// using the subscriptions as they are configured.
+const ScheduleProfileFull = "full"
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ScheduleConfig switches the subscriptions of a target
+// between profiles depending on the time of day.
+type ScheduleConfig struct {
+	// time zone the windows are evaluated in, defaults to the local time zone.
+	Timezone string `mapstructure:"timezone,omitempty" yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	// profile used outside of the windows, defaults to full.
+	DefaultProfile string `mapstructure:"default-profile,omitempty" yaml:"default-profile,omitempty" json:"default-profile,omitempty"`
+	// time windows, the first one matching the current time sets the profile.
+	Windows []*ScheduleWindow `mapstructure:"windows,omitempty" yaml:"windows,omitempty" json:"windows,omitempty"`
+	// profiles, by name.
+	Profiles map[string]*SubscriptionProfile `mapstructure:"profiles,omitempty" yaml:"profiles,omitempty" json:"profiles,omitempty"`
+
+	location *time.Location
+}
+
+// ScheduleWindow is a daily time window during which a profile is used.
+type ScheduleWindow struct {
+	Profile string `mapstructure:"profile,omitempty" yaml:"profile,omitempty" json:"profile,omitempty"`
+	// week days the window applies to: mon, tue, wed, thu, fri, sat or sun.
+	// An empty list means every day.
+	Days []string `mapstructure:"days,omitempty" yaml:"days,omitempty" json:"days,omitempty"`
+	// window start and end, formatted as HH:MM.
+	// If end is not after start, the window spans midnight.
+	Start string `mapstructure:"start,omitempty" yaml:"start,omitempty" json:"start,omitempty"`
+	End   string `mapstructure:"end,omitempty" yaml:"end,omitempty" json:"end,omitempty"`
+
+	days  map[time.Weekday]struct{}
+	start time.Duration
+	end   time.Duration
+}
+
+// SubscriptionProfile overrides the subscriptions sample intervals and paths.
+type SubscriptionProfile struct {
+	// sample interval of all the sampled STREAM subscriptions.
+	SampleInterval *time.Duration `mapstructure:"sample-interval,omitempty" yaml:"sample-interval,omitempty" json:"sample-interval,omitempty"`
+	// per subscription overrides, they take precedence over the profile sample interval.
+	Subscriptions map[string]*SubscriptionOverride `mapstructure:"subscriptions,omitempty" yaml:"subscriptions,omitempty" json:"subscriptions,omitempty"`
+}
+
+// SubscriptionOverride replaces the sample interval and/or the paths of a subscription.
+type SubscriptionOverride struct {
+	SampleInterval *time.Duration `mapstructure:"sample-interval,omitempty" yaml:"sample-interval,omitempty" json:"sample-interval,omitempty"`
+	Paths          []string       `mapstructure:"paths,omitempty" yaml:"paths,omitempty" json:"paths,omitempty"`
+}
+
+// Validate checks the schedule values and sets the defaults.
+func (sc *ScheduleConfig) Validate() error {
+	if sc == nil {
+		return nil
+	}
+	var err error
+	sc.location = time.Local
+	if sc.Timezone != "" {
+		sc.location, err = time.LoadLocation(sc.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %v", sc.Timezone, err)
+		}
+	}
+	if sc.DefaultProfile == "" {
+		sc.DefaultProfile = ScheduleProfileFull
+	}
+	if !sc.hasProfile(sc.DefaultProfile) {
+		return fmt.Errorf("unknown default-profile %q", sc.DefaultProfile)
+	}
+	if len(sc.Windows) == 0 {
+		return fmt.Errorf("no windows defined")
+	}
+	for i, w := range sc.Windows {
+		if w == nil {
+			return fmt.Errorf("window %d: empty window", i)
+		}
+		if !sc.hasProfile(w.Profile) {
+			return fmt.Errorf("window %d: unknown profile %q", i, w.Profile)
+		}
+		err = w.validate()
+		if err != nil {
+			return fmt.Errorf("window %d: %v", i, err)
+		}
+	}
+	for name, p := range sc.Profiles {
+		if name == ScheduleProfileFull {
+			return fmt.Errorf("profile name %q is reserved", ScheduleProfileFull)
+		}
+		if p == nil {
+			continue
+		}
+		if p.SampleInterval != nil && *p.SampleInterval < 0 {
+			return fmt.Errorf("profile %s: sample-interval cannot be negative", name)
+		}
+		for subName, o := range p.Subscriptions {
+			if o != nil && o.SampleInterval != nil && *o.SampleInterval < 0 {
+				return fmt.Errorf("profile %s: subscription %s: sample-interval cannot be negative", name, subName)
+			}
+		}
+	}
+	return nil
+}
+
+func (sc *ScheduleConfig) hasProfile(name string) bool {
+	if name == ScheduleProfileFull {
+		return true
+	}
+	_, ok := sc.Profiles[name]
+	return ok
+}
+
+func (w *ScheduleWindow) validate() error {
+	var err error
+	w.start, err = parseTimeOfDay(w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %v", err)
+	}
+	w.end, err = parseTimeOfDay(w.End)
+	if err != nil {
+		return fmt.Errorf("invalid end: %v", err)
+	}
+	if w.start == w.end {
+		return fmt.Errorf("start and end cannot be equal")
+	}
+	w.days = make(map[time.Weekday]struct{}, len(w.Days))
+	for _, d := range w.Days {
+		wd, ok := scheduleDays[strings.ToLower(d)]
+		if !ok {
+			return fmt.Errorf("unknown day %q", d)
+		}
+		w.days[wd] = struct{}{}
+	}
+	return nil
+}
+
+// parseTimeOfDay parses s formatted as HH:MM
+// and returns the duration since midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (w *ScheduleWindow) hasDay(d time.Weekday) bool {
+	if len(w.days) == 0 {
+		return true
+	}
+	_, ok := w.days[d]
+	return ok
+}
+
+func (w *ScheduleWindow) contains(t time.Time) bool {
+	tod := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.start < w.end {
+		return tod >= w.start && tod < w.end && w.hasDay(t.Weekday())
+	}
+	// the window spans midnight, its days are the days it starts on.
+	if tod >= w.start {
+		return w.hasDay(t.Weekday())
+	}
+	return tod < w.end && w.hasDay((t.Weekday()+6)%7)
+}
+
+// Profile returns the name of the profile to use at t.
+// The schedule must have been validated.
+func (sc *ScheduleConfig) Profile(t time.Time) string {
+	if sc.location != nil {
+		t = t.In(sc.location)
+	}
+	for _, w := range sc.Windows {
+		if w.contains(t) {
+			return w.Profile
+		}
+	}
+	return sc.DefaultProfile
+}
+
+// Apply returns a copy of the subscription name with its sample intervals
+// and paths set as per the profile.
+// It returns sub if the profile does not change it.
+func (p *SubscriptionProfile) Apply(name string, sub *SubscriptionConfig) *SubscriptionConfig {
+	if p == nil {
+		return sub
+	}
+	interval := p.SampleInterval
+	var paths []string
+	if o, ok := p.Subscriptions[name]; ok && o != nil {
+		if o.SampleInterval != nil {
+			interval = o.SampleInterval
+		}
+		paths = o.Paths
+	}
+	nsub := *sub
+	changed := len(paths) > 0
+	if changed {
+		nsub.Paths = paths
+	}
+	if interval != nil && strings.ToUpper(sub.Mode) == "STREAM" {
+		if isSampled(sub) {
+			nsub.SampleInterval = interval
+			changed = true
+		}
+		if len(sub.StreamSubscriptions) > 0 {
+			nsub.StreamSubscriptions = make([]*SubscriptionConfig, 0, len(sub.StreamSubscriptions))
+			for _, ssub := range sub.StreamSubscriptions {
+				nssub := *ssub
+				if isSampled(ssub) {
+					nssub.SampleInterval = interval
+					changed = true
+				}
+				nsub.StreamSubscriptions = append(nsub.StreamSubscriptions, &nssub)
+			}
+		}
+	}
+	if !changed {
+		return sub
+	}
+	return &nsub
+}
+
+// isSampled reports whether sub is a sampled STREAM subscription.
+func isSampled(sub *SubscriptionConfig) bool {
+	if strings.ToLower(sub.StreamMode) == "sample" {
+		return true
+	}
+	return sub.SampleInterval != nil && *sub.SampleInterval > 0
+}
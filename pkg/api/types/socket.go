@@ -0,0 +1,92 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// SocketOptions are the options applied to the gNMI server listener socket
+// or to the sockets of the gRPC connections to a target.
+type SocketOptions struct {
+	// DSCP value (0-63) set in the IP header of the sent packets.
+	DSCP *int `mapstructure:"dscp,omitempty" yaml:"dscp,omitempty" json:"dscp,omitempty"`
+	// IPv4 TOS or IPv6 traffic class byte (0-255),
+	// mutually exclusive with DSCP.
+	TOS *int `mapstructure:"tos,omitempty" yaml:"tos,omitempty" json:"tos,omitempty"`
+	// name of the network interface or VRF device the socket is bound to.
+	BindToDevice string `mapstructure:"bind-to-device,omitempty" yaml:"bind-to-device,omitempty" json:"bind-to-device,omitempty"`
+	// source IP address of the connections to a target,
+	// not applicable to the gNMI server listener.
+	SourceAddress string `mapstructure:"source-address,omitempty" yaml:"source-address,omitempty" json:"source-address,omitempty"`
+}
+
+// Validate checks the socket options values.
+func (so *SocketOptions) Validate() error {
+	if so == nil {
+		return nil
+	}
+	if so.DSCP != nil && so.TOS != nil {
+		return errors.New("dscp and tos are mutually exclusive")
+	}
+	if so.DSCP != nil && (*so.DSCP < 0 || *so.DSCP > 63) {
+		return fmt.Errorf("invalid dscp value %d, must be between 0 and 63", *so.DSCP)
+	}
+	if so.TOS != nil && (*so.TOS < 0 || *so.TOS > 255) {
+		return fmt.Errorf("invalid tos value %d, must be between 0 and 255", *so.TOS)
+	}
+	if so.SourceAddress != "" && net.ParseIP(so.SourceAddress) == nil {
+		return fmt.Errorf("invalid source-address %q", so.SourceAddress)
+	}
+	return nil
+}
+
+// tos returns the TOS byte to set on the socket, if any.
+func (so *SocketOptions) tos() (int, bool) {
+	switch {
+	case so.DSCP != nil:
+		return *so.DSCP << 2, true
+	case so.TOS != nil:
+		return *so.TOS, true
+	}
+	return 0, false
+}
+
+// LocalAddr returns the local address the connections are dialed from,
+// it returns nil if no source address is set.
+func (so *SocketOptions) LocalAddr() net.Addr {
+	if so == nil || so.SourceAddress == "" {
+		return nil
+	}
+	ip := net.ParseIP(so.SourceAddress)
+	if ip == nil {
+		return nil
+	}
+	return &net.TCPAddr{IP: ip}
+}
+
+// Control is meant to be used as a net.Dialer or net.ListenConfig Control function,
+// it sets the socket options on the socket before it is connected or bound.
+func (so *SocketOptions) Control(network, _ string, c syscall.RawConn) error {
+	if so == nil || !strings.HasPrefix(network, "tcp") {
+		return nil
+	}
+	var err error
+	cerr := c.Control(func(fd uintptr) {
+		err = so.setSockOpts(network, fd)
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package types
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func (so *SocketOptions) setSockOpts(network string, fd uintptr) error {
+	if so.BindToDevice != "" {
+		err := syscall.BindToDevice(int(fd), so.BindToDevice)
+		if err != nil {
+			return fmt.Errorf("failed to bind socket to device %q: %w", so.BindToDevice, err)
+		}
+	}
+	tos, ok := so.tos()
+	if !ok {
+		return nil
+	}
+	if network == "tcp6" {
+		err := syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, tos)
+		if err != nil {
+			return fmt.Errorf("failed to set IPv6 traffic class: %w", err)
+		}
+		// dual stack sockets carry IPv4 traffic as well,
+		// the error is ignored for IPv6 only sockets.
+		syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, tos)
+		return nil
+	}
+	err := syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, tos)
+	if err != nil {
+		return fmt.Errorf("failed to set IP TOS: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package types
+
+import "errors"
+
+func (so *SocketOptions) setSockOpts(_ string, _ uintptr) error {
+	_, ok := so.tos()
+	if so.BindToDevice != "" || ok {
+		return errors.New("dscp, tos and bind-to-device socket options are only supported on linux")
+	}
+	return nil
+}
@@ -39,6 +39,76 @@ type SubscriptionConfig struct {
 	StreamSubscriptions []*SubscriptionConfig `mapstructure:"stream-subscriptions,omitempty" json:"stream-subscriptions,omitempty"`
 	Outputs             []string              `mapstructure:"outputs,omitempty" json:"outputs,omitempty"`
 	Depth               uint32                `mapstructure:"depth,omitempty" json:"depth,omitempty"`
+	OutputOptions       *OutputOptions        `mapstructure:"output-options,omitempty" json:"output-options,omitempty"`
+	AbsenceAlarm        *AbsenceAlarmConfig   `mapstructure:"absence-alarm,omitempty" json:"absence-alarm,omitempty"`
+	Priority            string                `mapstructure:"priority,omitempty" json:"priority,omitempty"`
+	SyncEvent           string                `mapstructure:"sync-event,omitempty" json:"sync-event,omitempty"`
+	PollInterval        time.Duration         `mapstructure:"poll-interval,omitempty" json:"poll-interval,omitempty"`
+	PollTriggers        []string              `mapstructure:"poll-triggers,omitempty" json:"poll-triggers,omitempty"`
+	ExpandWildcards     *WildcardExpansion    `mapstructure:"expand-wildcards,omitempty" json:"expand-wildcards,omitempty"`
+	Neighborhood        []*NeighborhoodRule   `mapstructure:"neighborhood,omitempty" json:"neighborhood,omitempty"`
+}
+
+// POLL subscriptions triggers, on top of the poll-interval.
+const (
+	// Poll requests sent on REST API calls.
+	PollTriggerAPI = "api"
+	// Poll requests sent when gNMIc receives a SIGUSR1.
+	PollTriggerSignal = "signal"
+)
+
+// HasPollTrigger reports whether the Poll requests of the subscription
+// can be triggered by trigger.
+func (sc *SubscriptionConfig) HasPollTrigger(trigger string) bool {
+	for _, t := range sc.PollTriggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// subscription priority classes, they define the order in which
+// the subscriptions responses are shed when the pipeline is congested.
+const (
+	SubscriptionPriorityHigh   = "high"
+	SubscriptionPriorityNormal = "normal"
+	SubscriptionPriorityLow    = "low"
+)
+
+// AbsenceAlarmConfig defines the maximum time a STREAM subscription
+// can go without receiving any update before an alarm event is sent to the outputs.
+type AbsenceAlarmConfig struct {
+	Interval  time.Duration `mapstructure:"interval,omitempty" yaml:"interval,omitempty" json:"interval,omitempty"`
+	EventName string        `mapstructure:"event-name,omitempty" yaml:"event-name,omitempty" json:"event-name,omitempty"`
+}
+
+// OutputOptions overrides the format and the event processors
+// used by the outputs to write the messages of a subscription.
+type OutputOptions struct {
+	Format          string   `mapstructure:"format,omitempty" yaml:"format,omitempty" json:"format,omitempty"`
+	EventProcessors []string `mapstructure:"event-processors,omitempty" yaml:"event-processors,omitempty" json:"event-processors,omitempty"`
+}
+
+// WildcardExpansion makes the client expand the wildcard paths of a subscription
+// into the keyed paths found with a Get request before subscribing.
+// If RefreshInterval is set, the paths are discovered again periodically.
+type WildcardExpansion struct {
+	RefreshInterval time.Duration `mapstructure:"refresh-interval,omitempty" yaml:"refresh-interval,omitempty" json:"refresh-interval,omitempty"`
+}
+
+// NeighborhoodRule makes the client subscribe to the paths related to the data
+// received by a STREAM subscription.
+// When an update matching Path is received, a subscription to the Subscribe paths is created.
+// Path is a full path where the key values set to `*` are captured by key name,
+// the Subscribe paths are Go templates rendered with the captured key values.
+// The created subscription copies the Subscription one, or the subscription the rule belongs to.
+// It is removed when the matched path is deleted.
+type NeighborhoodRule struct {
+	Path             string   `mapstructure:"path,omitempty" yaml:"path,omitempty" json:"path,omitempty"`
+	Subscribe        []string `mapstructure:"subscribe,omitempty" yaml:"subscribe,omitempty" json:"subscribe,omitempty"`
+	Subscription     string   `mapstructure:"subscription,omitempty" yaml:"subscription,omitempty" json:"subscription,omitempty"`
+	MaxSubscriptions int      `mapstructure:"max-subscriptions,omitempty" yaml:"max-subscriptions,omitempty" json:"max-subscriptions,omitempty"`
 }
 
 type HistoryConfig struct {
@@ -131,6 +131,7 @@ type TargetConfig struct {
 	SkipVerify    *bool             `mapstructure:"skip-verify,omitempty" yaml:"skip-verify,omitempty" json:"skip-verify,omitempty"`
 	TLSServerName string            `mapstructure:"tls-server-name,omitempty" yaml:"tls-server-name,omitempty" json:"tls-server-name,omitempty"`
 	Subscriptions []string          `mapstructure:"subscriptions,omitempty" yaml:"subscriptions,omitempty" json:"subscriptions,omitempty"`
+	Bundles       []string          `mapstructure:"bundles,omitempty" yaml:"bundles,omitempty" json:"bundles,omitempty"`
 	Outputs       []string          `mapstructure:"outputs,omitempty" yaml:"outputs,omitempty" json:"outputs,omitempty"`
 	BufferSize    uint              `mapstructure:"buffer-size,omitempty" yaml:"buffer-size,omitempty" json:"buffer-size,omitempty"`
 	RetryTimer    time.Duration     `mapstructure:"retry,omitempty" yaml:"retry-timer,omitempty" json:"retry-timer,omitempty"`
@@ -142,7 +143,9 @@ type TargetConfig struct {
 	ProtoDirs     []string          `mapstructure:"proto-dirs,omitempty" yaml:"proto-dirs,omitempty" json:"proto-dirs,omitempty"`
 	Tags          []string          `mapstructure:"tags,omitempty" yaml:"tags,omitempty" json:"tags,omitempty"`
 	EventTags     map[string]string `mapstructure:"event-tags,omitempty" yaml:"event-tags,omitempty" json:"event-tags,omitempty"`
+	Labels        map[string]string `mapstructure:"labels,omitempty" yaml:"labels,omitempty" json:"labels,omitempty"`
 	Gzip          *bool             `mapstructure:"gzip,omitempty" yaml:"gzip,omitempty" json:"gzip,omitempty"`
+	Compression   string            `mapstructure:"compression,omitempty" yaml:"compression,omitempty" json:"compression,omitempty"`
 	Token         *string           `mapstructure:"token,omitempty" yaml:"token,omitempty" json:"token,omitempty"`
 	Proxy         string            `mapstructure:"proxy,omitempty" yaml:"proxy,omitempty" json:"proxy,omitempty"`
 	//
@@ -152,8 +155,31 @@ type TargetConfig struct {
 	CipherSuites     []string          `mapstructure:"cipher-suites,omitempty" yaml:"cipher-suites,omitempty" json:"cipher-suites,omitempty"`
 	TCPKeepalive     time.Duration     `mapstructure:"tcp-keepalive,omitempty" yaml:"tcp-keepalive,omitempty" json:"tcp-keepalive,omitempty"`
 	GRPCKeepalive    *clientKeepalive  `mapstructure:"grpc-keepalive,omitempty" yaml:"grpc-keepalive,omitempty" json:"grpc-keepalive,omitempty"`
-
-	tlsConfig *tls.Config
+	DNS              *DNSConfig        `mapstructure:"dns,omitempty" yaml:"dns,omitempty" json:"dns,omitempty"`
+	SocketOptions    *SocketOptions    `mapstructure:"socket-options,omitempty" yaml:"socket-options,omitempty" json:"socket-options,omitempty"`
+	Budget           *BudgetConfig     `mapstructure:"budget,omitempty" yaml:"budget,omitempty" json:"budget,omitempty"`
+	Schedule         *ScheduleConfig   `mapstructure:"schedule,omitempty" yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	// if set, the subscribe responses are checked for gNMI specification violations.
+	ResponseValidation *ResponseValidationConfig `mapstructure:"response-validation,omitempty" yaml:"response-validation,omitempty" json:"response-validation,omitempty"`
+	// how the module-qualified path elements names are sent to the target,
+	// one of strip, first, origin or prefix. Sent as written if empty.
+	PathNamespace string `mapstructure:"path-namespace,omitempty" yaml:"path-namespace,omitempty" json:"path-namespace,omitempty"`
+	// if true, the target vendor, OS and OS version are detected on first connect.
+	AutoDetect bool `mapstructure:"auto-detect,omitempty" yaml:"auto-detect,omitempty" json:"auto-detect,omitempty"`
+	// if true, the TLS sessions are cached and resumed on reconnect.
+	TLSSessionResumption *bool `mapstructure:"tls-session-resumption,omitempty" yaml:"tls-session-resumption,omitempty" json:"tls-session-resumption,omitempty"`
+	// if true, the responses received from the target are written
+	// to the outputs one at a time, in the order they were received.
+	OrderedDelivery bool `mapstructure:"ordered-delivery,omitempty" yaml:"ordered-delivery,omitempty" json:"ordered-delivery,omitempty"`
+	// if true, the gRPC connection state changes and the dial failures
+	// are exported as events to the target outputs.
+	ConnectionEvents bool `mapstructure:"connection-events,omitempty" yaml:"connection-events,omitempty" json:"connection-events,omitempty"`
+	// per subscription output options, they take precedence over
+	// the output options set under the subscription.
+	SubscriptionsOutputOptions map[string]*OutputOptions `mapstructure:"subscriptions-output-options,omitempty" yaml:"subscriptions-output-options,omitempty" json:"subscriptions-output-options,omitempty"`
+
+	tlsConfig       *tls.Config
+	tlsSessionCache tls.ClientSessionCache
 }
 
 type clientKeepalive struct {
@@ -180,6 +206,13 @@ func (tc *TargetConfig) SetTLSConfig(tlsConfig *tls.Config) {
 	tc.tlsConfig = tlsConfig
 }
 
+// SetTLSSessionCache sets the cache used to store the target TLS sessions
+// when tls-session-resumption is enabled.
+// If not set, a per target in memory cache is used.
+func (tc *TargetConfig) SetTLSSessionCache(c tls.ClientSessionCache) {
+	tc.tlsSessionCache = c
+}
+
 // NewTLSConfig //
 func (tc *TargetConfig) NewTLSConfig() (*tls.Config, error) {
 	if tc.tlsConfig != nil {
@@ -199,8 +232,19 @@ func (tc *TargetConfig) NewTLSConfig() (*tls.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	resumption := tc.TLSSessionResumption != nil && *tc.TLSSessionResumption
 	if tlsConfig == nil {
-		return nil, nil
+		if !resumption {
+			return nil, nil
+		}
+		// default TLS config, with a session cache.
+		tlsConfig = new(tls.Config)
+	}
+	if resumption {
+		if tc.tlsSessionCache == nil {
+			tc.tlsSessionCache = tls.NewLRUClientSessionCache(0)
+		}
+		tlsConfig.ClientSessionCache = tc.tlsSessionCache
 	}
 	if tc.LogTLSSecret != nil && *tc.LogTLSSecret {
 		logPath := tc.Name + ".tlssecret.log"
@@ -240,8 +284,11 @@ func (tc *TargetConfig) NewTLSConfig() (*tls.Config, error) {
 // GrpcDialOptions creates the grpc.dialOption list from the target's configuration
 func (tc *TargetConfig) GrpcDialOptions() ([]grpc.DialOption, error) {
 	tOpts := make([]grpc.DialOption, 0, 1)
-	// gzip
-	if tc.Gzip != nil && *tc.Gzip {
+	// compression, takes precedence over gzip
+	switch {
+	case tc.Compression != "":
+		tOpts = append(tOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(tc.Compression)))
+	case tc.Gzip != nil && *tc.Gzip:
 		tOpts = append(tOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
 	}
 	// gRPC keepalive
@@ -252,21 +299,15 @@ func (tc *TargetConfig) GrpcDialOptions() ([]grpc.DialOption, error) {
 			PermitWithoutStream: tc.GRPCKeepalive.PermitWithoutStream,
 		}))
 	}
+	creds, err := tc.TransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	tOpts = append(tOpts, grpc.WithTransportCredentials(creds))
 	// insecure
 	if tc.Insecure != nil && *tc.Insecure {
-		tOpts = append(tOpts,
-			grpc.WithTransportCredentials(
-				insecure.NewCredentials(),
-			),
-		)
 		return tOpts, nil
 	}
-	// secure
-	tlsConfig, err := tc.NewTLSConfig()
-	if err != nil {
-		return nil, err
-	}
-	tOpts = append(tOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	// token credentials
 	if tc.Token != nil && *tc.Token != "" {
 		tOpts = append(tOpts,
@@ -283,6 +324,18 @@ func (tc *TargetConfig) GrpcDialOptions() ([]grpc.DialOption, error) {
 	return tOpts, nil
 }
 
+// TransportCredentials returns the gRPC transport credentials of the target connection.
+func (tc *TargetConfig) TransportCredentials() (credentials.TransportCredentials, error) {
+	if tc.Insecure != nil && *tc.Insecure {
+		return insecure.NewCredentials(), nil
+	}
+	tlsConfig, err := tc.NewTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
 func (tc *TargetConfig) UsernameString() string {
 	if tc.Username == nil {
 		return notApplicable
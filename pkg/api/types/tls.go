@@ -1,6 +1,12 @@
 package types
 
-import "fmt"
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/openconfig/gnmic/pkg/api/utils"
+)
 
 type TLSConfig struct {
 	CaFile     string `mapstructure:"ca-file,omitempty"`
@@ -8,6 +14,25 @@ type TLSConfig struct {
 	CertFile   string `mapstructure:"cert-file,omitempty"`
 	SkipVerify bool   `mapstructure:"skip-verify,omitempty"`
 	ClientAuth string `mapstructure:"client-auth,omitempty"`
+	// client side only options.
+	// ServerName overrides the server name sent in the SNI extension
+	// and checked against the server certificate.
+	ServerName string `mapstructure:"server-name,omitempty"`
+	// SystemCA adds the ca-file certificates to the system CA pool instead of replacing it.
+	SystemCA bool `mapstructure:"system-ca,omitempty"`
+	// SPIFFE retrieves the client certificate and the trust bundle from a SPIFFE Workload API.
+	SPIFFE *SPIFFEConfig `mapstructure:"spiffe,omitempty"`
+}
+
+// SPIFFEConfig defines how the X.509 SVID is retrieved from a SPIFFE Workload API.
+type SPIFFEConfig struct {
+	// Workload API address, e.g unix:///run/spire/sockets/agent.sock.
+	// Defaults to the SPIFFE_ENDPOINT_SOCKET environment variable.
+	SocketPath string `mapstructure:"socket-path,omitempty"`
+	// SPIFFE ID of the SVID to use, if the workload gets more than one.
+	SPIFFEID string `mapstructure:"spiffe-id,omitempty"`
+	// SPIFFE ID expected in the server certificate, any ID from the trust domain is accepted if not set.
+	ServerID string `mapstructure:"server-id,omitempty"`
 }
 
 func (t *TLSConfig) Validate() error {
@@ -25,3 +50,58 @@ func (t *TLSConfig) Validate() error {
 	}
 	return nil
 }
+
+// ClientConfig builds the *tls.Config of a client connection.
+// It returns nil if no TLS option is set.
+func (t *TLSConfig) ClientConfig() (*tls.Config, error) {
+	if t == nil {
+		return nil, nil
+	}
+	if t.SPIFFE != nil {
+		if t.CaFile != "" || t.CertFile != "" || t.KeyFile != "" {
+			return nil, fmt.Errorf("spiffe cannot be set together with ca-file, cert-file or key-file")
+		}
+		return t.spiffeClientConfig()
+	}
+	tlsConfig, err := utils.NewTLSConfig(t.CaFile, t.CertFile, t.KeyFile, "", t.SkipVerify, false)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		if t.ServerName == "" {
+			return nil, nil
+		}
+		tlsConfig = new(tls.Config)
+	}
+	tlsConfig.ServerName = t.ServerName
+	if t.SystemCA && t.CaFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		err = utils.AppendCACertificates(pool, t.CaFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+func (t *TLSConfig) spiffeClientConfig() (*tls.Config, error) {
+	src, err := utils.SharedX509Source(t.SPIFFE.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	// the server certificate is verified against the SPIFFE trust bundle
+	// and ID instead of the system CAs and the host name.
+	tlsConfig := &tls.Config{
+		ServerName:           t.ServerName,
+		InsecureSkipVerify:   true,
+		GetClientCertificate: src.GetClientCertificate(t.SPIFFE.SPIFFEID),
+	}
+	if !t.SkipVerify {
+		tlsConfig.VerifyPeerCertificate = src.VerifyPeerCertificate(t.SPIFFE.SPIFFEID, t.SPIFFE.ServerID)
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,102 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// UnixSocket sets the permissions of the unix socket file a server listens on.
+type UnixSocket struct {
+	// file mode in octal notation, e.g: 0660.
+	Mode string `mapstructure:"mode,omitempty" yaml:"mode,omitempty" json:"mode,omitempty"`
+	// owner user name or uid.
+	Owner string `mapstructure:"owner,omitempty" yaml:"owner,omitempty" json:"owner,omitempty"`
+	// owner group name or gid.
+	Group string `mapstructure:"group,omitempty" yaml:"group,omitempty" json:"group,omitempty"`
+}
+
+// Validate checks the socket file mode.
+func (us *UnixSocket) Validate() error {
+	if us == nil || us.Mode == "" {
+		return nil
+	}
+	_, err := us.fileMode()
+	return err
+}
+
+func (us *UnixSocket) fileMode() (os.FileMode, error) {
+	m, err := strconv.ParseUint(us.Mode, 8, 32)
+	if err != nil || m > 0777 {
+		return 0, fmt.Errorf("invalid unix socket mode %q, must be an octal permission, e.g: 0660", us.Mode)
+	}
+	return os.FileMode(m), nil
+}
+
+// Apply sets the ownership then the mode of the socket file at path.
+func (us *UnixSocket) Apply(path string) error {
+	if us == nil {
+		return nil
+	}
+	if us.Owner != "" || us.Group != "" {
+		uid, gid := -1, -1
+		if us.Owner != "" {
+			id, err := lookupID(us.Owner, func(name string) (string, error) {
+				u, err := user.Lookup(name)
+				if err != nil {
+					return "", err
+				}
+				return u.Uid, nil
+			})
+			if err != nil {
+				return fmt.Errorf("unix socket owner %q: %w", us.Owner, err)
+			}
+			uid = id
+		}
+		if us.Group != "" {
+			id, err := lookupID(us.Group, func(name string) (string, error) {
+				g, err := user.LookupGroup(name)
+				if err != nil {
+					return "", err
+				}
+				return g.Gid, nil
+			})
+			if err != nil {
+				return fmt.Errorf("unix socket group %q: %w", us.Group, err)
+			}
+			gid = id
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+	if us.Mode == "" {
+		return nil
+	}
+	m, err := us.fileMode()
+	if err != nil {
+		return err
+	}
+	return os.Chmod(path, m)
+}
+
+// lookupID returns s if it is numeric, its id looked up by name otherwise.
+func lookupID(s string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	ids, err := lookup(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(ids)
+}
@@ -0,0 +1,102 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const unixSocketPrefix = "unix://"
+
+// Address is a parsed network address.
+type Address struct {
+	// "tcp" or "unix"
+	Network string
+	// host name or IP address, without brackets.
+	// IPv6 addresses keep their zone, e.g: fe80::1%eth0.
+	// For unix sockets, the socket path.
+	Host string
+	Port string
+}
+
+// ParseAddress parses addr, which can be one of:
+//   - host, host:port
+//   - IPv4, IPv4:port
+//   - IPv6, [IPv6], [IPv6]:port, optionally with a zone: fe80::1%eth0, [fe80::1%eth0]:57400
+//   - unix:///path/to/socket
+//
+// defaultPort is used when addr does not include a port,
+// an IPv6 address without brackets is never considered to include a port.
+func ParseAddress(addr, defaultPort string) (*Address, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil, errors.New("empty address")
+	}
+	if strings.HasPrefix(addr, unixSocketPrefix) {
+		return &Address{Network: "unix", Host: strings.TrimPrefix(addr, unixSocketPrefix)}, nil
+	}
+	a := &Address{Network: "tcp"}
+	var err error
+	switch {
+	case strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]"):
+		// bracketed IPv6 without port
+		a.Host = addr[1 : len(addr)-1]
+	case !strings.HasPrefix(addr, "[") && strings.Count(addr, ":") > 1:
+		// IPv6 without brackets nor port
+		a.Host = addr
+	default:
+		a.Host, a.Port, err = net.SplitHostPort(addr)
+		if err != nil {
+			if !strings.Contains(err.Error(), "missing port in address") {
+				return nil, fmt.Errorf("invalid address %q: %v", addr, err)
+			}
+			a.Host = addr
+		}
+	}
+	if strings.Contains(a.Host, ":") {
+		if _, err := netip.ParseAddr(a.Host); err != nil {
+			return nil, fmt.Errorf("invalid address %q: %v", addr, err)
+		}
+	}
+	if a.Port == "" {
+		a.Port = defaultPort
+	}
+	if a.Port == "" {
+		return nil, fmt.Errorf("invalid address %q: missing port", addr)
+	}
+	if p, err := strconv.Atoi(a.Port); err == nil && (p < 0 || p > 65535) {
+		return nil, fmt.Errorf("invalid address %q: port out of range", addr)
+	}
+	return a, nil
+}
+
+// String returns the address in the host:port format,
+// with IPv6 addresses enclosed in brackets;
+// or in the unix:///path format for unix sockets.
+func (a *Address) String() string {
+	if a.Network == "unix" {
+		return unixSocketPrefix + a.Host
+	}
+	return net.JoinHostPort(a.Host, a.Port)
+}
+
+// GRPCTarget returns addr escaped so that it can be used as a gRPC dial target.
+// gRPC parses dial targets as URLs, which requires the IPv6 zone
+// delimiter to be percent-encoded (RFC 6874).
+func GRPCTarget(addr string) string {
+	if !strings.Contains(addr, "%") || strings.Contains(addr, "%25") {
+		return addr
+	}
+	return strings.ReplaceAll(addr, "%", "%25")
+}
@@ -0,0 +1,155 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        string
+		defaultPort string
+		want        *Address
+		wantString  string
+		wantErr     bool
+	}{
+		{
+			name:        "hostname",
+			addr:        "router1",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "router1", Port: "57400"},
+			wantString:  "router1:57400",
+		},
+		{
+			name:        "hostname_port",
+			addr:        "router1:6030",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "router1", Port: "6030"},
+			wantString:  "router1:6030",
+		},
+		{
+			name:        "ipv4",
+			addr:        " 10.1.1.1 ",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "10.1.1.1", Port: "57400"},
+			wantString:  "10.1.1.1:57400",
+		},
+		{
+			name:       "any_address_port",
+			addr:       ":7890",
+			want:       &Address{Network: "tcp", Host: "", Port: "7890"},
+			wantString: ":7890",
+		},
+		{
+			name:        "ipv6",
+			addr:        "2001:db8::1",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "2001:db8::1", Port: "57400"},
+			wantString:  "[2001:db8::1]:57400",
+		},
+		{
+			name:        "ipv6_brackets",
+			addr:        "[2001:db8::1]",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "2001:db8::1", Port: "57400"},
+			wantString:  "[2001:db8::1]:57400",
+		},
+		{
+			name:        "ipv6_port",
+			addr:        "[2001:db8::1]:6030",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "2001:db8::1", Port: "6030"},
+			wantString:  "[2001:db8::1]:6030",
+		},
+		{
+			name:        "ipv6_zone",
+			addr:        "fe80::1%mgmt",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "fe80::1%mgmt", Port: "57400"},
+			wantString:  "[fe80::1%mgmt]:57400",
+		},
+		{
+			name:        "ipv6_zone_port",
+			addr:        "[fe80::1%mgmt]:6030",
+			defaultPort: "57400",
+			want:        &Address{Network: "tcp", Host: "fe80::1%mgmt", Port: "6030"},
+			wantString:  "[fe80::1%mgmt]:6030",
+		},
+		{
+			name:       "unix",
+			addr:       "unix:///var/run/gnmi.sock",
+			want:       &Address{Network: "unix", Host: "/var/run/gnmi.sock"},
+			wantString: "unix:///var/run/gnmi.sock",
+		},
+		{
+			name:    "empty",
+			addr:    "",
+			wantErr: true,
+		},
+		{
+			name:    "missing_port",
+			addr:    "router1",
+			wantErr: true,
+		},
+		{
+			name:        "invalid_ipv6",
+			addr:        "[2001:db8::zz]:6030",
+			defaultPort: "57400",
+			wantErr:     true,
+		},
+		{
+			name:        "port_out_of_range",
+			addr:        "router1:70000",
+			defaultPort: "57400",
+			wantErr:     true,
+		},
+		{
+			name:        "unclosed_bracket",
+			addr:        "[2001:db8::1:6030",
+			defaultPort: "57400",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAddress(tt.addr, tt.defaultPort)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("got %+v, expected %+v", got, tt.want)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("got string %q, expected %q", got.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestGRPCTarget(t *testing.T) {
+	tests := map[string]string{
+		"router1:57400":          "router1:57400",
+		"[2001:db8::1]:57400":    "[2001:db8::1]:57400",
+		"[fe80::1%mgmt]:57400":   "[fe80::1%25mgmt]:57400",
+		"[fe80::1%25mgmt]:57400": "[fe80::1%25mgmt]:57400",
+	}
+	for in, want := range tests {
+		if got := GRPCTarget(in); got != want {
+			t.Errorf("%q: got %q, expected %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// SPIFFEEndpointSocketEnv is the environment variable holding
+	// the default SPIFFE Workload API address.
+	SPIFFEEndpointSocketEnv = "SPIFFE_ENDPOINT_SOCKET"
+
+	spiffeFetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+	spiffeHeaderKey           = "workload.spiffe.io"
+
+	spiffeFirstSVIDTimeout = 10 * time.Second
+	spiffeMaxRetryInterval = 30 * time.Second
+)
+
+// X509SVID is a SPIFFE X.509 SVID: a certificate chain, its private key
+// and the CA bundle of its trust domain.
+type X509SVID struct {
+	ID          string
+	Certificate tls.Certificate
+	Bundle      []*x509.Certificate
+}
+
+// X509Source keeps the X.509 SVIDs received from a SPIFFE Workload API up to date.
+// The Workload API pushes new SVIDs before the current ones expire.
+type X509Source struct {
+	addr string
+
+	m     *sync.RWMutex
+	svids []*X509SVID
+	err   error
+	ready chan struct{}
+	once  *sync.Once
+}
+
+var (
+	x509SourcesMu = new(sync.Mutex)
+	x509Sources   = make(map[string]*X509Source)
+)
+
+// SharedX509Source returns the X509Source of the Workload API at addr,
+// it is created and started on first use then shared by all callers.
+// If addr is empty, the address is read from the SPIFFE_ENDPOINT_SOCKET environment variable.
+// It waits for the first SVIDs to be received.
+func SharedX509Source(addr string) (*X509Source, error) {
+	if addr == "" {
+		addr = os.Getenv(SPIFFEEndpointSocketEnv)
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("missing SPIFFE Workload API address, set it or the %s environment variable", SPIFFEEndpointSocketEnv)
+	}
+	x509SourcesMu.Lock()
+	s, ok := x509Sources[addr]
+	if !ok {
+		s = newX509Source(addr)
+		x509Sources[addr] = s
+		go s.run(context.Background())
+	}
+	x509SourcesMu.Unlock()
+	return s, s.wait(spiffeFirstSVIDTimeout)
+}
+
+func newX509Source(addr string) *X509Source {
+	return &X509Source{
+		addr:  addr,
+		m:     new(sync.RWMutex),
+		ready: make(chan struct{}),
+		once:  new(sync.Once),
+	}
+}
+
+func (s *X509Source) wait(timeout time.Duration) error {
+	select {
+	case <-s.ready:
+	case <-time.After(timeout):
+		s.m.RLock()
+		defer s.m.RUnlock()
+		if s.err != nil {
+			return fmt.Errorf("no SVID received from %s: %w", s.addr, s.err)
+		}
+		return fmt.Errorf("no SVID received from %s within %s", s.addr, timeout)
+	}
+	return nil
+}
+
+// run fetches the SVIDs until ctx is done, reconnecting on failure.
+func (s *X509Source) run(ctx context.Context) {
+	retry := time.Second
+	for {
+		err := s.fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		s.m.Lock()
+		s.err = err
+		s.m.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		if retry *= 2; retry > spiffeMaxRetryInterval {
+			retry = spiffeMaxRetryInterval
+		}
+	}
+}
+
+// fetch opens a FetchX509SVID stream and records the received SVIDs.
+func (s *X509Source) fetch(ctx context.Context) error {
+	conn, err := grpc.DialContext(ctx, s.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, spiffeHeaderKey, "true"))
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true},
+		spiffeFetchX509SVIDMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+	// the request message has no fields
+	err = stream.SendMsg(&[]byte{})
+	if err != nil {
+		return err
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		return err
+	}
+	for {
+		var b []byte
+		err = stream.RecvMsg(&b)
+		if err != nil {
+			return err
+		}
+		svids, err := parseX509SVIDResponse(b)
+		if err != nil {
+			return err
+		}
+		if len(svids) == 0 {
+			continue
+		}
+		s.m.Lock()
+		s.svids = svids
+		s.err = nil
+		s.m.Unlock()
+		s.once.Do(func() { close(s.ready) })
+	}
+}
+
+// svid returns the SVID with the given SPIFFE ID, or the first one if id is empty.
+func (s *X509Source) svid(id string) (*X509SVID, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	for _, svid := range s.svids {
+		if id == "" || svid.ID == id {
+			return svid, nil
+		}
+	}
+	if id == "" {
+		return nil, errors.New("no SVID available")
+	}
+	return nil, fmt.Errorf("no SVID with ID %q available", id)
+}
+
+// GetClientCertificate returns a tls.Config GetClientCertificate function
+// presenting the current SVID with SPIFFE ID id, or the first one if id is empty.
+func (s *X509Source) GetClientCertificate(id string) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		svid, err := s.svid(id)
+		if err != nil {
+			return nil, err
+		}
+		return &svid.Certificate, nil
+	}
+}
+
+// VerifyPeerCertificate returns a tls.Config VerifyPeerCertificate function
+// verifying the peer certificate chain against the current trust bundle.
+// If serverID is not empty, the peer certificate must have it as URI SAN.
+// The tls.Config using it must set InsecureSkipVerify.
+func (s *X509Source) VerifyPeerCertificate(id, serverID string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		svid, err := s.svid(id)
+		if err != nil {
+			return err
+		}
+		return verifySVIDChain(rawCerts, svid.Bundle, serverID)
+	}
+}
+
+func verifySVIDChain(rawCerts [][]byte, bundle []*x509.Certificate, serverID string) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	roots := x509.NewCertPool()
+	for _, c := range bundle {
+		roots.AddCert(c)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return err
+	}
+	if serverID == "" {
+		return nil
+	}
+	for _, u := range certs[0].URIs {
+		if u.String() == serverID {
+			return nil
+		}
+	}
+	return fmt.Errorf("peer certificate does not have SPIFFE ID %q", serverID)
+}
+
+// parseX509SVIDResponse decodes a X509SVIDResponse message.
+//
+//	message X509SVIDResponse {
+//	  repeated X509SVID svids = 1;
+//	  ...
+//	}
+func parseX509SVIDResponse(b []byte) ([]*X509SVID, error) {
+	svids := make([]*X509SVID, 0, 1)
+	err := rangeProtoBytesFields(b, func(num protowire.Number, v []byte) error {
+		if num != 1 {
+			return nil
+		}
+		svid, err := parseX509SVID(v)
+		if err != nil {
+			return err
+		}
+		svids = append(svids, svid)
+		return nil
+	})
+	return svids, err
+}
+
+// parseX509SVID decodes a X509SVID message.
+//
+//	message X509SVID {
+//	  string spiffe_id = 1;
+//	  bytes x509_svid = 2;     // ASN.1 DER certificates, leaf first
+//	  bytes x509_svid_key = 3; // ASN.1 DER PKCS#8 private key
+//	  bytes bundle = 4;        // ASN.1 DER CA certificates
+//	}
+func parseX509SVID(b []byte) (*X509SVID, error) {
+	var chain, key, bundle []byte
+	svid := new(X509SVID)
+	err := rangeProtoBytesFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			svid.ID = string(v)
+		case 2:
+			chain = v
+		case 3:
+			key = v
+		case 4:
+			bundle = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	certs, err := x509.ParseCertificates(chain)
+	if err != nil {
+		return nil, fmt.Errorf("SVID %q: invalid certificates: %w", svid.ID, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("SVID %q: missing certificates", svid.ID)
+	}
+	pk, err := x509.ParsePKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("SVID %q: invalid private key: %w", svid.ID, err)
+	}
+	signer, ok := pk.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("SVID %q: unsupported private key type %T", svid.ID, pk)
+	}
+	svid.Bundle, err = x509.ParseCertificates(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("SVID %q: invalid bundle: %w", svid.ID, err)
+	}
+	svid.Certificate = tls.Certificate{
+		PrivateKey: signer,
+		Leaf:       certs[0],
+	}
+	for _, c := range certs {
+		svid.Certificate.Certificate = append(svid.Certificate.Certificate, c.Raw)
+	}
+	return svid, nil
+}
+
+// rangeProtoBytesFields calls fn with the length delimited fields of the message b,
+// the other fields are skipped.
+func rangeProtoBytesFields(b []byte, fn func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		err := fn(num, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawCodec passes the messages bytes as is, the messages are *[]byte.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
@@ -0,0 +1,211 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// svid returns the DER certificate and PKCS#8 key of an SVID with the given SPIFFE ID.
+func (ca *testCA) svid(t *testing.T, id string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{u},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, pk
+}
+
+func x509SVIDResponse(id string, cert, key, bundle []byte) []byte {
+	var svid []byte
+	svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+	svid = protowire.AppendString(svid, id)
+	svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, cert)
+	svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, key)
+	svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, bundle)
+	var rsp []byte
+	rsp = protowire.AppendTag(rsp, 1, protowire.BytesType)
+	return protowire.AppendBytes(rsp, svid)
+}
+
+// workloadAPIServer serves the FetchX509SVID method on a unix socket,
+// it sends rsp to each client and returns the server address.
+func workloadAPIServer(t *testing.T, rsp []byte) string {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			if method != spiffeFetchX509SVIDMethod {
+				return errors.New("unexpected method")
+			}
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			if len(md.Get(spiffeHeaderKey)) == 0 {
+				return errors.New("missing security header")
+			}
+			var req []byte
+			err := stream.RecvMsg(&req)
+			if err != nil {
+				return err
+			}
+			err = stream.SendMsg(&rsp)
+			if err != nil {
+				return err
+			}
+			<-stream.Context().Done()
+			return nil
+		}),
+	)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+	return "unix://" + sock
+}
+
+func TestSharedX509Source(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key := ca.svid(t, "spiffe://example.org/gnmic")
+	addr := workloadAPIServer(t, x509SVIDResponse("spiffe://example.org/gnmic", cert, key, ca.cert.Raw))
+
+	src, err := SharedX509Source(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := SharedX509Source(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src != again {
+		t.Errorf("expected the source to be shared")
+	}
+
+	c, err := src.GetClientCertificate("")(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Leaf.URIs[0].String() != "spiffe://example.org/gnmic" {
+		t.Errorf("unexpected client certificate ID: %s", c.Leaf.URIs[0])
+	}
+	_, err = src.GetClientCertificate("spiffe://example.org/other")(nil)
+	if err == nil {
+		t.Errorf("expected an error for an unknown SVID ID")
+	}
+
+	serverCert, _ := ca.svid(t, "spiffe://example.org/kafka")
+	err = src.VerifyPeerCertificate("", "")([][]byte{serverCert}, nil)
+	if err != nil {
+		t.Errorf("expected the server certificate to be valid: %v", err)
+	}
+	err = src.VerifyPeerCertificate("", "spiffe://example.org/kafka")([][]byte{serverCert}, nil)
+	if err != nil {
+		t.Errorf("expected the server ID to match: %v", err)
+	}
+	err = src.VerifyPeerCertificate("", "spiffe://example.org/nats")([][]byte{serverCert}, nil)
+	if err == nil {
+		t.Errorf("expected a server ID mismatch")
+	}
+	otherCert, _ := newTestCA(t).svid(t, "spiffe://example.org/kafka")
+	err = src.VerifyPeerCertificate("", "")([][]byte{otherCert}, nil)
+	if err == nil {
+		t.Errorf("expected a certificate from another CA to be rejected")
+	}
+}
+
+func TestSharedX509SourceMissingAddress(t *testing.T) {
+	t.Setenv(SPIFFEEndpointSocketEnv, "")
+	_, err := SharedX509Source("")
+	if err == nil {
+		t.Errorf("expected an error without a Workload API address")
+	}
+}
+
+func TestParseX509SVIDResponseInvalid(t *testing.T) {
+	ca := newTestCA(t)
+	cert, _ := ca.svid(t, "spiffe://example.org/gnmic")
+	_, err := parseX509SVIDResponse(x509SVIDResponse("spiffe://example.org/gnmic", cert, []byte("key"), ca.cert.Raw))
+	if err == nil {
+		t.Errorf("expected an invalid private key error")
+	}
+	_, err = parseX509SVIDResponse([]byte{0x0a, 0xff})
+	if err == nil {
+		t.Errorf("expected a decoding error")
+	}
+}
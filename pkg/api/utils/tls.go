@@ -206,12 +206,20 @@ func readFromStdin(ctx context.Context) ([]byte, error) {
 // LoadCACertificates reads PEM-encoded CA certificates from a file and adds them to a CertPool.
 // It returns the CertPool and any error encountered.
 func LoadCACertificates(filePath string) (*x509.CertPool, error) {
-	certPEMBlock, err := os.ReadFile(filePath)
+	certPool := x509.NewCertPool()
+	err := AppendCACertificates(certPool, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read the cert file: %s: %w", filePath, err)
+		return nil, err
 	}
+	return certPool, nil
+}
 
-	certPool := x509.NewCertPool()
+// AppendCACertificates reads PEM-encoded CA certificates from a file and adds them to certPool.
+func AppendCACertificates(certPool *x509.CertPool, filePath string) error {
+	certPEMBlock, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read the cert file: %s: %w", filePath, err)
+	}
 
 	for {
 		block, rest := pem.Decode(certPEMBlock)
@@ -222,14 +230,14 @@ func LoadCACertificates(filePath string) (*x509.CertPool, error) {
 
 		cert, err := x509.ParseCertificate(block.Bytes)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			return fmt.Errorf("failed to parse certificate: %w", err)
 		}
 
 		if !cert.IsCA {
-			return nil, fmt.Errorf("file %s contains a certificate that is not a CA", filePath)
+			return fmt.Errorf("file %s contains a certificate that is not a CA", filePath)
 		}
 		certPool.AddCert(cert)
 	}
 
-	return certPool, nil
+	return nil
 }
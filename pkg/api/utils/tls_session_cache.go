@@ -0,0 +1,212 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// sessions older than this are not loaded from the cache file,
+	// TLS 1.3 tickets cannot be used for more than 7 days.
+	tlsSessionMaxAge = 7 * 24 * time.Hour
+	// delay between a session update and the cache file write,
+	// it groups the writes during reconnect storms.
+	tlsSessionSaveDelay = time.Second
+)
+
+// TLSSessionCache is a TLS client session cache shared by multiple targets.
+// Each target gets its own view of the cache using Target(),
+// so that targets with the same server name do not share sessions.
+//
+// If created with a file path, the sessions are persisted to that file
+// and loaded back when the cache is created, allowing the connections
+// to be resumed after a process restart.
+type TLSSessionCache struct {
+	m         sync.Mutex
+	sessions  map[string]*tlsSessionEntry
+	file      string
+	saveTimer *time.Timer
+	onError   func(error)
+}
+
+type tlsSessionEntry struct {
+	Ticket  []byte    `json:"ticket,omitempty"`
+	State   []byte    `json:"state,omitempty"`
+	Created time.Time `json:"created,omitempty"`
+
+	cs *tls.ClientSessionState
+}
+
+// NewTLSSessionCache creates a TLSSessionCache.
+// If file is not empty, the sessions are loaded from it and saved to it on change.
+// onError, if not nil, is called with the errors occurring when the file is written.
+func NewTLSSessionCache(file string, onError func(error)) (*TLSSessionCache, error) {
+	c := &TLSSessionCache{
+		sessions: make(map[string]*tlsSessionEntry),
+		file:     file,
+		onError:  onError,
+	}
+	if file == "" {
+		return c, nil
+	}
+	err := c.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS sessions file %q: %v", file, err)
+	}
+	return c, nil
+}
+
+// Target returns a tls.ClientSessionCache storing
+// the sessions of the target name in the shared cache.
+func (c *TLSSessionCache) Target(name string) tls.ClientSessionCache {
+	return &targetSessionCache{c: c, prefix: name + "/"}
+}
+
+// Len returns the number of cached sessions.
+func (c *TLSSessionCache) Len() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return len(c.sessions)
+}
+
+// Save writes the cached sessions to the cache file, if any.
+func (c *TLSSessionCache) Save() error {
+	if c.file == "" {
+		return nil
+	}
+	c.m.Lock()
+	if c.saveTimer != nil {
+		c.saveTimer.Stop()
+		c.saveTimer = nil
+	}
+	b, err := json.Marshal(c.sessions)
+	c.m.Unlock()
+	if err != nil {
+		return err
+	}
+	// the sessions contain secrets, the file is only readable by its owner.
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file)
+}
+
+func (c *TLSSessionCache) load() error {
+	b, err := os.ReadFile(c.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	sessions := make(map[string]*tlsSessionEntry)
+	err = json.Unmarshal(b, &sessions)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for k, e := range sessions {
+		if now.Sub(e.Created) > tlsSessionMaxAge {
+			continue
+		}
+		state, err := tls.ParseSessionState(e.State)
+		if err != nil {
+			continue
+		}
+		e.cs, err = tls.NewResumptionState(e.Ticket, state)
+		if err != nil {
+			continue
+		}
+		c.sessions[k] = e
+	}
+	return nil
+}
+
+func (c *TLSSessionCache) get(key string) (*tls.ClientSessionState, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	e, ok := c.sessions[key]
+	if !ok {
+		return nil, false
+	}
+	return e.cs, true
+}
+
+func (c *TLSSessionCache) put(key string, cs *tls.ClientSessionState) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if cs == nil {
+		if _, ok := c.sessions[key]; !ok {
+			return
+		}
+		delete(c.sessions, key)
+		c.scheduleSave()
+		return
+	}
+	e := &tlsSessionEntry{Created: time.Now(), cs: cs}
+	if c.file != "" {
+		// sessions that cannot be serialized are only kept in memory.
+		if ticket, state, err := cs.ResumptionState(); err == nil {
+			if b, err := state.Bytes(); err == nil {
+				e.Ticket, e.State = ticket, b
+			}
+		}
+	}
+	c.sessions[key] = e
+	c.scheduleSave()
+}
+
+// scheduleSave arms the save timer if it is not already armed.
+// It assumes c.m is held.
+func (c *TLSSessionCache) scheduleSave() {
+	if c.file == "" || c.saveTimer != nil {
+		return
+	}
+	c.saveTimer = time.AfterFunc(tlsSessionSaveDelay, func() {
+		err := c.Save()
+		if err != nil && c.onError != nil {
+			c.onError(err)
+		}
+	})
+}
+
+type targetSessionCache struct {
+	c      *TLSSessionCache
+	prefix string
+}
+
+func (t *targetSessionCache) Get(sessionKey string) (*tls.ClientSessionState, bool) {
+	return t.c.get(t.prefix + sessionKey)
+}
+
+func (t *targetSessionCache) Put(sessionKey string, cs *tls.ClientSessionState) {
+	t.c.put(t.prefix+sessionKey, cs)
+}
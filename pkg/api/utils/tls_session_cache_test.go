@@ -0,0 +1,137 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tlsSessionServer accepts TLS connections, writes a byte
+// so that the client reads the session tickets, and closes them.
+func tlsSessionServer(t *testing.T, version uint16) net.Listener {
+	t.Helper()
+	cert, err := SelfSignedCerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   version,
+		MaxVersion:   version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte{0})
+			conn.Close()
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+// dialTLS connects to addr using cache and reports
+// whether the TLS session was resumed.
+func dialTLS(t *testing.T, addr string, cache tls.ClientSessionCache) bool {
+	t.Helper()
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		InsecureSkipVerify: true,
+		ClientSessionCache: cache,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = io.ReadFull(conn, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	return conn.ConnectionState().DidResume
+}
+
+func TestTLSSessionCache(t *testing.T) {
+	versions := map[string]uint16{
+		"tls1.2": tls.VersionTLS12,
+		"tls1.3": tls.VersionTLS13,
+	}
+	for name, version := range versions {
+		t.Run(name, func(t *testing.T) {
+			l := tlsSessionServer(t, version)
+			file := filepath.Join(t.TempDir(), "sessions.json")
+
+			c, err := NewTLSSessionCache(file, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dialTLS(t, l.Addr().String(), c.Target("t1")) {
+				t.Fatal("first connection resumed")
+			}
+			if !dialTLS(t, l.Addr().String(), c.Target("t1")) {
+				t.Error("second connection not resumed")
+			}
+			// sessions are not shared between targets
+			if dialTLS(t, l.Addr().String(), c.Target("t2")) {
+				t.Error("connection of another target resumed")
+			}
+			if err = c.Save(); err != nil {
+				t.Fatal(err)
+			}
+			st, err := os.Stat(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.Mode().Perm() != 0600 {
+				t.Errorf("unexpected file permissions %v", st.Mode().Perm())
+			}
+
+			// a new cache loaded from the file resumes the sessions.
+			c, err = NewTLSSessionCache(file, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Len() == 0 {
+				t.Fatal("no session loaded from file")
+			}
+			if !dialTLS(t, l.Addr().String(), c.Target("t1")) {
+				t.Error("connection not resumed after reload")
+			}
+			// stop the pending save before the temporary dir is removed.
+			if err = c.Save(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTLSSessionCacheMissingFile(t *testing.T) {
+	c, err := NewTLSSessionCache(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("unexpected sessions: %d", c.Len())
+	}
+	file := filepath.Join(t.TempDir(), "invalid.json")
+	if err = os.WriteFile(file, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewTLSSessionCache(file, nil); err == nil {
+		t.Error("expected an error loading an invalid file")
+	}
+}
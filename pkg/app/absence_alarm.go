@@ -0,0 +1,153 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const maxAbsenceCheckInterval = time.Second
+
+// absenceMonitor tracks the last time a response was received
+// by each STREAM subscription of a target configured with an absence-alarm.
+// It is not safe for concurrent use, it is owned by the target's collector loop.
+type absenceMonitor struct {
+	target string
+	subs   map[string]*absenceState
+}
+
+type absenceState struct {
+	name      string
+	interval  time.Duration
+	eventName string
+	outputs   []string
+	lastSeen  time.Time
+	absent    bool
+}
+
+// newAbsenceMonitor returns nil if none of the target subscriptions
+// has an absence-alarm.
+func newAbsenceMonitor(t *target.Target, now time.Time) *absenceMonitor {
+	m := &absenceMonitor{
+		target: t.Config.Name,
+		subs:   make(map[string]*absenceState),
+	}
+	for name, sc := range t.Subscriptions {
+		if sc.AbsenceAlarm == nil || strings.ToUpper(sc.Mode) != "STREAM" {
+			continue
+		}
+		outs := sc.Outputs
+		if len(outs) == 0 {
+			outs = t.Config.Outputs
+		}
+		m.subs[name] = &absenceState{
+			name:      name,
+			interval:  sc.AbsenceAlarm.Interval,
+			eventName: sc.AbsenceAlarm.EventName,
+			outputs:   outs,
+			lastSeen:  now,
+		}
+		subscriptionUpdatesAbsent.WithLabelValues(m.target, name).Set(0)
+	}
+	if len(m.subs) == 0 {
+		return nil
+	}
+	return m
+}
+
+// checkInterval returns the period at which the subscriptions
+// should be checked.
+func (m *absenceMonitor) checkInterval() time.Duration {
+	ci := maxAbsenceCheckInterval
+	for _, st := range m.subs {
+		if i := st.interval / 10; i > 0 && i < ci {
+			ci = i
+		}
+	}
+	return ci
+}
+
+// seen records a response received by subscription name.
+// It returns the subscription state if it was absent.
+func (m *absenceMonitor) seen(name string, now time.Time) *absenceState {
+	st, ok := m.subs[name]
+	if !ok {
+		return nil
+	}
+	st.lastSeen = now
+	if !st.absent {
+		return nil
+	}
+	st.absent = false
+	subscriptionUpdatesAbsent.WithLabelValues(m.target, name).Set(0)
+	return st
+}
+
+// check returns the states of the subscriptions that did not receive
+// any response within their interval since the last check.
+func (m *absenceMonitor) check(now time.Time) []*absenceState {
+	var r []*absenceState
+	for name, st := range m.subs {
+		if st.absent || now.Sub(st.lastSeen) < st.interval {
+			continue
+		}
+		st.absent = true
+		subscriptionUpdatesAbsent.WithLabelValues(m.target, name).Set(1)
+		r = append(r, st)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].name < r[j].name
+	})
+	return r
+}
+
+// stop removes the target subscriptions metrics.
+func (m *absenceMonitor) stop() {
+	for name := range m.subs {
+		subscriptionUpdatesAbsent.DeleteLabelValues(m.target, name)
+	}
+}
+
+// event builds the event reporting the subscription absence state change.
+func (st *absenceState) event(target string, ts time.Time) *formatters.EventMsg {
+	absent := 0
+	if st.absent {
+		absent = 1
+	}
+	return &formatters.EventMsg{
+		Name:      st.eventName,
+		Timestamp: ts.UnixNano(),
+		Tags: map[string]string{
+			"source":            target,
+			"subscription-name": st.name,
+		},
+		Values: map[string]interface{}{
+			"absent":                absent,
+			"interval":              st.interval.String(),
+			"last-update-timestamp": st.lastSeen.UnixNano(),
+		},
+	}
+}
+
+func (a *App) exportAbsenceEvents(ctx context.Context, m *absenceMonitor, ts time.Time, states ...*absenceState) {
+	for _, st := range states {
+		if st.absent {
+			a.Logger.Printf("target %q: subscription %s: no update received for %s", m.target, st.name, ts.Sub(st.lastSeen).Round(time.Second))
+		} else {
+			a.Logger.Printf("target %q: subscription %s: updates resumed", m.target, st.name)
+		}
+		a.exportEvent(ctx, st.event(m.target, ts), st.outputs...)
+	}
+}
@@ -0,0 +1,117 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestAbsenceMonitor(t *testing.T) {
+	tg := &target.Target{
+		Config: &types.TargetConfig{Name: "router1", Outputs: []string{"out1"}},
+		Subscriptions: map[string]*types.SubscriptionConfig{
+			"sub1": {
+				Name: "sub1",
+				Mode: "stream",
+				AbsenceAlarm: &types.AbsenceAlarmConfig{
+					Interval:  time.Minute,
+					EventName: "subscription-absence",
+				},
+			},
+			"sub2": {
+				Name:    "sub2",
+				Mode:    "stream",
+				Outputs: []string{"out2"},
+				AbsenceAlarm: &types.AbsenceAlarmConfig{
+					Interval:  5 * time.Minute,
+					EventName: "sub2-absence",
+				},
+			},
+			"sub3": {Name: "sub3", Mode: "stream"},
+			"sub4": {
+				Name:         "sub4",
+				Mode:         "once",
+				AbsenceAlarm: &types.AbsenceAlarmConfig{Interval: time.Minute},
+			},
+		},
+	}
+	start := time.Unix(0, 0)
+	m := newAbsenceMonitor(tg, start)
+	if m == nil {
+		t.Fatal("expected an absence monitor")
+	}
+	defer m.stop()
+	if len(m.subs) != 2 {
+		t.Fatalf("expected 2 monitored subscriptions, got %d", len(m.subs))
+	}
+	if ci := m.checkInterval(); ci != time.Second {
+		t.Errorf("expected a check interval of 1s, got %s", ci)
+	}
+
+	// sub1 keeps receiving updates until 30s
+	if st := m.seen("sub1", start.Add(30*time.Second)); st != nil {
+		t.Errorf("unexpected state change on update: %+v", st)
+	}
+	if sts := m.check(start.Add(time.Minute)); len(sts) != 0 {
+		t.Errorf("expected no absent subscription, got %d", len(sts))
+	}
+	// sub1 absent
+	sts := m.check(start.Add(91 * time.Second))
+	if len(sts) != 1 || sts[0].name != "sub1" {
+		t.Fatalf("expected sub1 to be absent, got %+v", sts)
+	}
+	ev := sts[0].event(m.target, start.Add(91*time.Second))
+	if ev.Name != "subscription-absence" || ev.Values["absent"] != 1 ||
+		ev.Tags["source"] != "router1" || ev.Tags["subscription-name"] != "sub1" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if len(sts[0].outputs) != 1 || sts[0].outputs[0] != "out1" {
+		t.Errorf("expected the target outputs, got %v", sts[0].outputs)
+	}
+	// the alarm is not repeated
+	if sts := m.check(start.Add(2 * time.Minute)); len(sts) != 0 {
+		t.Errorf("expected no new absent subscription, got %+v", sts)
+	}
+	// sub1 resumes
+	st := m.seen("sub1", start.Add(3*time.Minute))
+	if st == nil {
+		t.Fatal("expected sub1 to be cleared")
+	}
+	if ev := st.event(m.target, start.Add(3*time.Minute)); ev.Values["absent"] != 0 {
+		t.Errorf("unexpected cleared event: %+v", ev)
+	}
+	// sub2 absent, sub1 absent again
+	sts = m.check(start.Add(5 * time.Minute))
+	if len(sts) != 2 || sts[0].name != "sub1" || sts[1].name != "sub2" {
+		t.Fatalf("expected sub1 and sub2 to be absent, got %+v", sts)
+	}
+	if ev := sts[1].event(m.target, start.Add(5*time.Minute)); ev.Name != "sub2-absence" || sts[1].outputs[0] != "out2" {
+		t.Errorf("unexpected sub2 event: %+v, outputs %v", ev, sts[1].outputs)
+	}
+	// unmonitored subscriptions are ignored
+	if st := m.seen("sub3", start.Add(5*time.Minute)); st != nil {
+		t.Errorf("unexpected state for sub3: %+v", st)
+	}
+}
+
+func TestNewAbsenceMonitorNone(t *testing.T) {
+	tg := &target.Target{
+		Config: &types.TargetConfig{Name: "router1"},
+		Subscriptions: map[string]*types.SubscriptionConfig{
+			"sub1": {Name: "sub1", Mode: "stream"},
+		},
+	}
+	if m := newAbsenceMonitor(tg, time.Now()); m != nil {
+		t.Errorf("expected no absence monitor, got %+v", m)
+	}
+}
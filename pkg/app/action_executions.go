@@ -0,0 +1,72 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/actions"
+)
+
+type executionDecisionRequest struct {
+	User string `json:"user,omitempty"`
+}
+
+func (a *App) actionRoutes(r *mux.Router) {
+	r.HandleFunc("/actions/executions", a.handleActionExecutionsGet).Methods(http.MethodGet)
+	r.HandleFunc("/actions/executions/{id}", a.handleActionExecutionsGet).Methods(http.MethodGet)
+	r.HandleFunc("/actions/executions/{id}/approve", a.handleActionExecutionDecision("approved", actions.Approve)).Methods(http.MethodPost)
+	r.HandleFunc("/actions/executions/{id}/reject", a.handleActionExecutionDecision("rejected", actions.Reject)).Methods(http.MethodPost)
+}
+
+func (a *App) handleActionExecutionsGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		a.handlerCommonGet(w, actions.AuditTrail(r.URL.Query().Get("status")))
+		return
+	}
+	ex, err := actions.GetExecution(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	a.handlerCommonGet(w, ex)
+}
+
+func (a *App) handleActionExecutionDecision(decision string, decide func(id, user string) (*actions.Execution, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := new(executionDecisionRequest)
+		if r.ContentLength != 0 {
+			err := json.NewDecoder(r.Body).Decode(req)
+			if err != nil {
+				adminError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if req.User == "" {
+			req.User, _, _ = r.BasicAuth()
+		}
+		ex, err := decide(mux.Vars(r)["id"], req.User)
+		switch {
+		case errors.Is(err, actions.ErrExecutionNotFound):
+			adminError(w, http.StatusNotFound, err)
+			return
+		case err != nil:
+			adminError(w, http.StatusConflict, err)
+			return
+		}
+		a.Logger.Printf("action execution %s of trigger %q %s by %q", ex.ID, ex.Trigger, decision, ex.User)
+		a.handlerCommonGet(w, ex)
+	}
+}
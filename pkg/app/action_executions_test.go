@@ -0,0 +1,71 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/actions"
+)
+
+func TestHandleActionExecutionDecision(t *testing.T) {
+	a := New()
+	ex := &actions.Execution{
+		Trigger: "app-test",
+		Status:  actions.ExecutionPending,
+		Created: time.Now(),
+	}
+	actions.Record(ex, func(ex *actions.Execution) { actions.Complete(ex, nil, nil) }, nil)
+
+	do := func(h http.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/actions/executions/"+id, strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+	approve := a.handleActionExecutionDecision("approved", actions.Approve)
+	if rec := do(approve, "unknown", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown execution", rec.Code)
+	}
+	rec := do(approve, ex.ID, `{"user":"alice"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	rex := new(actions.Execution)
+	if err := json.Unmarshal(rec.Body.Bytes(), rex); err != nil {
+		t.Fatal(err)
+	}
+	if rex.User != "alice" || rex.Status != actions.ExecutionRunning {
+		t.Errorf("unexpected execution %+v", rex)
+	}
+	reject := a.handleActionExecutionDecision("rejected", actions.Reject)
+	if rec := do(reject, ex.ID, ""); rec.Code != http.StatusConflict {
+		t.Errorf("unexpected status code %d for an approved execution", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	a.handleActionExecutionsGet(rec, httptest.NewRequest(http.MethodGet, "/api/v1/actions/executions?status=pending", nil))
+	var pending []*actions.Execution
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pending {
+		if p.ID == ex.ID {
+			t.Errorf("expected the approved execution not to be pending")
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	logLevelInfo  = "info"
+	logLevelDebug = "debug"
+
+	defaultProcessorBypassDuration = 5 * time.Minute
+)
+
+type logLevel struct {
+	Level string `json:"level"`
+}
+
+type cacheDumpRequest struct {
+	Target       string `json:"target,omitempty"`
+	Subscription string `json:"subscription,omitempty"`
+	Path         string `json:"path,omitempty"`
+	File         string `json:"file,omitempty"`
+}
+
+type cacheDumpResponse struct {
+	File          string `json:"file"`
+	Notifications int    `json:"notifications"`
+}
+
+type processorBypassRequest struct {
+	Duration string `json:"duration,omitempty"`
+}
+
+type processorBypass struct {
+	Until time.Time `json:"until"`
+}
+
+func (a *App) handleAdminLogLevelGet(w http.ResponseWriter, r *http.Request) {
+	level := logLevelInfo
+	if a.debugEnabled() {
+		level = logLevelDebug
+	}
+	a.handlerCommonGet(w, logLevel{Level: level})
+}
+
+// handleAdminLogLevelPut toggles the debug logs at runtime.
+func (a *App) handleAdminLogLevelPut(w http.ResponseWriter, r *http.Request) {
+	req := new(logLevel)
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		adminError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch req.Level {
+	case logLevelInfo:
+		a.debug.Store(false)
+	case logLevelDebug:
+		a.debug.Store(true)
+	default:
+		adminError(w, http.StatusBadRequest,
+			fmt.Errorf("unknown log level %q, must be one of %q or %q", req.Level, logLevelInfo, logLevelDebug))
+		return
+	}
+	a.Logger.Printf("log level set to %q", req.Level)
+	a.handlerCommonGet(w, req)
+}
+
+// handleAdminCacheDump returns the cached notifications of a target,
+// optionally filtered by subscription and path, or writes them
+// to a new file in the api-server dump-dir.
+func (a *App) handleAdminCacheDump(w http.ResponseWriter, r *http.Request) {
+	if a.c == nil {
+		adminError(w, http.StatusNotFound, errors.New("gNMI server cache is not enabled"))
+		return
+	}
+	req := new(cacheDumpRequest)
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		adminError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Target == "" {
+		adminError(w, http.StatusBadRequest, errors.New("missing target"))
+		return
+	}
+	var file string
+	if req.File != "" {
+		if a.Config.APIServer == nil || a.Config.APIServer.DumpDir == "" {
+			adminError(w, http.StatusForbidden, errors.New("cache dumps to files are disabled, api-server dump-dir is not set"))
+			return
+		}
+		// the file must stay within the dump directory
+		if !filepath.IsLocal(req.File) {
+			adminError(w, http.StatusBadRequest, fmt.Errorf("invalid file %q, must be a relative path without \"..\" elements", req.File))
+			return
+		}
+		file = filepath.Join(a.Config.APIServer.DumpDir, req.File)
+	}
+	if req.Subscription == "" {
+		req.Subscription = "*"
+	}
+	p := new(gnmi.Path)
+	if req.Path != "" {
+		p, err = path.ParsePath(req.Path)
+		if err != nil {
+			adminError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	tc, err := a.readCache(req.Subscription, req.Target, p)
+	if err != nil {
+		adminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if file == "" {
+		a.handlerCommonGet(w, tc)
+		return
+	}
+	count := 0
+	for _, ns := range tc {
+		count += len(ns)
+	}
+	// do not overwrite existing files
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			adminError(w, http.StatusConflict, err)
+			return
+		}
+		adminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(tc)
+	if err != nil {
+		adminError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.Logger.Printf("dumped %d cached notifications of target %q to %q", count, req.Target, file)
+	a.handlerCommonGet(w, cacheDumpResponse{File: file, Notifications: count})
+}
+
+func (a *App) handleAdminProcessorsBypassGet(w http.ResponseWriter, r *http.Request) {
+	bps := formatters.BypassedProcessors()
+	rs := make(map[string]processorBypass, len(bps))
+	for name, until := range bps {
+		rs[name] = processorBypass{Until: until}
+	}
+	a.handlerCommonGet(w, rs)
+}
+
+// handleAdminProcessorBypass makes a processor pass the events through unchanged
+// for the requested duration, in all the outputs and inputs using it.
+func (a *App) handleAdminProcessorBypass(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	a.configLock.RLock()
+	_, ok := a.Config.Processors[name]
+	a.configLock.RUnlock()
+	if !ok {
+		adminError(w, http.StatusNotFound, fmt.Errorf("processor %q not found", name))
+		return
+	}
+	req := new(processorBypassRequest)
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			adminError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	d := defaultProcessorBypassDuration
+	if req.Duration != "" {
+		var err error
+		d, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			adminError(w, http.StatusBadRequest, err)
+			return
+		}
+		if d <= 0 {
+			adminError(w, http.StatusBadRequest, errors.New("bypass duration must be positive"))
+			return
+		}
+	}
+	until := formatters.BypassProcessor(name, d)
+	a.Logger.Printf("processor %q bypassed until %s", name, until)
+	a.handlerCommonGet(w, processorBypass{Until: until})
+}
+
+func (a *App) handleAdminProcessorRestore(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if !formatters.RestoreProcessor(name) {
+		adminError(w, http.StatusNotFound, fmt.Errorf("processor %q is not bypassed", name))
+		return
+	}
+	a.Logger.Printf("processor %q restored", name)
+}
+
+func adminError(w http.ResponseWriter, code int, err error) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+}
@@ -0,0 +1,160 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/cache"
+	"github.com/openconfig/gnmic/pkg/config"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestHandleAdminLogLevel(t *testing.T) {
+	a := New()
+	put := func(body string) int {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/log-level", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		a.handleAdminLogLevelPut(rec, req)
+		return rec.Code
+	}
+	if code := put(`{"level":"debug"}`); code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	if !a.debugEnabled() {
+		t.Errorf("expected debug to be enabled")
+	}
+	rec := httptest.NewRecorder()
+	a.handleAdminLogLevelGet(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/log-level", nil))
+	ll := new(logLevel)
+	if err := json.Unmarshal(rec.Body.Bytes(), ll); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if ll.Level != logLevelDebug {
+		t.Errorf("got level %q, want %q", ll.Level, logLevelDebug)
+	}
+	if code := put(`{"level":"info"}`); code != http.StatusOK || a.debugEnabled() {
+		t.Errorf("expected debug to be disabled, status code %d", code)
+	}
+	if code := put(`{"level":"trace"}`); code != http.StatusBadRequest {
+		t.Errorf("unexpected status code %d for an unknown level", code)
+	}
+}
+
+func TestHandleAdminCacheDump(t *testing.T) {
+	a := New()
+	c, err := cache.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	a.c = c
+	a.c.Write(context.Background(), "sub1", &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: time.Now().UnixNano(),
+				Prefix:    &gnmi.Path{Target: "r1"},
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "r1"}},
+					},
+				},
+			},
+		},
+	})
+	dump := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/dump", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		a.handleAdminCacheDump(rec, req)
+		return rec
+	}
+	// no file, the dump is returned in the response
+	rec := dump(`{"target":"r1"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	tc := make(targetCache)
+	if err := json.Unmarshal(rec.Body.Bytes(), &tc); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(tc["sub1"]) != 1 {
+		t.Errorf("unexpected dump %s", rec.Body)
+	}
+	// dumps to files require a dump directory
+	if rec := dump(`{"target":"r1","file":"r1.json"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("unexpected status code %d without a dump-dir", rec.Code)
+	}
+	dir := t.TempDir()
+	a.Config.APIServer = &config.APIServer{DumpDir: dir}
+	for _, f := range []string{"/tmp/r1.json", "../r1.json", "a/../../r1.json"} {
+		if rec := dump(`{"target":"r1","file":"` + f + `"}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("unexpected status code %d for file %q", rec.Code, f)
+		}
+	}
+	rec = dump(`{"target":"r1","file":"r1.json"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	rsp := new(cacheDumpResponse)
+	if err := json.Unmarshal(rec.Body.Bytes(), rsp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rsp.File != filepath.Join(dir, "r1.json") || rsp.Notifications != 1 {
+		t.Errorf("unexpected response %+v", rsp)
+	}
+	if _, err := os.Stat(rsp.File); err != nil {
+		t.Errorf("dump file not written: %v", err)
+	}
+	if rec := dump(`{"target":"r1","file":"r1.json"}`); rec.Code != http.StatusConflict {
+		t.Errorf("unexpected status code %d for an existing file", rec.Code)
+	}
+}
+
+func TestHandleAdminProcessorBypass(t *testing.T) {
+	a := New()
+	a.Config.Processors = map[string]map[string]interface{}{
+		"proc1": {"event-drop": map[string]interface{}{}},
+	}
+	do := func(h http.HandlerFunc, method, name, body string) int {
+		req := httptest.NewRequest(method, "/api/v1/admin/processors/"+name+"/bypass", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"name": name})
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec.Code
+	}
+	if code := do(a.handleAdminProcessorBypass, http.MethodPost, "unknown", ""); code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown processor", code)
+	}
+	if code := do(a.handleAdminProcessorBypass, http.MethodPost, "proc1", `{"duration":"bad"}`); code != http.StatusBadRequest {
+		t.Errorf("unexpected status code %d for a bad duration", code)
+	}
+	if code := do(a.handleAdminProcessorBypass, http.MethodPost, "proc1", `{"duration":"1m"}`); code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	if _, ok := formatters.BypassedProcessors()["proc1"]; !ok {
+		t.Errorf("expected proc1 to be bypassed")
+	}
+	if code := do(a.handleAdminProcessorRestore, http.MethodDelete, "proc1", ""); code != http.StatusOK {
+		t.Errorf("unexpected status code %d", code)
+	}
+	if code := do(a.handleAdminProcessorRestore, http.MethodDelete, "proc1", ""); code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for a processor not bypassed", code)
+	}
+}
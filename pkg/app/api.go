@@ -45,15 +45,36 @@ func (a *App) newAPIServer() (*http.Server, error) {
 	}
 
 	if a.Config.APIServer.EnableMetrics {
-		a.router.Handle("/metrics", promhttp.HandlerFor(a.reg, promhttp.HandlerOpts{}))
+		a.router.Handle("/metrics", promhttp.HandlerFor(a.instanceLabelsGatherer(a.reg), promhttp.HandlerOpts{}))
 		a.reg.MustRegister(collectors.NewGoCollector())
 		a.reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 		a.reg.MustRegister(subscribeResponseReceivedCounter)
+		a.reg.MustRegister(subscriptionUpdatesAbsent)
+		a.reg.MustRegister(neighborhoodSubscriptions)
+		a.reg.MustRegister(subscriptionSynced)
+		a.reg.MustRegister(subscriptionSyncDuration)
+		a.reg.MustRegister(targetBudgetExceededMsgs)
+		a.reg.MustRegister(subscribeResponsesShedCounter)
+		a.reg.MustRegister(subscribeResponseViolations)
+		a.reg.MustRegister(targetConnState)
+		a.reg.MustRegister(targetConnStateTransitions)
+		a.reg.MustRegister(targetDialDuration)
+		a.reg.MustRegister(targetDialAttempts)
+		a.reg.MustRegister(targetTLSHandshakeErrors)
+		a.reg.MustRegister(gnmiServerSlowConsumersEvicted)
+		a.reg.MustRegister(gnmiServerSendRateLimitWait)
+		a.reg.MustRegister(gnmiServerACLDenied)
 		go a.startClusterMetrics()
+		go a.startOutputsMetrics()
+	}
+	var handler http.Handler = a.router
+	// mutating requests are served by the admin listener only
+	if a.Config.APIServer.Admin != nil {
+		handler = readOnlyHandler(a.router)
 	}
 	s := &http.Server{
 		Addr:         a.Config.APIServer.Address,
-		Handler:      a.router,
+		Handler:      handler,
 		ReadTimeout:  a.Config.APIServer.Timeout / 2,
 		WriteTimeout: a.Config.APIServer.Timeout / 2,
 	}
@@ -65,6 +86,49 @@ func (a *App) newAPIServer() (*http.Server, error) {
 	return s, nil
 }
 
+// newAPIAdminServer creates the API server admin listener, serving all the API endpoints.
+// It must be called after newAPIServer, which sets the routes.
+func (a *App) newAPIAdminServer() (*http.Server, error) {
+	admin := a.Config.APIServer.Admin
+	s := &http.Server{
+		Addr:         admin.Address,
+		Handler:      a.router,
+		ReadTimeout:  a.Config.APIServer.Timeout / 2,
+		WriteTimeout: a.Config.APIServer.Timeout / 2,
+	}
+	if admin.TLS != nil {
+		var err error
+		s.TLSConfig, err = utils.NewTLSConfig(
+			admin.TLS.CaFile,
+			admin.TLS.CertFile,
+			admin.TLS.KeyFile,
+			admin.TLS.ClientAuth,
+			false, // skip-verify
+			true,  // genSelfSigned
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// readOnlyHandler rejects the requests which method may change the gNMIc state.
+func readOnlyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			h.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(APIErrors{Errors: []string{
+				fmt.Sprintf("method %s is only allowed on the api-server admin address", r.Method),
+			}})
+		}
+	})
+}
+
 type APIErrors struct {
 	Errors []string `json:"errors,omitempty"`
 }
@@ -84,6 +148,7 @@ func (a *App) handleConfigTargetsGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if t, ok := a.Config.Targets[id]; ok {
+		w.Header().Set("ETag", configETag(t))
 		err = json.NewEncoder(w).Encode(t)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -153,6 +218,18 @@ func (a *App) handleConfigTargetsSubscriptions(w http.ResponseWriter, r *http.Re
 func (a *App) handleConfigTargetsDelete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
+	if r.Header.Get("If-Match") != "" {
+		a.configLock.RLock()
+		var etag string
+		if tc, ok := a.Config.Targets[id]; ok {
+			etag = configETag(tc)
+		}
+		a.configLock.RUnlock()
+		if !configPreconditionsMet(r, etag) {
+			writePreconditionFailed(w, "target", id)
+			return
+		}
+	}
 	err := a.DeleteTarget(r.Context(), id)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
@@ -244,6 +321,22 @@ func (a *App) handleTargetsDelete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (a *App) handleOutputsGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	oh := a.outputsHealth()
+	if id == "" {
+		a.handlerCommonGet(w, oh)
+		return
+	}
+	if st, ok := oh[id]; ok {
+		a.handlerCommonGet(w, st)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("output %q not found", id)}})
+}
+
 type clusteringResponse struct {
 	ClusterName           string          `json:"name,omitempty"`
 	NumberOfLockedTargets int             `json:"number-of-locked-targets"`
@@ -0,0 +1,123 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+type testOutput struct {
+	healthy bool
+}
+
+func (o *testOutput) Init(context.Context, string, map[string]interface{}, ...outputs.Option) error {
+	return nil
+}
+func (o *testOutput) Write(context.Context, proto.Message, outputs.Meta) {}
+func (o *testOutput) WriteEvent(context.Context, *formatters.EventMsg)   {}
+func (o *testOutput) Close() error                                       { return nil }
+func (o *testOutput) RegisterMetrics(*prometheus.Registry)               {}
+func (o *testOutput) String() string                                     { return "" }
+func (o *testOutput) SetLogger(*log.Logger)                              {}
+func (o *testOutput) SetName(string)                                     {}
+func (o *testOutput) SetClusterName(string)                              {}
+func (o *testOutput) SetTargetsConfig(map[string]*types.TargetConfig)    {}
+func (o *testOutput) Healthy() bool                                      { return o.healthy }
+func (o *testOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func TestHandleOutputsGet(t *testing.T) {
+	a := New()
+	a.Config.Outputs["out1"] = map[string]interface{}{"type": "kafka"}
+	a.Config.Outputs["out2"] = map[string]interface{}{"type": "failover"}
+	o1 := &testOutput{healthy: true}
+	o2 := &testOutput{healthy: false}
+	a.Outputs["out1"] = o1
+	a.Outputs["out2"] = o2
+
+	get := func(id string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/outputs", nil)
+		if id != "" {
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+		}
+		rec := httptest.NewRecorder()
+		a.handleOutputsGet(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	code, body := get("")
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	all := make(map[string]*outputHealth)
+	if err := json.Unmarshal(body, &all); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]*outputHealth{
+		"out1": {Type: "kafka", Healthy: true},
+		"out2": {Type: "failover", Healthy: false},
+	}
+	if !cmp.Equal(all, want) {
+		t.Errorf("unexpected outputs health: %s", cmp.Diff(want, all))
+	}
+
+	// out2 recovers
+	o2.healthy = true
+	code, body = get("out2")
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	one := new(outputHealth)
+	if err := json.Unmarshal(body, one); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !one.Healthy || one.Type != "failover" {
+		t.Errorf("unexpected output health: %+v", one)
+	}
+
+	code, _ = get("out3")
+	if code != http.StatusNotFound {
+		t.Errorf("expected status code %d, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestReadOnlyHandler(t *testing.T) {
+	h := readOnlyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for method, code := range map[string]int{
+		http.MethodGet:    http.StatusOK,
+		http.MethodHead:   http.StatusOK,
+		http.MethodPost:   http.StatusForbidden,
+		http.MethodPatch:  http.StatusForbidden,
+		http.MethodDelete: http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(method, "/api/v1/config/targets", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != code {
+			t.Errorf("%s: got status %d, want %d", method, rec.Code, code)
+		}
+	}
+}
@@ -14,10 +14,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -37,10 +41,14 @@ import (
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/openconfig/gnmic/pkg/api/recorder"
+	"github.com/openconfig/gnmic/pkg/api/server"
 	"github.com/openconfig/gnmic/pkg/api/target"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
 	"github.com/openconfig/gnmic/pkg/cache"
 	"github.com/openconfig/gnmic/pkg/config"
+	"github.com/openconfig/gnmic/pkg/faults"
 	"github.com/openconfig/gnmic/pkg/formatters"
 	"github.com/openconfig/gnmic/pkg/formatters/plugin_manager"
 	"github.com/openconfig/gnmic/pkg/inputs"
@@ -74,6 +82,23 @@ type App struct {
 	targetsChan   chan *target.Target
 	activeTargets map[string]struct{}
 	targetsLockFn map[string]context.CancelFunc
+	// learned targets hostname, used to rewrite the notifications prefix target
+	targetsHostname map[string]string
+	// targets with a hostname to be learned again after a subscription error
+	targetsHostnameRefresh map[string]struct{}
+	targetRewriteTpl       *template.Template
+	// cached targets capabilities, if a capabilities-cache is configured
+	targetsCapabilities map[string]*targetCapabilities
+	// detected targets platform, for targets with auto-detect enabled
+	targetsPlatform map[string]*targetPlatform
+	// targets POLL subscriptions with a poll-interval or poll-triggers
+	targetsPolls map[string]*targetPolls
+	// targets subscriptions initial sync states
+	targetsSync map[string]*targetSync
+	// targets received messages statistics
+	targetsStats map[string]*targetStatistics
+	// faults injected in the collected messages, nil if not configured
+	faultInjector *faults.Injector
 	rootDesc      desc.Descriptor
 	// end collector
 	router *mux.Router
@@ -81,11 +106,16 @@ type App struct {
 	// api
 	apiServices map[string]*lockers.Service
 	isLeader    bool
+	// asynchronous API jobs
+	jobsLock *sync.RWMutex
+	jobs     map[string]*job
 	// prometheus registry
 	reg *prometheus.Registry
 	//
 	Logger *log.Logger
 	out    io.Writer
+	// debug logs, set by --debug and toggled by the admin API
+	debug atomic.Bool
 	// prompt mode
 	PromptMode    bool
 	PromptHistory []string
@@ -96,9 +126,41 @@ type App struct {
 	wg        *sync.WaitGroup
 	printLock *sync.Mutex
 	errCh     chan error
+	// number of updates printed per target,
+	// reported in the targets results summary.
+	// protected by printLock.
+	respCounts map[string]int
+	// records the gNMI messages exchanged with the targets,
+	// nil if --record is not set.
+	recorder *recorder.Recorder
+	// TLS sessions cache shared by the targets
+	// with tls-session-resumption enabled.
+	tlsSessionCache *utils.TLSSessionCache
+	// paces the targets gNMI client creations,
+	// nil if --dial-rate is not set.
+	dialPacer *dialPacer
+	// limits the number of targets the gnmi-server
+	// sends unary RPCs to concurrently, nil if unlimited.
+	serverTargetsSem chan struct{}
+	// gnmi-server per client subscriptions quotas,
+	// nil if not configured.
+	subscriptionQuotas *server.SubscriptionQuotas
+	// gnmi-server per client access rules,
+	// nil if not configured.
+	serverACL *serverACL
+	// gnmi-server client certificate to targets mapping
+	clientCertTargets *clientCertTargets
+	// gnmi-server per subscription send rates
+	subscriptionSendRates []*subscriptionSendRate
+	// gnmi-server per client send rate limiters,
+	// nil if max-send-rate is not configured.
+	clientRateLimiters *clientRateLimiters
 	// gNMI cache, used if a gnmi-server is configured
 	// with subscribe or proxy commands.
 	c cache.Cache
+	// collector extension added to the gnmi-server SubscribeResponses,
+	// nil if not enabled.
+	collectorExt *outputs.CollectorExtension
 	// tunnel server
 	// gRPC server where the tunnel service will be registered
 	grpcTunnelSrv *grpc.Server
@@ -121,16 +183,25 @@ func New() *App {
 		Config:     config.New(),
 		reg:        prometheus.NewRegistry(),
 		//
-		operLock:      new(sync.RWMutex),
-		Targets:       make(map[string]*target.Target),
-		Outputs:       make(map[string]outputs.Output),
-		Inputs:        make(map[string]inputs.Input),
-		targetsChan:   make(chan *target.Target),
-		activeTargets: make(map[string]struct{}),
-		targetsLockFn: make(map[string]context.CancelFunc),
+		operLock:               new(sync.RWMutex),
+		Targets:                make(map[string]*target.Target),
+		Outputs:                make(map[string]outputs.Output),
+		Inputs:                 make(map[string]inputs.Input),
+		targetsChan:            make(chan *target.Target),
+		activeTargets:          make(map[string]struct{}),
+		targetsLockFn:          make(map[string]context.CancelFunc),
+		targetsHostname:        make(map[string]string),
+		targetsHostnameRefresh: make(map[string]struct{}),
+		targetsCapabilities:    make(map[string]*targetCapabilities),
+		targetsPlatform:        make(map[string]*targetPlatform),
+		targetsPolls:           make(map[string]*targetPolls),
+		targetsSync:            make(map[string]*targetSync),
+		targetsStats:           make(map[string]*targetStatistics),
 		//
 		router:        mux.NewRouter(),
 		apiServices:   make(map[string]*lockers.Service),
+		jobsLock:      new(sync.RWMutex),
+		jobs:          make(map[string]*job),
 		Logger:        log.New(io.Discard, "[gnmic] ", log.LstdFlags|log.Lmsgprefix),
 		out:           os.Stdout,
 		PromptHistory: make([]string, 0, 128),
@@ -161,6 +232,7 @@ func (a *App) InitGlobalFlags() {
 	a.RootCmd.ResetFlags()
 
 	a.RootCmd.PersistentFlags().StringVar(&a.Config.CfgFile, "config", "", "main config file")
+	a.RootCmd.PersistentFlags().StringArrayVar(&a.Config.CfgOverlays, "config-overlay", []string{}, "config file merged on top of the main config file, can be repeated")
 	a.RootCmd.PersistentFlags().StringSliceVarP(&a.Config.GlobalFlags.Address, "address", "a", []string{}, "comma separated gnmi targets addresses")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.Username, "username", "u", "", "username")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.Password, "password", "p", "", "password")
@@ -188,6 +260,12 @@ func (a *App) InitGlobalFlags() {
 	a.RootCmd.PersistentFlags().BoolVarP(&a.Config.GlobalFlags.LogTLSSecret, "log-tls-secret", "", false, "enable logging of a TLS pre-master secret to a file")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.TLSServerName, "tls-server-name",
 		"", "", "sets the server name to be used when verifying the hostname on the returned certificates unless --skip-verify is set")
+	a.RootCmd.PersistentFlags().BoolVarP(&a.Config.GlobalFlags.TLSSessionResumption, "tls-session-resumption", "", false, "cache the targets TLS sessions and resume them when reconnecting")
+	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.TLSSessionCacheFile, "tls-session-cache-file", "", "", "file the TLS sessions cache is persisted to, allows resuming the sessions after a restart")
+
+	a.RootCmd.PersistentFlags().Float64VarP(&a.Config.GlobalFlags.DialRate, "dial-rate", "", 0, "maximum number of new target connections per second, 0 means no limit")
+	a.RootCmd.PersistentFlags().IntVarP(&a.Config.GlobalFlags.DialBurst, "dial-burst", "", 1, "number of target connections allowed to be started at once when --dial-rate is set")
+	a.RootCmd.PersistentFlags().DurationVarP(&a.Config.GlobalFlags.DialJitter, "dial-jitter", "", 0, "maximum random delay added to each target connection when --dial-rate is set")
 
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.ClusterName, "cluster-name", "", defaultClusterName, "cluster name the gnmic instance belongs to, this is used for target loadsharing via a locker")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.InstanceName, "instance-name", "", "", "gnmic instance name")
@@ -195,6 +273,12 @@ func (a *App) InitGlobalFlags() {
 	a.RootCmd.PersistentFlags().StringArrayVarP(&a.Config.GlobalFlags.ProtoFile, "proto-file", "", nil, "proto file(s) name(s)")
 	a.RootCmd.PersistentFlags().StringArrayVarP(&a.Config.GlobalFlags.ProtoDir, "proto-dir", "", nil, "directory to look for proto files specified with --proto-file")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.TargetsFile, "targets-file", "", "", "path to file with targets configuration")
+	a.RootCmd.PersistentFlags().StringSliceVarP(&a.Config.GlobalFlags.Group, "group", "", []string{}, "comma separated target groups names, the request is sent to all the group members")
+	a.RootCmd.PersistentFlags().UintVarP(&a.Config.GlobalFlags.Concurrency, "concurrency", "", 0, "maximum number of targets handled concurrently, 0 means no limit")
+	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.ExitPolicy, "exit-policy", "", exitPolicyFailIfAny, fmt.Sprintf("exit code policy when a request is sent to multiple targets, one of %q", exitPolicies))
+	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.ErrorReport, "error-report", "", "", "write a JSON report of the per target results to this file, stdout or stderr")
+	a.RootCmd.PersistentFlags().BoolVarP(&a.Config.GlobalFlags.Progress, "progress", "", false, "print a progress bar to stderr when a request is sent to multiple targets")
+	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.Record, "record", "", "", "record the gNMI messages exchanged with the targets to this file, decode it with 'gnmic decode'")
 	a.RootCmd.PersistentFlags().BoolVarP(&a.Config.GlobalFlags.Gzip, "gzip", "", false, "enable gzip compression on gRPC connections")
 	a.RootCmd.PersistentFlags().StringVarP(&a.Config.GlobalFlags.Token, "token", "", "", "token value, used for gRPC token based authentication")
 
@@ -225,15 +309,44 @@ func (a *App) PreRunE(cmd *cobra.Command, args []string) error {
 	a.Config.Address = config.ParseAddressField(a.Config.Address)
 	a.Logger.Printf("version=%s, commit=%s, date=%s, gitURL=%s, docs=https://gnmic.openconfig.net", version, commit, date, gitURL)
 
+	a.debug.Store(a.Config.Debug)
 	if a.Config.Debug {
 		grpclog.SetLogger(a.Logger) //lint:ignore SA1019 see https://github.com/karimra/gnmic/issues/59
 	}
 	a.Logger.Printf("using config file %q", a.Config.FileConfig.ConfigFileUsed())
 	a.logConfigKVs()
-	return a.validateGlobals()
+	err = a.validateGlobals()
+	if err != nil {
+		return err
+	}
+	a.tlsSessionCache, err = utils.NewTLSSessionCache(a.Config.TLSSessionCacheFile, func(err error) {
+		a.Logger.Printf("failed to save TLS sessions cache: %v", err)
+	})
+	if err != nil {
+		return err
+	}
+	a.dialPacer = newDialPacer(a.Config.DialRate, a.Config.DialBurst, a.Config.DialJitter)
+	if a.Config.Record != "" {
+		a.recorder, err = recorder.NewFile(a.Config.Record)
+		if err != nil {
+			return fmt.Errorf("failed to create gNMI messages recorder: %v", err)
+		}
+		a.Logger.Printf("recording gNMI messages to %q", a.Config.Record)
+	}
+	return nil
+}
+
+// debugEnabled reports whether the debug logs are enabled.
+func (a *App) debugEnabled() bool {
+	return a.debug.Load()
 }
 
 func (a *App) validateGlobals() error {
+	switch a.Config.ExitPolicy {
+	case "", exitPolicyFailIfAny, exitPolicyFailIfAll, exitPolicyFailFast:
+	default:
+		return fmt.Errorf("unknown --exit-policy %q, must be one of %q", a.Config.ExitPolicy, exitPolicies)
+	}
 	if a.Config.Insecure {
 		if a.Config.SkipVerify {
 			return errors.New("flags --insecure and --skip-verify are mutually exclusive")
@@ -257,11 +370,17 @@ func (a *App) validateGlobals() error {
 			return errors.New("flags --insecure and --tls-min-version are mutually exclusive")
 		}
 	}
+	if a.Config.DialRate < 0 {
+		return fmt.Errorf("invalid --dial-rate %v, must be a positive number", a.Config.DialRate)
+	}
+	if a.Config.DialJitter < 0 {
+		return fmt.Errorf("invalid --dial-jitter %s, must be a positive duration", a.Config.DialJitter)
+	}
 	return nil
 }
 
 func (a *App) logConfigKVs() {
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		keys := a.Config.FileConfig.AllKeys()
 		sort.Strings(keys)
 
@@ -283,6 +402,15 @@ func (a *App) logConfigKVs() {
 func (a *App) PrintMsg(address string, msgName string, msg proto.Message) error {
 	a.printLock.Lock()
 	defer a.printLock.Unlock()
+	if n, ok := responseCount(msg); ok {
+		if a.respCounts == nil {
+			a.respCounts = make(map[string]int)
+		}
+		a.respCounts[address] += n
+	}
+	if a.Config.Format == formatSummary {
+		return nil
+	}
 	if a.Config.PrintRequest {
 		fmt.Fprint(os.Stderr, msgName)
 		fmt.Fprintln(os.Stderr, "")
@@ -332,6 +460,12 @@ func (a *App) createCollectorDialOpts() []grpc.DialOption {
 	if a.Config.Gzip {
 		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
 	}
+	if a.recorder != nil {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(a.recorder.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(a.recorder.StreamClientInterceptor()),
+		)
+	}
 	if a.Config.APIServer != nil && a.Config.APIServer.EnableMetrics && a.reg != nil {
 		grpcClientMetrics := grpc_prometheus.NewClientMetrics()
 		opts = append(opts,
@@ -362,6 +496,12 @@ func (a *App) loadTargets(e fsnotify.Event) {
 	defer a.sem.Release(1)
 	switch e.Op {
 	case fsnotify.Write, fsnotify.Create:
+		// the reloaded main config file does not include the other files
+		err = a.Config.MergeIncludes(ctx)
+		if err != nil {
+			a.Logger.Printf("failed to merge included config files: %v", err)
+			return
+		}
 		newTargets, err := a.Config.GetTargets()
 		if err != nil && !errors.Is(err, config.ErrNoTargetsFound) {
 			a.Logger.Printf("failed getting targets from new config: %v", err)
@@ -372,7 +512,7 @@ func (a *App) loadTargets(e fsnotify.Event) {
 			// delete targets
 			for n := range currentTargets {
 				if _, ok := newTargets[n]; !ok {
-					if a.Config.Debug {
+					if a.debugEnabled() {
 						a.Logger.Printf("target %q deleted from config", n)
 					}
 					err = a.DeleteTarget(a.ctx, n)
@@ -384,7 +524,7 @@ func (a *App) loadTargets(e fsnotify.Event) {
 			// add targets
 			for n, tc := range newTargets {
 				if _, ok := currentTargets[n]; !ok {
-					if a.Config.Debug {
+					if a.debugEnabled() {
 						a.Logger.Printf("target %q added to config", n)
 					}
 					a.AddTargetConfig(tc)
@@ -437,22 +577,33 @@ func (a *App) startAPIServer() {
 		a.Logger.Printf("failed to create a new API server: %v", err)
 		return
 	}
-	go func() {
-		var err error
-		if s.TLSConfig != nil {
-			err = s.ListenAndServeTLS("", "")
-			if err != nil {
-				a.Logger.Printf("API server err: %v", err)
-				return
-			}
-		} else {
-			err = s.ListenAndServe()
-			if err != nil {
-				a.Logger.Printf("API server err: %v", err)
-				return
-			}
-		}
-	}()
+	go a.serveAPI("API server", s, a.Config.APIServer.IPFilter)
+	if a.Config.APIServer.Admin == nil {
+		return
+	}
+	as, err := a.newAPIAdminServer()
+	if err != nil {
+		a.Logger.Printf("failed to create the API admin server: %v", err)
+		return
+	}
+	go a.serveAPI("API admin server", as, a.Config.APIServer.Admin.IPFilter)
+}
+
+func (a *App) serveAPI(name string, s *http.Server, ipf *types.IPFilter) {
+	l, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		a.Logger.Printf("%s err: %v", name, err)
+		return
+	}
+	l = server.NewIPFilterListener(l, ipf, a.Logger)
+	if s.TLSConfig != nil {
+		err = s.ServeTLS(l, "", "")
+	} else {
+		err = s.Serve(l)
+	}
+	if err != nil {
+		a.Logger.Printf("%s err: %v", name, err)
+	}
 }
 
 func (a *App) LoadProtoFiles() (desc.Descriptor, error) {
@@ -0,0 +1,309 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/grpctunnel/tunnel"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	gfile "github.com/openconfig/gnmic/pkg/file"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+const (
+	intentStatePresent = "present"
+	intentStateAbsent  = "absent"
+
+	intentVarsFileSuffix = "_vars"
+)
+
+// intent is the desired configuration of a target,
+// as read from the apply command intent file.
+type intent struct {
+	// encoding of the Set requests values, json or json_ietf.
+	// defaults to the global --encoding flag.
+	Encoding  string            `yaml:"encoding,omitempty"`
+	Resources []*intentResource `yaml:"resources,omitempty"`
+}
+
+// intentResource is the desired state of the configuration
+// found under a path.
+type intentResource struct {
+	Path string `yaml:"path,omitempty"`
+	// present or absent, defaults to present.
+	State string `yaml:"state,omitempty"`
+	// if true, the configuration under path is replaced by value,
+	// otherwise value is merged leaf by leaf.
+	Replace bool        `yaml:"replace,omitempty"`
+	Value   interface{} `yaml:"value,omitempty"`
+}
+
+type intentTemplateInput struct {
+	TargetName string
+	Vars       map[string]interface{}
+}
+
+func (a *App) ApplyPreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	if a.Config.LocalFlags.ApplyIntentFile == "" {
+		return errors.New("missing intent file, set it with --intent-file")
+	}
+	if a.Config.Format == formatEvent {
+		return fmt.Errorf("format event not supported for Set RPC")
+	}
+	if a.Config.LocalFlags.ApplyReconcileInterval < 0 {
+		return errors.New("reconcile-interval cannot be negative")
+	}
+	if err := validateReconcileAction(a.Config.LocalFlags.ApplyReconcileAction); err != nil {
+		return err
+	}
+	if a.Config.LocalFlags.ApplyReconcileInterval > 0 && a.Config.LocalFlags.ApplyDryRun {
+		return errors.New("flags --dry-run and --reconcile-interval are mutually exclusive")
+	}
+	if len(a.Config.GlobalFlags.File) > 0 {
+		err := a.yangFilesPreProcessing()
+		if err != nil {
+			return err
+		}
+		err = a.generateYangSchema(a.Config.GlobalFlags.File, a.Config.GlobalFlags.Exclude)
+		if err != nil {
+			return fmt.Errorf("failed loading the YANG schema: %v", err)
+		}
+	}
+	a.createCollectorDialOpts()
+	return a.initTunnelServer(tunnel.ServerConfig{
+		AddTargetHandler:    a.tunServerAddTargetHandler,
+		DeleteTargetHandler: a.tunServerDeleteTargetHandler,
+		RegisterHandler:     a.tunServerRegisterHandler,
+		Handler:             a.tunServerHandler,
+	})
+}
+
+func (a *App) ApplyRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitApplyFlags(cmd)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	targetsConfig, err := a.GetTargets()
+	if err != nil {
+		return fmt.Errorf("failed getting targets config: %v", err)
+	}
+	if !a.PromptMode {
+		for _, tc := range targetsConfig {
+			a.AddTargetConfig(tc)
+		}
+	}
+	tpl, vars, err := a.readIntentFile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed reading intent file: %v", err)
+	}
+	if a.Config.LocalFlags.ApplyReconcileInterval > 0 {
+		return a.reconcile(tpl, vars)
+	}
+	numTargets := len(a.Config.Targets)
+	a.errCh = make(chan error, numTargets*2)
+	rs := a.runOnTargets(ctx, 0, 0, func(ctx context.Context, tc *types.TargetConfig) error {
+		req, err := a.intentSetRequest(tpl, vars, tc.Name)
+		if err != nil {
+			a.logError(fmt.Errorf("target %q: failed to create set request: %v", tc.Name, err))
+			return err
+		}
+		return a.applySetRequest(ctx, tc, req)
+	})
+	return a.checkTargetsErrors(rs)
+}
+
+func (a *App) applySetRequest(ctx context.Context, tc *types.TargetConfig, req *gnmi.SetRequest) error {
+	if a.Config.PrintRequest || a.Config.LocalFlags.ApplyDryRun {
+		err := a.PrintMsg(tc.Name, "Set Request:", req)
+		if err != nil {
+			a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
+		}
+	}
+	if a.Config.LocalFlags.ApplyDryRun {
+		return nil
+	}
+	response, err := a.ClientSet(ctx, tc, req)
+	if err != nil {
+		a.logError(fmt.Errorf("target %q set request failed: %v", tc.Name, err))
+		return err
+	}
+	err = a.PrintMsg(tc.Name, "Set Response:", response)
+	if err != nil {
+		a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
+	}
+	return err
+}
+
+// readIntentFile reads the intent file template and its variables file.
+// If not set with --vars, the variables are read from the file named
+// after the intent file with a `_vars` suffix, if it exists.
+func (a *App) readIntentFile(ctx context.Context) (*template.Template, map[string]interface{}, error) {
+	name := a.Config.LocalFlags.ApplyIntentFile
+	b, err := gfile.ReadFile(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	tpl, err := gtemplate.CreateTemplate("intent", string(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	varsFile := a.Config.LocalFlags.ApplyVars
+	if varsFile == "" {
+		ext := filepath.Ext(name)
+		varsFile = strings.TrimSuffix(name, ext) + intentVarsFileSuffix + ext
+		if _, err := os.Stat(varsFile); err != nil {
+			return tpl, nil, nil
+		}
+	}
+	b, err = gfile.ReadFile(ctx, varsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var v interface{}
+	err = yaml.Unmarshal(b, &v)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode variables file %q: %v", varsFile, err)
+	}
+	switch v := utils.Convert(v).(type) {
+	case nil:
+		return tpl, nil, nil
+	case map[string]interface{}:
+		return tpl, v, nil
+	default:
+		return nil, nil, fmt.Errorf("unexpected variables file %q format", varsFile)
+	}
+}
+
+// intentSetRequest renders the intent template for targetName
+// and maps the result to a SetRequest.
+func (a *App) intentSetRequest(tpl *template.Template, vars map[string]interface{}, targetName string) (*gnmi.SetRequest, error) {
+	in, err := a.renderIntent(tpl, vars, targetName)
+	if err != nil {
+		return nil, err
+	}
+	return in.setRequest(a.listKeysFunc(nil))
+}
+
+func (a *App) renderIntent(tpl *template.Template, vars map[string]interface{}, targetName string) (*intent, error) {
+	buf := new(bytes.Buffer)
+	err := tpl.Execute(buf, intentTemplateInput{
+		TargetName: targetName,
+		Vars:       vars,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if a.debugEnabled() {
+		a.Logger.Printf("target %q intent:\n%s", targetName, buf.String())
+	}
+	in := new(intent)
+	err = yaml.Unmarshal(buf.Bytes(), in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode intent: %v", err)
+	}
+	if in.Encoding == "" {
+		in.Encoding = a.Config.Encoding
+	}
+	return in, nil
+}
+
+// setRequest maps the intent resources to a SetRequest.
+// The values of the resources that are not replaced are split into leaf updates,
+// using listKeys to build the list entries paths.
+func (in *intent) setRequest(listKeys path.ListKeysFunc) (*gnmi.SetRequest, error) {
+	enc := strings.ToLower(strings.ReplaceAll(in.Encoding, "-", "_"))
+	switch enc {
+	case "json", "json_ietf":
+	default:
+		return nil, fmt.Errorf("unsupported intent encoding %q, must be json or json_ietf", in.Encoding)
+	}
+	if len(in.Resources) == 0 {
+		return nil, errors.New("intent has no resources")
+	}
+	req := new(gnmi.SetRequest)
+	for i, r := range in.Resources {
+		if r.Path == "" {
+			r.Path = "/"
+		}
+		p, err := path.ParsePath(strings.TrimSpace(r.Path))
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: invalid path %q: %v", i, r.Path, err)
+		}
+		switch strings.ToLower(r.State) {
+		case "", intentStatePresent:
+		case intentStateAbsent:
+			if r.Value != nil {
+				return nil, fmt.Errorf("resource %d: an absent resource cannot have a value", i)
+			}
+			req.Delete = append(req.Delete, p)
+			continue
+		default:
+			return nil, fmt.Errorf("resource %d: unknown state %q", i, r.State)
+		}
+		if r.Value == nil {
+			return nil, fmt.Errorf("resource %d: missing value", i)
+		}
+		b, err := json.Marshal(utils.Convert(r.Value))
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		}
+		if r.Replace {
+			req.Replace = append(req.Replace, &gnmi.Update{Path: p, Val: jsonTypedValue(b, enc)})
+			continue
+		}
+		upds, err := path.FromJSON(p, b, listKeys)
+		if errors.Is(err, path.ErrUnknownListKeys) {
+			return nil, fmt.Errorf("resource %d: %v: load the YANG models with --file", i, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		}
+		for _, upd := range upds {
+			upd.Val = jsonTypedValue(upd.GetVal().GetJsonVal(), enc)
+			req.Update = append(req.Update, upd)
+		}
+	}
+	return req, nil
+}
+
+func jsonTypedValue(b []byte, enc string) *gnmi.TypedValue {
+	if enc == "json_ietf" {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: b}}
+	}
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: b}}
+}
+
+func (a *App) InitApplyFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ApplyIntentFile, "intent-file", "f", "", "intent file mapped to a Set request for each target")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ApplyVars, "vars", "", "", "intent file template variables file")
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.ApplyDryRun, "dry-run", "", false, "prints the set requests without initiating a gRPC connection")
+	cmd.Flags().DurationVarP(&a.Config.LocalFlags.ApplyReconcileInterval, "reconcile-interval", "", 0, "if set, periodically compares the targets configuration with the intent instead of applying it once")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ApplyReconcileAction, "reconcile-action", "", reconcileActionReport, "action taken when a drift from the intent is detected: report or enforce")
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
+	})
+}
@@ -0,0 +1,102 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+const testIntent = `
+encoding: json_ietf
+resources:
+  - path: /interfaces
+    value:
+      interface:
+        - name: ethernet-1/1
+          config:
+            description: {{ index .Vars .TargetName "description" }}
+  - path: /system/config
+    replace: true
+    value:
+      hostname: {{ .TargetName }}
+  - path: /network-instances/network-instance[name=old]
+    state: absent
+`
+
+func TestIntentSetRequest(t *testing.T) {
+	a := New()
+	tpl, err := gtemplate.CreateTemplate("intent", testIntent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]interface{}{
+		"router1": map[string]interface{}{"description": "uplink"},
+	}
+	// keys of the interface list set with --list-key or found in the YANG schema
+	listKeys := a.listKeysFunc(map[string][]string{"interface": {"name"}})
+	in, err := a.renderIntent(tpl, vars, "router1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := in.setRequest(listKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustPath := func(s string) *gnmi.Path {
+		p, err := path.ParsePath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	want := &gnmi.SetRequest{
+		Delete: []*gnmi.Path{mustPath("/network-instances/network-instance[name=old]")},
+		Replace: []*gnmi.Update{{
+			Path: mustPath("/system/config"),
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"hostname":"router1"}`)}},
+		}},
+		Update: []*gnmi.Update{{
+			Path: mustPath("/interfaces/interface[name=ethernet-1/1]/config/description"),
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"uplink"`)}},
+		}, {
+			Path: mustPath("/interfaces/interface[name=ethernet-1/1]/name"),
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"ethernet-1/1"`)}},
+		}},
+	}
+	if !proto.Equal(req, want) {
+		t.Errorf("unexpected SetRequest:\n got: %s\nwant: %s", prototext.Format(req), prototext.Format(want))
+	}
+}
+
+func TestIntentSetRequestErrors(t *testing.T) {
+	tests := map[string]*intent{
+		"no_resources":  {Encoding: "json"},
+		"bad_encoding":  {Encoding: "proto", Resources: []*intentResource{{Path: "/a", Value: 1}}},
+		"unknown_state": {Encoding: "json", Resources: []*intentResource{{Path: "/a", State: "running", Value: 1}}},
+		"absent_value":  {Encoding: "json", Resources: []*intentResource{{Path: "/a", State: "absent", Value: 1}}},
+		"missing_value": {Encoding: "json", Resources: []*intentResource{{Path: "/a"}}},
+		"unknown_keys": {Encoding: "json", Resources: []*intentResource{{Path: "/interfaces", Value: map[string]interface{}{
+			"interface": []interface{}{map[string]interface{}{"name": "ethernet-1/1"}},
+		}}}},
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := in.setRequest(nil); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -53,16 +53,11 @@ func (a *App) CapRunE(cmd *cobra.Command, args []string) error {
 	}
 	numTargets := len(a.Config.Targets)
 	a.errCh = make(chan error, numTargets*2)
-	a.wg.Add(numTargets)
-	for _, tc := range a.Config.Targets {
-		go a.ReqCapabilities(ctx, tc)
-	}
-	a.wg.Wait()
-	return a.checkErrors()
+	rs := a.runOnTargets(ctx, a.Config.LocalFlags.CapabilitiesMaxConcurrentTargets, 0, a.ReqCapabilities)
+	return a.checkTargetsErrors(rs)
 }
 
-func (a *App) ReqCapabilities(ctx context.Context, tc *types.TargetConfig) {
-	defer a.wg.Done()
+func (a *App) ReqCapabilities(ctx context.Context, tc *types.TargetConfig) error {
 	ext := make([]*gnmi_ext.Extension, 0) //
 	if a.Config.PrintRequest {
 		err := a.PrintMsg(tc.Name, "Capabilities Request:", &gnmi.CapabilityRequest{
@@ -77,19 +72,21 @@ func (a *App) ReqCapabilities(ctx context.Context, tc *types.TargetConfig) {
 	response, err := a.ClientCapabilities(ctx, tc, ext...)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q, capabilities request failed: %v", tc.Name, err))
-		return
+		return err
 	}
 
 	err = a.PrintMsg(tc.Name, "Capabilities Response:", response)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
 	}
+	return err
 }
 
 func (a *App) InitCapabilitiesFlags(cmd *cobra.Command) {
 	cmd.ResetFlags()
 
 	cmd.Flags().BoolVarP(&a.Config.LocalFlags.CapabilitiesVersion, "version", "", false, "show gnmi version only")
+	cmd.Flags().UintVarP(&a.Config.LocalFlags.CapabilitiesMaxConcurrentTargets, "max-concurrent-targets", "", 0, "maximum number of targets the request is sent to concurrently, overrides the global flag --concurrency")
 	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
 		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
 	})
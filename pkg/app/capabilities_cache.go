@@ -0,0 +1,290 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const capabilitiesChangeEventName = "capabilities-change"
+
+// targetCapabilities is the cached CapabilityResponse of a target.
+type targetCapabilities struct {
+	Target             string               `json:"target,omitempty"`
+	LastUpdated        time.Time            `json:"last-updated,omitempty"`
+	GNMIVersion        string               `json:"gnmi-version,omitempty"`
+	SupportedEncodings []string             `json:"supported-encodings,omitempty"`
+	SupportedModels    []*capabilitiesModel `json:"supported-models,omitempty"`
+}
+
+type capabilitiesModel struct {
+	Name         string `json:"name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+func (m *capabilitiesModel) String() string {
+	if m.Version == "" {
+		return m.Name
+	}
+	return m.Name + "@" + m.Version
+}
+
+// capabilitiesChange is the difference between two cached CapabilityResponses.
+type capabilitiesChange struct {
+	oldGNMIVersion   string
+	newGNMIVersion   string
+	addedModels      []string
+	removedModels    []string
+	addedEncodings   []string
+	removedEncodings []string
+}
+
+func newTargetCapabilities(name string, rsp *gnmi.CapabilityResponse) *targetCapabilities {
+	tcaps := &targetCapabilities{
+		Target:             name,
+		LastUpdated:        time.Now(),
+		GNMIVersion:        rsp.GetGNMIVersion(),
+		SupportedEncodings: make([]string, 0, len(rsp.GetSupportedEncodings())),
+		SupportedModels:    make([]*capabilitiesModel, 0, len(rsp.GetSupportedModels())),
+	}
+	for _, enc := range rsp.GetSupportedEncodings() {
+		tcaps.SupportedEncodings = append(tcaps.SupportedEncodings, strings.ToLower(enc.String()))
+	}
+	sort.Strings(tcaps.SupportedEncodings)
+	for _, m := range rsp.GetSupportedModels() {
+		tcaps.SupportedModels = append(tcaps.SupportedModels, &capabilitiesModel{
+			Name:         m.GetName(),
+			Organization: m.GetOrganization(),
+			Version:      m.GetVersion(),
+		})
+	}
+	sort.Slice(tcaps.SupportedModels, func(i, j int) bool {
+		return tcaps.SupportedModels[i].String() < tcaps.SupportedModels[j].String()
+	})
+	return tcaps
+}
+
+// watchTargetCapabilities caches the target CapabilityResponse and refreshes it
+// periodically until ctx is done, if the capabilities-cache is configured.
+func (a *App) watchTargetCapabilities(ctx context.Context, t *target.Target) {
+	if a.Config.CapabilitiesCache == nil {
+		return
+	}
+	ticker := time.NewTicker(a.Config.CapabilitiesCache.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		a.refreshTargetCapabilities(ctx, t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *App) refreshTargetCapabilities(ctx context.Context, t *target.Target) {
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+	rsp, err := t.Capabilities(ctx)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to refresh capabilities: %v", t.Config.Name, err)
+		return
+	}
+	tcaps := newTargetCapabilities(t.Config.Name, rsp)
+	a.operLock.Lock()
+	old := a.targetsCapabilities[t.Config.Name]
+	// the target might have been deleted in the meantime
+	if _, ok := a.Targets[t.Config.Name]; ok {
+		a.targetsCapabilities[t.Config.Name] = tcaps
+	}
+	a.operLock.Unlock()
+	if old == nil {
+		a.Logger.Printf("target %q: cached capabilities: gNMI version %s, %d models, encodings %v",
+			t.Config.Name, tcaps.GNMIVersion, len(tcaps.SupportedModels), tcaps.SupportedEncodings)
+		return
+	}
+	ch := diffTargetCapabilities(old, tcaps)
+	if ch == nil {
+		return
+	}
+	a.Logger.Printf("target %q: capabilities changed: %s", t.Config.Name, ch)
+	a.exportEvent(ctx, ch.event(t.Config.Name, tcaps.LastUpdated), t.Config.Outputs...)
+}
+
+// deleteTargetCapabilities removes the cached capabilities of target tName.
+// It must be called with the operLock held.
+func (a *App) deleteTargetCapabilities(tName string) {
+	delete(a.targetsCapabilities, tName)
+}
+
+// getTargetsCapabilities returns the cached capabilities of the targets
+// in names, or of all the targets if names is empty.
+func (a *App) getTargetsCapabilities(names ...string) []*targetCapabilities {
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	r := make([]*targetCapabilities, 0, len(a.targetsCapabilities))
+	if len(names) == 0 {
+		for _, tcaps := range a.targetsCapabilities {
+			r = append(r, tcaps)
+		}
+	}
+	for _, name := range names {
+		if tcaps, ok := a.targetsCapabilities[name]; ok {
+			r = append(r, tcaps)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Target < r[j].Target
+	})
+	return r
+}
+
+func diffTargetCapabilities(old, new *targetCapabilities) *capabilitiesChange {
+	ch := &capabilitiesChange{}
+	if old.GNMIVersion != new.GNMIVersion {
+		ch.oldGNMIVersion = old.GNMIVersion
+		ch.newGNMIVersion = new.GNMIVersion
+	}
+	oldModels := make([]string, 0, len(old.SupportedModels))
+	for _, m := range old.SupportedModels {
+		oldModels = append(oldModels, m.String())
+	}
+	newModels := make([]string, 0, len(new.SupportedModels))
+	for _, m := range new.SupportedModels {
+		newModels = append(newModels, m.String())
+	}
+	ch.addedModels, ch.removedModels = diffStrings(oldModels, newModels)
+	ch.addedEncodings, ch.removedEncodings = diffStrings(old.SupportedEncodings, new.SupportedEncodings)
+	if ch.oldGNMIVersion == ch.newGNMIVersion &&
+		len(ch.addedModels)+len(ch.removedModels)+len(ch.addedEncodings)+len(ch.removedEncodings) == 0 {
+		return nil
+	}
+	return ch
+}
+
+// diffStrings returns the elements of new missing from old
+// and the elements of old missing from new.
+func diffStrings(old, new []string) ([]string, []string) {
+	om := make(map[string]struct{}, len(old))
+	for _, s := range old {
+		om[s] = struct{}{}
+	}
+	nm := make(map[string]struct{}, len(new))
+	for _, s := range new {
+		nm[s] = struct{}{}
+	}
+	var added, removed []string
+	for _, s := range new {
+		if _, ok := om[s]; !ok {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if _, ok := nm[s]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func (ch *capabilitiesChange) String() string {
+	parts := make([]string, 0, 5)
+	if ch.oldGNMIVersion != ch.newGNMIVersion {
+		parts = append(parts, fmt.Sprintf("gNMI version %q -> %q", ch.oldGNMIVersion, ch.newGNMIVersion))
+	}
+	for _, p := range []struct {
+		name string
+		s    []string
+	}{
+		{"added models", ch.addedModels},
+		{"removed models", ch.removedModels},
+		{"added encodings", ch.addedEncodings},
+		{"removed encodings", ch.removedEncodings},
+	} {
+		if len(p.s) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", p.name, strings.Join(p.s, ",")))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (ch *capabilitiesChange) event(name string, ts time.Time) *formatters.EventMsg {
+	ev := &formatters.EventMsg{
+		Name:      capabilitiesChangeEventName,
+		Timestamp: ts.UnixNano(),
+		Tags:      map[string]string{"source": name},
+		Values:    make(map[string]interface{}),
+	}
+	if ch.oldGNMIVersion != ch.newGNMIVersion {
+		ev.Values["old-gnmi-version"] = ch.oldGNMIVersion
+		ev.Values["gnmi-version"] = ch.newGNMIVersion
+	}
+	for k, v := range map[string][]string{
+		"added-models":      ch.addedModels,
+		"removed-models":    ch.removedModels,
+		"added-encodings":   ch.addedEncodings,
+		"removed-encodings": ch.removedEncodings,
+	} {
+		if len(v) > 0 {
+			ev.Values[k] = strings.Join(v, ",")
+		}
+	}
+	return ev
+}
+
+func (a *App) handleCapabilitiesGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		a.handlerCommonGet(w, a.getTargetsCapabilities())
+		return
+	}
+	tcaps := a.getTargetsCapabilities(id)
+	if len(tcaps) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("no capabilities found for target %q", id)}})
+		return
+	}
+	a.handlerCommonGet(w, tcaps[0])
+}
+
+func targetCapabilitiesToNotification(tcaps *targetCapabilities) *gnmi.Notification {
+	b, _ := json.Marshal(tcaps)
+	return &gnmi.Notification{
+		Timestamp: tcaps.LastUpdated.UnixNano(),
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{
+					Origin: "gnmic",
+					Elem: []*gnmi.PathElem{
+						{
+							Name: "capabilities",
+							Key:  map[string]string{"name": tcaps.Target},
+						},
+					},
+				},
+				Val: &gnmi.TypedValue{
+					Value: &gnmi.TypedValue_JsonVal{JsonVal: b},
+				},
+			},
+		},
+	}
+}
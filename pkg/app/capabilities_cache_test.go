@@ -0,0 +1,193 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type eventsOutput struct {
+	testOutput
+	m      sync.Mutex
+	events []*formatters.EventMsg
+}
+
+func (o *eventsOutput) WriteEvent(_ context.Context, ev *formatters.EventMsg) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.events = append(o.events, ev)
+}
+
+type mutableCapabilitiesServer struct {
+	gnmi.UnimplementedGNMIServer
+	m   sync.Mutex
+	rsp *gnmi.CapabilityResponse
+}
+
+func (s *mutableCapabilitiesServer) Capabilities(context.Context, *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.rsp, nil
+}
+
+func (s *mutableCapabilitiesServer) set(rsp *gnmi.CapabilityResponse) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.rsp = rsp
+}
+
+func TestDiffTargetCapabilities(t *testing.T) {
+	old := newTargetCapabilities("r1", &gnmi.CapabilityResponse{
+		GNMIVersion:        "0.7.0",
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_JSON, gnmi.Encoding_PROTO},
+		SupportedModels: []*gnmi.ModelData{
+			{Name: "openconfig-interfaces", Version: "2.0.0"},
+			{Name: "openconfig-system"},
+		},
+	})
+	if ch := diffTargetCapabilities(old, old); ch != nil {
+		t.Errorf("unexpected change: %s", ch)
+	}
+	new := newTargetCapabilities("r1", &gnmi.CapabilityResponse{
+		GNMIVersion:        "0.10.0",
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_PROTO, gnmi.Encoding_JSON_IETF},
+		SupportedModels: []*gnmi.ModelData{
+			{Name: "openconfig-interfaces", Version: "3.0.0"},
+			{Name: "openconfig-system"},
+		},
+	})
+	ch := diffTargetCapabilities(old, new)
+	if ch == nil {
+		t.Fatalf("expected a change")
+	}
+	want := `gNMI version "0.7.0" -> "0.10.0", added models: openconfig-interfaces@3.0.0, removed models: openconfig-interfaces@2.0.0, added encodings: json_ietf, removed encodings: json`
+	if ch.String() != want {
+		t.Errorf("unexpected change:\ngot:  %s\nwant: %s", ch, want)
+	}
+	ev := ch.event("r1", new.LastUpdated)
+	if ev.Name != capabilitiesChangeEventName || ev.Tags["source"] != "r1" ||
+		ev.Values["gnmi-version"] != "0.10.0" || ev.Values["added-encodings"] != "json_ietf" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestRefreshTargetCapabilities(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &mutableCapabilitiesServer{rsp: &gnmi.CapabilityResponse{
+		GNMIVersion:     "0.7.0",
+		SupportedModels: []*gnmi.ModelData{{Name: "openconfig-interfaces"}},
+	}}
+	s := grpc.NewServer()
+	gnmi.RegisterGNMIServer(s, srv)
+	go s.Serve(l)
+	defer s.Stop()
+
+	insecure := true
+	tg := target.NewTarget(&types.TargetConfig{
+		Name:     "r1",
+		Address:  l.Addr().String(),
+		Insecure: &insecure,
+		Timeout:  5 * time.Second,
+	})
+	ctx := context.Background()
+	if err := tg.CreateGNMIClient(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer tg.Close()
+
+	a := New()
+	o := &eventsOutput{}
+	a.Outputs["o1"] = o
+	a.Targets["r1"] = tg
+
+	a.refreshTargetCapabilities(ctx, tg)
+	if caps := a.getTargetsCapabilities("r1"); len(caps) != 1 || caps[0].GNMIVersion != "0.7.0" {
+		t.Fatalf("unexpected cached capabilities: %+v", caps)
+	}
+	// no event on the first response nor without change
+	a.refreshTargetCapabilities(ctx, tg)
+	if len(o.events) != 0 {
+		t.Fatalf("unexpected events: %v", o.events)
+	}
+
+	srv.set(&gnmi.CapabilityResponse{
+		GNMIVersion:     "0.7.0",
+		SupportedModels: []*gnmi.ModelData{{Name: "openconfig-interfaces"}, {Name: "openconfig-system"}},
+	})
+	a.refreshTargetCapabilities(ctx, tg)
+	if len(o.events) != 1 || o.events[0].Values["added-models"] != "openconfig-system" {
+		t.Fatalf("unexpected events: %v", o.events)
+	}
+
+	// REST API
+	get := func(id string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+		if id != "" {
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+		}
+		rec := httptest.NewRecorder()
+		a.handleCapabilitiesGet(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+	code, body := get("r1")
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	tcaps := new(targetCapabilities)
+	if err := json.Unmarshal(body, tcaps); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(tcaps.SupportedModels) != 2 {
+		t.Errorf("unexpected capabilities: %s", body)
+	}
+	if code, _ := get("r2"); code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown target", code)
+	}
+
+	// gnmic origin
+	rsp, err := a.handlegNMIcInternalGet(ctx, &gnmi.GetRequest{
+		Path: []*gnmi.Path{{
+			Origin: "gnmic",
+			Elem:   []*gnmi.PathElem{{Name: "capabilities", Key: map[string]string{"name": "r1"}}},
+		}},
+		Encoding: gnmi.Encoding_JSON,
+	})
+	if err != nil {
+		t.Fatalf("gnmic origin Get failed: %v", err)
+	}
+	if len(rsp.GetNotification()) != 1 {
+		t.Fatalf("unexpected Get response: %v", rsp)
+	}
+
+	// the cache is removed with the target
+	a.operLock.Lock()
+	a.deleteTargetCapabilities("r1")
+	a.operLock.Unlock()
+	if caps := a.getTargetsCapabilities(); len(caps) != 0 {
+		t.Errorf("capabilities not removed: %v", caps)
+	}
+}
@@ -0,0 +1,605 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnoi/cert"
+	"github.com/openconfig/grpctunnel/tunnel"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	gfile "github.com/openconfig/gnmic/pkg/file"
+)
+
+const (
+	defaultCertValidity = 365 * 24 * time.Hour
+	defaultCertKeySize  = 2048
+)
+
+// certAuthority is the local CA used to sign the
+// certificate signing requests generated by the targets.
+type certAuthority struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	// PEM encoded certificates sent to the targets
+	// as the trust bundle of the loaded certificate.
+	bundle [][]byte
+}
+
+// certificateInfo is the printed summary of a certificate.
+type certificateInfo struct {
+	ID               string     `json:"id,omitempty"`
+	Subject          string     `json:"subject,omitempty"`
+	Issuer           string     `json:"issuer,omitempty"`
+	SerialNumber     string     `json:"serial-number,omitempty"`
+	NotBefore        time.Time  `json:"not-before,omitempty"`
+	NotAfter         time.Time  `json:"not-after,omitempty"`
+	DNSNames         []string   `json:"dns-names,omitempty"`
+	IPAddresses      []string   `json:"ip-addresses,omitempty"`
+	Endpoints        []string   `json:"endpoints,omitempty"`
+	ModificationTime *time.Time `json:"modification-time,omitempty"`
+}
+
+// InitCertGetFlags used to init or reset certGetCmd flags for gnmic-prompt mode
+func (a *App) InitCertGetFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertID, "id", "", "", "only show the certificate with this ID")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "cert-get", flag.Name), flag)
+	})
+}
+
+// InitCertInstallFlags used to init or reset certInstallCmd flags for gnmic-prompt mode
+func (a *App) InitCertInstallFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	a.initCertSignFlags(cmd)
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "cert-install", flag.Name), flag)
+	})
+}
+
+// InitCertRotateFlags used to init or reset certRotateCmd flags for gnmic-prompt mode
+func (a *App) InitCertRotateFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	a.initCertSignFlags(cmd)
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.CertNoVerify, "no-verify", "", false, "finalize the rotation without verifying that a new connection to the target succeeds")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "cert-rotate", flag.Name), flag)
+	})
+}
+
+func (a *App) initCertSignFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertID, "id", "", "", "certificate ID")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCACert, "ca-cert", "", "", "CA certificate used to sign the target CSR")
+	cmd.MarkFlagRequired("ca-cert")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCAKey, "ca-key", "", "", "CA private key used to sign the target CSR")
+	cmd.MarkFlagRequired("ca-key")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCABundle, "ca-bundle", "", "", "CA certificates loaded on the target together with the signed certificate, used by the target to verify the clients certificates")
+	cmd.Flags().DurationVarP(&a.Config.LocalFlags.CertValidity, "validity", "", defaultCertValidity, "validity of the signed certificate")
+	cmd.Flags().Uint32VarP(&a.Config.LocalFlags.CertKeySize, "key-size", "", defaultCertKeySize, "minimum size of the RSA key generated by the target")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCommonName, "common-name", "", "", "CSR common name, defaults to the target name")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCountry, "country", "", "", "CSR country")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertState, "state", "", "", "CSR state")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertCity, "city", "", "", "CSR city")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertOrg, "org", "", "", "CSR organization")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertOrgUnit, "org-unit", "", "", "CSR organizational unit")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertIPAddress, "ip-address", "", "", "CSR IP address, defaults to the target address if it is an IP")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.CertEmailID, "email-id", "", "", "CSR email ID")
+}
+
+func (a *App) CertPreRunE(cmd *cobra.Command, _ []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	if a.Config.Format == formatEvent {
+		return fmt.Errorf("format event not supported for gNOI certificate management RPCs")
+	}
+	if a.Config.LocalFlags.CertValidity < 0 {
+		return errors.New("validity cannot be negative")
+	}
+	a.createCollectorDialOpts()
+	return a.initTunnelServer(tunnel.ServerConfig{
+		AddTargetHandler:    a.tunServerAddTargetHandler,
+		DeleteTargetHandler: a.tunServerDeleteTargetHandler,
+		RegisterHandler:     a.tunServerRegisterHandler,
+		Handler:             a.tunServerHandler,
+	})
+}
+
+func (a *App) CertGetRunE(cmd *cobra.Command, _ []string) error {
+	defer a.InitCertGetFlags(cmd)
+	return a.runCert(func(ctx context.Context, tc *types.TargetConfig) error {
+		return a.certGet(ctx, tc)
+	})
+}
+
+func (a *App) CertInstallRunE(cmd *cobra.Command, _ []string) error {
+	defer a.InitCertInstallFlags(cmd)
+	ca, err := a.loadCertAuthority(a.ctx)
+	if err != nil {
+		return err
+	}
+	return a.runCert(func(ctx context.Context, tc *types.TargetConfig) error {
+		return a.certInstall(ctx, tc, ca)
+	})
+}
+
+func (a *App) CertRotateRunE(cmd *cobra.Command, _ []string) error {
+	defer a.InitCertRotateFlags(cmd)
+	ca, err := a.loadCertAuthority(a.ctx)
+	if err != nil {
+		return err
+	}
+	return a.runCert(func(ctx context.Context, tc *types.TargetConfig) error {
+		return a.certRotate(ctx, tc, ca)
+	})
+}
+
+func (a *App) runCert(fn func(context.Context, *types.TargetConfig) error) error {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+	targetsConfig, err := a.GetTargets()
+	if err != nil {
+		return fmt.Errorf("failed getting targets config: %v", err)
+	}
+	if a.PromptMode {
+		for _, tc := range targetsConfig {
+			a.AddTargetConfig(tc)
+		}
+	}
+	numTargets := len(a.Config.Targets)
+	a.errCh = make(chan error, numTargets*2)
+	rs := a.runOnTargets(ctx, 0, 0, func(ctx context.Context, tc *types.TargetConfig) error {
+		err := fn(ctx, tc)
+		if err != nil {
+			a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
+		}
+		return err
+	})
+	return a.checkTargetsErrors(rs)
+}
+
+// certClient returns a gNOI CertificateManagement client
+// using the target gRPC connection.
+func (a *App) certClient(ctx context.Context, tc *types.TargetConfig) (*target.Target, cert.CertificateManagementClient, error) {
+	a.operLock.Lock()
+	t, err := a.initTarget(tc)
+	a.operLock.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	a.operLock.RLock()
+	err = a.CreateGNMIClient(ctx, t)
+	a.operLock.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, cert.NewCertificateManagementClient(t.Conn()), nil
+}
+
+func (a *App) certGet(ctx context.Context, tc *types.TargetConfig) error {
+	t, client, err := a.certClient(ctx, tc)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+	rsp, err := client.GetCertificates(t.RequestContext(ctx), &cert.GetCertificatesRequest{}, t.CallOptions()...)
+	if err != nil {
+		return fmt.Errorf("GetCertificates failed: %v", err)
+	}
+	switch a.Config.Format {
+	case formatPROTOJSON, formatPROTOTEXT, formatPROTO:
+		return a.PrintMsg(tc.Name, "GetCertificates Response:", rsp)
+	}
+	infos := make([]*certificateInfo, 0, len(rsp.GetCertificateInfo()))
+	for _, ci := range rsp.GetCertificateInfo() {
+		if a.Config.LocalFlags.CertID != "" && ci.GetCertificateId() != a.Config.LocalFlags.CertID {
+			continue
+		}
+		info, err := newCertificateInfo(ci)
+		if err != nil {
+			return fmt.Errorf("certificate %q: %v", ci.GetCertificateId(), err)
+		}
+		infos = append(infos, info)
+	}
+	return a.printCertInfo(tc.Name, infos)
+}
+
+func (a *App) certInstall(ctx context.Context, tc *types.TargetConfig, ca *certAuthority) error {
+	t, client, err := a.certClient(ctx, tc)
+	if err != nil {
+		return err
+	}
+	id := a.Config.LocalFlags.CertID
+	stream, err := client.Install(t.RequestContext(ctx), t.CallOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to start Install RPC: %v", err)
+	}
+	defer stream.CloseSend()
+
+	a.Logger.Printf("target %q: requesting CSR for certificate %q", tc.Name, id)
+	err = stream.Send(&cert.InstallCertificateRequest{
+		InstallRequest: &cert.InstallCertificateRequest_GenerateCsr{
+			GenerateCsr: a.generateCSRRequest(tc),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send GenerateCSR request: %v", err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive GenerateCSR response: %v", err)
+	}
+	csr := rsp.GetGeneratedCsr().GetCsr()
+	if csr == nil {
+		return fmt.Errorf("unexpected Install response: %v", rsp)
+	}
+	crt, certPEM, err := ca.sign(csr.GetCsr(), a.Config.LocalFlags.CertValidity)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&cert.InstallCertificateRequest{
+		InstallRequest: &cert.InstallCertificateRequest_LoadCertificate{
+			LoadCertificate: ca.loadCertificateRequest(id, certPEM),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send LoadCertificate request: %v", err)
+	}
+	rsp, err = stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive LoadCertificate response: %v", err)
+	}
+	if rsp.GetLoadCertificate() == nil {
+		return fmt.Errorf("unexpected Install response: %v", rsp)
+	}
+	a.Logger.Printf("target %q: certificate %q installed", tc.Name, id)
+	return a.printCertInfo(tc.Name, []*certificateInfo{x509CertificateInfo(id, crt)})
+}
+
+func (a *App) certRotate(ctx context.Context, tc *types.TargetConfig, ca *certAuthority) error {
+	t, client, err := a.certClient(ctx, tc)
+	if err != nil {
+		return err
+	}
+	id := a.Config.LocalFlags.CertID
+	stream, err := client.Rotate(t.RequestContext(ctx), t.CallOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to start Rotate RPC: %v", err)
+	}
+	// closing the stream without a FinalizeRequest
+	// makes the target roll back to the previous certificate.
+	defer stream.CloseSend()
+
+	a.Logger.Printf("target %q: requesting CSR for certificate %q", tc.Name, id)
+	err = stream.Send(&cert.RotateCertificateRequest{
+		RotateRequest: &cert.RotateCertificateRequest_GenerateCsr{
+			GenerateCsr: a.generateCSRRequest(tc),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send GenerateCSR request: %v", err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive GenerateCSR response: %v", err)
+	}
+	csr := rsp.GetGeneratedCsr().GetCsr()
+	if csr == nil {
+		return fmt.Errorf("unexpected Rotate response: %v", rsp)
+	}
+	crt, certPEM, err := ca.sign(csr.GetCsr(), a.Config.LocalFlags.CertValidity)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&cert.RotateCertificateRequest{
+		RotateRequest: &cert.RotateCertificateRequest_LoadCertificate{
+			LoadCertificate: ca.loadCertificateRequest(id, certPEM),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send LoadCertificate request: %v", err)
+	}
+	rsp, err = stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive LoadCertificate response: %v", err)
+	}
+	if rsp.GetLoadCertificate() == nil {
+		return fmt.Errorf("unexpected Rotate response: %v", rsp)
+	}
+	if !a.Config.LocalFlags.CertNoVerify {
+		err = a.verifyTargetConnection(ctx, tc)
+		if err != nil {
+			return fmt.Errorf("rotation of certificate %q not finalized: %v", id, err)
+		}
+	}
+	err = stream.Send(&cert.RotateCertificateRequest{
+		RotateRequest: &cert.RotateCertificateRequest_FinalizeRotation{
+			FinalizeRotation: &cert.FinalizeRequest{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send Finalize request: %v", err)
+	}
+	stream.CloseSend()
+	_, err = stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("rotation of certificate %q failed: %v", id, err)
+	}
+	a.Logger.Printf("target %q: certificate %q rotated", tc.Name, id)
+	return a.printCertInfo(tc.Name, []*certificateInfo{x509CertificateInfo(id, crt)})
+}
+
+// verifyTargetConnection opens a new connection to the target,
+// using the rotated certificate, and sends it a Capabilities request.
+func (a *App) verifyTargetConnection(ctx context.Context, tc *types.TargetConfig) error {
+	ntc := *tc
+	t := target.NewTarget(&ntc)
+	defer t.Close()
+	err := a.CreateGNMIClient(ctx, t)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+	_, err = t.Capabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("verification CapabilitiesRequest failed: %v", err)
+	}
+	return nil
+}
+
+func (a *App) generateCSRRequest(tc *types.TargetConfig) *cert.GenerateCSRRequest {
+	commonName := a.Config.LocalFlags.CertCommonName
+	if commonName == "" {
+		commonName = tc.Name
+	}
+	ipAddress := a.Config.LocalFlags.CertIPAddress
+	if ipAddress == "" {
+		ipAddress = targetIPAddress(tc.Address)
+	}
+	return &cert.GenerateCSRRequest{
+		CertificateId: a.Config.LocalFlags.CertID,
+		CsrParams: &cert.CSRParams{
+			Type:               cert.CertificateType_CT_X509,
+			MinKeySize:         a.Config.LocalFlags.CertKeySize,
+			KeyType:            cert.KeyType_KT_RSA,
+			CommonName:         commonName,
+			Country:            a.Config.LocalFlags.CertCountry,
+			State:              a.Config.LocalFlags.CertState,
+			City:               a.Config.LocalFlags.CertCity,
+			Organization:       a.Config.LocalFlags.CertOrg,
+			OrganizationalUnit: a.Config.LocalFlags.CertOrgUnit,
+			IpAddress:          ipAddress,
+			EmailId:            a.Config.LocalFlags.CertEmailID,
+		},
+	}
+}
+
+// targetIPAddress returns the IP address of the first
+// of the target addresses, empty if it is not an IP.
+func targetIPAddress(address string) string {
+	addr, _, _ := strings.Cut(address, ",")
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
+func (a *App) loadCertAuthority(ctx context.Context) (*certAuthority, error) {
+	certPEM, err := gfile.ReadFile(ctx, a.Config.LocalFlags.CertCACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+	}
+	keyPEM, err := gfile.ReadFile(ctx, a.Config.LocalFlags.CertCAKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %v", err)
+	}
+	ca, err := newCertAuthority(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if a.Config.LocalFlags.CertCABundle == "" {
+		return ca, nil
+	}
+	b, err := gfile.ReadFile(ctx, a.Config.LocalFlags.CertCABundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+	}
+	ca.bundle, err = splitPEMCertificates(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+	}
+	return ca, nil
+}
+
+func newCertAuthority(certPEM, keyPEM []byte) (*certAuthority, error) {
+	kp, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA key pair: %v", err)
+	}
+	crt, err := x509.ParseCertificate(kp.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %v", err)
+	}
+	if !crt.IsCA {
+		return nil, errors.New("the CA certificate is not a CA")
+	}
+	key, ok := kp.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key type %T", kp.PrivateKey)
+	}
+	return &certAuthority{cert: crt, key: key}, nil
+}
+
+// sign issues a certificate for the PEM or DER encoded CSR,
+// valid for both server and client authentication.
+// It returns the certificate and its PEM encoding.
+func (ca *certAuthority) sign(csrBytes []byte, validity time.Duration) (*x509.Certificate, []byte, error) {
+	if block, _ := pem.Decode(csrBytes); block != nil {
+		csrBytes = block.Bytes
+	}
+	csr, err := x509.ParseCertificateRequest(csrBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CSR: %v", err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, nil, fmt.Errorf("invalid CSR signature: %v", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	if validity == 0 {
+		validity = defaultCertValidity
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:   serialNumber,
+		Subject:        csr.Subject,
+		DNSNames:       csr.DNSNames,
+		IPAddresses:    csr.IPAddresses,
+		EmailAddresses: csr.EmailAddresses,
+		URIs:           csr.URIs,
+		NotBefore:      now,
+		NotAfter:       now.Add(validity),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign CSR: %v", err)
+	}
+	crt, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return crt, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func (ca *certAuthority) loadCertificateRequest(id string, certPEM []byte) *cert.LoadCertificateRequest {
+	req := &cert.LoadCertificateRequest{
+		CertificateId: id,
+		Certificate: &cert.Certificate{
+			Type:        cert.CertificateType_CT_X509,
+			Certificate: certPEM,
+		},
+	}
+	for _, b := range ca.bundle {
+		req.CaCertificates = append(req.CaCertificates, &cert.Certificate{
+			Type:        cert.CertificateType_CT_X509,
+			Certificate: b,
+		})
+	}
+	return req
+}
+
+// splitPEMCertificates returns each of the certificates found
+// in b as a separate PEM block.
+func splitPEMCertificates(b []byte) ([][]byte, error) {
+	certs := make([][]byte, 0)
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, err
+		}
+		certs = append(certs, pem.EncodeToMemory(block))
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+func newCertificateInfo(ci *cert.CertificateInfo) (*certificateInfo, error) {
+	b := ci.GetCertificate().GetCertificate()
+	if block, _ := pem.Decode(b); block != nil {
+		b = block.Bytes
+	}
+	crt, err := x509.ParseCertificate(b)
+	if err != nil {
+		return nil, err
+	}
+	info := x509CertificateInfo(ci.GetCertificateId(), crt)
+	for _, ep := range ci.GetEndpoints() {
+		info.Endpoints = append(info.Endpoints, fmt.Sprintf("%s:%s", ep.GetType(), ep.GetEndpoint()))
+	}
+	if ci.GetModificationTime() > 0 {
+		mt := time.Unix(0, ci.GetModificationTime())
+		info.ModificationTime = &mt
+	}
+	return info, nil
+}
+
+func x509CertificateInfo(id string, crt *x509.Certificate) *certificateInfo {
+	info := &certificateInfo{
+		ID:           id,
+		Subject:      crt.Subject.String(),
+		Issuer:       crt.Issuer.String(),
+		SerialNumber: crt.SerialNumber.Text(16),
+		NotBefore:    crt.NotBefore,
+		NotAfter:     crt.NotAfter,
+		DNSNames:     crt.DNSNames,
+	}
+	for _, ip := range crt.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	return info
+}
+
+func (a *App) printCertInfo(name string, infos []*certificateInfo) error {
+	a.printLock.Lock()
+	defer a.printLock.Unlock()
+	if a.Config.Format == formatSummary {
+		return nil
+	}
+	b, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return err
+	}
+	printPrefix := ""
+	if len(a.Config.TargetsList()) > 1 && !a.Config.NoPrefix {
+		printPrefix = fmt.Sprintf("[%s] ", name)
+	}
+	fmt.Fprintf(a.out, "%s\n", indent(printPrefix, string(bytes.TrimSpace(b))))
+	return nil
+}
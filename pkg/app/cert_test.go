@@ -0,0 +1,333 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnoi/cert"
+	"google.golang.org/grpc"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func testCertAuthority(t *testing.T) (*certAuthority, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	ca, err := newCertAuthority(certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, certPEM
+}
+
+func TestCertAuthoritySign(t *testing.T) {
+	ca, caPEM := testCertAuthority(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     pkix.Name{CommonName: "router1"},
+		DNSNames:    []string{"router1.lab"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, csr := range map[string][]byte{
+		"pem": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+		"der": csrDER,
+	} {
+		t.Run(name, func(t *testing.T) {
+			crt, certPEM, err := ca.sign(csr, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block, _ := pem.Decode(certPEM)
+			if block == nil || block.Type != "CERTIFICATE" {
+				t.Fatalf("unexpected certificate PEM: %s", certPEM)
+			}
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(caPEM)
+			for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+				_, err = crt.Verify(x509.VerifyOptions{
+					Roots:     pool,
+					DNSName:   "router1.lab",
+					KeyUsages: []x509.ExtKeyUsage{usage},
+				})
+				if err != nil {
+					t.Errorf("certificate verification failed: %v", err)
+				}
+			}
+			if err = crt.VerifyHostname("10.0.0.1"); err != nil {
+				t.Errorf("IP SAN verification failed: %v", err)
+			}
+			if crt.Subject.CommonName != "router1" {
+				t.Errorf("unexpected common name %q", crt.Subject.CommonName)
+			}
+			if d := crt.NotAfter.Sub(crt.NotBefore); d != time.Hour {
+				t.Errorf("unexpected validity %s", d)
+			}
+		})
+	}
+	if _, _, err = ca.sign([]byte("not a csr"), time.Hour); err == nil {
+		t.Error("expected an error signing an invalid CSR")
+	}
+}
+
+func TestNewCertAuthorityNotCA(t *testing.T) {
+	ca, _ := testCertAuthority(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "leaf"},
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, certPEM, err := ca.sign(csrDER, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newCertAuthority(certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	if err == nil {
+		t.Error("expected an error loading a non CA certificate")
+	}
+}
+
+func TestSplitPEMCertificates(t *testing.T) {
+	_, ca1 := testCertAuthority(t)
+	_, ca2 := testCertAuthority(t)
+	bundle := append(append([]byte{}, ca1...), ca2...)
+	certs, err := splitPEMCertificates(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || string(certs[0]) != string(ca1) || string(certs[1]) != string(ca2) {
+		t.Errorf("unexpected certificates: %q", certs)
+	}
+	if _, err = splitPEMCertificates([]byte("garbage")); err == nil {
+		t.Error("expected an error for a bundle without certificates")
+	}
+}
+
+func TestTargetIPAddress(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.1:57400":            "10.0.0.1",
+		"[2001:db8::1]:57400":       "2001:db8::1",
+		"router1:57400":             "",
+		"10.0.0.1:57400,10.0.0.2:1": "10.0.0.1",
+		"10.0.0.1":                  "10.0.0.1",
+	}
+	for addr, want := range tests {
+		if got := targetIPAddress(addr); got != want {
+			t.Errorf("targetIPAddress(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+// fakeCertServer generates a CSR for each GenerateCSR request
+// and records the loaded certificates.
+type fakeCertServer struct {
+	cert.UnimplementedCertificateManagementServer
+	loaded    map[string]*cert.LoadCertificateRequest
+	finalized bool
+}
+
+func (s *fakeCertServer) csr(req *cert.GenerateCSRRequest) (*cert.GenerateCSRResponse, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: req.GetCsrParams().GetCommonName()},
+	}
+	if ip := net.ParseIP(req.GetCsrParams().GetIpAddress()); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		return nil, err
+	}
+	return &cert.GenerateCSRResponse{Csr: &cert.CSR{
+		Type: cert.CertificateType_CT_X509,
+		Csr:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+	}}, nil
+}
+
+func (s *fakeCertServer) Install(stream cert.CertificateManagement_InstallServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	csr, err := s.csr(req.GetGenerateCsr())
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&cert.InstallCertificateResponse{InstallResponse: &cert.InstallCertificateResponse_GeneratedCsr{GeneratedCsr: csr}})
+	if err != nil {
+		return err
+	}
+	req, err = stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.loaded[req.GetLoadCertificate().GetCertificateId()] = req.GetLoadCertificate()
+	return stream.Send(&cert.InstallCertificateResponse{InstallResponse: &cert.InstallCertificateResponse_LoadCertificate{LoadCertificate: &cert.LoadCertificateResponse{}}})
+}
+
+func (s *fakeCertServer) Rotate(stream cert.CertificateManagement_RotateServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	csr, err := s.csr(req.GetGenerateCsr())
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&cert.RotateCertificateResponse{RotateResponse: &cert.RotateCertificateResponse_GeneratedCsr{GeneratedCsr: csr}})
+	if err != nil {
+		return err
+	}
+	req, err = stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.loaded[req.GetLoadCertificate().GetCertificateId()] = req.GetLoadCertificate()
+	err = stream.Send(&cert.RotateCertificateResponse{RotateResponse: &cert.RotateCertificateResponse_LoadCertificate{LoadCertificate: &cert.LoadCertificateResponse{}}})
+	if err != nil {
+		return err
+	}
+	req, err = stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.finalized = req.GetFinalizeRotation() != nil
+	return nil
+}
+
+func (s *fakeCertServer) GetCertificates(context.Context, *cert.GetCertificatesRequest) (*cert.GetCertificatesResponse, error) {
+	rsp := &cert.GetCertificatesResponse{}
+	for id, req := range s.loaded {
+		rsp.CertificateInfo = append(rsp.CertificateInfo, &cert.CertificateInfo{
+			CertificateId: id,
+			Certificate:   req.GetCertificate(),
+		})
+	}
+	return rsp, nil
+}
+
+func TestCertInstallRotate(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &fakeCertServer{loaded: make(map[string]*cert.LoadCertificateRequest)}
+	s := grpc.NewServer()
+	cert.RegisterCertificateManagementServer(s, srv)
+	gnmi.RegisterGNMIServer(s, &mutableCapabilitiesServer{rsp: &gnmi.CapabilityResponse{GNMIVersion: "0.10.0"}})
+	go s.Serve(l)
+	defer s.Stop()
+
+	ca, caPEM := testCertAuthority(t)
+	ca.bundle = [][]byte{caPEM}
+	a := New()
+	out := new(bytes.Buffer)
+	a.out = out
+	a.Config.LocalFlags.CertID = "gnmi-server"
+	insecure := true
+	tc := &types.TargetConfig{
+		Name:     "r1",
+		Address:  l.Addr().String(),
+		Insecure: &insecure,
+		Timeout:  5 * time.Second,
+	}
+	ctx := context.Background()
+
+	if err = a.certInstall(ctx, tc, ca); err != nil {
+		t.Fatal(err)
+	}
+	req := srv.loaded["gnmi-server"]
+	if req == nil {
+		t.Fatal("certificate not loaded")
+	}
+	if len(req.GetCaCertificates()) != 1 || !bytes.Equal(req.GetCaCertificates()[0].GetCertificate(), caPEM) {
+		t.Errorf("unexpected CA certificates: %v", req.GetCaCertificates())
+	}
+	block, _ := pem.Decode(req.GetCertificate().GetCertificate())
+	if block == nil {
+		t.Fatal("loaded certificate is not PEM encoded")
+	}
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crt.Subject.CommonName != "r1" || len(crt.IPAddresses) != 1 || !crt.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("unexpected certificate subject %q and IPs %v", crt.Subject, crt.IPAddresses)
+	}
+
+	if err = a.certRotate(ctx, tc, ca); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.finalized {
+		t.Error("rotation not finalized")
+	}
+	rotated, _ := pem.Decode(srv.loaded["gnmi-server"].GetCertificate().GetCertificate())
+	if bytes.Equal(rotated.Bytes, block.Bytes) {
+		t.Error("certificate not rotated")
+	}
+
+	out.Reset()
+	if err = a.certGet(ctx, tc); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`"id": "gnmi-server"`)) || !bytes.Contains(out.Bytes(), []byte(`"issuer": "CN=test-ca"`)) {
+		t.Errorf("unexpected get output:\n%s", out)
+	}
+}
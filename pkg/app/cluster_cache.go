@@ -0,0 +1,174 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openconfig/gnmic/pkg/lockers"
+)
+
+// targetCache is the content of the gNMI server cache for a single target,
+// grouped by subscription name.
+// Each notification is encoded in protojson.
+type targetCache map[string][]json.RawMessage
+
+func (a *App) handleTargetsCacheGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if a.c == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{"gNMI server cache is not enabled"}})
+		return
+	}
+	tc, err := a.readTargetCache(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	a.handlerCommonGet(w, tc)
+}
+
+// readTargetCache reads all the notifications of target `name`
+// from the local cache.
+func (a *App) readTargetCache(name string) (targetCache, error) {
+	return a.readCache("*", name, &gnmi.Path{})
+}
+
+// readCache reads the notifications of subscription `sub` and target `name`
+// under path p from the local cache.
+func (a *App) readCache(sub, name string, p *gnmi.Path) (targetCache, error) {
+	notifs, err := a.c.Read(sub, name, p)
+	if err != nil {
+		return nil, err
+	}
+	tc := make(targetCache, len(notifs))
+	for sub, ns := range notifs {
+		tc[sub] = make([]json.RawMessage, 0, len(ns))
+		for _, n := range ns {
+			b, err := protojson.Marshal(n)
+			if err != nil {
+				return nil, err
+			}
+			tc[sub] = append(tc[sub], b)
+		}
+	}
+	return tc, nil
+}
+
+// writeTargetCache writes the notifications in tc to the local cache
+// and returns the number of notifications written.
+func (a *App) writeTargetCache(ctx context.Context, tc targetCache) (int, error) {
+	count := 0
+	for sub, ns := range tc {
+		for _, b := range ns {
+			n := new(gnmi.Notification)
+			err := protojson.Unmarshal(b, n)
+			if err != nil {
+				return count, err
+			}
+			a.c.Write(ctx, sub, &gnmi.SubscribeResponse{
+				Response: &gnmi.SubscribeResponse_Update{Update: n},
+			})
+			count++
+		}
+	}
+	return count, nil
+}
+
+// bootstrapTargetCache fills the local cache with the notifications
+// of target `name` cached by the other cluster members.
+// It is called when this instance acquires the target lock, so that
+// ONCE subscriptions and Get requests served from the cache do not
+// see a gap while the new subscription to the target is established.
+func (a *App) bootstrapTargetCache(ctx context.Context, name string) {
+	if a.c == nil || a.Config.Clustering == nil || !a.Config.Clustering.CacheBootstrap {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.Config.Clustering.CacheBootstrapTimeout)
+	defer cancel()
+	serviceName := fmt.Sprintf("%s-%s", a.Config.Clustering.ClusterName, apiServiceName)
+	srvs, err := a.locker.GetServices(ctx, serviceName, []string{"cluster-name=" + a.Config.Clustering.ClusterName})
+	if err != nil {
+		a.Logger.Printf("failed to get cluster members to bootstrap target %q cache: %v", name, err)
+		return
+	}
+	selfID := a.Config.Clustering.InstanceName + "-api"
+	for _, s := range srvs {
+		if s.ID == selfID {
+			continue
+		}
+		tc, err := a.getPeerTargetCache(ctx, s, name)
+		if err != nil {
+			a.Logger.Printf("failed to get target %q cache from %q: %v", name, s.ID, err)
+			continue
+		}
+		count, err := a.writeTargetCache(ctx, tc)
+		if err != nil {
+			a.Logger.Printf("failed to write target %q cache from %q: %v", name, s.ID, err)
+		}
+		if count > 0 {
+			a.Logger.Printf("bootstrapped target %q cache with %d notification(s) from %q", name, count, s.ID)
+		}
+	}
+}
+
+// getPeerTargetCache queries the API server of cluster member s
+// for the cached notifications of target `name`.
+func (a *App) getPeerTargetCache(ctx context.Context, s *lockers.Service, name string) (targetCache, error) {
+	scheme := "http"
+	client := &http.Client{
+		Timeout: defaultHTTPClientTimeout,
+	}
+	for _, t := range s.Tags {
+		if strings.HasPrefix(t, "protocol=") {
+			scheme = strings.Split(t, "=")[1]
+			break
+		}
+	}
+	if scheme == "https" {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		}
+	}
+	url := fmt.Sprintf("%s://%s/api/v1/targets/%s/cache", scheme, s.Address, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code=%d", rsp.StatusCode)
+	}
+	tc := make(targetCache)
+	err = json.NewDecoder(rsp.Body).Decode(&tc)
+	if err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
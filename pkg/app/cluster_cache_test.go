@@ -0,0 +1,99 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/cache"
+	"github.com/openconfig/gnmic/pkg/lockers"
+)
+
+func TestTargetCacheBootstrap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newApp := func() *App {
+		a := New()
+		c, err := cache.New(nil)
+		if err != nil {
+			t.Fatalf("failed to create cache: %v", err)
+		}
+		a.c = c
+		return a
+	}
+	// peer holding the target cache
+	peer := newApp()
+	peer.c.Write(ctx, "sub1", &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: time.Now().UnixNano(),
+				Prefix:    &gnmi.Path{Target: "r1"},
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "r1"}},
+					},
+				},
+			},
+		},
+	})
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/targets/{id}/cache", peer.handleTargetsCacheGet).Methods(http.MethodGet)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	s := &lockers.Service{
+		ID:      "peer-api",
+		Address: strings.TrimPrefix(srv.URL, "http://"),
+		Tags:    []string{"protocol=http"},
+	}
+	a := newApp()
+	tc, err := a.getPeerTargetCache(ctx, s, "r1")
+	if err != nil {
+		t.Fatalf("failed to get peer target cache: %v", err)
+	}
+	count, err := a.writeTargetCache(ctx, tc)
+	if err != nil {
+		t.Fatalf("failed to write target cache: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 notification, got %d", count)
+	}
+	notifs, err := a.c.Read("sub1", "r1", &gnmi.Path{})
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if len(notifs["sub1"]) != 1 ||
+		notifs["sub1"][0].GetUpdate()[0].GetVal().GetStringVal() != "r1" {
+		t.Errorf("unexpected cache content: %v", notifs)
+	}
+
+	// unknown target: empty cache, no error
+	tc, err = a.getPeerTargetCache(ctx, s, "r2")
+	if err != nil {
+		t.Fatalf("failed to get peer target cache: %v", err)
+	}
+	if len(tc) != 0 {
+		t.Errorf("expected an empty cache, got %v", tc)
+	}
+	// peer without a cache
+	peer.c = nil
+	if _, err := a.getPeerTargetCache(ctx, s, "r1"); err == nil {
+		t.Errorf("expected an error from a peer without a cache")
+	}
+}
@@ -0,0 +1,439 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	gfile "github.com/openconfig/gnmic/pkg/file"
+)
+
+const (
+	// the generated configs share the targets list and rely on
+	// the clustering tags to pin each target to its instance.
+	clusterPlanModeCluster = "cluster"
+	// each generated config only contains the targets of its instance.
+	clusterPlanModeStandalone = "standalone"
+
+	defaultClusterPlanInstanceCapacity = 5000
+	defaultClusterPlanHeadroom         = 0.2
+	defaultClusterPlanInstancePrefix   = "gnmic"
+	clusterPlanShardTagPrefix          = "shard="
+)
+
+// plannedTarget is a target and its message rate, in notifications per second.
+type plannedTarget struct {
+	name     string
+	rate     float64
+	measured bool
+}
+
+// planShard is the set of targets assigned to a single gNMIc instance.
+type planShard struct {
+	instance string
+	targets  []*plannedTarget
+	rate     float64
+}
+
+type clusterPlanOptions struct {
+	// notifications per second a single instance can process.
+	capacity float64
+	// max number of targets per instance, 0 for no limit.
+	maxTargets int
+	// fraction of the capacity kept free.
+	headroom float64
+	// fixed number of instances, 0 to compute it.
+	instances int
+	prefix    string
+}
+
+// InitClusterPlanFlags used to init or reset clusterPlanCmd flags for gnmic-prompt mode
+func (a *App) InitClusterPlanFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ClusterPlanRates, "rates", "", "", "file or URL with the measured or estimated targets rates, in notifications per second")
+	cmd.Flags().Float64VarP(&a.Config.LocalFlags.ClusterPlanDefaultRate, "default-rate", "", 0, "rate of the targets missing from the rates file, defaults to the average measured rate")
+	cmd.Flags().Float64VarP(&a.Config.LocalFlags.ClusterPlanInstanceCapacity, "instance-capacity", "", defaultClusterPlanInstanceCapacity, "notifications per second a single instance can process")
+	cmd.Flags().IntVarP(&a.Config.LocalFlags.ClusterPlanMaxTargets, "max-targets", "", 0, "max number of targets per instance, 0 for no limit")
+	cmd.Flags().Float64VarP(&a.Config.LocalFlags.ClusterPlanHeadroom, "headroom", "", defaultClusterPlanHeadroom, "fraction of the instance capacity kept free, between 0 and 1")
+	cmd.Flags().IntVarP(&a.Config.LocalFlags.ClusterPlanInstances, "instances", "", 0, "number of instances to spread the targets over, computed from the rates if not set")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ClusterPlanMode, "mode", "", clusterPlanModeCluster, "generated configuration mode, one of: cluster, standalone")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ClusterPlanInstancePrefix, "instance-prefix", "", defaultClusterPlanInstancePrefix, "instances name prefix")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ClusterPlanOutputDir, "output-dir", "", "", "directory the per instance configuration files are written to, the plan is only printed if not set")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "cluster-plan", flag.Name), flag)
+	})
+}
+
+func (a *App) ClusterPlanPreRunE(cmd *cobra.Command, _ []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	switch a.Config.LocalFlags.ClusterPlanMode {
+	case clusterPlanModeCluster, clusterPlanModeStandalone:
+	default:
+		return fmt.Errorf("unknown mode %q, must be one of: %s, %s",
+			a.Config.LocalFlags.ClusterPlanMode, clusterPlanModeCluster, clusterPlanModeStandalone)
+	}
+	if a.Config.LocalFlags.ClusterPlanHeadroom < 0 || a.Config.LocalFlags.ClusterPlanHeadroom >= 1 {
+		return errors.New("headroom must be between 0 and 1")
+	}
+	if a.Config.LocalFlags.ClusterPlanInstanceCapacity <= 0 && a.Config.LocalFlags.ClusterPlanInstances <= 0 {
+		return errors.New("one of instance-capacity or instances must be set")
+	}
+	return nil
+}
+
+func (a *App) ClusterPlanRunE(cmd *cobra.Command, _ []string) error {
+	defer a.InitClusterPlanFlags(cmd)
+
+	targetsConfig, err := a.Config.GetTargets()
+	if err != nil {
+		return err
+	}
+	if len(targetsConfig) == 0 {
+		return errors.New("no targets found")
+	}
+	rates := make(map[string]float64)
+	if a.Config.LocalFlags.ClusterPlanRates != "" {
+		b, err := gfile.ReadFile(a.ctx, a.Config.LocalFlags.ClusterPlanRates)
+		if err != nil {
+			return fmt.Errorf("failed to read rates: %v", err)
+		}
+		rates, err = parseTargetsRates(b)
+		if err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(targetsConfig))
+	for n := range targetsConfig {
+		names = append(names, n)
+	}
+	targets, err := plannedTargets(names, rates, a.Config.LocalFlags.ClusterPlanDefaultRate)
+	if err != nil {
+		return err
+	}
+	opts := &clusterPlanOptions{
+		capacity:   a.Config.LocalFlags.ClusterPlanInstanceCapacity,
+		maxTargets: a.Config.LocalFlags.ClusterPlanMaxTargets,
+		headroom:   a.Config.LocalFlags.ClusterPlanHeadroom,
+		instances:  a.Config.LocalFlags.ClusterPlanInstances,
+		prefix:     a.Config.LocalFlags.ClusterPlanInstancePrefix,
+	}
+	shards, err := planShards(targets, opts)
+	if err != nil {
+		return err
+	}
+	printClusterPlan(os.Stdout, shards, opts)
+
+	if a.Config.LocalFlags.ClusterPlanOutputDir == "" {
+		return nil
+	}
+	doc, err := readYAMLConfigDoc(a.Config.FileConfig.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	addrs := targetsConfigAddresses(targetsConfig)
+	err = os.MkdirAll(a.Config.LocalFlags.ClusterPlanOutputDir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, s := range shards {
+		var idoc yaml.MapSlice
+		switch a.Config.LocalFlags.ClusterPlanMode {
+		case clusterPlanModeStandalone:
+			idoc = standaloneInstanceConfig(doc, s, addrs)
+		default:
+			idoc = clusterInstanceConfig(doc, s, shards, addrs)
+		}
+		b, err := yaml.Marshal(idoc)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(a.Config.LocalFlags.ClusterPlanOutputDir, s.instance+".yaml")
+		err = os.WriteFile(file, b, 0600)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("instance %q configuration written to %s\n", s.instance, file)
+	}
+	return nil
+}
+
+// parseTargetsRates parses a YAML or JSON map of target names to rates.
+// A rate is either a number or an object with a rate field,
+// such as the response of the /api/v1/targets/statistics endpoint.
+func parseTargetsRates(b []byte) (map[string]float64, error) {
+	raw := make(map[string]interface{})
+	err := yaml.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rates: %v", err)
+	}
+	rates := make(map[string]float64, len(raw))
+	for name, v := range raw {
+		if m, ok := v.(map[interface{}]interface{}); ok {
+			v = m["rate"]
+		}
+		switch v := v.(type) {
+		case int:
+			rates[name] = float64(v)
+		case float64:
+			rates[name] = v
+		default:
+			return nil, fmt.Errorf("target %q: invalid rate %v", name, v)
+		}
+		if rates[name] < 0 {
+			return nil, fmt.Errorf("target %q: invalid rate %v", name, v)
+		}
+	}
+	return rates, nil
+}
+
+// plannedTargets returns the targets names with their rate, the targets without
+// a rate get defaultRate, or the average of the known rates if defaultRate is 0.
+func plannedTargets(names []string, rates map[string]float64, defaultRate float64) ([]*plannedTarget, error) {
+	targets := make([]*plannedTarget, 0, len(names))
+	var known int
+	var total float64
+	for _, n := range names {
+		t := &plannedTarget{name: n}
+		t.rate, t.measured = rates[n]
+		if t.measured {
+			known++
+			total += t.rate
+		}
+		targets = append(targets, t)
+	}
+	if known < len(targets) && defaultRate <= 0 {
+		if known == 0 {
+			return nil, errors.New("no targets rates known, set a rates file or a default rate")
+		}
+		defaultRate = total / float64(known)
+	}
+	for _, t := range targets {
+		if !t.measured {
+			t.rate = defaultRate
+		}
+	}
+	return targets, nil
+}
+
+// instancesCount returns the number of instances needed to handle the targets
+// without exceeding the instance usable capacity nor the max number of targets.
+func instancesCount(targets []*plannedTarget, opts *clusterPlanOptions) int {
+	if opts.instances > 0 {
+		return opts.instances
+	}
+	var total float64
+	for _, t := range targets {
+		total += t.rate
+	}
+	n := int(math.Ceil(total / (opts.capacity * (1 - opts.headroom))))
+	if opts.maxTargets > 0 {
+		if m := (len(targets) + opts.maxTargets - 1) / opts.maxTargets; m > n {
+			n = m
+		}
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// planShards spreads the targets over the instances, the targets are assigned
+// in decreasing rate order to the least loaded instance that is not full.
+func planShards(targets []*plannedTarget, opts *clusterPlanOptions) ([]*planShard, error) {
+	n := instancesCount(targets, opts)
+	if opts.maxTargets > 0 && n*opts.maxTargets < len(targets) {
+		return nil, fmt.Errorf("%d instances cannot hold %d targets with max-targets=%d", n, len(targets), opts.maxTargets)
+	}
+	shards := make([]*planShard, 0, n)
+	for i := 0; i < n; i++ {
+		shards = append(shards, &planShard{instance: fmt.Sprintf("%s-%d", opts.prefix, i+1)})
+	}
+	sorted := make([]*plannedTarget, len(targets))
+	copy(sorted, targets)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].rate == sorted[j].rate {
+			return sorted[i].name < sorted[j].name
+		}
+		return sorted[i].rate > sorted[j].rate
+	})
+	for _, t := range sorted {
+		var selected *planShard
+		for _, s := range shards {
+			if opts.maxTargets > 0 && len(s.targets) >= opts.maxTargets {
+				continue
+			}
+			if selected == nil || s.rate < selected.rate ||
+				(s.rate == selected.rate && len(s.targets) < len(selected.targets)) {
+				selected = s
+			}
+		}
+		selected.targets = append(selected.targets, t)
+		selected.rate += t.rate
+	}
+	for _, s := range shards {
+		sort.Slice(s.targets, func(i, j int) bool {
+			return s.targets[i].name < s.targets[j].name
+		})
+	}
+	return shards, nil
+}
+
+func printClusterPlan(w io.Writer, shards []*planShard, opts *clusterPlanOptions) {
+	var total float64
+	var count int
+	tabData := make([][]string, 0, len(shards))
+	for _, s := range shards {
+		total += s.rate
+		count += len(s.targets)
+		load := "-"
+		if opts.capacity > 0 {
+			load = fmt.Sprintf("%.0f%%", 100*s.rate/opts.capacity)
+		}
+		tabData = append(tabData, []string{
+			s.instance,
+			fmt.Sprintf("%d", len(s.targets)),
+			fmt.Sprintf("%.1f", s.rate),
+			load,
+		})
+	}
+	fmt.Fprintf(w, "%d targets, %.1f notifications/s, %d instances\n", count, total, len(shards))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Instance", "Targets", "Rate (notif/s)", "Load"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(tabData)
+	table.Render()
+}
+
+// readYAMLConfigDoc reads the configuration file used, if any.
+func readYAMLConfigDoc(file string) (yaml.MapSlice, error) {
+	var doc yaml.MapSlice
+	if file == "" {
+		return doc, nil
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", "":
+	default:
+		return nil, fmt.Errorf("only YAML configuration files are supported: %q", file)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %v", file, err)
+	}
+	return doc, nil
+}
+
+func targetsConfigAddresses(tcs map[string]*types.TargetConfig) map[string]string {
+	addrs := make(map[string]string, len(tcs))
+	for n, tc := range tcs {
+		addrs[n] = tc.Address
+	}
+	return addrs
+}
+
+// targetDocEntry returns a copy of the configuration file entry of target name,
+// or an entry with its address if the target is not defined in the file.
+func targetDocEntry(doc yaml.MapSlice, name, address string) yaml.MapSlice {
+	if targets, ok := mapSliceValue(doc, "targets").(yaml.MapSlice); ok {
+		if entry, ok := mapSliceValue(targets, name).(yaml.MapSlice); ok {
+			return append(yaml.MapSlice{}, entry...)
+		}
+	}
+	if address == name {
+		return yaml.MapSlice{}
+	}
+	return yaml.MapSlice{{Key: "address", Value: address}}
+}
+
+// standaloneInstanceConfig returns the configuration of the instance of shard s,
+// only the shard targets are kept and the clustering section is removed.
+func standaloneInstanceConfig(doc yaml.MapSlice, s *planShard, addrs map[string]string) yaml.MapSlice {
+	targets := make(yaml.MapSlice, 0, len(s.targets))
+	for _, t := range s.targets {
+		targets = append(targets, yaml.MapItem{Key: t.name, Value: targetDocEntry(doc, t.name, addrs[t.name])})
+	}
+	idoc := make(yaml.MapSlice, 0, len(doc))
+	for _, item := range doc {
+		if fmt.Sprint(item.Key) != "clustering" {
+			idoc = append(idoc, item)
+		}
+	}
+	return setMapSliceValue(idoc, "targets", targets)
+}
+
+// clusterInstanceConfig returns the configuration of the instance of shard s,
+// all the targets are kept and tagged with their shard tag, the instance
+// clustering tags start with the shard tag.
+func clusterInstanceConfig(doc yaml.MapSlice, s *planShard, shards []*planShard, addrs map[string]string) yaml.MapSlice {
+	targets := make(yaml.MapSlice, 0)
+	for _, sh := range shards {
+		for _, t := range sh.targets {
+			entry := targetDocEntry(doc, t.name, addrs[t.name])
+			tags := []interface{}{clusterPlanShardTagPrefix + sh.instance}
+			if ts, ok := mapSliceValue(entry, "tags").([]interface{}); ok {
+				tags = append(tags, ts...)
+			}
+			targets = append(targets, yaml.MapItem{Key: t.name, Value: setMapSliceValue(entry, "tags", tags)})
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return fmt.Sprint(targets[i].Key) < fmt.Sprint(targets[j].Key)
+	})
+	clustering, _ := mapSliceValue(doc, "clustering").(yaml.MapSlice)
+	clustering = append(yaml.MapSlice{}, clustering...)
+	tags := []interface{}{clusterPlanShardTagPrefix + s.instance}
+	if ts, ok := mapSliceValue(clustering, "tags").([]interface{}); ok {
+		tags = append(tags, ts...)
+	}
+	clustering = setMapSliceValue(clustering, "instance-name", s.instance)
+	clustering = setMapSliceValue(clustering, "tags", tags)
+
+	idoc := setMapSliceValue(append(yaml.MapSlice{}, doc...), "targets", targets)
+	return setMapSliceValue(idoc, "clustering", clustering)
+}
+
+func mapSliceValue(ms yaml.MapSlice, k string) interface{} {
+	for _, item := range ms {
+		if fmt.Sprint(item.Key) == k {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+// setMapSliceValue sets ms[k] to v, keeping the position of k if it exists.
+func setMapSliceValue(ms yaml.MapSlice, k string, v interface{}) yaml.MapSlice {
+	for i, item := range ms {
+		if fmt.Sprint(item.Key) == k {
+			ms[i].Value = v
+			return ms
+		}
+	}
+	return append(ms, yaml.MapItem{Key: k, Value: v})
+}
@@ -0,0 +1,176 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseTargetsRates(t *testing.T) {
+	rates, err := parseTargetsRates([]byte(`{"r1": {"notifications": 10, "rate": 2.5}, "r2": 4}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["r1"] != 2.5 || rates["r2"] != 4 {
+		t.Fatalf("unexpected rates: %v", rates)
+	}
+	for _, in := range []string{`{"r1": "fast"}`, `{"r1": -1}`, `[1, 2]`} {
+		if _, err := parseTargetsRates([]byte(in)); err == nil {
+			t.Errorf("expected an error for %s", in)
+		}
+	}
+}
+
+func TestPlannedTargets(t *testing.T) {
+	targets, err := plannedTargets([]string{"r1", "r2", "r3"}, map[string]float64{"r1": 10, "r2": 30}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pt := range targets {
+		if pt.name == "r3" && (pt.rate != 20 || pt.measured) {
+			t.Fatalf("expected r3 to get the average rate, got %+v", pt)
+		}
+	}
+	_, err = plannedTargets([]string{"r1"}, nil, 0)
+	if err == nil {
+		t.Fatal("expected an error without any known rate")
+	}
+}
+
+func TestPlanShards(t *testing.T) {
+	rates := map[string]float64{"r1": 400, "r2": 300, "r3": 200, "r4": 100, "r5": 100, "r6": 100}
+	names := make([]string, 0, len(rates))
+	for n := range rates {
+		names = append(names, n)
+	}
+	targets, err := plannedTargets(names, rates, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		opts          *clusterPlanOptions
+		wantInstances int
+		wantMaxRate   float64
+		wantErr       bool
+	}{
+		"by_capacity": {
+			// usable capacity 500/s, 1200/s in total
+			opts:          &clusterPlanOptions{capacity: 625, headroom: 0.2, prefix: "gnmic"},
+			wantInstances: 3,
+			wantMaxRate:   400,
+		},
+		"by_max_targets": {
+			opts:          &clusterPlanOptions{capacity: 10000, maxTargets: 2, prefix: "gnmic"},
+			wantInstances: 3,
+			wantMaxRate:   500,
+		},
+		"fixed": {
+			opts:          &clusterPlanOptions{instances: 2, prefix: "gnmic"},
+			wantInstances: 2,
+			wantMaxRate:   600,
+		},
+		"fixed_too_small": {
+			opts:    &clusterPlanOptions{instances: 2, maxTargets: 2, prefix: "gnmic"},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			shards, err := planShards(targets, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(shards) != tt.wantInstances {
+				t.Fatalf("expected %d instances, got %d", tt.wantInstances, len(shards))
+			}
+			var count int
+			for _, s := range shards {
+				count += len(s.targets)
+				if s.rate > tt.wantMaxRate {
+					t.Errorf("instance %s rate %f above %f", s.instance, s.rate, tt.wantMaxRate)
+				}
+				if tt.opts.maxTargets > 0 && len(s.targets) > tt.opts.maxTargets {
+					t.Errorf("instance %s has %d targets", s.instance, len(s.targets))
+				}
+			}
+			if count != len(targets) {
+				t.Fatalf("expected %d targets, got %d", len(targets), count)
+			}
+		})
+	}
+}
+
+func TestClusterInstanceConfig(t *testing.T) {
+	var doc yaml.MapSlice
+	err := yaml.Unmarshal([]byte(`
+username: admin
+targets:
+  r1:
+    address: 10.0.0.1:57400
+    tags: [edge]
+  r2:
+clustering:
+  cluster-name: c1
+`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards := []*planShard{
+		{instance: "gnmic-1", targets: []*plannedTarget{{name: "r1"}}},
+		{instance: "gnmic-2", targets: []*plannedTarget{{name: "r2"}}},
+	}
+	addrs := map[string]string{"r1": "10.0.0.1:57400", "r2": "r2"}
+
+	b, err := yaml.Marshal(clusterInstanceConfig(doc, shards[1], shards, addrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `username: admin
+targets:
+  r1:
+    address: 10.0.0.1:57400
+    tags:
+    - shard=gnmic-1
+    - edge
+  r2:
+    tags:
+    - shard=gnmic-2
+clustering:
+  cluster-name: c1
+  instance-name: gnmic-2
+  tags:
+  - shard=gnmic-2
+`
+	if string(b) != want {
+		t.Fatalf("unexpected cluster config:\n%s\nwant:\n%s", b, want)
+	}
+
+	b, err = yaml.Marshal(standaloneInstanceConfig(doc, shards[0], addrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `username: admin
+targets:
+  r1:
+    address: 10.0.0.1:57400
+    tags:
+    - edge
+`
+	if string(b) != want {
+		t.Fatalf("unexpected standalone config:\n%s\nwant:\n%s", b, want)
+	}
+}
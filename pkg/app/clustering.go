@@ -74,13 +74,19 @@ func (a *App) inCluster() bool {
 }
 
 func (a *App) apiServiceRegistration() {
-	addr, port, _ := net.SplitHostPort(a.Config.APIServer.Address)
+	// the cluster members call each other's mutating endpoints,
+	// register the admin listener if configured.
+	apiAddr, apiTLS := a.Config.APIServer.Address, a.Config.APIServer.TLS
+	if a.Config.APIServer.Admin != nil {
+		apiAddr, apiTLS = a.Config.APIServer.Admin.Address, a.Config.APIServer.Admin.TLS
+	}
+	addr, port, _ := net.SplitHostPort(apiAddr)
 	p, _ := strconv.Atoi(port)
 
 	tags := make([]string, 0, 2+len(a.Config.Clustering.Tags))
 	tags = append(tags, fmt.Sprintf("cluster-name=%s", a.Config.Clustering.ClusterName))
 	tags = append(tags, fmt.Sprintf("instance-name=%s", a.Config.Clustering.InstanceName))
-	if a.Config.APIServer.TLS != nil {
+	if apiTLS != nil {
 		tags = append(tags, "protocol=https")
 	} else {
 		tags = append(tags, "protocol=http")
@@ -293,7 +299,7 @@ func (a *App) dispatchTargets(ctx context.Context) {
 }
 
 func (a *App) dispatchTarget(ctx context.Context, tc *types.TargetConfig) error {
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		a.Logger.Printf("checking if %q is locked", tc.Name)
 	}
 	key := fmt.Sprintf("gnmic/%s/targets/%s", a.Config.Clustering.ClusterName, tc.Name)
@@ -301,7 +307,7 @@ func (a *App) dispatchTarget(ctx context.Context, tc *types.TargetConfig) error
 	if err != nil {
 		return err
 	}
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		a.Logger.Printf("target %q is locked: %v", tc.Name, locked)
 	}
 	if locked {
@@ -436,7 +442,7 @@ func (a *App) getInstancesLoad(instances ...string) (map[string]int, error) {
 	if err != nil {
 		return nil, err
 	}
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		a.Logger.Println("current locks:", locks)
 	}
 	load := make(map[string]int)
@@ -488,7 +494,7 @@ func (a *App) getTargetToInstanceMapping() (map[string]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		a.Logger.Println("current locks:", locks)
 	}
 	for k, v := range locks {
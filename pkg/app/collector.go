@@ -13,8 +13,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"google.golang.org/protobuf/proto"
@@ -22,6 +24,7 @@ import (
 	"github.com/openconfig/gnmic/pkg/api/target"
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
 	"github.com/openconfig/gnmic/pkg/outputs"
 )
 
@@ -38,7 +41,7 @@ func (a *App) StartCollector(ctx context.Context) {
 	}()
 
 	for t := range a.targetsChan {
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			a.Logger.Printf("starting target %+v", t)
 		}
 		if t == nil {
@@ -48,7 +51,7 @@ func (a *App) StartCollector(ctx context.Context) {
 		_, ok := a.activeTargets[t.Config.Name]
 		a.operLock.RUnlock()
 		if ok {
-			if a.Config.Debug {
+			if a.debugEnabled() {
 				a.Logger.Printf("target %q listener already active", t.Config.Name)
 			}
 			continue
@@ -63,11 +66,77 @@ func (a *App) StartCollector(ctx context.Context) {
 			remainingOnceSubscriptions := numOnceSubscriptions
 			numSubscriptions := len(t.Subscriptions)
 			rspChan, errChan := t.ReadSubscriptions()
+			ts := a.initSyncStates(t, time.Now())
+			stats := a.targetStats(t.Config.Name)
+			defer a.deleteSyncStates(t.Config.Name, ts)
+			// absence alarms
+			var absenceCheck <-chan time.Time
+			am := newAbsenceMonitor(t, time.Now())
+			if am != nil {
+				ticker := time.NewTicker(am.checkInterval())
+				defer ticker.Stop()
+				defer am.stop()
+				absenceCheck = ticker.C
+			}
+			// sequence number of the last response exported
+			// when the target has ordered delivery enabled.
+			var seq uint64
+			// priority classes
+			ps := newPriorityShedder(t.Config.Name)
+			defer ps.stop()
+			// response validation
+			rv := newResponseValidator(t)
+			if rv != nil {
+				defer rv.stop()
+			}
+			// neighborhood subscriptions
+			nb := newNeighborhood(t)
+			defer nb.stop()
+			// telemetry budget
+			bm := newBudgetMonitor(t)
+			bctx := ctx
+			if bm != nil {
+				var cancel context.CancelFunc
+				bctx, cancel = context.WithCancel(ctx)
+				defer cancel()
+				defer bm.stop()
+			}
 			for {
 				select {
+				case now := <-absenceCheck:
+					a.exportAbsenceEvents(ctx, am, now, am.check(now)...)
 				case rsp := <-rspChan:
 					subscribeResponseReceivedCounter.WithLabelValues(t.Config.Name, rsp.SubscriptionConfig.Name).Add(1)
-					if a.Config.Debug {
+					stats.received(rsp.Response, time.Now())
+					if rv != nil {
+						enc := a.subscriptionEncoding(t.Config, rsp.SubscriptionConfig)
+						if vs := rv.validate(rsp.Response, enc, time.Now()); len(vs) > 0 {
+							outs := rsp.SubscriptionConfig.Outputs
+							if len(outs) == 0 {
+								outs = t.Config.Outputs
+							}
+							a.reportViolations(ctx, rv, rsp.SubscriptionName, outs, vs)
+							if rv.cfg.Drop {
+								continue
+							}
+						}
+					}
+					if am != nil {
+						now := time.Now()
+						if st := am.seen(rsp.SubscriptionName, now); st != nil {
+							a.exportAbsenceEvents(ctx, am, now, st)
+						}
+					}
+					if rsp.Response.GetSyncResponse() {
+						if st := a.syncReceived(t.Config.Name, rsp.SubscriptionName, time.Now()); st != nil {
+							a.exportSyncEvent(ctx, st)
+						}
+					}
+					a.handleNeighborhood(ctx, nb, rsp)
+					if ps.shed(rsp.SubscriptionConfig.Priority, rsp.Response, bufferLevel(rspChan)) {
+						continue
+					}
+					if a.debugEnabled() {
 						a.Logger.Printf("target %q: gNMI Subscribe Response: %+v", t.Config.Name, rsp)
 					}
 					err := t.DecodeProtoBytes(rsp.Response)
@@ -75,17 +144,44 @@ func (a *App) StartCollector(ctx context.Context) {
 						a.Logger.Printf("target %q: failed to decode proto bytes: %v", t.Config.Name, err)
 						continue
 					}
+					var throttled bool
+					if bm != nil {
+						now := time.Now()
+						exceeded, first := bm.add(proto.Size(rsp.Response), now)
+						if exceeded && first {
+							a.Logger.Printf("target %q: telemetry budget exceeded, action=%s", t.Config.Name, bm.cfg.Action)
+						}
+						switch {
+						case !exceeded:
+						case bm.cfg.Action == types.BudgetActionDrop:
+							continue
+						case bm.cfg.Action == types.BudgetActionTag:
+							throttled = true
+						case bm.cfg.Action == types.BudgetActionRaiseInterval && first:
+							if bm.canRaise(now) {
+								a.raiseSampleIntervals(bctx, t)
+							}
+						}
+					}
 					m := outputs.Meta{
 						"source":            t.Config.Name,
 						"format":            a.Config.Format,
 						"subscription-name": rsp.SubscriptionName,
 					}
+					if throttled {
+						m[budgetThrottledMetaKey] = "true"
+					}
 					if rsp.SubscriptionConfig.Target != "" {
 						m["subscription-target"] = rsp.SubscriptionConfig.Target
 					}
-					for k, v := range t.Config.EventTags {
-						m[k] = v
+					if t.Config.OrderedDelivery {
+						seq++
+						m[formatters.MetaSequenceNumber] = strconv.FormatUint(seq, 10)
 					}
+					addTargetMeta(m, t.Config)
+					a.addTargetPlatformMeta(m, t.Config)
+					addOutputOptionsMeta(m, t.Config, rsp.SubscriptionConfig)
+					a.rewriteTarget(ctx, t, rsp.Response, m)
 
 					// Allow overridden outputs per subscription
 					// If both target and subscription have a specified Output, the subscription's Output will be used
@@ -96,7 +192,10 @@ func (a *App) StartCollector(ctx context.Context) {
 						outs = t.Config.Outputs
 					}
 
-					if a.subscriptionMode(rsp.SubscriptionName) == subscriptionModeONCE {
+					// the responses of a target with ordered delivery are exported
+					// by this goroutine, the next one is read once all the outputs
+					// accepted the current one.
+					if t.Config.OrderedDelivery || a.subscriptionMode(rsp.SubscriptionName) == subscriptionModeONCE {
 						a.Export(ctx, rsp.Response, m, outs...)
 					} else {
 						go a.Export(ctx, rsp.Response, m, outs...)
@@ -116,11 +215,14 @@ func (a *App) StartCollector(ctx context.Context) {
 						return
 					}
 				case tErr := <-errChan:
+					stats.failed()
 					if errors.Is(tErr.Err, io.EOF) {
 						a.Logger.Printf("target %q: subscription %s closed stream(EOF)", t.Config.Name, tErr.SubscriptionName)
 					} else {
 						a.Logger.Printf("target %q: subscription %s rcv error: %v", t.Config.Name, tErr.SubscriptionName, tErr.Err)
 					}
+					a.refreshTargetHostname(t.Config.Name)
+					a.syncLost(t.Config.Name, tErr.SubscriptionName, time.Now())
 					if remainingOnceSubscriptions > 0 {
 						if a.subscriptionMode(tErr.SubscriptionName) == subscriptionModeONCE {
 							remainingOnceSubscriptions--
@@ -152,10 +254,24 @@ func (a *App) StartCollector(ctx context.Context) {
 	}
 }
 
+// Export writes the subscribe response rsp to the outputs outs,
+// or to all the outputs if outs is empty, after applying the
+// configured fault injection.
 func (a *App) Export(ctx context.Context, rsp *gnmi.SubscribeResponse, m outputs.Meta, outs ...string) {
 	if rsp == nil {
 		return
 	}
+	if a.faultInjector != nil {
+		for i := a.faultInjector.Inject(ctx); i > 0; i-- {
+			a.export(ctx, rsp, m, outs...)
+		}
+		return
+	}
+	a.export(ctx, rsp, m, outs...)
+}
+
+func (a *App) export(ctx context.Context, rsp *gnmi.SubscribeResponse, m outputs.Meta, outs ...string) {
+	a.addInstanceMeta(m)
 	go a.updateCache(ctx, rsp, m)
 	wg := new(sync.WaitGroup)
 	// target has no outputs explicitly defined
@@ -187,6 +303,30 @@ func (a *App) Export(ctx context.Context, rsp *gnmi.SubscribeResponse, m outputs
 	wg.Wait()
 }
 
+// exportEvent writes an event message to the outputs outs,
+// or to all the outputs if outs is empty.
+func (a *App) exportEvent(ctx context.Context, ev *formatters.EventMsg, outs ...string) {
+	if len(a.Config.InstanceLabels) > 0 {
+		if ev.Tags == nil {
+			ev.Tags = make(map[string]string, len(a.Config.InstanceLabels))
+		}
+		a.addInstanceMeta(ev.Tags)
+	}
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	if len(outs) == 0 {
+		for _, o := range a.Outputs {
+			o.WriteEvent(ctx, ev)
+		}
+		return
+	}
+	for _, name := range outs {
+		if o, ok := a.Outputs[name]; ok {
+			o.WriteEvent(ctx, ev)
+		}
+	}
+}
+
 func (a *App) updateCache(ctx context.Context, rsp *gnmi.SubscribeResponse, m outputs.Meta) {
 	if a.c == nil {
 		return
@@ -205,9 +345,10 @@ func (a *App) updateCache(ctx context.Context, rsp *gnmi.SubscribeResponse, m ou
 			a.Logger.Printf("response missing target")
 			return
 		}
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			a.Logger.Printf("updating target %q cache", target)
 		}
+		a.collectorExt.Received(target, m["source"])
 		sub := m["subscription-name"]
 		a.c.Write(ctx, sub, &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: r.Update}})
 	}
@@ -250,6 +391,7 @@ func (a *App) CreateTarget(name string) error {
 	if tc, ok := a.Config.Targets[name]; ok {
 		if _, ok := a.Targets[name]; !ok {
 			a.operLock.Lock()
+			a.setTLSSessionCache(tc)
 			a.Targets[tc.Name] = target.NewTarget(tc)
 			a.operLock.Unlock()
 		}
@@ -257,3 +399,83 @@ func (a *App) CreateTarget(name string) error {
 	}
 	return fmt.Errorf("unknown target %q", name)
 }
+
+// InitCollector prepares the collector to be started without going through
+// the subscribe command, it is used when gNMIc is embedded in another program.
+func (a *App) InitCollector() {
+	a.Config.FileConfig.SetDefault("port", defaultGrpcPort)
+	if a.Config.Encoding == "" {
+		a.Config.Encoding = "json"
+	}
+	if a.Config.Timeout <= 0 {
+		a.Config.Timeout = 10 * time.Second
+	}
+	if a.Config.Retry <= 0 {
+		a.Config.Retry = defaultRetryTimer
+	}
+	if a.Config.MaxMsgSize <= 0 {
+		a.Config.MaxMsgSize = msgSize
+	}
+	a.createCollectorDialOpts()
+}
+
+// AddSubscriptionConfig validates and adds a *SubscriptionConfig to the configuration map
+func (a *App) AddSubscriptionConfig(sc *types.SubscriptionConfig) error {
+	err := a.Config.SetSubscriptionConfigDefaults(sc)
+	if err != nil {
+		return err
+	}
+	a.configLock.Lock()
+	defer a.configLock.Unlock()
+	if _, ok := a.Config.Subscriptions[sc.Name]; ok {
+		return fmt.Errorf("subscription %q already exists", sc.Name)
+	}
+	a.Config.Subscriptions[sc.Name] = sc
+	return nil
+}
+
+// AddOutput adds an initialized output to the collector outputs,
+// replacing any existing output with the same name.
+func (a *App) AddOutput(name string, o outputs.Output) {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	if old, ok := a.Outputs[name]; ok {
+		old.Close()
+	}
+	a.Outputs[name] = o
+}
+
+// CreateTargetConfig adds a *TargetConfig to the configuration map,
+// it fails if a target with the same name already exists.
+func (a *App) CreateTargetConfig(tc *types.TargetConfig) error {
+	if !a.addTargetConfig(tc) {
+		return fmt.Errorf("target %q already exists", tc.Name)
+	}
+	a.Logger.Printf("added target %s", tc)
+	return nil
+}
+
+// CloseOutputs closes and removes all the outputs.
+func (a *App) CloseOutputs() {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	for name, o := range a.Outputs {
+		if err := o.Close(); err != nil {
+			a.Logger.Printf("failed to close output %q: %v", name, err)
+		}
+		delete(a.Outputs, name)
+	}
+}
+
+// StartTarget starts the stream subscriptions of a target
+// present in the configuration map.
+func (a *App) StartTarget(ctx context.Context, name string) error {
+	a.configLock.RLock()
+	tc, ok := a.Config.Targets[name]
+	a.configLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown target %q", name)
+	}
+	go a.TargetSubscribeStream(ctx, tc)
+	return nil
+}
@@ -0,0 +1,360 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// configETag returns the entity tag of the configuration object v,
+// it changes whenever one of the object fields does.
+func configETag(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// configPreconditionsMet evaluates the If-Match and If-None-Match headers of r
+// against etag, the entity tag of the current object, empty if it does not exist.
+func configPreconditionsMet(r *http.Request, etag string) bool {
+	if im := r.Header.Get("If-Match"); im != "" {
+		if etag == "" {
+			return false
+		}
+		if !etagListContains(im, etag) {
+			return false
+		}
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etag != "" {
+		if etagListContains(inm, etag) {
+			return false
+		}
+	}
+	return true
+}
+
+// etagListContains reports whether the comma separated list of entity tags
+// in a If-Match or If-None-Match header matches etag.
+func etagListContains(list, etag string) bool {
+	for _, t := range strings.Split(list, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writePreconditionFailed(w http.ResponseWriter, kind, id string) {
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("%s %q does not match the request preconditions", kind, id)}})
+}
+
+// writeConfigObject writes the configuration object v and its entity tag,
+// with status 201 if the object was created, 200 otherwise.
+func writeConfigObject(w http.ResponseWriter, v interface{}, created bool) {
+	w.Header().Set("ETag", configETag(v))
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeConfigObject decodes the request body into v.
+// It writes the error response and returns false on failure.
+func decodeConfigObject(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBadRequest(w, err)
+		return false
+	}
+	defer r.Body.Close()
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		writeBadRequest(w, err)
+		return false
+	}
+	return true
+}
+
+func writeBadRequest(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+}
+
+// targets
+
+func (a *App) handleConfigTargetsPut(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	tc := new(types.TargetConfig)
+	if !decodeConfigObject(w, r, tc) {
+		return
+	}
+	if tc.Name == "" {
+		tc.Name = id
+	}
+	if tc.Name != id {
+		writeBadRequest(w, fmt.Errorf("target name %q does not match the request path", tc.Name))
+		return
+	}
+	if tc.Address == "" {
+		tc.Address = id
+	}
+	err := a.Config.SetTargetConfigDefaults(tc)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	a.configLock.Lock()
+	current, exists := a.Config.Targets[id]
+	var etag string
+	if exists {
+		etag = configETag(current)
+	}
+	if !configPreconditionsMet(r, etag) {
+		a.configLock.Unlock()
+		writePreconditionFailed(w, "target", id)
+		return
+	}
+	if exists && configETag(tc) == etag {
+		a.configLock.Unlock()
+		writeConfigObject(w, current, false)
+		return
+	}
+	a.Config.Targets[id] = tc
+	a.configLock.Unlock()
+	a.Logger.Printf("target %q config set", id)
+	if exists {
+		a.restartTarget(id, tc)
+	}
+	writeConfigObject(w, tc, !exists)
+}
+
+// restartTarget restarts the subscriptions of target name with config tc,
+// if the target is running on this instance and not in a cluster.
+func (a *App) restartTarget(name string, tc *types.TargetConfig) {
+	if a.inCluster() {
+		return
+	}
+	a.operLock.RLock()
+	_, running := a.Targets[name]
+	a.operLock.RUnlock()
+	if !running {
+		return
+	}
+	a.Logger.Printf("restarting target %q", name)
+	err := a.stopTarget(a.Context(), name)
+	if err != nil {
+		a.Logger.Printf("failed to stop target %q: %v", name, err)
+		return
+	}
+	go a.TargetSubscribeStream(a.Context(), tc)
+}
+
+// subscriptions
+
+func (a *App) handleConfigSubscriptionsGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	a.configLock.RLock()
+	sc, ok := a.Config.Subscriptions[id]
+	a.configLock.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("subscription %q not found", id)}})
+		return
+	}
+	writeConfigObject(w, sc, false)
+}
+
+func (a *App) handleConfigSubscriptionsPut(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	sc := new(types.SubscriptionConfig)
+	if !decodeConfigObject(w, r, sc) {
+		return
+	}
+	if sc.Name == "" {
+		sc.Name = id
+	}
+	if sc.Name != id {
+		writeBadRequest(w, fmt.Errorf("subscription name %q does not match the request path", sc.Name))
+		return
+	}
+	err := a.Config.SetSubscriptionConfigDefaults(sc)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	a.configLock.Lock()
+	current, exists := a.Config.Subscriptions[id]
+	var etag string
+	if exists {
+		etag = configETag(current)
+	}
+	if !configPreconditionsMet(r, etag) {
+		a.configLock.Unlock()
+		writePreconditionFailed(w, "subscription", id)
+		return
+	}
+	if exists && configETag(sc) == etag {
+		a.configLock.Unlock()
+		writeConfigObject(w, current, false)
+		return
+	}
+	a.Config.Subscriptions[id] = sc
+	a.configLock.Unlock()
+	a.Logger.Printf("subscription %q config set", id)
+	if exists {
+		// restart the targets using the previous subscription config
+		for _, name := range a.subscriptionRunningTargets(id) {
+			a.configLock.RLock()
+			tc, ok := a.Config.Targets[name]
+			a.configLock.RUnlock()
+			if ok {
+				a.restartTarget(name, tc)
+			}
+		}
+	}
+	writeConfigObject(w, sc, !exists)
+}
+
+// subscriptionRunningTargets returns the names of the running targets
+// using subscription name.
+func (a *App) subscriptionRunningTargets(name string) []string {
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	names := make([]string, 0)
+	for tn, t := range a.Targets {
+		if _, ok := t.Subscriptions[name]; ok {
+			names = append(names, tn)
+		}
+	}
+	return names
+}
+
+func (a *App) handleConfigSubscriptionsDelete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	a.configLock.Lock()
+	defer a.configLock.Unlock()
+	sc, ok := a.Config.Subscriptions[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("subscription %q not found", id)}})
+		return
+	}
+	if !configPreconditionsMet(r, configETag(sc)) {
+		writePreconditionFailed(w, "subscription", id)
+		return
+	}
+	for _, tc := range a.Config.Targets {
+		for _, s := range tc.Subscriptions {
+			if s == id {
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("subscription %q is used by target %q", id, tc.Name)}})
+				return
+			}
+		}
+	}
+	delete(a.Config.Subscriptions, id)
+	a.Logger.Printf("subscription %q deleted from config", id)
+}
+
+// outputs
+
+func (a *App) handleConfigOutputsGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	a.configLock.RLock()
+	cfg, ok := a.Config.Outputs[id]
+	a.configLock.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("output %q not found", id)}})
+		return
+	}
+	writeConfigObject(w, cfg, false)
+}
+
+func (a *App) handleConfigOutputsPut(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	cfg := make(map[string]interface{})
+	if !decodeConfigObject(w, r, &cfg) {
+		return
+	}
+	err := a.Config.SetOutputConfigDefaults(cfg)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	a.configLock.Lock()
+	current, exists := a.Config.Outputs[id]
+	var etag string
+	if exists {
+		etag = configETag(current)
+	}
+	if !configPreconditionsMet(r, etag) {
+		a.configLock.Unlock()
+		writePreconditionFailed(w, "output", id)
+		return
+	}
+	if exists && configETag(cfg) == etag {
+		a.configLock.Unlock()
+		writeConfigObject(w, current, false)
+		return
+	}
+	a.Config.Outputs[id] = cfg
+	a.configLock.Unlock()
+	a.Logger.Printf("output %q config set", id)
+	// (re)start the output with its new config
+	a.operLock.RLock()
+	_, running := a.Outputs[id]
+	a.operLock.RUnlock()
+	if running {
+		a.DeleteOutput(id)
+	}
+	a.InitOutput(a.Context(), id, a.Config.Targets)
+	writeConfigObject(w, cfg, !exists)
+}
+
+func (a *App) handleConfigOutputsDelete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	a.configLock.Lock()
+	cfg, ok := a.Config.Outputs[id]
+	if !ok {
+		a.configLock.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("output %q not found", id)}})
+		return
+	}
+	if !configPreconditionsMet(r, configETag(cfg)) {
+		a.configLock.Unlock()
+		writePreconditionFailed(w, "output", id)
+		return
+	}
+	delete(a.Config.Outputs, id)
+	a.configLock.Unlock()
+	a.Logger.Printf("output %q deleted from config", id)
+	a.operLock.RLock()
+	_, running := a.Outputs[id]
+	a.operLock.RUnlock()
+	if running {
+		a.DeleteOutput(id)
+	}
+}
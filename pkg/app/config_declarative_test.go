@@ -0,0 +1,114 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestConfigTargetsPut(t *testing.T) {
+	a := New()
+	put := func(body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/config/targets/r1", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "r1"})
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		a.handleConfigTargetsPut(rec, req)
+		return rec
+	}
+	rec := put(`{"address": "r1:57400"}`, map[string]string{"If-None-Match": "*"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+	// same object, no change
+	rec = put(`{"address": "r1:57400"}`, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != etag {
+		t.Fatalf("expected an unchanged object, got %d, etag %s", rec.Code, rec.Header().Get("ETag"))
+	}
+	// create only
+	rec = put(`{"address": "r1:57400"}`, map[string]string{"If-None-Match": "*"})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+	// replace
+	rec = put(`{"address": "r1:57401"}`, map[string]string{"If-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("expected the ETag to change")
+	}
+	if got := a.Config.Targets["r1"].Address; got != "r1:57401" {
+		t.Fatalf("unexpected address %q", got)
+	}
+	// stale ETag
+	rec = put(`{"address": "r1:57402"}`, map[string]string{"If-Match": etag})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+	// name mismatch
+	rec = put(`{"name": "r2", "address": "r1:57400"}`, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+}
+
+func TestConfigSubscriptionsPutDelete(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/config/subscriptions/sub1",
+		strings.NewReader(`{"paths": ["/interfaces"], "sample-interval": 10000000000}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "sub1"})
+	rec := httptest.NewRecorder()
+	a.handleConfigSubscriptionsPut(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	etag := rec.Header().Get("ETag")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/config/subscriptions/sub1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "sub1"})
+	rec = httptest.NewRecorder()
+	a.handleConfigSubscriptionsGet(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != etag {
+		t.Fatalf("unexpected response %d, etag %s", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	del := func() int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/config/subscriptions/sub1", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "sub1"})
+		req.Header.Set("If-Match", etag)
+		rec := httptest.NewRecorder()
+		a.handleConfigSubscriptionsDelete(rec, req)
+		return rec.Code
+	}
+	a.Config.Targets["r1"] = &types.TargetConfig{Name: "r1", Subscriptions: []string{"sub1"}}
+	if code := del(); code != http.StatusConflict {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	delete(a.Config.Targets, "r1")
+	if code := del(); code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	if _, ok := a.Config.Subscriptions["sub1"]; ok {
+		t.Fatal("subscription not deleted")
+	}
+}
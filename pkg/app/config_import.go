@@ -0,0 +1,91 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// InitConfigImportFlags used to init or reset configImportCmd flags for gnmic-prompt mode
+func (a *App) InitConfigImportFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigImportFrom, "from", "", "", "collector the configuration is imported from, one of: telegraf, pipeline")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigImportFile, "file", "", "", "path to the configuration file to import")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigImportFileType, "file-type", "", "", "imported file type, one of: toml, ini, yaml, json. Derived from the file extension if not set")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigImportOutputFile, "output-file", "", "", "path to the file the gNMIc configuration is written to, defaults to stdout")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "config-import", flag.Name), flag)
+	})
+}
+
+func (a *App) ConfigImportRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitConfigImportFlags(cmd)
+
+	switch a.Config.LocalFlags.ConfigImportFrom {
+	case config.ImportFormatTelegraf, config.ImportFormatPipeline:
+	default:
+		return fmt.Errorf("unsupported import source %q", a.Config.LocalFlags.ConfigImportFrom)
+	}
+	f, err := os.Open(a.Config.LocalFlags.ConfigImportFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ic, err := config.ImportConfig(a.Config.LocalFlags.ConfigImportFrom,
+		importFileType(a.Config.LocalFlags.ConfigImportFile, a.Config.LocalFlags.ConfigImportFileType),
+		f)
+	if err != nil {
+		return fmt.Errorf("failed to import %q: %v", a.Config.LocalFlags.ConfigImportFile, err)
+	}
+	for _, w := range ic.Warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", w)
+	}
+	b, err := yaml.Marshal(ic)
+	if err != nil {
+		return err
+	}
+	if a.Config.LocalFlags.ConfigImportOutputFile == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(a.Config.LocalFlags.ConfigImportOutputFile, b, 0644)
+}
+
+// importFileType returns the viper config type of the imported file,
+// an empty string lets config.ImportConfig pick the source collector default.
+func importFileType(name, fileType string) string {
+	if fileType != "" {
+		return fileType
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".toml":
+		return "toml"
+	case ".ini":
+		return "ini"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const targetConnectionEventName = "target_connection"
+
+// dial attempts results
+const (
+	dialResultSuccess      = "success"
+	dialResultTimeout      = "timeout"
+	dialResultTLSHandshake = "tls_handshake_error"
+	dialResultError        = "error"
+)
+
+var targetConnState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "target",
+	Name:      "connection_state",
+	Help:      "gRPC connection state of the target: 0=IDLE, 1=CONNECTING, 2=READY, 3=TRANSIENT_FAILURE, 4=SHUTDOWN",
+}, []string{"source"})
+
+var targetConnStateTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "target",
+	Name:      "connection_state_transitions_total",
+	Help:      "Total number of gRPC connection state changes of the target, by new state",
+}, []string{"source", "state"})
+
+var targetDialDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "target",
+	Name:      "dial_duration_seconds",
+	Help:      "Duration of the last attempt to connect to the target",
+}, []string{"source"})
+
+var targetDialAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "target",
+	Name:      "number_of_dial_attempts_total",
+	Help:      "Total number of attempts to connect to the target, by result",
+}, []string{"source", "result"})
+
+var targetTLSHandshakeErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "target",
+	Name:      "number_of_tls_handshake_errors_total",
+	Help:      "Total number of failed TLS handshakes with the target",
+}, []string{"source"})
+
+// handshakeRecorder counts the failed TLS handshakes of a target
+// and keeps the last error, so that it is reported instead of the dial timeout.
+type handshakeRecorder struct {
+	name    string
+	m       *sync.Mutex
+	lastErr error
+}
+
+func (h *handshakeRecorder) record(authority string, err error) {
+	if err == nil {
+		return
+	}
+	targetTLSHandshakeErrors.WithLabelValues(h.name).Inc()
+	h.m.Lock()
+	h.lastErr = fmt.Errorf("TLS handshake with %s failed: %w", authority, err)
+	h.m.Unlock()
+}
+
+// takeErr returns the last handshake error and clears it.
+func (h *handshakeRecorder) takeErr() error {
+	h.m.Lock()
+	defer h.m.Unlock()
+	err := h.lastErr
+	h.lastErr = nil
+	return err
+}
+
+// dialTarget creates the target gRPC client, recording the dial duration and result.
+// If the dial fails because of a TLS handshake error, that error is returned.
+func (a *App) dialTarget(ctx context.Context, t *target.Target, opts ...grpc.DialOption) error {
+	name := t.Config.Name
+	hs := &handshakeRecorder{name: name, m: new(sync.Mutex)}
+	t.SetHandshakeHook(hs.record)
+	start := time.Now()
+	err := t.CreateGNMIClient(ctx, opts...)
+	d := time.Since(start)
+	targetDialDuration.WithLabelValues(name).Set(d.Seconds())
+	if err == nil {
+		hs.takeErr()
+		targetDialAttempts.WithLabelValues(name, dialResultSuccess).Inc()
+		targetConnState.WithLabelValues(name).Set(float64(connectivity.Ready))
+		return nil
+	}
+	result := dialResultError
+	if errors.Is(err, context.DeadlineExceeded) {
+		result = dialResultTimeout
+	}
+	if hsErr := hs.takeErr(); hsErr != nil {
+		result = dialResultTLSHandshake
+		err = hsErr
+	}
+	targetDialAttempts.WithLabelValues(name, result).Inc()
+	targetConnState.WithLabelValues(name).Set(float64(connectivity.TransientFailure))
+	if t.Config.ConnectionEvents {
+		a.exportEvent(ctx, dialFailureEvent(name, result, d, err, time.Now()), t.Config.Outputs...)
+	}
+	return err
+}
+
+// watchTargetConnState records the target gRPC connection state changes
+// until ctx is done.
+func (a *App) watchTargetConnState(ctx context.Context, t *target.Target) {
+	name := t.Config.Name
+	t.WatchConnState(ctx, func(from, to connectivity.State) {
+		targetConnState.WithLabelValues(name).Set(float64(to))
+		targetConnStateTransitions.WithLabelValues(name, to.String()).Inc()
+		a.Logger.Printf("target %q: gRPC connection state changed from %s to %s", name, from, to)
+		if t.Config.ConnectionEvents {
+			a.exportEvent(ctx, connStateEvent(name, from, to, time.Now()), t.Config.Outputs...)
+		}
+	})
+}
+
+// deleteTargetConnMetrics removes the connection metrics of target name.
+func deleteTargetConnMetrics(name string) {
+	labels := prometheus.Labels{"source": name}
+	targetConnState.DeletePartialMatch(labels)
+	targetConnStateTransitions.DeletePartialMatch(labels)
+	targetDialDuration.DeletePartialMatch(labels)
+	targetDialAttempts.DeletePartialMatch(labels)
+	targetTLSHandshakeErrors.DeletePartialMatch(labels)
+}
+
+func connStateEvent(name string, from, to connectivity.State, ts time.Time) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      targetConnectionEventName,
+		Timestamp: ts.UnixNano(),
+		Tags: map[string]string{
+			"source": name,
+			"event":  "state-change",
+		},
+		Values: map[string]interface{}{
+			"state":          to.String(),
+			"previous-state": from.String(),
+		},
+	}
+}
+
+func dialFailureEvent(name, result string, d time.Duration, err error, ts time.Time) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      targetConnectionEventName,
+		Timestamp: ts.UnixNano(),
+		Tags: map[string]string{
+			"source": name,
+			"event":  "dial-failure",
+		},
+		Values: map[string]interface{}{
+			"result":                result,
+			"error":                 err.Error(),
+			"dial-duration-seconds": d.Seconds(),
+		},
+	}
+}
@@ -0,0 +1,131 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+)
+
+// tlsGNMIServer starts a gNMI server using a self signed certificate.
+func tlsGNMIServer(t *testing.T) (string, *grpc.Server) {
+	t.Helper()
+	cert, err := utils.SelfSignedCerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+	gnmi.RegisterGNMIServer(s, &gnmi.UnimplementedGNMIServer{})
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+	return l.Addr().String(), s
+}
+
+func TestDialTargetTLSHandshakeError(t *testing.T) {
+	addr, _ := tlsGNMIServer(t)
+	skipVerify := false
+	insecure := false
+	tg := target.NewTarget(&types.TargetConfig{
+		Name:             "tls-fail",
+		Address:          addr,
+		SkipVerify:       &skipVerify,
+		Insecure:         &insecure,
+		Timeout:          time.Second,
+		ConnectionEvents: true,
+	})
+	defer deleteTargetConnMetrics("tls-fail")
+	a := New()
+	o := &eventsOutput{}
+	a.Outputs["o1"] = o
+
+	err := a.dialTarget(context.Background(), tg)
+	if err == nil || !strings.Contains(err.Error(), "TLS handshake") {
+		t.Fatalf("expected a TLS handshake error, got %v", err)
+	}
+	if v := testutil.ToFloat64(targetDialAttempts.WithLabelValues("tls-fail", dialResultTLSHandshake)); v != 1 {
+		t.Errorf("expected 1 failed dial attempt, got %v", v)
+	}
+	if v := testutil.ToFloat64(targetTLSHandshakeErrors.WithLabelValues("tls-fail")); v < 1 {
+		t.Errorf("expected the TLS handshake errors to be counted")
+	}
+	if v := testutil.ToFloat64(targetConnState.WithLabelValues("tls-fail")); v != float64(connectivity.TransientFailure) {
+		t.Errorf("unexpected connection state %v", v)
+	}
+	if len(o.events) != 1 || o.events[0].Tags["event"] != "dial-failure" ||
+		o.events[0].Values["result"] != dialResultTLSHandshake {
+		t.Errorf("unexpected events: %v", o.events)
+	}
+}
+
+func TestWatchTargetConnState(t *testing.T) {
+	addr, srv := tlsGNMIServer(t)
+	skipVerify := true
+	insecure := false
+	tg := target.NewTarget(&types.TargetConfig{
+		Name:             "tls-ok",
+		Address:          addr,
+		SkipVerify:       &skipVerify,
+		Insecure:         &insecure,
+		Timeout:          5 * time.Second,
+		ConnectionEvents: true,
+	})
+	defer deleteTargetConnMetrics("tls-ok")
+	a := New()
+	o := &eventsOutput{}
+	a.Outputs["o1"] = o
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := a.dialTarget(ctx, tg); err != nil {
+		t.Fatal(err)
+	}
+	defer tg.Close()
+	if v := testutil.ToFloat64(targetDialAttempts.WithLabelValues("tls-ok", dialResultSuccess)); v != 1 {
+		t.Errorf("expected 1 successful dial attempt, got %v", v)
+	}
+	go a.watchTargetConnState(ctx, tg)
+	// give the watcher time to read the initial state
+	time.Sleep(100 * time.Millisecond)
+	srv.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if testutil.ToFloat64(targetConnState.WithLabelValues("tls-ok")) != float64(connectivity.Ready) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if v := testutil.ToFloat64(targetConnState.WithLabelValues("tls-ok")); v == float64(connectivity.Ready) {
+		t.Fatal("expected the connection state to change once the server is stopped")
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+	if len(o.events) == 0 || o.events[0].Tags["event"] != "state-change" ||
+		o.events[0].Values["previous-state"] != connectivity.Ready.String() {
+		t.Errorf("unexpected events: %v", o.events)
+	}
+}
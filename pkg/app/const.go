@@ -21,6 +21,11 @@ const (
 	formatEvent     = "event"
 	formatPROTO     = "proto"
 	formatFLAT      = "flat"
+	formatSummary   = "summary"
+
+	exitPolicyFailIfAny = "fail-if-any"
+	exitPolicyFailIfAll = "fail-if-all"
+	exitPolicyFailFast  = "fail-fast"
 )
 
 var encodingNames = []string{
@@ -38,6 +43,13 @@ var formatNames = []string{
 	formatEvent,
 	formatPROTO,
 	formatFLAT,
+	formatSummary,
+}
+
+var exitPolicies = []string{
+	exitPolicyFailIfAny,
+	exitPolicyFailIfAll,
+	exitPolicyFailFast,
 }
 
 var tlsVersions = []string{"1.3", "1.2", "1.1", "1.0", "1"}
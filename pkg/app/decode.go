@@ -0,0 +1,286 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/recorder"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	decodeTypeRecord = "record"
+
+	decodeInputFormatBinary    = "binary"
+	decodeInputFormatDelimited = "delimited"
+	decodeInputFormatHex       = "hex"
+	decodeInputFormatBase64    = "base64"
+)
+
+// decodeTypes maps the --type flag values to the
+// raw message they decode to.
+var decodeTypes = map[string]func() proto.Message{
+	"capabilities-request":  func() proto.Message { return new(gnmi.CapabilityRequest) },
+	"capabilities-response": func() proto.Message { return new(gnmi.CapabilityResponse) },
+	"get-request":           func() proto.Message { return new(gnmi.GetRequest) },
+	"get-response":          func() proto.Message { return new(gnmi.GetResponse) },
+	"set-request":           func() proto.Message { return new(gnmi.SetRequest) },
+	"set-response":          func() proto.Message { return new(gnmi.SetResponse) },
+	"subscribe-request":     func() proto.Message { return new(gnmi.SubscribeRequest) },
+	"subscribe-response":    func() proto.Message { return new(gnmi.SubscribeResponse) },
+}
+
+var decodeInputFormats = []string{
+	decodeInputFormatBinary,
+	decodeInputFormatDelimited,
+	decodeInputFormatHex,
+	decodeInputFormatBase64,
+}
+
+func (a *App) DecodePreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	if a.Config.LocalFlags.DecodeInput == "" {
+		return errors.New("missing flag --input")
+	}
+	switch a.Config.Format {
+	case "", formatJSON, formatPROTOJSON, formatPROTOTEXT, formatEvent, formatFLAT:
+	default:
+		return fmt.Errorf("format %q is not supported by the decode command", a.Config.Format)
+	}
+	if a.Config.LocalFlags.DecodeType != decodeTypeRecord {
+		if _, ok := decodeTypes[a.Config.LocalFlags.DecodeType]; !ok {
+			return fmt.Errorf("unknown --type %q", a.Config.LocalFlags.DecodeType)
+		}
+		switch a.Config.LocalFlags.DecodeInputFormat {
+		case decodeInputFormatBinary, decodeInputFormatDelimited, decodeInputFormatHex, decodeInputFormatBase64:
+		default:
+			return fmt.Errorf("unknown --input-format %q, must be one of %q",
+				a.Config.LocalFlags.DecodeInputFormat, decodeInputFormats)
+		}
+		if len(a.Config.LocalFlags.DecodeMethod) > 0 {
+			return errors.New("flag --method can only be used with --type record")
+		}
+	}
+	_, err := a.LoadProtoFiles()
+	return err
+}
+
+func (a *App) DecodeRunE(cmd *cobra.Command, args []string) error {
+	var r io.Reader = os.Stdin
+	if a.Config.LocalFlags.DecodeInput != "-" {
+		f, err := os.Open(a.Config.LocalFlags.DecodeInput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	if a.Config.LocalFlags.DecodeType == decodeTypeRecord {
+		return a.decodeRecords(recorder.NewReader(r))
+	}
+	return a.decodeRaw(r)
+}
+
+func (a *App) decodeRecords(r *recorder.Reader) error {
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !a.decodeMethodSelected(rec.Method) {
+			continue
+		}
+		fmt.Fprintf(a.out, "%s %s %s %s rpc-id=%d",
+			rec.Timestamp.Format(time.RFC3339Nano), rec.Side, rec.Direction, rec.Method, rec.RPCID)
+		if rec.Peer != "" {
+			fmt.Fprintf(a.out, " peer=%s", rec.Peer)
+		}
+		fmt.Fprintln(a.out)
+		if rec.Error != "" {
+			fmt.Fprintf(a.out, "error: %s\n", rec.Error)
+			continue
+		}
+		msg, err := rec.Message()
+		if err != nil {
+			fmt.Fprintf(a.out, "failed to decode %s message: %v\n", rec.Type, err)
+			continue
+		}
+		err = a.printDecoded(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// decodeRaw decodes protobuf encoded messages of the type set with --type,
+// framed according to --input-format.
+func (a *App) decodeRaw(r io.Reader) error {
+	newMsg := decodeTypes[a.Config.LocalFlags.DecodeType]
+	switch a.Config.LocalFlags.DecodeInputFormat {
+	case decodeInputFormatBinary:
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		msg := newMsg()
+		err = proto.Unmarshal(b, msg)
+		if err != nil {
+			return err
+		}
+		return a.printDecoded(msg)
+	case decodeInputFormatDelimited:
+		br := bufio.NewReader(r)
+		for {
+			msg := newMsg()
+			err := protodelim.UnmarshalFrom(br, msg)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			err = a.printDecoded(msg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// hex and base64 inputs hold one message per line.
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), msgSize)
+	lineNum := 0
+	for sc.Scan() {
+		lineNum++
+		line := strings.Join(strings.Fields(sc.Text()), "")
+		if line == "" {
+			continue
+		}
+		var b []byte
+		var err error
+		if a.Config.LocalFlags.DecodeInputFormat == decodeInputFormatHex {
+			b, err = hex.DecodeString(strings.TrimPrefix(line, "0x"))
+		} else {
+			b, err = base64.StdEncoding.DecodeString(line)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		msg := newMsg()
+		err = proto.Unmarshal(b, msg)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		err = a.printDecoded(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func (a *App) printDecoded(msg proto.Message) error {
+	err := a.decodeProtoBytes(msg)
+	if err != nil {
+		return err
+	}
+	format := a.Config.Format
+	switch msg.(type) {
+	case *gnmi.SubscribeResponse, *gnmi.GetResponse:
+	default:
+		// the event and flat formats only apply to responses.
+		if format == formatEvent || format == formatFLAT {
+			format = formatPROTOTEXT
+		}
+	}
+	mo := &formatters.MarshalOptions{
+		Multiline: true,
+		Indent:    "  ",
+		Format:    format,
+	}
+	b, err := mo.Marshal(msg, nil)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		// not a gNMI message, fallback to prototext.
+		mo.Format = formatPROTOTEXT
+		b, err = mo.Marshal(msg, nil)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(a.out, strings.TrimSuffix(string(b), "\n"))
+	return nil
+}
+
+// decodeProtoBytes decodes the ProtoBytes values of the message
+// using the proto files loaded with --proto-file.
+func (a *App) decodeProtoBytes(msg proto.Message) error {
+	if a.rootDesc == nil {
+		return nil
+	}
+	t := &target.Target{RootDesc: a.rootDesc}
+	switch msg := msg.(type) {
+	case *gnmi.SubscribeResponse:
+		return t.DecodeProtoBytes(msg)
+	case *gnmi.GetResponse:
+		for _, n := range msg.GetNotification() {
+			err := t.DecodeNotificationProtoBytes(n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeMethodSelected returns true if the method matches one of
+// the --method flag values, either by its full name or by its last element.
+func (a *App) decodeMethodSelected(method string) bool {
+	if len(a.Config.LocalFlags.DecodeMethod) == 0 {
+		return true
+	}
+	short := method[strings.LastIndex(method, "/")+1:]
+	for _, m := range a.Config.LocalFlags.DecodeMethod {
+		if strings.EqualFold(m, method) || strings.EqualFold(m, short) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) InitDecodeFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.DecodeInput, "input", "", "", "path to the file to decode, - reads from stdin")
+	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.DecodeMethod, "method", "", []string{}, "only decode recorded messages of the given gRPC method(s), e.g: Get, Subscribe or /gnmi.gNMI/Set")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.DecodeType, "type", "", decodeTypeRecord, "input type, a file written using --record or a raw gNMI message type, e.g: subscribe-response, get-response")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.DecodeInputFormat, "input-format", "", decodeInputFormatBinary, "raw messages framing, one of: binary, delimited, hex or base64")
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
+	})
+}
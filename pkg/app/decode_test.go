@@ -0,0 +1,121 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/recorder"
+)
+
+func TestDecodeRecords(t *testing.T) {
+	req, err := proto.Marshal(&gnmi.GetRequest{Path: []*gnmi.Path{{Elem: []*gnmi.PathElem{{Name: "interfaces"}}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	capReq, err := proto.Marshal(&gnmi.CapabilityRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := new(bytes.Buffer)
+	enc := json.NewEncoder(in)
+	for _, rec := range []*recorder.Record{
+		{Timestamp: time.Unix(0, 0).UTC(), Side: recorder.SideClient, Direction: recorder.DirectionSent, Method: "/gnmi.gNMI/Get", Peer: "router1:57400", RPCID: 1, Type: "gnmi.GetRequest", Data: req},
+		{Timestamp: time.Unix(1, 0).UTC(), Side: recorder.SideClient, Direction: recorder.DirectionReceived, Method: "/gnmi.gNMI/Get", Peer: "router1:57400", RPCID: 1, Error: "rpc error: code = Unavailable"},
+		{Timestamp: time.Unix(2, 0).UTC(), Side: recorder.SideClient, Direction: recorder.DirectionSent, Method: "/gnmi.gNMI/Capabilities", RPCID: 2, Type: "gnmi.CapabilityRequest", Data: capReq},
+	} {
+		if err := enc.Encode(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := New()
+	out := new(bytes.Buffer)
+	a.out = out
+	a.Config.LocalFlags.DecodeMethod = []string{"get"}
+	err = a.decodeRecords(recorder.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, s := range []string{
+		"1970-01-01T00:00:00Z client sent /gnmi.gNMI/Get rpc-id=1 peer=router1:57400",
+		`"interfaces"`,
+		"error: rpc error: code = Unavailable",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("output does not contain %q:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "Capabilities") {
+		t.Errorf("output contains a message of a method that was not selected:\n%s", got)
+	}
+}
+
+func TestDecodeRaw(t *testing.T) {
+	rsps := []*gnmi.SubscribeResponse{
+		{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{
+			Timestamp: 42,
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "router1"}},
+			}},
+		}}},
+		{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}},
+	}
+	hexIn := new(bytes.Buffer)
+	delimIn := new(bytes.Buffer)
+	for _, rsp := range rsps {
+		b, err := proto.Marshal(rsp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hexIn.WriteString(hex.EncodeToString(b) + "\n")
+		_, err = protodelim.MarshalTo(delimIn, rsp)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		inputFormat string
+		in          *bytes.Buffer
+	}{
+		{inputFormat: decodeInputFormatHex, in: hexIn},
+		{inputFormat: decodeInputFormatDelimited, in: delimIn},
+	} {
+		t.Run(tc.inputFormat, func(t *testing.T) {
+			a := New()
+			out := new(bytes.Buffer)
+			a.out = out
+			a.Config.Format = formatFLAT
+			a.Config.LocalFlags.DecodeType = "subscribe-response"
+			a.Config.LocalFlags.DecodeInputFormat = tc.inputFormat
+			err := a.decodeRaw(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := out.String()
+			if !strings.Contains(got, "system/name: router1") {
+				t.Errorf("output does not contain the update:\n%s", got)
+			}
+			if !strings.Contains(got, "sync_response") {
+				t.Errorf("output does not contain the sync response:\n%s", got)
+			}
+		})
+	}
+}
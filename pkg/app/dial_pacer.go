@@ -0,0 +1,82 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// dialPacer spreads the targets gNMI client creations over time
+// so that starting (or taking over) many targets at once does not
+// flood the devices control planes and their AAA servers
+// with simultaneous session setups.
+type dialPacer struct {
+	limiter *rate.Limiter
+	jitter  time.Duration
+}
+
+// newDialPacer returns a dialPacer allowing dialRate dials per second
+// with bursts of up to burst dials, each dial is further delayed by a
+// random duration in [0, jitter).
+// It returns nil if dialRate is not a positive number.
+func newDialPacer(dialRate float64, burst int, jitter time.Duration) *dialPacer {
+	if dialRate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &dialPacer{
+		limiter: rate.NewLimiter(rate.Limit(dialRate), burst),
+		jitter:  jitter,
+	}
+}
+
+// wait blocks until the next dial is allowed or ctx is done.
+// A nil dialPacer never blocks.
+func (p *dialPacer) wait(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	err := p.limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if p.jitter <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(p.jitter))))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitDialTurn waits for the target's turn to dial, if dial pacing is enabled.
+func (a *App) waitDialTurn(ctx context.Context, name string) error {
+	if a.dialPacer == nil {
+		return nil
+	}
+	start := time.Now()
+	err := a.dialPacer.wait(ctx)
+	if err != nil {
+		return err
+	}
+	if a.debugEnabled() {
+		a.Logger.Printf("target %q dial paced for %s", name, time.Since(start))
+	}
+	return nil
+}
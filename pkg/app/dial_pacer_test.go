@@ -0,0 +1,76 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDialPacerDisabled(t *testing.T) {
+	p := newDialPacer(0, 10, time.Second)
+	if p != nil {
+		t.Fatalf("expected a nil dialPacer, got %+v", p)
+	}
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Fatalf("nil dialPacer blocked for %s", d)
+	}
+}
+
+func TestDialPacerRate(t *testing.T) {
+	// 20 dials/s with a burst of 2: the first 2 dials go through at once,
+	// the next 4 are spaced by 50ms.
+	p := newDialPacer(20, 2, 0)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	d := time.Since(start)
+	if d < 180*time.Millisecond {
+		t.Fatalf("6 dials took %s, expected at least 200ms", d)
+	}
+	if d > time.Second {
+		t.Fatalf("6 dials took %s, expected about 200ms", d)
+	}
+}
+
+func TestDialPacerJitter(t *testing.T) {
+	p := newDialPacer(1000, 1000, 20*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d := time.Since(start); d > 500*time.Millisecond {
+			t.Fatalf("dial %d delayed for %s, expected less than the 20ms jitter", i, d)
+		}
+	}
+}
+
+func TestDialPacerContextCanceled(t *testing.T) {
+	p := newDialPacer(0.1, 1, 0)
+	// consume the burst
+	if err := p.wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := p.wait(ctx); err == nil {
+		t.Fatal("expected an error waiting for the next dial")
+	}
+}
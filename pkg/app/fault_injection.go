@@ -0,0 +1,28 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/openconfig/gnmic/pkg/faults"
+)
+
+// initFaultInjection creates the injector applying the configured faults
+// to the messages received from the targets.
+func (a *App) initFaultInjection() error {
+	if !a.Config.FaultInjection.Enabled() {
+		return nil
+	}
+	var err error
+	a.faultInjector, err = faults.NewInjector(a.Config.FaultInjection)
+	if err != nil {
+		return err
+	}
+	a.Logger.Printf("fault injection enabled: %+v", *a.Config.FaultInjection)
+	return nil
+}
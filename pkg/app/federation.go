@@ -0,0 +1,233 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/lockers"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const federationMemberUpMetric = "gnmic_federation_member_up"
+
+// outputsGatherer returns a gatherer merging the metrics
+// of the local outputs implementing outputs.MetricsGatherer.
+func (a *App) outputsGatherer() prometheus.Gatherers {
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	gs := make(prometheus.Gatherers, 0, len(a.Outputs))
+	for _, o := range a.Outputs {
+		if g := outputs.GathererOf(o); g != nil {
+			gs = append(gs, g)
+		}
+	}
+	return gs
+}
+
+// handleOutputsMetrics exposes the metrics of the local prometheus outputs.
+func (a *App) handleOutputsMetrics(w http.ResponseWriter, r *http.Request) {
+	promhttp.HandlerFor(a.outputsGatherer(), promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}).
+		ServeHTTP(w, r)
+}
+
+// handleClusterMetrics exposes the metrics of the prometheus outputs of all the cluster members.
+// It is served by the leader only, the other members redirect the request to it.
+func (a *App) handleClusterMetrics(w http.ResponseWriter, r *http.Request) {
+	if a.Config.Clustering == nil || a.Config.Clustering.Federation == nil ||
+		!a.Config.Clustering.Federation.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	if !a.isLeader {
+		a.redirectToLeader(w, r)
+		return
+	}
+	a.configLock.RLock()
+	members := make([]*lockers.Service, 0, len(a.apiServices))
+	for _, s := range a.apiServices {
+		members = append(members, s)
+	}
+	a.configLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.Config.Clustering.Federation.Timeout)
+	defer cancel()
+	mfs := a.federate(ctx, members, a.Config.Clustering.Federation.InstanceLabel)
+
+	format := expfmt.Negotiate(r.Header)
+	w.Header().Set("Content-Type", string(format))
+	enc := expfmt.NewEncoder(w, format)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			a.Logger.Printf("failed to encode federated metric family %q: %v", mf.GetName(), err)
+			return
+		}
+	}
+}
+
+// redirectToLeader redirects the request to the same path on the leader API server.
+func (a *App) redirectToLeader(w http.ResponseWriter, r *http.Request) {
+	leaderKey := a.leaderKey()
+	leader, err := a.locker.List(r.Context(), leaderKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	services, err := a.locker.GetServices(r.Context(), fmt.Sprintf("%s-%s", a.Config.Clustering.ClusterName, apiServiceName), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, s := range services {
+		if s.ID != leader[leaderKey]+"-api" {
+			continue
+		}
+		http.Redirect(w, r, fmt.Sprintf("%s://%s%s", serviceScheme(s), s.Address, r.URL.RequestURI()), http.StatusTemporaryRedirect)
+		return
+	}
+	http.Error(w, "cluster leader not found", http.StatusServiceUnavailable)
+}
+
+// federate fetches the outputs metrics of all the members and merges them,
+// each series is labeled with label=<name of the member it comes from>.
+func (a *App) federate(ctx context.Context, members []*lockers.Service, label string) []*dto.MetricFamily {
+	m := new(sync.Mutex)
+	memberMfs := make(map[string]map[string]*dto.MetricFamily, len(members))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(members))
+	for _, s := range members {
+		go func(s *lockers.Service) {
+			defer wg.Done()
+			mfs, err := fetchMemberMetrics(ctx, s)
+			if err != nil {
+				a.Logger.Printf("failed to fetch outputs metrics from %q: %v", s.ID, err)
+			}
+			m.Lock()
+			memberMfs[serviceInstanceName(s)] = mfs
+			m.Unlock()
+		}(s)
+	}
+	wg.Wait()
+	return mergeMetricFamilies(memberMfs, label)
+}
+
+func fetchMemberMetrics(ctx context.Context, s *lockers.Service) (map[string]*dto.MetricFamily, error) {
+	client := &http.Client{
+		Timeout: defaultHTTPClientTimeout,
+	}
+	scheme := serviceScheme(s)
+	if scheme == "https" {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/api/v1/outputs/metrics", scheme, s.Address), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code=%d", rsp.StatusCode)
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(rsp.Body)
+}
+
+// mergeMetricFamilies merges the metric families of each member, keyed by member name,
+// adding the label `label=member` to their metrics.
+// A metric family with a type different from the one already found under the same name is skipped.
+// A gauge named gnmic_federation_member_up reports the members which metrics could be fetched.
+func mergeMetricFamilies(memberMfs map[string]map[string]*dto.MetricFamily, label string) []*dto.MetricFamily {
+	names := make([]string, 0, len(memberMfs))
+	for name := range memberMfs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	up := &dto.MetricFamily{
+		Name: proto.String(federationMemberUpMetric),
+		Help: proto.String("Whether the outputs metrics of the cluster member were fetched by the leader"),
+		Type: dto.MetricType_GAUGE.Enum(),
+	}
+	merged := make(map[string]*dto.MetricFamily)
+	for _, member := range names {
+		mfs := memberMfs[member]
+		v := 0.0
+		if mfs != nil {
+			v = 1
+		}
+		up.Metric = append(up.Metric, &dto.Metric{
+			Label: []*dto.LabelPair{{Name: proto.String(label), Value: proto.String(member)}},
+			Gauge: &dto.Gauge{Value: proto.Float64(v)},
+		})
+		for name, mf := range mfs {
+			cur, ok := merged[name]
+			if !ok {
+				cur = &dto.MetricFamily{Name: mf.Name, Help: mf.Help, Type: mf.Type}
+				merged[name] = cur
+			}
+			if cur.GetType() != mf.GetType() {
+				continue
+			}
+			for _, metric := range mf.GetMetric() {
+				metric.Label = append(metric.Label, &dto.LabelPair{Name: proto.String(label), Value: proto.String(member)})
+				sort.Slice(metric.Label, func(i, j int) bool {
+					return metric.Label[i].GetName() < metric.Label[j].GetName()
+				})
+				cur.Metric = append(cur.Metric, metric)
+			}
+		}
+	}
+	result := make([]*dto.MetricFamily, 0, len(merged)+1)
+	for _, mf := range merged {
+		result = append(result, mf)
+	}
+	result = append(result, up)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result
+}
+
+func serviceScheme(s *lockers.Service) string {
+	for _, t := range s.Tags {
+		if strings.HasPrefix(t, "protocol=") {
+			return strings.TrimPrefix(t, "protocol=")
+		}
+	}
+	return "http"
+}
+
+func serviceInstanceName(s *lockers.Service) string {
+	for _, t := range s.Tags {
+		if strings.HasPrefix(t, "instance-name=") {
+			return strings.TrimPrefix(t, "instance-name=")
+		}
+	}
+	return strings.TrimSuffix(s.ID, "-api")
+}
@@ -0,0 +1,82 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/openconfig/gnmic/pkg/lockers"
+)
+
+type testGathererOutput struct {
+	testOutput
+	reg *prometheus.Registry
+}
+
+func (o *testGathererOutput) Gatherer() prometheus.Gatherer { return o.reg }
+
+func newTestMember(t *testing.T, name string, value float64) *lockers.Service {
+	reg := prometheus.NewRegistry()
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: "interface_in_octets", Help: "in octets"})
+	g.Set(value)
+	reg.MustRegister(g)
+	m := New()
+	m.Outputs["prom"] = &testGathererOutput{reg: reg}
+	m.Outputs["other"] = &testOutput{}
+	srv := httptest.NewServer(http.HandlerFunc(m.handleOutputsMetrics))
+	t.Cleanup(srv.Close)
+	return &lockers.Service{
+		ID:      name + "-api",
+		Address: strings.TrimPrefix(srv.URL, "http://"),
+		Tags:    []string{"instance-name=" + name, "protocol=http"},
+	}
+}
+
+func TestFederate(t *testing.T) {
+	a := New()
+	members := []*lockers.Service{
+		newTestMember(t, "gnmic1", 1),
+		newTestMember(t, "gnmic2", 2),
+		{ID: "gnmic3-api", Address: "127.0.0.1:1", Tags: []string{"instance-name=gnmic3"}},
+	}
+	mfs := a.federate(context.Background(), members, "instance")
+	if len(mfs) != 2 {
+		t.Fatalf("expected 2 metric families, got %d", len(mfs))
+	}
+	if mfs[0].GetName() != federationMemberUpMetric {
+		t.Fatalf("unexpected metric family %q", mfs[0].GetName())
+	}
+	up := make(map[string]float64)
+	for _, m := range mfs[0].GetMetric() {
+		up[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	if up["gnmic1"] != 1 || up["gnmic2"] != 1 || up["gnmic3"] != 0 {
+		t.Errorf("unexpected members status: %v", up)
+	}
+	if mfs[1].GetName() != "interface_in_octets" {
+		t.Fatalf("unexpected metric family %q", mfs[1].GetName())
+	}
+	values := make(map[string]float64)
+	for _, m := range mfs[1].GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "instance" {
+				values[l.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	if len(values) != 2 || values["gnmic1"] != 1 || values["gnmic2"] != 2 {
+		t.Errorf("unexpected federated values: %v", values)
+	}
+}
@@ -127,7 +127,7 @@ func (a *App) yangFilesPreProcessing() error {
 		if err != nil {
 			return err
 		}
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			for _, fdir := range expanded {
 				a.Logger.Printf("adding %s to YANG paths", fdir)
 			}
@@ -140,7 +140,7 @@ func (a *App) yangFilesPreProcessing() error {
 	}
 	a.Config.GlobalFlags.File = make([]string, 0, len(yfiles))
 	a.Config.GlobalFlags.File = append(a.Config.GlobalFlags.File, yfiles...)
-	if a.Config.Debug {
+	if a.debugEnabled() {
 		for _, file := range a.Config.GlobalFlags.File {
 			a.Logger.Printf("loading %s file", file)
 		}
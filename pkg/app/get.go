@@ -78,34 +78,30 @@ func (a *App) GetRun(cmd *cobra.Command, args []string) error {
 	// other formats
 	numTargets := len(a.Config.Targets)
 	a.errCh = make(chan error, numTargets*3)
-	a.wg.Add(numTargets)
-	for _, tc := range a.Config.Targets {
-		go a.GetRequest(ctx, tc)
-	}
-	a.wg.Wait()
-	err = a.checkErrors()
+	rs := a.runOnTargets(ctx, a.Config.LocalFlags.GetMaxConcurrentTargets, 0, a.GetRequest)
+	err = a.checkTargetsErrors(rs)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (a *App) GetRequest(ctx context.Context, tc *types.TargetConfig) {
-	defer a.wg.Done()
+func (a *App) GetRequest(ctx context.Context, tc *types.TargetConfig) error {
 	req, err := a.Config.CreateGetRequest(tc)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q building Get request failed: %v", tc.Name, err))
-		return
+		return err
 	}
 	response, err := a.getRequest(ctx, tc, req)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q Get request failed: %v", tc.Name, err))
-		return
+		return err
 	}
 	err = a.PrintMsg(tc.Name, "Get Response:", response)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
 	}
+	return err
 }
 
 func (a *App) getRequest(ctx context.Context, tc *types.TargetConfig, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
@@ -176,6 +172,7 @@ func (a *App) InitGetFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&a.Config.LocalFlags.GetValuesOnly, "values-only", "", false, "print GetResponse values only")
 	cmd.Flags().StringArrayVarP(&a.Config.LocalFlags.GetProcessor, "processor", "", []string{}, "list of processor names to run")
 	cmd.Flags().Uint32VarP(&a.Config.LocalFlags.GetDepth, "depth", "", 0, "depth extension value")
+	cmd.Flags().UintVarP(&a.Config.LocalFlags.GetMaxConcurrentTargets, "max-concurrent-targets", "", 0, "maximum number of targets the request is sent to concurrently, overrides the global flag --concurrency")
 
 	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
 		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
@@ -218,36 +215,34 @@ func (a *App) intializeEventProcessors() ([]formatters.EventProcessor, error) {
 func (a *App) handleGetRequestEvent(ctx context.Context, evps []formatters.EventProcessor) error {
 	numTargets := len(a.Config.Targets)
 	a.errCh = make(chan error, numTargets*3)
-	a.wg.Add(numTargets)
 	rsps := make(chan *getResponseEvents, numTargets)
-	for _, tc := range a.Config.Targets {
-		go func(tc *types.TargetConfig) {
-			defer a.wg.Done()
-			req, err := a.Config.CreateGetRequest(tc)
-			if err != nil {
-				a.errCh <- err
-				return
-			}
-			resp, err := a.getRequest(ctx, tc, req)
-			if err != nil {
-				a.errCh <- err
-				return
-			}
-			evs, err := formatters.GetResponseToEventMsgs(resp, map[string]string{"source": tc.Name}, evps...)
-			if err != nil {
-				a.errCh <- err
-			}
-			rsps <- &getResponseEvents{name: tc.Name, rsp: evs}
-		}(tc)
-	}
-	a.wg.Wait()
+	rs := a.runOnTargets(ctx, a.Config.LocalFlags.GetMaxConcurrentTargets, 0, func(ctx context.Context, tc *types.TargetConfig) error {
+		req, err := a.Config.CreateGetRequest(tc)
+		if err != nil {
+			a.errCh <- err
+			return err
+		}
+		resp, err := a.getRequest(ctx, tc, req)
+		if err != nil {
+			a.errCh <- err
+			return err
+		}
+		meta := map[string]string{"source": tc.Name}
+		addTargetMeta(meta, tc)
+		evs, err := formatters.GetResponseToEventMsgs(resp, meta, evps...)
+		if err != nil {
+			a.errCh <- err
+		}
+		rsps <- &getResponseEvents{name: tc.Name, rsp: evs}
+		return err
+	})
 	close(rsps)
 
 	responses := make(map[string][]*formatters.EventMsg)
 	for r := range rsps {
 		responses[r.name] = r.rsp
 	}
-	err := a.checkErrors()
+	err := a.checkTargetsErrors(rs)
 	if err != nil {
 		return err
 	}
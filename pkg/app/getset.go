@@ -43,6 +43,9 @@ func (a *App) GetSetRunE(cmd *cobra.Command, args []string) error {
 	if a.Config.Format == formatEvent {
 		return fmt.Errorf("format event not supported for GetSet RPC")
 	}
+	if a.Config.Format == formatSummary {
+		return fmt.Errorf("format summary not supported for GetSet RPC")
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	// setupCloseHandler(cancel)
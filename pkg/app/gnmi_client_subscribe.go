@@ -31,6 +31,8 @@ type subscriptionRequest struct {
 	name string
 	// gNMI subscription request
 	req *gnmi.SubscribeRequest
+	// subscription config, set when its wildcard paths are expanded before subscribing
+	sc *types.SubscriptionConfig
 }
 
 func (a *App) TargetSubscribeStream(ctx context.Context, tc *types.TargetConfig) {
@@ -70,6 +72,7 @@ START:
 				goto START
 			}
 			a.Logger.Printf("acquired lock for target %q", tc.Name)
+			a.bootstrapTargetCache(nctx, tc.Name)
 		}
 		a.Logger.Printf("queuing target %q", tc.Name)
 		a.targetsChan <- t
@@ -158,22 +161,31 @@ func (a *App) clientSubscribe(ctx context.Context, tc *types.TargetConfig) error
 	}
 
 	subscriptionsConfigs := t.Subscriptions
-	if len(subscriptionsConfigs) == 0 {
+	if len(subscriptionsConfigs) == 0 && !usesAutoBundle(tc) {
 		subscriptionsConfigs = a.Config.Subscriptions
 	}
-	if len(subscriptionsConfigs) == 0 {
+	if len(subscriptionsConfigs) == 0 && !usesAutoBundle(tc) {
 		return fmt.Errorf("target %q has no subscriptions defined", tc.Name)
 	}
+	profile := targetScheduleProfile(tc, time.Now())
+	if profile != "" {
+		a.Logger.Printf("target %q: using schedule profile %q", tc.Name, profile)
+	}
 	subRequests := make([]subscriptionRequest, 0, len(subscriptionsConfigs))
 	for scName, sc := range subscriptionsConfigs {
-		req, err := a.Config.CreateSubscribeRequest(sc, tc)
+		ssc := scheduledSubscription(tc, profile, scName, sc)
+		req, err := a.Config.CreateSubscribeRequest(ssc, tc)
 		if err != nil {
 			if errors.Is(errors.Unwrap(err), config.ErrConfig) {
 				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
 		}
-		subRequests = append(subRequests, subscriptionRequest{name: scName, req: req})
+		sreq := subscriptionRequest{name: scName, req: req}
+		if ssc.ExpandWildcards != nil {
+			sreq.sc = ssc
+		}
+		subRequests = append(subRequests, sreq)
 	}
 	if t.Cfn != nil {
 		t.Cfn()
@@ -196,7 +208,10 @@ CRCLIENT:
 			// overwrite target address
 			t.Config.Address = t.Config.Name
 		}
-		err := t.CreateGNMIClient(ctx, targetDialOpts...)
+		if err := a.waitDialTurn(gnmiCtx, tc.Name); err != nil {
+			return err
+		}
+		err := a.dialTarget(ctx, t, targetDialOpts...)
 		if err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				a.Logger.Printf("failed to initialize target %q timeout (%s) reached", tc.Name, t.Config.Timeout)
@@ -209,12 +224,23 @@ CRCLIENT:
 		}
 	}
 	a.Logger.Printf("target %q gNMI client created", t.Config.Name)
+	go a.watchTargetConnState(gnmiCtx, t)
+	go a.learnTargetHostname(gnmiCtx, t)
+	go a.watchTargetCapabilities(gnmiCtx, t)
+	a.detectTargetPlatform(gnmiCtx, t)
+	subRequests = append(subRequests, a.autoBundleRequests(t)...)
 
 	for _, sreq := range subRequests {
+		if sreq.sc != nil {
+			go a.subscribeExpanded(gnmiCtx, t, sreq.name, sreq.sc)
+			continue
+		}
 		a.Logger.Printf("sending gNMI SubscribeRequest: subscribe='%+v', mode='%+v', encoding='%+v', to %s",
 			sreq.req, sreq.req.GetSubscribe().GetMode(), sreq.req.GetSubscribe().GetEncoding(), t.Config.Name)
 		go t.Subscribe(gnmiCtx, sreq.req, sreq.name)
 	}
+	a.startPollTriggers(gnmiCtx, t)
+	go a.watchTargetSchedule(gnmiCtx, t, subscriptionsConfigs, profile)
 	return nil
 }
 
@@ -227,10 +253,10 @@ func (a *App) clientSubscribeOnce(ctx context.Context, tc *types.TargetConfig) e
 	}
 
 	subscriptionsConfigs := t.Subscriptions
-	if len(subscriptionsConfigs) == 0 {
+	if len(subscriptionsConfigs) == 0 && !usesAutoBundle(tc) {
 		subscriptionsConfigs = a.Config.Subscriptions
 	}
-	if len(subscriptionsConfigs) == 0 {
+	if len(subscriptionsConfigs) == 0 && !usesAutoBundle(tc) {
 		return fmt.Errorf("target %q has no subscriptions defined", tc.Name)
 	}
 	subRequests := make([]subscriptionRequest, 0)
@@ -258,7 +284,10 @@ CRCLIENT:
 		// overwrite target address
 		t.Config.Address = t.Config.Name
 	}
-	if err := t.CreateGNMIClient(ctx, targetDialOpts...); err != nil {
+	if err := a.waitDialTurn(gnmiCtx, tc.Name); err != nil {
+		return err
+	}
+	if err := a.dialTarget(ctx, t, targetDialOpts...); err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			a.Logger.Printf("failed to initialize target %q timeout (%s) reached", tc.Name, t.Config.Timeout)
 		} else {
@@ -270,6 +299,11 @@ CRCLIENT:
 
 	}
 	a.Logger.Printf("target %q gNMI client created", t.Config.Name)
+	// the responses of a ONCE subscription are received right away,
+	// wait for the hostname before subscribing.
+	a.learnTargetHostname(gnmiCtx, t)
+	a.detectTargetPlatform(gnmiCtx, t)
+	subRequests = append(subRequests, a.autoBundleRequests(t)...)
 OUTER:
 	for _, sreq := range subRequests {
 		a.Logger.Printf("sending gNMI SubscribeRequest: subscribe='%+v', mode='%+v', encoding='%+v', to %s",
@@ -292,6 +326,10 @@ OUTER:
 					continue
 				default:
 					m := outputs.Meta{"source": t.Config.Name, "format": a.Config.Format, "subscription-name": sreq.name}
+					addTargetMeta(m, t.Config)
+					a.addTargetPlatformMeta(m, t.Config)
+					addOutputOptionsMeta(m, t.Config, t.Subscriptions[sreq.name])
+					a.rewriteTarget(ctx, t, rsp, m)
 					a.Export(ctx, rsp, m, t.Config.Outputs...)
 				}
 			}
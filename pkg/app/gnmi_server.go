@@ -15,7 +15,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,10 +29,12 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/recorder"
 	"github.com/openconfig/gnmic/pkg/api/server"
 	"github.com/openconfig/gnmic/pkg/api/target"
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/openconfig/gnmic/pkg/cache"
+	"github.com/openconfig/gnmic/pkg/outputs"
 )
 
 type streamClient struct {
@@ -53,7 +57,40 @@ func (a *App) startGnmiServer() error {
 		a.Logger.Printf("failed to initialize gNMI cache: %v", err)
 		return err
 	}
+	collectorID := a.Config.InstanceName
+	if a.Config.Clustering != nil && a.Config.Clustering.InstanceName != "" {
+		collectorID = a.Config.Clustering.InstanceName
+	}
+	a.collectorExt = outputs.NewCollectorExtension(a.Config.GnmiServer.CollectorExtension, collectorID)
+	a.initServerTargetsSem()
+	a.initSubscriptionQuotas()
+	err = a.initServerACL()
+	if err != nil {
+		return fmt.Errorf("gnmi-server acl: %v", err)
+	}
+	a.initClientCertTargets()
+	err = a.initSubscriptionSendRates()
+	if err != nil {
+		return fmt.Errorf("gnmi-server max-send-rate: %v", err)
+	}
+	if a.Config.GnmiServer.ValidateSet {
+		if len(a.Config.GlobalFlags.File) == 0 {
+			return errors.New("gnmi-server validate-set requires the YANG files to be set with --file")
+		}
+		err = a.yangFilesPreProcessing()
+		if err != nil {
+			return err
+		}
+		err = a.generateYangSchema(a.Config.GlobalFlags.File, a.Config.GlobalFlags.Exclude)
+		if err != nil {
+			return fmt.Errorf("failed loading the YANG schema: %v", err)
+		}
+	}
 
+	srvRecorder, err := a.gnmiServerRecorder()
+	if err != nil {
+		return err
+	}
 	s, err := server.New(server.Config{
 		Address:              a.Config.GnmiServer.Address,
 		MaxUnaryRPC:          a.Config.GnmiServer.MaxUnaryRPC,
@@ -66,11 +103,19 @@ func (a *App) startGnmiServer() error {
 		RateLimit:            a.Config.GnmiServer.RateLimit,
 		HealthEnabled:        true,
 		TLS:                  a.Config.GnmiServer.TLS,
+		SocketOptions:        a.Config.GnmiServer.SocketOptions,
+		IPFilter:             a.Config.GnmiServer.IPFilter,
+		Compression:          a.Config.GnmiServer.Compression,
+		UnixSocket:           a.Config.GnmiServer.UnixSocket,
+		SocketActivation:     a.Config.GnmiServer.SocketActivation,
 	}, server.WithLogger(a.Logger),
+		server.WithCapabilitiesHandler(a.serverCapabilitiesHandler),
 		server.WithGetHandler(a.serverGetHandler),
 		server.WithSetHandler(a.serverSetHandler),
 		server.WithSubscribeHandler(a.serverSubscribeHandler),
 		server.WithRegistry(a.reg),
+		server.WithRecorder(srvRecorder),
+		server.WithSubscriptionQuotas(a.subscriptionQuotas),
 	)
 	if err != nil {
 		return err
@@ -222,20 +267,35 @@ func (a *App) handleONCESubscriptionRequest(sc *streamClient) {
 		a.Logger.Printf("subscription request to target %q processed", sc.target)
 	}()
 
-	for n := range a.c.Subscribe(sc.stream.Context(), ro) {
+	for n := range a.subscribeCache(sc.stream.Context(), ro) {
 		if n.Err != nil {
 			err = n.Err
 			return
 		}
-		err = sc.stream.Send(&gnmi.SubscribeResponse{
+		rsp := &gnmi.SubscribeResponse{
 			Response: &gnmi.SubscribeResponse_Update{
 				Update: n.Notification,
 			},
-		})
+		}
+		err = a.collectorExt.Add(rsp)
 		if err != nil {
 			return
 		}
+		err = sc.stream.Send(rsp)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribeCache runs a cache subscription,
+// batching the returned notifications if configured.
+func (a *App) subscribeCache(ctx context.Context, ro *cache.ReadOpts) chan *cache.Notification {
+	ch := a.c.Subscribe(ctx, ro)
+	if b := a.Config.GnmiServer.Batching; b != nil {
+		return batchNotifications(ctx, ch, b.MaxUpdates, b.MaxDelay)
 	}
+	return ch
 }
 
 func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
@@ -286,9 +346,50 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 	wg := new(sync.WaitGroup)
 	wg.Add(len(subs))
 
+	send := sc.stream.Send
+	// queue the responses and evict the subscriber
+	// if it does not keep up with them.
+	if scc := a.Config.GnmiServer.SlowConsumer; scc != nil {
+		q := newStreamQueue(scc.QueueSize, scc.Threshold, scc.Duration)
+		streamSend := send
+		send = func(rsp *gnmi.SubscribeResponse) error {
+			return q.push(ctx, rsp)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := q.run(ctx, streamSend)
+			if status.Code(err) == codes.ResourceExhausted {
+				a.Logger.Printf("evicting STREAM subscription from %q to target %q: %v", peer.Addr, sc.target, err)
+				gnmiServerSlowConsumersEvicted.Inc()
+			}
+			if err != nil && !errors.Is(err, context.Canceled) {
+				errChan <- err
+			}
+		}()
+	}
+
+	// pace the responses so that a single subscription
+	// does not starve the other ones.
+	// the subscriptions without a send rate of their own
+	// share the client send rate, across all the Subscribe RPCs of the client.
+	var clientLimiter *streamRateLimiter
+	if msr := a.Config.GnmiServer.MaxSendRate; msr != nil && a.clientRateLimiters != nil {
+		client := clientSendRateIdentity(ctx)
+		var release func()
+		clientLimiter, release = a.clientRateLimiters.acquire(client, msr.ClientRate(client))
+		defer release()
+	}
+
 	for i, sub := range subs {
 		a.Logger.Printf("handling subscriptionList item[%d]: target %q, %q", i, sc.target, sub.String())
 
+		l := clientLimiter
+		if r := a.subscriptionSendRate(pr, sub.GetPath()); r != nil {
+			l = newStreamRateLimiter(r)
+		}
+		send := limitedSend(ctx, send, l)
+
 		go func(sub *gnmi.Subscription) {
 			defer wg.Done()
 			var ro *cache.ReadOpts
@@ -305,7 +406,7 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 						},
 					},
 					Mode:              cache.ReadMode_StreamOnChange,
-					HeartbeatInterval: time.Duration(sub.GetHeartbeatInterval()),
+					HeartbeatInterval: a.heartbeatInterval(sub),
 					SuppressRedundant: sub.GetSuppressRedundant(),
 					UpdatesOnly:       sc.req.GetSubscribe().GetUpdatesOnly(),
 				}
@@ -326,7 +427,7 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 						}},
 					Mode:              cache.ReadMode_StreamSample,
 					SampleInterval:    period,
-					HeartbeatInterval: time.Duration(sub.GetHeartbeatInterval()),
+					HeartbeatInterval: a.heartbeatInterval(sub),
 					SuppressRedundant: sub.GetSuppressRedundant(),
 					UpdatesOnly:       sc.req.GetSubscribe().GetUpdatesOnly(),
 				}
@@ -334,7 +435,7 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 
 			a.Logger.Printf("cache subscribe: %+v", ro)
 
-			for n := range a.c.Subscribe(ctx, ro) {
+			for n := range a.subscribeCache(ctx, ro) {
 				// `errChan <- n.Err` should trigger the gnmi-server side cleanup
 				// only wait would be for the cache to close the channel
 				if n.Err != nil {
@@ -347,14 +448,18 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 					continue
 				}
 
-				err := sc.stream.Send(&gnmi.SubscribeResponse{
+				rsp := &gnmi.SubscribeResponse{
 					Response: &gnmi.SubscribeResponse_Update{
 						Update: n.Notification,
 					},
-				})
+				}
+				err := a.collectorExt.Add(rsp)
+				if err == nil {
+					err = send(rsp)
+				}
 
 				if err != nil {
-					errChan <- n.Err
+					errChan <- err
 				}
 			}
 		}(sub)
@@ -366,6 +471,16 @@ func (a *App) handleStreamSubscriptionRequest(sc *streamClient) {
 	wg.Wait()
 }
 
+// heartbeatInterval returns the heartbeat interval of sub,
+// raised to the configured minimum if it is lower.
+func (a *App) heartbeatInterval(sub *gnmi.Subscription) time.Duration {
+	hb := time.Duration(sub.GetHeartbeatInterval())
+	if hb > 0 && hb < a.Config.GnmiServer.MinHeartbeatInterval {
+		return a.Config.GnmiServer.MinHeartbeatInterval
+	}
+	return hb
+}
+
 func (a *App) handlePolledSubscription(sc *streamClient) {
 	defer close(sc.errChan)
 	a.handleONCESubscriptionRequest(sc)
@@ -461,6 +576,19 @@ func (a *App) handlegNMIGetPath(elems []*gnmi.PathElem, enc gnmi.Encoding) ([]*g
 			for _, sub := range a.Config.Subscriptions {
 				notifications = append(notifications, subscriptionConfigToNotification(sub, enc))
 			}
+		case "capabilities":
+			switch enc {
+			case gnmi.Encoding_JSON, gnmi.Encoding_JSON_IETF:
+			default:
+				return nil, status.Errorf(codes.InvalidArgument, "capabilities are only available with JSON encodings")
+			}
+			var names []string
+			if name, ok := e.Key["name"]; ok {
+				names = append(names, name)
+			}
+			for _, tcaps := range a.getTargetsCapabilities(names...) {
+				notifications = append(notifications, targetCapabilitiesToNotification(tcaps))
+			}
 		// case "outputs":
 		// case "inputs":
 		// case "processors":
@@ -851,11 +979,19 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 		}
 	}
 
+	targetName := req.GetPrefix().GetTarget()
+	err := a.clientCertTargets.authorize(ctx, aclRPCGet, targetName)
+	if err != nil {
+		return nil, err
+	}
+	err = a.serverACL.authorize(ctx, aclRPCGet, targetName, req.GetPrefix(), req.GetPath())
+	if err != nil {
+		return nil, err
+	}
 	if _, ok := origins["gnmic"]; ok {
 		return a.handlegNMIcInternalGet(ctx, req)
 	}
 
-	targetName := req.GetPrefix().GetTarget()
 	pr, _ := peer.FromContext(ctx)
 	a.Logger.Printf("received Get request from %q to target %q", pr.Addr, targetName)
 
@@ -865,10 +1001,22 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 	}
 	numTargets := len(targets)
 	if numTargets == 0 {
+		// the target might be handled by another instance
+		// sharing the same cache.
+		if a.c != nil && targetName != "" && targetName != "*" {
+			rsp, err := a.cacheGetHandler(req)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to read cache: %v", err)
+			}
+			if len(rsp.GetNotification()) > 0 {
+				return rsp, nil
+			}
+		}
 		return nil, status.Errorf(codes.NotFound, "unknown target %q", targetName)
 	}
 	results := make(chan *gnmi.Notification)
 	errChan := make(chan error, numTargets)
+	budget := a.newTargetsBudget(ctx, numTargets)
 
 	response := &gnmi.GetResponse{
 		// assume one notification per path per target
@@ -896,6 +1044,12 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 	for name, t := range targets {
 		go func(name string, t *target.Target) {
 			defer wg.Done()
+			release, err := a.acquireServerTarget(ctx)
+			if err != nil {
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
+				return
+			}
+			defer release()
 
 			creq := proto.Clone(req).(*gnmi.GetRequest)
 			if creq.GetPrefix() == nil {
@@ -904,10 +1058,12 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 			if creq.GetPrefix().GetTarget() == "" || creq.GetPrefix().GetTarget() == "*" {
 				creq.Prefix.Target = name
 			}
-			res, err := t.Get(ctx, creq)
+			tctx, tcancel := budget.targetContext(ctx)
+			defer tcancel()
+			res, err := t.Get(tctx, creq)
 			if err != nil {
 				a.Logger.Printf("target %q err: %v", name, err)
-				errChan <- fmt.Errorf("target %q err: %v", name, err)
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
 				return
 			}
 
@@ -918,7 +1074,11 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 				if n.GetPrefix().GetTarget() == "" {
 					n.Prefix.Target = name
 				}
-				results <- n
+				select {
+				case results <- n:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}(name, t)
 	}
@@ -927,16 +1087,49 @@ func (a *App) serverGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi
 	close(errChan)
 	for err := range errChan {
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "%v", err)
+			return nil, targetRPCStatus(err)
 		}
 	}
-	<-done
-	if a.Config.Debug {
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, targetRPCStatus(ctx.Err())
+	}
+	if a.debugEnabled() {
 		a.Logger.Printf("sending GetResponse to %q: %+v", pr.Addr, response)
 	}
 	return response, nil
 }
 
+// cacheGetHandler builds a GetResponse from the notifications
+// stored in the gNMI server cache.
+func (a *App) cacheGetHandler(req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	paths := req.GetPath()
+	if len(paths) == 0 {
+		paths = []*gnmi.Path{{}}
+	}
+	rsp := new(gnmi.GetResponse)
+	for _, name := range strings.Split(req.GetPrefix().GetTarget(), ",") {
+		for _, p := range paths {
+			cp := &gnmi.Path{
+				Origin: req.GetPrefix().GetOrigin(),
+				Elem:   append(append([]*gnmi.PathElem{}, req.GetPrefix().GetElem()...), p.GetElem()...),
+			}
+			if cp.Origin == "" {
+				cp.Origin = p.GetOrigin()
+			}
+			notifs, err := a.c.Read("*", name, cp)
+			if err != nil {
+				return nil, err
+			}
+			for _, ns := range notifs {
+				rsp.Notification = append(rsp.Notification, ns...)
+			}
+		}
+	}
+	return rsp, nil
+}
+
 func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
 	numUpdates := len(req.GetUpdate())
 	numReplaces := len(req.GetReplace())
@@ -945,8 +1138,40 @@ func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi
 	if numUpdates+numReplaces+numDeletes+numUnionReplace == 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "missing update/replace/delete path(s)")
 	}
+	paths := setRequestPaths(req)
+	origins := make(map[string]struct{})
+	for _, p := range paths {
+		origin := p.GetOrigin()
+		if origin == "" {
+			origin = req.GetPrefix().GetOrigin()
+		}
+		origins[origin] = struct{}{}
+	}
+	_, gnmicOrigin := origins["gnmic"]
+	if gnmicOrigin && len(origins) > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "combining `gnmic` origin with other origin values is not supported")
+	}
+	if a.Config.GnmiServer.ValidateSet && !gnmicOrigin {
+		if err := validateSetRequest(a.SchemaTree, req); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
 
 	targetName := req.GetPrefix().GetTarget()
+	err := a.clientCertTargets.authorize(ctx, aclRPCSet, targetName)
+	if err != nil {
+		return nil, err
+	}
+	err = a.serverACL.authorize(ctx, aclRPCSet, targetName, req.GetPrefix(), paths)
+	if err != nil {
+		return nil, err
+	}
+	if gnmicOrigin {
+		if !a.Config.GnmiServer.ConfigSet {
+			return nil, status.Errorf(codes.PermissionDenied, "Set requests with the `gnmic` origin are disabled")
+		}
+		return a.handlegNMIcInternalSet(ctx, req)
+	}
 	pr, _ := peer.FromContext(ctx)
 	a.Logger.Printf("received Set request from %q to target %q", pr.Addr, targetName)
 
@@ -960,6 +1185,8 @@ func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi
 	}
 	results := make(chan *gnmi.UpdateResult)
 	errChan := make(chan error, numTargets)
+	budget := a.newTargetsBudget(ctx, numTargets)
+	attempts := a.newSetAttempts()
 
 	response := &gnmi.SetResponse{
 		// assume one update per target, per update/replace/delete
@@ -988,6 +1215,12 @@ func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi
 	for name, t := range targets {
 		go func(name string, t *target.Target) {
 			defer wg.Done()
+			release, err := a.acquireServerTarget(ctx)
+			if err != nil {
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
+				return
+			}
+			defer release()
 
 			creq := proto.Clone(req).(*gnmi.SetRequest)
 			if creq.GetPrefix() == nil {
@@ -996,15 +1229,21 @@ func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi
 			if creq.GetPrefix().GetTarget() == "" || creq.GetPrefix().GetTarget() == "*" {
 				creq.Prefix.Target = name
 			}
-			res, err := t.Set(ctx, creq)
+			tctx, tcancel := budget.targetContext(ctx)
+			defer tcancel()
+			res, err := a.targetSet(tctx, name, t, creq, attempts)
 			if err != nil {
 				a.Logger.Printf("target %q err: %v", name, err)
-				errChan <- fmt.Errorf("target %q err: %v", name, err)
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
 				return
 			}
 			for _, upd := range res.GetResponse() {
 				upd.Path.Target = name
-				results <- upd
+				select {
+				case results <- upd:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}(name, t)
 	}
@@ -1013,10 +1252,20 @@ func (a *App) serverSetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi
 	close(errChan)
 	for err := range errChan {
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "%v", err)
+			return nil, targetRPCStatus(err)
+		}
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, targetRPCStatus(ctx.Err())
+	}
+	if attempts != nil {
+		err = attempts.addExtension(a.Config.GnmiServer.SetRetry.ExtensionID, response)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to add Set attempts extension: %v", err)
 		}
 	}
-	<-done
 	a.Logger.Printf("sending SetResponse to %q: %+v", pr.Addr, response)
 	return response, nil
 }
@@ -1041,12 +1290,27 @@ func (a *App) serverSubscribeHandler(req *gnmi.SubscribeRequest, stream gnmi.GNM
 	a.Logger.Printf("received a subscribe request mode=%v from %q for target %q", sc.req.GetSubscribe().GetMode(), pr.Addr, sc.target)
 	defer a.Logger.Printf("subscription from peer %q terminated", pr.Addr)
 
+	err := a.clientCertTargets.authorize(stream.Context(), aclRPCSubscribe, sc.target)
+	if err != nil {
+		return err
+	}
+	err = a.serverACL.authorize(stream.Context(), aclRPCSubscribe, sc.target, sc.req.GetSubscribe().GetPrefix(), subscribeRequestPaths(sc.req))
+	if err != nil {
+		return err
+	}
+
 	// closing of this channel is handled by respective goroutines that are going to send error on this channel
 	errChan := make(chan error, len(sc.req.GetSubscribe().GetSubscription()))
 	sc.errChan = errChan // send-only
 
-	switch sc.req.GetSubscribe().GetMode() {
-	case gnmi.SubscriptionList_ONCE:
+	internal, err := isGNMIcSubscription(sc.req.GetSubscribe())
+	if err != nil {
+		return err
+	}
+	switch mode := sc.req.GetSubscribe().GetMode(); {
+	case internal:
+		go a.handleStatisticsSubscription(sc)
+	case mode == gnmi.SubscriptionList_ONCE:
 		go func() {
 			a.handleONCESubscriptionRequest(sc)
 			errChan <- sc.stream.Send(&gnmi.SubscribeResponse{
@@ -1055,12 +1319,12 @@ func (a *App) serverSubscribeHandler(req *gnmi.SubscribeRequest, stream gnmi.GNM
 			close(errChan)
 		}()
 
-	case gnmi.SubscriptionList_POLL:
+	case mode == gnmi.SubscriptionList_POLL:
 		go a.handlePolledSubscription(sc)
-	case gnmi.SubscriptionList_STREAM:
+	case mode == gnmi.SubscriptionList_STREAM:
 		go a.handleStreamSubscriptionRequest(sc)
 	default:
-		return status.Errorf(codes.InvalidArgument, "unrecognized subscription mode: %v", sc.req.GetSubscribe().GetMode())
+		return status.Errorf(codes.InvalidArgument, "unrecognized subscription mode: %v", mode)
 	}
 
 	// flushing the errChan
@@ -1073,9 +1337,67 @@ func (a *App) serverSubscribeHandler(req *gnmi.SubscribeRequest, stream gnmi.GNM
 	// returning first non-nil error and flushing rest in defer
 	for err := range errChan {
 		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
 			return status.Errorf(codes.Internal, "%v", err)
 		}
 	}
 
 	return nil
 }
+
+// gnmiServerRecorder returns a recorder writing to the gnmi-server record file,
+// or nil if not configured.
+func (a *App) gnmiServerRecorder() (*recorder.Recorder, error) {
+	if a.Config.GnmiServer.Record == "" {
+		return nil, nil
+	}
+	r, err := recorder.NewFile(a.Config.GnmiServer.Record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gnmi-server messages recorder: %v", err)
+	}
+	a.Logger.Printf("recording gnmi-server messages to %q", a.Config.GnmiServer.Record)
+	return r, nil
+}
+
+func (a *App) initSubscriptionQuotas() {
+	if a.Config.GnmiServer.MaxSubscriptionsPerClient <= 0 && len(a.Config.GnmiServer.ClientMaxSubscriptions) == 0 {
+		return
+	}
+	a.subscriptionQuotas = server.NewSubscriptionQuotas(
+		a.Config.GnmiServer.MaxSubscriptionsPerClient,
+		a.Config.GnmiServer.ClientMaxSubscriptions,
+	)
+}
+
+func (a *App) handleGNMIServerClientsGet(w http.ResponseWriter, r *http.Request) {
+	if a.subscriptionQuotas == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{"gnmi-server per client subscriptions quotas are not configured"}})
+		return
+	}
+	a.handlerCommonGet(w, a.subscriptionQuotas.Usage())
+}
+
+func (a *App) initServerTargetsSem() {
+	if a.Config.GnmiServer.MaxConcurrentTargets == 0 {
+		return
+	}
+	a.serverTargetsSem = make(chan struct{}, a.Config.GnmiServer.MaxConcurrentTargets)
+}
+
+// acquireServerTarget blocks until the gnmi-server is allowed to send
+// an RPC to one more target, as per max-concurrent-targets.
+// The returned function must be called once the RPC returned.
+func (a *App) acquireServerTarget(ctx context.Context) (func(), error) {
+	if a.serverTargetsSem == nil {
+		return func() {}, nil
+	}
+	select {
+	case a.serverTargetsSem <- struct{}{}:
+		return func() { <-a.serverTargetsSem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,281 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// gnmi-server RPCs subject to the access rules
+const (
+	aclRPCGet       = "get"
+	aclRPCSet       = "set"
+	aclRPCSubscribe = "subscribe"
+)
+
+// client identity the rules apply to
+// if the client has no rules of its own
+const aclDefaultClient = "*"
+
+var gnmiServerACLDenied = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "gnmi_server",
+	Name:      "acl_denied_requests_total",
+	Help:      "Total number of gNMI requests denied by the access rules, by RPC",
+}, []string{"rpc"})
+
+// serverACL holds the compiled gnmi-server access rules.
+// A client is allowed to send a request if each of its
+// targets and paths is allowed by one of its rules.
+type serverACL struct {
+	// rules by lower case client identity
+	rules map[string][]*aclRule
+}
+
+type aclRule struct {
+	// nil if the rule applies to all the RPCs
+	rpcs map[string]struct{}
+	// nil if the rule applies to all the targets
+	targets []*regexp.Regexp
+	// nil if the rule applies to all the paths
+	paths []*aclPath
+}
+
+type aclPath struct {
+	origin string
+	elems  []*aclPathElem
+}
+
+type aclPathElem struct {
+	name *regexp.Regexp
+	// `...` is only matched by `...`
+	anyDepth bool
+	keys     map[string]*regexp.Regexp
+}
+
+func newServerACL(acl map[string][]*config.ACLRule) (*serverACL, error) {
+	sacl := &serverACL{rules: make(map[string][]*aclRule, len(acl))}
+	for client, rules := range acl {
+		client = strings.ToLower(client)
+		for i, r := range rules {
+			ar, err := newACLRule(r)
+			if err != nil {
+				return nil, fmt.Errorf("client %q rule %d: %v", client, i, err)
+			}
+			sacl.rules[client] = append(sacl.rules[client], ar)
+		}
+	}
+	return sacl, nil
+}
+
+func newACLRule(r *config.ACLRule) (*aclRule, error) {
+	ar := new(aclRule)
+	if len(r.RPCs) > 0 {
+		ar.rpcs = make(map[string]struct{}, len(r.RPCs))
+		for _, rpc := range r.RPCs {
+			ar.rpcs[rpc] = struct{}{}
+		}
+	}
+	for _, t := range r.Targets {
+		ar.targets = append(ar.targets, globRegexp(t))
+	}
+	for _, p := range r.Paths {
+		ap, err := newACLPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ar.paths = append(ar.paths, ap)
+	}
+	return ar, nil
+}
+
+// newACLPath compiles path p, its elements names and keys values can be globs.
+func newACLPath(p string) (*aclPath, error) {
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %v", p, err)
+	}
+	ap := &aclPath{
+		origin: gp.GetOrigin(),
+		elems:  make([]*aclPathElem, 0, len(gp.GetElem())),
+	}
+	for _, pe := range gp.GetElem() {
+		ae := &aclPathElem{
+			name:     globRegexp(pe.GetName()),
+			anyDepth: pe.GetName() == "...",
+			keys:     make(map[string]*regexp.Regexp, len(pe.GetKey())),
+		}
+		for k, v := range pe.GetKey() {
+			ae.keys[k] = globRegexp(v)
+		}
+		ap.elems = append(ap.elems, ae)
+	}
+	return ap, nil
+}
+
+// globRegexp compiles glob g, in which `*` matches any sequence of characters.
+func globRegexp(g string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(g), `\*`, ".*") + "$")
+}
+
+// authorize returns a PermissionDenied error if the client sending a request over ctx
+// is not allowed to send rpc to the targets tn with the paths prefixed by prefix.
+// The client is identified by the common name of its verified certificate,
+// the clients without one get the `*` rules.
+// tn is a comma separated list of target names, an empty value or `*` means all the targets,
+// which is only allowed by a rule applying to all the targets or with a `*` target.
+func (acl *serverACL) authorize(ctx context.Context, rpc, tn string, prefix *gnmi.Path, paths []*gnmi.Path) error {
+	if acl == nil {
+		return nil
+	}
+	client := strings.ToLower(clientCertCommonName(ctx))
+	rules, ok := acl.rules[client]
+	if !ok {
+		rules = acl.rules[aclDefaultClient]
+	}
+	if tn == "" {
+		tn = "*"
+	}
+	if len(paths) == 0 {
+		paths = []*gnmi.Path{{}}
+	}
+	for _, t := range strings.Split(tn, ",") {
+		for _, p := range paths {
+			fp := &gnmi.Path{
+				Origin: prefix.GetOrigin(),
+				Elem:   append(append([]*gnmi.PathElem{}, prefix.GetElem()...), p.GetElem()...),
+			}
+			if fp.Origin == "" {
+				fp.Origin = p.GetOrigin()
+			}
+			if !allowedBy(rules, rpc, t, fp) {
+				gnmiServerACLDenied.WithLabelValues(rpc).Inc()
+				return status.Errorf(codes.PermissionDenied, "client %q is not allowed to %s path %q of target %q",
+					client, rpc, "/"+path.GnmiPathToXPath(fp, false), t)
+			}
+		}
+	}
+	return nil
+}
+
+func allowedBy(rules []*aclRule, rpc, target string, p *gnmi.Path) bool {
+	for _, r := range rules {
+		if r.allows(rpc, target, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *aclRule) allows(rpc, target string, p *gnmi.Path) bool {
+	if r.rpcs != nil {
+		if _, ok := r.rpcs[rpc]; !ok {
+			return false
+		}
+	}
+	if r.targets != nil && !matchAny(r.targets, target) {
+		return false
+	}
+	if r.paths == nil {
+		return true
+	}
+	for _, ap := range r.paths {
+		if ap.covers(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether path p is the path ap or one of its descendants.
+func (ap *aclPath) covers(p *gnmi.Path) bool {
+	if ap.origin != "" && ap.origin != p.GetOrigin() {
+		return false
+	}
+	elems := p.GetElem()
+	if len(elems) < len(ap.elems) {
+		return false
+	}
+	for i, ae := range ap.elems {
+		pe := elems[i]
+		if pe.GetName() == "..." && !ae.anyDepth {
+			return false
+		}
+		if !ae.name.MatchString(pe.GetName()) {
+			return false
+		}
+		for k, re := range ae.keys {
+			v, ok := pe.GetKey()[k]
+			if !ok {
+				// a missing key selects all the list entries
+				v = "*"
+			}
+			if !re.MatchString(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (a *App) initServerACL() error {
+	if len(a.Config.GnmiServer.ACL) == 0 {
+		return nil
+	}
+	var err error
+	a.serverACL, err = newServerACL(a.Config.GnmiServer.ACL)
+	return err
+}
+
+// setRequestPaths returns the paths of the deletes, replaces, updates
+// and union replaces of Set request req.
+func setRequestPaths(req *gnmi.SetRequest) []*gnmi.Path {
+	paths := make([]*gnmi.Path, 0, len(req.GetDelete())+len(req.GetReplace())+len(req.GetUpdate())+len(req.GetUnionReplace()))
+	paths = append(paths, req.GetDelete()...)
+	for _, upd := range req.GetReplace() {
+		paths = append(paths, upd.GetPath())
+	}
+	for _, upd := range req.GetUpdate() {
+		paths = append(paths, upd.GetPath())
+	}
+	for _, upd := range req.GetUnionReplace() {
+		paths = append(paths, upd.GetPath())
+	}
+	return paths
+}
+
+// subscribeRequestPaths returns the paths of the subscriptions of req.
+func subscribeRequestPaths(req *gnmi.SubscribeRequest) []*gnmi.Path {
+	subs := req.GetSubscribe().GetSubscription()
+	paths := make([]*gnmi.Path, 0, len(subs))
+	for _, sub := range subs {
+		paths = append(paths, sub.GetPath())
+	}
+	return paths
+}
@@ -0,0 +1,212 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+func TestServerACLAuthorize(t *testing.T) {
+	acl, err := newServerACL(map[string][]*config.ACLRule{
+		"Grafana": {
+			{
+				RPCs:    []string{aclRPCGet, aclRPCSubscribe},
+				Targets: []string{"leaf*"},
+				Paths: []string{
+					"/interfaces/interface[name=ethernet-1/*]/statistics",
+					"/system/name",
+				},
+			},
+		},
+		"admin": {{}},
+		"*": {
+			{
+				RPCs:  []string{aclRPCGet},
+				Paths: []string{"/system"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		client  string
+		rpc     string
+		target  string
+		prefix  string
+		paths   []string
+		allowed bool
+	}{
+		{
+			name:    "allowed_path",
+			client:  "grafana",
+			rpc:     aclRPCSubscribe,
+			target:  "leaf1",
+			paths:   []string{"/interfaces/interface[name=ethernet-1/1]/statistics/in-octets"},
+			allowed: true,
+		},
+		{
+			name:    "allowed_with_prefix",
+			client:  "GRAFANA",
+			rpc:     aclRPCGet,
+			target:  "leaf1,leaf2",
+			prefix:  "/interfaces/interface[name=ethernet-1/2]",
+			paths:   []string{"statistics", "/statistics/out-octets"},
+			allowed: true,
+		},
+		{
+			name:   "denied_rpc",
+			client: "grafana",
+			rpc:    aclRPCSet,
+			target: "leaf1",
+			paths:  []string{"/system/name"},
+		},
+		{
+			name:   "denied_target",
+			client: "grafana",
+			rpc:    aclRPCGet,
+			target: "leaf1,spine1",
+			paths:  []string{"/system/name"},
+		},
+		{
+			name:   "denied_all_targets",
+			client: "grafana",
+			rpc:    aclRPCGet,
+			paths:  []string{"/system/name"},
+		},
+		{
+			name:   "denied_parent_path",
+			client: "grafana",
+			rpc:    aclRPCSubscribe,
+			target: "leaf1",
+			paths:  []string{"/interfaces/interface[name=ethernet-1/1]"},
+		},
+		{
+			name:   "denied_wildcard_key",
+			client: "grafana",
+			rpc:    aclRPCSubscribe,
+			target: "leaf1",
+			paths:  []string{"/interfaces/interface/statistics"},
+		},
+		{
+			name:   "denied_no_paths",
+			client: "grafana",
+			rpc:    aclRPCGet,
+			target: "leaf1",
+		},
+		{
+			name:    "admin",
+			client:  "admin",
+			rpc:     aclRPCSet,
+			target:  "*",
+			paths:   []string{"/"},
+			allowed: true,
+		},
+		{
+			name:    "default_rules",
+			client:  "other",
+			rpc:     aclRPCGet,
+			target:  "spine1",
+			paths:   []string{"/system/information"},
+			allowed: true,
+		},
+		{
+			name:   "default_rules_denied",
+			client: "other",
+			rpc:    aclRPCSubscribe,
+			target: "spine1",
+			paths:  []string{"/system/information"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := certPeerContext(tt.client)
+			var prefix *gnmi.Path
+			if tt.prefix != "" {
+				prefix, err = path.ParsePath(tt.prefix)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			paths := make([]*gnmi.Path, 0, len(tt.paths))
+			for _, p := range tt.paths {
+				gp, err := path.ParsePath(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				paths = append(paths, gp)
+			}
+			err := acl.authorize(ctx, tt.rpc, tt.target, prefix, paths)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if status.Code(err) != codes.PermissionDenied {
+				t.Errorf("expected a PermissionDenied error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServerACLNotConfigured(t *testing.T) {
+	var acl *serverACL
+	err := acl.authorize(context.Background(), aclRPCSet, "*", nil, nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestServerACLNoRules(t *testing.T) {
+	acl, err := newServerACL(map[string][]*config.ACLRule{
+		"admin": {{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = acl.authorize(certPeerContext("user1"), aclRPCGet, "leaf1", nil, nil)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a PermissionDenied error, got %v", err)
+	}
+}
+
+func TestServerACLUnverifiedClient(t *testing.T) {
+	acl, err := newServerACL(map[string][]*config.ACLRule{
+		"admin": {{}},
+		"*": {
+			{
+				RPCs: []string{aclRPCGet},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the username metadata is not an identity
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("username", "admin"))
+	err = acl.authorize(ctx, aclRPCSet, "leaf1", nil, nil)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a PermissionDenied error, got %v", err)
+	}
+	err = acl.authorize(ctx, aclRPCGet, "leaf1", nil, nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
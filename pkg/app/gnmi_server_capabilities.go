@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/server"
+)
+
+// gnmicModel is the model of the configuration and state
+// exposed by the gnmi-server under the gnmic origin.
+var gnmicModel = &capabilitiesModel{
+	Name:         "gnmic",
+	Organization: "openconfig",
+}
+
+// serverCapabilitiesHandler returns the union of the models and encodings
+// cached from the targets Capabilities responses, plus the gnmic origin model.
+// The targets supporting each model are listed in a registered extension.
+// Only the targets the client may address are taken into account.
+func (a *App) serverCapabilitiesHandler(ctx context.Context, req *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	tcaps := a.clientCertTargets.filterCapabilities(ctx, a.getTargetsCapabilities())
+	rsp, ct := aggregateCapabilities(tcaps)
+	err := api.Extension_CapabilitiesTargets(api.DefaultCapabilitiesTargetsExtensionID, ct)(rsp)
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func aggregateCapabilities(tcaps []*targetCapabilities) (*gnmi.CapabilityResponse, *api.CapabilitiesTargets) {
+	models := make(map[capabilitiesModel]*api.ModelTargets)
+	gm := *gnmicModel
+	gm.Version = version
+	models[gm] = &api.ModelTargets{
+		Name:         gm.Name,
+		Organization: gm.Organization,
+		Version:      gm.Version,
+	}
+	// the gnmic origin values are JSON encoded
+	encodings := map[gnmi.Encoding]struct{}{
+		gnmi.Encoding_JSON:      {},
+		gnmi.Encoding_JSON_IETF: {},
+	}
+	for _, tc := range tcaps {
+		for _, m := range tc.SupportedModels {
+			mt, ok := models[*m]
+			if !ok {
+				mt = &api.ModelTargets{
+					Name:         m.Name,
+					Organization: m.Organization,
+					Version:      m.Version,
+				}
+				models[*m] = mt
+			}
+			// tcaps is sorted by target name
+			mt.Targets = append(mt.Targets, tc.Target)
+		}
+		for _, enc := range tc.SupportedEncodings {
+			if v, ok := gnmi.Encoding_value[strings.ToUpper(enc)]; ok {
+				encodings[gnmi.Encoding(v)] = struct{}{}
+			}
+		}
+	}
+
+	rsp := &gnmi.CapabilityResponse{
+		SupportedModels:    make([]*gnmi.ModelData, 0, len(models)),
+		SupportedEncodings: make([]gnmi.Encoding, 0, len(encodings)),
+		GNMIVersion:        server.GNMIVersion,
+	}
+	ct := &api.CapabilitiesTargets{
+		Models: make([]*api.ModelTargets, 0, len(models)),
+	}
+	for _, mt := range models {
+		ct.Models = append(ct.Models, mt)
+	}
+	sort.Slice(ct.Models, func(i, j int) bool {
+		if ct.Models[i].Name != ct.Models[j].Name {
+			return ct.Models[i].Name < ct.Models[j].Name
+		}
+		if ct.Models[i].Organization != ct.Models[j].Organization {
+			return ct.Models[i].Organization < ct.Models[j].Organization
+		}
+		return ct.Models[i].Version < ct.Models[j].Version
+	})
+	for _, mt := range ct.Models {
+		rsp.SupportedModels = append(rsp.SupportedModels, &gnmi.ModelData{
+			Name:         mt.Name,
+			Organization: mt.Organization,
+			Version:      mt.Version,
+		})
+	}
+	for enc := range encodings {
+		rsp.SupportedEncodings = append(rsp.SupportedEncodings, enc)
+	}
+	sort.Slice(rsp.SupportedEncodings, func(i, j int) bool {
+		return rsp.SupportedEncodings[i] < rsp.SupportedEncodings[j]
+	})
+	return rsp, ct
+}
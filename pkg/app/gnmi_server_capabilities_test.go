@@ -0,0 +1,135 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+)
+
+func TestServerCapabilitiesHandler(t *testing.T) {
+	a := New()
+	a.targetsCapabilities["r2"] = newTargetCapabilities("r2", &gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{
+			{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "3.0.0"},
+			{Name: "srl_nokia-interfaces", Organization: "Nokia", Version: "2024-03-31"},
+		},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_JSON_IETF, gnmi.Encoding_PROTO},
+		GNMIVersion:        "0.10.0",
+	})
+	a.targetsCapabilities["r1"] = newTargetCapabilities("r1", &gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{
+			{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "3.0.0"},
+			{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "2.5.0"},
+		},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_ASCII},
+		GNMIVersion:        "0.7.0",
+	})
+
+	rsp, err := a.serverCapabilitiesHandler(context.Background(), new(gnmi.CapabilityRequest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEncodings := []gnmi.Encoding{
+		gnmi.Encoding_JSON,
+		gnmi.Encoding_PROTO,
+		gnmi.Encoding_ASCII,
+		gnmi.Encoding_JSON_IETF,
+	}
+	if !reflect.DeepEqual(rsp.GetSupportedEncodings(), wantEncodings) {
+		t.Errorf("got encodings %v, expected %v", rsp.GetSupportedEncodings(), wantEncodings)
+	}
+	wantModels := []*api.ModelTargets{
+		{Name: "gnmic", Organization: "openconfig", Version: version},
+		{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "2.5.0", Targets: []string{"r1"}},
+		{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "3.0.0", Targets: []string{"r1", "r2"}},
+		{Name: "srl_nokia-interfaces", Organization: "Nokia", Version: "2024-03-31", Targets: []string{"r2"}},
+	}
+	if len(rsp.GetSupportedModels()) != len(wantModels) {
+		t.Fatalf("got models %v, expected %d models", rsp.GetSupportedModels(), len(wantModels))
+	}
+	for i, m := range rsp.GetSupportedModels() {
+		if m.GetName() != wantModels[i].Name || m.GetVersion() != wantModels[i].Version {
+			t.Errorf("model %d: got %v, expected %+v", i, m, wantModels[i])
+		}
+	}
+	ct, err := api.CapabilitiesTargetsFromExtensions(api.DefaultCapabilitiesTargetsExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct == nil || !reflect.DeepEqual(ct.Models, wantModels) {
+		t.Errorf("got models targets %+v, expected %+v", ct, wantModels)
+	}
+}
+
+func TestServerCapabilitiesHandlerClientCertTargets(t *testing.T) {
+	a := New()
+	a.targetsCapabilities["leaf1"] = newTargetCapabilities("leaf1", &gnmi.CapabilityResponse{
+		SupportedModels:    []*gnmi.ModelData{{Name: "openconfig-interfaces", Version: "3.0.0"}},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_JSON_IETF},
+	})
+	a.targetsCapabilities["spine1"] = newTargetCapabilities("spine1", &gnmi.CapabilityResponse{
+		SupportedModels:    []*gnmi.ModelData{{Name: "srl_nokia-interfaces", Version: "2024-03-31"}},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_PROTO},
+	})
+	a.clientCertTargets = newClientCertTargets(map[string][]string{
+		"grafana": {"leaf*"},
+	})
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []*api.ModelTargets
+	}{
+		{
+			name: "mapped",
+			ctx:  certPeerContext("grafana"),
+			want: []*api.ModelTargets{
+				{Name: "gnmic", Organization: "openconfig", Version: version},
+				{Name: "openconfig-interfaces", Version: "3.0.0", Targets: []string{"leaf1"}},
+			},
+		},
+		{
+			name: "not_mapped",
+			ctx:  certPeerContext("other"),
+			want: []*api.ModelTargets{
+				{Name: "gnmic", Organization: "openconfig", Version: version},
+			},
+		},
+		{
+			name: "no_certificate",
+			ctx:  context.Background(),
+			want: []*api.ModelTargets{
+				{Name: "gnmic", Organization: "openconfig", Version: version},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := a.serverCapabilitiesHandler(tt.ctx, new(gnmi.CapabilityRequest))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct, err := api.CapabilitiesTargetsFromExtensions(api.DefaultCapabilitiesTargetsExtensionID, rsp.GetExtension())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ct == nil || !reflect.DeepEqual(ct.Models, tt.want) {
+				t.Errorf("got models targets %+v, expected %+v", ct, tt.want)
+			}
+			if len(rsp.GetSupportedModels()) != len(tt.want) {
+				t.Errorf("got models %v, expected %d models", rsp.GetSupportedModels(), len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// clientCertTargets restricts the targets a gnmi-server client may address
+// based on the common name of its verified certificate.
+type clientCertTargets struct {
+	// targets names globs by lower case certificate common name
+	targets map[string][]*regexp.Regexp
+}
+
+func newClientCertTargets(m map[string][]string) *clientCertTargets {
+	ct := &clientCertTargets{targets: make(map[string][]*regexp.Regexp, len(m))}
+	for cn, targets := range m {
+		cn = strings.ToLower(cn)
+		for _, t := range targets {
+			ct.targets[cn] = append(ct.targets[cn], globRegexp(t))
+		}
+	}
+	return ct
+}
+
+// authorize returns a PermissionDenied error if the certificate of the client sending
+// a request over ctx is not mapped to each of the targets tn.
+// tn is a comma separated list of target names, an empty value or `*` means all the targets,
+// which is only allowed by a `*` target.
+func (ct *clientCertTargets) authorize(ctx context.Context, rpc, tn string) error {
+	if ct == nil {
+		return nil
+	}
+	cn := clientCertCommonName(ctx)
+	if cn == "" {
+		gnmiServerACLDenied.WithLabelValues(rpc).Inc()
+		return status.Error(codes.PermissionDenied, "a verified client certificate is required")
+	}
+	globs, ok := ct.targets[strings.ToLower(cn)]
+	if !ok {
+		gnmiServerACLDenied.WithLabelValues(rpc).Inc()
+		return status.Errorf(codes.PermissionDenied, "client certificate %q is not mapped to any target", cn)
+	}
+	if tn == "" {
+		tn = "*"
+	}
+	for _, t := range strings.Split(tn, ",") {
+		if !matchAny(globs, t) {
+			gnmiServerACLDenied.WithLabelValues(rpc).Inc()
+			return status.Errorf(codes.PermissionDenied, "client certificate %q is not allowed to %s target %q", cn, rpc, t)
+		}
+	}
+	return nil
+}
+
+// filterCapabilities returns the capabilities of the targets in tcaps the
+// certificate of the client sending a request over ctx is mapped to.
+func (ct *clientCertTargets) filterCapabilities(ctx context.Context, tcaps []*targetCapabilities) []*targetCapabilities {
+	if ct == nil {
+		return tcaps
+	}
+	globs := ct.targets[strings.ToLower(clientCertCommonName(ctx))]
+	allowed := make([]*targetCapabilities, 0, len(tcaps))
+	for _, tc := range tcaps {
+		if matchAny(globs, tc.Target) {
+			allowed = append(allowed, tc)
+		}
+	}
+	return allowed
+}
+
+// clientCertCommonName returns the common name of the verified
+// certificate of the client sending a request over ctx.
+func clientCertCommonName(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	for _, chain := range tlsInfo.State.VerifiedChains {
+		if len(chain) > 0 && chain[0].Subject.CommonName != "" {
+			return chain[0].Subject.CommonName
+		}
+	}
+	return ""
+}
+
+func (a *App) initClientCertTargets() {
+	if len(a.Config.GnmiServer.ClientCertTargets) == 0 {
+		return
+	}
+	a.clientCertTargets = newClientCertTargets(a.Config.GnmiServer.ClientCertTargets)
+}
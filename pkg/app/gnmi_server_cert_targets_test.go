@@ -0,0 +1,78 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func certPeerContext(cn string) context.Context {
+	pr := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}}
+	if cn != "" {
+		pr.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}},
+		}}
+	}
+	return peer.NewContext(context.Background(), pr)
+}
+
+func TestClientCertTargetsAuthorize(t *testing.T) {
+	ct := newClientCertTargets(map[string][]string{
+		"grafana": {"leaf1", "spine*"},
+		"noc":     {"*"},
+	})
+	tests := []struct {
+		name    string
+		cn      string
+		target  string
+		allowed bool
+	}{
+		{name: "mapped_target", cn: "Grafana", target: "leaf1", allowed: true},
+		{name: "mapped_glob", cn: "grafana", target: "spine2", allowed: true},
+		{name: "mapped_targets_list", cn: "grafana", target: "leaf1,spine1", allowed: true},
+		{name: "unmapped_target", cn: "grafana", target: "leaf2"},
+		{name: "unmapped_target_in_list", cn: "grafana", target: "leaf1,leaf2"},
+		{name: "all_targets", cn: "grafana", target: ""},
+		{name: "all_targets_star", cn: "grafana", target: "*"},
+		{name: "wildcard_mapping", cn: "noc", target: "", allowed: true},
+		{name: "unknown_cn", cn: "other", target: "leaf1"},
+		{name: "no_certificate", target: "leaf1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ct.authorize(certPeerContext(tt.cn), aclRPCGet, tt.target)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if status.Code(err) != codes.PermissionDenied {
+				t.Errorf("expected a PermissionDenied error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClientCertTargetsNotConfigured(t *testing.T) {
+	var ct *clientCertTargets
+	if err := ct.authorize(context.Background(), aclRPCSet, "leaf1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
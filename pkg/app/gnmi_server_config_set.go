@@ -0,0 +1,443 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// configuration objects settable with the `gnmic` origin
+const (
+	configSetTargets       = "targets"
+	configSetSubscriptions = "subscriptions"
+)
+
+// configSet stages the changes of a Set request with the `gnmic` origin
+// to the targets and subscriptions configuration.
+// It is used with the configLock held.
+type configSet struct {
+	a             *App
+	targets       map[string]*types.TargetConfig
+	subscriptions map[string]*types.SubscriptionConfig
+	// names of the changed objects, true if the object is deleted
+	changedTargets       map[string]bool
+	changedSubscriptions map[string]bool
+}
+
+// handlegNMIcInternalSet applies Set request req, with the `gnmic` origin,
+// to the targets and subscriptions configuration.
+// The request is applied as a whole or not at all: the deletes, replaces
+// and updates are staged in this order, then validated together.
+// The running targets affected by the changes are restarted.
+func (a *App) handlegNMIcInternalSet(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+	if len(req.GetUnionReplace()) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "union_replace is not supported with the `gnmic` origin")
+	}
+	results := make([]*gnmi.UpdateResult, 0, len(req.GetDelete())+len(req.GetReplace())+len(req.GetUpdate()))
+
+	a.configLock.Lock()
+	cs := a.newConfigSet()
+	for _, p := range req.GetDelete() {
+		err := cs.apply(gnmi.UpdateResult_DELETE, path.PathElems(req.GetPrefix(), p), nil)
+		if err != nil {
+			a.configLock.Unlock()
+			return nil, err
+		}
+		results = append(results, &gnmi.UpdateResult{Path: p, Op: gnmi.UpdateResult_DELETE})
+	}
+	for _, upd := range req.GetReplace() {
+		err := cs.apply(gnmi.UpdateResult_REPLACE, path.PathElems(req.GetPrefix(), upd.GetPath()), upd.GetVal())
+		if err != nil {
+			a.configLock.Unlock()
+			return nil, err
+		}
+		results = append(results, &gnmi.UpdateResult{Path: upd.GetPath(), Op: gnmi.UpdateResult_REPLACE})
+	}
+	for _, upd := range req.GetUpdate() {
+		err := cs.apply(gnmi.UpdateResult_UPDATE, path.PathElems(req.GetPrefix(), upd.GetPath()), upd.GetVal())
+		if err != nil {
+			a.configLock.Unlock()
+			return nil, err
+		}
+		results = append(results, &gnmi.UpdateResult{Path: upd.GetPath(), Op: gnmi.UpdateResult_UPDATE})
+	}
+	err := cs.validate()
+	if err != nil {
+		a.configLock.Unlock()
+		return nil, err
+	}
+	deleted, restart := cs.commit()
+	a.configLock.Unlock()
+
+	for _, name := range deleted {
+		err = a.deleteTargetState(ctx, name)
+		if err != nil {
+			a.Logger.Printf("failed to delete target %q: %v", name, err)
+		}
+	}
+	for name, tc := range restart {
+		a.restartTarget(name, tc)
+	}
+	return &gnmi.SetResponse{
+		Prefix:    req.GetPrefix(),
+		Response:  results,
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+func (a *App) newConfigSet() *configSet {
+	cs := &configSet{
+		a:                    a,
+		targets:              make(map[string]*types.TargetConfig, len(a.Config.Targets)),
+		subscriptions:        make(map[string]*types.SubscriptionConfig, len(a.Config.Subscriptions)),
+		changedTargets:       make(map[string]bool),
+		changedSubscriptions: make(map[string]bool),
+	}
+	for n, tc := range a.Config.Targets {
+		cs.targets[n] = tc
+	}
+	for n, sc := range a.Config.Subscriptions {
+		cs.subscriptions[n] = sc
+	}
+	return cs
+}
+
+// apply stages operation op on the path made of elems, with value val.
+// elems starts with `targets[name=<name>]` or `subscriptions[name=<name>]`,
+// optionally followed by the path of one of the object fields.
+func (cs *configSet) apply(op gnmi.UpdateResult_Operation, elems []*gnmi.PathElem, val *gnmi.TypedValue) error {
+	if len(elems) == 0 {
+		return status.Errorf(codes.InvalidArgument, "missing path")
+	}
+	kind := elems[0].GetName()
+	switch kind {
+	case configSetTargets, configSetSubscriptions:
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown path element %q", kind)
+	}
+	name := elems[0].GetKey()["name"]
+	if name == "" || strings.Contains(name, "*") {
+		return status.Errorf(codes.InvalidArgument, "path element %q requires a name key", kind)
+	}
+	fields := make([]string, 0, len(elems)-1)
+	for _, e := range elems[1:] {
+		if len(e.GetKey()) > 0 {
+			return status.Errorf(codes.InvalidArgument, "unexpected keys in path element %q", e.GetName())
+		}
+		fields = append(fields, e.GetName())
+	}
+	if len(fields) > 0 && fields[0] == "name" {
+		return status.Errorf(codes.InvalidArgument, "%s %q: the name can't be changed", kind, name)
+	}
+	current, exists, err := cs.object(kind, name)
+	if err != nil {
+		return err
+	}
+	if op == gnmi.UpdateResult_DELETE {
+		// deleting a path that does not exist is not an error
+		if !exists {
+			return nil
+		}
+		if len(fields) == 0 {
+			cs.delete(kind, name)
+			return nil
+		}
+		deleteConfigField(current, fields)
+		return cs.set(kind, name, current)
+	}
+	v, err := configSetValue(val)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "%s %q: value must be a JSON object", kind, name)
+		}
+		if n, ok := m["name"]; ok && n != name {
+			return status.Errorf(codes.InvalidArgument, "%s %q: name %v does not match the path", kind, name, n)
+		}
+		if op == gnmi.UpdateResult_UPDATE && exists {
+			mergeConfigMaps(current, m)
+			m = current
+		}
+		return cs.set(kind, name, m)
+	}
+	if !exists {
+		return status.Errorf(codes.NotFound, "%s %q not found", kind, name)
+	}
+	err = setConfigField(current, fields, v, op == gnmi.UpdateResult_UPDATE)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s %q: %v", kind, name, err)
+	}
+	return cs.set(kind, name, current)
+}
+
+// object returns the staged object name of kind as a map.
+func (cs *configSet) object(kind, name string) (map[string]interface{}, bool, error) {
+	var v interface{}
+	switch kind {
+	case configSetTargets:
+		tc, ok := cs.targets[name]
+		if !ok {
+			return nil, false, nil
+		}
+		v = tc
+	case configSetSubscriptions:
+		sc, ok := cs.subscriptions[name]
+		if !ok {
+			return nil, false, nil
+		}
+		v = sc
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false, status.Errorf(codes.Internal, "%s %q: %v", kind, name, err)
+	}
+	m := make(map[string]interface{})
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, false, status.Errorf(codes.Internal, "%s %q: %v", kind, name, err)
+	}
+	return m, true, nil
+}
+
+// set stages object name of kind from its map representation m.
+func (cs *configSet) set(kind, name string, m map[string]interface{}) error {
+	m["name"] = name
+	switch kind {
+	case configSetTargets:
+		tc := new(types.TargetConfig)
+		err := decodeConfigMap(m, tc)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "target %q: %v", name, err)
+		}
+		if tc.Address == "" {
+			tc.Address = name
+		}
+		err = cs.a.Config.SetTargetConfigDefaults(tc)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "target %q: %v", name, err)
+		}
+		cs.targets[name] = tc
+		cs.changedTargets[name] = false
+	case configSetSubscriptions:
+		sc := new(types.SubscriptionConfig)
+		err := decodeConfigMap(m, sc)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "subscription %q: %v", name, err)
+		}
+		err = cs.a.Config.SetSubscriptionConfigDefaults(sc)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "subscription %q: %v", name, err)
+		}
+		cs.subscriptions[name] = sc
+		cs.changedSubscriptions[name] = false
+	}
+	return nil
+}
+
+func (cs *configSet) delete(kind, name string) {
+	switch kind {
+	case configSetTargets:
+		delete(cs.targets, name)
+		cs.changedTargets[name] = true
+	case configSetSubscriptions:
+		delete(cs.subscriptions, name)
+		cs.changedSubscriptions[name] = true
+	}
+}
+
+// validate checks that the staged targets only use existing subscriptions.
+func (cs *configSet) validate() error {
+	for name, deleted := range cs.changedSubscriptions {
+		if !deleted {
+			continue
+		}
+		for _, tc := range cs.targets {
+			for _, s := range tc.Subscriptions {
+				if s == name {
+					return status.Errorf(codes.FailedPrecondition, "subscription %q is used by target %q", name, tc.Name)
+				}
+			}
+		}
+	}
+	for name, deleted := range cs.changedTargets {
+		if deleted {
+			continue
+		}
+		for _, s := range cs.targets[name].Subscriptions {
+			if _, ok := cs.subscriptions[s]; !ok {
+				return status.Errorf(codes.InvalidArgument, "target %q: unknown subscription %q", name, s)
+			}
+		}
+	}
+	return nil
+}
+
+// commit writes the staged changes to the configuration.
+// It returns the names of the deleted targets and the configs
+// of the existing targets to restart.
+func (cs *configSet) commit() ([]string, map[string]*types.TargetConfig) {
+	a := cs.a
+	deleted := make([]string, 0)
+	restart := make(map[string]*types.TargetConfig)
+	changedSubs := make([]string, 0)
+	for name, del := range cs.changedSubscriptions {
+		current, exists := a.Config.Subscriptions[name]
+		if del {
+			delete(a.Config.Subscriptions, name)
+			a.Logger.Printf("subscription %q deleted from config", name)
+			continue
+		}
+		sc := cs.subscriptions[name]
+		if exists && configETag(current) == configETag(sc) {
+			continue
+		}
+		a.Config.Subscriptions[name] = sc
+		a.Logger.Printf("subscription %q config set", name)
+		if exists {
+			changedSubs = append(changedSubs, name)
+		}
+	}
+	for name, del := range cs.changedTargets {
+		current, exists := a.Config.Targets[name]
+		if del {
+			delete(a.Config.Targets, name)
+			a.Logger.Printf("target %q deleted from config", name)
+			deleted = append(deleted, name)
+			continue
+		}
+		tc := cs.targets[name]
+		if exists && configETag(current) == configETag(tc) {
+			continue
+		}
+		a.Config.Targets[name] = tc
+		a.Logger.Printf("target %q config set", name)
+		if exists {
+			restart[name] = tc
+		}
+	}
+	// restart the targets using the previous config of the changed subscriptions
+	for _, sn := range changedSubs {
+		for _, name := range a.subscriptionRunningTargets(sn) {
+			if tc, ok := a.Config.Targets[name]; ok {
+				restart[name] = tc
+			}
+		}
+	}
+	return deleted, restart
+}
+
+// configSetValue returns the value of tv, as decoded from its JSON representation.
+func configSetValue(tv *gnmi.TypedValue) (interface{}, error) {
+	var b []byte
+	switch tv.GetValue().(type) {
+	case *gnmi.TypedValue_JsonVal:
+		b = tv.GetJsonVal()
+	case *gnmi.TypedValue_JsonIetfVal:
+		b = tv.GetJsonIetfVal()
+	default:
+		var err error
+		b, err = scalarJSONValue(tv)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid JSON value: %v", err)
+	}
+	return v, nil
+}
+
+// decodeConfigMap decodes the JSON representation m of a configuration object into v.
+// Durations can be set as strings, e.g: "10s".
+func decodeConfigMap(m map[string]interface{}, v interface{}) error {
+	decoder, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				mapstructure.StringToTimeDurationHookFunc(),
+				mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+			),
+			TagName:     "json",
+			ErrorUnused: true,
+			Result:      v,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(m)
+}
+
+// setConfigField sets the field at fields of m to v.
+// If merge is true and both the current and the new values are objects, they are merged.
+func setConfigField(m map[string]interface{}, fields []string, v interface{}, merge bool) error {
+	for i, f := range fields[:len(fields)-1] {
+		next, ok := m[f]
+		if !ok || next == nil {
+			next = make(map[string]interface{})
+			m[f] = next
+		}
+		nm, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("field %q is not an object", strings.Join(fields[:i+1], "/"))
+		}
+		m = nm
+	}
+	f := fields[len(fields)-1]
+	if merge {
+		cm, ok := m[f].(map[string]interface{})
+		nm, nok := v.(map[string]interface{})
+		if ok && nok {
+			mergeConfigMaps(cm, nm)
+			return nil
+		}
+	}
+	m[f] = v
+	return nil
+}
+
+// deleteConfigField deletes the field at fields of m, if it exists.
+func deleteConfigField(m map[string]interface{}, fields []string) {
+	for _, f := range fields[:len(fields)-1] {
+		nm, ok := m[f].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = nm
+	}
+	delete(m, fields[len(fields)-1])
+}
+
+// mergeConfigMaps merges src into dst, recursively.
+func mergeConfigMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		sm, ok := v.(map[string]interface{})
+		if dm, dok := dst[k].(map[string]interface{}); ok && dok {
+			mergeConfigMaps(dm, sm)
+			continue
+		}
+		dst[k] = v
+	}
+}
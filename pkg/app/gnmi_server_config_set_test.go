@@ -0,0 +1,226 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+func TestServerSetgNMIcOrigin(t *testing.T) {
+	a := New()
+	a.Config.FileConfig.Set("gnmi-server/address", ":0")
+	if err := a.Config.GetGNMIServer(); err != nil {
+		t.Fatal(err)
+	}
+	mustPath := func(s string) *gnmi.Path {
+		p, err := path.ParsePath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	jsonVal := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(s)}}
+	}
+	set := func(req *gnmi.SetRequest) error {
+		_, err := a.serverSetHandler(context.Background(), req)
+		return err
+	}
+
+	// disabled by default
+	err := set(&gnmi.SetRequest{
+		Delete: []*gnmi.Path{mustPath("gnmic:/targets[name=r1]")},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected a PermissionDenied error, got %v", err)
+	}
+	a.Config.GnmiServer.ConfigSet = true
+
+	// create a target and its subscription
+	err = set(&gnmi.SetRequest{
+		Replace: []*gnmi.Update{
+			{
+				Path: mustPath("gnmic:/targets[name=r1]"),
+				Val:  jsonVal(`{"address": "10.0.0.1:57400", "subscriptions": ["sub1"], "timeout": "5s", "tags": ["leaf"]}`),
+			},
+			{
+				Path: mustPath("gnmic:/subscriptions[name=sub1]"),
+				Val:  jsonVal(`{"paths": ["/interfaces"], "sample-interval": "10s"}`),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, ok := a.Config.Targets["r1"]
+	if !ok {
+		t.Fatal("target r1 not created")
+	}
+	if tc.Name != "r1" || tc.Address != "10.0.0.1:57400" || tc.Timeout != 5*time.Second {
+		t.Errorf("unexpected target config %+v", tc)
+	}
+	sc, ok := a.Config.Subscriptions["sub1"]
+	if !ok {
+		t.Fatal("subscription sub1 not created")
+	}
+	if sc.SampleInterval == nil || *sc.SampleInterval != 10*time.Second {
+		t.Errorf("unexpected subscription config %+v", sc)
+	}
+
+	// update a single field, with the prefix holding the object path
+	err = set(&gnmi.SetRequest{
+		Prefix: mustPath("gnmic:/targets[name=r1]"),
+		Update: []*gnmi.Update{
+			{
+				Path: mustPath("gnmic:/address"),
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "10.0.0.2:57400"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc = a.Config.Targets["r1"]
+	if tc.Address != "10.0.0.2:57400" || !reflect.DeepEqual(tc.Subscriptions, []string{"sub1"}) || tc.Timeout != 5*time.Second {
+		t.Errorf("unexpected target config after update %+v", tc)
+	}
+
+	// delete a field
+	err = set(&gnmi.SetRequest{
+		Delete: []*gnmi.Path{mustPath("gnmic:/targets[name=r1]/tags")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := a.Config.Targets["r1"].Tags; len(tags) != 0 {
+		t.Errorf("unexpected target tags %v", tags)
+	}
+
+	tests := []struct {
+		name string
+		req  *gnmi.SetRequest
+		code codes.Code
+	}{
+		{
+			name: "unknown_subscription",
+			req: &gnmi.SetRequest{
+				Update: []*gnmi.Update{{
+					Path: mustPath("gnmic:/targets[name=r1]/subscriptions"),
+					Val:  jsonVal(`["sub2"]`),
+				}},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "subscription_in_use",
+			req: &gnmi.SetRequest{
+				Delete: []*gnmi.Path{mustPath("gnmic:/subscriptions[name=sub1]")},
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "unknown_field",
+			req: &gnmi.SetRequest{
+				Update: []*gnmi.Update{{
+					Path: mustPath("gnmic:/targets[name=r1]/not-a-field"),
+					Val:  jsonVal(`true`),
+				}},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "rename",
+			req: &gnmi.SetRequest{
+				Update: []*gnmi.Update{{
+					Path: mustPath("gnmic:/targets[name=r1]/name"),
+					Val:  jsonVal(`"r2"`),
+				}},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown_target_field",
+			req: &gnmi.SetRequest{
+				Update: []*gnmi.Update{{
+					Path: mustPath("gnmic:/targets[name=r2]/address"),
+					Val:  jsonVal(`"10.0.0.3:57400"`),
+				}},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "missing_name",
+			req: &gnmi.SetRequest{
+				Delete: []*gnmi.Path{mustPath("gnmic:/targets")},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "mixed_origins",
+			req: &gnmi.SetRequest{
+				Delete: []*gnmi.Path{
+					mustPath("gnmic:/targets[name=r1]"),
+					mustPath("/interfaces"),
+				},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// the first update is valid, the request is rejected as a whole
+			name: "atomic",
+			req: &gnmi.SetRequest{
+				Update: []*gnmi.Update{
+					{
+						Path: mustPath("gnmic:/targets[name=r1]/address"),
+						Val:  jsonVal(`"10.0.0.3:57400"`),
+					},
+					{
+						Path: mustPath("gnmic:/targets[name=r1]/timeout"),
+						Val:  jsonVal(`"not-a-duration"`),
+					},
+				},
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := set(tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected a %v error, got %v", tt.code, err)
+			}
+			if got := a.Config.Targets["r1"].Address; got != "10.0.0.2:57400" {
+				t.Errorf("unexpected change of the target address to %q", got)
+			}
+		})
+	}
+
+	// delete the target and its subscription in the same request
+	err = set(&gnmi.SetRequest{
+		Delete: []*gnmi.Path{
+			mustPath("gnmic:/subscriptions[name=sub1]"),
+			mustPath("gnmic:/targets[name=r1]"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Config.Targets) != 0 || len(a.Config.Subscriptions) != 0 {
+		t.Errorf("unexpected config %v, %v", a.Config.Targets, a.Config.Subscriptions)
+	}
+}
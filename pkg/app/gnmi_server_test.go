@@ -0,0 +1,95 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/cache"
+)
+
+func TestCacheGetHandler(t *testing.T) {
+	a := New()
+	c, err := cache.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	a.c = c
+	for _, tn := range []string{"r1", "r2"} {
+		a.c.Write(context.Background(), "sub1", &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: &gnmi.Notification{
+					Timestamp: time.Now().UnixNano(),
+					Prefix:    &gnmi.Path{Target: tn},
+					Update: []*gnmi.Update{
+						{
+							Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}},
+							Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: tn}},
+						},
+						{
+							Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "version"}}},
+							Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "v1"}},
+						},
+					},
+				},
+			},
+		})
+	}
+	tests := []struct {
+		name string
+		req  *gnmi.GetRequest
+		want int
+	}{
+		{
+			name: "single_path",
+			req: &gnmi.GetRequest{
+				Prefix: &gnmi.Path{Target: "r1"},
+				Path:   []*gnmi.Path{{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}}},
+			},
+			want: 1,
+		},
+		{
+			name: "prefix_only",
+			req: &gnmi.GetRequest{
+				Prefix: &gnmi.Path{Target: "r1", Elem: []*gnmi.PathElem{{Name: "system"}}},
+			},
+			want: 2,
+		},
+		{
+			name: "multiple_targets",
+			req: &gnmi.GetRequest{
+				Prefix: &gnmi.Path{Target: "r1,r2"},
+				Path:   []*gnmi.Path{{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}}},
+			},
+			want: 2,
+		},
+		{
+			name: "unknown_target",
+			req: &gnmi.GetRequest{
+				Prefix: &gnmi.Path{Target: "r3"},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := a.cacheGetHandler(tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := len(rsp.GetNotification()); got != tt.want {
+				t.Errorf("expected %d notification(s), got %d: %v", tt.want, got, rsp)
+			}
+		})
+	}
+}
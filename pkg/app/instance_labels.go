@@ -0,0 +1,79 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// addInstanceMeta adds the instance labels to the metadata m of an exported message.
+// Keys already set in m are never overwritten.
+func (a *App) addInstanceMeta(m map[string]string) {
+	if m == nil {
+		return
+	}
+	for k, v := range a.Config.InstanceLabels {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+}
+
+// instanceLabelsGatherer returns g with the instance labels
+// added to all the gathered metrics.
+func (a *App) instanceLabelsGatherer(g prometheus.Gatherer) prometheus.Gatherer {
+	if len(a.Config.InstanceLabels) == 0 {
+		return g
+	}
+	names := make([]string, 0, len(a.Config.InstanceLabels))
+	for k := range a.Config.InstanceLabels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	labels := make([]*dto.LabelPair, 0, len(names))
+	for _, k := range names {
+		labels = append(labels, &dto.LabelPair{
+			Name:  proto.String(k),
+			Value: proto.String(a.Config.InstanceLabels[k]),
+		})
+	}
+	return &labeledGatherer{Gatherer: g, labels: labels}
+}
+
+// labeledGatherer adds labels to the metrics of the wrapped gatherer,
+// a metric label with the same name takes precedence.
+type labeledGatherer struct {
+	prometheus.Gatherer
+	labels []*dto.LabelPair
+}
+
+func (g *labeledGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := g.Gatherer.Gather()
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			existing := make(map[string]struct{}, len(m.GetLabel()))
+			for _, lp := range m.GetLabel() {
+				existing[lp.GetName()] = struct{}{}
+			}
+			for _, lp := range g.labels {
+				if _, ok := existing[lp.GetName()]; !ok {
+					m.Label = append(m.Label, lp)
+				}
+			}
+			sort.Slice(m.Label, func(i, j int) bool {
+				return m.Label[i].GetName() < m.Label[j].GetName()
+			})
+		}
+	}
+	return mfs, err
+}
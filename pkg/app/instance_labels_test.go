@@ -0,0 +1,42 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestInstanceLabelsGatherer(t *testing.T) {
+	a := New()
+	a.Config.InstanceLabels = map[string]string{"collector": "pop1-gnmic-2", "source": "ignored"}
+	reg := prometheus.NewRegistry()
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total"}, []string{"source"})
+	c.WithLabelValues("r1").Inc()
+	reg.MustRegister(c)
+
+	mfs, err := a.instanceLabelsGatherer(reg).Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	labels := make(map[string]string)
+	for _, lp := range mfs[0].GetMetric()[0].GetLabel() {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+	if len(labels) != 2 || labels["collector"] != "pop1-gnmic-2" || labels["source"] != "r1" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+
+	m := map[string]string{"source": "r1"}
+	a.addInstanceMeta(m)
+	if m["collector"] != "pop1-gnmic-2" || m["source"] != "r1" {
+		t.Errorf("unexpected metadata: %v", m)
+	}
+}
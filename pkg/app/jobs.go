@@ -0,0 +1,260 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+const (
+	jobStatusPending   = "pending"
+	jobStatusRunning   = "running"
+	jobStatusCompleted = "completed"
+
+	jobResultOK    = "ok"
+	jobResultError = "error"
+
+	jobTypeTargetsBulk = "targets-bulk"
+
+	// max number of finished jobs kept, the oldest ones are removed first.
+	maxFinishedJobs = 100
+)
+
+// job is an operation run asynchronously on behalf of an API client,
+// its progress and per item results are returned by the jobs endpoints.
+type job struct {
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Status   string       `json:"status,omitempty"`
+	Created  time.Time    `json:"created,omitempty"`
+	Started  time.Time    `json:"started,omitempty"`
+	Finished time.Time    `json:"finished,omitempty"`
+	Total    int          `json:"total"`
+	Done     int          `json:"done"`
+	Failed   int          `json:"failed"`
+	Results  []*jobResult `json:"results,omitempty"`
+
+	m *sync.Mutex
+}
+
+// jobResult is the result of a job operation on a single item.
+type jobResult struct {
+	Item      string `json:"item,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// targetsBulkRequest is the body of a targets bulk request.
+type targetsBulkRequest struct {
+	// targets configurations to add.
+	Add []*types.TargetConfig `json:"add,omitempty"`
+	// names of the targets to delete.
+	Delete []string `json:"delete,omitempty"`
+	// if true, the added targets subscriptions are started.
+	Start bool `json:"start,omitempty"`
+}
+
+func newJob(typ string, total int) *job {
+	return &job{
+		ID:      uuid.New().String(),
+		Type:    typ,
+		Status:  jobStatusPending,
+		Created: time.Now(),
+		Total:   total,
+		Results: make([]*jobResult, 0, total),
+		m:       new(sync.Mutex),
+	}
+}
+
+func (j *job) start() {
+	j.m.Lock()
+	defer j.m.Unlock()
+	j.Status = jobStatusRunning
+	j.Started = time.Now()
+}
+
+func (j *job) finish() {
+	j.m.Lock()
+	defer j.m.Unlock()
+	j.Status = jobStatusCompleted
+	j.Finished = time.Now()
+}
+
+// record adds the result of operation op on item to the job.
+func (j *job) record(item, op string, err error) {
+	j.m.Lock()
+	defer j.m.Unlock()
+	r := &jobResult{Item: item, Operation: op, Status: jobResultOK}
+	if err != nil {
+		r.Status = jobResultError
+		r.Error = err.Error()
+		j.Failed++
+	}
+	j.Done++
+	j.Results = append(j.Results, r)
+}
+
+// snapshot returns a copy of the job, without the results if withResults is false.
+func (j *job) snapshot(withResults bool) *job {
+	j.m.Lock()
+	defer j.m.Unlock()
+	c := *j
+	c.m = nil
+	c.Results = nil
+	if withResults {
+		c.Results = make([]*jobResult, len(j.Results))
+		copy(c.Results, j.Results)
+	}
+	return &c
+}
+
+func (j *job) finished() bool {
+	j.m.Lock()
+	defer j.m.Unlock()
+	return j.Status == jobStatusCompleted
+}
+
+// addJob stores job j, removing the oldest finished jobs above maxFinishedJobs.
+func (a *App) addJob(j *job) {
+	a.jobsLock.Lock()
+	defer a.jobsLock.Unlock()
+	finished := make([]*job, 0, len(a.jobs))
+	for _, oj := range a.jobs {
+		if oj.finished() {
+			finished = append(finished, oj)
+		}
+	}
+	if len(finished) >= maxFinishedJobs {
+		sort.Slice(finished, func(i, k int) bool {
+			return finished[i].Created.Before(finished[k].Created)
+		})
+		for _, oj := range finished[:len(finished)-maxFinishedJobs+1] {
+			delete(a.jobs, oj.ID)
+		}
+	}
+	a.jobs[j.ID] = j
+}
+
+func (a *App) getJob(id string) *job {
+	a.jobsLock.RLock()
+	defer a.jobsLock.RUnlock()
+	return a.jobs[id]
+}
+
+// runTargetsBulk adds and deletes the targets of the bulk request req.
+// The jobs run one at a time, and not along a configuration file reload.
+func (a *App) runTargetsBulk(ctx context.Context, j *job, req *targetsBulkRequest) {
+	defer j.finish()
+	err := a.sem.Acquire(ctx, 1)
+	if err != nil {
+		for _, name := range req.Delete {
+			j.record(name, "delete", err)
+		}
+		for _, tc := range req.Add {
+			j.record(tc.Name, "add", err)
+		}
+		return
+	}
+	defer a.sem.Release(1)
+	j.start()
+	a.Logger.Printf("job %s: running %d target operations", j.ID, j.Total)
+	// deletions first, a target can be replaced in a single request.
+	for _, name := range req.Delete {
+		j.record(name, "delete", a.DeleteTarget(ctx, name))
+	}
+	for _, tc := range req.Add {
+		j.record(tc.Name, "add", a.bulkAddTarget(ctx, tc, req.Start))
+	}
+	a.Logger.Printf("job %s: completed", j.ID)
+}
+
+func (a *App) bulkAddTarget(ctx context.Context, tc *types.TargetConfig, start bool) error {
+	if tc.Name == "" {
+		return errors.New("missing target name and address")
+	}
+	if !a.addTargetConfig(tc) {
+		return fmt.Errorf("target %q already exists", tc.Name)
+	}
+	a.Logger.Printf("target %q added to config", tc.Name)
+	if start && !a.inCluster() {
+		a.wg.Add(1)
+		go a.TargetSubscribeStream(ctx, tc)
+	}
+	return nil
+}
+
+func (a *App) handleConfigTargetsBulkPost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	defer r.Body.Close()
+	req := new(targetsBulkRequest)
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	if len(req.Add)+len(req.Delete) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{"no targets to add or delete"}})
+		return
+	}
+	for _, tc := range req.Add {
+		if tc.Name == "" {
+			tc.Name = tc.Address
+		}
+	}
+	j := newJob(jobTypeTargetsBulk, len(req.Add)+len(req.Delete))
+	a.addJob(j)
+	go a.runTargetsBulk(a.Context(), j, req)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(j.snapshot(false))
+}
+
+func (a *App) handleJobsGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id != "" {
+		j := a.getJob(id)
+		if j == nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("job %q not found", id)}})
+			return
+		}
+		a.handlerCommonGet(w, j.snapshot(true))
+		return
+	}
+	a.jobsLock.RLock()
+	jobs := make([]*job, 0, len(a.jobs))
+	for _, j := range a.jobs {
+		jobs = append(jobs, j.snapshot(false))
+	}
+	a.jobsLock.RUnlock()
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Created.Before(jobs[k].Created)
+	})
+	a.handlerCommonGet(w, jobs)
+}
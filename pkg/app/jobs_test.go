@@ -0,0 +1,92 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestTargetsBulkJob(t *testing.T) {
+	a := New()
+	a.Config.Targets["r1"] = &types.TargetConfig{Name: "r1", Address: "r1:57400"}
+	a.Config.Targets["r2"] = &types.TargetConfig{Name: "r2", Address: "r2:57400"}
+
+	body := `{
+		"add": [{"name": "r3", "address": "r3:57400"}, {"address": "r4:57400"}, {"name": "r2"}],
+		"delete": ["r1", "unknown"]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/targets/bulk", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	a.handleConfigTargetsBulkPost(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body)
+	}
+	accepted := new(job)
+	if err := json.NewDecoder(rec.Body).Decode(accepted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if accepted.ID == "" || accepted.Total != 5 {
+		t.Fatalf("unexpected job: %+v", accepted)
+	}
+
+	get := func() *job {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+accepted.ID, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": accepted.ID})
+		rec := httptest.NewRecorder()
+		a.handleJobsGet(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d", rec.Code)
+		}
+		j := new(job)
+		if err := json.NewDecoder(rec.Body).Decode(j); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return j
+	}
+	var j *job
+	deadline := time.Now().Add(time.Second)
+	for j = get(); j.Status != jobStatusCompleted; j = get() {
+		if time.Now().After(deadline) {
+			t.Fatalf("job not completed: %+v", j)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if j.Done != 5 || j.Failed != 2 || len(j.Results) != 5 {
+		t.Fatalf("unexpected job: %+v", j)
+	}
+	for _, r := range j.Results {
+		failed := r.Item == "unknown" || r.Item == "r2"
+		if failed != (r.Status == jobResultError) {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+	for name, exists := range map[string]bool{"r1": false, "r2": true, "r3": true, "r4:57400": true} {
+		if _, ok := a.Config.Targets[name]; ok != exists {
+			t.Errorf("target %q: expected exists=%v", name, exists)
+		}
+	}
+
+	// unknown job
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/jobs/x", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "x"})
+	rec = httptest.NewRecorder()
+	a.handleJobsGet(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown job", rec.Code)
+	}
+}
@@ -0,0 +1,86 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+func (a *App) LintPreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	switch a.Config.Format {
+	case "", formatJSON:
+	default:
+		return fmt.Errorf("format %q is not supported by the lint command, use %q for a machine readable output", a.Config.Format, formatJSON)
+	}
+	if a.Config.FileConfig.ConfigFileUsed() == "" {
+		return errors.New("no configuration file found, set one with --config")
+	}
+	return nil
+}
+
+func (a *App) LintRunE(cmd *cobra.Command, args []string) error {
+	findings := a.Config.Lint()
+	var numErrors, numWarnings int
+	for _, f := range findings {
+		switch f.Severity {
+		case config.LintSeverityError:
+			numErrors++
+		case config.LintSeverityWarning:
+			numWarnings++
+		}
+	}
+	if a.Config.Format == formatJSON {
+		b, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.out, string(b))
+	} else {
+		if len(findings) > 0 {
+			printLintFindings(a.out, findings)
+		}
+		fmt.Fprintf(a.out, "%s: %d error(s), %d warning(s)\n", a.Config.FileConfig.ConfigFileUsed(), numErrors, numWarnings)
+	}
+	if numErrors > 0 || (a.Config.LocalFlags.LintStrict && numWarnings > 0) {
+		return fmt.Errorf("configuration file has %d error(s) and %d warning(s)", numErrors, numWarnings)
+	}
+	return nil
+}
+
+func printLintFindings(w io.Writer, findings []*config.LintFinding) {
+	tabData := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		tabData = append(tabData, []string{f.Severity, f.Check, f.Path, f.Message})
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Severity", "Check", "Path", "Message"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(tabData)
+	table.Render()
+}
+
+func (a *App) InitLintFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.LintStrict, "strict", "", false, "exit with an error code if warnings are found")
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
+	})
+}
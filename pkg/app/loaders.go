@@ -127,6 +127,8 @@ START:
 				}
 				delete(a.Targets, del)
 			}
+			a.deleteTargetHostname(del)
+			a.deleteTargetCapabilities(del)
 			a.operLock.Unlock()
 		}
 		for _, add := range targetOp.Add {
@@ -18,6 +18,7 @@ import (
 
 const (
 	clusterMetricsUpdatePeriod = 10 * time.Second
+	outputMetricsUpdatePeriod  = 10 * time.Second
 )
 
 // subscribe
@@ -28,6 +29,48 @@ var subscribeResponseReceivedCounter = prometheus.NewCounterVec(prometheus.Count
 	Help:      "Total number of received subscribe response messages",
 }, []string{"source", "subscription"})
 
+var subscriptionUpdatesAbsent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "updates_absent",
+	Help:      "Set to 1 if the subscription did not receive any update within its absence-alarm interval",
+}, []string{"source", "subscription"})
+
+var subscriptionSynced = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "synced",
+	Help:      "Set to 1 if the subscription received the sync response marking the end of its initial updates",
+}, []string{"source", "subscription"})
+
+var subscriptionSyncDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "sync_duration_seconds",
+	Help:      "Time it took the target to send the initial updates of the subscription",
+}, []string{"source", "subscription"})
+
+var targetBudgetExceededMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "number_of_budget_exceeding_messages_total",
+	Help:      "Total number of subscribe response messages received above the target telemetry budget",
+}, []string{"source", "action"})
+
+var subscribeResponsesShedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "number_of_shed_subscribe_response_messages_total",
+	Help:      "Total number of subscribe response messages dropped by priority class because the target buffer was congested",
+}, []string{"source", "priority"})
+
+var subscribeResponseViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "number_of_response_violations_total",
+	Help:      "Total number of gNMI specification violations found in the subscribe responses received from the target",
+}, []string{"source", "check"})
+
 // cluster
 var clusterNumberOfLockedTargets = prometheus.NewGauge(prometheus.GaugeOpts{
 	Namespace: "gnmic",
@@ -42,6 +85,55 @@ var clusterIsLeader = prometheus.NewGauge(prometheus.GaugeOpts{
 	Help:      "Has value 1 if this gnmic instance is the cluster leader, 0 otherwise",
 })
 
+// gnmi-server
+var gnmiServerSlowConsumersEvicted = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "gnmi_server",
+	Name:      "slow_consumers_evicted_total",
+	Help:      "Total number of STREAM subscriptions terminated because the subscriber was too slow",
+})
+var gnmiServerSendRateLimitWait = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "gnmi_server",
+	Name:      "send_rate_limit_wait_seconds_total",
+	Help:      "Total time spent by STREAM subscriptions waiting for the max-send-rate limit",
+})
+
+// outputs
+var outputHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "output",
+	Name:      "healthy",
+	Help:      "Has value 1 if the output is healthy, 0 otherwise",
+}, []string{"name", "type"})
+
+func (a *App) startOutputsMetrics() {
+	if a.Config.APIServer == nil || !a.Config.APIServer.EnableMetrics {
+		return
+	}
+	err := a.reg.Register(outputHealthy)
+	if err != nil {
+		a.Logger.Printf("failed to register metric: %v", err)
+	}
+	ticker := time.NewTicker(outputMetricsUpdatePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			outputHealthy.Reset()
+			for name, st := range a.outputsHealth() {
+				if st.Healthy {
+					outputHealthy.WithLabelValues(name, st.Type).Set(1)
+				} else {
+					outputHealthy.WithLabelValues(name, st.Type).Set(0)
+				}
+			}
+		}
+	}
+}
+
 func (a *App) startClusterMetrics() {
 	if a.Config.APIServer == nil || !a.Config.APIServer.EnableMetrics || a.Config.Clustering == nil {
 		return
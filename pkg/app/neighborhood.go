@@ -0,0 +1,271 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+var neighborhoodSubscriptions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "subscribe",
+	Name:      "neighborhood_subscriptions",
+	Help:      "Number of subscriptions created by the neighborhood rules of a subscription",
+}, []string{"source", "subscription"})
+
+// neighborhood holds the subscriptions of a target created by
+// the neighborhood rules of its subscriptions.
+// It is only used by the target collector goroutine.
+type neighborhood struct {
+	t *target.Target
+	// compiled rules by subscription name
+	rules map[string][]*neighborhoodRule
+}
+
+type neighborhoodRule struct {
+	cfg    *types.NeighborhoodRule
+	parent string
+	index  int
+	elems  []*gnmi.PathElem
+	tpls   []*template.Template
+	// created subscriptions names, by matched path
+	subs map[string]string
+	// set when the max number of subscriptions is reached
+	limited bool
+}
+
+func newNeighborhood(t *target.Target) *neighborhood {
+	return &neighborhood{
+		t:     t,
+		rules: make(map[string][]*neighborhoodRule),
+	}
+}
+
+// rulesOf returns the compiled neighborhood rules of subscription name.
+func (n *neighborhood) rulesOf(name string, sc *types.SubscriptionConfig) ([]*neighborhoodRule, error) {
+	if rs, ok := n.rules[name]; ok {
+		return rs, nil
+	}
+	rs := make([]*neighborhoodRule, 0, len(sc.Neighborhood))
+	for i, r := range sc.Neighborhood {
+		p, err := path.ParsePath(r.Path)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: invalid path %q: %v", i, r.Path, err)
+		}
+		nr := &neighborhoodRule{
+			cfg:    r,
+			parent: name,
+			index:  i,
+			elems:  p.GetElem(),
+			tpls:   make([]*template.Template, 0, len(r.Subscribe)),
+			subs:   make(map[string]string),
+		}
+		for _, sp := range r.Subscribe {
+			tpl, err := gtemplate.CreateTemplate(fmt.Sprintf("%s-%d", name, i), sp)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid subscribe path %q: %v", i, sp, err)
+			}
+			nr.tpls = append(nr.tpls, tpl)
+		}
+		rs = append(rs, nr)
+	}
+	n.rules[name] = rs
+	return rs, nil
+}
+
+// handleNeighborhood creates the subscriptions triggered by the updates of response rsp,
+// and removes the ones triggered by the paths it deletes.
+func (a *App) handleNeighborhood(ctx context.Context, n *neighborhood, rsp *target.SubscribeResponse) {
+	if len(rsp.SubscriptionConfig.Neighborhood) == 0 {
+		return
+	}
+	notif := rsp.Response.GetUpdate()
+	if notif == nil {
+		return
+	}
+	rules, err := n.rulesOf(rsp.SubscriptionName, rsp.SubscriptionConfig)
+	if err != nil {
+		a.Logger.Printf("target %q: subscription %s: neighborhood: %v", n.t.Config.Name, rsp.SubscriptionName, err)
+		n.rules[rsp.SubscriptionName] = nil
+		return
+	}
+	for _, r := range rules {
+		for _, upd := range notif.GetUpdate() {
+			m := matchNeighborhood(r.elems, path.PathElems(notif.GetPrefix(), upd.GetPath()))
+			if m == nil {
+				continue
+			}
+			a.neighborhoodSubscribe(ctx, n, r, rsp.SubscriptionConfig, m)
+		}
+		for _, del := range notif.GetDelete() {
+			relems := path.PathElems(notif.GetPrefix(), del)
+			// only the deletion of the matched path itself removes its subscription
+			if len(relems) != len(r.elems) {
+				continue
+			}
+			m := matchNeighborhood(r.elems, relems)
+			if m == nil {
+				continue
+			}
+			a.neighborhoodUnsubscribe(n, r, m)
+		}
+	}
+}
+
+func (a *App) neighborhoodSubscribe(ctx context.Context, n *neighborhood, r *neighborhoodRule, parent *types.SubscriptionConfig, m *neighborhoodMatch) {
+	if _, ok := r.subs[m.path]; ok {
+		return
+	}
+	if len(r.subs) >= r.cfg.MaxSubscriptions {
+		if !r.limited {
+			r.limited = true
+			a.Logger.Printf("target %q: subscription %s: neighborhood rule %d reached its max number of subscriptions (%d)",
+				n.t.Config.Name, r.parent, r.index, r.cfg.MaxSubscriptions)
+		}
+		return
+	}
+	paths, err := r.render(n.t.Config.Name, m)
+	if err != nil {
+		a.Logger.Printf("target %q: subscription %s: neighborhood rule %d: %v", n.t.Config.Name, r.parent, r.index, err)
+		return
+	}
+	base := parent
+	if r.cfg.Subscription != "" {
+		a.configLock.RLock()
+		sc, ok := a.Config.Subscriptions[r.cfg.Subscription]
+		a.configLock.RUnlock()
+		if !ok {
+			a.Logger.Printf("target %q: subscription %s: neighborhood rule %d: unknown subscription %q",
+				n.t.Config.Name, r.parent, r.index, r.cfg.Subscription)
+			return
+		}
+		base = sc
+	}
+	name := fmt.Sprintf("%s/%d%s", r.parent, r.index, m.path)
+	nsc := *base
+	nsc.Name = name
+	nsc.Paths = paths
+	nsc.StreamSubscriptions = nil
+	nsc.ExpandWildcards = nil
+	nsc.Neighborhood = nil
+	req, err := a.Config.CreateSubscribeRequest(&nsc, n.t.Config)
+	if err != nil {
+		a.Logger.Printf("target %q: subscription %s: failed to create subscribe request: %v", n.t.Config.Name, name, err)
+		return
+	}
+	r.subs[m.path] = name
+	r.limited = false
+	neighborhoodSubscriptions.WithLabelValues(n.t.Config.Name, r.parent).Inc()
+	a.Logger.Printf("target %q: subscription %s: subscribing to neighborhood paths %v", n.t.Config.Name, name, paths)
+	n.t.SetSubscription(name, &nsc)
+	go n.t.Subscribe(ctx, req, name)
+}
+
+func (a *App) neighborhoodUnsubscribe(n *neighborhood, r *neighborhoodRule, m *neighborhoodMatch) {
+	name, ok := r.subs[m.path]
+	if !ok {
+		return
+	}
+	delete(r.subs, m.path)
+	neighborhoodSubscriptions.WithLabelValues(n.t.Config.Name, r.parent).Dec()
+	a.Logger.Printf("target %q: subscription %s: neighborhood path deleted, unsubscribing", n.t.Config.Name, name)
+	n.t.DeleteSubscription(name)
+}
+
+// stop removes the neighborhood subscriptions metrics of the target.
+func (n *neighborhood) stop() {
+	neighborhoodSubscriptions.DeletePartialMatch(prometheus.Labels{"source": n.t.Config.Name})
+}
+
+// render returns the sorted subscription paths of rule r
+// executed with the keys captured by match m.
+func (r *neighborhoodRule) render(targetName string, m *neighborhoodMatch) ([]string, error) {
+	data := map[string]any{
+		"target":       targetName,
+		"subscription": r.parent,
+		"path":         m.path,
+		"elems":        m.elems,
+	}
+	for k, v := range m.keys {
+		data[k] = v
+	}
+	paths := make([]string, 0, len(r.tpls))
+	b := new(bytes.Buffer)
+	for _, tpl := range r.tpls {
+		b.Reset()
+		if err := tpl.Execute(b, data); err != nil {
+			return nil, err
+		}
+		p := strings.TrimSpace(b.String())
+		if p == "" {
+			continue
+		}
+		if _, err := path.ParsePath(p); err != nil {
+			return nil, fmt.Errorf("invalid rendered path %q: %v", p, err)
+		}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no path rendered")
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// neighborhoodMatch is a path matching a neighborhood rule path.
+type neighborhoodMatch struct {
+	// matched path, without the elements following the rule path ones
+	path string
+	// values of the keys set to `*` in the rule path, by key name.
+	// If several elements have a wildcard key with the same name, the last one is kept.
+	keys map[string]string
+	// values of the keys set to `*` in the rule path, by element then key name.
+	elems map[string]map[string]string
+}
+
+// matchNeighborhood returns the match of the path elements relems
+// with the rule path elements welems, or nil if they don't match.
+func matchNeighborhood(welems, relems []*gnmi.PathElem) *neighborhoodMatch {
+	if !matchElems(welems, relems) {
+		return nil
+	}
+	m := &neighborhoodMatch{
+		path:  "/" + path.GnmiPathToXPath(&gnmi.Path{Elem: relems[:len(welems)]}, false),
+		keys:  make(map[string]string),
+		elems: make(map[string]map[string]string),
+	}
+	for i, we := range welems {
+		for k, v := range we.GetKey() {
+			if v != "*" {
+				continue
+			}
+			rv := relems[i].GetKey()[k]
+			m.keys[k] = rv
+			name := relems[i].GetName()
+			if m.elems[name] == nil {
+				m.elems[name] = make(map[string]string)
+			}
+			m.elems[name][k] = rv
+		}
+	}
+	return m
+}
@@ -0,0 +1,156 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func neighborhoodResponse(t *testing.T, sc *types.SubscriptionConfig, updates, deletes []string) *target.SubscribeResponse {
+	t.Helper()
+	n := &gnmi.Notification{}
+	for _, p := range updates {
+		gp, err := path.ParsePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.Update = append(n.Update, &gnmi.Update{Path: gp})
+	}
+	for _, p := range deletes {
+		gp, err := path.ParsePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.Delete = append(n.Delete, gp)
+	}
+	return &target.SubscribeResponse{
+		SubscriptionName:   sc.Name,
+		SubscriptionConfig: sc,
+		Response:           &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: n}},
+	}
+}
+
+func TestHandleNeighborhood(t *testing.T) {
+	sc := &types.SubscriptionConfig{
+		Name:       "ni",
+		Paths:      []string{"/network-instances/network-instance/state/name"},
+		Mode:       "STREAM",
+		StreamMode: "ON_CHANGE",
+		Neighborhood: []*types.NeighborhoodRule{
+			{
+				Path: "/network-instances/network-instance[name=*]",
+				Subscribe: []string{
+					"/network-instances/network-instance[name={{ .name }}]/protocols/protocol/bgp/neighbors",
+				},
+				MaxSubscriptions: 2,
+			},
+		},
+	}
+	tg := target.NewTarget(&types.TargetConfig{Name: "t1"})
+	tg.Subscriptions["ni"] = sc
+	a := New()
+	a.Config.Encoding = "json"
+	nb := newNeighborhood(tg)
+	defer nb.stop()
+	// the subscribe goroutines return immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	a.handleNeighborhood(ctx, nb, neighborhoodResponse(t, sc, []string{
+		"/network-instances/network-instance[name=default]/state/name",
+		"/network-instances/network-instance[name=default]/state/type",
+		"/network-instances/network-instance[name=mgmt]/state/name",
+		"/network-instances/network-instance[name=vrf1]/state/name",
+	}, nil))
+
+	name := "ni/0/network-instances/network-instance[name=default]"
+	nsc, ok := tg.Subscriptions[name]
+	if !ok {
+		t.Fatalf("expected subscription %q to be created, got %v", name, tg.Subscriptions)
+	}
+	want := []string{"/network-instances/network-instance[name=default]/protocols/protocol/bgp/neighbors"}
+	if !reflect.DeepEqual(nsc.Paths, want) {
+		t.Errorf("unexpected paths %v", nsc.Paths)
+	}
+	if nsc.Neighborhood != nil || nsc.StreamMode != "ON_CHANGE" {
+		t.Errorf("unexpected subscription config %v", nsc)
+	}
+	// the 3rd network instance is over the max number of subscriptions
+	if len(tg.Subscriptions) != 3 {
+		t.Errorf("expected 2 subscriptions to be created, got %d", len(tg.Subscriptions)-1)
+	}
+
+	a.handleNeighborhood(ctx, nb, neighborhoodResponse(t, sc, nil, []string{
+		"/network-instances/network-instance[name=default]/state/type",
+		"/network-instances/network-instance[name=mgmt]",
+	}))
+	if _, ok := tg.Subscriptions[name]; !ok {
+		t.Errorf("expected subscription %q to be kept after a leaf deletion", name)
+	}
+	if _, ok := tg.Subscriptions["ni/0/network-instances/network-instance[name=mgmt]"]; ok {
+		t.Errorf("expected the mgmt subscription to be removed")
+	}
+
+	a.handleNeighborhood(ctx, nb, neighborhoodResponse(t, sc, []string{
+		"/network-instances/network-instance[name=vrf1]/state/name",
+	}, nil))
+	if _, ok := tg.Subscriptions["ni/0/network-instances/network-instance[name=vrf1]"]; !ok {
+		t.Errorf("expected the vrf1 subscription to be created once below the max")
+	}
+}
+
+func TestNeighborhoodRender(t *testing.T) {
+	wp, err := path.ParsePath("/network-instances/network-instance[name=*]/protocols/protocol[identifier=BGP][name=*]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp, err := path.ParsePath("/network-instances/network-instance[name=vrf1]/protocols/protocol[identifier=BGP][name=bgp1]/state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := matchNeighborhood(wp.GetElem(), rp.GetElem())
+	if m == nil {
+		t.Fatal("expected the path to match")
+	}
+	if m.path != "/network-instances/network-instance[name=vrf1]/protocols/protocol[identifier=BGP][name=bgp1]" {
+		t.Errorf("unexpected matched path %q", m.path)
+	}
+	sc := &types.SubscriptionConfig{
+		Neighborhood: []*types.NeighborhoodRule{{
+			Path: "/network-instances/network-instance[name=*]/protocols/protocol[identifier=BGP][name=*]",
+			Subscribe: []string{
+				`/network-instances/network-instance[name={{ index .elems "network-instance" "name" }}]/protocols/protocol[name={{ .name }}]/bgp`,
+				`{{ if eq .target "t1" }}/network-instances/network-instance[name={{ index .elems "network-instance" "name" }}]/afts{{ end }}`,
+			},
+		}},
+	}
+	rs, err := newNeighborhood(target.NewTarget(&types.TargetConfig{Name: "t2"})).rulesOf("s", sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := rs[0].render("t2", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/network-instances/network-instance[name=vrf1]/protocols/protocol[name=bgp1]/bgp"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("unexpected paths %v", paths)
+	}
+	if matchNeighborhood(wp.GetElem(), rp.GetElem()[:2]) != nil {
+		t.Errorf("expected a shorter path not to match")
+	}
+}
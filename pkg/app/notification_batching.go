@@ -0,0 +1,128 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/cache"
+)
+
+// batchNotifications reads the notifications from the cache channel in
+// and merges consecutive ones sharing the same prefix into a single
+// notification with up to maxUpdates updates.
+// A batch is sent once it is full, once a notification that cannot be merged
+// into it is received or once it is maxDelay old.
+// The merged notification carries the most recent timestamp of its updates.
+// The returned channel is closed once in is closed.
+func batchNotifications(ctx context.Context, in <-chan *cache.Notification, maxUpdates int, maxDelay time.Duration) chan *cache.Notification {
+	out := make(chan *cache.Notification)
+	go func() {
+		defer close(out)
+		// keep reading from the cache until it closes the channel
+		// so that it does not block on a write.
+		defer func() {
+			for range in {
+			}
+		}()
+
+		var batch *cache.Notification
+		var timeout <-chan time.Time
+		send := func(n *cache.Notification) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- n:
+				return true
+			}
+		}
+		flush := func() bool {
+			if batch == nil {
+				return true
+			}
+			n := batch
+			batch, timeout = nil, nil
+			return send(n)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case n, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if n.Err != nil || !batchable(n.Notification) {
+					if !flush() || !send(n) {
+						return
+					}
+					continue
+				}
+				if batch != nil && !mergeable(batch, n, maxUpdates) {
+					if !flush() {
+						return
+					}
+				}
+				if batch == nil {
+					batch = &cache.Notification{
+						Name: n.Name,
+						Notification: &gnmi.Notification{
+							Timestamp: n.Notification.GetTimestamp(),
+							Prefix:    n.Notification.GetPrefix(),
+							Update:    make([]*gnmi.Update, 0, len(n.Notification.GetUpdate())),
+						},
+					}
+					timeout = time.After(maxDelay)
+				}
+				// the cached notifications are not modified,
+				// their updates are appended to the batch notification.
+				batch.Notification.Update = append(batch.Notification.Update, n.Notification.GetUpdate()...)
+				if n.Notification.GetTimestamp() > batch.Notification.GetTimestamp() {
+					batch.Notification.Timestamp = n.Notification.GetTimestamp()
+				}
+				if len(batch.Notification.GetUpdate()) >= maxUpdates {
+					if !flush() {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// batchable returns true if the notification only contains updates.
+func batchable(n *gnmi.Notification) bool {
+	return n != nil &&
+		len(n.GetUpdate()) > 0 &&
+		len(n.GetDelete()) == 0 &&
+		!n.GetAtomic()
+}
+
+// mergeable returns true if the notification n can be added to batch
+// without exceeding maxUpdates.
+func mergeable(batch, n *cache.Notification, maxUpdates int) bool {
+	if batch.Name != n.Name {
+		return false
+	}
+	if len(batch.Notification.GetUpdate())+len(n.Notification.GetUpdate()) > maxUpdates {
+		return false
+	}
+	return proto.Equal(batch.Notification.GetPrefix(), n.Notification.GetPrefix())
+}
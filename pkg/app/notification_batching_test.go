@@ -0,0 +1,126 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/cache"
+)
+
+func leafNotification(target string, ts int64, elem string) *cache.Notification {
+	return &cache.Notification{
+		Name: "sub1",
+		Notification: &gnmi.Notification{
+			Timestamp: ts,
+			Prefix:    &gnmi.Path{Target: target},
+			Update: []*gnmi.Update{
+				{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: elem}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: ts}},
+				},
+			},
+		},
+	}
+}
+
+func collectNotifications(t *testing.T, ch chan *cache.Notification) []*cache.Notification {
+	t.Helper()
+	result := make([]*cache.Notification, 0)
+	for {
+		select {
+		case n, ok := <-ch:
+			if !ok {
+				return result
+			}
+			result = append(result, n)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the batched notifications")
+		}
+	}
+}
+
+func TestBatchNotifications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("merge_and_split", func(t *testing.T) {
+		in := make(chan *cache.Notification)
+		out := batchNotifications(ctx, in, 3, time.Minute)
+		go func() {
+			defer close(in)
+			// 4 leaves of target1, split in batches of 3 and 1
+			for i := 1; i <= 4; i++ {
+				in <- leafNotification("target1", int64(i), "a")
+			}
+			// a different prefix starts a new batch
+			in <- leafNotification("target2", 10, "b")
+			// a delete is not batched
+			in <- &cache.Notification{Name: "sub1", Notification: &gnmi.Notification{
+				Prefix: &gnmi.Path{Target: "target2"},
+				Delete: []*gnmi.Path{{Elem: []*gnmi.PathElem{{Name: "b"}}}},
+			}}
+			in <- leafNotification("target2", 11, "b")
+		}()
+		result := collectNotifications(t, out)
+		wantUpdates := []int{3, 1, 1, 0, 1}
+		if len(result) != len(wantUpdates) {
+			t.Fatalf("expected %d notifications, got %d: %v", len(wantUpdates), len(result), result)
+		}
+		for i, n := range result {
+			if got := len(n.Notification.GetUpdate()); got != wantUpdates[i] {
+				t.Errorf("notification %d: expected %d updates, got %d", i, wantUpdates[i], got)
+			}
+		}
+		if ts := result[0].Notification.GetTimestamp(); ts != 3 {
+			t.Errorf("expected the batch timestamp to be the most recent one, got %d", ts)
+		}
+		if len(result[3].Notification.GetDelete()) != 1 {
+			t.Errorf("expected the delete notification to be forwarded as is, got %v", result[3].Notification)
+		}
+	})
+
+	t.Run("max_delay", func(t *testing.T) {
+		in := make(chan *cache.Notification)
+		defer close(in)
+		out := batchNotifications(ctx, in, 100, 10*time.Millisecond)
+		in <- leafNotification("target1", 1, "a")
+		in <- leafNotification("target1", 2, "b")
+		select {
+		case n := <-out:
+			if len(n.Notification.GetUpdate()) != 2 {
+				t.Errorf("expected 2 updates, got %d", len(n.Notification.GetUpdate()))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the batch was not sent after max-delay")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		in := make(chan *cache.Notification)
+		out := batchNotifications(ctx, in, 100, time.Minute)
+		go func() {
+			defer close(in)
+			in <- leafNotification("target1", 1, "a")
+			in <- &cache.Notification{Err: errors.New("cache error")}
+		}()
+		result := collectNotifications(t, out)
+		if len(result) != 2 {
+			t.Fatalf("expected 2 notifications, got %d", len(result))
+		}
+		if result[0].Err != nil || result[1].Err == nil {
+			t.Errorf("expected the pending batch to be sent before the error: %v", result)
+		}
+	})
+}
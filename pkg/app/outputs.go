@@ -11,7 +11,6 @@ package app
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/openconfig/gnmic/pkg/outputs"
@@ -23,39 +22,38 @@ func (a *App) InitOutput(ctx context.Context, name string, tcs map[string]*types
 	if _, ok := a.Outputs[name]; ok {
 		return
 	}
-	wg := new(sync.WaitGroup)
 	if cfg, ok := a.Config.Outputs[name]; ok {
 		if outType, ok := cfg["type"]; ok {
 			a.Logger.Printf("starting output type %s", outType)
 			if initializer, ok := outputs.Outputs[outType.(string)]; ok {
-				out := initializer()
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					err := out.Init(ctx, name, cfg,
-						outputs.WithLogger(a.Logger),
-						outputs.WithEventProcessors(
-							a.Config.Processors,
-							a.Logger,
-							a.Config.Targets,
-							a.Config.Actions,
-						),
-						outputs.WithRegistry(a.reg),
-						outputs.WithName(a.Config.InstanceName),
-						outputs.WithClusterName(a.Config.ClusterName),
-						outputs.WithTargetsConfig(tcs),
-					)
-					if err != nil {
-						a.Logger.Printf("failed to init output type %q: %v", outType, err)
-					}
-				}()
+				out, err := outputs.WrapOutput(initializer(), cfg, a.Logger)
+				if err != nil {
+					a.Logger.Printf("failed to init output type %q: %v", outType, err)
+					return
+				}
+				err = out.Init(ctx, name, cfg,
+					outputs.WithLogger(a.Logger),
+					outputs.WithEventProcessors(
+						a.Config.Processors,
+						a.Logger,
+						a.Config.Targets,
+						a.Config.Actions,
+					),
+					outputs.WithRegistry(a.reg),
+					outputs.WithName(a.Config.InstanceName),
+					outputs.WithClusterName(a.Config.ClusterName),
+					outputs.WithTargetsConfig(tcs),
+				)
+				if err != nil {
+					a.Logger.Printf("failed to init output type %q: %v", outType, err)
+					return
+				}
 				a.operLock.Lock()
 				a.Outputs[name] = out
 				a.operLock.Unlock()
 			}
 		}
 	}
-	wg.Wait()
 }
 
 func (a *App) InitOutputs(ctx context.Context) {
@@ -98,3 +96,25 @@ func (a *App) DeleteOutput(name string) error {
 	delete(a.Outputs, name)
 	return nil
 }
+
+type outputHealth struct {
+	Type    string `json:"type,omitempty"`
+	Healthy bool   `json:"healthy"`
+}
+
+// outputsHealth returns the health status of the running outputs, by name.
+func (a *App) outputsHealth() map[string]*outputHealth {
+	a.configLock.RLock()
+	defer a.configLock.RUnlock()
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	oh := make(map[string]*outputHealth, len(a.Outputs))
+	for name, o := range a.Outputs {
+		st := &outputHealth{Healthy: outputs.IsHealthy(o)}
+		if cfg, ok := a.Config.Outputs[name]; ok {
+			st.Type, _ = cfg["type"].(string)
+		}
+		oh[name] = st
+	}
+	return oh
+}
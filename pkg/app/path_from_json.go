@@ -0,0 +1,153 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+type jsonLeafPath struct {
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func (a *App) PathFromJSONPreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	if a.Config.LocalFlags.PathFromJSONInput == "" {
+		return errors.New("missing flag --input")
+	}
+	if _, err := parseListKeysFlag(a.Config.LocalFlags.PathFromJSONListKey); err != nil {
+		return err
+	}
+	if len(a.Config.GlobalFlags.File) == 0 {
+		return nil
+	}
+	err := a.yangFilesPreProcessing()
+	if err != nil {
+		return err
+	}
+	return a.generateYangSchema(a.Config.GlobalFlags.File, a.Config.GlobalFlags.Exclude)
+}
+
+func (a *App) PathFromJSONRunE(cmd *cobra.Command, args []string) error {
+	b, err := readJSONInput(a.Config.LocalFlags.PathFromJSONInput)
+	if err != nil {
+		return err
+	}
+	base, err := path.ParsePath(a.Config.LocalFlags.PathFromJSONBasePath)
+	if err != nil {
+		return fmt.Errorf("invalid base path: %v", err)
+	}
+	listKeys, err := parseListKeysFlag(a.Config.LocalFlags.PathFromJSONListKey)
+	if err != nil {
+		return err
+	}
+	upds, err := path.FromJSON(base, b, a.listKeysFunc(listKeys))
+	if errors.Is(err, path.ErrUnknownListKeys) {
+		return fmt.Errorf("%v: set them with --list-key or load the YANG models with --file", err)
+	}
+	if err != nil {
+		return err
+	}
+	leaves := make([]*jsonLeafPath, 0, len(upds))
+	for _, upd := range upds {
+		leaves = append(leaves, &jsonLeafPath{
+			Path:  "/" + path.GnmiPathToXPath(upd.GetPath(), false),
+			Value: upd.GetVal().GetJsonVal(),
+		})
+	}
+	if a.Config.Format == "json" {
+		b, err := json.MarshalIndent(leaves, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.out, string(b))
+		return nil
+	}
+	for _, l := range leaves {
+		fmt.Fprintf(a.out, "%s: %s\n", l.Path, l.Value)
+	}
+	return nil
+}
+
+func (a *App) InitPathFromJSONFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.PathFromJSONInput, "input", "", "", "path to the JSON file to map to paths, '-' reads from stdin")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.PathFromJSONBasePath, "base-path", "", "", "path the JSON value would be set at")
+	cmd.Flags().StringArrayVarP(&a.Config.LocalFlags.PathFromJSONListKey, "list-key", "", []string{}, "list keys names in the format <list>=<key1>[,<key2>], overrides the keys found in the YANG files")
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
+	})
+}
+
+// listKeysFunc returns a path.ListKeysFunc looking up the list keys
+// in the keys set with --list-key, then in the loaded YANG schema.
+func (a *App) listKeysFunc(listKeys map[string][]string) path.ListKeysFunc {
+	return func(p *gnmi.Path) []string {
+		elems := p.GetElem()
+		if len(elems) == 0 {
+			return nil
+		}
+		if keys, ok := listKeys[stripModulePrefix(elems[len(elems)-1].GetName())]; ok {
+			return keys
+		}
+		if a.SchemaTree == nil || len(a.SchemaTree.Dir) == 0 {
+			return nil
+		}
+		e := a.SchemaTree
+		for _, pe := range elems {
+			e = findSchemaChild(e, pe.GetName())
+			if e == nil {
+				return nil
+			}
+		}
+		if !e.IsList() {
+			return nil
+		}
+		return strings.Fields(e.Key)
+	}
+}
+
+// parseListKeysFlag parses the --list-key flag values
+// formatted as <list>=<key1>[,<key2>].
+func parseListKeysFlag(vals []string) (map[string][]string, error) {
+	listKeys := make(map[string][]string, len(vals))
+	for _, v := range vals {
+		list, keys, ok := strings.Cut(v, "=")
+		if !ok || list == "" || keys == "" {
+			return nil, fmt.Errorf("invalid --list-key value %q, expected <list>=<key1>[,<key2>]", v)
+		}
+		for _, k := range strings.Split(keys, ",") {
+			k = strings.TrimSpace(k)
+			if k == "" {
+				return nil, fmt.Errorf("invalid --list-key value %q, empty key name", v)
+			}
+			listKeys[stripModulePrefix(list)] = append(listKeys[stripModulePrefix(list)], k)
+		}
+	}
+	return listKeys, nil
+}
+
+func readJSONInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
@@ -0,0 +1,57 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+func TestParseListKeysFlag(t *testing.T) {
+	got, err := parseListKeysFlag([]string{"interface=name", "t:neighbor=peer-address,vrf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"interface": {"name"},
+		"neighbor":  {"peer-address", "vrf"},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected list keys: %v", cmp.Diff(want, got))
+	}
+	for _, v := range []string{"interface", "=name", "interface=", "interface=name,"} {
+		if _, err := parseListKeysFlag([]string{v}); err == nil {
+			t.Errorf("expected an error for %q", v)
+		}
+	}
+}
+
+func TestListKeysFunc(t *testing.T) {
+	a := &App{SchemaTree: testSetValidateSchema(t)}
+	fn := a.listKeysFunc(map[string][]string{"neighbor": {"address"}})
+	tests := map[string][]string{
+		"/interfaces/interface":   {"name"},
+		"/t:interfaces/interface": {"name"},
+		"/bgp/neighbor":           {"address"},
+		"/system":                 nil,
+		"/foo/interface":          nil,
+	}
+	for p, want := range tests {
+		gp, err := path.ParsePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fn(gp); !cmp.Equal(got, want) {
+			t.Errorf("path %q: expected keys %v, got %v", p, want, got)
+		}
+	}
+}
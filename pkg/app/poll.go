@@ -0,0 +1,275 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+const pollTriggerInterval = "interval"
+
+var errNoPollSubscription = errors.New("no matching POLL subscription")
+
+// targetPolls holds the poll states of a target POLL subscriptions.
+// mu serializes the Poll requests sent to the target.
+type targetPolls struct {
+	mu   sync.Mutex
+	subs map[string]*pollState
+}
+
+// pollState is the state of a POLL subscription as returned by the REST API.
+type pollState struct {
+	Subscription string     `json:"subscription,omitempty"`
+	Interval     string     `json:"interval,omitempty"`
+	Triggers     []string   `json:"triggers,omitempty"`
+	Polls        uint64     `json:"polls"`
+	LastPoll     *time.Time `json:"last-poll,omitempty"`
+	LastTrigger  string     `json:"last-trigger,omitempty"`
+	LastError    string     `json:"last-error,omitempty"`
+
+	sc *types.SubscriptionConfig
+}
+
+// hasPollTriggers reports whether the collector sends
+// the Poll requests of the subscription.
+func hasPollTriggers(sc *types.SubscriptionConfig) bool {
+	return strings.ToUpper(sc.Mode) == subscriptionModePOLL &&
+		(sc.PollInterval > 0 || len(sc.PollTriggers) > 0)
+}
+
+// startPollTriggers registers the target POLL subscriptions that have triggers
+// and starts their poll-interval timers, until ctx is done.
+func (a *App) startPollTriggers(ctx context.Context, t *target.Target) {
+	tp := &targetPolls{subs: make(map[string]*pollState)}
+	for name, sc := range t.Subscriptions {
+		if !hasPollTriggers(sc) {
+			continue
+		}
+		ps := &pollState{
+			Subscription: name,
+			Triggers:     sc.PollTriggers,
+			sc:           sc,
+		}
+		if sc.PollInterval > 0 {
+			ps.Interval = sc.PollInterval.String()
+		}
+		tp.subs[name] = ps
+	}
+	if len(tp.subs) == 0 {
+		return
+	}
+	targetName := t.Config.Name
+	a.operLock.Lock()
+	a.targetsPolls[targetName] = tp
+	a.operLock.Unlock()
+
+	for name, ps := range tp.subs {
+		if ps.sc.PollInterval <= 0 {
+			continue
+		}
+		go func(name string, interval time.Duration) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					err := a.poll(ctx, targetName, tp, name, pollTriggerInterval)
+					if err != nil {
+						a.Logger.Printf("target %q, subscription %q: failed to send Poll request: %v", targetName, name, err)
+					}
+				}
+			}
+		}(name, ps.sc.PollInterval)
+	}
+	go func() {
+		<-ctx.Done()
+		a.operLock.Lock()
+		defer a.operLock.Unlock()
+		// the target might have been restarted with a new set of polls.
+		if a.targetsPolls[targetName] == tp {
+			delete(a.targetsPolls, targetName)
+		}
+	}()
+}
+
+// poll sends a Poll request to the target subscription and records it in the subscription poll state.
+func (a *App) poll(ctx context.Context, targetName string, tp *targetPolls, subName, trigger string) error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	ps, ok := tp.subs[subName]
+	if !ok {
+		return errNoPollSubscription
+	}
+	err := a.clientSubscribePoll(ctx, targetName, subName)
+	now := time.Now()
+	ps.LastPoll = &now
+	ps.LastTrigger = trigger
+	if err != nil {
+		ps.LastError = err.Error()
+		return err
+	}
+	ps.Polls++
+	ps.LastError = ""
+	return nil
+}
+
+// pollTargets sends a Poll request to the POLL subscriptions accepting trigger.
+// If targetName or subName are empty, all targets or all subscriptions are polled.
+// It returns the number of Poll requests sent.
+func (a *App) pollTargets(ctx context.Context, trigger, targetName, subName string) (int, error) {
+	a.operLock.RLock()
+	tps := make(map[string]*targetPolls, len(a.targetsPolls))
+	for name, tp := range a.targetsPolls {
+		if targetName == "" || targetName == name {
+			tps[name] = tp
+		}
+	}
+	a.operLock.RUnlock()
+
+	var errs []error
+	numPolls := 0
+	for name, tp := range tps {
+		tp.mu.Lock()
+		subs := make([]string, 0, len(tp.subs))
+		for sn, ps := range tp.subs {
+			if (subName == "" || subName == sn) && ps.sc.HasPollTrigger(trigger) {
+				subs = append(subs, sn)
+			}
+		}
+		tp.mu.Unlock()
+		for _, sn := range subs {
+			err := a.poll(ctx, name, tp, sn, trigger)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("target %q, subscription %q: %w", name, sn, err))
+				continue
+			}
+			numPolls++
+		}
+	}
+	if numPolls == 0 && len(errs) == 0 {
+		return 0, errNoPollSubscription
+	}
+	return numPolls, errors.Join(errs...)
+}
+
+// targetPollStates returns a copy of the poll states of a target, sorted by subscription name.
+func (a *App) targetPollStates(targetName string) []*pollState {
+	a.operLock.RLock()
+	tp, ok := a.targetsPolls[targetName]
+	a.operLock.RUnlock()
+	if !ok {
+		return nil
+	}
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	states := make([]*pollState, 0, len(tp.subs))
+	for _, ps := range tp.subs {
+		cps := *ps
+		states = append(states, &cps)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Subscription < states[j].Subscription
+	})
+	return states
+}
+
+// handlePollSignals polls the subscriptions with the signal trigger
+// each time a poll signal is received, until ctx is done.
+func (a *App) handlePollSignals(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	if !notifyPollSignal(sigCh) {
+		return
+	}
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			n, err := a.pollTargets(ctx, types.PollTriggerSignal, "", "")
+			if errors.Is(err, errNoPollSubscription) {
+				a.Logger.Printf("received poll signal: no subscription with the %q poll trigger", types.PollTriggerSignal)
+				continue
+			}
+			a.Logger.Printf("received poll signal: sent %d Poll request(s)", n)
+			if err != nil {
+				a.Logger.Printf("failed to send Poll requests: %v", err)
+			}
+		}
+	}
+}
+
+func (a *App) handleTargetsPollGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a.operLock.RLock()
+	_, ok := a.Targets[id]
+	a.operLock.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("target %q not found", id)}})
+		return
+	}
+	states := a.targetPollStates(id)
+	if states == nil {
+		states = []*pollState{}
+	}
+	a.handlerCommonGet(w, states)
+}
+
+// handleTargetsPollPost sends a Poll request to the target POLL subscriptions
+// with the api trigger, or only to the one set in the subscription query parameter.
+func (a *App) handleTargetsPollPost(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a.operLock.RLock()
+	_, ok := a.Targets[id]
+	a.operLock.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("target %q not found", id)}})
+		return
+	}
+	sub := r.URL.Query().Get("subscription")
+	_, err := a.pollTargets(a.ctx, types.PollTriggerAPI, id, sub)
+	switch {
+	case errors.Is(err, errNoPollSubscription):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("target %q: %v with the %q poll trigger", id, err, types.PollTriggerAPI)}})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	a.handlerCommonGet(w, a.targetPollStates(id))
+}
@@ -0,0 +1,18 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package app
+
+import "os"
+
+// there is no SIGUSR1 on windows, the signal poll trigger is not supported.
+func notifyPollSignal(chan<- os.Signal) bool {
+	return false
+}
@@ -0,0 +1,160 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// fakePollClient records the Poll requests sent on a POLL subscription stream.
+type fakePollClient struct {
+	grpc.ClientStream
+	m     sync.Mutex
+	polls int
+}
+
+func (f *fakePollClient) Send(req *gnmi.SubscribeRequest) error {
+	if req.GetPoll() != nil {
+		f.m.Lock()
+		f.polls++
+		f.m.Unlock()
+	}
+	return nil
+}
+
+func (f *fakePollClient) Recv() (*gnmi.SubscribeResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newPollTestApp(t *testing.T, sc *types.SubscriptionConfig) (*App, *fakePollClient) {
+	t.Helper()
+	a := New()
+	tg := target.NewTarget(&types.TargetConfig{Name: "router1"})
+	tg.Subscriptions[sc.Name] = sc
+	fc := new(fakePollClient)
+	tg.SubscribeClients[sc.Name] = fc
+	a.Targets["router1"] = tg
+	return a, fc
+}
+
+func TestPollTriggers(t *testing.T) {
+	a, fc := newPollTestApp(t, &types.SubscriptionConfig{
+		Name:         "sub1",
+		Mode:         "poll",
+		PollTriggers: []string{types.PollTriggerAPI},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	a.startPollTriggers(ctx, a.Targets["router1"])
+
+	n, err := a.pollTargets(ctx, types.PollTriggerAPI, "router1", "")
+	if err != nil || n != 1 {
+		t.Fatalf("unexpected result: polls=%d, err=%v", n, err)
+	}
+	if fc.polls != 1 {
+		t.Errorf("expected 1 Poll request, got %d", fc.polls)
+	}
+	if _, err := a.pollTargets(ctx, types.PollTriggerSignal, "", ""); !errors.Is(err, errNoPollSubscription) {
+		t.Errorf("expected no subscription with the signal trigger, got %v", err)
+	}
+	states := a.targetPollStates("router1")
+	if len(states) != 1 || states[0].Polls != 1 || states[0].LastTrigger != types.PollTriggerAPI {
+		t.Errorf("unexpected poll states: %+v", states)
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for a.targetPollStates("router1") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("poll states not removed after the target stopped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	a, fc := newPollTestApp(t, &types.SubscriptionConfig{
+		Name:         "sub1",
+		Mode:         "POLL",
+		PollInterval: 10 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.startPollTriggers(ctx, a.Targets["router1"])
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		fc.m.Lock()
+		polls := fc.polls
+		fc.m.Unlock()
+		if polls >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at least 2 Poll requests, got %d", polls)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the subscription does not accept API triggers
+	if _, err := a.pollTargets(ctx, types.PollTriggerAPI, "router1", ""); !errors.Is(err, errNoPollSubscription) {
+		t.Errorf("expected no subscription with the api trigger, got %v", err)
+	}
+}
+
+func TestHandleTargetsPoll(t *testing.T) {
+	a, fc := newPollTestApp(t, &types.SubscriptionConfig{
+		Name:         "sub1",
+		Mode:         "POLL",
+		PollTriggers: []string{types.PollTriggerAPI},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.startPollTriggers(ctx, a.Targets["router1"])
+
+	do := func(h http.HandlerFunc, method, id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/targets/"+id+"/poll"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+	if rec := do(a.handleTargetsPollPost, http.MethodPost, "router1", "?subscription=sub2"); rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown subscription", rec.Code)
+	}
+	if rec := do(a.handleTargetsPollPost, http.MethodPost, "router2", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for an unknown target", rec.Code)
+	}
+	if rec := do(a.handleTargetsPollPost, http.MethodPost, "router1", "?subscription=sub1"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rec.Code, rec.Body.String())
+	}
+	if fc.polls != 1 {
+		t.Errorf("expected 1 Poll request, got %d", fc.polls)
+	}
+	rec := do(a.handleTargetsPollGet, http.MethodGet, "router1", "")
+	states := make([]*pollState, 0)
+	if err := json.Unmarshal(rec.Body.Bytes(), &states); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(states) != 1 || states[0].Subscription != "sub1" || states[0].Polls != 1 {
+		t.Errorf("unexpected poll states: %s", rec.Body.String())
+	}
+}
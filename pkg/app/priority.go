@@ -0,0 +1,90 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// fill levels of a target's responses buffer at which
+// the subscriptions responses are shed by priority class.
+const (
+	// above this level, only one in priorityLowSampleRate
+	// low priority responses is kept.
+	priorityLowSampleLevel = 0.5
+	priorityLowSampleRate  = 10
+	// above this level, all low priority responses are dropped.
+	priorityLowDropLevel = 0.75
+	// above this level, normal priority responses are dropped as well.
+	priorityNormalDropLevel = 0.9
+)
+
+// priorityShedder drops the responses of a target's subscriptions
+// by priority class when the target's responses buffer fills up.
+// High priority responses and the responses of the subscriptions
+// without a priority are never dropped.
+// It is not safe for concurrent use, it is owned by the target's collector loop.
+type priorityShedder struct {
+	target string
+	// number of low priority responses received while sampling.
+	lowSeen uint64
+}
+
+func newPriorityShedder(t string) *priorityShedder {
+	return &priorityShedder{target: t}
+}
+
+// shed reports whether rsp, received by a subscription with the given
+// priority, should be dropped given the fill level of the target's
+// responses buffer. Sync responses are never dropped.
+func (s *priorityShedder) shed(priority string, rsp *gnmi.SubscribeResponse, level float64) bool {
+	if _, ok := rsp.GetResponse().(*gnmi.SubscribeResponse_SyncResponse); ok {
+		return false
+	}
+	var drop bool
+	switch priority {
+	case types.SubscriptionPriorityHigh:
+		return false
+	case types.SubscriptionPriorityLow:
+		switch {
+		case level >= priorityLowDropLevel:
+			drop = true
+		case level >= priorityLowSampleLevel:
+			drop = s.lowSeen%priorityLowSampleRate != 0
+			s.lowSeen++
+		default:
+			s.lowSeen = 0
+		}
+	case types.SubscriptionPriorityNormal:
+		drop = level >= priorityNormalDropLevel
+	default:
+		return false
+	}
+	if drop {
+		subscribeResponsesShedCounter.WithLabelValues(s.target, priority).Inc()
+	}
+	return drop
+}
+
+// stop removes the target metrics.
+func (s *priorityShedder) stop() {
+	subscribeResponsesShedCounter.DeleteLabelValues(s.target, types.SubscriptionPriorityNormal)
+	subscribeResponsesShedCounter.DeleteLabelValues(s.target, types.SubscriptionPriorityLow)
+}
+
+// bufferLevel returns the fill level of a target's responses buffer, between 0 and 1.
+func bufferLevel(ch chan *target.SubscribeResponse) float64 {
+	if cap(ch) == 0 {
+		return 0
+	}
+	return float64(len(ch)) / float64(cap(ch))
+}
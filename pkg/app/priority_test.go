@@ -0,0 +1,76 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestPriorityShedder(t *testing.T) {
+	update := &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{}}}
+	sync := &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}}
+	tests := []struct {
+		name     string
+		priority string
+		rsp      *gnmi.SubscribeResponse
+		level    float64
+		want     bool
+	}{
+		{name: "high_full", priority: types.SubscriptionPriorityHigh, rsp: update, level: 1},
+		{name: "normal_congested", priority: types.SubscriptionPriorityNormal, rsp: update, level: 0.8},
+		{name: "normal_full", priority: types.SubscriptionPriorityNormal, rsp: update, level: 0.9, want: true},
+		{name: "unset_full", rsp: update, level: 1},
+		{name: "low_idle", priority: types.SubscriptionPriorityLow, rsp: update, level: 0.2},
+		{name: "low_congested", priority: types.SubscriptionPriorityLow, rsp: update, level: 0.75, want: true},
+		{name: "low_sync", priority: types.SubscriptionPriorityLow, rsp: sync, level: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPriorityShedder("router1")
+			defer s.stop()
+			if got := s.shed(tt.priority, tt.rsp, tt.level); got != tt.want {
+				t.Errorf("expected shed=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPriorityShedderSampling(t *testing.T) {
+	update := &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{}}}
+	s := newPriorityShedder("router1")
+	defer s.stop()
+	var kept int
+	for i := 0; i < 3*priorityLowSampleRate; i++ {
+		if !s.shed(types.SubscriptionPriorityLow, update, priorityLowSampleLevel) {
+			kept++
+		}
+	}
+	if kept != 3 {
+		t.Errorf("expected 3 kept low priority responses, got %d", kept)
+	}
+}
+
+func TestBufferLevel(t *testing.T) {
+	ch := make(chan *target.SubscribeResponse, 4)
+	if l := bufferLevel(ch); l != 0 {
+		t.Errorf("expected an empty buffer, got %v", l)
+	}
+	ch <- nil
+	if l := bufferLevel(ch); l != 0.25 {
+		t.Errorf("expected a 0.25 fill level, got %v", l)
+	}
+	if l := bufferLevel(make(chan *target.SubscribeResponse)); l != 0 {
+		t.Errorf("expected an unbuffered channel level of 0, got %v", l)
+	}
+}
@@ -2,12 +2,14 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/gnmic/pkg/config"
 	"github.com/openconfig/gnmic/pkg/file"
 	"github.com/openconfig/gnmic/pkg/formatters"
@@ -15,6 +17,7 @@ import (
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func (a *App) ProcessorPreRunE(cmd *cobra.Command, args []string) error {
@@ -28,38 +31,73 @@ func (a *App) ProcessorPreRunE(cmd *cobra.Command, args []string) error {
 }
 
 func (a *App) ProcessorRunE(cmd *cobra.Command, args []string) error {
+	rrevs, err := a.runProcessors(cmd.Context(),
+		a.Config.LocalFlags.ProcessorName,
+		a.Config.LocalFlags.ProcessorInput,
+		a.Config.LocalFlags.ProcessorInputDelimiter,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(a.Config.LocalFlags.ProcessorOutput) != 0 {
+		b, err := a.promFormat(rrevs, a.Config.LocalFlags.ProcessorOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	numEvOut := len(rrevs)
+	for i, rev := range rrevs {
+		b, err := json.MarshalIndent(rev, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		if i == numEvOut-1 {
+			break
+		}
+	}
+	return nil
+}
+
+// runProcessors applies the named processors to each of the events messages read from
+// the input file. The messages are separated by delimiter, each one being a JSON list of events.
+func (a *App) runProcessors(ctx context.Context, names []string, input, delimiter string) ([][]*formatters.EventMsg, error) {
 	actionsConfig, err := a.Config.GetActions()
 	if err != nil {
-		return fmt.Errorf("failed reading actions config: %v", err)
+		return nil, fmt.Errorf("failed reading actions config: %v", err)
 	}
 	pConfig, err := a.Config.GetEventProcessors()
 	if err != nil {
-		return fmt.Errorf("failed reading event processors config: %v", err)
+		return nil, fmt.Errorf("failed reading event processors config: %v", err)
 	}
 	tcs, err := a.Config.GetTargets()
 	if err != nil {
 		if !errors.Is(err, config.ErrNoTargetsFound) {
-			return err
+			return nil, err
 		}
 	}
 	// initialize processors
 	evps, err := formatters.MakeEventProcessors(
 		a.Logger,
-		a.Config.LocalFlags.ProcessorName,
+		names,
 		pConfig,
 		tcs,
 		actionsConfig,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// read input file
-	inputBytes, err := file.ReadFile(cmd.Context(), a.Config.LocalFlags.ProcessorInput)
+	inputBytes, err := file.ReadFile(ctx, input)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	evInput := make([][]*formatters.EventMsg, 0)
-	msgs := bytes.Split(inputBytes, []byte(a.Config.LocalFlags.ProcessorInputDelimiter))
+	msgs := bytes.Split(inputBytes, []byte(delimiter))
 	for i, bg := range msgs {
 		if len(bg) == 0 {
 			continue
@@ -67,14 +105,14 @@ func (a *App) ProcessorRunE(cmd *cobra.Command, args []string) error {
 		mevs := make([]map[string]any, 0)
 		err = json.Unmarshal(bg, &mevs)
 		if err != nil {
-			return fmt.Errorf("failed json Unmarshal at msg index %d: %s: %v", i, bg, err)
+			return nil, fmt.Errorf("failed json Unmarshal at msg index %d: %s: %v", i, bg, err)
 		}
 
 		evs := make([]*formatters.EventMsg, 0, len(mevs))
 		for _, mev := range mevs {
 			ev, err := formatters.EventFromMap(mev)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			evs = append(evs, ev)
 		}
@@ -88,39 +126,98 @@ func (a *App) ProcessorRunE(cmd *cobra.Command, args []string) error {
 		}
 		rrevs = append(rrevs, revs)
 	}
+	return rrevs, nil
+}
 
-	if len(a.Config.LocalFlags.ProcessorOutput) != 0 {
-		b, err := a.promFormat(rrevs, a.Config.LocalFlags.ProcessorOutput)
-		if err != nil {
-			return err
-		}
-		fmt.Println(string(b))
+func (a *App) InitProcessorFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorInput, "input", "", "", "processors input")
+	cmd.MarkFlagRequired("input")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorInputDelimiter, "delimiter", "", "\n", "processors input delimiter")
+	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.ProcessorName, "name", "", nil, "list of processors to apply to the input")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorOutput, "output", "", "", "output name")
+}
+
+func (a *App) ProcessorTestPreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	return a.initPluginManager()
+}
+
+// ProcessorTestRunE runs the processors against the input events and,
+// if an expected output file is set, compares the result with it.
+// It returns an error if the output differs from the expected one.
+func (a *App) ProcessorTestRunE(cmd *cobra.Command, args []string) error {
+	rrevs, err := a.runProcessors(cmd.Context(),
+		a.Config.LocalFlags.ProcessorTestName,
+		a.Config.LocalFlags.ProcessorTestInput,
+		a.Config.LocalFlags.ProcessorTestInputDelimiter,
+	)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(rrevs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if a.Config.LocalFlags.ProcessorTestExpected == "" {
+		fmt.Fprintln(a.out, string(b))
 		return nil
 	}
+	expectedBytes, err := file.ReadFile(cmd.Context(), a.Config.LocalFlags.ProcessorTestExpected)
+	if err != nil {
+		return err
+	}
+	return a.compareProcessorsOutput(b, expectedBytes)
+}
 
-	numEvOut := len(rrevs)
-	for i, rev := range rrevs {
-		b, err := json.MarshalIndent(rev, "", "  ")
-		if err != nil {
-			return err
-		}
-		fmt.Println(string(b))
-		if i == numEvOut-1 {
-			break
+// compareProcessorsOutput compares the JSON encoded processors output
+// with the expected one, a JSON list of events lists, one per input message.
+// The differences are printed per message.
+func (a *App) compareProcessorsOutput(out, expected []byte) error {
+	var got, want [][]any
+	err := json.Unmarshal(out, &got)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(expected, &want)
+	if err != nil {
+		return fmt.Errorf("failed to parse expected output: %v", err)
+	}
+	failed := 0
+	if len(got) != len(want) {
+		failed++
+		fmt.Fprintf(a.out, "FAIL: got %d messages, expected %d\n", len(got), len(want))
+	}
+	for i := 0; i < len(got) && i < len(want); i++ {
+		diff := cmp.Diff(want[i], got[i])
+		if diff == "" {
+			continue
 		}
+		failed++
+		fmt.Fprintf(a.out, "FAIL: message %d (-expected +got):\n%s\n", i, diff)
+	}
+	if failed > 0 {
+		return errors.New("processors output does not match the expected output")
 	}
+	fmt.Fprintf(a.out, "PASS: %d message(s)\n", len(got))
 	return nil
 }
 
-func (a *App) InitProcessorFlags(cmd *cobra.Command) {
+func (a *App) InitProcessorTestFlags(cmd *cobra.Command) {
 	cmd.ResetFlags()
 
-	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorInput, "input", "", "", "processors input")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorTestInput, "input", "", "", "processors input events file")
 	cmd.MarkFlagRequired("input")
-	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorInputDelimiter, "delimiter", "", "\n", "processors input delimiter")
-	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.ProcessorName, "name", "", nil, "list of processors to apply to the input")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorTestInputDelimiter, "delimiter", "", "\n", "processors input delimiter")
+	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.ProcessorTestName, "name", "", nil, "list of processors to apply to the input, in order")
 	cmd.MarkFlagRequired("name")
-	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorOutput, "output", "", "", "output name")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ProcessorTestExpected, "expected", "", "", "expected output file, a JSON list of events lists, one per input message")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "processor-test", flag.Name), flag)
+	})
 }
 
 func (a *App) promFormat(rrevs [][]*formatters.EventMsg, outName string) ([]byte, error) {
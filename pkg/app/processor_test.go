@@ -0,0 +1,80 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessorTest(t *testing.T) {
+	a := New()
+	a.Config.FileConfig.SetConfigType("yaml")
+	err := a.Config.FileConfig.ReadConfig(strings.NewReader(`
+processors:
+  add-site:
+    event-add-tag:
+      value-names:
+        - "."
+      add:
+        site: dc1
+  drop-admin:
+    event-delete:
+      value-names:
+        - ".*admin-status$"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	err = os.WriteFile(input, []byte(
+		`[{"name":"sub1","timestamp":1,"tags":{"source":"r1"},"values":{"/interface/admin-status":"up","/interface/oper-status":"up"}}]`+"\n"+
+			`[{"name":"sub1","timestamp":2,"tags":{"source":"r2"},"values":{"/interface/oper-status":"down"}}]`+"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rrevs, err := a.runProcessors(context.Background(), []string{"add-site", "drop-admin"}, input, "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(rrevs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[
+  [{"name":"sub1","timestamp":1,"tags":{"source":"r1","site":"dc1"},"values":{"/interface/oper-status":"up"}}],
+  [{"name":"sub1","timestamp":2,"tags":{"source":"r2","site":"dc1"},"values":{"/interface/oper-status":"down"}}]
+]`
+	buf := new(bytes.Buffer)
+	a.out = buf
+	err = a.compareProcessorsOutput(out, []byte(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "PASS: 2 message(s)") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	err = a.compareProcessorsOutput(out, []byte(strings.Replace(expected, `"down"`, `"up"`, 1)))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(buf.String(), "FAIL: message 1") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const progressBarWidth = 40
+
+// progressBar prints the number of targets that
+// completed a request, out of the total number of targets.
+type progressBar struct {
+	m      *sync.Mutex
+	w      io.Writer
+	total  int
+	done   int
+	failed int
+}
+
+func newProgressBar(w io.Writer, total int) *progressBar {
+	pb := &progressBar{
+		m:     new(sync.Mutex),
+		w:     w,
+		total: total,
+	}
+	pb.render()
+	return pb
+}
+
+// add records a completed target, it is a noop on a nil progressBar.
+func (pb *progressBar) add(failed bool) {
+	if pb == nil {
+		return
+	}
+	pb.m.Lock()
+	defer pb.m.Unlock()
+	pb.done++
+	if failed {
+		pb.failed++
+	}
+	pb.render()
+}
+
+func (pb *progressBar) render() {
+	filled := progressBarWidth
+	if pb.total > 0 {
+		filled = progressBarWidth * pb.done / pb.total
+	}
+	fmt.Fprintf(pb.w, "\r[%s%s] %d/%d targets",
+		strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled),
+		pb.done, pb.total)
+	if pb.failed > 0 {
+		fmt.Fprintf(pb.w, ", %d failed", pb.failed)
+	}
+	if pb.done >= pb.total {
+		fmt.Fprintln(pb.w)
+	}
+}
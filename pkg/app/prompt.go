@@ -33,14 +33,14 @@ func (a *App) PromptRunE(cmd *cobra.Command, args []string) error {
 	a.PromptHistory = make([]string, 0, 256)
 	home, err := homedir.Dir()
 	if err != nil {
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			a.Logger.Printf("failed to get home directory: %v", err)
 		}
 		return nil
 	}
 	content, err := os.ReadFile(filepath.Join(home, ".gnmic.history"))
 	if err != nil {
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			a.Logger.Printf("failed to read history file: %v", err)
 		}
 		return nil
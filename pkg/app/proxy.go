@@ -79,10 +79,22 @@ func (a *App) ProxyRunE(cmd *cobra.Command, args []string) error {
 	a.startAPIServer()
 	go a.startLoaderProxy(cmd.Context())
 	go a.registerGNMIServer(cmd.Context(), "isProxy=true")
+	a.notifyServiceReady(cmd.Context())
 	return a.startGNMIProxyServer(cmd.Context())
 }
 
 func (a *App) startGNMIProxyServer(ctx context.Context) error {
+	a.initServerTargetsSem()
+	a.initSubscriptionQuotas()
+	err := a.initServerACL()
+	if err != nil {
+		return fmt.Errorf("gnmi-server acl: %v", err)
+	}
+	a.initClientCertTargets()
+	srvRecorder, err := a.gnmiServerRecorder()
+	if err != nil {
+		return err
+	}
 	s, err := server.New(server.Config{
 		Address:              a.Config.GnmiServer.Address,
 		MaxUnaryRPC:          a.Config.GnmiServer.MaxUnaryRPC,
@@ -95,11 +107,18 @@ func (a *App) startGNMIProxyServer(ctx context.Context) error {
 		HealthEnabled:        true,
 		RateLimit:            a.Config.GnmiServer.RateLimit,
 		TLS:                  a.Config.GnmiServer.TLS,
+		SocketOptions:        a.Config.GnmiServer.SocketOptions,
+		IPFilter:             a.Config.GnmiServer.IPFilter,
+		Compression:          a.Config.GnmiServer.Compression,
+		UnixSocket:           a.Config.GnmiServer.UnixSocket,
+		SocketActivation:     a.Config.GnmiServer.SocketActivation,
 	}, server.WithLogger(a.Logger),
 		server.WithRegistry(a.reg),
 		server.WithGetHandler(a.proxyGetHandler),
 		server.WithSetHandler(a.proxySetHandler),
-		server.WithSubscribeHandler(a.proxySubscribeHandler))
+		server.WithSubscribeHandler(a.proxySubscribeHandler),
+		server.WithRecorder(srvRecorder),
+		server.WithSubscriptionQuotas(a.subscriptionQuotas))
 	if err != nil {
 		return err
 	}
@@ -108,6 +127,14 @@ func (a *App) startGNMIProxyServer(ctx context.Context) error {
 
 func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
 	targetName := req.GetPrefix().GetTarget()
+	err := a.clientCertTargets.authorize(ctx, aclRPCGet, targetName)
+	if err != nil {
+		return nil, err
+	}
+	err = a.serverACL.authorize(ctx, aclRPCGet, targetName, req.GetPrefix(), req.GetPath())
+	if err != nil {
+		return nil, err
+	}
 	pr, _ := peer.FromContext(ctx)
 	a.Logger.Printf("received Get request from %q to target %q", pr.Addr, targetName)
 
@@ -122,6 +149,7 @@ func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.
 
 	results := make(chan *gnmi.Notification)
 	errChan := make(chan error, numTargets)
+	budget := a.newTargetsBudget(ctx, numTargets)
 
 	response := &gnmi.GetResponse{
 		// assume one notification target
@@ -152,6 +180,12 @@ func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.
 	for name, t := range targets {
 		go func(name string, t *target.Target) {
 			defer wg.Done()
+			release, err := a.acquireServerTarget(ctx)
+			if err != nil {
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
+				return
+			}
+			defer release()
 
 			creq := proto.Clone(req).(*gnmi.GetRequest)
 			if creq.GetPrefix() == nil {
@@ -160,10 +194,12 @@ func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.
 			if creq.GetPrefix().GetTarget() == "" || creq.GetPrefix().GetTarget() == "*" {
 				creq.Prefix.Target = name
 			}
-			res, err := t.Get(ctx, creq)
+			tctx, tcancel := budget.targetContext(ctx)
+			defer tcancel()
+			res, err := t.Get(tctx, creq)
 			if err != nil {
 				a.Logger.Printf("target %q err: %v", name, err)
-				errChan <- fmt.Errorf("target %q err: %v", name, err)
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
 				return
 			}
 
@@ -174,7 +210,11 @@ func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.
 				if n.GetPrefix().GetTarget() == "" {
 					n.Prefix.Target = name
 				}
-				results <- n
+				select {
+				case results <- n:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}(name, t)
 	}
@@ -183,11 +223,15 @@ func (a *App) proxyGetHandler(ctx context.Context, req *gnmi.GetRequest) (*gnmi.
 	close(errChan)
 	for err := range errChan {
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "%v", err)
+			return nil, targetRPCStatus(err)
 		}
 	}
-	<-done
-	if a.Config.Debug {
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, targetRPCStatus(ctx.Err())
+	}
+	if a.debugEnabled() {
 		a.Logger.Printf("sending GetResponse to %q: %+v", pr.Addr, response)
 	}
 	return response, nil
@@ -203,6 +247,14 @@ func (a *App) proxySetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.
 	}
 
 	targetName := req.GetPrefix().GetTarget()
+	err := a.clientCertTargets.authorize(ctx, aclRPCSet, targetName)
+	if err != nil {
+		return nil, err
+	}
+	err = a.serverACL.authorize(ctx, aclRPCSet, targetName, req.GetPrefix(), setRequestPaths(req))
+	if err != nil {
+		return nil, err
+	}
 	pr, _ := peer.FromContext(ctx)
 	a.Logger.Printf("received Set request from %q to target %q", pr.Addr, targetName)
 
@@ -216,6 +268,8 @@ func (a *App) proxySetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.
 	}
 	results := make(chan *gnmi.UpdateResult)
 	errChan := make(chan error, numTargets)
+	budget := a.newTargetsBudget(ctx, numTargets)
+	attempts := a.newSetAttempts()
 
 	response := &gnmi.SetResponse{
 		// assume one update per target, per update/replace/delete
@@ -244,6 +298,12 @@ func (a *App) proxySetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.
 	for name, t := range targets {
 		go func(name string, t *target.Target) {
 			defer wg.Done()
+			release, err := a.acquireServerTarget(ctx)
+			if err != nil {
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
+				return
+			}
+			defer release()
 
 			creq := proto.Clone(req).(*gnmi.SetRequest)
 			if creq.GetPrefix() == nil {
@@ -252,15 +312,21 @@ func (a *App) proxySetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.
 			if creq.GetPrefix().GetTarget() == "" || creq.GetPrefix().GetTarget() == "*" {
 				creq.Prefix.Target = name
 			}
-			res, err := t.Set(ctx, creq)
+			tctx, tcancel := budget.targetContext(ctx)
+			defer tcancel()
+			res, err := a.targetSet(tctx, name, t, creq, attempts)
 			if err != nil {
 				a.Logger.Printf("target %q err: %v", name, err)
-				errChan <- fmt.Errorf("target %q err: %v", name, err)
+				errChan <- fmt.Errorf("target %q err: %w", name, err)
 				return
 			}
 			for _, upd := range res.GetResponse() {
 				upd.Path.Target = name
-				results <- upd
+				select {
+				case results <- upd:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}(name, t)
 	}
@@ -269,10 +335,20 @@ func (a *App) proxySetHandler(ctx context.Context, req *gnmi.SetRequest) (*gnmi.
 	close(errChan)
 	for err := range errChan {
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "%v", err)
+			return nil, targetRPCStatus(err)
+		}
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, targetRPCStatus(ctx.Err())
+	}
+	if attempts != nil {
+		err = attempts.addExtension(a.Config.GnmiServer.SetRetry.ExtensionID, response)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to add Set attempts extension: %v", err)
 		}
 	}
-	<-done
 	a.Logger.Printf("sending SetResponse to %q: %+v", pr.Addr, response)
 	return response, nil
 }
@@ -295,6 +371,14 @@ func (a *App) proxySubscribeHandler(req *gnmi.SubscribeRequest, stream gnmi.GNMI
 
 	ctx := stream.Context()
 	targetName := getTargetFromSubscribeRequest(req)
+	err := a.clientCertTargets.authorize(ctx, aclRPCSubscribe, targetName)
+	if err != nil {
+		return err
+	}
+	err = a.serverACL.authorize(ctx, aclRPCSubscribe, targetName, req.GetSubscribe().GetPrefix(), subscribeRequestPaths(req))
+	if err != nil {
+		return err
+	}
 
 	targets, err := a.selectTargets(ctx, targetName)
 	if err != nil {
@@ -584,6 +668,7 @@ OUTER:
 }
 
 func (a *App) createTarget(ctx context.Context, tc *types.TargetConfig) (*target.Target, error) {
+	a.setTLSSessionCache(tc)
 	t := target.NewTarget(tc)
 	targetDialOpts := a.dialOpts
 	if a.Config.UseTunnelServer {
@@ -0,0 +1,417 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	reconcileActionReport  = "report"
+	reconcileActionEnforce = "enforce"
+
+	driftKindMissing    = "missing"
+	driftKindMismatch   = "mismatch"
+	driftKindUnexpected = "unexpected"
+
+	intentDriftEventName = "intent-drift"
+)
+
+var applyDriftedLeaves = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "apply",
+	Name:      "drifted_leaves",
+	Help:      "Number of leaves that differ from the intent as of the last reconciliation run",
+}, []string{"source"})
+
+var applyReconcileRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "apply",
+	Name:      "reconcile_runs_total",
+	Help:      "Total number of reconciliation runs by result",
+}, []string{"source", "result"})
+
+// intentDrift is a difference between the desired
+// and the observed state of a leaf.
+type intentDrift struct {
+	Path     string
+	Kind     string
+	Desired  string
+	Observed string
+}
+
+func (d *intentDrift) event(targetName string, ts int64) *formatters.EventMsg {
+	ev := &formatters.EventMsg{
+		Name:      intentDriftEventName,
+		Timestamp: ts,
+		Tags: map[string]string{
+			"source": targetName,
+			"path":   d.Path,
+			"kind":   d.Kind,
+		},
+		Values: make(map[string]interface{}, 2),
+	}
+	if d.Desired != "" {
+		ev.Values["desired"] = d.Desired
+	}
+	if d.Observed != "" {
+		ev.Values["observed"] = d.Observed
+	}
+	return ev
+}
+
+// reconcile runs the intent reconciliation loop for each target
+// until the app context is canceled.
+func (a *App) reconcile(tpl *template.Template, vars map[string]interface{}) error {
+	err := a.Config.GetAPIServer()
+	if err != nil {
+		return err
+	}
+	if a.Config.APIServer != nil && a.Config.APIServer.EnableMetrics && a.reg != nil {
+		a.reg.MustRegister(applyDriftedLeaves)
+		a.reg.MustRegister(applyReconcileRuns)
+	}
+	a.startAPIServer()
+	a.Logger.Printf("reconciling %d target(s) every %s, action=%s",
+		len(a.Config.Targets), a.Config.LocalFlags.ApplyReconcileInterval, a.Config.LocalFlags.ApplyReconcileAction)
+	a.operLock.RLock()
+	for _, tc := range a.Config.Targets {
+		a.wg.Add(1)
+		go func(tc *types.TargetConfig) {
+			defer a.wg.Done()
+			a.reconcileTarget(a.ctx, tc, tpl, vars)
+		}(tc)
+	}
+	a.operLock.RUnlock()
+	a.wg.Wait()
+	return nil
+}
+
+func (a *App) reconcileTarget(ctx context.Context, tc *types.TargetConfig, tpl *template.Template, vars map[string]interface{}) {
+	ticker := time.NewTicker(a.Config.LocalFlags.ApplyReconcileInterval)
+	defer ticker.Stop()
+	for {
+		result := a.reconcileOnce(ctx, tc, tpl, vars)
+		applyReconcileRuns.WithLabelValues(tc.Name, result).Inc()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileOnce compares the observed state of target tc with its intent,
+// reports the drift and re-applies the intent if the action is enforce.
+// It returns the result of the run: in-sync, drift, enforced or error.
+func (a *App) reconcileOnce(ctx context.Context, tc *types.TargetConfig, tpl *template.Template, vars map[string]interface{}) string {
+	in, err := a.renderIntent(tpl, vars, tc.Name)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to render intent: %v", tc.Name, err)
+		return "error"
+	}
+	listKeys := a.listKeysFunc(nil)
+	drifts, err := a.intentDrifts(ctx, tc, in, listKeys)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to get observed state: %v", tc.Name, err)
+		return "error"
+	}
+	applyDriftedLeaves.WithLabelValues(tc.Name).Set(float64(len(drifts)))
+	if len(drifts) == 0 {
+		if a.debugEnabled() {
+			a.Logger.Printf("target %q: in sync with intent", tc.Name)
+		}
+		return "in-sync"
+	}
+	a.printDrifts(tc.Name, drifts)
+	if a.Config.LocalFlags.ApplyReconcileAction != reconcileActionEnforce {
+		return "drift"
+	}
+	req, err := in.setRequest(listKeys)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to create set request: %v", tc.Name, err)
+		return "error"
+	}
+	a.Logger.Printf("target %q: %d drifted leaves, re-applying intent", tc.Name, len(drifts))
+	if err = a.applySetRequest(ctx, tc, req); err != nil {
+		return "error"
+	}
+	return "enforced"
+}
+
+func (a *App) printDrifts(targetName string, drifts []*intentDrift) {
+	ts := time.Now().UnixNano()
+	evs := make([]*formatters.EventMsg, 0, len(drifts))
+	for _, d := range drifts {
+		a.Logger.Printf("target %q: drift %s at %s: desired=%s observed=%s", targetName, d.Kind, d.Path, d.Desired, d.Observed)
+		evs = append(evs, d.event(targetName, ts))
+	}
+	b, err := json.MarshalIndent(evs, "", "  ")
+	if err != nil {
+		a.Logger.Printf("failed to marshal drift events: %v", err)
+		return
+	}
+	a.printLock.Lock()
+	defer a.printLock.Unlock()
+	fmt.Fprintf(a.out, "%s\n", b)
+}
+
+// intentDrifts gets the configuration under each intent resource path
+// from target tc and returns its differences with the intent.
+func (a *App) intentDrifts(ctx context.Context, tc *types.TargetConfig, in *intent, listKeys path.ListKeysFunc) ([]*intentDrift, error) {
+	enc := strings.ToLower(strings.ReplaceAll(in.Encoding, "-", "_"))
+	gnmiEnc, ok := gnmi.Encoding_value[strings.ToUpper(enc)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported intent encoding %q", in.Encoding)
+	}
+	drifts := make([]*intentDrift, 0)
+	for i, r := range in.Resources {
+		p, err := path.ParsePath(strings.TrimSpace(r.Path))
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: invalid path %q: %v", i, r.Path, err)
+		}
+		req := &gnmi.GetRequest{
+			Path:     []*gnmi.Path{p},
+			Type:     gnmi.GetRequest_CONFIG,
+			Encoding: gnmi.Encoding(gnmiEnc),
+		}
+		var observed []*gnmi.Notification
+		rsp, err := a.ClientGet(ctx, tc, req)
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		default:
+			observed = rsp.GetNotification()
+		}
+		rd, err := r.drifts(p, observed, listKeys)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		}
+		drifts = append(drifts, rd...)
+	}
+	return drifts, nil
+}
+
+// drifts compares the resource desired state at path p
+// with the observed notifications.
+func (r *intentResource) drifts(p *gnmi.Path, observed []*gnmi.Notification, listKeys path.ListKeysFunc) ([]*intentDrift, error) {
+	obs, err := observedLeaves(observed, listKeys)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(r.State) == intentStateAbsent {
+		if len(obs) == 0 {
+			return nil, nil
+		}
+		return []*intentDrift{{Path: leafKey(p), Kind: driftKindUnexpected}}, nil
+	}
+	b, err := json.Marshal(utils.Convert(r.Value))
+	if err != nil {
+		return nil, err
+	}
+	upds, err := path.FromJSON(p, b, listKeys)
+	if err != nil {
+		return nil, err
+	}
+	desired := make(map[string]string, len(upds))
+	drifts := make([]*intentDrift, 0)
+	for _, upd := range upds {
+		k := leafKey(upd.GetPath())
+		dv := normalizeJSONValue(upd.GetVal().GetJsonVal())
+		desired[k] = dv
+		ov, ok := obs[k]
+		switch {
+		case !ok:
+			drifts = append(drifts, &intentDrift{Path: k, Kind: driftKindMissing, Desired: dv})
+		case ov != dv:
+			drifts = append(drifts, &intentDrift{Path: k, Kind: driftKindMismatch, Desired: dv, Observed: ov})
+		}
+	}
+	// the configuration under a replaced path must
+	// not have any leaf absent from the intent.
+	if r.Replace {
+		for k, ov := range obs {
+			if _, ok := desired[k]; !ok {
+				drifts = append(drifts, &intentDrift{Path: k, Kind: driftKindUnexpected, Observed: ov})
+			}
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Path < drifts[j].Path
+	})
+	return drifts, nil
+}
+
+// observedLeaves flattens the notifications updates into a map
+// of normalized leaf values indexed by leaf path.
+func observedLeaves(notifications []*gnmi.Notification, listKeys path.ListKeysFunc) (map[string]string, error) {
+	leaves := make(map[string]string)
+	for _, n := range notifications {
+		for _, upd := range n.GetUpdate() {
+			p := joinPaths(n.GetPrefix(), upd.GetPath())
+			var b []byte
+			switch v := upd.GetVal().GetValue().(type) {
+			case *gnmi.TypedValue_JsonVal:
+				b = v.JsonVal
+			case *gnmi.TypedValue_JsonIetfVal:
+				b = v.JsonIetfVal
+			default:
+				sb, err := scalarJSONValue(upd.GetVal())
+				if err != nil {
+					return nil, fmt.Errorf("path %s: %v", path.GnmiPathToXPath(p, false), err)
+				}
+				leaves[leafKey(p)] = normalizeJSONValue(sb)
+				continue
+			}
+			leafUpds, err := path.FromJSON(p, b, listKeys)
+			if err != nil {
+				return nil, err
+			}
+			for _, lu := range leafUpds {
+				leaves[leafKey(lu.GetPath())] = normalizeJSONValue(lu.GetVal().GetJsonVal())
+			}
+		}
+	}
+	return leaves, nil
+}
+
+func joinPaths(prefix, p *gnmi.Path) *gnmi.Path {
+	elems := make([]*gnmi.PathElem, 0, len(prefix.GetElem())+len(p.GetElem()))
+	elems = append(elems, prefix.GetElem()...)
+	elems = append(elems, p.GetElem()...)
+	return &gnmi.Path{Elem: elems}
+}
+
+// leafKey returns the XPath of p without module prefixes,
+// so that the desired and observed leaves can be compared
+// regardless of the encoding used by the target.
+func leafKey(p *gnmi.Path) string {
+	sb := new(strings.Builder)
+	for _, pe := range p.GetElem() {
+		sb.WriteString("/")
+		sb.WriteString(stripModulePrefix(pe.GetName()))
+		keys := make([]string, 0, len(pe.GetKey()))
+		for k := range pe.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(sb, "[%s=%s]", stripModulePrefix(k), pe.GetKey()[k])
+		}
+	}
+	if sb.Len() == 0 {
+		return "/"
+	}
+	return sb.String()
+}
+
+// normalizeJSONValue returns a string representation of a JSON leaf value
+// where numbers, booleans and strings holding the same value are equal,
+// e.g: 10 and "10", or "openconfig-if-ethernet:SPEED_10GB" and "SPEED_10GB".
+func normalizeJSONValue(b []byte) string {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return string(b)
+	}
+	return normalizeValue(v)
+}
+
+func normalizeValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return stripIdentityPrefix(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalizeValue(item))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// stripIdentityPrefix removes the module prefix of an identityref value.
+// Values such as IPv6 addresses are returned unchanged.
+func stripIdentityPrefix(s string) string {
+	i := strings.Index(s, ":")
+	if i <= 0 || strings.Contains(s[i+1:], ":") || s[i+1:] == "" {
+		return s
+	}
+	if c := s[0]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+		return s
+	}
+	return s[i+1:]
+}
+
+func scalarJSONValue(tv *gnmi.TypedValue) ([]byte, error) {
+	var v any
+	switch tv.GetValue().(type) {
+	case *gnmi.TypedValue_StringVal:
+		v = tv.GetStringVal()
+	case *gnmi.TypedValue_AsciiVal:
+		v = tv.GetAsciiVal()
+	case *gnmi.TypedValue_BoolVal:
+		v = tv.GetBoolVal()
+	case *gnmi.TypedValue_IntVal:
+		v = tv.GetIntVal()
+	case *gnmi.TypedValue_UintVal:
+		v = tv.GetUintVal()
+	case *gnmi.TypedValue_DoubleVal:
+		v = tv.GetDoubleVal()
+	case *gnmi.TypedValue_LeaflistVal:
+		items := make([]json.RawMessage, 0, len(tv.GetLeaflistVal().GetElement()))
+		for _, e := range tv.GetLeaflistVal().GetElement() {
+			b, err := scalarJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, b)
+		}
+		v = items
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", tv.GetValue())
+	}
+	return json.Marshal(v)
+}
+
+func validateReconcileAction(action string) error {
+	switch action {
+	case "", reconcileActionReport, reconcileActionEnforce:
+		return nil
+	}
+	return fmt.Errorf("unknown reconcile action %q, must be report or enforce", action)
+}
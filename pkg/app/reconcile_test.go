@@ -0,0 +1,139 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+func TestIntentResourceDrifts(t *testing.T) {
+	a := New()
+	listKeys := a.listKeysFunc(map[string][]string{"interface": {"name"}})
+	jsonIETF := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(s)}}
+	}
+	tests := []struct {
+		name     string
+		resource *intentResource
+		path     string
+		observed []*gnmi.Notification
+		want     []*intentDrift
+	}{
+		{
+			name: "in_sync",
+			resource: &intentResource{
+				Value: map[string]interface{}{
+					"interface": []interface{}{
+						map[string]interface{}{"name": "e1", "config": map[string]interface{}{"mtu": 9000, "type": "ethernetCsmacd"}},
+					},
+				},
+			},
+			path: "/interfaces",
+			observed: []*gnmi.Notification{{
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interfaces"}}},
+					Val:  jsonIETF(`{"openconfig-interfaces:interface":[{"name":"e1","config":{"mtu":"9000","type":"iana-if-type:ethernetCsmacd","enabled":true}}]}`),
+				}},
+			}},
+			want: []*intentDrift{},
+		},
+		{
+			name: "missing_and_mismatch",
+			resource: &intentResource{
+				Value: map[string]interface{}{"description": "uplink", "mtu": 9000},
+			},
+			path: "/interfaces/interface[name=e1]/config",
+			observed: []*gnmi.Notification{{
+				Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interfaces"}, {Name: "interface", Key: map[string]string{"name": "e1"}}}},
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "config"}, {Name: "mtu"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1500}},
+				}},
+			}},
+			want: []*intentDrift{
+				{Path: "/interfaces/interface[name=e1]/config/description", Kind: driftKindMissing, Desired: "uplink"},
+				{Path: "/interfaces/interface[name=e1]/config/mtu", Kind: driftKindMismatch, Desired: "9000", Observed: "1500"},
+			},
+		},
+		{
+			name: "replace_unexpected",
+			resource: &intentResource{
+				Replace: true,
+				Value:   map[string]interface{}{"hostname": "r1"},
+			},
+			path: "/system/config",
+			observed: []*gnmi.Notification{{
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "config"}}},
+					Val:  jsonIETF(`{"hostname":"r1","domain-name":"lab"}`),
+				}},
+			}},
+			want: []*intentDrift{
+				{Path: "/system/config/domain-name", Kind: driftKindUnexpected, Observed: "lab"},
+			},
+		},
+		{
+			name:     "absent_not_found",
+			resource: &intentResource{State: intentStateAbsent},
+			path:     "/network-instances/network-instance[name=old]",
+			want:     nil,
+		},
+		{
+			name:     "absent_exists",
+			resource: &intentResource{State: intentStateAbsent},
+			path:     "/network-instances/network-instance[name=old]",
+			observed: []*gnmi.Notification{{
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "network-instances"}, {Name: "network-instance", Key: map[string]string{"name": "old"}}}},
+					Val:  jsonIETF(`{"name":"old"}`),
+				}},
+			}},
+			want: []*intentDrift{
+				{Path: "/network-instances/network-instance[name=old]", Kind: driftKindUnexpected},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := path.ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.resource.drifts(p, tt.observed, listKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected drifts:\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	tests := map[string]string{
+		`10`:                                  "10",
+		`"10"`:                                "10",
+		`true`:                                "true",
+		`"openconfig-if-ethernet:SPEED_10GB"`: "SPEED_10GB",
+		`"2001:db8::1"`:                       "2001:db8::1",
+		`"fe80::1"`:                           "fe80::1",
+		`["a","b"]`:                           "[a,b]",
+	}
+	for in, want := range tests {
+		if got := normalizeJSONValue([]byte(in)); got != want {
+			t.Errorf("normalizeJSONValue(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// response validation checks
+const (
+	violationTimestamp = "timestamp"
+	violationPath      = "path"
+	violationEncoding  = "encoding"
+	violationDuplicate = "duplicate"
+)
+
+var validationChecks = []string{violationTimestamp, violationPath, violationEncoding, violationDuplicate}
+
+// responseViolation is a gNMI specification violation found in a subscribe response.
+type responseViolation struct {
+	check   string
+	path    string
+	message string
+}
+
+// responseValidator checks the subscribe responses received from a target
+// configured with response-validation.
+// It is not safe for concurrent use, it is owned by the target's collector loop.
+type responseValidator struct {
+	target string
+	cfg    *types.ResponseValidationConfig
+	// checks with at least one violation logged.
+	logged map[string]struct{}
+}
+
+// newResponseValidator returns nil if the target does not have response-validation.
+func newResponseValidator(t *target.Target) *responseValidator {
+	if t.Config.ResponseValidation == nil {
+		return nil
+	}
+	return &responseValidator{
+		target: t.Config.Name,
+		cfg:    t.Config.ResponseValidation,
+		logged: make(map[string]struct{}),
+	}
+}
+
+// validate returns the violations found in rsp, received at now
+// by a subscription with the given encoding.
+func (v *responseValidator) validate(rsp *gnmi.SubscribeResponse, encoding string, now time.Time) []*responseViolation {
+	n := rsp.GetUpdate()
+	if n == nil {
+		return nil
+	}
+	vs := validateNotification(n, encoding, now, v.cfg.MaxClockSkew)
+	for _, vl := range vs {
+		subscribeResponseViolations.WithLabelValues(v.target, vl.check).Inc()
+	}
+	return vs
+}
+
+// firstOf reports whether vl is the first violation of its check.
+func (v *responseValidator) firstOf(vl *responseViolation) bool {
+	if _, ok := v.logged[vl.check]; ok {
+		return false
+	}
+	v.logged[vl.check] = struct{}{}
+	return true
+}
+
+// stop removes the target violations metrics.
+func (v *responseValidator) stop() {
+	for _, c := range validationChecks {
+		subscribeResponseViolations.DeleteLabelValues(v.target, c)
+	}
+}
+
+// event builds the event reporting violation vl.
+func (v *responseValidator) event(sub string, vl *responseViolation, ts time.Time) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      v.cfg.EventName,
+		Timestamp: ts.UnixNano(),
+		Tags: map[string]string{
+			"source":            v.target,
+			"subscription-name": sub,
+			"check":             vl.check,
+		},
+		Values: map[string]interface{}{
+			"path":      vl.path,
+			"violation": vl.message,
+		},
+	}
+}
+
+// reportViolations logs and exports the violations found in a response of subscription sub.
+// Only the first violation of each check is logged, unless debug is enabled.
+func (a *App) reportViolations(ctx context.Context, v *responseValidator, sub string, outs []string, vs []*responseViolation) {
+	now := time.Now()
+	for _, vl := range vs {
+		if v.firstOf(vl) || a.debugEnabled() {
+			a.Logger.Printf("target %q: subscription %s: %s violation: %s: %s", v.target, sub, vl.check, vl.path, vl.message)
+		}
+		if v.cfg.EventName != "" {
+			a.exportEvent(ctx, v.event(sub, vl, now), outs...)
+		}
+	}
+}
+
+// validateNotification checks the notification n timestamp, paths and values.
+func validateNotification(n *gnmi.Notification, encoding string, now time.Time, maxSkew time.Duration) []*responseViolation {
+	vs := make([]*responseViolation, 0)
+	prefix := responsePath(nil, n.GetPrefix())
+	switch {
+	case n.GetTimestamp() == 0:
+		vs = append(vs, &responseViolation{violationTimestamp, prefix, "missing timestamp"})
+	default:
+		skew := now.Sub(time.Unix(0, n.GetTimestamp()))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > maxSkew {
+			vs = append(vs, &responseViolation{violationTimestamp, prefix,
+				fmt.Sprintf("timestamp %d is %s away from the local clock", n.GetTimestamp(), skew.Round(time.Second))})
+		}
+	}
+	vs = append(vs, validatePath(n.GetPrefix(), prefix)...)
+
+	seen := make(map[string]string)
+	dup := func(p, kind string) {
+		if prev, ok := seen[p]; ok {
+			vs = append(vs, &responseViolation{violationDuplicate, p,
+				fmt.Sprintf("path in both %s and %s", prev, kind)})
+			return
+		}
+		seen[p] = kind
+	}
+	for _, upd := range n.GetUpdate() {
+		p := responsePath(n.GetPrefix(), upd.GetPath())
+		vs = append(vs, validatePath(upd.GetPath(), p)...)
+		if n.GetPrefix().GetOrigin() != "" && upd.GetPath().GetOrigin() != "" {
+			vs = append(vs, &responseViolation{violationPath, p, "origin set in both prefix and path"})
+		}
+		dup(p, "update")
+		if vl := validateTypedValue(upd.GetVal(), encoding); vl != "" {
+			vs = append(vs, &responseViolation{violationEncoding, p, vl})
+		}
+	}
+	for _, del := range n.GetDelete() {
+		p := responsePath(n.GetPrefix(), del)
+		vs = append(vs, validatePath(del, p)...)
+		dup(p, "delete")
+	}
+	return vs
+}
+
+// validatePath checks the elements of path p, xp is the path reported in the violations.
+func validatePath(p *gnmi.Path, xp string) []*responseViolation {
+	if p == nil {
+		return nil
+	}
+	var vs []*responseViolation
+	if len(p.GetElement()) > 0 {
+		vs = append(vs, &responseViolation{violationPath, xp, "deprecated element field used"})
+	}
+	for _, e := range p.GetElem() {
+		switch {
+		case e.GetName() == "":
+			vs = append(vs, &responseViolation{violationPath, xp, "empty path element name"})
+		case strings.ContainsAny(e.GetName(), "/[]"):
+			vs = append(vs, &responseViolation{violationPath, xp, fmt.Sprintf("invalid path element name %q", e.GetName())})
+		}
+		for k, val := range e.GetKey() {
+			if k == "" || val == "" {
+				vs = append(vs, &responseViolation{violationPath, xp, fmt.Sprintf("empty key name or value in element %q", e.GetName())})
+			}
+		}
+	}
+	return vs
+}
+
+// validateTypedValue checks that the value tv matches the subscription encoding.
+// It returns the violation message, empty if tv is valid.
+func validateTypedValue(tv *gnmi.TypedValue, encoding string) string {
+	if tv == nil {
+		return "update without value"
+	}
+	encoding = strings.ReplaceAll(strings.ToLower(encoding), "-", "_")
+	switch v := tv.GetValue().(type) {
+	case nil:
+		return "update without value"
+	case *gnmi.TypedValue_JsonVal:
+		if encoding != "" && encoding != "json" {
+			return fmt.Sprintf("json_val received with encoding %s", encoding)
+		}
+		if !json.Valid(v.JsonVal) {
+			return "json_val is not valid JSON"
+		}
+	case *gnmi.TypedValue_JsonIetfVal:
+		if encoding != "" && encoding != "json_ietf" {
+			return fmt.Sprintf("json_ietf_val received with encoding %s", encoding)
+		}
+		if !json.Valid(v.JsonIetfVal) {
+			return "json_ietf_val is not valid JSON"
+		}
+	case *gnmi.TypedValue_AsciiVal:
+		if encoding != "" && encoding != "ascii" {
+			return fmt.Sprintf("ascii_val received with encoding %s", encoding)
+		}
+	case *gnmi.TypedValue_BytesVal:
+		if encoding != "" && encoding != "bytes" && encoding != "proto" {
+			return fmt.Sprintf("bytes_val received with encoding %s", encoding)
+		}
+	}
+	return ""
+}
+
+// responsePath returns the xpath of path p under prefix.
+func responsePath(prefix, p *gnmi.Path) string {
+	origin := p.GetOrigin()
+	if origin == "" {
+		origin = prefix.GetOrigin()
+	}
+	return path.GnmiPathToXPath(&gnmi.Path{Origin: origin, Elem: path.PathElems(prefix, p)}, false)
+}
@@ -0,0 +1,106 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestValidateNotification(t *testing.T) {
+	now := time.Now()
+	elem := func(names ...string) *gnmi.Path {
+		p := &gnmi.Path{}
+		for _, n := range names {
+			p.Elem = append(p.Elem, &gnmi.PathElem{Name: n})
+		}
+		return p
+	}
+	jsonVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{"a":1}`)}}
+	tests := map[string]struct {
+		n        *gnmi.Notification
+		encoding string
+		want     []string
+	}{
+		"valid": {
+			n: &gnmi.Notification{
+				Timestamp: now.UnixNano(),
+				Prefix:    &gnmi.Path{Origin: "openconfig", Elem: []*gnmi.PathElem{{Name: "interfaces"}}},
+				Update: []*gnmi.Update{
+					{Path: elem("interface"), Val: jsonVal},
+					{Path: elem("counters"), Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1}}},
+				},
+				Delete: []*gnmi.Path{elem("subinterfaces")},
+			},
+			encoding: "json",
+		},
+		"missing_timestamp": {
+			n:    &gnmi.Notification{Update: []*gnmi.Update{{Path: elem("a"), Val: jsonVal}}},
+			want: []string{violationTimestamp},
+		},
+		"timestamp_in_seconds": {
+			n:    &gnmi.Notification{Timestamp: now.Unix(), Update: []*gnmi.Update{{Path: elem("a"), Val: jsonVal}}},
+			want: []string{violationTimestamp},
+		},
+		"bad_paths": {
+			n: &gnmi.Notification{
+				Timestamp: now.UnixNano(),
+				Prefix:    &gnmi.Path{Origin: "openconfig"},
+				Update: []*gnmi.Update{
+					{Path: &gnmi.Path{Origin: "openconfig", Elem: []*gnmi.PathElem{{Name: "a"}}}, Val: jsonVal},
+					{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "b/c"}}}, Val: jsonVal},
+					{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "d", Key: map[string]string{"name": ""}}}}, Val: jsonVal},
+					{Path: &gnmi.Path{Element: []string{"e"}}, Val: jsonVal},
+				},
+			},
+			want: []string{violationPath, violationPath, violationPath, violationPath},
+		},
+		"encoding_mismatch": {
+			n: &gnmi.Notification{
+				Timestamp: now.UnixNano(),
+				Update: []*gnmi.Update{
+					{Path: elem("a"), Val: jsonVal},
+					{Path: elem("b"), Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{`)}}},
+					{Path: elem("c")},
+				},
+			},
+			encoding: "json_ietf",
+			want:     []string{violationEncoding, violationEncoding, violationEncoding},
+		},
+		"duplicates": {
+			n: &gnmi.Notification{
+				Timestamp: now.UnixNano(),
+				Update: []*gnmi.Update{
+					{Path: elem("a"), Val: jsonVal},
+					{Path: elem("a"), Val: jsonVal},
+				},
+				Delete: []*gnmi.Path{elem("a")},
+			},
+			want: []string{violationDuplicate, violationDuplicate},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			vs := validateNotification(tt.n, tt.encoding, now, time.Minute)
+			if len(vs) != len(tt.want) {
+				for _, vl := range vs {
+					t.Logf("%s: %s: %s", vl.check, vl.path, vl.message)
+				}
+				t.Fatalf("expected %d violations, got %d", len(tt.want), len(vs))
+			}
+			for i, vl := range vs {
+				if vl.check != tt.want[i] {
+					t.Errorf("violation %d: expected check %q, got %q: %s", i, tt.want[i], vl.check, vl.message)
+				}
+			}
+		})
+	}
+}
@@ -19,13 +19,20 @@ func (a *App) routes() {
 	a.clusterRoutes(apiV1)
 	a.configRoutes(apiV1)
 	a.targetRoutes(apiV1)
+	a.outputRoutes(apiV1)
 	a.healthRoutes(apiV1)
+	a.gnmiServerRoutes(apiV1)
+	a.adminRoutes(apiV1)
+	a.jobRoutes(apiV1)
+	a.actionRoutes(apiV1)
+	a.schemaRoutes(apiV1)
 }
 
 func (a *App) clusterRoutes(r *mux.Router) {
 	r.HandleFunc("/cluster", a.handleClusteringGet).Methods(http.MethodGet)
 	r.HandleFunc("/cluster/members", a.handleClusteringMembersGet).Methods(http.MethodGet)
 	r.HandleFunc("/cluster/leader", a.handleClusteringLeaderGet).Methods(http.MethodGet)
+	r.HandleFunc("/cluster/metrics", a.handleClusterMetrics).Methods(http.MethodGet)
 }
 
 func (a *App) configRoutes(r *mux.Router) {
@@ -35,12 +42,20 @@ func (a *App) configRoutes(r *mux.Router) {
 	r.HandleFunc("/config/targets", a.handleConfigTargetsGet).Methods(http.MethodGet)
 	r.HandleFunc("/config/targets/{id}", a.handleConfigTargetsGet).Methods(http.MethodGet)
 	r.HandleFunc("/config/targets", a.handleConfigTargetsPost).Methods(http.MethodPost)
+	r.HandleFunc("/config/targets/bulk", a.handleConfigTargetsBulkPost).Methods(http.MethodPost)
 	r.HandleFunc("/config/targets/{id}", a.handleConfigTargetsDelete).Methods(http.MethodDelete)
+	r.HandleFunc("/config/targets/{id}", a.handleConfigTargetsPut).Methods(http.MethodPut)
 	r.HandleFunc("/config/targets/{id}/subscriptions", a.handleConfigTargetsSubscriptions).Methods(http.MethodPatch)
 	// config/subscriptions
 	r.HandleFunc("/config/subscriptions", a.handleConfigSubscriptions).Methods(http.MethodGet)
+	r.HandleFunc("/config/subscriptions/{id}", a.handleConfigSubscriptionsGet).Methods(http.MethodGet)
+	r.HandleFunc("/config/subscriptions/{id}", a.handleConfigSubscriptionsPut).Methods(http.MethodPut)
+	r.HandleFunc("/config/subscriptions/{id}", a.handleConfigSubscriptionsDelete).Methods(http.MethodDelete)
 	// config/outputs
 	r.HandleFunc("/config/outputs", a.handleConfigOutputs).Methods(http.MethodGet)
+	r.HandleFunc("/config/outputs/{id}", a.handleConfigOutputsGet).Methods(http.MethodGet)
+	r.HandleFunc("/config/outputs/{id}", a.handleConfigOutputsPut).Methods(http.MethodPut)
+	r.HandleFunc("/config/outputs/{id}", a.handleConfigOutputsDelete).Methods(http.MethodDelete)
 	// config/inputs
 	r.HandleFunc("/config/inputs", a.handleConfigInputs).Methods(http.MethodGet)
 	// config/processors
@@ -56,11 +71,53 @@ func (a *App) configRoutes(r *mux.Router) {
 func (a *App) targetRoutes(r *mux.Router) {
 	// targets
 	r.HandleFunc("/targets", a.handleTargetsGet).Methods(http.MethodGet)
+	r.HandleFunc("/targets/statistics", a.handleTargetsStatisticsGet).Methods(http.MethodGet)
 	r.HandleFunc("/targets/{id}", a.handleTargetsGet).Methods(http.MethodGet)
 	r.HandleFunc("/targets/{id}", a.handleTargetsPost).Methods(http.MethodPost)
 	r.HandleFunc("/targets/{id}", a.handleTargetsDelete).Methods(http.MethodDelete)
+	r.HandleFunc("/targets/{id}/cache", a.handleTargetsCacheGet).Methods(http.MethodGet)
+	r.HandleFunc("/targets/{id}/sync", a.handleTargetsSyncGet).Methods(http.MethodGet)
+	r.HandleFunc("/targets/{id}/statistics", a.handleTargetsStatisticsGet).Methods(http.MethodGet)
+	r.HandleFunc("/targets/{id}/poll", a.handleTargetsPollGet).Methods(http.MethodGet)
+	r.HandleFunc("/targets/{id}/poll", a.handleTargetsPollPost).Methods(http.MethodPost)
+	// cached capabilities
+	r.HandleFunc("/capabilities", a.handleCapabilitiesGet).Methods(http.MethodGet)
+	r.HandleFunc("/capabilities/{id}", a.handleCapabilitiesGet).Methods(http.MethodGet)
+}
+
+func (a *App) outputRoutes(r *mux.Router) {
+	// outputs
+	r.HandleFunc("/outputs", a.handleOutputsGet).Methods(http.MethodGet)
+	r.HandleFunc("/outputs/metrics", a.handleOutputsMetrics).Methods(http.MethodGet)
+	r.HandleFunc("/outputs/{id}", a.handleOutputsGet).Methods(http.MethodGet)
+}
+
+func (a *App) gnmiServerRoutes(r *mux.Router) {
+	// gnmi-server clients subscriptions usage
+	r.HandleFunc("/gnmi-server/clients", a.handleGNMIServerClientsGet).Methods(http.MethodGet)
+}
+
+func (a *App) schemaRoutes(r *mux.Router) {
+	// outputs, inputs and processors config schema
+	r.HandleFunc("/schema", a.handleSchemaGet).Methods(http.MethodGet)
+	r.HandleFunc("/schema/{kind}", a.handleSchemaGet).Methods(http.MethodGet)
+	r.HandleFunc("/schema/{kind}/{type}", a.handleSchemaGet).Methods(http.MethodGet)
 }
 
 func (a *App) healthRoutes(r *mux.Router) {
 	r.HandleFunc("/healthz", a.handleHealthzGet).Methods(http.MethodGet)
 }
+
+func (a *App) jobRoutes(r *mux.Router) {
+	r.HandleFunc("/jobs", a.handleJobsGet).Methods(http.MethodGet)
+	r.HandleFunc("/jobs/{id}", a.handleJobsGet).Methods(http.MethodGet)
+}
+
+func (a *App) adminRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/log-level", a.handleAdminLogLevelGet).Methods(http.MethodGet)
+	r.HandleFunc("/admin/log-level", a.handleAdminLogLevelPut).Methods(http.MethodPut)
+	r.HandleFunc("/admin/cache/dump", a.handleAdminCacheDump).Methods(http.MethodPost)
+	r.HandleFunc("/admin/processors/bypass", a.handleAdminProcessorsBypassGet).Methods(http.MethodGet)
+	r.HandleFunc("/admin/processors/{name}/bypass", a.handleAdminProcessorBypass).Methods(http.MethodPost)
+	r.HandleFunc("/admin/processors/{name}/bypass", a.handleAdminProcessorRestore).Methods(http.MethodDelete)
+}
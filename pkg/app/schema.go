@@ -0,0 +1,68 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// InitConfigSchemaFlags used to init or reset configSchemaCmd flags for gnmic-prompt mode
+func (a *App) InitConfigSchemaFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigSchemaKind, "kind", "", "", fmt.Sprintf("plugin kind, one of: %v", config.SchemaKinds))
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ConfigSchemaType, "type", "", "", "plugin type, e.g: kafka. Requires --kind")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "config-schema", flag.Name), flag)
+	})
+}
+
+// ConfigSchemaRunE prints the configuration schema of the outputs, inputs and processors
+// this binary supports, as YAML or as JSON if the global format is json.
+func (a *App) ConfigSchemaRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitConfigSchemaFlags(cmd)
+
+	schema, err := config.SelectPluginsSchema(a.Config.LocalFlags.ConfigSchemaKind, a.Config.LocalFlags.ConfigSchemaType)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if a.Config.Format == formatJSON {
+		b, err = json.MarshalIndent(schema, "", "  ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(schema)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = a.out.Write(b)
+	return err
+}
+
+func (a *App) handleSchemaGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	schema, err := config.SelectPluginsSchema(vars["kind"], vars["type"])
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{err.Error()}})
+		return
+	}
+	a.handlerCommonGet(w, schema)
+}
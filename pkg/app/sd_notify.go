@@ -0,0 +1,95 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// systemd notify protocol states
+const (
+	sdNotifyReady    = "READY=1"
+	sdNotifyStopping = "STOPPING=1"
+	sdNotifyWatchdog = "WATCHDOG=1"
+)
+
+// sdNotify sends state to the systemd notify socket found in
+// the NOTIFY_SOCKET environment variable.
+// It returns false without an error if gNMIc is not running
+// as a systemd service with notify support.
+func sdNotify(state string) (bool, error) {
+	socketAddr := &net.UnixAddr{
+		Name: os.Getenv("NOTIFY_SOCKET"),
+		Net:  "unixgram",
+	}
+	if socketAddr.Name == "" {
+		return false, nil
+	}
+	conn, err := net.DialUnix(socketAddr.Net, nil, socketAddr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sdWatchdogInterval returns the interval at which systemd expects
+// the watchdog keep-alive messages, 0 if the watchdog is not enabled.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// notifyServiceReady signals systemd that gNMIc finished starting up,
+// then sends the watchdog keep-alive messages at half the configured
+// watchdog interval until ctx is done.
+func (a *App) notifyServiceReady(ctx context.Context) {
+	ok, err := sdNotify(sdNotifyReady)
+	if err != nil {
+		a.Logger.Printf("failed to notify systemd: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	a.Logger.Printf("notified systemd of service readiness")
+	go func() {
+		var tickerCh <-chan time.Time
+		if interval := sdWatchdogInterval(); interval > 0 {
+			ticker := time.NewTicker(interval / 2)
+			defer ticker.Stop()
+			tickerCh = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				sdNotify(sdNotifyStopping)
+				return
+			case <-tickerCh:
+				_, err := sdNotify(sdNotifyWatchdog)
+				if err != nil {
+					a.Logger.Printf("failed to send systemd watchdog keep-alive: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,81 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package app
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	ok, err := sdNotify(sdNotifyReady)
+	if ok || err != nil {
+		t.Fatalf("expected a noop without NOTIFY_SOCKET, got %v, %v", ok, err)
+	}
+
+	sockPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to create fake notify socket: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", sockPath)
+
+	ok, err = sdNotify(sdNotifyReady)
+	if !ok || err != nil {
+		t.Fatalf("expected notification to be sent, got %v, %v", ok, err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notification: %v", err)
+	}
+	if string(buf[:n]) != sdNotifyReady {
+		t.Errorf("unexpected notification: %q", string(buf[:n]))
+	}
+
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	ok, err = sdNotify(sdNotifyReady)
+	if ok || err == nil {
+		t.Errorf("expected an error with an unreachable socket, got %v, %v", ok, err)
+	}
+}
+
+func TestSdWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		usec string
+		pid  string
+		want time.Duration
+	}{
+		{name: "not_set", want: 0},
+		{name: "invalid", usec: "abc", want: 0},
+		{name: "negative", usec: "-1", want: 0},
+		{name: "set", usec: "30000000", want: 30 * time.Second},
+		{name: "same_pid", usec: "2000000", pid: strconv.Itoa(os.Getpid()), want: 2 * time.Second},
+		{name: "other_pid", usec: "2000000", pid: "1", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+			if got := sdWatchdogInterval(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// share of the time left before the client deadline kept
+// to build and send the response, once the targets responded.
+const serverResponseMarginRatio = 20 // 5%
+
+// targetsBudget splits the deadline of a Get or Set RPC received by the gNMI server
+// between the RPCs fanned out to the targets.
+// The targets are contacted in waves of max-concurrent-targets,
+// each target RPC gets the time left divided by the number of waves left.
+type targetsBudget struct {
+	deadline    time.Time
+	concurrency int64
+	pending     atomic.Int64
+}
+
+// newTargetsBudget returns a targetsBudget for an RPC fanned out to numTargets targets.
+// It returns nil if the context has no deadline.
+func (a *App) newTargetsBudget(ctx context.Context, numTargets int) *targetsBudget {
+	deadline, ok := ctx.Deadline()
+	if !ok || numTargets == 0 {
+		return nil
+	}
+	concurrency := numTargets
+	if a.serverTargetsSem != nil && cap(a.serverTargetsSem) < concurrency {
+		concurrency = cap(a.serverTargetsSem)
+	}
+	b := &targetsBudget{
+		deadline:    deadline.Add(-time.Until(deadline) / serverResponseMarginRatio),
+		concurrency: int64(concurrency),
+	}
+	b.pending.Store(int64(numTargets))
+	return b
+}
+
+// targetContext returns the context of the RPC sent to a single target.
+// It must be called once per target, right before sending the RPC.
+func (b *targetsBudget) targetContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b == nil {
+		return context.WithCancel(ctx)
+	}
+	pending := b.pending.Add(-1) + 1
+	if pending < 1 {
+		pending = 1
+	}
+	waves := (pending + b.concurrency - 1) / b.concurrency
+	return context.WithTimeout(ctx, time.Until(b.deadline)/time.Duration(waves))
+}
+
+// targetRPCStatus converts an error returned by a target RPC
+// into the status returned to the gNMI server client.
+func targetRPCStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded:
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled:
+		code = codes.Canceled
+	}
+	return status.Errorf(code, "%v", err)
+}
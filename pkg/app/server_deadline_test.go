@@ -0,0 +1,83 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTargetsBudget(t *testing.T) {
+	a := &App{}
+	if b := a.newTargetsBudget(context.Background(), 4); b != nil {
+		t.Fatalf("expected a nil budget without a deadline")
+	}
+	// a nil budget does not set a deadline
+	tctx, cancel := (*targetsBudget)(nil).targetContext(context.Background())
+	defer cancel()
+	if _, ok := tctx.Deadline(); ok {
+		t.Errorf("unexpected deadline set on the target context")
+	}
+
+	a.serverTargetsSem = make(chan struct{}, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	b := a.newTargetsBudget(ctx, 4)
+	// 4 targets, 2 at a time: the first 2 targets get half of the budget,
+	// the last 2 get what's left.
+	for i, want := range []time.Duration{4750 * time.Millisecond, 4750 * time.Millisecond, 9500 * time.Millisecond, 9500 * time.Millisecond} {
+		tctx, tcancel := b.targetContext(ctx)
+		defer tcancel()
+		d, ok := tctx.Deadline()
+		if !ok {
+			t.Fatalf("target %d: missing deadline", i)
+		}
+		got := time.Until(d)
+		if got > want || got < want-200*time.Millisecond {
+			t.Errorf("target %d: expected a timeout of about %s, got %s", i, want, got)
+		}
+	}
+}
+
+func TestTargetRPCStatus(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"context_deadline": {
+			err:  fmt.Errorf("target %q err: %w", "t1", context.DeadlineExceeded),
+			code: codes.DeadlineExceeded,
+		},
+		"grpc_deadline": {
+			err:  fmt.Errorf("target %q err: %w", "t1", status.Error(codes.DeadlineExceeded, "context deadline exceeded")),
+			code: codes.DeadlineExceeded,
+		},
+		"canceled": {
+			err:  fmt.Errorf("target %q err: %w", "t1", status.Error(codes.Canceled, "context canceled")),
+			code: codes.Canceled,
+		},
+		"other": {
+			err:  fmt.Errorf("target %q err: %w", "t1", errors.New("connection refused")),
+			code: codes.Internal,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := status.Code(targetRPCStatus(tt.err)); got != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, got)
+			}
+		})
+	}
+}
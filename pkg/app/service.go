@@ -0,0 +1,89 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultServiceName        = "gnmic"
+	defaultServiceDisplayName = "gNMIc"
+	defaultServiceDescription = "gNMI collector"
+
+	// environment variable used to pass the name of the event
+	// signaled by the service manager to stop the service command.
+	serviceStopEventEnv = "GNMIC_SERVICE_STOP_EVENT"
+)
+
+var errServiceUnsupported = errors.New("service management is only supported on Windows, use a systemd unit with Type=notify on Linux")
+
+// InitServiceFlags used to init or reset the service sub commands flags for gnmic-prompt mode
+func (a *App) InitServiceFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.ServiceName, "name", "", defaultServiceName, "service name")
+	if cmd.Name() == "install" {
+		cmd.Flags().StringVarP(&a.Config.LocalFlags.ServiceDisplayName, "display-name", "", defaultServiceDisplayName, "service display name")
+		cmd.Flags().StringVarP(&a.Config.LocalFlags.ServiceDescription, "description", "", defaultServiceDescription, "service description")
+	}
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "service", flag.Name), flag)
+	})
+}
+
+// ServiceInstallRunE installs gNMIc as a service, the args are
+// the gNMIc command line the service runs, e.g: `--config gnmic.yaml subscribe`
+func (a *App) ServiceInstallRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitServiceFlags(cmd)
+	if len(args) == 0 {
+		return errors.New("missing service command, e.g: gnmic service install -- --config gnmic.yaml subscribe")
+	}
+	err := installService(a.Config.LocalFlags.ServiceName,
+		a.Config.LocalFlags.ServiceDisplayName,
+		a.Config.LocalFlags.ServiceDescription,
+		args)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "service %q installed\n", a.Config.LocalFlags.ServiceName)
+	return nil
+}
+
+func (a *App) ServiceUninstallRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitServiceFlags(cmd)
+	err := uninstallService(a.Config.LocalFlags.ServiceName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "service %q uninstalled\n", a.Config.LocalFlags.ServiceName)
+	return nil
+}
+
+// ServiceRunRunE is the entry point of the installed service,
+// it runs the gNMIc command line given as args under the service manager control.
+func (a *App) ServiceRunRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitServiceFlags(cmd)
+	if len(args) == 0 {
+		return errors.New("missing service command")
+	}
+	return runService(a.Config.LocalFlags.ServiceName, args)
+}
+
+// NotifyServiceStop makes the service manager stop requests send os.Interrupt to c,
+// it is a noop if gNMIc is not running as a service command.
+func NotifyServiceStop(c chan<- os.Signal) {
+	notifyServiceStop(c)
+}
@@ -0,0 +1,206 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const serviceStopTimeout = 10 * time.Second
+
+func installService(name, displayName, description string, args []string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	exePath, err = filepath.Abs(exePath)
+	if err != nil {
+		return err
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %v", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err == nil {
+		s.Close()
+		return fmt.Errorf("service %q already exists", name)
+	}
+	svcArgs := append([]string{"service", "run", "--name", name, "--"}, args...)
+	s, err = m.CreateService(name, exePath, mgr.Config{
+		DisplayName: displayName,
+		Description: description,
+		StartType:   mgr.StartAutomatic,
+	}, svcArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to create service %q: %v", name, err)
+	}
+	defer s.Close()
+	err = eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil {
+		s.Delete()
+		return fmt.Errorf("failed to setup service %q event log source: %v", name, err)
+	}
+	return nil
+}
+
+func uninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %v", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %q is not installed", name)
+	}
+	defer s.Close()
+	err = s.Delete()
+	if err != nil {
+		return fmt.Errorf("failed to delete service %q: %v", name, err)
+	}
+	return eventlog.Remove(name)
+}
+
+func runService(name string, args []string) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		return fmt.Errorf("not running under the service manager, use `gnmic %s` instead", args)
+	}
+	elog, err := eventlog.Open(name)
+	if err != nil {
+		return err
+	}
+	defer elog.Close()
+	elog.Info(1, fmt.Sprintf("starting service %q", name))
+	err = svc.Run(name, &windowsService{name: name, args: args, elog: elog})
+	if err != nil {
+		elog.Error(1, fmt.Sprintf("service %q failed: %v", name, err))
+		return err
+	}
+	elog.Info(1, fmt.Sprintf("service %q stopped", name))
+	return nil
+}
+
+// windowsService runs the gNMIc command line args as a child
+// process and reports its state to the service manager.
+type windowsService struct {
+	name string
+	args []string
+	elog *eventlog.Log
+}
+
+func (ws *windowsService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.StartPending}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		ws.elog.Error(1, fmt.Sprintf("failed to get executable path: %v", err))
+		return true, 1
+	}
+	// the child process is asked to stop gracefully by signaling
+	// a named event, its name is passed as an environment variable.
+	stopEventName := fmt.Sprintf("Local\\gnmic-service-%s-%d-stop", ws.name, os.Getpid())
+	stopEventNamePtr, err := windows.UTF16PtrFromString(stopEventName)
+	if err != nil {
+		ws.elog.Error(1, fmt.Sprintf("invalid stop event name: %v", err))
+		return true, 1
+	}
+	stopEvent, err := windows.CreateEvent(nil, 1, 0, stopEventNamePtr)
+	if err != nil {
+		ws.elog.Error(1, fmt.Sprintf("failed to create stop event: %v", err))
+		return true, 1
+	}
+	defer windows.CloseHandle(stopEvent)
+
+	cmd := exec.Command(exePath, ws.args...)
+	cmd.Env = append(os.Environ(), serviceStopEventEnv+"="+stopEventName)
+	err = cmd.Start()
+	if err != nil {
+		ws.elog.Error(1, fmt.Sprintf("failed to start %v: %v", ws.args, err))
+		return true, 1
+	}
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- cmd.Wait()
+	}()
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+	for {
+		select {
+		case err := <-doneCh:
+			if err != nil {
+				ws.elog.Error(1, fmt.Sprintf("service command exited: %v", err))
+				return true, 1
+			}
+			return false, 0
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending, WaitHint: uint32(serviceStopTimeout / time.Millisecond)}
+				err := windows.SetEvent(stopEvent)
+				if err != nil {
+					ws.elog.Warning(1, fmt.Sprintf("failed to signal the service command to stop: %v", err))
+				}
+				select {
+				case <-doneCh:
+				case <-time.After(serviceStopTimeout):
+					ws.elog.Warning(1, fmt.Sprintf("service command did not stop after %s, killing it", serviceStopTimeout))
+					cmd.Process.Kill()
+					<-doneCh
+				}
+				return false, 0
+			default:
+				ws.elog.Warning(1, fmt.Sprintf("unexpected control request #%d", c))
+			}
+		}
+	}
+}
+
+// notifyServiceStop sends os.Interrupt to c when the service
+// stop event found in the environment is signaled.
+func notifyServiceStop(c chan<- os.Signal) {
+	name := os.Getenv(serviceStopEventEnv)
+	if name == "" {
+		return
+	}
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return
+	}
+	h, err := windows.OpenEvent(windows.SYNCHRONIZE, false, namePtr)
+	if err != nil {
+		return
+	}
+	go func() {
+		defer windows.CloseHandle(h)
+		ev, err := windows.WaitForSingleObject(h, windows.INFINITE)
+		if err != nil || ev != windows.WAIT_OBJECT_0 {
+			return
+		}
+		c <- os.Interrupt
+	}()
+}
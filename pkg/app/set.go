@@ -10,6 +10,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
@@ -27,6 +28,19 @@ func (a *App) SetPreRunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if a.Config.SetValidate {
+		if len(a.Config.GlobalFlags.File) == 0 {
+			return errors.New("flag --validate requires the YANG files to be set with --file")
+		}
+		err = a.yangFilesPreProcessing()
+		if err != nil {
+			return err
+		}
+		err = a.generateYangSchema(a.Config.GlobalFlags.File, a.Config.GlobalFlags.Exclude)
+		if err != nil {
+			return fmt.Errorf("failed loading the YANG schema: %v", err)
+		}
+	}
 
 	a.createCollectorDialOpts()
 	return a.initTunnelServer(tunnel.ServerConfig{
@@ -61,29 +75,35 @@ func (a *App) SetRunE(cmd *cobra.Command, args []string) error {
 	}
 	numTargets := len(a.Config.Targets)
 	a.errCh = make(chan error, numTargets*2)
-	a.wg.Add(numTargets)
-	for _, tc := range a.Config.Targets {
-		go a.SetRequest(ctx, tc)
-	}
-	a.wg.Wait()
-	return a.checkErrors()
+	rs := a.runOnTargets(ctx, a.Config.LocalFlags.SetMaxConcurrentTargets, 0, a.SetRequest)
+	return a.checkTargetsErrors(rs)
 }
 
-func (a *App) SetRequest(ctx context.Context, tc *types.TargetConfig) {
-	defer a.wg.Done()
+func (a *App) SetRequest(ctx context.Context, tc *types.TargetConfig) error {
 	reqs, err := a.Config.CreateSetRequest(tc.Name)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q: failed to create set request: %v", tc.Name, err))
-		return
+		return err
 	}
+	var errs []error
 	for _, req := range reqs {
-		a.setRequest(ctx, tc, req)
+		if err := a.setRequest(ctx, tc, req); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
 }
 
-func (a *App) setRequest(ctx context.Context, tc *types.TargetConfig, req *gnmi.SetRequest) {
+func (a *App) setRequest(ctx context.Context, tc *types.TargetConfig, req *gnmi.SetRequest) error {
 	a.Logger.Printf("sending gNMI SetRequest: prefix='%v', delete='%v', replace='%v', update='%v', extension='%v' to %s",
 		req.Prefix, req.Delete, req.Replace, req.Update, req.Extension, tc.Name)
+	if a.Config.SetValidate {
+		err := validateSetRequest(a.SchemaTree, req)
+		if err != nil {
+			a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
+			return err
+		}
+	}
 	if a.Config.PrintRequest || a.Config.SetDryRun {
 		err := a.PrintMsg(tc.Name, "Set Request:", req)
 		if err != nil {
@@ -91,17 +111,18 @@ func (a *App) setRequest(ctx context.Context, tc *types.TargetConfig, req *gnmi.
 		}
 	}
 	if a.Config.SetDryRun {
-		return
+		return nil
 	}
 	response, err := a.ClientSet(ctx, tc, req)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q set request failed: %v", tc.Name, err))
-		return
+		return err
 	}
 	err = a.PrintMsg(tc.Name, "Set Response:", response)
 	if err != nil {
 		a.logError(fmt.Errorf("target %q: %v", tc.Name, err))
 	}
+	return err
 }
 
 // InitSetFlags used to init or reset setCmd flags for gnmic-prompt mode
@@ -142,8 +163,10 @@ func (a *App) InitSetFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&a.Config.LocalFlags.SetCommitId, "commit-id", "", "", "commit ID value")
 	cmd.Flags().BoolVarP(&a.Config.LocalFlags.SetCommitRequest, "commit-request", "", false, "start a commit confirmed transaction")
 	cmd.Flags().BoolVarP(&a.Config.LocalFlags.SetCommitConfirm, "commit-confirm", "", false, "confirm the commit ID")
+	cmd.Flags().UintVarP(&a.Config.LocalFlags.SetMaxConcurrentTargets, "max-concurrent-targets", "", 0, "maximum number of targets the request is sent to concurrently, overrides the global flag --concurrency")
 	cmd.Flags().BoolVarP(&a.Config.LocalFlags.SetCommitCancel, "commit-cancel", "", false, "cancel the commit")
 	cmd.Flags().DurationVarP(&a.Config.LocalFlags.SetCommitRollbackDuration, "rollback-duration", "", 0, "set the commit rollback duration")
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.SetValidate, "validate", "", false, "validate the set request against the YANG models loaded with --file before sending it")
 
 	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
 		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
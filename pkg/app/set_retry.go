@@ -0,0 +1,138 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/target"
+)
+
+// setAttempts collects the Set attempts of the targets
+// a Set RPC received by the gNMI server is fanned out to.
+type setAttempts struct {
+	m       sync.Mutex
+	targets []*api.TargetSetAttempts
+}
+
+func (a *App) newSetAttempts() *setAttempts {
+	if a.Config.GnmiServer.SetRetry == nil {
+		return nil
+	}
+	return new(setAttempts)
+}
+
+func (sa *setAttempts) add(ta *api.TargetSetAttempts) {
+	if sa == nil {
+		return
+	}
+	sa.m.Lock()
+	defer sa.m.Unlock()
+	sa.targets = append(sa.targets, ta)
+}
+
+// addExtension adds the collected Set attempts to the SetResponse rsp.
+func (sa *setAttempts) addExtension(id int32, rsp *gnmi.SetResponse) error {
+	if sa == nil {
+		return nil
+	}
+	sa.m.Lock()
+	defer sa.m.Unlock()
+	return api.Extension_SetAttempts(gnmi_ext.ExtensionID(id), &api.SetAttempts{Targets: sa.targets})(rsp)
+}
+
+// targetSet sends the SetRequest req to target t.
+// If the gnmi-server set-retry is configured, the RPC is retried
+// when it fails with a transient error code.
+// An attempt that failed with DEADLINE_EXCEEDED, or with UNAVAILABLE while
+// the target connection was ready, may have been applied by the target:
+// the request is retried only if it is idempotent or if retry-non-idempotent is set.
+func (a *App) targetSet(ctx context.Context, name string, t *target.Target, req *gnmi.SetRequest, sa *setAttempts) (*gnmi.SetResponse, error) {
+	cfg := a.Config.GnmiServer.SetRetry
+	if cfg == nil {
+		return t.Set(ctx, req)
+	}
+	ta := &api.TargetSetAttempts{Target: name}
+	defer sa.add(ta)
+
+	backoff := cfg.Backoff
+	for {
+		ta.Attempts++
+		connReady := t.ConnState() == connectivity.Ready.String()
+		actx, cancel := setAttemptContext(ctx, cfg.MaxAttempts-ta.Attempts+1)
+		rsp, err := t.Set(actx, req)
+		cancel()
+		if err == nil {
+			return rsp, nil
+		}
+		ta.Errors = append(ta.Errors, err.Error())
+		code := status.Code(err)
+		if code != codes.Unavailable && code != codes.DeadlineExceeded {
+			return nil, err
+		}
+		if code == codes.DeadlineExceeded || connReady {
+			ta.MaybeApplied = true
+		}
+		if ta.Attempts >= cfg.MaxAttempts || ctx.Err() != nil {
+			return nil, fmt.Errorf("%w (%d attempt(s)%s)", err, ta.Attempts, maybeAppliedNote(ta))
+		}
+		if ta.MaybeApplied && !cfg.RetryNonIdempotent && !idempotentSetRequest(req) {
+			return nil, fmt.Errorf("%w (not retried: the request is not idempotent and may have been applied)", err)
+		}
+		a.Logger.Printf("target %q: Set attempt %d failed: %v, retrying in %s", name, ta.Attempts, err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w (%d attempt(s)%s)", err, ta.Attempts, maybeAppliedNote(ta))
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
+
+// setAttemptContext returns the context of a single Set attempt,
+// if ctx has a deadline, the time left is split between the attempts left.
+func setAttemptContext(ctx context.Context, attemptsLeft int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || attemptsLeft <= 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(attemptsLeft))
+}
+
+// idempotentSetRequest returns true if sending the SetRequest req
+// multiple times results in the same target state as sending it once.
+// Requests starting, confirming or canceling a commit are not idempotent.
+func idempotentSetRequest(req *gnmi.SetRequest) bool {
+	for _, ext := range req.GetExtension() {
+		if ext.GetCommit() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func maybeAppliedNote(ta *api.TargetSetAttempts) string {
+	if ta.MaybeApplied {
+		return ", may have been applied"
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// fakeSetClient returns the errors in errs to the successive Set RPCs,
+// then successful responses.
+type fakeSetClient struct {
+	gnmi.GNMIClient
+	errs  []error
+	calls int
+}
+
+func (c *fakeSetClient) Set(ctx context.Context, req *gnmi.SetRequest, opts ...grpc.CallOption) (*gnmi.SetResponse, error) {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		return nil, c.errs[c.calls-1]
+	}
+	return &gnmi.SetResponse{}, nil
+}
+
+func testSetRetryApp(t *testing.T, retry map[string]any) *App {
+	t.Helper()
+	cfg := config.New()
+	cfg.FileConfig.Set("gnmi-server/address", ":0")
+	for k, v := range retry {
+		cfg.FileConfig.Set("gnmi-server/set-retry/"+k, v)
+	}
+	if err := cfg.GetGNMIServer(); err != nil {
+		t.Fatal(err)
+	}
+	return &App{Config: cfg, Logger: log.New(io.Discard, "", 0)}
+}
+
+func TestTargetSet(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	deadline := status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+	commitReq := &gnmi.SetRequest{
+		Extension: []*gnmi_ext.Extension{{Ext: &gnmi_ext.Extension_Commit{Commit: &gnmi_ext.Commit{Id: "c1"}}}},
+	}
+	tests := []struct {
+		name             string
+		retry            map[string]any
+		req              *gnmi.SetRequest
+		errs             []error
+		wantErr          string
+		wantCalls        int
+		wantMaybeApplied bool
+	}{
+		{
+			name:      "no_retry",
+			errs:      []error{unavailable},
+			wantErr:   "connection refused",
+			wantCalls: 1,
+		},
+		{
+			name:      "unavailable_then_success",
+			retry:     map[string]any{"backoff": "1ms"},
+			errs:      []error{unavailable, unavailable},
+			wantCalls: 3,
+		},
+		{
+			name:      "non_transient_error",
+			retry:     map[string]any{"backoff": "1ms"},
+			errs:      []error{status.Error(codes.InvalidArgument, "bad path")},
+			wantErr:   "bad path",
+			wantCalls: 1,
+		},
+		{
+			name:             "max_attempts",
+			retry:            map[string]any{"backoff": "1ms", "max-attempts": 2},
+			errs:             []error{deadline, deadline, deadline},
+			wantErr:          "2 attempt(s), may have been applied",
+			wantCalls:        2,
+			wantMaybeApplied: true,
+		},
+		{
+			name:             "non_idempotent_maybe_applied",
+			retry:            map[string]any{"backoff": "1ms"},
+			req:              commitReq,
+			errs:             []error{deadline},
+			wantErr:          "not retried",
+			wantCalls:        1,
+			wantMaybeApplied: true,
+		},
+		{
+			name:      "non_idempotent_not_applied",
+			retry:     map[string]any{"backoff": "1ms"},
+			req:       commitReq,
+			errs:      []error{unavailable},
+			wantCalls: 2,
+		},
+		{
+			name:             "retry_non_idempotent",
+			retry:            map[string]any{"backoff": "1ms", "retry-non-idempotent": true},
+			req:              commitReq,
+			errs:             []error{deadline},
+			wantCalls:        2,
+			wantMaybeApplied: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := testSetRetryApp(t, tt.retry)
+			if tt.retry == nil {
+				a.Config.GnmiServer.SetRetry = nil
+			}
+			client := &fakeSetClient{errs: tt.errs}
+			tg := target.NewTarget(&types.TargetConfig{Name: "t1"})
+			tg.Client = client
+			req := tt.req
+			if req == nil {
+				req = &gnmi.SetRequest{}
+			}
+			sa := a.newSetAttempts()
+			_, err := a.targetSet(context.Background(), "t1", tg, req, sa)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("expected %d Set RPCs, got %d", tt.wantCalls, client.calls)
+			}
+			if sa == nil {
+				return
+			}
+			rsp := new(gnmi.SetResponse)
+			if err := sa.addExtension(a.Config.GnmiServer.SetRetry.ExtensionID, rsp); err != nil {
+				t.Fatal(err)
+			}
+			got, err := api.SetAttemptsFromExtensions(api.DefaultSetAttemptsExtensionID, rsp.GetExtension())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Targets) != 1 {
+				t.Fatalf("expected 1 target in the Set attempts extension, got %d", len(got.Targets))
+			}
+			ta := got.Targets[0]
+			if ta.Target != "t1" || ta.Attempts != tt.wantCalls || ta.MaybeApplied != tt.wantMaybeApplied {
+				t.Errorf("unexpected target attempts: %+v", ta)
+			}
+		})
+	}
+}
@@ -0,0 +1,641 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+// SetValidationError lists the errors found while validating
+// a SetRequest against the loaded YANG schema.
+type SetValidationError struct {
+	Errors []string
+}
+
+func (e *SetValidationError) Error() string {
+	return fmt.Sprintf("set request validation failed with %d error(s):\n%s",
+		len(e.Errors), strings.Join(e.Errors, "\n"))
+}
+
+var leafrefPredicate = regexp.MustCompile(`\[[^\]]*\]`)
+
+type setValidator struct {
+	root *yang.Entry
+	errs []string
+}
+
+// validateSetRequest validates the paths and values of req against the schema tree root.
+// It checks that the paths exist and are configurable, that the values types,
+// ranges, lengths, patterns and enumerations match the schema, that list entries
+// have their keys and, for replace operations, that the mandatory leaves are present.
+// Leafrefs are validated against the type of the referenced leaf when it can be resolved,
+// the referenced instance existence is not checked.
+// Values using the ASCII, bytes or proto encodings and paths with the `cli` origin are not validated.
+func validateSetRequest(root *yang.Entry, req *gnmi.SetRequest) error {
+	v := &setValidator{root: root}
+	prefix := req.GetPrefix()
+	for _, p := range req.GetDelete() {
+		fp := joinSetPaths(prefix, p)
+		if skipSetValidation(fp, nil) {
+			continue
+		}
+		e, err := v.findEntry(fp)
+		if err != nil {
+			v.addf("delete", fp, "%v", err)
+			continue
+		}
+		if e.ReadOnly() {
+			v.addf("delete", fp, "node is not configurable")
+		}
+	}
+	for _, op := range []struct {
+		name    string
+		updates []*gnmi.Update
+	}{
+		{"replace", req.GetReplace()},
+		{"update", req.GetUpdate()},
+		{"union-replace", req.GetUnionReplace()},
+	} {
+		for _, upd := range op.updates {
+			v.validateUpdate(op.name, joinSetPaths(prefix, upd.GetPath()), upd.GetVal())
+		}
+	}
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &SetValidationError{Errors: v.errs}
+}
+
+func (v *setValidator) addf(op string, p *gnmi.Path, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Sprintf("%s %s: %s", op, setValidationXPath(p), fmt.Sprintf(format, args...)))
+}
+
+func setValidationXPath(p *gnmi.Path) string {
+	xp := "/" + path.GnmiPathToXPath(&gnmi.Path{Elem: p.GetElem()}, false)
+	if p.GetOrigin() != "" {
+		return p.GetOrigin() + ":" + xp
+	}
+	return xp
+}
+
+func joinSetPaths(prefix, p *gnmi.Path) *gnmi.Path {
+	r := &gnmi.Path{
+		Origin: prefix.GetOrigin(),
+		Elem:   make([]*gnmi.PathElem, 0, len(prefix.GetElem())+len(p.GetElem())),
+	}
+	if p.GetOrigin() != "" {
+		r.Origin = p.GetOrigin()
+	}
+	r.Elem = append(r.Elem, prefix.GetElem()...)
+	r.Elem = append(r.Elem, p.GetElem()...)
+	return r
+}
+
+func skipSetValidation(p *gnmi.Path, val *gnmi.TypedValue) bool {
+	if p.GetOrigin() == "cli" {
+		return true
+	}
+	switch val.GetValue().(type) {
+	case *gnmi.TypedValue_AsciiVal, *gnmi.TypedValue_BytesVal,
+		*gnmi.TypedValue_ProtoBytes, *gnmi.TypedValue_AnyVal:
+		return true
+	}
+	return false
+}
+
+// findEntry returns the schema entry of path p,
+// it checks the list keys names and values set in p.
+func (v *setValidator) findEntry(p *gnmi.Path) (*yang.Entry, error) {
+	e := v.root
+	for _, pe := range p.GetElem() {
+		child := findSchemaChild(e, pe.GetName())
+		if child == nil {
+			return nil, fmt.Errorf("unknown element %q under %q", pe.GetName(), schemaPath(e))
+		}
+		e = child
+		if len(pe.GetKey()) == 0 {
+			continue
+		}
+		if !e.IsList() {
+			return nil, fmt.Errorf("element %q is not a list, it cannot have keys", pe.GetName())
+		}
+		keys := strings.Fields(e.Key)
+		for _, k := range sortedMapKeys(pe.GetKey()) {
+			kv := pe.GetKey()[k]
+			kn := stripModulePrefix(k)
+			if !contains(keys, kn) {
+				return nil, fmt.Errorf("list %q has no key %q, its keys are %v", pe.GetName(), k, keys)
+			}
+			if kv == "*" {
+				continue
+			}
+			kl := e.Dir[kn]
+			if kl == nil || kl.Type == nil {
+				continue
+			}
+			if err := validateLeafValue(kl, kl.Type, kv); err != nil {
+				return nil, fmt.Errorf("list %q key %q: %v", pe.GetName(), k, err)
+			}
+		}
+	}
+	return e, nil
+}
+
+func (v *setValidator) validateUpdate(op string, p *gnmi.Path, val *gnmi.TypedValue) {
+	if skipSetValidation(p, val) {
+		return
+	}
+	e, err := v.findEntry(p)
+	if err != nil {
+		v.addf(op, p, "%v", err)
+		return
+	}
+	if e != v.root && e.ReadOnly() {
+		v.addf(op, p, "node is not configurable")
+		return
+	}
+	value, err := decodeTypedValue(val)
+	if err != nil {
+		v.addf(op, p, "%v", err)
+		return
+	}
+	var keysInPath map[string]string
+	if n := len(p.GetElem()); n > 0 {
+		keysInPath = p.GetElem()[n-1].GetKey()
+	}
+	if e.IsList() {
+		switch value := value.(type) {
+		case []any:
+			// a list of entries
+			for i, item := range value {
+				v.validateListEntry(op, p, fmt.Sprintf("[%d]", i), e, item, nil)
+			}
+		default:
+			// a single entry, the keys can be set in the path.
+			v.validateListEntry(op, p, "", e, value, keysInPath)
+		}
+		return
+	}
+	for _, msg := range validateNode(e, value, op == "replace" || op == "union-replace", "") {
+		v.addf(op, p, "%s", msg)
+	}
+}
+
+func (v *setValidator) validateListEntry(op string, p *gnmi.Path, loc string, e *yang.Entry, value any, keysInPath map[string]string) {
+	mandatory := op == "replace" || op == "union-replace"
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.addf(op, p, "%s: expected a list entry object, got %s", locOrValue(loc), jsonType(value))
+		return
+	}
+	for _, msg := range validateListKeys(e, obj, keysInPath, loc) {
+		v.addf(op, p, "%s", msg)
+	}
+	for _, msg := range validateNode(e, obj, mandatory, loc) {
+		v.addf(op, p, "%s", msg)
+	}
+}
+
+// validateNode validates value against the schema entry e,
+// it returns a list of error messages prefixed with their location within value.
+func validateNode(e *yang.Entry, value any, mandatory bool, loc string) []string {
+	switch {
+	case e.IsLeaf():
+		if err := validateLeafValue(e, e.Type, value); err != nil {
+			return []string{fmt.Sprintf("%s: %v", locOrValue(loc), err)}
+		}
+		return nil
+	case e.IsLeafList():
+		items, ok := value.([]any)
+		if !ok {
+			// a single value
+			items = []any{value}
+		}
+		var msgs []string
+		for i, item := range items {
+			if err := validateLeafValue(e, e.Type, item); err != nil {
+				msgs = append(msgs, fmt.Sprintf("%s[%d]: %v", locOrValue(loc), i, err))
+			}
+		}
+		return msgs
+	}
+	// container, list entry or root
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected an object, got %s", locOrValue(loc), jsonType(value))}
+	}
+	var msgs []string
+	for _, k := range sortedMapKeys(obj) {
+		name := stripModulePrefix(k)
+		childLoc := loc + "/" + name
+		child := findSchemaChild(e, name)
+		if child == nil {
+			msgs = append(msgs, fmt.Sprintf("%s: unknown element %q", locOrValue(childLoc), k))
+			continue
+		}
+		if child.ReadOnly() {
+			msgs = append(msgs, fmt.Sprintf("%s: node is not configurable", locOrValue(childLoc)))
+			continue
+		}
+		if !child.IsList() {
+			msgs = append(msgs, validateNode(child, obj[k], mandatory, childLoc)...)
+			continue
+		}
+		items, ok := obj[k].([]any)
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("%s: expected a list of entries, got %s", locOrValue(childLoc), jsonType(obj[k])))
+			continue
+		}
+		for i, item := range items {
+			itemLoc := fmt.Sprintf("%s[%d]", childLoc, i)
+			itemObj, ok := item.(map[string]any)
+			if !ok {
+				msgs = append(msgs, fmt.Sprintf("%s: expected a list entry object, got %s", locOrValue(itemLoc), jsonType(item)))
+				continue
+			}
+			msgs = append(msgs, validateListKeys(child, itemObj, nil, itemLoc)...)
+			msgs = append(msgs, validateNode(child, itemObj, mandatory, itemLoc)...)
+		}
+	}
+	if mandatory {
+		for _, name := range missingMandatory(e, obj) {
+			msgs = append(msgs, fmt.Sprintf("%s: missing mandatory leaf %q", locOrValue(loc), name))
+		}
+	}
+	return msgs
+}
+
+// validateListKeys checks that the list entry obj has all the list keys,
+// either in the object itself or in the path.
+func validateListKeys(e *yang.Entry, obj map[string]any, keysInPath map[string]string, loc string) []string {
+	present := make(map[string]struct{}, len(obj)+len(keysInPath))
+	for k := range obj {
+		present[stripModulePrefix(k)] = struct{}{}
+	}
+	for k := range keysInPath {
+		present[stripModulePrefix(k)] = struct{}{}
+	}
+	var msgs []string
+	for _, k := range strings.Fields(e.Key) {
+		if _, ok := present[k]; !ok {
+			msgs = append(msgs, fmt.Sprintf("%s: list %q entry is missing key %q", locOrValue(loc), e.Name, k))
+		}
+	}
+	return msgs
+}
+
+// missingMandatory returns the names of the mandatory leaves of e missing from obj.
+func missingMandatory(e *yang.Entry, obj map[string]any) []string {
+	present := make(map[string]struct{}, len(obj))
+	for k := range obj {
+		present[stripModulePrefix(k)] = struct{}{}
+	}
+	var missing []string
+	for _, name := range sortedMapKeys(e.Dir) {
+		child := e.Dir[name]
+		if child.IsChoice() || !child.IsLeaf() || child.Mandatory != yang.TSTrue {
+			continue
+		}
+		if _, ok := present[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// validateLeafValue validates a leaf value against the YANG type t.
+// e is the leaf entry, it is used to resolve leafref paths.
+func validateLeafValue(e *yang.Entry, t *yang.YangType, value any) error {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case yang.Ystring:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %s", jsonType(value))
+		}
+		if len(t.Length) > 0 {
+			l := yang.FromInt(int64(utf8.RuneCountInString(s)))
+			if !t.Length.Contains(yang.YangRange{{Min: l, Max: l}}) {
+				return fmt.Errorf("string %q length %d out of the allowed length %s", s, utf8.RuneCountInString(s), t.Length)
+			}
+		}
+		for _, p := range t.Pattern {
+			re, err := regexp.Compile("^(?:" + p + ")$")
+			if err != nil {
+				// XSD patterns that do not compile as Go regular expressions are not checked.
+				continue
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("string %q does not match the pattern %q", s, p)
+			}
+		}
+		for _, p := range t.POSIXPattern {
+			re, err := regexp.CompilePOSIX(p)
+			if err != nil {
+				continue
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("string %q does not match the pattern %q", s, p)
+			}
+		}
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yint64,
+		yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Yuint64:
+		s, ok := numberString(value)
+		if !ok {
+			return fmt.Errorf("expected an integer, got %s", jsonType(value))
+		}
+		n, err := parseYangInt(t.Kind, s)
+		if err != nil {
+			return err
+		}
+		if !t.Range.Contains(yang.YangRange{{Min: n, Max: n}}) {
+			return fmt.Errorf("value %s out of the allowed range %s", s, t.Range)
+		}
+	case yang.Ydecimal64:
+		s, ok := numberString(value)
+		if !ok {
+			return fmt.Errorf("expected a decimal number, got %s", jsonType(value))
+		}
+		n, err := yang.ParseDecimal(s, uint8(t.FractionDigits))
+		if err != nil {
+			return fmt.Errorf("invalid decimal64 value %s: %v", s, err)
+		}
+		if !t.Range.Contains(yang.YangRange{{Min: n, Max: n}}) {
+			return fmt.Errorf("value %s out of the allowed range %s", s, t.Range)
+		}
+	case yang.Ybool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %s", jsonType(value))
+		}
+	case yang.Yenum:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected an enumeration name, got %s", jsonType(value))
+		}
+		if t.Enum != nil && !t.Enum.IsDefined(s) {
+			return fmt.Errorf("invalid value %q, expected one of %v", s, t.Enum.Names())
+		}
+	case yang.Yidentityref:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected an identity name, got %s", jsonType(value))
+		}
+		if t.IdentityBase == nil {
+			return nil
+		}
+		name := stripModulePrefix(s)
+		names := make([]string, 0, len(t.IdentityBase.Values))
+		for _, id := range t.IdentityBase.Values {
+			if id.Name == name {
+				return nil
+			}
+			names = append(names, id.Name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("invalid identity %q, expected one of %v", s, names)
+	case yang.Ybits:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a space separated list of bits, got %s", jsonType(value))
+		}
+		for _, b := range strings.Fields(s) {
+			if t.Bit != nil && !t.Bit.IsDefined(b) {
+				return fmt.Errorf("invalid bit %q, expected any of %v", b, t.Bit.Names())
+			}
+		}
+	case yang.Ybinary:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a base64 encoded string, got %s", jsonType(value))
+		}
+		if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			return fmt.Errorf("invalid base64 value: %v", err)
+		}
+	case yang.Yempty:
+		switch value := value.(type) {
+		case nil:
+		case []any:
+			if len(value) != 1 || value[0] != nil {
+				return fmt.Errorf("expected [null] for an empty leaf")
+			}
+		default:
+			return fmt.Errorf("expected [null] for an empty leaf, got %s", jsonType(value))
+		}
+	case yang.Yunion:
+		for _, ut := range t.Type {
+			if validateLeafValue(e, ut, value) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %v does not match any of the union types", value)
+	case yang.Yleafref:
+		target := e.Find(leafrefPredicate.ReplaceAllString(t.Path, ""))
+		if target == nil || target == e || target.Type == nil {
+			// the referenced leaf cannot be resolved.
+			return nil
+		}
+		return validateLeafValue(target, target.Type, value)
+	}
+	return nil
+}
+
+func parseYangInt(kind yang.TypeKind, s string) (yang.Number, error) {
+	var bits int
+	var signed bool
+	switch kind {
+	case yang.Yint8:
+		bits, signed = 8, true
+	case yang.Yint16:
+		bits, signed = 16, true
+	case yang.Yint32:
+		bits, signed = 32, true
+	case yang.Yint64:
+		bits, signed = 64, true
+	case yang.Yuint8:
+		bits = 8
+	case yang.Yuint16:
+		bits = 16
+	case yang.Yuint32:
+		bits = 32
+	case yang.Yuint64:
+		bits = 64
+	}
+	if signed {
+		i, err := strconv.ParseInt(s, 10, bits)
+		if err != nil {
+			return yang.Number{}, fmt.Errorf("invalid %s value %s", yang.TypeKindToName[kind], s)
+		}
+		return yang.FromInt(i), nil
+	}
+	u, err := strconv.ParseUint(s, 10, bits)
+	if err != nil {
+		return yang.Number{}, fmt.Errorf("invalid %s value %s", yang.TypeKindToName[kind], s)
+	}
+	return yang.FromUint(u), nil
+}
+
+// numberString returns the string representation of a JSON number,
+// numbers encoded as strings (e.g 64 bit integers in JSON_IETF) are accepted.
+func numberString(value any) (string, bool) {
+	switch value := value.(type) {
+	case json.Number:
+		return value.String(), true
+	case string:
+		return value, true
+	}
+	return "", false
+}
+
+// decodeTypedValue converts a TypedValue to the value
+// it would be decoded as from a JSON document.
+func decodeTypedValue(val *gnmi.TypedValue) (any, error) {
+	switch v := val.GetValue().(type) {
+	case *gnmi.TypedValue_JsonVal:
+		return decodeJSONValue(v.JsonVal)
+	case *gnmi.TypedValue_JsonIetfVal:
+		return decodeJSONValue(v.JsonIetfVal)
+	case *gnmi.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmi.TypedValue_IntVal:
+		return json.Number(strconv.FormatInt(v.IntVal, 10)), nil
+	case *gnmi.TypedValue_UintVal:
+		return json.Number(strconv.FormatUint(v.UintVal, 10)), nil
+	case *gnmi.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmi.TypedValue_FloatVal:
+		return json.Number(strconv.FormatFloat(float64(v.FloatVal), 'f', -1, 32)), nil
+	case *gnmi.TypedValue_DoubleVal:
+		return json.Number(strconv.FormatFloat(v.DoubleVal, 'f', -1, 64)), nil
+	case *gnmi.TypedValue_LeaflistVal:
+		items := make([]any, 0, len(v.LeaflistVal.GetElement()))
+		for _, el := range v.LeaflistVal.GetElement() {
+			item, err := decodeTypedValue(el)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", val.GetValue())
+}
+
+func decodeJSONValue(b []byte) (any, error) {
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %v", err)
+	}
+	return value, nil
+}
+
+// findSchemaChild returns the child of e named name,
+// looking through the choice and case nodes.
+func findSchemaChild(e *yang.Entry, name string) *yang.Entry {
+	name = stripModulePrefix(name)
+	// the root entry children are the modules.
+	if isRootEntry(e) {
+		for _, m := range sortedMapKeys(e.Dir) {
+			if c := findSchemaChild(e.Dir[m], name); c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+	if c, ok := e.Dir[name]; ok && !c.IsChoice() && !c.IsCase() {
+		return c
+	}
+	for _, c := range e.Dir {
+		if !c.IsChoice() && !c.IsCase() {
+			continue
+		}
+		if r := findSchemaChild(c, name); r != nil {
+			return r
+		}
+	}
+	return nil
+}
+
+func isRootEntry(e *yang.Entry) bool {
+	root, _ := e.Annotation["root"].(bool)
+	return root
+}
+
+func schemaPath(e *yang.Entry) string {
+	if isRootEntry(e) {
+		return "/"
+	}
+	return e.Path()
+}
+
+func stripModulePrefix(name string) string {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func locOrValue(loc string) string {
+	if loc == "" {
+		return "value"
+	}
+	return "value" + loc
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,264 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+const testSetValidateModule = `
+module test {
+  namespace "urn:test";
+  prefix t;
+
+  identity proto-base;
+  identity bgp { base proto-base; }
+  identity ospf { base proto-base; }
+
+  container system {
+    leaf hostname {
+      type string {
+        length "1..16";
+        pattern "[a-z][a-z0-9-]*";
+      }
+    }
+    leaf mtu {
+      type uint16 { range "68..9000"; }
+    }
+    leaf enabled { type boolean; }
+    leaf ratio { type decimal64 { fraction-digits 2; range "0..1"; } }
+    leaf uptime { type uint64; config false; }
+  }
+  container interfaces {
+    list interface {
+      key "name";
+      leaf name { type string; }
+      leaf description { type string; }
+      leaf admin-state {
+        type enumeration { enum enable; enum disable; }
+      }
+      leaf vlan {
+        type union { type uint16 { range "1..4094"; } type enumeration { enum any; } }
+      }
+      leaf protocol { type identityref { base proto-base; } }
+      leaf type { type string; mandatory true; }
+      leaf-list tags { type string { length "1..4"; } }
+    }
+  }
+  container routing {
+    leaf interface {
+      type leafref { path "/interfaces/interface/name"; }
+    }
+    leaf router-id {
+      type leafref { path "/system/mtu"; }
+    }
+  }
+}
+`
+
+func testSetValidateSchema(t *testing.T) *yang.Entry {
+	t.Helper()
+	ms := yang.NewModules()
+	if err := ms.Parse(testSetValidateModule, "test.yang"); err != nil {
+		t.Fatalf("failed to parse test module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("failed to process test module: %v", errs)
+	}
+	root := buildRootEntry()
+	e := yang.ToEntry(ms.Modules["test"])
+	root.Dir[e.Name] = e
+	return root
+}
+
+func testSetUpdate(t *testing.T, p string, val *gnmi.TypedValue) *gnmi.Update {
+	t.Helper()
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &gnmi.Update{Path: gp, Val: val}
+}
+
+func jsonVal(s string) *gnmi.TypedValue {
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(s)}}
+}
+
+func TestValidateSetRequest(t *testing.T) {
+	root := testSetValidateSchema(t)
+	tests := []struct {
+		name    string
+		replace map[string]*gnmi.TypedValue
+		update  map[string]*gnmi.TypedValue
+		delete  []string
+		errs    int
+	}{
+		{
+			name: "valid_container_update",
+			update: map[string]*gnmi.TypedValue{
+				"/system": jsonVal(`{"hostname":"leaf1","mtu":1500,"enabled":true,"ratio":"0.5"}`),
+			},
+		},
+		{
+			name: "valid_leaf_updates",
+			update: map[string]*gnmi.TypedValue{
+				"/system/mtu":      {Value: &gnmi.TypedValue_UintVal{UintVal: 9000}},
+				"/system/hostname": {Value: &gnmi.TypedValue_StringVal{StringVal: "spine-1"}},
+			},
+		},
+		{
+			name: "valid_list_entry_replace",
+			replace: map[string]*gnmi.TypedValue{
+				"/interfaces/interface[name=e1]": jsonVal(`{"type":"eth","admin-state":"enable","vlan":"any","protocol":"test:bgp","tags":["a","b"]}`),
+			},
+		},
+		{
+			name: "valid_list_replace",
+			replace: map[string]*gnmi.TypedValue{
+				"/interfaces": jsonVal(`{"interface":[{"name":"e1","type":"eth","vlan":10},{"name":"e2","type":"eth"}]}`),
+			},
+		},
+		{
+			name: "valid_leafref",
+			update: map[string]*gnmi.TypedValue{
+				"/routing": jsonVal(`{"interface":"e1","router-id":1500}`),
+			},
+		},
+		{
+			name:   "valid_delete",
+			delete: []string{"/interfaces/interface[name=e1]/description"},
+		},
+		{
+			name: "unknown_path",
+			update: map[string]*gnmi.TypedValue{
+				"/system/foo": {Value: &gnmi.TypedValue_StringVal{StringVal: "bar"}},
+			},
+			errs: 1,
+		},
+		{
+			name:   "unknown_delete_path",
+			delete: []string{"/interfaces/interface[name=e1]/foo"},
+			errs:   1,
+		},
+		{
+			name: "unknown_list_key",
+			update: map[string]*gnmi.TypedValue{
+				"/interfaces/interface[id=e1]/description": {Value: &gnmi.TypedValue_StringVal{StringVal: "foo"}},
+			},
+			errs: 1,
+		},
+		{
+			name: "read_only",
+			update: map[string]*gnmi.TypedValue{
+				"/system": jsonVal(`{"uptime":10}`),
+			},
+			errs: 1,
+		},
+		{
+			name: "bad_leaf_values",
+			update: map[string]*gnmi.TypedValue{
+				"/system": jsonVal(`{"hostname":"Leaf1","mtu":10,"enabled":"yes","ratio":"1.5"}`),
+			},
+			errs: 4,
+		},
+		{
+			name: "string_length",
+			update: map[string]*gnmi.TypedValue{
+				"/system/hostname": {Value: &gnmi.TypedValue_StringVal{StringVal: "a-very-long-hostname"}},
+			},
+			errs: 1,
+		},
+		{
+			name: "integer_overflow",
+			update: map[string]*gnmi.TypedValue{
+				"/system/mtu": {Value: &gnmi.TypedValue_IntVal{IntVal: 70000}},
+			},
+			errs: 1,
+		},
+		{
+			name: "bad_enum_identity_union",
+			update: map[string]*gnmi.TypedValue{
+				"/interfaces/interface[name=e1]": jsonVal(`{"admin-state":"up","protocol":"isis","vlan":5000}`),
+			},
+			errs: 3,
+		},
+		{
+			name: "missing_mandatory",
+			replace: map[string]*gnmi.TypedValue{
+				"/interfaces/interface[name=e1]": jsonVal(`{"description":"foo"}`),
+			},
+			errs: 1,
+		},
+		{
+			name: "missing_list_key",
+			update: map[string]*gnmi.TypedValue{
+				"/interfaces": jsonVal(`{"interface":[{"description":"foo"}]}`),
+			},
+			errs: 1,
+		},
+		{
+			name: "bad_leaf_list_item",
+			update: map[string]*gnmi.TypedValue{
+				"/interfaces/interface[name=e1]/tags": jsonVal(`["a","toolong"]`),
+			},
+			errs: 1,
+		},
+		{
+			name: "bad_leafref_type",
+			update: map[string]*gnmi.TypedValue{
+				"/routing/router-id": jsonVal(`"abc"`),
+			},
+			errs: 1,
+		},
+		{
+			name: "cli_origin_skipped",
+			update: map[string]*gnmi.TypedValue{
+				"cli:/show version": {Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "foo"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &gnmi.SetRequest{}
+			for p, v := range tt.replace {
+				req.Replace = append(req.Replace, testSetUpdate(t, p, v))
+			}
+			for p, v := range tt.update {
+				req.Update = append(req.Update, testSetUpdate(t, p, v))
+			}
+			for _, p := range tt.delete {
+				gp, err := path.ParsePath(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Delete = append(req.Delete, gp)
+			}
+			err := validateSetRequest(root, req)
+			if tt.errs == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			verr := new(SetValidationError)
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a SetValidationError, got %v", err)
+			}
+			if len(verr.Errors) != tt.errs {
+				t.Errorf("expected %d errors, got %d: %v", tt.errs, len(verr.Errors), verr)
+			}
+		})
+	}
+}
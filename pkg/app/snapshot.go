@@ -0,0 +1,359 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/grpctunnel/tunnel"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/config"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	snapshotManifestFileName = "manifest.json"
+	snapshotStatusCompleted  = "completed"
+	snapshotStatusFailed     = "failed"
+)
+
+type snapshotManifest struct {
+	Paths         []string                         `json:"paths,omitempty"`
+	Prefix        string                           `json:"prefix,omitempty"`
+	Subscriptions []string                         `json:"subscriptions,omitempty"`
+	FileFormat    string                           `json:"file-format,omitempty"`
+	Started       time.Time                        `json:"started,omitempty"`
+	Updated       time.Time                        `json:"updated,omitempty"`
+	Targets       map[string]*snapshotTargetStatus `json:"targets,omitempty"`
+}
+
+type snapshotTargetStatus struct {
+	Status    string    `json:"status,omitempty"`
+	File      string    `json:"file,omitempty"`
+	Size      int       `json:"size,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func (a *App) SnapshotPreRunE(cmd *cobra.Command, args []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	a.Config.LocalFlags.SnapshotPath = config.SanitizeArrayFlagValue(a.Config.LocalFlags.SnapshotPath)
+	a.Config.LocalFlags.SnapshotSubscription = config.SanitizeArrayFlagValue(a.Config.LocalFlags.SnapshotSubscription)
+
+	numPaths := len(a.Config.LocalFlags.SnapshotPath)
+	numSubs := len(a.Config.LocalFlags.SnapshotSubscription)
+	if numPaths == 0 && numSubs == 0 {
+		return errors.New("one of flags --path or --subscription must be set")
+	}
+	if numPaths > 0 && numSubs > 0 {
+		return errors.New("flags --path and --subscription cannot be mixed")
+	}
+	switch a.Config.LocalFlags.SnapshotFileFormat {
+	case "json", "protojson", "prototext", "proto":
+	default:
+		return fmt.Errorf("unsupported snapshot file format %q", a.Config.LocalFlags.SnapshotFileFormat)
+	}
+
+	a.createCollectorDialOpts()
+	return a.initTunnelServer(tunnel.ServerConfig{
+		AddTargetHandler:    a.tunServerAddTargetHandler,
+		DeleteTargetHandler: a.tunServerDeleteTargetHandler,
+		RegisterHandler:     a.tunServerRegisterHandler,
+		Handler:             a.tunServerHandler,
+	})
+}
+
+func (a *App) SnapshotRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitSnapshotFlags(cmd)
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	targetsConfig, err := a.GetTargets()
+	if err != nil {
+		return fmt.Errorf("failed getting targets config: %v", err)
+	}
+	var subs []*types.SubscriptionConfig
+	if len(a.Config.LocalFlags.SnapshotSubscription) > 0 {
+		subs, err = a.snapshotSubscriptions()
+		if err != nil {
+			return err
+		}
+	}
+	err = os.MkdirAll(a.Config.LocalFlags.SnapshotDir, 0755)
+	if err != nil {
+		return err
+	}
+	manifest, err := a.loadSnapshotManifest()
+	if err != nil {
+		return err
+	}
+	mu := new(sync.Mutex)
+
+	a.errCh = make(chan error, 2*len(targetsConfig))
+	for _, tc := range targetsConfig {
+		if st, ok := manifest.Targets[tc.Name]; ok && st.Status == snapshotStatusCompleted {
+			a.Logger.Printf("target %q: snapshot already completed, skipping", tc.Name)
+			continue
+		}
+		a.wg.Add(1)
+		go func(tc *types.TargetConfig) {
+			defer a.wg.Done()
+			st := a.snapshotTarget(ctx, tc, subs)
+			mu.Lock()
+			manifest.Targets[tc.Name] = st
+			err := a.writeSnapshotManifest(manifest)
+			mu.Unlock()
+			// errors are logged after releasing the lock,
+			// each target sends at most 2 errors to the errors channel.
+			if err != nil {
+				a.logError(fmt.Errorf("failed to write snapshot manifest: %v", err))
+			}
+			if st.Status == snapshotStatusFailed {
+				a.logError(fmt.Errorf("target %q snapshot failed: %s", tc.Name, st.Error))
+			}
+		}(tc)
+	}
+	a.wg.Wait()
+	return a.checkErrors()
+}
+
+// snapshotSubscriptions returns the configured subscriptions
+// referenced by the --subscription flag.
+func (a *App) snapshotSubscriptions() ([]*types.SubscriptionConfig, error) {
+	allSubs, err := a.Config.GetSubscriptions(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading subscriptions config: %v", err)
+	}
+	subs := make([]*types.SubscriptionConfig, 0, len(a.Config.LocalFlags.SnapshotSubscription))
+	for _, name := range a.Config.LocalFlags.SnapshotSubscription {
+		sc, ok := allSubs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown subscription %q", name)
+		}
+		subs = append(subs, sc)
+	}
+	return subs, nil
+}
+
+// loadSnapshotManifest reads the manifest from the snapshot directory
+// if the --resume flag is set, or returns a new one.
+func (a *App) loadSnapshotManifest() (*snapshotManifest, error) {
+	m := &snapshotManifest{
+		Paths:         a.Config.LocalFlags.SnapshotPath,
+		Prefix:        a.Config.LocalFlags.SnapshotPrefix,
+		Subscriptions: a.Config.LocalFlags.SnapshotSubscription,
+		FileFormat:    a.Config.LocalFlags.SnapshotFileFormat,
+		Started:       time.Now(),
+		Targets:       make(map[string]*snapshotTargetStatus),
+	}
+	if !a.Config.LocalFlags.SnapshotResume {
+		return m, nil
+	}
+	b, err := os.ReadFile(filepath.Join(a.Config.LocalFlags.SnapshotDir, snapshotManifestFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return nil, err
+	}
+	pm := new(snapshotManifest)
+	err = json.Unmarshal(b, pm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %v", err)
+	}
+	if strings.Join(pm.Paths, ",") != strings.Join(m.Paths, ",") ||
+		pm.Prefix != m.Prefix ||
+		strings.Join(pm.Subscriptions, ",") != strings.Join(m.Subscriptions, ",") ||
+		pm.FileFormat != m.FileFormat {
+		return nil, errors.New("cannot resume: the existing snapshot manifest was created with different parameters")
+	}
+	if pm.Targets == nil {
+		pm.Targets = make(map[string]*snapshotTargetStatus)
+	}
+	return pm, nil
+}
+
+func (a *App) writeSnapshotManifest(m *snapshotManifest) error {
+	m.Updated = time.Now()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(a.Config.LocalFlags.SnapshotDir, snapshotManifestFileName), b)
+}
+
+func (a *App) snapshotTarget(ctx context.Context, tc *types.TargetConfig, subs []*types.SubscriptionConfig) *snapshotTargetStatus {
+	st := &snapshotTargetStatus{Timestamp: time.Now()}
+	var rsp *gnmi.GetResponse
+	var err error
+	if len(subs) > 0 {
+		rsp, err = a.snapshotTargetOnce(ctx, tc, subs)
+	} else {
+		rsp, err = a.snapshotTargetGet(ctx, tc)
+	}
+	a.closeSnapshotTarget(tc.Name)
+	if err != nil {
+		st.Status = snapshotStatusFailed
+		st.Error = err.Error()
+		return st
+	}
+	mo := &formatters.MarshalOptions{
+		Multiline: true,
+		Indent:    "  ",
+		Format:    a.Config.LocalFlags.SnapshotFileFormat,
+	}
+	b, err := mo.Marshal(rsp, map[string]string{"source": tc.Name})
+	if err != nil {
+		st.Status = snapshotStatusFailed
+		st.Error = err.Error()
+		return st
+	}
+	st.File = snapshotFileName(tc.Name, a.Config.LocalFlags.SnapshotFileFormat)
+	err = writeFileAtomic(filepath.Join(a.Config.LocalFlags.SnapshotDir, st.File), b)
+	if err != nil {
+		st.Status = snapshotStatusFailed
+		st.Error = err.Error()
+		return st
+	}
+	st.Size = len(b)
+	st.Status = snapshotStatusCompleted
+	a.Logger.Printf("target %q: snapshot written to %q", tc.Name, st.File)
+	return st
+}
+
+func (a *App) snapshotTargetGet(ctx context.Context, tc *types.TargetConfig) (*gnmi.GetResponse, error) {
+	enc := a.Config.Encoding
+	if tc.Encoding != nil {
+		enc = *tc.Encoding
+	}
+	gnmiOpts := []api.GNMIOption{
+		api.Encoding(enc),
+		api.DataType(a.Config.LocalFlags.SnapshotType),
+		api.Prefix(a.Config.LocalFlags.SnapshotPrefix),
+	}
+	for _, p := range a.Config.LocalFlags.SnapshotPath {
+		gnmiOpts = append(gnmiOpts, api.Path(strings.TrimSpace(p)))
+	}
+	req, err := api.NewGetRequest(gnmiOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return a.ClientGet(ctx, tc, req)
+}
+
+// snapshotTargetOnce runs the subscriptions subs in ONCE mode and
+// collects the received notifications in a single GetResponse.
+// All the subscriptions must complete within the target timeout.
+func (a *App) snapshotTargetOnce(ctx context.Context, tc *types.TargetConfig, subs []*types.SubscriptionConfig) (*gnmi.GetResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, tc.Timeout)
+	defer cancel()
+	a.operLock.Lock()
+	t, err := a.initTarget(tc)
+	a.operLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	a.operLock.RLock()
+	err = a.CreateGNMIClient(ctx, t)
+	a.operLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	rsp := new(gnmi.GetResponse)
+	for _, sc := range subs {
+		osc := *sc
+		osc.Mode = subscriptionModeONCE
+		req, err := a.Config.CreateSubscribeRequest(&osc, tc)
+		if err != nil {
+			return nil, err
+		}
+		rsps, err := t.SubscribeOnce(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("subscription %q failed: %v", sc.Name, err)
+		}
+		for _, r := range rsps {
+			if n := r.GetUpdate(); n != nil {
+				rsp.Notification = append(rsp.Notification, n)
+			}
+		}
+	}
+	sort.SliceStable(rsp.Notification, func(i, j int) bool {
+		return rsp.Notification[i].GetTimestamp() < rsp.Notification[j].GetTimestamp()
+	})
+	return rsp, nil
+}
+
+// closeSnapshotTarget closes the gNMI client of the target once its snapshot is taken.
+func (a *App) closeSnapshotTarget(name string) {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	if t, ok := a.Targets[name]; ok {
+		t.Close()
+		delete(a.Targets, name)
+	}
+}
+
+// snapshotFileName returns the snapshot file name of the target name.
+// The target name is query escaped, so that distinct target names (e.g: `a:1` and `a/1`)
+// map to distinct valid file names.
+func snapshotFileName(name, format string) string {
+	name = url.QueryEscape(name)
+	switch format {
+	case "proto":
+		return name + ".pb"
+	case "prototext":
+		return name + ".txt"
+	default:
+		return name + ".json"
+	}
+}
+
+// writeFileAtomic writes b to a temporary file then renames it to name,
+// so that an interrupted write does not leave a partial file behind.
+func writeFileAtomic(name string, b []byte) error {
+	tmp := name + ".tmp"
+	err := os.WriteFile(tmp, b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// InitSnapshotFlags used to init or reset snapshotCmd flags for gnmic-prompt mode
+func (a *App) InitSnapshotFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringArrayVarP(&a.Config.LocalFlags.SnapshotPath, "path", "", []string{}, "get request paths")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotPrefix, "prefix", "", "", "get request prefix")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotType, "type", "t", "ALL", "data type requested from the target. one of: ALL, CONFIG, STATE, OPERATIONAL")
+	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.SnapshotSubscription, "subscription", "", []string{}, "names of configured subscriptions to run in ONCE mode, instead of a Get request")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotDir, "dir", "", "snapshot", "directory where the snapshot files and manifest are written")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotFileFormat, "file-format", "", "json", "snapshot files format. one of: json, protojson, prototext, proto")
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.SnapshotResume, "resume", "", false, "resume an interrupted snapshot, skipping the targets marked as completed in the existing manifest")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
+	})
+}
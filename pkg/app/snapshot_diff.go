@@ -0,0 +1,308 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	snapshotChangeAdded   = "added"
+	snapshotChangeRemoved = "removed"
+	snapshotChangeChanged = "changed"
+
+	snapshotDiffEventName = "snapshot-diff"
+	// maximum time to wait for the outputs to deliver the change events
+	snapshotDiffFlushTimeout = 30 * time.Second
+)
+
+type snapshotDiffReport struct {
+	Ref     string                         `json:"ref,omitempty"`
+	Compare string                         `json:"compare,omitempty"`
+	Targets map[string][]*snapshotLeafDiff `json:"targets,omitempty"`
+}
+
+type snapshotLeafDiff struct {
+	Change string      `json:"change,omitempty"`
+	Path   string      `json:"path,omitempty"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// InitSnapshotDiffFlags used to init or reset snapshotDiffCmd flags for gnmic-prompt mode
+func (a *App) InitSnapshotDiffFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotDiffRef, "ref", "", "", "reference snapshot directory")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotDiffCompare, "compare", "", "", "snapshot directory to compare against the reference")
+	cmd.MarkFlagRequired("ref")
+	cmd.MarkFlagRequired("compare")
+	cmd.Flags().StringArrayVarP(&a.Config.LocalFlags.SnapshotDiffPath, "path", "", []string{}, "regular expressions used to filter the compared paths")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.SnapshotDiffReportFormat, "report-format", "", "text", "change report format, one of: text, json")
+	cmd.Flags().StringSliceVarP(&a.Config.LocalFlags.SnapshotDiffOutput, "output", "", []string{}, "names of configured outputs the changes are written to as events")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "snapshot-diff", flag.Name), flag)
+	})
+}
+
+func (a *App) SnapshotDiffRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitSnapshotDiffFlags(cmd)
+
+	switch a.Config.LocalFlags.SnapshotDiffReportFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unsupported report format %q", a.Config.LocalFlags.SnapshotDiffReportFormat)
+	}
+	filters := make([]*regexp.Regexp, 0, len(a.Config.LocalFlags.SnapshotDiffPath))
+	for _, p := range a.Config.LocalFlags.SnapshotDiffPath {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid path filter %q: %v", p, err)
+		}
+		filters = append(filters, re)
+	}
+	refLeaves, err := readSnapshotLeaves(a.Config.LocalFlags.SnapshotDiffRef)
+	if err != nil {
+		return err
+	}
+	cmpLeaves, err := readSnapshotLeaves(a.Config.LocalFlags.SnapshotDiffCompare)
+	if err != nil {
+		return err
+	}
+	report := &snapshotDiffReport{
+		Ref:     a.Config.LocalFlags.SnapshotDiffRef,
+		Compare: a.Config.LocalFlags.SnapshotDiffCompare,
+		Targets: make(map[string][]*snapshotLeafDiff),
+	}
+	for name := range refLeaves {
+		if _, ok := cmpLeaves[name]; !ok {
+			cmpLeaves[name] = map[string]interface{}{}
+		}
+	}
+	for name, cl := range cmpLeaves {
+		rl, ok := refLeaves[name]
+		if !ok {
+			rl = map[string]interface{}{}
+		}
+		d := diffSnapshotLeaves(rl, cl, filters)
+		if len(d) > 0 {
+			report.Targets[name] = d
+		}
+	}
+	err = a.printSnapshotDiffReport(report)
+	if err != nil {
+		return err
+	}
+	if len(a.Config.LocalFlags.SnapshotDiffOutput) > 0 {
+		return a.writeSnapshotDiffEvents(cmd.Context(), report)
+	}
+	return nil
+}
+
+// readSnapshotLeaves reads the completed target snapshots found
+// in dir and returns their flattened leaves, per target.
+func readSnapshotLeaves(dir string) (map[string]map[string]interface{}, error) {
+	b, err := os.ReadFile(filepath.Join(dir, snapshotManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %v", err)
+	}
+	m := new(snapshotManifest)
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest %q: %v", dir, err)
+	}
+	leaves := make(map[string]map[string]interface{}, len(m.Targets))
+	for name, st := range m.Targets {
+		if st.Status != snapshotStatusCompleted {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, st.File))
+		if err != nil {
+			return nil, err
+		}
+		leaves[name], err = snapshotFileLeaves(b, m.FileFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot file %q: %v", st.File, err)
+		}
+	}
+	return leaves, nil
+}
+
+func snapshotFileLeaves(b []byte, format string) (map[string]interface{}, error) {
+	rsp, err := formatters.DecodeGetResponse(b, format)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := formatters.ResponsesFlat(rsp)
+	if err != nil {
+		return nil, err
+	}
+	// the flattened paths are relative, make them absolute.
+	rs := make(map[string]interface{}, len(leaves))
+	for p, v := range leaves {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		rs[p] = v
+	}
+	return rs, nil
+}
+
+func diffSnapshotLeaves(ref, cmp map[string]interface{}, filters []*regexp.Regexp) []*snapshotLeafDiff {
+	ds := make([]*snapshotLeafDiff, 0)
+	for p, v := range ref {
+		if !matchAnyRegex(p, filters) {
+			continue
+		}
+		nv, ok := cmp[p]
+		if !ok {
+			ds = append(ds, &snapshotLeafDiff{Change: snapshotChangeRemoved, Path: p, Old: v})
+			continue
+		}
+		if !reflect.DeepEqual(v, nv) {
+			ds = append(ds, &snapshotLeafDiff{Change: snapshotChangeChanged, Path: p, Old: v, New: nv})
+		}
+	}
+	for p, v := range cmp {
+		if !matchAnyRegex(p, filters) {
+			continue
+		}
+		if _, ok := ref[p]; !ok {
+			ds = append(ds, &snapshotLeafDiff{Change: snapshotChangeAdded, Path: p, New: v})
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].Path < ds[j].Path
+	})
+	return ds
+}
+
+func matchAnyRegex(s string, res []*regexp.Regexp) bool {
+	if len(res) == 0 {
+		return true
+	}
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) printSnapshotDiffReport(r *snapshotDiffReport) error {
+	if a.Config.LocalFlags.SnapshotDiffReportFormat == "json" {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.out, string(b))
+		return nil
+	}
+	names := make([]string, 0, len(r.Targets))
+	for name := range r.Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sb := new(strings.Builder)
+	for _, name := range names {
+		fmt.Fprintf(sb, "[%s]\n", name)
+		for _, d := range r.Targets[name] {
+			switch d.Change {
+			case snapshotChangeAdded:
+				fmt.Fprintf(sb, "+\t%s: %v\n", d.Path, d.New)
+			case snapshotChangeRemoved:
+				fmt.Fprintf(sb, "-\t%s: %v\n", d.Path, d.Old)
+			case snapshotChangeChanged:
+				fmt.Fprintf(sb, "~\t%s: %v -> %v\n", d.Path, d.Old, d.New)
+			}
+		}
+	}
+	fmt.Fprint(a.out, sb.String())
+	return nil
+}
+
+// writeSnapshotDiffEvents writes each change in the report as an
+// event message to the outputs referenced by the --output flag.
+func (a *App) writeSnapshotDiffEvents(ctx context.Context, r *snapshotDiffReport) error {
+	_, err := a.Config.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("failed getting outputs config: %v", err)
+	}
+	for _, name := range a.Config.LocalFlags.SnapshotDiffOutput {
+		if _, ok := a.Config.Outputs[name]; !ok {
+			return fmt.Errorf("unknown output %q", name)
+		}
+		a.InitOutput(ctx, name, nil)
+	}
+	defer func() {
+		for _, name := range a.Config.LocalFlags.SnapshotDiffOutput {
+			a.DeleteOutput(name)
+		}
+	}()
+	now := time.Now().UnixNano()
+	for name, ds := range r.Targets {
+		for _, d := range ds {
+			ev := &formatters.EventMsg{
+				Name:      snapshotDiffEventName,
+				Timestamp: now,
+				Tags: map[string]string{
+					"source": name,
+					"path":   d.Path,
+					"change": d.Change,
+				},
+				Values: make(map[string]interface{}),
+			}
+			if d.Old != nil {
+				ev.Values["old"] = d.Old
+			}
+			if d.New != nil {
+				ev.Values["new"] = d.New
+			}
+			a.operLock.RLock()
+			for _, oName := range a.Config.LocalFlags.SnapshotDiffOutput {
+				if o, ok := a.Outputs[oName]; ok {
+					o.WriteEvent(ctx, ev)
+				}
+			}
+			a.operLock.RUnlock()
+		}
+	}
+	// wait for the asynchronous outputs to deliver the events
+	// before the deferred DeleteOutput closes them.
+	fctx, cancel := context.WithTimeout(ctx, snapshotDiffFlushTimeout)
+	defer cancel()
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	for _, oName := range a.Config.LocalFlags.SnapshotDiffOutput {
+		o, ok := a.Outputs[oName]
+		if !ok {
+			continue
+		}
+		if err := outputs.Flush(fctx, o); err != nil {
+			a.Logger.Printf("failed to flush output %q: %v", oName, err)
+		}
+	}
+	return nil
+}
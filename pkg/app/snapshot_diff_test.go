@@ -0,0 +1,162 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestDiffSnapshotLeaves(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     map[string]interface{}
+		cmp     map[string]interface{}
+		filters []string
+		want    []*snapshotLeafDiff
+	}{
+		{
+			name: "equal",
+			ref:  map[string]interface{}{"/a": "x", "/b": float64(1)},
+			cmp:  map[string]interface{}{"/a": "x", "/b": float64(1)},
+			want: []*snapshotLeafDiff{},
+		},
+		{
+			name: "added_removed_changed",
+			ref:  map[string]interface{}{"/a": "x", "/b": float64(1), "/c": true},
+			cmp:  map[string]interface{}{"/a": "y", "/c": true, "/d": "new"},
+			want: []*snapshotLeafDiff{
+				{Change: snapshotChangeChanged, Path: "/a", Old: "x", New: "y"},
+				{Change: snapshotChangeRemoved, Path: "/b", Old: float64(1)},
+				{Change: snapshotChangeAdded, Path: "/d", New: "new"},
+			},
+		},
+		{
+			name: "empty_ref",
+			ref:  map[string]interface{}{},
+			cmp:  map[string]interface{}{"/b": "2", "/a": "1"},
+			want: []*snapshotLeafDiff{
+				{Change: snapshotChangeAdded, Path: "/a", New: "1"},
+				{Change: snapshotChangeAdded, Path: "/b", New: "2"},
+			},
+		},
+		{
+			name: "list_values",
+			ref:  map[string]interface{}{"/a": []interface{}{"x", "y"}},
+			cmp:  map[string]interface{}{"/a": []interface{}{"x", "z"}},
+			want: []*snapshotLeafDiff{
+				{Change: snapshotChangeChanged, Path: "/a", Old: []interface{}{"x", "y"}, New: []interface{}{"x", "z"}},
+			},
+		},
+		{
+			name: "filtered",
+			ref: map[string]interface{}{
+				"/interfaces/interface[name=e1]/state/oper-status": "UP",
+				"/system/state/hostname":                           "r1",
+			},
+			cmp: map[string]interface{}{
+				"/interfaces/interface[name=e1]/state/oper-status": "DOWN",
+				"/system/state/hostname":                           "r2",
+			},
+			filters: []string{"^/interfaces/"},
+			want: []*snapshotLeafDiff{
+				{
+					Change: snapshotChangeChanged,
+					Path:   "/interfaces/interface[name=e1]/state/oper-status",
+					Old:    "UP",
+					New:    "DOWN",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := make([]*regexp.Regexp, 0, len(tt.filters))
+			for _, f := range tt.filters {
+				filters = append(filters, regexp.MustCompile(f))
+			}
+			got := diffSnapshotLeaves(tt.ref, tt.cmp, filters)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got:")
+				for _, d := range got {
+					t.Errorf("  %+v", d)
+				}
+				t.Errorf("expected:")
+				for _, d := range tt.want {
+					t.Errorf("  %+v", d)
+				}
+			}
+		})
+	}
+}
+
+// TestJSONSnapshotLeaves checks that a snapshot written in json format
+// is flattened into the same keyed leaves as the protobuf based formats.
+func TestJSONSnapshotLeaves(t *testing.T) {
+	rsp := &gnmi.GetResponse{
+		Notification: []*gnmi.Notification{
+			{
+				Timestamp: 42,
+				Prefix:    mustParsePath(t, "/interfaces"),
+				Update: []*gnmi.Update{
+					{
+						Path: mustParsePath(t, "/interface[name=ethernet-1/1]/state"),
+						Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{
+							JsonIetfVal: []byte(`{"admin-status":"UP","counters":{"in-octets":"10"}}`),
+						}},
+					},
+					{
+						Path: mustParsePath(t, "/interface[name=ethernet-1/2]/state/oper-status"),
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "DOWN"}},
+					},
+				},
+			},
+		},
+	}
+	mo := &formatters.MarshalOptions{Format: "json"}
+	b, err := mo.Marshal(rsp, map[string]string{"source": "r1"})
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	got, err := snapshotFileLeaves(b, "json")
+	if err != nil {
+		t.Fatalf("failed to flatten json snapshot: %v", err)
+	}
+	pb, err := proto.Marshal(rsp)
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	want, err := snapshotFileLeaves(pb, "proto")
+	if err != nil {
+		t.Fatalf("failed to flatten proto snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json leaves: %v", got)
+		t.Errorf("proto leaves: %v", want)
+	}
+	if _, ok := got["/interfaces/interface[name=ethernet-1/2]/state/oper-status"]; !ok {
+		t.Errorf("missing keyed leaf in %v", got)
+	}
+}
+
+func mustParsePath(t *testing.T, p string) *gnmi.Path {
+	t.Helper()
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		t.Fatalf("failed to parse path %q: %v", p, err)
+	}
+	return gp
+}
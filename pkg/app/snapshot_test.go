@@ -0,0 +1,159 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotFileName(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "router1", format: "json", want: "router1.json"},
+		{name: "router1", format: "protojson", want: "router1.json"},
+		{name: "router1", format: "prototext", want: "router1.txt"},
+		{name: "router1", format: "proto", want: "router1.pb"},
+		{name: "10.0.0.1:57400", format: "json", want: "10.0.0.1%3A57400.json"},
+		{name: "a/1", format: "json", want: "a%2F1.json"},
+		{name: "a_1", format: "json", want: "a_1.json"},
+	}
+	for _, tt := range tests {
+		if got := snapshotFileName(tt.name, tt.format); got != tt.want {
+			t.Errorf("snapshotFileName(%q, %q)=%q, expected %q", tt.name, tt.format, got, tt.want)
+		}
+	}
+	// distinct target names must not share a file
+	seen := make(map[string]string)
+	for _, name := range []string{"a:1", "a/1", "a_1", "a%3A1", "a 1", "a+1"} {
+		f := snapshotFileName(name, "json")
+		if other, ok := seen[f]; ok {
+			t.Errorf("targets %q and %q share the snapshot file %q", name, other, f)
+		}
+		seen[f] = name
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file.json")
+	for _, content := range []string{"first", "second"} {
+		err := writeFileAtomic(name, []byte(content))
+		if err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if string(b) != content {
+			t.Errorf("got content %q, expected %q", b, content)
+		}
+	}
+	if _, err := os.Stat(name + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "file.json"), []byte("x")); err == nil {
+		t.Errorf("expected an error writing to a missing directory")
+	}
+}
+
+func TestLoadSnapshotManifest(t *testing.T) {
+	previous := &snapshotManifest{
+		Paths:      []string{"/interfaces", "/system"},
+		FileFormat: "json",
+		Targets: map[string]*snapshotTargetStatus{
+			"r1": {Status: snapshotStatusCompleted, File: "r1.json"},
+			"r2": {Status: snapshotStatusFailed, Error: "timeout"},
+		},
+	}
+	tests := []struct {
+		name        string
+		resume      bool
+		noFile      bool
+		paths       []string
+		format      string
+		wantErr     bool
+		wantTargets int
+	}{
+		{
+			name:   "no_resume",
+			paths:  []string{"/interfaces", "/system"},
+			format: "json",
+		},
+		{
+			name:        "resume",
+			resume:      true,
+			paths:       []string{"/interfaces", "/system"},
+			format:      "json",
+			wantTargets: 2,
+		},
+		{
+			name:   "resume_without_manifest",
+			resume: true,
+			noFile: true,
+			paths:  []string{"/interfaces"},
+			format: "json",
+		},
+		{
+			name:    "resume_paths_mismatch",
+			resume:  true,
+			paths:   []string{"/interfaces"},
+			format:  "json",
+			wantErr: true,
+		},
+		{
+			name:    "resume_format_mismatch",
+			resume:  true,
+			paths:   []string{"/interfaces", "/system"},
+			format:  "proto",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			a.Config.LocalFlags.SnapshotDir = t.TempDir()
+			if !tt.noFile {
+				b, err := json.Marshal(previous)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(a.Config.LocalFlags.SnapshotDir, snapshotManifestFileName), b, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			a.Config.LocalFlags.SnapshotResume = tt.resume
+			a.Config.LocalFlags.SnapshotPath = tt.paths
+			a.Config.LocalFlags.SnapshotFileFormat = tt.format
+			m, err := a.loadSnapshotManifest()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(m.Targets) != tt.wantTargets {
+				t.Errorf("got %d targets in the manifest, expected %d", len(m.Targets), tt.wantTargets)
+			}
+			if tt.wantTargets > 0 && m.Targets["r1"].Status != snapshotStatusCompleted {
+				t.Errorf("expected target r1 to be completed, got %+v", m.Targets["r1"])
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxStreamQueueCheckInterval = time.Second
+
+// streamQueue decouples the cache reads of a STREAM subscription
+// from the sends to the subscriber.
+// It is bounded so that a slow subscriber cannot grow it indefinitely,
+// and it reports the subscriber as slow if its depth stays at or above
+// the threshold for the configured duration.
+type streamQueue struct {
+	ch        chan *gnmi.SubscribeResponse
+	threshold int
+	duration  time.Duration
+}
+
+func newStreamQueue(size, threshold int, duration time.Duration) *streamQueue {
+	return &streamQueue{
+		ch:        make(chan *gnmi.SubscribeResponse, size),
+		threshold: threshold,
+		duration:  duration,
+	}
+}
+
+// push queues rsp, it blocks while the queue is full.
+func (q *streamQueue) push(ctx context.Context, rsp *gnmi.SubscribeResponse) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case q.ch <- rsp:
+		return nil
+	}
+}
+
+// run sends the queued responses using send until ctx is done or a send fails.
+// It returns a ResourceExhausted error if the subscriber is slow.
+func (q *streamQueue) run(ctx context.Context, send func(*gnmi.SubscribeResponse) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case rsp := <-q.ch:
+				err := send(rsp)
+				if err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}
+	}()
+
+	checkInterval := q.duration / 10
+	if checkInterval > maxStreamQueueCheckInterval {
+		checkInterval = maxStreamQueueCheckInterval
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	// time since the queue depth is above the threshold
+	var above time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errCh:
+			return err
+		case now := <-ticker.C:
+			if len(q.ch) < q.threshold {
+				above = time.Time{}
+				continue
+			}
+			if above.IsZero() {
+				above = now
+				continue
+			}
+			if now.Sub(above) >= q.duration {
+				return status.Errorf(codes.ResourceExhausted,
+					"slow consumer: send queue depth stayed above %d for %s", q.threshold, q.duration)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamQueue(t *testing.T) {
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+	}
+	t.Run("fast_consumer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		q := newStreamQueue(10, 5, 100*time.Millisecond)
+		sent := make(chan *gnmi.SubscribeResponse, 20)
+		errCh := make(chan error)
+		go func() {
+			errCh <- q.run(ctx, func(r *gnmi.SubscribeResponse) error {
+				sent <- r
+				return nil
+			})
+		}()
+		for i := 0; i < 20; i++ {
+			if err := q.push(ctx, rsp); err != nil {
+				t.Fatalf("unexpected push error: %v", err)
+			}
+		}
+		for i := 0; i < 20; i++ {
+			select {
+			case <-sent:
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for response %d", i)
+			}
+		}
+		cancel()
+		if err := <-errCh; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+	t.Run("slow_consumer", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		q := newStreamQueue(10, 5, 100*time.Millisecond)
+		for i := 0; i < 10; i++ {
+			if err := q.push(ctx, rsp); err != nil {
+				t.Fatalf("unexpected push error: %v", err)
+			}
+		}
+		err := q.run(ctx, func(*gnmi.SubscribeResponse) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("expected a ResourceExhausted error, got %v", err)
+		}
+	})
+	t.Run("send_error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		q := newStreamQueue(10, 5, time.Minute)
+		sendErr := errors.New("stream closed")
+		if err := q.push(ctx, rsp); err != nil {
+			t.Fatalf("unexpected push error: %v", err)
+		}
+		err := q.run(ctx, func(*gnmi.SubscribeResponse) error { return sendErr })
+		if !errors.Is(err, sendErr) {
+			t.Errorf("expected the send error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// streamRateLimiter paces the responses sent on a STREAM subscription
+// so that a single subscription cannot use more than its share of the
+// server resources.
+type streamRateLimiter struct {
+	msgs  *rate.Limiter
+	bytes *rate.Limiter
+}
+
+// newStreamRateLimiter returns a limiter enforcing r,
+// or nil if r does not limit anything.
+func newStreamRateLimiter(r *config.SendRate) *streamRateLimiter {
+	if r == nil || (r.MessagesPerSecond <= 0 && r.BytesPerSecond <= 0) {
+		return nil
+	}
+	l := new(streamRateLimiter)
+	if r.MessagesPerSecond > 0 {
+		l.msgs = rate.NewLimiter(rate.Limit(r.MessagesPerSecond), r.MessagesBurst)
+	}
+	if r.BytesPerSecond > 0 {
+		l.bytes = rate.NewLimiter(rate.Limit(r.BytesPerSecond), r.BytesBurst)
+	}
+	return l
+}
+
+// wait blocks until rsp can be sent or ctx is done.
+func (l *streamRateLimiter) wait(ctx context.Context, rsp *gnmi.SubscribeResponse) error {
+	now := time.Now()
+	defer func() {
+		if d := time.Since(now); d > time.Millisecond {
+			gnmiServerSendRateLimitWait.Add(d.Seconds())
+		}
+	}()
+	if l.msgs != nil {
+		if err := l.msgs.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.bytes != nil {
+		n := proto.Size(rsp)
+		// a response bigger than the burst consumes the whole burst
+		if n > l.bytes.Burst() {
+			n = l.bytes.Burst()
+		}
+		return l.bytes.WaitN(ctx, n)
+	}
+	return nil
+}
+
+// limitedSend returns send paced by the limiter l,
+// or send itself if l is nil.
+func limitedSend(ctx context.Context, send func(*gnmi.SubscribeResponse) error, l *streamRateLimiter) func(*gnmi.SubscribeResponse) error {
+	if l == nil {
+		return send
+	}
+	return func(rsp *gnmi.SubscribeResponse) error {
+		if err := l.wait(ctx, rsp); err != nil {
+			return err
+		}
+		return send(rsp)
+	}
+}
+
+// clientLimiterTTL is how long the limiter of a client
+// without any STREAM subscription is kept,
+// so that reconnecting does not reset its send rate.
+const clientLimiterTTL = time.Minute
+
+// clientRateLimiters holds the limiters shared by the STREAM
+// subscriptions of each client.
+type clientRateLimiters struct {
+	m        *sync.Mutex
+	limiters map[string]*clientRateLimiter
+}
+
+type clientRateLimiter struct {
+	l *streamRateLimiter
+	// number of Subscribe RPCs using the limiter
+	refs     int
+	lastUsed time.Time
+}
+
+func newClientRateLimiters() *clientRateLimiters {
+	return &clientRateLimiters{
+		m:        new(sync.Mutex),
+		limiters: make(map[string]*clientRateLimiter),
+	}
+}
+
+// acquire returns the limiter of client, creating it with rate r
+// if the client does not have one, and a func releasing it.
+// The limiters unused for longer than clientLimiterTTL are removed.
+func (c *clientRateLimiters) acquire(client string, r *config.SendRate) (*streamRateLimiter, func()) {
+	client = strings.ToLower(client)
+	now := time.Now()
+	c.m.Lock()
+	defer c.m.Unlock()
+	for k, cl := range c.limiters {
+		if cl.refs == 0 && now.Sub(cl.lastUsed) > clientLimiterTTL {
+			delete(c.limiters, k)
+		}
+	}
+	cl, ok := c.limiters[client]
+	if !ok {
+		l := newStreamRateLimiter(r)
+		if l == nil {
+			return nil, func() {}
+		}
+		cl = &clientRateLimiter{l: l}
+		c.limiters[client] = cl
+	}
+	cl.refs++
+	return cl.l, func() {
+		c.m.Lock()
+		defer c.m.Unlock()
+		cl.refs--
+		cl.lastUsed = time.Now()
+	}
+}
+
+// clientSendRateIdentity returns the identity the client send rate applies to:
+// the common name of the client verified certificate if any,
+// otherwise its host address.
+// Unlike server.ClientIdentity, it ignores the `username` metadata
+// which the client can set freely.
+func clientSendRateIdentity(ctx context.Context) string {
+	if cn := clientCertCommonName(ctx); cn != "" {
+		return cn
+	}
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return pr.Addr.String()
+	}
+	return host
+}
+
+// subscriptionSendRate is a per subscription send rate
+// with its compiled paths.
+type subscriptionSendRate struct {
+	paths []*aclPath
+	rate  *config.SendRate
+}
+
+func newSubscriptionSendRates(srs []*config.SubscriptionSendRate) ([]*subscriptionSendRate, error) {
+	ssrs := make([]*subscriptionSendRate, 0, len(srs))
+	for i, sr := range srs {
+		ssr := &subscriptionSendRate{rate: &sr.SendRate}
+		for _, p := range sr.Paths {
+			ap, err := newACLPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("subscription send rate %d: %v", i, err)
+			}
+			ssr.paths = append(ssr.paths, ap)
+		}
+		ssrs = append(ssrs, ssr)
+	}
+	return ssrs, nil
+}
+
+func (a *App) initSubscriptionSendRates() error {
+	msr := a.Config.GnmiServer.MaxSendRate
+	if msr == nil {
+		return nil
+	}
+	a.clientRateLimiters = newClientRateLimiters()
+	if len(msr.Subscriptions) == 0 {
+		return nil
+	}
+	var err error
+	a.subscriptionSendRates, err = newSubscriptionSendRates(msr.Subscriptions)
+	return err
+}
+
+// subscriptionSendRate returns the send rate of the subscription with path p
+// relative to prefix, or nil if no per subscription send rate covers it.
+func (a *App) subscriptionSendRate(prefix, p *gnmi.Path) *config.SendRate {
+	if len(a.subscriptionSendRates) == 0 {
+		return nil
+	}
+	fp := &gnmi.Path{
+		Origin: prefix.GetOrigin(),
+		Elem:   append(append([]*gnmi.PathElem{}, prefix.GetElem()...), p.GetElem()...),
+	}
+	if fp.Origin == "" {
+		fp.Origin = p.GetOrigin()
+	}
+	for _, ssr := range a.subscriptionSendRates {
+		for _, ap := range ssr.paths {
+			if ap.covers(fp) {
+				return ssr.rate
+			}
+		}
+	}
+	return nil
+}
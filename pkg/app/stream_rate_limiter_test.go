@@ -0,0 +1,170 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+func TestStreamRateLimiter(t *testing.T) {
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: 42,
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interfaces"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+				}},
+			},
+		},
+	}
+	if l := newStreamRateLimiter(&config.SendRate{MessagesBurst: 1, BytesBurst: 1}); l != nil {
+		t.Fatal("expected no limiter without a rate")
+	}
+
+	tests := []struct {
+		name string
+		rate *config.SendRate
+	}{
+		{
+			name: "messages",
+			rate: &config.SendRate{MessagesPerSecond: 20, MessagesBurst: 1},
+		},
+		{
+			// the response is bigger than the burst
+			name: "bytes",
+			rate: &config.SendRate{BytesPerSecond: 20, BytesBurst: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newStreamRateLimiter(tt.rate)
+			ctx := context.Background()
+			now := time.Now()
+			for i := 0; i < 5; i++ {
+				if err := l.wait(ctx, rsp); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			// the first response is sent right away,
+			// the next 4 ones are 50ms apart.
+			if d := time.Since(now); d < 150*time.Millisecond {
+				t.Errorf("5 responses sent in %s", d)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+			if err := l.wait(ctx, rsp); err == nil {
+				t.Error("expected an error after the context is canceled")
+			}
+		})
+	}
+}
+
+func TestSubscriptionSendRate(t *testing.T) {
+	counters := &config.SubscriptionSendRate{
+		Paths:    []string{"/interfaces/interface[name=*]/state/counters"},
+		SendRate: config.SendRate{MessagesPerSecond: 10},
+	}
+	alarms := &config.SubscriptionSendRate{
+		Paths:    []string{"/system/alarms", "openconfig:/components"},
+		SendRate: config.SendRate{MessagesPerSecond: 1000},
+	}
+	ssrs, err := newSubscriptionSendRates([]*config.SubscriptionSendRate{counters, alarms})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{subscriptionSendRates: ssrs}
+	tests := []struct {
+		name   string
+		prefix string
+		path   string
+		want   *config.SendRate
+	}{
+		{name: "covered", path: "/interfaces/interface[name=1/1]/state/counters", want: &counters.SendRate},
+		{name: "below", path: "/interfaces/interface[name=1/1]/state/counters/in-octets", want: &counters.SendRate},
+		{name: "with_prefix", prefix: "/interfaces", path: "interface[name=1/1]/state/counters", want: &counters.SendRate},
+		{name: "second_entry", path: "/system/alarms", want: &alarms.SendRate},
+		{name: "origin", path: "openconfig:/components/component", want: &alarms.SendRate},
+		{name: "other_origin", path: "native:/components/component"},
+		{name: "parent", path: "/interfaces"},
+		{name: "wildcard_path", path: "/interfaces/interface/state"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prefix *gnmi.Path
+			if tt.prefix != "" {
+				prefix = mustParsePath(t, tt.prefix)
+			}
+			if got := a.subscriptionSendRate(prefix, mustParsePath(t, tt.path)); got != tt.want {
+				t.Errorf("got send rate %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+	if (&App{}).subscriptionSendRate(nil, mustParsePath(t, "/system/alarms")) != nil {
+		t.Errorf("expected no send rate without per subscription send rates")
+	}
+}
+
+func TestClientRateLimiters(t *testing.T) {
+	r := &config.SendRate{MessagesPerSecond: 10}
+	c := newClientRateLimiters()
+
+	l1, release1 := c.acquire("grafana", r)
+	l2, release2 := c.acquire("Grafana", r)
+	if l1 == nil || l1 != l2 {
+		t.Fatal("expected the subscriptions of a client to share a limiter")
+	}
+	l3, release3 := c.acquire("10.0.0.1", r)
+	if l3 == l1 {
+		t.Fatal("expected clients to get distinct limiters")
+	}
+	if l, _ := c.acquire("noc", nil); l != nil {
+		t.Fatal("expected no limiter without a rate")
+	}
+	release1()
+	release2()
+	release3()
+	// a client reconnecting within the TTL gets its limiter back
+	l4, release4 := c.acquire("grafana", r)
+	if l4 != l1 {
+		t.Fatal("expected the limiter to be kept after the subscriptions end")
+	}
+	release4()
+
+	c.limiters["grafana"].lastUsed = time.Now().Add(-2 * clientLimiterTTL)
+	c.acquire("10.0.0.1", r)
+	if _, ok := c.limiters["grafana"]; ok {
+		t.Error("expected the expired limiter to be removed")
+	}
+	if _, ok := c.limiters["10.0.0.1"]; !ok {
+		t.Error("expected the limiter in use to be kept")
+	}
+}
+
+func TestClientSendRateIdentity(t *testing.T) {
+	if got := clientSendRateIdentity(certPeerContext("grafana")); got != "grafana" {
+		t.Errorf("got identity %q, expected the certificate common name", got)
+	}
+	// the username metadata is not trusted
+	ctx := metadata.NewIncomingContext(certPeerContext(""), metadata.Pairs("username", "grafana"))
+	if got := clientSendRateIdentity(ctx); got != "10.0.0.1" {
+		t.Errorf("got identity %q, expected the peer address", got)
+	}
+	if got := clientSendRateIdentity(context.Background()); got != "" {
+		t.Errorf("got identity %q without a peer", got)
+	}
+}
@@ -47,6 +47,10 @@ func (a *App) SubscribePreRunE(cmd *cobra.Command, args []string) error {
 func (a *App) SubscribeRunE(cmd *cobra.Command, args []string) error {
 	defer a.InitSubscribeFlags(cmd)
 
+	if a.Config.Format == formatSummary {
+		return fmt.Errorf("format summary not supported for Subscribe RPC")
+	}
+
 	// prompt mode
 	if a.PromptMode {
 		return a.SubscribeRunPrompt(cmd, args)
@@ -77,6 +81,30 @@ func (a *App) SubscribeRunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	err = a.Config.GetTargetRewrite()
+	if err != nil {
+		return err
+	}
+	err = a.initTargetRewrite()
+	if err != nil {
+		return err
+	}
+	err = a.Config.GetCapabilitiesCache()
+	if err != nil {
+		return err
+	}
+	err = a.Config.GetInstanceLabels()
+	if err != nil {
+		return err
+	}
+	err = a.Config.GetFaultInjection()
+	if err != nil {
+		return err
+	}
+	err = a.initFaultInjection()
+	if err != nil {
+		return err
+	}
 	numInputs := len(a.Config.Inputs)
 	if len(subCfg) == 0 && numInputs == 0 {
 		return errors.New("no subscriptions or inputs configuration found")
@@ -85,7 +113,7 @@ func (a *App) SubscribeRunE(cmd *cobra.Command, args []string) error {
 	if allSubscriptionsModeOnce(subCfg) {
 		return a.SubscribeRunONCE(cmd, args)
 	}
-	// only poll mode subscriptions requested
+	// only poll mode subscriptions without triggers requested
 	if allSubscriptionsModePoll(subCfg) {
 		return a.SubscribeRunPoll(cmd, args)
 	}
@@ -130,6 +158,10 @@ func (a *App) SubscribeRunE(cmd *cobra.Command, args []string) error {
 	if a.Config.LocalFlags.SubscribeWatchConfig {
 		go a.watchConfig()
 	}
+	if anySubscriptionPollTrigger(subCfg, types.PollTriggerSignal) {
+		go a.handlePollSignals(a.ctx)
+	}
+	a.notifyServiceReady(a.ctx)
 
 	for range a.ctx.Done() {
 		return a.ctx.Err()
@@ -142,12 +174,12 @@ func (a *App) subscribeStream(ctx context.Context, tc *types.TargetConfig) {
 	a.TargetSubscribeStream(ctx, tc)
 }
 
-func (a *App) subscribeOnce(ctx context.Context, tc *types.TargetConfig) {
-	defer a.wg.Done()
+func (a *App) subscribeOnce(ctx context.Context, tc *types.TargetConfig) error {
 	err := a.TargetSubscribeOnce(ctx, tc)
 	if err != nil {
 		a.logError(err)
 	}
+	return err
 }
 
 func (a *App) subscribePoll(ctx context.Context, tc *types.TargetConfig) {
@@ -183,6 +215,7 @@ func (a *App) InitSubscribeFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&a.Config.LocalFlags.SubscribeHistoryStart, "history-start", "", "", "sets the start time in a historical range subscription, nanoseconds since Unix epoch or RFC3339 format")
 	cmd.Flags().StringVarP(&a.Config.LocalFlags.SubscribeHistoryEnd, "history-end", "", "", "sets the end time in a historical range subscription, nanoseconds since Unix epoch or RFC3339 format")
 	cmd.Flags().Uint32VarP(&a.Config.LocalFlags.SubscribeDepth, "depth", "", 0, "depth extension value")
+	cmd.Flags().UintVarP(&a.Config.LocalFlags.SubscribeMaxConcurrentTargets, "max-concurrent-targets", "", 0, "maximum number of targets subscribed to concurrently in once mode, overrides the global flag --concurrency")
 	//
 	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
 		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", cmd.Name(), flag.Name), flag)
@@ -207,6 +240,14 @@ func (a *App) readConfigs() error {
 	if err != nil {
 		return fmt.Errorf("failed reading event processors config: %v", err)
 	}
+	err = a.Config.GetValueTypes()
+	if err != nil {
+		return fmt.Errorf("failed reading value types config: %v", err)
+	}
+	err = formatters.SetValueTypes(a.Config.ValueTypes)
+	if err != nil {
+		return err
+	}
 	_, err = a.LoadProtoFiles()
 	if err != nil {
 		return fmt.Errorf("failed loading proto files: %v", err)
@@ -388,6 +429,15 @@ func allSubscriptionsModeOnce(subs map[string]*types.SubscriptionConfig) bool {
 	return true
 }
 
+func anySubscriptionPollTrigger(subs map[string]*types.SubscriptionConfig, trigger string) bool {
+	for _, sub := range subs {
+		if sub.HasPollTrigger(trigger) {
+			return true
+		}
+	}
+	return false
+}
+
 func allSubscriptionsModePoll(subs map[string]*types.SubscriptionConfig) bool {
 	if len(subs) == 0 {
 		return false
@@ -396,6 +446,10 @@ func allSubscriptionsModePoll(subs map[string]*types.SubscriptionConfig) bool {
 		if strings.ToUpper(sub.Mode) != "POLL" {
 			return false
 		}
+		// polls sent by the collector
+		if sub.PollInterval > 0 || len(sub.PollTriggers) > 0 {
+			return false
+		}
 	}
 	return true
 }
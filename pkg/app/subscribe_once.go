@@ -10,7 +10,6 @@ package app
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/openconfig/grpctunnel/tunnel"
 	"github.com/spf13/cobra"
@@ -35,22 +34,8 @@ func (a *App) SubscribeRunONCE(_ *cobra.Command, _ []string) error {
 	//
 	a.InitOutputs(a.ctx)
 
-	var limiter *time.Ticker
-	if a.Config.LocalFlags.SubscribeBackoff > 0 {
-		limiter = time.NewTicker(a.Config.LocalFlags.SubscribeBackoff)
-	}
 	numTargets := len(a.Config.Targets)
 	a.errCh = make(chan error, numTargets)
-	a.wg.Add(numTargets)
-	for _, tc := range a.Config.Targets {
-		go a.subscribeOnce(a.ctx, tc)
-		if limiter != nil {
-			<-limiter.C
-		}
-	}
-	if limiter != nil {
-		limiter.Stop()
-	}
-	a.wg.Wait()
-	return a.checkErrors()
+	rs := a.runOnTargets(a.ctx, a.Config.LocalFlags.SubscribeMaxConcurrentTargets, a.Config.LocalFlags.SubscribeBackoff, a.subscribeOnce)
+	return a.checkTargetsErrors(rs)
 }
@@ -0,0 +1,183 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// subscriptionSync is the initial synchronization state of a target subscription:
+// a subscription is synced once the target sent a sync_response
+// indicating that the initial updates were all sent.
+type subscriptionSync struct {
+	Target       string `json:"target,omitempty"`
+	Subscription string `json:"subscription,omitempty"`
+	Synced       bool   `json:"synced"`
+	// start of the current stream: listener start or last stream error.
+	Since time.Time `json:"since,omitempty"`
+	// time the sync_response was received.
+	SyncedAt time.Time `json:"synced-at,omitempty"`
+	// time it took the target to send its initial updates.
+	SyncDuration string `json:"sync-duration,omitempty"`
+
+	eventName string
+	outputs   []string
+}
+
+// targetSync holds the sync states of the subscriptions of a target,
+// it is created when the target listener starts.
+type targetSync struct {
+	subs map[string]*subscriptionSync
+}
+
+// initSyncStates sets the subscriptions of target t as not synced.
+func (a *App) initSyncStates(t *target.Target, now time.Time) *targetSync {
+	ts := &targetSync{subs: make(map[string]*subscriptionSync, len(t.Subscriptions))}
+	for name, sc := range t.Subscriptions {
+		outs := sc.Outputs
+		if len(outs) == 0 {
+			outs = t.Config.Outputs
+		}
+		ts.subs[name] = &subscriptionSync{
+			Target:       t.Config.Name,
+			Subscription: name,
+			Since:        now,
+			eventName:    sc.SyncEvent,
+			outputs:      outs,
+		}
+		subscriptionSynced.WithLabelValues(t.Config.Name, name).Set(0)
+	}
+	a.operLock.Lock()
+	a.targetsSync[t.Config.Name] = ts
+	a.operLock.Unlock()
+	return ts
+}
+
+// syncReceived records the sync_response received by subscription sub of target name.
+// It returns the subscription state if it was not synced already.
+func (a *App) syncReceived(name, sub string, now time.Time) *subscriptionSync {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	ts, ok := a.targetsSync[name]
+	if !ok {
+		return nil
+	}
+	st, ok := ts.subs[sub]
+	if !ok || st.Synced {
+		return nil
+	}
+	st.Synced = true
+	st.SyncedAt = now
+	d := now.Sub(st.Since)
+	st.SyncDuration = d.String()
+	subscriptionSynced.WithLabelValues(name, sub).Set(1)
+	subscriptionSyncDuration.WithLabelValues(name, sub).Set(d.Seconds())
+	r := *st
+	return &r
+}
+
+// syncLost marks subscription sub of target name as not synced
+// after its stream failed, the target sends its initial updates again
+// once the subscription is re-established.
+func (a *App) syncLost(name, sub string, now time.Time) {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	ts, ok := a.targetsSync[name]
+	if !ok {
+		return
+	}
+	st, ok := ts.subs[sub]
+	if !ok {
+		return
+	}
+	st.Synced = false
+	st.Since = now
+	st.SyncedAt = time.Time{}
+	st.SyncDuration = ""
+	subscriptionSynced.WithLabelValues(name, sub).Set(0)
+}
+
+// deleteSyncStates removes the subscriptions states ts and metrics of target name,
+// unless they were already replaced by a new listener of the same target.
+func (a *App) deleteSyncStates(name string, ts *targetSync) {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	if a.targetsSync[name] != ts {
+		return
+	}
+	for sub := range ts.subs {
+		subscriptionSynced.DeleteLabelValues(name, sub)
+		subscriptionSyncDuration.DeleteLabelValues(name, sub)
+	}
+	delete(a.targetsSync, name)
+}
+
+// getSyncStates returns the subscriptions states of target name sorted by subscription name.
+func (a *App) getSyncStates(name string) []*subscriptionSync {
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	ts, ok := a.targetsSync[name]
+	if !ok {
+		return nil
+	}
+	r := make([]*subscriptionSync, 0, len(ts.subs))
+	for _, st := range ts.subs {
+		c := *st
+		r = append(r, &c)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Subscription < r[j].Subscription
+	})
+	return r
+}
+
+// event builds the event reporting that the subscription is synced.
+func (st *subscriptionSync) event() *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      st.eventName,
+		Timestamp: st.SyncedAt.UnixNano(),
+		Tags: map[string]string{
+			"source":            st.Target,
+			"subscription-name": st.Subscription,
+		},
+		Values: map[string]interface{}{
+			"synced":                1,
+			"sync-duration-seconds": st.SyncedAt.Sub(st.Since).Seconds(),
+		},
+	}
+}
+
+func (a *App) exportSyncEvent(ctx context.Context, st *subscriptionSync) {
+	a.Logger.Printf("target %q: subscription %s: synced after %s", st.Target, st.Subscription, st.SyncDuration)
+	if st.eventName == "" {
+		return
+	}
+	a.exportEvent(ctx, st.event(), st.outputs...)
+}
+
+func (a *App) handleTargetsSyncGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	states := a.getSyncStates(id)
+	if states == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("no subscriptions found for target %q", id)}})
+		return
+	}
+	a.handlerCommonGet(w, states)
+}
@@ -0,0 +1,102 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestSyncStates(t *testing.T) {
+	a := New()
+	o := &eventsOutput{}
+	a.Outputs["o1"] = o
+	tg := &target.Target{
+		Config: &types.TargetConfig{Name: "r1"},
+		Subscriptions: map[string]*types.SubscriptionConfig{
+			"sub1": {Name: "sub1", SyncEvent: "sub1-synced"},
+			"sub2": {Name: "sub2"},
+		},
+	}
+	start := time.Unix(100, 0)
+	ts := a.initSyncStates(tg, start)
+	states := a.getSyncStates("r1")
+	if len(states) != 2 || states[0].Subscription != "sub1" || states[0].Synced || states[1].Synced {
+		t.Fatalf("unexpected initial states: %+v", states)
+	}
+
+	st := a.syncReceived("r1", "sub1", start.Add(3*time.Second))
+	if st == nil || !st.Synced || st.SyncDuration != "3s" {
+		t.Fatalf("unexpected state after sync: %+v", st)
+	}
+	// only the first sync response is reported
+	if st := a.syncReceived("r1", "sub1", start.Add(4*time.Second)); st != nil {
+		t.Errorf("unexpected state for a second sync response: %+v", st)
+	}
+	if st := a.syncReceived("r1", "unknown", start); st != nil {
+		t.Errorf("unexpected state for an unknown subscription: %+v", st)
+	}
+
+	a.exportSyncEvent(context.Background(), st)
+	if len(o.events) != 1 || o.events[0].Name != "sub1-synced" ||
+		o.events[0].Values["sync-duration-seconds"] != 3.0 {
+		t.Fatalf("unexpected sync events: %+v", o.events)
+	}
+	// no event without an event name
+	a.exportSyncEvent(context.Background(), a.syncReceived("r1", "sub2", start.Add(time.Second)))
+	if len(o.events) != 1 {
+		t.Fatalf("unexpected sync events: %+v", o.events)
+	}
+
+	a.syncLost("r1", "sub1", start.Add(time.Minute))
+	states = a.getSyncStates("r1")
+	if states[0].Synced || !states[0].Since.Equal(start.Add(time.Minute)) || !states[1].Synced {
+		t.Fatalf("unexpected states after a stream error: %+v", states)
+	}
+
+	// REST API
+	get := func(id string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/targets/"+id+"/sync", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		a.handleTargetsSyncGet(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+	code, body := get("r1")
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", code)
+	}
+	var rsp []*subscriptionSync
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(rsp) != 2 || rsp[1].Subscription != "sub2" || !rsp[1].Synced {
+		t.Errorf("unexpected response: %s", body)
+	}
+
+	// states replaced by a new listener are not deleted
+	ts2 := a.initSyncStates(tg, start)
+	a.deleteSyncStates("r1", ts)
+	if len(a.getSyncStates("r1")) != 2 {
+		t.Fatalf("expected the new listener states to be kept")
+	}
+	a.deleteSyncStates("r1", ts2)
+	if code, _ := get("r1"); code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for a stopped target", code)
+	}
+}
@@ -11,11 +11,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/fullstorydev/grpcurl"
 
 	"github.com/openconfig/gnmic/pkg/api/target"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
 // initTarget initializes a new target given its name.
@@ -24,18 +26,27 @@ import (
 func (a *App) initTarget(tc *types.TargetConfig) (*target.Target, error) {
 	t, ok := a.Targets[tc.Name]
 	if !ok {
+		a.setTLSSessionCache(tc)
 		t := target.NewTarget(tc)
 		for _, subName := range tc.Subscriptions {
 			if sub, ok := a.Config.Subscriptions[subName]; ok {
 				t.Subscriptions[subName] = sub
 			}
 		}
-		if len(t.Subscriptions) == 0 {
+		bundleSubs, err := a.Config.BundlesSubscriptions(tc.Bundles...)
+		if err != nil {
+			return nil, err
+		}
+		for n, sub := range bundleSubs {
+			t.Subscriptions[n] = sub
+		}
+		// a target using the auto bundle gets its subscriptions once its platform is detected
+		if len(t.Subscriptions) == 0 && !usesAutoBundle(tc) {
 			for n, sub := range a.Config.Subscriptions {
 				t.Subscriptions[n] = sub
 			}
 		}
-		err := a.parseProtoFiles(t)
+		err = a.parseProtoFiles(t)
 		if err != nil {
 			return nil, err
 		}
@@ -45,6 +56,24 @@ func (a *App) initTarget(tc *types.TargetConfig) (*target.Target, error) {
 	return t, nil
 }
 
+// setTLSSessionCache makes the target store its TLS sessions
+// in the cache shared by all the targets.
+func (a *App) setTLSSessionCache(tc *types.TargetConfig) {
+	if a.tlsSessionCache != nil {
+		tc.SetTLSSessionCache(a.tlsSessionCache.Target(tc.Name))
+	}
+}
+
+// SaveTLSSessions writes the TLS sessions cache to its file, if configured.
+func (a *App) SaveTLSSessions() {
+	if a.tlsSessionCache == nil {
+		return
+	}
+	if err := a.tlsSessionCache.Save(); err != nil {
+		a.Logger.Printf("failed to save TLS sessions cache: %v", err)
+	}
+}
+
 func (a *App) stopTarget(ctx context.Context, name string) error {
 	if a.Targets == nil {
 		return nil
@@ -59,6 +88,9 @@ func (a *App) stopTarget(ctx context.Context, name string) error {
 	t := a.Targets[name]
 	t.StopSubscriptions()
 	delete(a.Targets, name)
+	a.deleteTargetHostname(name)
+	a.deleteTargetCapabilities(name)
+	a.deleteTargetPlatform(name)
 	if a.locker == nil {
 		return nil
 	}
@@ -76,7 +108,12 @@ func (a *App) DeleteTarget(ctx context.Context, name string) error {
 	delete(a.Config.Targets, name)
 	a.configLock.Unlock()
 	a.Logger.Printf("target %q deleted from config", name)
-	// delete from oper map
+	return a.deleteTargetState(ctx, name)
+}
+
+// deleteTargetState stops target name, if it is running,
+// and deletes its operational state.
+func (a *App) deleteTargetState(ctx context.Context, name string) error {
 	a.operLock.Lock()
 	defer a.operLock.Unlock()
 	if cfn, ok := a.targetsLockFn[name]; ok {
@@ -85,6 +122,12 @@ func (a *App) DeleteTarget(ctx context.Context, name string) error {
 	if a.c != nil {
 		a.c.DeleteTarget(name)
 	}
+	a.collectorExt.Delete(name)
+	a.deleteTargetHostname(name)
+	a.deleteTargetCapabilities(name)
+	a.deleteTargetPlatform(name)
+	a.deleteTargetStats(name)
+	deleteTargetConnMetrics(name)
 	if t, ok := a.Targets[name]; ok {
 		delete(a.Targets, name)
 		t.Close()
@@ -119,9 +162,16 @@ func (a *App) UpdateTargetSubscription(ctx context.Context, name string, subs []
 // AddTargetConfig adds a *TargetConfig to the configuration map
 func (a *App) AddTargetConfig(tc *types.TargetConfig) {
 	a.Logger.Printf("adding target %s", tc)
-	_, ok := a.Config.Targets[tc.Name]
-	if ok {
-		return
+	a.addTargetConfig(tc)
+}
+
+// addTargetConfig adds tc to the configuration map if a target
+// with the same name does not exist, it returns false otherwise.
+func (a *App) addTargetConfig(tc *types.TargetConfig) bool {
+	a.configLock.Lock()
+	defer a.configLock.Unlock()
+	if _, ok := a.Config.Targets[tc.Name]; ok {
+		return false
 	}
 	if tc.BufferSize <= 0 {
 		tc.BufferSize = a.Config.TargetBufferSize
@@ -129,10 +179,8 @@ func (a *App) AddTargetConfig(tc *types.TargetConfig) {
 	if tc.RetryTimer <= 0 {
 		tc.RetryTimer = a.Config.Retry
 	}
-
-	a.configLock.Lock()
-	defer a.configLock.Unlock()
 	a.Config.Targets[tc.Name] = tc
+	return true
 }
 
 func (a *App) parseProtoFiles(t *target.Target) error {
@@ -161,3 +209,43 @@ func (a *App) targetConfigExists(name string) bool {
 	a.configLock.RUnlock()
 	return ok
 }
+
+// addTargetMeta adds the target event-tags and labels to the
+// metadata m of a message received from the target.
+// The event-tags overwrite the keys already set in m, the labels never
+// overwrite them (e.g: source, format, subscription-name or an event-tag).
+func addTargetMeta(m map[string]string, tc *types.TargetConfig) {
+	for k, v := range tc.EventTags {
+		m[k] = v
+	}
+	for k, v := range tc.Labels {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+}
+
+// addOutputOptionsMeta sets the output format and event processors
+// overridden for the subscription sc in the message metadata m.
+// The options set under the target for sc take precedence over
+// the ones set under the subscription.
+func addOutputOptionsMeta(m map[string]string, tc *types.TargetConfig, sc *types.SubscriptionConfig) {
+	if sc == nil {
+		return
+	}
+	opts := make([]*types.OutputOptions, 0, 2)
+	if sc.OutputOptions != nil {
+		opts = append(opts, sc.OutputOptions)
+	}
+	if tc != nil && tc.SubscriptionsOutputOptions[sc.Name] != nil {
+		opts = append(opts, tc.SubscriptionsOutputOptions[sc.Name])
+	}
+	for _, o := range opts {
+		if o.Format != "" {
+			m[formatters.MetaOutputFormat] = o.Format
+		}
+		if o.EventProcessors != nil {
+			m[formatters.MetaOutputEventProcessors] = strings.Join(o.EventProcessors, ",")
+		}
+	}
+}
@@ -0,0 +1,609 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/mitchellh/go-homedir"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+const (
+	vendorNokiaSRL  = "nokia_srl"
+	vendorNokiaSROS = "nokia_sros"
+	vendorArista    = "arista"
+	vendorCisco     = "cisco"
+	vendorJuniper   = "juniper"
+	vendorUnknown   = "unknown"
+)
+
+// encodings in order of preference
+var targetAddEncodings = []string{"json_ietf", "json", "proto", "ascii"}
+
+// subscriptionRecommendation is a subscription proposed when adding
+// a target, if the target advertises a model matching model.
+type subscriptionRecommendation struct {
+	name           string
+	model          string
+	paths          []string
+	streamMode     string
+	sampleInterval time.Duration
+}
+
+var openconfigSubscriptions = []subscriptionRecommendation{
+	{
+		name:           "oc-interfaces-counters",
+		model:          "openconfig-interfaces",
+		paths:          []string{"/interfaces/interface/state/counters"},
+		streamMode:     "sample",
+		sampleInterval: 10 * time.Second,
+	},
+	{
+		name:       "oc-interfaces-oper-status",
+		model:      "openconfig-interfaces",
+		paths:      []string{"/interfaces/interface/state/oper-status"},
+		streamMode: "on_change",
+	},
+	{
+		name:           "oc-system-cpus",
+		model:          "openconfig-system",
+		paths:          []string{"/system/cpus/cpu/state/total"},
+		streamMode:     "sample",
+		sampleInterval: 30 * time.Second,
+	},
+	{
+		name:       "oc-bgp-neighbors-state",
+		model:      "openconfig-network-instance",
+		paths:      []string{"/network-instances/network-instance/protocols/protocol/bgp/neighbors/neighbor/state/session-state"},
+		streamMode: "on_change",
+	},
+}
+
+// recommended subscriptions per vendor,
+// vendors not listed get the openconfig subscriptions.
+var vendorSubscriptions = map[string][]subscriptionRecommendation{
+	vendorNokiaSRL: {
+		{
+			name:           "srl-interfaces-stats",
+			model:          "srl_nokia-interfaces",
+			paths:          []string{"/interface[name=*]/statistics"},
+			streamMode:     "sample",
+			sampleInterval: 10 * time.Second,
+		},
+		{
+			name:       "srl-interfaces-oper-state",
+			model:      "srl_nokia-interfaces",
+			paths:      []string{"/interface[name=*]/oper-state"},
+			streamMode: "on_change",
+		},
+		{
+			name:           "srl-cpu",
+			model:          "srl_nokia-platform-cpu",
+			paths:          []string{"/platform/control[slot=*]/cpu[index=all]/total"},
+			streamMode:     "sample",
+			sampleInterval: 30 * time.Second,
+		},
+		{
+			name:       "srl-bgp-neighbors-state",
+			model:      "srl_nokia-bgp",
+			paths:      []string{"/network-instance[name=*]/protocols/bgp/neighbor[peer-address=*]/session-state"},
+			streamMode: "on_change",
+		},
+	},
+	vendorNokiaSROS: {
+		{
+			name:           "sros-ports-stats",
+			model:          "nokia-state",
+			paths:          []string{"/state/port[port-id=*]/statistics"},
+			streamMode:     "sample",
+			sampleInterval: 10 * time.Second,
+		},
+		{
+			name:       "sros-ports-oper-state",
+			model:      "nokia-state",
+			paths:      []string{"/state/port[port-id=*]/oper-state"},
+			streamMode: "on_change",
+		},
+		{
+			name:           "sros-cpu",
+			model:          "nokia-state",
+			paths:          []string{"/state/system/cpu[sample-period=60]/summary"},
+			streamMode:     "sample",
+			sampleInterval: 60 * time.Second,
+		},
+	},
+}
+
+// targetProbe is the result of probing a target.
+type targetProbe struct {
+	insecure    bool
+	skipVerify  bool
+	gnmiVersion string
+	vendor      string
+	encodings   []string
+	models      []*gnmi.ModelData
+}
+
+// InitTargetAddFlags used to init or reset targetAddCmd flags for gnmic-prompt mode
+func (a *App) InitTargetAddFlags(cmd *cobra.Command) {
+	cmd.ResetFlags()
+
+	cmd.Flags().BoolVarP(&a.Config.LocalFlags.TargetAddInteractive, "interactive", "i", false, "prompt for the missing target parameters and for the subscriptions to add")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.TargetAddName, "name", "", "", "target name, defaults to the target address")
+	cmd.Flags().StringVarP(&a.Config.LocalFlags.TargetAddFile, "file", "", "", "YAML configuration file the target is added to, defaults to the configuration file in use")
+
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		a.Config.FileConfig.BindPFlag(fmt.Sprintf("%s-%s", "target-add", flag.Name), flag)
+	})
+}
+
+func (a *App) TargetAddPreRunE(cmd *cobra.Command, _ []string) error {
+	a.Config.SetLocalFlagsFromFile(cmd)
+	a.createCollectorDialOpts()
+	return nil
+}
+
+func (a *App) TargetAddRunE(cmd *cobra.Command, args []string) error {
+	defer a.InitTargetAddFlags(cmd)
+
+	interactive := a.Config.LocalFlags.TargetAddInteractive
+	tc := &types.TargetConfig{Name: a.Config.LocalFlags.TargetAddName}
+	if len(a.Config.Address) > 0 {
+		tc.Address = a.Config.Address[0]
+	}
+	if a.Config.Username != "" {
+		tc.Username = &a.Config.Username
+	}
+	if a.Config.Password != "" {
+		tc.Password = &a.Config.Password
+	}
+	if interactive {
+		err := promptTargetConfig(tc)
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Address == "" {
+		return errors.New("missing target address")
+	}
+	if tc.Name == "" {
+		tc.Name = tc.Address
+	}
+	err := a.Config.SetTargetConfigDefaults(tc)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("probing target %q...\n", tc.Address)
+	p, err := a.probeTarget(a.ctx, tc)
+	if err != nil {
+		return err
+	}
+	fmt.Print(p.summary())
+
+	proposed := newTargetAddConfig(tc, p)
+	recs := recommendedSubscriptions(p.vendor, p.models)
+	for _, rec := range recs {
+		if interactive {
+			ok, err := promptConfirm(fmt.Sprintf("add subscription %q (%s)", rec.name, strings.Join(rec.paths, ", ")))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		proposed.addSubscription(rec)
+	}
+
+	b, err := yaml.Marshal(proposed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("proposed configuration:\n%s", string(b))
+
+	file, err := a.targetAddFile()
+	if err != nil {
+		return err
+	}
+	if interactive {
+		ok, err := promptConfirm(fmt.Sprintf("add target %q to %s", tc.Name, file))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	err = appendTargetConfig(file, proposed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("target %q added to %s\n", tc.Name, file)
+	return nil
+}
+
+// targetAddFile returns the configuration file the target is added to.
+func (a *App) targetAddFile() (string, error) {
+	if a.Config.LocalFlags.TargetAddFile != "" {
+		return a.Config.LocalFlags.TargetAddFile, nil
+	}
+	if f := a.Config.FileConfig.ConfigFileUsed(); f != "" {
+		return f, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gnmic.yaml"), nil
+}
+
+func promptTargetConfig(tc *types.TargetConfig) error {
+	var err error
+	if tc.Address == "" {
+		tc.Address, err = promptString("target address", "", false)
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Name == "" {
+		tc.Name, err = promptString("target name", tc.Address, false)
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Username == nil {
+		username, err := promptString("username", "", false)
+		if err != nil {
+			return err
+		}
+		tc.Username = &username
+	}
+	if tc.Password == nil {
+		password, err := promptString("password", "", true)
+		if err != nil {
+			return err
+		}
+		tc.Password = &password
+	}
+	return nil
+}
+
+func promptString(label, def string, mask bool) (string, error) {
+	p := promptui.Prompt{
+		Label:   label,
+		Default: def,
+	}
+	if mask {
+		p.Mask = '*'
+	}
+	s, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(s), nil
+}
+
+func promptConfirm(label string) (bool, error) {
+	p := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+		Default:   "y",
+	}
+	_, err := p.Run()
+	if err != nil {
+		if errors.Is(err, promptui.ErrAbort) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// probeTarget detects the target transport security and sends it a Capabilities request.
+func (a *App) probeTarget(ctx context.Context, tc *types.TargetConfig) (*targetProbe, error) {
+	insecure, skipVerify, err := probeTransport(ctx, tc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe target %q: %w", tc.Name, err)
+	}
+	ptc := *tc
+	ptc.Insecure = &insecure
+	ptc.SkipVerify = &skipVerify
+	rsp, err := a.probeCapabilities(ctx, &ptc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe target %q: %w", tc.Name, err)
+	}
+	p := &targetProbe{
+		insecure:    insecure,
+		skipVerify:  skipVerify,
+		gnmiVersion: rsp.GetGNMIVersion(),
+		vendor:      detectVendor(rsp.GetSupportedModels()),
+		models:      rsp.GetSupportedModels(),
+	}
+	for _, enc := range rsp.GetSupportedEncodings() {
+		p.encodings = append(p.encodings, strings.ToLower(enc.String()))
+	}
+	return p, nil
+}
+
+// probeTransport runs a TLS handshake with the target to find out if it requires TLS
+// and if its certificate can be verified.
+// It returns the insecure and skip-verify values to use, the configured values
+// are returned as is if the target is explicitly configured as insecure.
+func probeTransport(ctx context.Context, tc *types.TargetConfig) (bool, bool, error) {
+	configured := tc.SkipVerify != nil && *tc.SkipVerify
+	if tc.Insecure != nil && *tc.Insecure || strings.HasPrefix(tc.Address, "unix://") {
+		return tc.Insecure != nil && *tc.Insecure, configured, nil
+	}
+	ttc := *tc
+	verify := false
+	ttc.SkipVerify = &verify
+	tlsConfig, err := ttc.NewTLSConfig()
+	if err != nil {
+		return false, false, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	d := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: tc.Timeout},
+		Config:    tlsConfig,
+	}
+	addr, _, _ := strings.Cut(tc.Address, ",")
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err == nil {
+		conn.Close()
+		return false, configured, nil
+	}
+	var opErr *net.OpError
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &certErr):
+		return false, true, nil
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		// the TCP connection failed
+		return false, false, err
+	default:
+		// the TLS handshake failed, the target does not use TLS
+		return true, false, nil
+	}
+}
+
+func (a *App) probeCapabilities(ctx context.Context, tc *types.TargetConfig) (*gnmi.CapabilityResponse, error) {
+	t := target.NewTarget(tc)
+	defer t.Close()
+	ctx, cancel := context.WithTimeout(ctx, tc.Timeout)
+	defer cancel()
+	err := a.CreateGNMIClient(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return t.Capabilities(ctx)
+}
+
+// detectVendor guesses the target vendor from the models it advertises.
+func detectVendor(models []*gnmi.ModelData) string {
+	for _, m := range models {
+		name := m.GetName()
+		org := strings.ToLower(m.GetOrganization())
+		switch {
+		case strings.Contains(name, "srl_nokia"):
+			return vendorNokiaSRL
+		case strings.HasPrefix(name, "nokia-"):
+			return vendorNokiaSROS
+		case strings.Contains(org, "arista"):
+			return vendorArista
+		case strings.Contains(org, "cisco"):
+			return vendorCisco
+		case strings.Contains(org, "juniper"):
+			return vendorJuniper
+		}
+	}
+	return vendorUnknown
+}
+
+// recommendedSubscriptions returns the subscriptions recommended for the vendor,
+// restricted to the ones whose model is advertised by the target.
+func recommendedSubscriptions(vendor string, models []*gnmi.ModelData) []subscriptionRecommendation {
+	recs, ok := vendorSubscriptions[vendor]
+	if !ok {
+		recs = openconfigSubscriptions
+	}
+	r := make([]subscriptionRecommendation, 0, len(recs))
+	for _, rec := range recs {
+		for _, m := range models {
+			if strings.Contains(m.GetName(), rec.model) {
+				r = append(r, rec)
+				break
+			}
+		}
+	}
+	return r
+}
+
+// preferredEncoding returns the first encoding from targetAddEncodings
+// supported by the target.
+func preferredEncoding(encodings []string) string {
+	for _, enc := range targetAddEncodings {
+		for _, e := range encodings {
+			if e == enc {
+				return enc
+			}
+		}
+	}
+	return ""
+}
+
+func (p *targetProbe) summary() string {
+	sb := new(strings.Builder)
+	switch {
+	case p.insecure:
+		sb.WriteString("transport: insecure (no TLS)\n")
+	case p.skipVerify:
+		sb.WriteString("transport: TLS, the certificate could not be verified\n")
+	default:
+		sb.WriteString("transport: TLS\n")
+	}
+	fmt.Fprintf(sb, "gNMI version: %s\n", p.gnmiVersion)
+	fmt.Fprintf(sb, "vendor: %s\n", p.vendor)
+	fmt.Fprintf(sb, "encodings: %s\n", strings.Join(p.encodings, ", "))
+	origins := make([]string, 0, 2)
+	for _, m := range p.models {
+		if strings.HasPrefix(m.GetName(), "openconfig-") {
+			origins = append(origins, "openconfig")
+			break
+		}
+	}
+	if p.vendor != vendorUnknown {
+		origins = append(origins, "native ("+p.vendor+")")
+	}
+	fmt.Fprintf(sb, "models: %d, origins: %s\n", len(p.models), strings.Join(origins, ", "))
+	return sb.String()
+}
+
+// targetAddConfig is the configuration added to the config file.
+type targetAddConfig struct {
+	Targets       map[string]map[string]interface{} `yaml:"targets,omitempty"`
+	Subscriptions map[string]map[string]interface{} `yaml:"subscriptions,omitempty"`
+}
+
+func newTargetAddConfig(tc *types.TargetConfig, p *targetProbe) *targetAddConfig {
+	t := map[string]interface{}{
+		"address": tc.Address,
+	}
+	if tc.Username != nil && *tc.Username != "" {
+		t["username"] = *tc.Username
+	}
+	if tc.Password != nil && *tc.Password != "" {
+		t["password"] = *tc.Password
+	}
+	switch {
+	case p.insecure:
+		t["insecure"] = true
+	case p.skipVerify:
+		t["skip-verify"] = true
+	}
+	if tc.TLSCA != nil && *tc.TLSCA != "" && !p.insecure {
+		t["tls-ca"] = *tc.TLSCA
+	}
+	if enc := preferredEncoding(p.encodings); enc != "" {
+		t["encoding"] = enc
+	}
+	return &targetAddConfig{
+		Targets:       map[string]map[string]interface{}{tc.Name: t},
+		Subscriptions: map[string]map[string]interface{}{},
+	}
+}
+
+func (c *targetAddConfig) addSubscription(rec subscriptionRecommendation) {
+	sc := map[string]interface{}{
+		"paths":       rec.paths,
+		"mode":        "stream",
+		"stream-mode": rec.streamMode,
+	}
+	if rec.sampleInterval > 0 {
+		sc["sample-interval"] = rec.sampleInterval.String()
+	}
+	c.Subscriptions[rec.name] = sc
+	for _, t := range c.Targets {
+		subs, _ := t["subscriptions"].([]string)
+		t["subscriptions"] = append(subs, rec.name)
+	}
+}
+
+// appendTargetConfig adds the targets and subscriptions of c to the YAML file.
+// It fails if one of the targets already exists, existing subscriptions
+// with the same name are kept.
+// The file is rewritten, the previous version is saved with a .bak suffix.
+func appendTargetConfig(file string, c *targetAddConfig) error {
+	var doc yaml.MapSlice
+	b, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", "":
+		default:
+			return fmt.Errorf("only YAML configuration files are supported: %q", file)
+		}
+		err = yaml.Unmarshal(b, &doc)
+		if err != nil {
+			return fmt.Errorf("failed to parse %q: %v", file, err)
+		}
+		err = os.WriteFile(file+".bak", b, 0600)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(c.Targets) {
+		if !addMapSliceItem(&doc, "targets", name, c.Targets[name]) {
+			return fmt.Errorf("target %q already exists in %q", name, file)
+		}
+	}
+	for _, name := range sortedKeys(c.Subscriptions) {
+		addMapSliceItem(&doc, "subscriptions", name, c.Subscriptions[name])
+	}
+	b, err = yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0600)
+}
+
+// addMapSliceItem adds v under doc[k][name], creating doc[k] if needed.
+// It returns false if doc[k][name] already exists.
+func addMapSliceItem(doc *yaml.MapSlice, k, name string, v interface{}) bool {
+	idx := -1
+	for i, item := range *doc {
+		if item.Key == k {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		*doc = append(*doc, yaml.MapItem{Key: k})
+		idx = len(*doc) - 1
+	}
+	ms, _ := (*doc)[idx].Value.(yaml.MapSlice)
+	for _, item := range ms {
+		if fmt.Sprint(item.Key) == name {
+			return false
+		}
+	}
+	(*doc)[idx].Value = append(ms, yaml.MapItem{Key: name, Value: v})
+	return true
+}
+
+func sortedKeys(m map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
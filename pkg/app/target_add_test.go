@@ -0,0 +1,136 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/gnmitest"
+)
+
+func TestDetectVendor(t *testing.T) {
+	tests := []struct {
+		models []*gnmi.ModelData
+		want   string
+	}{
+		{[]*gnmi.ModelData{{Name: "urn:srl_nokia/interfaces:srl_nokia-interfaces", Organization: "Nokia"}}, vendorNokiaSRL},
+		{[]*gnmi.ModelData{{Name: "nokia-state", Organization: "Nokia"}}, vendorNokiaSROS},
+		{[]*gnmi.ModelData{{Name: "openconfig-interfaces", Organization: "OpenConfig working group"}, {Name: "arista-exp-eos", Organization: "Arista Networks, Inc."}}, vendorArista},
+		{[]*gnmi.ModelData{{Name: "Cisco-IOS-XR-ifmgr-oper", Organization: "Cisco Systems, Inc."}}, vendorCisco},
+		{[]*gnmi.ModelData{{Name: "openconfig-interfaces", Organization: "OpenConfig working group"}}, vendorUnknown},
+	}
+	for _, tt := range tests {
+		if got := detectVendor(tt.models); got != tt.want {
+			t.Errorf("detectVendor(%v) = %q, want %q", tt.models, got, tt.want)
+		}
+	}
+}
+
+func TestRecommendedSubscriptions(t *testing.T) {
+	models := []*gnmi.ModelData{
+		{Name: "openconfig-interfaces"},
+		{Name: "openconfig-network-instance"},
+	}
+	recs := recommendedSubscriptions(vendorArista, models)
+	names := make([]string, 0, len(recs))
+	for _, r := range recs {
+		names = append(names, r.name)
+	}
+	want := "oc-interfaces-counters,oc-interfaces-oper-status,oc-bgp-neighbors-state"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("unexpected recommended subscriptions: got %q, want %q", got, want)
+	}
+	if recs := recommendedSubscriptions(vendorNokiaSRL, models); len(recs) != 0 {
+		t.Errorf("expected no recommendations without native models, got %v", recs)
+	}
+}
+
+func TestAppendTargetConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "gnmic.yaml")
+	err := os.WriteFile(file, []byte("username: admin\ntargets:\n  r1:\n    address: 10.0.0.1:57400\nsubscriptions:\n  oc-interfaces-counters:\n    paths:\n    - /interfaces\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &targetProbe{insecure: true, encodings: []string{"proto", "json"}}
+	c := newTargetAddConfig(&types.TargetConfig{Name: "r2", Address: "10.0.0.2:57400"}, p)
+	c.addSubscription(openconfigSubscriptions[0])
+	c.addSubscription(openconfigSubscriptions[1])
+	err = appendTargetConfig(file, c)
+	if err != nil {
+		t.Fatalf("failed to add target: %v", err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `username: admin
+targets:
+  r1:
+    address: 10.0.0.1:57400
+  r2:
+    address: 10.0.0.2:57400
+    encoding: json
+    insecure: true
+    subscriptions:
+    - oc-interfaces-counters
+    - oc-interfaces-oper-status
+subscriptions:
+  oc-interfaces-counters:
+    paths:
+    - /interfaces
+  oc-interfaces-oper-status:
+    mode: stream
+    paths:
+    - /interfaces/interface/state/oper-status
+    stream-mode: on_change
+`
+	if string(b) != want {
+		t.Errorf("unexpected config file:\n%s\nwant:\n%s", b, want)
+	}
+	if _, err := os.Stat(file + ".bak"); err != nil {
+		t.Errorf("missing backup file: %v", err)
+	}
+	// the same target can't be added twice
+	if err := appendTargetConfig(file, c); err == nil {
+		t.Errorf("expected an error adding an existing target")
+	}
+}
+
+func TestProbeTarget(t *testing.T) {
+	tg := gnmitest.StartTarget(t, gnmitest.WithModels(
+		&gnmi.ModelData{Name: "openconfig-interfaces"},
+		&gnmi.ModelData{Name: "nokia-state", Organization: "Nokia"},
+	))
+
+	a := New()
+	a.createCollectorDialOpts()
+	tc := &types.TargetConfig{Name: "r1", Address: tg.Address(), Timeout: 5 * time.Second}
+	start := time.Now()
+	p, err := a.probeTarget(context.Background(), tc)
+	if err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if time.Since(start) >= tc.Timeout {
+		t.Errorf("probe waited for the TLS attempts timeout")
+	}
+	if !p.insecure || p.vendor != vendorNokiaSROS || p.gnmiVersion != "0.10.0" {
+		t.Errorf("unexpected probe result: %+v", p)
+	}
+	if enc := preferredEncoding(p.encodings); enc != "json_ietf" {
+		t.Errorf("unexpected preferred encoding: %s", enc)
+	}
+}
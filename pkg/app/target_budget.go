@@ -0,0 +1,167 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+const (
+	budgetWindow = time.Second
+	// minimum time between two sample interval raises,
+	// it gives the raised subscriptions time to be re-established.
+	budgetRaiseHoldOff = 30 * time.Second
+	// meta key added to the messages exceeding the budget
+	// when the action is tag.
+	budgetThrottledMetaKey = "throttled"
+)
+
+// budgetMonitor measures the rate of the subscribe responses received
+// from a target configured with a budget.
+// It is not safe for concurrent use, it is owned by the target's collector loop.
+type budgetMonitor struct {
+	target      string
+	cfg         *types.BudgetConfig
+	windowStart time.Time
+	msgs        float64
+	bytes       float64
+	exceeded    bool
+	lastRaise   time.Time
+}
+
+// newBudgetMonitor returns nil if the target has no budget.
+func newBudgetMonitor(t *target.Target) *budgetMonitor {
+	if t.Config.Budget == nil {
+		return nil
+	}
+	return &budgetMonitor{
+		target: t.Config.Name,
+		cfg:    t.Config.Budget,
+	}
+}
+
+// add records a response of n bytes received at now.
+// It returns true if the response exceeds the budget and,
+// as second value, whether it is the first one to do so in the current window.
+func (m *budgetMonitor) add(n int, now time.Time) (bool, bool) {
+	if now.Sub(m.windowStart) >= budgetWindow {
+		m.windowStart = now
+		m.msgs = 0
+		m.bytes = 0
+		m.exceeded = false
+	}
+	m.msgs++
+	m.bytes += float64(n)
+	if !m.over() {
+		return false, false
+	}
+	first := !m.exceeded
+	m.exceeded = true
+	targetBudgetExceededMsgs.WithLabelValues(m.target, m.cfg.Action).Inc()
+	return true, first
+}
+
+func (m *budgetMonitor) over() bool {
+	w := budgetWindow.Seconds()
+	if m.cfg.MessagesPerSecond > 0 && m.msgs > m.cfg.MessagesPerSecond*w {
+		return true
+	}
+	return m.cfg.BytesPerSecond > 0 && m.bytes > m.cfg.BytesPerSecond*w
+}
+
+// canRaise reports whether the sample intervals can be raised at now,
+// and records the raise if so.
+func (m *budgetMonitor) canRaise(now time.Time) bool {
+	if !m.lastRaise.IsZero() && now.Sub(m.lastRaise) < budgetRaiseHoldOff {
+		return false
+	}
+	m.lastRaise = now
+	return true
+}
+
+// stop removes the target metrics.
+func (m *budgetMonitor) stop() {
+	targetBudgetExceededMsgs.DeleteLabelValues(m.target, m.cfg.Action)
+}
+
+// raisedSubscription returns a copy of sc with its sample intervals
+// multiplied by factor and bounded by max.
+// It returns nil if sc is not a sampled STREAM subscription
+// or if its sample intervals cannot be raised any further.
+func raisedSubscription(sc *types.SubscriptionConfig, factor float64, max time.Duration) *types.SubscriptionConfig {
+	if strings.ToUpper(sc.Mode) != "STREAM" {
+		return nil
+	}
+	nsc := *sc
+	var raised bool
+	nsc.SampleInterval, raised = raiseInterval(sc.SampleInterval, factor, max)
+	if len(sc.StreamSubscriptions) > 0 {
+		nsc.StreamSubscriptions = make([]*types.SubscriptionConfig, 0, len(sc.StreamSubscriptions))
+		for _, ssc := range sc.StreamSubscriptions {
+			nssc := *ssc
+			var ok bool
+			nssc.SampleInterval, ok = raiseInterval(ssc.SampleInterval, factor, max)
+			raised = raised || ok
+			nsc.StreamSubscriptions = append(nsc.StreamSubscriptions, &nssc)
+		}
+	}
+	if !raised {
+		return nil
+	}
+	return &nsc
+}
+
+func raiseInterval(d *time.Duration, factor float64, max time.Duration) (*time.Duration, bool) {
+	if d == nil || *d <= 0 {
+		return d, false
+	}
+	nd := time.Duration(float64(*d) * factor)
+	if max > 0 && nd > max {
+		nd = max
+	}
+	if nd <= *d {
+		return d, false
+	}
+	return &nd, true
+}
+
+// raiseSampleIntervals re-establishes the sampled STREAM subscriptions
+// of target t with their sample intervals raised as per the target budget.
+// The subscriptions are canceled when ctx is done.
+func (a *App) raiseSampleIntervals(ctx context.Context, t *target.Target) {
+	bc := t.Config.Budget
+	// the subscriptions might be modified concurrently,
+	// e.g by a schedule or a gNMI Set.
+	scs := t.SubscriptionConfigs()
+	names := make([]string, 0, len(scs))
+	for name := range scs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		nsc := raisedSubscription(scs[name], bc.IntervalFactor, bc.MaxSampleInterval)
+		if nsc == nil {
+			continue
+		}
+		req, err := a.Config.CreateSubscribeRequest(nsc, t.Config)
+		if err != nil {
+			a.Logger.Printf("target %q: subscription %s: failed to create subscribe request: %v", t.Config.Name, name, err)
+			continue
+		}
+		a.Logger.Printf("target %q: subscription %s: raising sample interval(s) by a factor of %v", t.Config.Name, name, bc.IntervalFactor)
+		t.SetSubscription(name, nsc)
+		go t.Subscribe(ctx, req, name)
+	}
+}
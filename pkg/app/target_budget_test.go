@@ -0,0 +1,149 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestBudgetMonitor(t *testing.T) {
+	if m := newBudgetMonitor(&target.Target{Config: &types.TargetConfig{Name: "router1"}}); m != nil {
+		t.Fatal("expected no budget monitor")
+	}
+	tg := &target.Target{
+		Config: &types.TargetConfig{
+			Name: "router1",
+			Budget: &types.BudgetConfig{
+				MessagesPerSecond: 3,
+				BytesPerSecond:    1000,
+				Action:            types.BudgetActionDrop,
+			},
+		},
+	}
+	m := newBudgetMonitor(tg)
+	if m == nil {
+		t.Fatal("expected a budget monitor")
+	}
+	defer m.stop()
+	start := time.Unix(0, 0)
+	for i := 0; i < 3; i++ {
+		if exceeded, _ := m.add(10, start.Add(time.Duration(i)*time.Millisecond)); exceeded {
+			t.Fatalf("message %d: unexpected budget exceeded", i)
+		}
+	}
+	exceeded, first := m.add(10, start.Add(10*time.Millisecond))
+	if !exceeded || !first {
+		t.Fatalf("expected the first message over the messages budget, got exceeded=%v first=%v", exceeded, first)
+	}
+	exceeded, first = m.add(10, start.Add(20*time.Millisecond))
+	if !exceeded || first {
+		t.Fatalf("expected a subsequent message over the messages budget, got exceeded=%v first=%v", exceeded, first)
+	}
+	// new window
+	next := start.Add(time.Second)
+	if exceeded, _ := m.add(10, next); exceeded {
+		t.Fatal("expected the budget to be reset in a new window")
+	}
+	exceeded, first = m.add(1000, next.Add(time.Millisecond))
+	if !exceeded || !first {
+		t.Fatalf("expected the first message over the bytes budget, got exceeded=%v first=%v", exceeded, first)
+	}
+	// raise hold off
+	if !m.canRaise(start) {
+		t.Fatal("expected the first raise to be allowed")
+	}
+	if m.canRaise(start.Add(budgetRaiseHoldOff / 2)) {
+		t.Fatal("expected a raise within the hold off to be refused")
+	}
+	if !m.canRaise(start.Add(budgetRaiseHoldOff)) {
+		t.Fatal("expected a raise after the hold off to be allowed")
+	}
+}
+
+func TestRaisedSubscription(t *testing.T) {
+	d := func(d time.Duration) *time.Duration { return &d }
+	tests := []struct {
+		name   string
+		sc     *types.SubscriptionConfig
+		max    time.Duration
+		want   *time.Duration
+		wantSS []*time.Duration
+	}{
+		{
+			name: "sampled",
+			sc:   &types.SubscriptionConfig{Mode: "stream", StreamMode: "sample", SampleInterval: d(10 * time.Second)},
+			want: d(20 * time.Second),
+		},
+		{
+			name: "bounded",
+			sc:   &types.SubscriptionConfig{Mode: "stream", StreamMode: "sample", SampleInterval: d(10 * time.Second)},
+			max:  15 * time.Second,
+			want: d(15 * time.Second),
+		},
+		{
+			name: "at_max",
+			sc:   &types.SubscriptionConfig{Mode: "stream", StreamMode: "sample", SampleInterval: d(15 * time.Second)},
+			max:  15 * time.Second,
+		},
+		{
+			name: "on_change",
+			sc:   &types.SubscriptionConfig{Mode: "stream", StreamMode: "on-change"},
+		},
+		{
+			name: "once",
+			sc:   &types.SubscriptionConfig{Mode: "once", SampleInterval: d(10 * time.Second)},
+		},
+		{
+			name: "stream_subscriptions",
+			sc: &types.SubscriptionConfig{
+				Mode: "stream",
+				StreamSubscriptions: []*types.SubscriptionConfig{
+					{StreamMode: "sample", SampleInterval: d(time.Second)},
+					{StreamMode: "on-change"},
+				},
+			},
+			wantSS: []*time.Duration{d(2 * time.Second), nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := *tt.sc
+			got := raisedSubscription(tt.sc, 2, tt.max)
+			if tt.want == nil && tt.wantSS == nil {
+				if got != nil {
+					t.Fatalf("expected no raised subscription, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a raised subscription")
+			}
+			if tt.want != nil && (got.SampleInterval == nil || *got.SampleInterval != *tt.want) {
+				t.Errorf("expected sample interval %s, got %v", *tt.want, got.SampleInterval)
+			}
+			for i, w := range tt.wantSS {
+				gi := got.StreamSubscriptions[i].SampleInterval
+				if (w == nil) != (gi == nil) || (w != nil && *w != *gi) {
+					t.Errorf("stream subscription %d: expected sample interval %v, got %v", i, w, gi)
+				}
+			}
+			if tt.sc.SampleInterval != orig.SampleInterval ||
+				(tt.sc.SampleInterval != nil && *tt.sc.SampleInterval != *orig.SampleInterval) {
+				t.Error("original subscription modified")
+			}
+			if len(tt.sc.StreamSubscriptions) > 0 && *tt.sc.StreamSubscriptions[0].SampleInterval != time.Second {
+				t.Error("original stream subscription modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/config"
+)
+
+// metadata keys set to the detected target platform.
+const (
+	vendorMetaKey    = "vendor"
+	osMetaKey        = "os"
+	osVersionMetaKey = "os-version"
+)
+
+// OpenConfig software version leaf, queried if the OS has no native version path.
+const openconfigVersionPath = "/system/state/software-version"
+
+// targetPlatform is the vendor, OS and OS version detected on a target.
+type targetPlatform struct {
+	Vendor  string
+	OS      string
+	Version string
+}
+
+// platformSignature identifies a platform by a substring
+// of the name of one of the YANG models it supports.
+type platformSignature struct {
+	model       string
+	vendor      string
+	os          string
+	versionPath string
+}
+
+// platformSignatures are checked in order, the first one matching
+// one of the target supported models is used.
+var platformSignatures = []platformSignature{
+	{model: "srl_nokia", vendor: "nokia", os: "srlinux", versionPath: "/system/information/version"},
+	{model: "srlinux", vendor: "nokia", os: "srlinux", versionPath: "/system/information/version"},
+	{model: "nokia-state", vendor: "nokia", os: "sros", versionPath: "/state/system/version/version-number"},
+	{model: "nokia-conf", vendor: "nokia", os: "sros", versionPath: "/state/system/version/version-number"},
+	{model: "Cisco-IOS-XR", vendor: "cisco", os: "iosxr"},
+	{model: "Cisco-IOS-XE", vendor: "cisco", os: "iosxe"},
+	{model: "Cisco-NX-OS", vendor: "cisco", os: "nxos"},
+	{model: "junos", vendor: "juniper", os: "junos"},
+	{model: "arista", vendor: "arista", os: "eos"},
+}
+
+// detectPlatform returns the signature of the platform supporting models, or nil if unknown.
+func detectPlatform(models []*gnmi.ModelData) *platformSignature {
+	for i := range platformSignatures {
+		for _, m := range models {
+			if strings.Contains(m.GetName(), platformSignatures[i].model) {
+				return &platformSignatures[i]
+			}
+		}
+	}
+	return nil
+}
+
+// detectTargetPlatform derives the vendor and OS of target t from its supported models,
+// then queries its OS version, if the target has auto-detect enabled.
+// The platform is detected once, on the first connection to the target.
+func (a *App) detectTargetPlatform(ctx context.Context, t *target.Target) {
+	if !t.Config.AutoDetect || a.getTargetPlatform(t.Config.Name) != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+	rsp, err := t.Capabilities(ctx)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to detect platform: %v", t.Config.Name, err)
+		return
+	}
+	sig := detectPlatform(rsp.GetSupportedModels())
+	if sig == nil {
+		a.Logger.Printf("target %q: failed to detect platform: no known model found", t.Config.Name)
+		return
+	}
+	p := &targetPlatform{Vendor: sig.vendor, OS: sig.os}
+	versionPath := sig.versionPath
+	if versionPath == "" {
+		versionPath = openconfigVersionPath
+	}
+	req, err := api.NewGetRequest(api.Path(versionPath), api.EncodingJSON())
+	if err != nil {
+		a.Logger.Printf("target %q: failed to create version Get request: %v", t.Config.Name, err)
+		return
+	}
+	if grsp, err := t.Get(ctx, req); err != nil {
+		a.Logger.Printf("target %q: failed to get OS version: %v", t.Config.Name, err)
+	} else {
+	OUTER:
+		for _, n := range grsp.GetNotification() {
+			for _, upd := range n.GetUpdate() {
+				if p.Version = hostnameFromValue(upd.GetVal()); p.Version != "" {
+					break OUTER
+				}
+			}
+		}
+	}
+	a.Logger.Printf("target %q: detected vendor=%q os=%q os-version=%q", t.Config.Name, p.Vendor, p.OS, p.Version)
+	a.operLock.Lock()
+	// the target might have been deleted in the meantime
+	if _, ok := a.Targets[t.Config.Name]; ok {
+		a.targetsPlatform[t.Config.Name] = p
+	}
+	a.operLock.Unlock()
+}
+
+func (a *App) getTargetPlatform(name string) *targetPlatform {
+	a.operLock.RLock()
+	defer a.operLock.RUnlock()
+	return a.targetsPlatform[name]
+}
+
+// deleteTargetPlatform removes the detected platform of target tName.
+// It must be called with the operLock held.
+func (a *App) deleteTargetPlatform(tName string) {
+	delete(a.targetsPlatform, tName)
+}
+
+// addTargetPlatformMeta adds the detected platform of target tc
+// to the metadata m, without overwriting the keys already set.
+func (a *App) addTargetPlatformMeta(m map[string]string, tc *types.TargetConfig) {
+	if !tc.AutoDetect {
+		return
+	}
+	p := a.getTargetPlatform(tc.Name)
+	if p == nil {
+		return
+	}
+	for k, v := range map[string]string{
+		vendorMetaKey:    p.Vendor,
+		osMetaKey:        p.OS,
+		osVersionMetaKey: p.Version,
+	} {
+		if _, ok := m[k]; !ok && v != "" {
+			m[k] = v
+		}
+	}
+}
+
+// usesAutoBundle reports whether the target subscribes to the bundle
+// matching its detected platform.
+func usesAutoBundle(tc *types.TargetConfig) bool {
+	if !tc.AutoDetect {
+		return false
+	}
+	for _, b := range tc.Bundles {
+		if b == config.AutoBundle {
+			return true
+		}
+	}
+	return false
+}
+
+// autoBundleRequests returns the subscribe requests of the bundle
+// matching the detected platform of target t, if the target uses the auto bundle.
+// The bundle subscriptions already known to the target are skipped.
+func (a *App) autoBundleRequests(t *target.Target) []subscriptionRequest {
+	if !usesAutoBundle(t.Config) {
+		return nil
+	}
+	p := a.getTargetPlatform(t.Config.Name)
+	if p == nil {
+		a.Logger.Printf("target %q: platform not detected, skipping the %q bundle", t.Config.Name, config.AutoBundle)
+		return nil
+	}
+	name, err := config.PlatformBundle(p.Vendor, p.OS)
+	if err != nil {
+		a.Logger.Printf("target %q: %v", t.Config.Name, err)
+		return nil
+	}
+	subs, err := a.Config.BundlesSubscriptions(name)
+	if err != nil {
+		a.Logger.Printf("target %q: %v", t.Config.Name, err)
+		return nil
+	}
+	a.Logger.Printf("target %q: using subscription bundle %q", t.Config.Name, name)
+	names := make([]string, 0, len(subs))
+	for n := range subs {
+		if _, ok := t.Subscriptions[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	r := make([]subscriptionRequest, 0, len(names))
+	for _, n := range names {
+		req, err := a.Config.CreateSubscribeRequest(subs[n], t.Config)
+		if err != nil {
+			a.Logger.Printf("target %q: subscription %s: failed to create subscribe request: %v", t.Config.Name, n, err)
+			continue
+		}
+		r = append(r, subscriptionRequest{name: n, req: req})
+	}
+	return r
+}
@@ -0,0 +1,62 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	for _, tt := range []struct {
+		models []string
+		os     string
+	}{
+		{[]string{"openconfig-interfaces", "urn:srl_nokia/interfaces"}, "srlinux"},
+		{[]string{"nokia-conf", "nokia-state"}, "sros"},
+		{[]string{"Cisco-IOS-XR-ifmgr-oper"}, "iosxr"},
+		{[]string{"junos-conf-root"}, "junos"},
+		{[]string{"openconfig-interfaces"}, ""},
+	} {
+		models := make([]*gnmi.ModelData, 0, len(tt.models))
+		for _, m := range tt.models {
+			models = append(models, &gnmi.ModelData{Name: m})
+		}
+		sig := detectPlatform(models)
+		var os string
+		if sig != nil {
+			os = sig.os
+		}
+		if os != tt.os {
+			t.Errorf("models %v: expected os %q, got %q", tt.models, tt.os, os)
+		}
+	}
+}
+
+func TestAddTargetPlatformMeta(t *testing.T) {
+	a := New()
+	a.targetsPlatform["r1"] = &targetPlatform{Vendor: "nokia", OS: "srlinux", Version: "v24.3.1"}
+	tc := &types.TargetConfig{Name: "r1", AutoDetect: true}
+	m := map[string]string{"source": "r1", "os": "custom"}
+	a.addTargetPlatformMeta(m, tc)
+	want := map[string]string{"source": "r1", "os": "custom", "vendor": "nokia", "os-version": "v24.3.1"}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("expected %v, got %v", want, m)
+	}
+	// not added if auto-detect is disabled
+	m = map[string]string{}
+	a.addTargetPlatformMeta(m, &types.TargetConfig{Name: "r1"})
+	if len(m) != 0 {
+		t.Errorf("unexpected metadata %v", m)
+	}
+}
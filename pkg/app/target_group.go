@@ -0,0 +1,283 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// targetResult is the outcome of a request sent to a single target.
+type targetResult struct {
+	name     string
+	duration time.Duration
+	count    int
+	err      error
+	// canceled is true if the request was canceled
+	// because another target failed, with --exit-policy fail-fast.
+	canceled bool
+}
+
+func (r *targetResult) status() string {
+	switch {
+	case r.canceled:
+		return "CANCELED"
+	case r.err != nil:
+		return "FAILED"
+	default:
+		return "OK"
+	}
+}
+
+// runOnTargets calls fn for each target in a.Config.Targets, with at most
+// maxTargets calls running at the same time.
+// If maxTargets is 0, a.Config.Concurrency applies (no limit if 0 as well).
+// If backoff is not zero, it waits backoff between the start of two calls.
+// With --exit-policy fail-fast, the first failure cancels the remaining calls.
+// With --progress, a progress bar is printed to stderr.
+// A per target summary is printed once all the calls returned,
+// to stdout if the format is summary or to stderr if the targets
+// were selected using --group.
+// A JSON report is written to the --error-report file, if set.
+func (a *App) runOnTargets(ctx context.Context, maxTargets uint, backoff time.Duration, fn func(context.Context, *types.TargetConfig) error) []*targetResult {
+	targets := a.Config.TargetsList()
+	if maxTargets == 0 {
+		maxTargets = a.Config.Concurrency
+	}
+	var sem chan struct{}
+	if maxTargets > 0 {
+		sem = make(chan struct{}, maxTargets)
+	}
+	var limiter *time.Ticker
+	if backoff > 0 {
+		limiter = time.NewTicker(backoff)
+		defer limiter.Stop()
+	}
+	failFast := a.Config.ExitPolicy == exitPolicyFailFast
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failed atomic.Bool
+
+	a.printLock.Lock()
+	a.respCounts = make(map[string]int)
+	a.printLock.Unlock()
+
+	var pb *progressBar
+	if a.Config.Progress {
+		pb = newProgressBar(os.Stderr, len(targets))
+	}
+	results := make(chan *targetResult, len(targets))
+	report := func(r *targetResult) {
+		pb.add(r.err != nil)
+		results <- r
+	}
+	wg := new(sync.WaitGroup)
+	for i, tc := range targets {
+		if limiter != nil && i > 0 {
+			select {
+			case <-limiter.C:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			report(&targetResult{name: tc.Name, err: ctx.Err(), canceled: failed.Load()})
+			continue
+		}
+		wg.Add(1)
+		go func(tc *types.TargetConfig) {
+			defer wg.Done()
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+				}
+				if ctx.Err() != nil {
+					report(&targetResult{name: tc.Name, err: ctx.Err(), canceled: failed.Load()})
+					return
+				}
+			}
+			start := time.Now()
+			err := fn(ctx, tc)
+			r := &targetResult{name: tc.Name, duration: time.Since(start), err: err}
+			if err != nil && failFast {
+				if failed.CompareAndSwap(false, true) {
+					cancel()
+				} else {
+					r.canceled = true
+				}
+			}
+			report(r)
+		}(tc)
+	}
+	wg.Wait()
+	close(results)
+
+	rs := make([]*targetResult, 0, len(targets))
+	a.printLock.Lock()
+	for r := range results {
+		r.count = a.respCounts[r.name]
+		rs = append(rs, r)
+	}
+	a.printLock.Unlock()
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].name < rs[j].name
+	})
+
+	if a.Config.ErrorReport != "" {
+		if err := writeTargetsReport(a.Config.ErrorReport, rs); err != nil {
+			a.Logger.Printf("failed to write error report: %v", err)
+			fmt.Fprintf(os.Stderr, "failed to write error report: %v\n", err)
+		}
+	}
+	switch {
+	case a.Config.Format == formatSummary:
+		printTargetResults(a.out, rs)
+	case len(a.Config.Group) > 0:
+		printTargetResults(os.Stderr, rs)
+	}
+	return rs
+}
+
+// checkTargetsErrors returns an error if the results
+// do not satisfy the configured exit policy.
+func (a *App) checkTargetsErrors(rs []*targetResult) error {
+	err := a.checkErrors()
+	if err == nil {
+		return nil
+	}
+	if a.Config.ExitPolicy == exitPolicyFailIfAll {
+		for _, r := range rs {
+			if r.err == nil {
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+func printTargetResults(w io.Writer, rs []*targetResult) {
+	tabData := make([][]string, 0, len(rs))
+	for _, r := range rs {
+		var errMsg string
+		if r.err != nil {
+			errMsg = r.err.Error()
+		}
+		tabData = append(tabData, []string{
+			r.name,
+			r.status(),
+			r.duration.Round(time.Millisecond).String(),
+			strconv.Itoa(r.count),
+			errMsg,
+		})
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Target", "Status", "Duration", "Count", "Error"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(tabData)
+	table.Render()
+}
+
+// responseCount returns the number of updates in a response message,
+// the number of results of a SetResponse or the number of supported models
+// of a CapabilityResponse.
+// It returns false if msg is not a response.
+func responseCount(msg proto.Message) (int, bool) {
+	switch msg := msg.(type) {
+	case *gnmi.GetResponse:
+		var n int
+		for _, notif := range msg.GetNotification() {
+			n += len(notif.GetUpdate()) + len(notif.GetDelete())
+		}
+		return n, true
+	case *gnmi.SetResponse:
+		return len(msg.GetResponse()), true
+	case *gnmi.CapabilityResponse:
+		return len(msg.GetSupportedModels()), true
+	case *gnmi.SubscribeResponse:
+		return len(msg.GetUpdate().GetUpdate()) + len(msg.GetUpdate().GetDelete()), true
+	}
+	return 0, false
+}
+
+type targetsReport struct {
+	Total     int             `json:"total"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Canceled  int             `json:"canceled,omitempty"`
+	Targets   []*targetReport `json:"targets"`
+}
+
+type targetReport struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Count    int    `json:"count"`
+	Error    string `json:"error,omitempty"`
+}
+
+// writeTargetsReport writes the results as a JSON document to file,
+// or to stdout/stderr if file is "stdout"/"stderr".
+func writeTargetsReport(file string, rs []*targetResult) error {
+	report := &targetsReport{
+		Total:   len(rs),
+		Targets: make([]*targetReport, 0, len(rs)),
+	}
+	for _, r := range rs {
+		tr := &targetReport{
+			Name:     r.name,
+			Status:   strings.ToLower(r.status()),
+			Duration: r.duration.String(),
+			Count:    r.count,
+		}
+		switch {
+		case r.canceled:
+			report.Canceled++
+		case r.err != nil:
+			report.Failed++
+		default:
+			report.Succeeded++
+		}
+		if r.err != nil {
+			tr.Error = r.err.Error()
+		}
+		report.Targets = append(report.Targets, tr)
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	switch file {
+	case "stdout":
+		_, err = os.Stdout.Write(b)
+	case "stderr":
+		_, err = os.Stderr.Write(b)
+	default:
+		err = os.WriteFile(file, b, 0644)
+	}
+	return err
+}
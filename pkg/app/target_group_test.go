@@ -0,0 +1,239 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestRunOnTargetsConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency uint
+		maxTargets  uint
+		want        int32
+	}{
+		{name: "global", concurrency: 2, want: 2},
+		{name: "per_command_override", concurrency: 4, maxTargets: 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			a.Config.Targets = make(map[string]*types.TargetConfig)
+			for _, n := range []string{"t1", "t2", "t3", "t4", "t5"} {
+				a.Config.Targets[n] = &types.TargetConfig{Name: n}
+			}
+			a.Config.Concurrency = tt.concurrency
+
+			var running, maxRunning, calls int32
+			a.runOnTargets(context.Background(), tt.maxTargets, 0, func(context.Context, *types.TargetConfig) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&calls, 1)
+				return nil
+			})
+			if calls != 5 {
+				t.Errorf("expected 5 calls, got %d", calls)
+			}
+			if maxRunning > tt.want {
+				t.Errorf("expected at most %d concurrent calls, got %d", tt.want, maxRunning)
+			}
+		})
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	buf := new(bytes.Buffer)
+	pb := newProgressBar(buf, 4)
+	pb.add(false)
+	pb.add(true)
+	pb.add(false)
+	pb.add(false)
+	lines := strings.Split(buf.String(), "\r")
+	last := lines[len(lines)-1]
+	want := "[" + strings.Repeat("=", progressBarWidth) + "] 4/4 targets, 1 failed\n"
+	if last != want {
+		t.Errorf("unexpected progress bar output: got %q, want %q", last, want)
+	}
+	if !strings.Contains(buf.String(), "] 2/4 targets, 1 failed") {
+		t.Errorf("progress bar output does not contain the intermediate state: %q", buf.String())
+	}
+	// a nil progress bar is a noop
+	var nilPb *progressBar
+	nilPb.add(true)
+}
+
+func TestPrintTargetResults(t *testing.T) {
+	buf := new(bytes.Buffer)
+	printTargetResults(buf, []*targetResult{
+		{name: "t1", duration: 10 * time.Millisecond, count: 3},
+		{name: "t2", duration: 20 * time.Millisecond, err: errors.New("deadline exceeded")},
+	})
+	out := buf.String()
+	t.Log("\n" + out)
+	if !strings.Contains(out, "| t1     | OK     | 10ms     | 3 ") {
+		t.Errorf("expected successful target in output")
+	}
+	if !strings.Contains(out, "FAILED") || !strings.Contains(out, "deadline exceeded") {
+		t.Errorf("expected failed target in output")
+	}
+}
+
+func TestRunOnTargetsSummary(t *testing.T) {
+	a := New()
+	buf := new(bytes.Buffer)
+	a.out = buf
+	a.Config.Format = formatSummary
+	a.Config.Targets = map[string]*types.TargetConfig{
+		"t1": {Name: "t1"},
+		"t2": {Name: "t2"},
+	}
+	a.runOnTargets(context.Background(), 0, 0, func(_ context.Context, tc *types.TargetConfig) error {
+		if tc.Name == "t2" {
+			return errors.New("connection refused")
+		}
+		return a.PrintMsg(tc.Name, "Get Response:", &gnmi.GetResponse{
+			Notification: []*gnmi.Notification{
+				{Update: []*gnmi.Update{{}, {}}, Delete: []*gnmi.Path{{}}},
+				{Update: []*gnmi.Update{{}}},
+			},
+		})
+	})
+	out := buf.String()
+	t.Log("\n" + out)
+	lines := strings.Split(out, "\n")
+	var t1, t2 string
+	for _, l := range lines {
+		switch {
+		case strings.Contains(l, "| t1 "):
+			t1 = l
+		case strings.Contains(l, "| t2 "):
+			t2 = l
+		}
+	}
+	if !strings.Contains(t1, "| OK ") || !strings.Contains(t1, "| 4 ") {
+		t.Errorf("unexpected t1 row: %q", t1)
+	}
+	if !strings.Contains(t2, "| FAILED ") || !strings.Contains(t2, "connection refused") {
+		t.Errorf("unexpected t2 row: %q", t2)
+	}
+	if strings.Contains(out, "notification") {
+		t.Errorf("the responses should not be printed in summary format")
+	}
+}
+
+func TestRunOnTargetsFailFast(t *testing.T) {
+	a := New()
+	a.Config.ExitPolicy = exitPolicyFailFast
+	a.Config.Concurrency = 1
+	a.Config.Targets = map[string]*types.TargetConfig{
+		"t1": {Name: "t1"},
+		"t2": {Name: "t2"},
+		"t3": {Name: "t3"},
+	}
+	var calls int32
+	rs := a.runOnTargets(context.Background(), 0, 0, func(ctx context.Context, tc *types.TargetConfig) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("failed")
+	})
+	if calls != 1 {
+		t.Errorf("expected a single call, got %d", calls)
+	}
+	var failed, canceled int
+	for _, r := range rs {
+		switch r.status() {
+		case "FAILED":
+			failed++
+		case "CANCELED":
+			canceled++
+		}
+	}
+	if failed != 1 || canceled != 2 {
+		t.Errorf("expected 1 failed and 2 canceled targets, got %d and %d", failed, canceled)
+	}
+}
+
+func TestCheckTargetsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		errs    []error
+		wantErr bool
+	}{
+		{name: "any_none_failed", policy: exitPolicyFailIfAny, errs: []error{nil, nil}},
+		{name: "any_one_failed", policy: exitPolicyFailIfAny, errs: []error{nil, errors.New("e")}, wantErr: true},
+		{name: "all_one_failed", policy: exitPolicyFailIfAll, errs: []error{nil, errors.New("e")}},
+		{name: "all_all_failed", policy: exitPolicyFailIfAll, errs: []error{errors.New("e"), errors.New("e")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			a.Config.ExitPolicy = tt.policy
+			a.errCh = make(chan error, len(tt.errs))
+			rs := make([]*targetResult, 0, len(tt.errs))
+			for _, err := range tt.errs {
+				if err != nil {
+					a.errCh <- err
+				}
+				rs = append(rs, &targetResult{err: err})
+			}
+			err := a.checkTargetsErrors(rs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got err=%v, wantErr=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteTargetsReport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.json")
+	err := writeTargetsReport(file, []*targetResult{
+		{name: "t1", duration: time.Second, count: 2},
+		{name: "t2", err: errors.New("connection refused")},
+		{name: "t3", err: context.Canceled, canceled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := new(targetsReport)
+	err = json.Unmarshal(b, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Succeeded != 1 || report.Failed != 1 || report.Canceled != 1 {
+		t.Errorf("unexpected report totals: %s", b)
+	}
+	if report.Targets[1].Status != "failed" || report.Targets[1].Error != "connection refused" {
+		t.Errorf("unexpected target report: %+v", report.Targets[1])
+	}
+}
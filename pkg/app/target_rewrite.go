@@ -0,0 +1,198 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/config"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const hostnameMetaKey = "hostname"
+
+func (a *App) initTargetRewrite() error {
+	if a.Config.TargetRewrite == nil || a.Config.TargetRewrite.Mode != config.TargetRewriteModeTemplate {
+		return nil
+	}
+	tpl, err := gtemplate.CreateTemplate("target-rewrite", a.Config.TargetRewrite.Template)
+	if err != nil {
+		return fmt.Errorf("failed to parse target-rewrite template: %v", err)
+	}
+	a.targetRewriteTpl = tpl.Funcs(outputs.TemplateFuncs)
+	return nil
+}
+
+func (a *App) learnsTargetHostname() bool {
+	return a.Config.TargetRewrite != nil && a.Config.TargetRewrite.HostnamePath != ""
+}
+
+// learnTargetHostname queries the target hostname using a gNMI Get RPC
+// if the target-rewrite configuration requires it.
+// It is meant to run in its own goroutine, the previously learned hostname
+// is kept until the new one is received.
+func (a *App) learnTargetHostname(ctx context.Context, t *target.Target) {
+	if !a.learnsTargetHostname() {
+		return
+	}
+	req, err := api.NewGetRequest(
+		api.Path(a.Config.TargetRewrite.HostnamePath),
+		api.EncodingJSON(),
+	)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to create hostname Get request: %v", t.Config.Name, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.Config.TargetRewrite.Timeout)
+	defer cancel()
+	rsp, err := t.Get(ctx, req)
+	if err != nil {
+		a.Logger.Printf("target %q: failed to get hostname: %v", t.Config.Name, err)
+		return
+	}
+	for _, n := range rsp.GetNotification() {
+		for _, upd := range n.GetUpdate() {
+			hostname := hostnameFromValue(upd.GetVal())
+			if hostname == "" {
+				continue
+			}
+			a.Logger.Printf("target %q: learned hostname %q", t.Config.Name, hostname)
+			a.operLock.Lock()
+			// the target might have been deleted in the meantime
+			if _, ok := a.Targets[t.Config.Name]; ok {
+				a.targetsHostname[t.Config.Name] = hostname
+			}
+			a.operLock.Unlock()
+			return
+		}
+	}
+	a.Logger.Printf("target %q: hostname not found under path %q", t.Config.Name, a.Config.TargetRewrite.HostnamePath)
+}
+
+// refreshTargetHostname marks the hostname of the target tName
+// to be learned again once the target sends a new response.
+// It is called when a subscription fails, since the target might
+// have been replaced or renamed before the subscription is re-established.
+func (a *App) refreshTargetHostname(tName string) {
+	if !a.learnsTargetHostname() {
+		return
+	}
+	a.operLock.Lock()
+	a.targetsHostnameRefresh[tName] = struct{}{}
+	a.operLock.Unlock()
+}
+
+// deleteTargetHostname removes the learned hostname of target tName.
+// It must be called with the operLock held.
+func (a *App) deleteTargetHostname(tName string) {
+	delete(a.targetsHostname, tName)
+	delete(a.targetsHostnameRefresh, tName)
+}
+
+func hostnameFromValue(tv *gnmi.TypedValue) string {
+	switch tv.GetValue().(type) {
+	case *gnmi.TypedValue_StringVal:
+		return tv.GetStringVal()
+	case *gnmi.TypedValue_AsciiVal:
+		return tv.GetAsciiVal()
+	case *gnmi.TypedValue_JsonVal, *gnmi.TypedValue_JsonIetfVal:
+		b := tv.GetJsonVal()
+		if len(b) == 0 {
+			b = tv.GetJsonIetfVal()
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return ""
+		}
+		switch v := v.(type) {
+		case string:
+			return v
+		case map[string]interface{}:
+			// a container holding a single leaf
+			if len(v) == 1 {
+				for _, vv := range v {
+					if s, ok := vv.(string); ok {
+						return s
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// rewriteTarget sets the prefix target of the notification in rsp
+// according to the target-rewrite configuration.
+// It is applied before the response is cached and exported.
+// If the target hostname has to be learned again, the Get RPC is sent
+// in the background and the previous hostname is used meanwhile.
+func (a *App) rewriteTarget(ctx context.Context, t *target.Target, rsp *gnmi.SubscribeResponse, m outputs.Meta) {
+	if a.Config.TargetRewrite == nil {
+		return
+	}
+	upd := rsp.GetUpdate()
+	if upd == nil {
+		return
+	}
+	tName := t.Config.Name
+	a.operLock.Lock()
+	hostname := a.targetsHostname[tName]
+	_, refresh := a.targetsHostnameRefresh[tName]
+	if refresh {
+		delete(a.targetsHostnameRefresh, tName)
+	}
+	a.operLock.Unlock()
+	if refresh {
+		go a.learnTargetHostname(ctx, t)
+	}
+
+	var value string
+	switch a.Config.TargetRewrite.Mode {
+	case config.TargetRewriteModeName:
+		value = tName
+	case config.TargetRewriteModeHostname:
+		value = hostname
+		if value == "" {
+			value = tName
+		}
+	case config.TargetRewriteModeTemplate:
+		if a.targetRewriteTpl == nil {
+			return
+		}
+		data := make(map[string]string, len(m)+1)
+		for k, v := range m {
+			data[k] = v
+		}
+		if hostname != "" {
+			data[hostnameMetaKey] = hostname
+		}
+		sb := new(strings.Builder)
+		err := a.targetRewriteTpl.Execute(sb, data)
+		if err != nil {
+			a.Logger.Printf("target %q: failed to execute target-rewrite template: %v", tName, err)
+			return
+		}
+		value = sb.String()
+	}
+	if value == "" {
+		return
+	}
+	if upd.Prefix == nil {
+		upd.Prefix = new(gnmi.Path)
+	}
+	upd.Prefix.Target = value
+}
@@ -0,0 +1,177 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+func TestHostnameFromValue(t *testing.T) {
+	tests := []struct {
+		name string
+		tv   *gnmi.TypedValue
+		want string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "string",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "r1"}},
+			want: "r1",
+		},
+		{
+			name: "ascii",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "r1"}},
+			want: "r1",
+		},
+		{
+			name: "json_string",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`"r1"`)}},
+			want: "r1",
+		},
+		{
+			name: "json_ietf_container",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"hostname":"r1"}`)}},
+			want: "r1",
+		},
+		{
+			name: "json_container_multiple_leaves",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{"hostname":"r1","domain":"lab"}`)}},
+		},
+		{
+			name: "invalid_json",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{`)}},
+		},
+		{
+			name: "uint",
+			tv:   &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostnameFromValue(tt.tv); got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		hostname string
+		rsp      *gnmi.SubscribeResponse
+		want     string
+	}{
+		{
+			name: "disabled",
+			rsp:  updateResponse("dev"),
+			want: "dev",
+		},
+		{
+			name: "name",
+			cfg:  "target-rewrite:\n  mode: name\n",
+			rsp:  updateResponse(""),
+			want: "r1",
+		},
+		{
+			name:     "hostname",
+			cfg:      "target-rewrite:\n  mode: hostname\n",
+			hostname: "router1",
+			rsp:      updateResponse("dev"),
+			want:     "router1",
+		},
+		{
+			name: "hostname_not_learned",
+			cfg:  "target-rewrite:\n  mode: hostname\n",
+			rsp:  updateResponse("dev"),
+			want: "r1",
+		},
+		{
+			name:     "template",
+			cfg:      "target-rewrite:\n  mode: template\n  template: '{{ index . \"site\" }}-{{ index . \"hostname\" }}'\n",
+			hostname: "router1",
+			rsp:      updateResponse(""),
+			want:     "dc1-router1",
+		},
+		{
+			name: "sync_response",
+			cfg:  "target-rewrite:\n  mode: name\n",
+			rsp:  &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTargetRewriteApp(t, tt.cfg)
+			if tt.hostname != "" {
+				a.targetsHostname["r1"] = tt.hostname
+			}
+			tg := target.NewTarget(&types.TargetConfig{Name: "r1"})
+			a.rewriteTarget(context.Background(), tg, tt.rsp, outputs.Meta{"source": "r1", "site": "dc1"})
+			if got := tt.rsp.GetUpdate().GetPrefix().GetTarget(); got != tt.want {
+				t.Errorf("got prefix target %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetHostnameCleanup(t *testing.T) {
+	a := newTargetRewriteApp(t, "target-rewrite:\n  mode: hostname\n")
+	a.targetsHostname["r1"] = "router1"
+
+	a.refreshTargetHostname("r1")
+	if _, ok := a.targetsHostnameRefresh["r1"]; !ok {
+		t.Fatalf("expected the hostname to be marked for refresh")
+	}
+	a.operLock.Lock()
+	a.deleteTargetHostname("r1")
+	a.operLock.Unlock()
+	if len(a.targetsHostname) != 0 || len(a.targetsHostnameRefresh) != 0 {
+		t.Errorf("expected the target hostname to be deleted: %v, %v", a.targetsHostname, a.targetsHostnameRefresh)
+	}
+}
+
+func newTargetRewriteApp(t *testing.T, cfg string) *App {
+	t.Helper()
+	a := New()
+	a.Config.FileConfig.SetConfigType("yaml")
+	err := a.Config.FileConfig.ReadConfig(strings.NewReader(cfg))
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if err = a.Config.GetTargetRewrite(); err != nil {
+		t.Fatalf("failed to get target-rewrite config: %v", err)
+	}
+	if err = a.initTargetRewrite(); err != nil {
+		t.Fatalf("failed to init target-rewrite: %v", err)
+	}
+	return a
+}
+
+func updateResponse(tg string) *gnmi.SubscribeResponse {
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{},
+		},
+	}
+	if tg != "" {
+		rsp.GetUpdate().Prefix = &gnmi.Path{Target: tg}
+	}
+	return rsp
+}
@@ -0,0 +1,93 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// scheduledSubscription returns the subscription name of target tc
+// as per the schedule profile.
+func scheduledSubscription(tc *types.TargetConfig, profile, name string, sc *types.SubscriptionConfig) *types.SubscriptionConfig {
+	if tc.Schedule == nil {
+		return sc
+	}
+	return tc.Schedule.Profiles[profile].Apply(name, sc)
+}
+
+// targetScheduleProfile returns the schedule profile of target tc at now,
+// or an empty string if the target has no schedule.
+func targetScheduleProfile(tc *types.TargetConfig, now time.Time) string {
+	if tc.Schedule == nil {
+		return ""
+	}
+	return tc.Schedule.Profile(now)
+}
+
+// watchTargetSchedule checks the schedule of target t every minute
+// and re-establishes its subscriptions when the schedule switches to another profile.
+// subs are the subscriptions as configured, profile is the one they were established with.
+func (a *App) watchTargetSchedule(ctx context.Context, t *target.Target, subs map[string]*types.SubscriptionConfig, profile string) {
+	if t.Config.Schedule == nil {
+		return
+	}
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now = <-timer.C:
+		}
+		next := t.Config.Schedule.Profile(now)
+		if next == profile {
+			continue
+		}
+		a.Logger.Printf("target %q: switching subscriptions from schedule profile %q to %q", t.Config.Name, profile, next)
+		a.resubscribeProfile(ctx, t, subs, profile, next)
+		profile = next
+	}
+}
+
+// resubscribeProfile re-establishes the STREAM and POLL subscriptions of target t
+// that differ between profiles from and to.
+func (a *App) resubscribeProfile(ctx context.Context, t *target.Target, subs map[string]*types.SubscriptionConfig, from, to string) {
+	names := make([]string, 0, len(subs))
+	for name := range subs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sc := subs[name]
+		if strings.ToUpper(sc.Mode) == "ONCE" {
+			continue
+		}
+		nsc := scheduledSubscription(t.Config, to, name, sc)
+		if nsc == scheduledSubscription(t.Config, from, name, sc) {
+			continue
+		}
+		if nsc.ExpandWildcards != nil {
+			go a.subscribeExpanded(ctx, t, name, nsc)
+			continue
+		}
+		req, err := a.Config.CreateSubscribeRequest(nsc, t.Config)
+		if err != nil {
+			a.Logger.Printf("target %q: subscription %s: failed to create subscribe request: %v", t.Config.Name, name, err)
+			continue
+		}
+		go t.Subscribe(ctx, req, name)
+	}
+}
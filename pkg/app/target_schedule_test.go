@@ -0,0 +1,192 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func newTestSchedule(t *testing.T) *types.ScheduleConfig {
+	sc := &types.ScheduleConfig{
+		Timezone: "UTC",
+		Windows: []*types.ScheduleWindow{
+			{Profile: "reduced", Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "18:00"},
+			{Profile: "night", Days: []string{"sat"}, Start: "22:00", End: "06:00"},
+		},
+		Profiles: map[string]*types.SubscriptionProfile{
+			"reduced": {
+				SampleInterval: durationPtr(time.Minute),
+				Subscriptions: map[string]*types.SubscriptionOverride{
+					"interfaces": {Paths: []string{"/interfaces/interface/state/oper-status"}},
+					"cpu":        {SampleInterval: durationPtr(5 * time.Minute)},
+				},
+			},
+			"night": {SampleInterval: durationPtr(30 * time.Second)},
+		},
+	}
+	if err := sc.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	return sc
+}
+
+func TestScheduleProfile(t *testing.T) {
+	sc := newTestSchedule(t)
+	tests := []struct {
+		time string
+		want string
+	}{
+		// Monday
+		{time: "2024-06-03T07:59:59Z", want: types.ScheduleProfileFull},
+		{time: "2024-06-03T08:00:00Z", want: "reduced"},
+		{time: "2024-06-03T17:59:00Z", want: "reduced"},
+		{time: "2024-06-03T18:00:00Z", want: types.ScheduleProfileFull},
+		// Saturday
+		{time: "2024-06-08T12:00:00Z", want: types.ScheduleProfileFull},
+		{time: "2024-06-08T22:30:00Z", want: "night"},
+		// Sunday, the night window started on Saturday
+		{time: "2024-06-09T05:59:00Z", want: "night"},
+		{time: "2024-06-09T06:00:00Z", want: types.ScheduleProfileFull},
+		{time: "2024-06-09T22:30:00Z", want: types.ScheduleProfileFull},
+		// Monday morning, the night window does not start on Sunday
+		{time: "2024-06-10T01:00:00Z", want: types.ScheduleProfileFull},
+	}
+	for _, tt := range tests {
+		now, err := time.Parse(time.RFC3339, tt.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sc.Profile(now); got != tt.want {
+			t.Errorf("%s: got profile %q, want %q", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleTimezone(t *testing.T) {
+	sc := &types.ScheduleConfig{
+		Timezone: "Asia/Tokyo",
+		Windows: []*types.ScheduleWindow{
+			{Profile: "reduced", Start: "09:00", End: "17:00"},
+		},
+		Profiles: map[string]*types.SubscriptionProfile{"reduced": {}},
+	}
+	if err := sc.Validate(); err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	// 10:00 in Tokyo
+	now := time.Date(2024, 6, 3, 1, 0, 0, 0, time.UTC)
+	if got := sc.Profile(now); got != "reduced" {
+		t.Fatalf("got profile %q, want %q", got, "reduced")
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	tests := map[string]*types.ScheduleConfig{
+		"no windows": {},
+		"unknown window profile": {
+			Windows: []*types.ScheduleWindow{{Profile: "reduced", Start: "08:00", End: "18:00"}},
+		},
+		"unknown default profile": {
+			DefaultProfile: "reduced",
+			Windows:        []*types.ScheduleWindow{{Profile: "full", Start: "08:00", End: "18:00"}},
+		},
+		"invalid start": {
+			Windows:  []*types.ScheduleWindow{{Profile: "reduced", Start: "8h", End: "18:00"}},
+			Profiles: map[string]*types.SubscriptionProfile{"reduced": {}},
+		},
+		"equal start and end": {
+			Windows:  []*types.ScheduleWindow{{Profile: "reduced", Start: "08:00", End: "08:00"}},
+			Profiles: map[string]*types.SubscriptionProfile{"reduced": {}},
+		},
+		"unknown day": {
+			Windows:  []*types.ScheduleWindow{{Profile: "reduced", Days: []string{"monday"}, Start: "08:00", End: "18:00"}},
+			Profiles: map[string]*types.SubscriptionProfile{"reduced": {}},
+		},
+		"reserved profile name": {
+			Windows:  []*types.ScheduleWindow{{Profile: "full", Start: "08:00", End: "18:00"}},
+			Profiles: map[string]*types.SubscriptionProfile{"full": {}},
+		},
+		"unknown timezone": {
+			Timezone: "Mars/Olympus_Mons",
+			Windows:  []*types.ScheduleWindow{{Profile: "full", Start: "08:00", End: "18:00"}},
+		},
+	}
+	for name, sc := range tests {
+		if err := sc.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestScheduledSubscription(t *testing.T) {
+	tc := &types.TargetConfig{Name: "router1", Schedule: newTestSchedule(t)}
+	ifaces := &types.SubscriptionConfig{
+		Name:           "interfaces",
+		Paths:          []string{"/interfaces"},
+		Mode:           "stream",
+		StreamMode:     "sample",
+		SampleInterval: durationPtr(10 * time.Second),
+	}
+	cpu := &types.SubscriptionConfig{
+		Name: "cpu",
+		Mode: "stream",
+		StreamSubscriptions: []*types.SubscriptionConfig{
+			{Paths: []string{"/system/cpus"}, StreamMode: "sample", SampleInterval: durationPtr(10 * time.Second)},
+			{Paths: []string{"/system/state"}, StreamMode: "on-change"},
+		},
+	}
+	onChange := &types.SubscriptionConfig{
+		Name:       "bgp",
+		Paths:      []string{"/network-instances"},
+		Mode:       "stream",
+		StreamMode: "on-change",
+	}
+	// the full profile uses the subscriptions as configured
+	for _, sc := range []*types.SubscriptionConfig{ifaces, cpu, onChange} {
+		if got := scheduledSubscription(tc, types.ScheduleProfileFull, sc.Name, sc); got != sc {
+			t.Errorf("%s: expected the configured subscription with the full profile", sc.Name)
+		}
+	}
+	// reduced profile
+	got := scheduledSubscription(tc, "reduced", ifaces.Name, ifaces)
+	if !reflect.DeepEqual(got.Paths, []string{"/interfaces/interface/state/oper-status"}) {
+		t.Errorf("interfaces: unexpected paths %v", got.Paths)
+	}
+	if *got.SampleInterval != time.Minute {
+		t.Errorf("interfaces: got sample interval %s, want %s", *got.SampleInterval, time.Minute)
+	}
+	if *ifaces.SampleInterval != 10*time.Second || len(ifaces.Paths) != 1 || ifaces.Paths[0] != "/interfaces" {
+		t.Error("interfaces: the configured subscription was modified")
+	}
+	got = scheduledSubscription(tc, "reduced", cpu.Name, cpu)
+	if *got.StreamSubscriptions[0].SampleInterval != 5*time.Minute {
+		t.Errorf("cpu: got sample interval %s, want %s", *got.StreamSubscriptions[0].SampleInterval, 5*time.Minute)
+	}
+	if got.StreamSubscriptions[1].SampleInterval != nil {
+		t.Error("cpu: expected the on-change stream subscription to be left unchanged")
+	}
+	if *cpu.StreamSubscriptions[0].SampleInterval != 10*time.Second {
+		t.Error("cpu: the configured subscription was modified")
+	}
+	if got := scheduledSubscription(tc, "reduced", onChange.Name, onChange); got != onChange {
+		t.Error("bgp: expected the on-change subscription to be left unchanged")
+	}
+	// no schedule
+	if got := scheduledSubscription(&types.TargetConfig{Name: "router2"}, "", ifaces.Name, ifaces); got != ifaces {
+		t.Error("expected the configured subscription for a target without a schedule")
+	}
+}
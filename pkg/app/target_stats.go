@@ -0,0 +1,329 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+const (
+	gnmicOrigin = "gnmic"
+
+	defaultStatisticsSampleInterval = 10 * time.Second
+)
+
+// targetStatistics counts the messages received from a target,
+// they are kept until the target is deleted.
+type targetStatistics struct {
+	m             *sync.Mutex
+	notifications uint64
+	updates       uint64
+	deletes       uint64
+	syncResponses uint64
+	errors        uint64
+	// unix nano timestamp of the last received notification.
+	lastNotification int64
+	// time the statistics started being recorded.
+	since time.Time
+}
+
+// targetStatisticsResponse is the API representation of a target statistics,
+// rate is the average number of notifications per second since the
+// statistics started being recorded, it is the input of the cluster planner.
+type targetStatisticsResponse struct {
+	Notifications    uint64    `json:"notifications"`
+	Updates          uint64    `json:"updates"`
+	Deletes          uint64    `json:"deletes"`
+	SyncResponses    uint64    `json:"sync-responses"`
+	Errors           uint64    `json:"errors"`
+	LastNotification int64     `json:"last-notification,omitempty"`
+	Since            time.Time `json:"since"`
+	Rate             float64   `json:"rate"`
+}
+
+// targetStats returns the statistics of target name, creating them if needed.
+func (a *App) targetStats(name string) *targetStatistics {
+	a.operLock.Lock()
+	defer a.operLock.Unlock()
+	st, ok := a.targetsStats[name]
+	if !ok {
+		st = &targetStatistics{m: new(sync.Mutex), since: time.Now()}
+		a.targetsStats[name] = st
+	}
+	return st
+}
+
+// deleteTargetStats removes the statistics of target name.
+// It must be called with the operLock held.
+func (a *App) deleteTargetStats(name string) {
+	delete(a.targetsStats, name)
+}
+
+// received records the subscribe response rsp.
+func (st *targetStatistics) received(rsp *gnmi.SubscribeResponse, now time.Time) {
+	st.m.Lock()
+	defer st.m.Unlock()
+	switch rsp := rsp.GetResponse().(type) {
+	case *gnmi.SubscribeResponse_Update:
+		st.notifications++
+		st.updates += uint64(len(rsp.Update.GetUpdate()))
+		st.deletes += uint64(len(rsp.Update.GetDelete()))
+		st.lastNotification = now.UnixNano()
+	case *gnmi.SubscribeResponse_SyncResponse:
+		st.syncResponses++
+	}
+}
+
+// failed records a subscription stream error.
+func (st *targetStatistics) failed() {
+	st.m.Lock()
+	defer st.m.Unlock()
+	st.errors++
+}
+
+// response returns the API representation of the statistics at time now.
+func (st *targetStatistics) response(now time.Time) *targetStatisticsResponse {
+	st.m.Lock()
+	defer st.m.Unlock()
+	rsp := &targetStatisticsResponse{
+		Notifications:    st.notifications,
+		Updates:          st.updates,
+		Deletes:          st.deletes,
+		SyncResponses:    st.syncResponses,
+		Errors:           st.errors,
+		LastNotification: st.lastNotification,
+		Since:            st.since,
+	}
+	if d := now.Sub(st.since).Seconds(); d > 0 {
+		rsp.Rate = float64(st.notifications) / d
+	}
+	return rsp
+}
+
+// notification returns the statistics of target name as a gNMI notification
+// under the gnmic origin path targets[name=<name>]/statistics.
+func (st *targetStatistics) notification(name string, now time.Time) *gnmi.Notification {
+	st.m.Lock()
+	defer st.m.Unlock()
+	counters := []struct {
+		name string
+		val  uint64
+	}{
+		{"notifications", st.notifications},
+		{"updates", st.updates},
+		{"deletes", st.deletes},
+		{"sync-responses", st.syncResponses},
+		{"errors", st.errors},
+	}
+	n := &gnmi.Notification{
+		Timestamp: now.UnixNano(),
+		Prefix: &gnmi.Path{
+			Origin: gnmicOrigin,
+			Elem: []*gnmi.PathElem{
+				{Name: "targets", Key: map[string]string{"name": name}},
+				{Name: "statistics"},
+			},
+		},
+		Update: make([]*gnmi.Update, 0, len(counters)+1),
+	}
+	for _, c := range counters {
+		n.Update = append(n.Update, &gnmi.Update{
+			Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: c.name}}},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: c.val}},
+		})
+	}
+	if st.lastNotification > 0 {
+		n.Update = append(n.Update, &gnmi.Update{
+			Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "last-notification"}}},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: st.lastNotification}},
+		})
+	}
+	return n
+}
+
+// targetsStatisticsNames returns the sorted names of the configured targets
+// matching name, or all of them if name is empty or "*".
+func (a *App) targetsStatisticsNames(name string) []string {
+	a.configLock.RLock()
+	names := make([]string, 0, len(a.Config.Targets))
+	for n := range a.Config.Targets {
+		if name == "" || name == "*" || n == name {
+			names = append(names, n)
+		}
+	}
+	a.configLock.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+func (a *App) handleTargetsStatisticsGet(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	names := a.targetsStatisticsNames(id)
+	if id != "" && len(names) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIErrors{Errors: []string{fmt.Sprintf("target %q not found", id)}})
+		return
+	}
+	now := time.Now()
+	if id != "" {
+		a.handlerCommonGet(w, a.targetStats(id).response(now))
+		return
+	}
+	stats := make(map[string]*targetStatisticsResponse, len(names))
+	for _, n := range names {
+		stats[n] = a.targetStats(n).response(now)
+	}
+	a.handlerCommonGet(w, stats)
+}
+
+// targetsStatisticsNotifications returns the statistics of the configured targets
+// matching name, or of all of them if name is empty or "*".
+func (a *App) targetsStatisticsNotifications(name string, now time.Time) []*gnmi.Notification {
+	names := a.targetsStatisticsNames(name)
+	ns := make([]*gnmi.Notification, 0, len(names))
+	for _, n := range names {
+		ns = append(ns, a.targetStats(n).notification(n, now))
+	}
+	return ns
+}
+
+// statisticsSubscription is a subscription to the gnmic origin
+// path targets[name=<target>]/statistics.
+type statisticsSubscription struct {
+	target   string
+	interval time.Duration
+}
+
+// isGNMIcSubscription reports whether the subscription list req
+// is a subscription to the gnmic origin.
+// Combining the gnmic origin with other origins is not supported.
+func isGNMIcSubscription(req *gnmi.SubscriptionList) (bool, error) {
+	var gnmicPaths, others int
+	for _, sub := range req.GetSubscription() {
+		origin := sub.GetPath().GetOrigin()
+		if origin == "" {
+			origin = req.GetPrefix().GetOrigin()
+		}
+		if origin == gnmicOrigin {
+			gnmicPaths++
+		} else {
+			others++
+		}
+	}
+	if gnmicPaths > 0 && others > 0 {
+		return false, status.Errorf(codes.InvalidArgument, "combining `gnmic` origin with other origin values is not supported")
+	}
+	return gnmicPaths > 0, nil
+}
+
+// statisticsSubscriptions validates the gnmic origin subscriptions of req.
+func (a *App) statisticsSubscriptions(req *gnmi.SubscriptionList) ([]*statisticsSubscription, error) {
+	subs := make([]*statisticsSubscription, 0, len(req.GetSubscription()))
+	for _, sub := range req.GetSubscription() {
+		elems := path.PathElems(req.GetPrefix(), sub.GetPath())
+		if len(elems) != 2 || elems[0].GetName() != "targets" || elems[1].GetName() != "statistics" {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported gnmic origin subscription path %q",
+				path.GnmiPathToXPath(&gnmi.Path{Elem: elems}, false))
+		}
+		interval := time.Duration(sub.GetSampleInterval())
+		if interval == 0 {
+			interval = defaultStatisticsSampleInterval
+		} else if interval < a.Config.GnmiServer.MinSampleInterval {
+			interval = a.Config.GnmiServer.MinSampleInterval
+		}
+		subs = append(subs, &statisticsSubscription{
+			target:   elems[0].GetKey()["name"],
+			interval: interval,
+		})
+	}
+	return subs, nil
+}
+
+// handleStatisticsSubscription serves a ONCE or STREAM subscription to
+// the gnmic origin targets statistics, the STREAM subscriptions
+// are sampled at their sample interval.
+func (a *App) handleStatisticsSubscription(sc *streamClient) {
+	defer close(sc.errChan)
+	req := sc.req.GetSubscribe()
+	mode := req.GetMode()
+	if mode == gnmi.SubscriptionList_POLL {
+		sc.errChan <- status.Errorf(codes.Unimplemented, "POLL subscriptions to the gnmic origin are not supported")
+		return
+	}
+	subs, err := a.statisticsSubscriptions(req)
+	if err != nil {
+		sc.errChan <- err
+		return
+	}
+	ctx := sc.stream.Context()
+	m := new(sync.Mutex)
+	send := func(rsp *gnmi.SubscribeResponse) error {
+		m.Lock()
+		defer m.Unlock()
+		return sc.stream.Send(rsp)
+	}
+	sendStats := func(sub *statisticsSubscription) error {
+		for _, n := range a.targetsStatisticsNotifications(sub.target, time.Now()) {
+			err := send(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: n}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !req.GetUpdatesOnly() {
+		for _, sub := range subs {
+			if err := sendStats(sub); err != nil {
+				sc.errChan <- err
+				return
+			}
+		}
+	}
+	err = send(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}})
+	if err != nil || mode == gnmi.SubscriptionList_ONCE {
+		sc.errChan <- err
+		return
+	}
+	errs := make(chan error, len(subs))
+	for _, sub := range subs {
+		go func(sub *statisticsSubscription) {
+			ticker := time.NewTicker(sub.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := sendStats(sub); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}
+		}(sub)
+	}
+	select {
+	case <-ctx.Done():
+		sc.errChan <- ctx.Err()
+	case err := <-errs:
+		sc.errChan <- err
+	}
+}
@@ -0,0 +1,204 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// fakeSubscribeServer records the responses sent to a gNMI Subscribe client.
+type fakeSubscribeServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	m    sync.Mutex
+	rsps []*gnmi.SubscribeResponse
+}
+
+func (s *fakeSubscribeServer) Context() context.Context {
+	return peer.NewContext(s.ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}})
+}
+
+func (s *fakeSubscribeServer) Send(rsp *gnmi.SubscribeResponse) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.rsps = append(s.rsps, rsp)
+	return nil
+}
+
+func (s *fakeSubscribeServer) Recv() (*gnmi.SubscribeRequest, error) { return nil, io.EOF }
+
+func (s *fakeSubscribeServer) responses() []*gnmi.SubscribeResponse {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]*gnmi.SubscribeResponse(nil), s.rsps...)
+}
+
+func statisticsRequest(mode gnmi.SubscriptionList_Mode, target string, interval time.Duration) *gnmi.SubscribeRequest {
+	return &gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{
+				Prefix: &gnmi.Path{Origin: "gnmic"},
+				Mode:   mode,
+				Subscription: []*gnmi.Subscription{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{
+							{Name: "targets", Key: map[string]string{"name": target}},
+							{Name: "statistics"},
+						}},
+						Mode:           gnmi.SubscriptionMode_SAMPLE,
+						SampleInterval: uint64(interval),
+					},
+				},
+			},
+		},
+	}
+}
+
+func testStatisticsApp(t *testing.T) *App {
+	t.Helper()
+	a := testSetRetryApp(t, nil)
+	a.configLock = new(sync.RWMutex)
+	a.operLock = new(sync.RWMutex)
+	a.targetsStats = make(map[string]*targetStatistics)
+	a.Logger = log.New(io.Discard, "", 0)
+	a.Config.Targets = map[string]*types.TargetConfig{
+		"r1": {Name: "r1"},
+		"r2": {Name: "r2"},
+	}
+	a.targetStats("r1").received(&gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{
+			Update: []*gnmi.Update{{}, {}},
+			Delete: []*gnmi.Path{{}},
+		}},
+	}, time.Now())
+	a.targetStats("r1").received(&gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+	}, time.Now())
+	a.targetStats("r1").failed()
+	return a
+}
+
+func TestStatisticsSubscriptionOnce(t *testing.T) {
+	a := testStatisticsApp(t)
+	stream := &fakeSubscribeServer{ctx: context.Background()}
+	err := a.serverSubscribeHandler(statisticsRequest(gnmi.SubscriptionList_ONCE, "*", 0), stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rsps := stream.responses()
+	if len(rsps) != 3 || !rsps[2].GetSyncResponse() {
+		t.Fatalf("expected 2 notifications and a sync response, got %v", rsps)
+	}
+	n := rsps[0].GetUpdate()
+	if n.GetPrefix().GetOrigin() != "gnmic" || n.GetPrefix().GetElem()[0].GetKey()["name"] != "r1" {
+		t.Fatalf("unexpected notification prefix: %v", n.GetPrefix())
+	}
+	want := map[string]uint64{"notifications": 1, "updates": 2, "deletes": 1, "sync-responses": 1, "errors": 1}
+	for _, u := range n.GetUpdate() {
+		name := u.GetPath().GetElem()[0].GetName()
+		if w, ok := want[name]; ok && u.GetVal().GetUintVal() != w {
+			t.Errorf("%s: expected %d, got %d", name, w, u.GetVal().GetUintVal())
+		}
+	}
+}
+
+func TestStatisticsSubscriptionStream(t *testing.T) {
+	a := testStatisticsApp(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeSubscribeServer{ctx: ctx}
+	done := make(chan error)
+	go func() {
+		done <- a.serverSubscribeHandler(statisticsRequest(gnmi.SubscriptionList_STREAM, "r2", 10*time.Millisecond), stream)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for len(stream.responses()) < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected periodic notifications, got %v", stream.responses())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	rsps := stream.responses()
+	if !rsps[1].GetSyncResponse() {
+		t.Fatalf("expected a sync response after the initial notification, got %v", rsps[1])
+	}
+	for _, i := range []int{0, 2, 3} {
+		if name := rsps[i].GetUpdate().GetPrefix().GetElem()[0].GetKey()["name"]; name != "r2" {
+			t.Errorf("unexpected target %q", name)
+		}
+	}
+}
+
+func TestStatisticsSubscriptionErrors(t *testing.T) {
+	a := testStatisticsApp(t)
+	mixed := statisticsRequest(gnmi.SubscriptionList_ONCE, "*", 0)
+	mixed.GetSubscribe().Subscription = append(mixed.GetSubscribe().Subscription,
+		&gnmi.Subscription{Path: &gnmi.Path{Origin: "openconfig", Elem: []*gnmi.PathElem{{Name: "interfaces"}}}})
+	unknown := statisticsRequest(gnmi.SubscriptionList_ONCE, "*", 0)
+	unknown.GetSubscribe().GetSubscription()[0].Path.Elem[1].Name = "config"
+	for name, req := range map[string]*gnmi.SubscribeRequest{"mixed": mixed, "unknown": unknown} {
+		err := a.serverSubscribeHandler(req, &fakeSubscribeServer{ctx: context.Background()})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected an InvalidArgument error, got %v", name, err)
+		}
+	}
+}
+
+func TestHandleTargetsStatisticsGet(t *testing.T) {
+	a := testStatisticsApp(t)
+
+	w := httptest.NewRecorder()
+	a.handleTargetsStatisticsGet(w, httptest.NewRequest(http.MethodGet, "/api/v1/targets/statistics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	stats := make(map[string]*targetStatisticsResponse)
+	err := json.Unmarshal(w.Body.Bytes(), &stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 targets statistics, got %d", len(stats))
+	}
+	r1 := stats["r1"]
+	if r1.Notifications != 1 || r1.Updates != 2 || r1.Deletes != 1 || r1.SyncResponses != 1 || r1.Errors != 1 {
+		t.Fatalf("unexpected r1 statistics: %+v", r1)
+	}
+	if r1.Rate <= 0 {
+		t.Fatalf("expected a positive r1 rate, got %f", r1.Rate)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/targets/r3/statistics", nil)
+	r = mux.SetURLVars(r, map[string]string{"id": "r3"})
+	w = httptest.NewRecorder()
+	a.handleTargetsStatisticsGet(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,175 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+func TestAddTargetMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		meta map[string]string
+		tc   *types.TargetConfig
+		want map[string]string
+	}{
+		{
+			name: "no_labels",
+			meta: map[string]string{"source": "r1"},
+			tc:   &types.TargetConfig{Name: "r1"},
+			want: map[string]string{"source": "r1"},
+		},
+		{
+			name: "labels",
+			meta: map[string]string{"source": "r1"},
+			tc: &types.TargetConfig{
+				Name:   "r1",
+				Labels: map[string]string{"site": "dc1", "role": "spine"},
+			},
+			want: map[string]string{"source": "r1", "site": "dc1", "role": "spine"},
+		},
+		{
+			name: "event_tags_precedence",
+			meta: map[string]string{"source": "r1"},
+			tc: &types.TargetConfig{
+				Name:      "r1",
+				Labels:    map[string]string{"site": "dc1", "role": "spine"},
+				EventTags: map[string]string{"site": "dc2"},
+			},
+			want: map[string]string{"source": "r1", "site": "dc2", "role": "spine"},
+		},
+		{
+			name: "reserved_keys",
+			meta: map[string]string{
+				"source":            "r1",
+				"format":            "json",
+				"subscription-name": "sub1",
+			},
+			tc: &types.TargetConfig{
+				Name: "r1",
+				Labels: map[string]string{
+					"source":            "other",
+					"subscription-name": "other",
+					"site":              "dc1",
+				},
+				EventTags: map[string]string{"format": "other"},
+			},
+			want: map[string]string{
+				"source":            "r1",
+				"format":            "other",
+				"subscription-name": "sub1",
+				"site":              "dc1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addTargetMeta(tt.meta, tt.tc)
+			if !reflect.DeepEqual(tt.meta, tt.want) {
+				t.Errorf("got %v, expected %v", tt.meta, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddOutputOptionsMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		tc   *types.TargetConfig
+		sc   *types.SubscriptionConfig
+		want map[string]string
+	}{
+		{
+			name: "no_options",
+			tc:   &types.TargetConfig{Name: "r1"},
+			sc:   &types.SubscriptionConfig{Name: "sub1"},
+			want: map[string]string{"source": "r1"},
+		},
+		{
+			name: "subscription_options",
+			tc:   &types.TargetConfig{Name: "r1"},
+			sc: &types.SubscriptionConfig{
+				Name: "sub1",
+				OutputOptions: &types.OutputOptions{
+					Format:          "proto",
+					EventProcessors: []string{"proc1", "proc2"},
+				},
+			},
+			want: map[string]string{
+				"source":                             "r1",
+				formatters.MetaOutputFormat:          "proto",
+				formatters.MetaOutputEventProcessors: "proc1,proc2",
+			},
+		},
+		{
+			name: "target_options_precedence",
+			tc: &types.TargetConfig{
+				Name: "r1",
+				SubscriptionsOutputOptions: map[string]*types.OutputOptions{
+					"sub1": {EventProcessors: []string{}},
+					"sub2": {Format: "json"},
+				},
+			},
+			sc: &types.SubscriptionConfig{
+				Name: "sub1",
+				OutputOptions: &types.OutputOptions{
+					Format:          "proto",
+					EventProcessors: []string{"proc1"},
+				},
+			},
+			want: map[string]string{
+				"source":                             "r1",
+				formatters.MetaOutputFormat:          "proto",
+				formatters.MetaOutputEventProcessors: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string]string{"source": "r1"}
+			addOutputOptionsMeta(m, tt.tc, tt.sc)
+			if !reflect.DeepEqual(m, tt.want) {
+				t.Errorf("got %v, expected %v", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTargetStateCollectorExtension(t *testing.T) {
+	a := New()
+	a.collectorExt = outputs.NewCollectorExtension(&types.CollectorExtensionConfig{}, "gnmic1")
+	a.collectorExt.Received("leaf1", "leaf1:57400")
+	if err := a.deleteTargetState(context.Background(), "leaf1"); err != nil {
+		t.Fatal(err)
+	}
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{Prefix: &gnmi.Path{Target: "leaf1"}},
+		},
+	}
+	if err := a.collectorExt.Add(rsp); err != nil {
+		t.Fatal(err)
+	}
+	ci, err := api.CollectorInfoFromExtensions(api.DefaultCollectorExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci == nil || ci.ReceiveTimestamp != 0 || ci.Target != "leaf1" {
+		t.Errorf("expected the deleted target collector info to be forgotten, got %+v", ci)
+	}
+}
@@ -244,7 +244,7 @@ func (a *App) getTunnelTargetMatch(tt tunnel.Target) *types.TargetConfig {
 			continue
 		}
 		// target has a match
-		if a.Config.Debug {
+		if a.debugEnabled() {
 			a.Logger.Printf("target %+v matches %+v", tt, tm)
 		}
 		tc := new(types.TargetConfig)
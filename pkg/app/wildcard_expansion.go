@@ -0,0 +1,208 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/api/target"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// subscribeExpanded subscribes to target t with the wildcard paths of subscription sc
+// expanded into the keyed paths returned by a Get request.
+// If the subscription has a refresh-interval, the paths are discovered again
+// periodically and the subscription is re-established when they change.
+func (a *App) subscribeExpanded(ctx context.Context, t *target.Target, name string, sc *types.SubscriptionConfig) {
+	var current []string
+	subscribe := func() {
+		nsc, err := a.expandSubscriptionWildcards(ctx, t, sc)
+		if err != nil {
+			a.Logger.Printf("target %q: subscription %s: failed to expand wildcards: %v", t.Config.Name, name, err)
+			if current != nil {
+				// keep the current subscription
+				return
+			}
+			nsc = sc
+		}
+		if current != nil && equalStrings(current, nsc.Paths) {
+			return
+		}
+		req, err := a.Config.CreateSubscribeRequest(nsc, t.Config)
+		if err != nil {
+			a.Logger.Printf("target %q: subscription %s: failed to create subscribe request: %v", t.Config.Name, name, err)
+			return
+		}
+		a.Logger.Printf("target %q: subscription %s: subscribing to %d expanded paths", t.Config.Name, name, len(nsc.Paths))
+		current = nsc.Paths
+		go t.Subscribe(ctx, req, name)
+	}
+	subscribe()
+	if sc.ExpandWildcards.RefreshInterval <= 0 || strings.ToUpper(sc.Mode) == subscriptionModeONCE {
+		return
+	}
+	ticker := time.NewTicker(sc.ExpandWildcards.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			subscribe()
+		}
+	}
+}
+
+// expandSubscriptionWildcards returns a copy of subscription sc with its wildcard paths
+// replaced by the keyed paths found in the response to a Get request sent to target t.
+// Paths without wildcards, with a multi-level wildcard or without any match are kept as is.
+func (a *App) expandSubscriptionWildcards(ctx context.Context, t *target.Target, sc *types.SubscriptionConfig) (*types.SubscriptionConfig, error) {
+	pf, err := path.CreatePrefix(sc.Prefix, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix %q: %w", sc.Prefix, err)
+	}
+	paths := make([]string, 0, len(sc.Paths))
+	for _, p := range sc.Paths {
+		wp, err := path.ParsePath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", p, err)
+		}
+		if !expandablePath(wp) {
+			paths = append(paths, p)
+			continue
+		}
+		opts := []api.GNMIOption{
+			api.Path(p),
+			api.Encoding(a.subscriptionEncoding(t.Config, sc)),
+			api.Target(sc.Target),
+		}
+		if sc.Prefix != "" {
+			opts = append(opts, api.Prefix(sc.Prefix))
+		}
+		req, err := api.NewGetRequest(opts...)
+		if err != nil {
+			return nil, err
+		}
+		gctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+		rsp, err := t.Get(gctx, req)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("get %q: %w", p, err)
+		}
+		eps := expandPath(pf, wp, rsp.GetNotification())
+		if len(eps) == 0 {
+			paths = append(paths, p)
+			continue
+		}
+		paths = append(paths, eps...)
+	}
+	nsc := *sc
+	nsc.Paths = paths
+	return &nsc, nil
+}
+
+// subscriptionEncoding returns the encoding used by subscription sc of target tc.
+func (a *App) subscriptionEncoding(tc *types.TargetConfig, sc *types.SubscriptionConfig) string {
+	switch {
+	case sc.Encoding != nil:
+		return *sc.Encoding
+	case tc.Encoding != nil:
+		return *tc.Encoding
+	}
+	return a.Config.Encoding
+}
+
+// expandablePath reports whether path p has a single level wildcard
+// in an element name or key value, and no multi-level wildcard.
+func expandablePath(p *gnmi.Path) bool {
+	var wildcard bool
+	for _, pe := range p.GetElem() {
+		switch pe.GetName() {
+		case "...":
+			return false
+		case "*":
+			wildcard = true
+		}
+		for _, v := range pe.GetKey() {
+			if v == "*" {
+				wildcard = true
+			}
+		}
+	}
+	return wildcard
+}
+
+// expandPath returns the sorted concrete paths matching the wildcard path wp
+// relative to prefix pf, as found in the notifications.
+// The returned paths are relative to pf and have as many elements as wp.
+func expandPath(pf, wp *gnmi.Path, notifs []*gnmi.Notification) []string {
+	welems := path.PathElems(pf, wp)
+	numPrefixElems := len(pf.GetElem())
+	found := make(map[string]struct{})
+	for _, n := range notifs {
+		for _, upd := range n.GetUpdate() {
+			relems := path.PathElems(n.GetPrefix(), upd.GetPath())
+			if !matchElems(welems, relems) {
+				continue
+			}
+			p := &gnmi.Path{
+				Origin: wp.GetOrigin(),
+				Elem:   relems[numPrefixElems:len(welems)],
+			}
+			found["/"+path.GnmiPathToXPath(p, false)] = struct{}{}
+		}
+	}
+	r := make([]string, 0, len(found))
+	for p := range found {
+		r = append(r, p)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// matchElems reports whether the first elements of path elements relems
+// match the wildcard path elements welems.
+func matchElems(welems, relems []*gnmi.PathElem) bool {
+	if len(relems) < len(welems) {
+		return false
+	}
+	for i, we := range welems {
+		re := relems[i]
+		if we.GetName() != "*" && we.GetName() != re.GetName() {
+			return false
+		}
+		for k, v := range we.GetKey() {
+			rv, ok := re.GetKey()[k]
+			if !ok || (v != "*" && v != rv) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+func TestExpandablePath(t *testing.T) {
+	for p, want := range map[string]bool{
+		"/interfaces/interface/state":                  false,
+		"/interfaces/interface[name=*]/state":          true,
+		"/interfaces/*/state":                          true,
+		"/interfaces/interface[name=*]/.../counters":   false,
+		"/network-instances/network-instance[name=x]/": false,
+	} {
+		gp, err := path.ParsePath(p)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", p, err)
+		}
+		if got := expandablePath(gp); got != want {
+			t.Errorf("%q: expected %v, got %v", p, want, got)
+		}
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	notifs := []*gnmi.Notification{
+		{
+			Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{
+				{Name: "interfaces"},
+				{Name: "interface", Key: map[string]string{"name": "ethernet-1/2"}},
+			}},
+			Update: []*gnmi.Update{
+				{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "state"}, {Name: "oper-status"}}}},
+				{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "state"}, {Name: "admin-status"}}}},
+			},
+		},
+		{
+			Update: []*gnmi.Update{
+				{Path: &gnmi.Path{Elem: []*gnmi.PathElem{
+					{Name: "interfaces"},
+					{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}},
+					{Name: "state"},
+					{Name: "oper-status"},
+				}}},
+				// does not match the wildcard path
+				{Path: &gnmi.Path{Elem: []*gnmi.PathElem{
+					{Name: "interfaces"},
+					{Name: "interface", Key: map[string]string{"name": "ethernet-1/3"}},
+					{Name: "config"},
+				}}},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		path   string
+		want   []string
+	}{
+		{
+			name: "keyed path",
+			path: "/interfaces/interface[name=*]/state",
+			want: []string{
+				"/interfaces/interface[name=ethernet-1/1]/state",
+				"/interfaces/interface[name=ethernet-1/2]/state",
+			},
+		},
+		{
+			name:   "with prefix",
+			prefix: "/interfaces",
+			path:   "interface[name=*]/state",
+			want: []string{
+				"/interface[name=ethernet-1/1]/state",
+				"/interface[name=ethernet-1/2]/state",
+			},
+		},
+		{
+			name: "no match",
+			path: "/interfaces/interface[name=*]/counters",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pf, err := path.CreatePrefix(tt.prefix, "")
+			if err != nil {
+				t.Fatalf("failed to parse prefix: %v", err)
+			}
+			wp, err := path.ParsePath(tt.path)
+			if err != nil {
+				t.Fatalf("failed to parse path: %v", err)
+			}
+			got := expandPath(pf, wp, notifs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -22,10 +22,11 @@ import (
 type CacheType string
 
 const (
-	cacheType_OC    CacheType = "oc"
-	cacheType_Redis CacheType = "redis"
-	cacheType_NATS  CacheType = "nats"
-	cacheType_JS    CacheType = "jetstream"
+	cacheType_OC     CacheType = "oc"
+	cacheType_Redis  CacheType = "redis"
+	cacheType_NATS   CacheType = "nats"
+	cacheType_JS     CacheType = "jetstream"
+	cacheType_NATSKV CacheType = "nats-kv"
 )
 
 const (
@@ -66,6 +67,9 @@ type Config struct {
 	MaxMsgsPerSubscription int64         `mapstructure:"max-msgs-per-subscription,omitempty" json:"max-msgs-per-subscription,omitempty"`
 	FetchBatchSize         int           `mapstructure:"fetch-batch-size,omitempty" json:"fetch-batch-size,omitempty"`
 	FetchWaitTime          time.Duration `mapstructure:"fetch-wait-time,omitempty" json:"fetch-wait-time,omitempty"`
+
+	// NATS KV cfg options
+	Bucket string `mapstructure:"bucket,omitempty" json:"bucket,omitempty"`
 }
 
 func (c *Config) setDefaults() {
@@ -73,7 +77,7 @@ func (c *Config) setDefaults() {
 		switch c.Type {
 		case cacheType_Redis:
 			c.Address = defaultRedisAddress
-		case cacheType_JS, cacheType_NATS:
+		case cacheType_JS, cacheType_NATS, cacheType_NATSKV:
 			c.Address = defaultNATSAddress
 		}
 	}
@@ -84,6 +88,16 @@ func (c *Config) setDefaults() {
 		c.Expiration = defaultExpiration
 	}
 
+	if c.Type == cacheType_NATSKV {
+		if c.Bucket == "" {
+			c.Bucket = defaultBucket
+		}
+		if c.MaxBytes <= 0 {
+			c.MaxBytes = defaultMaxBytes
+		}
+		return
+	}
+
 	if c.Type != cacheType_JS {
 		return
 	}
@@ -118,6 +132,8 @@ func New(c *Config, opts ...Option) (Cache, error) {
 		return newJetStreamCache(c, opts...)
 	case cacheType_Redis:
 		return newRedisCache(c, opts...)
+	case cacheType_NATSKV:
+		return newNATSKVCache(c, opts...)
 	default:
 		return nil, fmt.Errorf("unknown cache type: %q", c.Type)
 	}
@@ -0,0 +1,480 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/utils"
+)
+
+const (
+	loggingPrefixNATSKV = "[cache:nats-kv] "
+	defaultBucket       = "gnmic-cache"
+	emptyOriginToken    = "_"
+)
+
+// natsKVCache stores each cached leaf as a separate key in a NATS JetStream
+// Key-Value bucket.
+// Unlike the nats and jetstream caches, it does not keep a local copy
+// of the data: reads and subscriptions are served by loading the relevant
+// keys from the bucket, so the bucket can be shared by multiple gNMIc instances.
+//
+// The keys are built as <subscription>.<target>.<origin>.<elem1>...<elemN>,
+// each token being base64 (URL alphabet) encoded.
+type natsKVCache struct {
+	cfg *Config
+	ns  *server.Server
+	nc  *nats.Conn
+	kv  nats.KeyValue
+
+	// configured remote address or locally started server address
+	addr   string
+	logger *log.Logger
+}
+
+func newNATSKVCache(cfg *Config, opts ...Option) (*natsKVCache, error) {
+	if cfg == nil {
+		cfg = &Config{Type: cacheType_NATSKV}
+	}
+	cfg.setDefaults()
+
+	var err error
+	c := &natsKVCache{
+		cfg: cfg,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.cfg.Address == defaultNATSAddress {
+		sopts := &server.Options{
+			Host:      cfg.Address,
+			Port:      -1,
+			JetStream: true,
+			NoSigs:    true,
+		}
+
+		c.ns, err = server.NewServer(sopts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.logger == nil {
+		c.logger = log.New(os.Stderr, loggingPrefixNATSKV, utils.DefaultLoggingFlags)
+	}
+	c.start()
+	err = c.createBucket()
+	if err != nil {
+		c.Stop()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *natsKVCache) SetLogger(logger *log.Logger) {
+	if logger != nil && c.logger != nil {
+		c.logger.SetOutput(logger.Writer())
+		c.logger.SetFlags(logger.Flags())
+		c.logger.SetPrefix(loggingPrefixNATSKV)
+	}
+}
+
+func (c *natsKVCache) start() {
+START:
+	if c.ns != nil {
+		go c.ns.Start()
+		if !c.ns.ReadyForConnections(reconnectTimer) {
+			c.ns.Shutdown()
+			c.logger.Printf("failed to start cache, retrying")
+			goto START
+		}
+	}
+
+	c.addr = c.cfg.Address
+	if c.ns != nil {
+		c.addr = c.ns.ClientURL()
+	}
+
+	var err error
+	opts := []nats.Option{
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			c.logger.Printf("NATS error: %v", err)
+		}),
+		nats.DisconnectHandler(func(_ *nats.Conn) {
+			c.logger.Println("Disconnected from NATS")
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			c.logger.Println("NATS connection is closed")
+		}),
+	}
+	if c.cfg.Username != "" && c.cfg.Password != "" {
+		opts = append(opts, nats.UserInfo(c.cfg.Username, c.cfg.Password))
+	}
+CONNECT:
+	if c.nc != nil {
+		c.nc.Close()
+	}
+
+	c.nc, err = nats.Connect(c.addr, opts...)
+	if err != nil {
+		c.logger.Printf("failed to connect: %v", err)
+		time.Sleep(reconnectTimer)
+		goto CONNECT
+	}
+}
+
+func (c *natsKVCache) createBucket() error {
+	js, err := c.nc.JetStream()
+	if err != nil {
+		return err
+	}
+	c.kv, err = js.KeyValue(c.cfg.Bucket)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrBucketNotFound) {
+		return err
+	}
+	c.logger.Printf("creating KV bucket %q", c.cfg.Bucket)
+	c.kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+		Bucket:   c.cfg.Bucket,
+		History:  1,
+		TTL:      c.cfg.Expiration,
+		MaxBytes: c.cfg.MaxBytes,
+		Storage:  nats.MemoryStorage,
+	})
+	return err
+}
+
+func (c *natsKVCache) Write(ctx context.Context, subscriptionName string, m proto.Message) {
+	switch m := m.ProtoReflect().Interface().(type) {
+	case *gnmi.SubscribeResponse:
+		switch rsp := m.GetResponse().(type) {
+		case *gnmi.SubscribeResponse_Update:
+			targetName := rsp.Update.GetPrefix().GetTarget()
+			if targetName == "" {
+				c.logger.Printf("subscription=%q: response missing target: %v", subscriptionName, rsp)
+				return
+			}
+			for _, del := range rsp.Update.GetDelete() {
+				err := c.deletePath(ctx, subscriptionName, targetName, joinPaths(rsp.Update.GetPrefix(), del))
+				if err != nil {
+					c.logger.Printf("failed to delete path from KV cache: %v", err)
+				}
+			}
+			for _, upd := range rsp.Update.GetUpdate() {
+				if upd.GetVal() == nil {
+					continue
+				}
+				p := joinPaths(rsp.Update.GetPrefix(), upd.GetPath())
+				if len(p.GetElem()) == 0 {
+					c.logger.Printf("write fail: received an update with en empty path: %v", upd)
+					continue
+				}
+				b, err := proto.Marshal(&gnmi.Notification{
+					Timestamp: rsp.Update.GetTimestamp(),
+					Prefix:    &gnmi.Path{Target: targetName, Origin: p.GetOrigin()},
+					Update: []*gnmi.Update{
+						{
+							Path: &gnmi.Path{Elem: p.GetElem()},
+							Val:  upd.GetVal(),
+						},
+					},
+				})
+				if err != nil {
+					c.logger.Printf("failed to marshal proto message: %v", err)
+					continue
+				}
+				_, err = c.kv.Put(kvKey(subscriptionName, targetName, p), b)
+				if err != nil {
+					c.logger.Printf("failed to write to KV cache: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// deletePath deletes the keys of path p and all the keys under it.
+func (c *natsKVCache) deletePath(ctx context.Context, sub, target string, p *gnmi.Path) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	w, err := c.kv.Watch(kvFilter(sub, target), nats.IgnoreDeletes(), nats.Context(ctx))
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	for e := range w.Updates() {
+		if e == nil {
+			return nil
+		}
+		n := new(gnmi.Notification)
+		err = proto.Unmarshal(e.Value(), n)
+		if err != nil {
+			return err
+		}
+		if len(n.GetUpdate()) == 0 {
+			continue
+		}
+		if !pathHasPrefix(joinPaths(n.GetPrefix(), n.GetUpdate()[0].GetPath()), p) {
+			continue
+		}
+		err = c.kv.Delete(e.Key())
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// load watches the bucket keys of subscription `sub` and target `target`
+// and writes their values to oc.
+// If updates is false, it returns once the existing values are written,
+// otherwise it keeps writing the received updates and deletes until ctx is done.
+func (c *natsKVCache) load(ctx context.Context, sub, target string, oc *gnmiCache, updates bool) error {
+	w, err := c.kv.Watch(kvFilter(sub, target), nats.Context(ctx))
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	// keys to subscription names and paths, used to translate
+	// key deletions into gNMI deletes
+	seen := make(map[string]*kvLeaf)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-w.Updates():
+			if !ok {
+				return nil
+			}
+			if e == nil {
+				if !updates {
+					return nil
+				}
+				continue
+			}
+			switch e.Operation() {
+			case nats.KeyValuePut:
+				subName, err := kvKeySubscription(e.Key())
+				if err != nil {
+					c.logger.Printf("unexpected key %q: %v", e.Key(), err)
+					continue
+				}
+				n := new(gnmi.Notification)
+				err = proto.Unmarshal(e.Value(), n)
+				if err != nil {
+					c.logger.Printf("failed to unmarshal proto msg: %v", err)
+					continue
+				}
+				if updates && len(n.GetUpdate()) > 0 {
+					seen[e.Key()] = &kvLeaf{sub: subName, prefix: n.GetPrefix(), path: n.GetUpdate()[0].GetPath()}
+				}
+				oc.Write(ctx, subName, &gnmi.SubscribeResponse{
+					Response: &gnmi.SubscribeResponse_Update{Update: n},
+				})
+			default: // delete or purge
+				l, ok := seen[e.Key()]
+				if !ok {
+					continue
+				}
+				delete(seen, e.Key())
+				oc.Write(ctx, l.sub, &gnmi.SubscribeResponse{
+					Response: &gnmi.SubscribeResponse_Update{
+						Update: &gnmi.Notification{
+							Timestamp: time.Now().UnixNano(),
+							Prefix:    l.prefix,
+							Delete:    []*gnmi.Path{l.path},
+						},
+					},
+				})
+			}
+		}
+	}
+}
+
+type kvLeaf struct {
+	sub    string
+	prefix *gnmi.Path
+	path   *gnmi.Path
+}
+
+// Read //
+func (c *natsKVCache) ReadAll() (map[string][]*gnmi.Notification, error) {
+	return c.Read("", "*", nil)
+}
+
+func (c *natsKVCache) Read(sub, target string, p *gnmi.Path) (map[string][]*gnmi.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+	oc := newGNMICache(c.cfg, "nats-kv")
+	err := c.load(ctx, sub, target, oc, false)
+	if err != nil {
+		return nil, err
+	}
+	return oc.read(sub, target, p), nil
+}
+
+func (c *natsKVCache) Subscribe(ctx context.Context, ro *ReadOpts) chan *Notification {
+	if ro == nil {
+		ro = new(ReadOpts)
+	}
+	ro.setDefaults()
+	oc := newGNMICache(c.cfg, "nats-kv")
+	lctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	err := c.load(lctx, ro.Subscription, ro.Target, oc, false)
+	cancel()
+	if err != nil {
+		c.logger.Printf("failed to load KV cache for target %q: %v", ro.Target, err)
+	}
+	if ro.Mode != ReadMode_Once {
+		go func() {
+			err := c.load(ctx, ro.Subscription, ro.Target, oc, true)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.logger.Printf("KV cache watch for target %q stopped: %v", ro.Target, err)
+			}
+		}()
+	}
+	return oc.Subscribe(ctx, ro)
+}
+
+func (c *natsKVCache) Stop() {
+	if c.nc != nil {
+		c.nc.Close()
+	}
+	if c.ns != nil {
+		c.ns.Shutdown()
+	}
+}
+
+func (c *natsKVCache) DeleteTarget(name string) {
+	err := c.deletePath(context.Background(), "", name, &gnmi.Path{})
+	if err != nil {
+		c.logger.Printf("failed to delete target %q from KV cache: %v", name, err)
+	}
+}
+
+func kvToken(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// kvFilter returns the keys filter matching the subscription `sub`
+// and target `target`, empty and "*" values match all.
+func kvFilter(sub, target string) string {
+	sb := new(strings.Builder)
+	if sub == "" || sub == "*" {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(kvToken(sub))
+	}
+	sb.WriteString(".")
+	if target == "" || target == "*" {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(kvToken(target))
+	}
+	sb.WriteString(".>")
+	return sb.String()
+}
+
+func kvKey(sub, target string, p *gnmi.Path) string {
+	sb := new(strings.Builder)
+	sb.WriteString(kvToken(sub))
+	sb.WriteString(".")
+	sb.WriteString(kvToken(target))
+	sb.WriteString(".")
+	if p.GetOrigin() == "" {
+		sb.WriteString(emptyOriginToken)
+	} else {
+		sb.WriteString(kvToken(p.GetOrigin()))
+	}
+	for _, e := range p.GetElem() {
+		sb.WriteString(".")
+		sb.WriteString(kvToken(pathElemString(e)))
+	}
+	return sb.String()
+}
+
+func kvKeySubscription(key string) (string, error) {
+	tk, _, _ := strings.Cut(key, ".")
+	b, err := base64.RawURLEncoding.DecodeString(tk)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func pathElemString(e *gnmi.PathElem) string {
+	sb := new(strings.Builder)
+	sb.WriteString(e.GetName())
+	kNames := make([]string, 0, len(e.GetKey()))
+	for k := range e.GetKey() {
+		kNames = append(kNames, k)
+	}
+	sort.Strings(kNames)
+	for _, k := range kNames {
+		fmt.Fprintf(sb, "[%s=%s]", k, e.GetKey()[k])
+	}
+	return sb.String()
+}
+
+// joinPaths returns a path made of the prefix and path p elements.
+func joinPaths(prefix, p *gnmi.Path) *gnmi.Path {
+	r := &gnmi.Path{
+		Origin: prefix.GetOrigin(),
+		Elem:   make([]*gnmi.PathElem, 0, len(prefix.GetElem())+len(p.GetElem())),
+	}
+	if r.Origin == "" {
+		r.Origin = p.GetOrigin()
+	}
+	r.Elem = append(r.Elem, prefix.GetElem()...)
+	r.Elem = append(r.Elem, p.GetElem()...)
+	return r
+}
+
+// pathHasPrefix reports whether path p is equal to or under path prefix.
+// The prefix can contain wildcards.
+func pathHasPrefix(p, prefix *gnmi.Path) bool {
+	if prefix.GetOrigin() != "" && prefix.GetOrigin() != p.GetOrigin() {
+		return false
+	}
+	pElems := p.GetElem()
+	for i, pe := range prefix.GetElem() {
+		if pe.GetName() == "..." {
+			return true
+		}
+		if i >= len(pElems) {
+			return false
+		}
+		if pe.GetName() != "*" && pe.GetName() != pElems[i].GetName() {
+			return false
+		}
+		for k, v := range pe.GetKey() {
+			if v == "*" {
+				continue
+			}
+			if pElems[i].GetKey()[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
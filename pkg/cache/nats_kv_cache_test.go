@@ -0,0 +1,167 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func kvTestUpdate(target, name, value string) *gnmi.SubscribeResponse {
+	return &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: time.Now().UnixNano(),
+				Prefix: &gnmi.Path{
+					Target: target,
+					Elem:   []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": name}}},
+				},
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "description"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: value}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func countNotifications(m map[string][]*gnmi.Notification) int {
+	count := 0
+	for _, ns := range m {
+		count += len(ns)
+	}
+	return count
+}
+
+func Test_natsKVCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := New(&Config{Type: cacheType_NATSKV})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	defer c.Stop()
+
+	c.Write(ctx, "sub1", kvTestUpdate("router1", "ethernet-1/1", "uplink"))
+	c.Write(ctx, "sub1", kvTestUpdate("router1", "ethernet-1/2", "downlink"))
+	c.Write(ctx, "sub2", kvTestUpdate("router2", "ethernet-1/1", "uplink"))
+
+	rs, err := c.Read("*", "router1", &gnmi.Path{})
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if count := countNotifications(rs); count != 2 || len(rs["sub1"]) != 2 {
+		t.Fatalf("expected 2 notifications for router1, got %v", rs)
+	}
+	rs, err = c.Read("*", "*", &gnmi.Path{
+		Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if count := countNotifications(rs); count != 2 {
+		t.Fatalf("expected 2 notifications for ethernet-1/1, got %v", rs)
+	}
+
+	// once subscription
+	ch := c.Subscribe(ctx, &ReadOpts{Target: "router2", Mode: ReadMode_Once})
+	count := 0
+	for n := range ch {
+		if n.Err != nil {
+			t.Fatalf("subscription error: %v", n.Err)
+		}
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 notification for router2, got %d", count)
+	}
+
+	// on-change subscription receives updates written after it started
+	sctx, scancel := context.WithCancel(ctx)
+	ch = c.Subscribe(sctx, &ReadOpts{Target: "router2", Mode: ReadMode_StreamOnChange, UpdatesOnly: true})
+	time.Sleep(100 * time.Millisecond)
+	c.Write(ctx, "sub2", kvTestUpdate("router2", "ethernet-1/1", "core"))
+	select {
+	case n := <-ch:
+		if got := n.Notification.GetUpdate()[0].GetVal().GetAsciiVal(); got != "core" {
+			t.Errorf("unexpected on-change value %q", got)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for on-change notification")
+	}
+	scancel()
+
+	// delete
+	c.Write(ctx, "sub1", &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: time.Now().UnixNano(),
+				Prefix:    &gnmi.Path{Target: "router1"},
+				Delete: []*gnmi.Path{
+					{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "ethernet-1/2"}}}},
+				},
+			},
+		},
+	})
+	rs, err = c.Read("sub1", "router1", &gnmi.Path{})
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if count := countNotifications(rs); count != 1 {
+		t.Fatalf("expected 1 notification after delete, got %v", rs)
+	}
+
+	c.DeleteTarget("router1")
+	rs, err = c.Read("*", "router1", &gnmi.Path{})
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if count := countNotifications(rs); count != 0 {
+		t.Fatalf("expected no notification after target delete, got %v", rs)
+	}
+	rs, err = c.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if count := countNotifications(rs); count != 1 {
+		t.Fatalf("expected 1 notification, got %v", rs)
+	}
+}
+
+func Test_pathHasPrefix(t *testing.T) {
+	p := &gnmi.Path{
+		Elem: []*gnmi.PathElem{
+			{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}},
+			{Name: "description"},
+		},
+	}
+	tests := []struct {
+		prefix *gnmi.Path
+		want   bool
+	}{
+		{prefix: &gnmi.Path{}, want: true},
+		{prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface"}}}, want: true},
+		{prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "*"}}}}, want: true},
+		{prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "ethernet-1/2"}}}}, want: false},
+		{prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "*"}, {Name: "description"}}}, want: true},
+		{prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface"}, {Name: "description"}, {Name: "x"}}}, want: false},
+		{prefix: &gnmi.Path{Origin: "openconfig"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := pathHasPrefix(p, tt.prefix); got != tt.want {
+			t.Errorf("pathHasPrefix(%v) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -194,6 +194,13 @@ func (gc *gnmiCache) subscribe(ctx context.Context, ro *ReadOpts, ch chan *Notif
 }
 
 func (gc *gnmiCache) handleSingleQuery(ctx context.Context, ro *ReadOpts, ch chan *Notification) {
+	gc.query(ctx, ro, ch, false)
+}
+
+// query sends the notifications matching ro to ch.
+// If ro.SuppressRedundant is set, only the values that changed since they were
+// last sent are sent, unless heartbeat is true in which case all values are sent.
+func (gc *gnmiCache) query(ctx context.Context, ro *ReadOpts, ch chan *Notification, heartbeat bool) {
 	if gc.debug {
 		gc.logger.Printf("running single query for target %q", ro.Target)
 	}
@@ -253,7 +260,7 @@ func (gc *gnmiCache) handleSingleQuery(ctx context.Context, ro *ReadOpts, ch cha
 								ro.m.RLock()
 								sv, ok := ro.lastSent[valXPath]
 								ro.m.RUnlock()
-								if !ok || !proto.Equal(sv, upd.Val) {
+								if heartbeat || !ok || !proto.Equal(sv, upd.Val) {
 									ch <- &Notification{
 										Name: name,
 										Notification: &gnmi.Notification{
@@ -298,6 +305,9 @@ func (gc *gnmiCache) handleSampledQuery(ctx context.Context, ro *ReadOpts, ch ch
 		gc.handleSingleQuery(ctx, ro, ch)
 	}
 
+	// with suppress redundant, the unchanged values are sent
+	// once per heartbeat interval, if one is set.
+	lastHeartbeat := time.Now()
 	ticker := time.NewTicker(ro.SampleInterval)
 	defer ticker.Stop()
 	for {
@@ -305,8 +315,14 @@ func (gc *gnmiCache) handleSampledQuery(ctx context.Context, ro *ReadOpts, ch ch
 		case <-ctx.Done():
 			gc.logger.Printf("periodic query to target %q stopped: %v", ro.Target, ctx.Err())
 			return
-		case <-ticker.C:
-			gc.handleSingleQuery(ctx, ro, ch)
+		case now := <-ticker.C:
+			// half a sample interval of tolerance absorbs the ticker jitter
+			heartbeat := ro.SuppressRedundant && ro.HeartbeatInterval > 0 &&
+				now.Sub(lastHeartbeat)+ro.SampleInterval/2 >= ro.HeartbeatInterval
+			if heartbeat {
+				lastHeartbeat = now
+			}
+			gc.query(ctx, ro, ch, heartbeat)
 		}
 	}
 }
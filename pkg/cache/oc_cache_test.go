@@ -359,3 +359,54 @@ func Test_gnmiCache_read(t *testing.T) {
 		})
 	}
 }
+
+func Test_gnmiCache_sampledSuppressRedundant(t *testing.T) {
+	gc := newGNMICache(&Config{}, "oc", WithLogger(log.Default()))
+	write := func(v string) {
+		gc.Write(context.TODO(), "sub1", &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: &gnmi.Notification{
+					Timestamp: time.Now().UnixNano(),
+					Prefix:    &gnmi.Path{Target: "t1"},
+					Update: []*gnmi.Update{{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "name"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: v}},
+					}},
+				},
+			},
+		})
+	}
+	write("srl1")
+
+	count := func(heartbeat time.Duration, change bool) int {
+		ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+		defer cancel()
+		ch := gc.Subscribe(ctx, &ReadOpts{
+			Target:            "t1",
+			Mode:              ReadMode_StreamSample,
+			SampleInterval:    10 * time.Millisecond,
+			HeartbeatInterval: heartbeat,
+			SuppressRedundant: true,
+		})
+		var n int
+		for range ch {
+			n++
+			if n == 1 && change {
+				write("srl2")
+			}
+		}
+		return n
+	}
+	// the initial value only
+	if n := count(0, false); n != 1 {
+		t.Errorf("expected 1 notification without heartbeat, got %d", n)
+	}
+	// the initial value and the changed value
+	if n := count(0, true); n != 2 {
+		t.Errorf("expected 2 notifications with a changed value, got %d", n)
+	}
+	// the initial value and about 3 heartbeats
+	if n := count(100*time.Millisecond, false); n < 3 || n > 5 {
+		t.Errorf("expected 3 to 5 notifications with heartbeats, got %d", n)
+	}
+}
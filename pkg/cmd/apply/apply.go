@@ -0,0 +1,35 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New creates the apply command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "apply a YAML intent file to the targets using gNMI Set RPCs",
+		Annotations: map[string]string{
+			"--intent-file": "FILE",
+			"--vars":        "FILE",
+		},
+		PreRunE: gApp.ApplyPreRunE,
+		RunE:    gApp.ApplyRunE,
+		PostRun: func(cmd *cobra.Command, _ []string) {
+			cmd.ResetFlags()
+			gApp.InitApplyFlags(cmd)
+		},
+		SilenceUsage: true,
+	}
+	gApp.InitApplyFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,75 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New creates the cert command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert",
+		Short: "manage the targets certificates using gNOI certificate management",
+	}
+	cmd.AddCommand(newCertGetCmd(gApp))
+	cmd.AddCommand(newCertInstallCmd(gApp))
+	cmd.AddCommand(newCertRotateCmd(gApp))
+	return cmd
+}
+
+// newCertGetCmd creates a new cert get command.
+func newCertGetCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "get",
+		Short:        "get the certificates installed on the targets",
+		PreRunE:      gApp.CertPreRunE,
+		RunE:         gApp.CertGetRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitCertGetFlags(cmd)
+	return cmd
+}
+
+// newCertInstallCmd creates a new cert install command.
+func newCertInstallCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "install a new certificate on the targets, signed by a local CA",
+		Annotations: map[string]string{
+			"--ca-cert":   "FILE",
+			"--ca-key":    "FILE",
+			"--ca-bundle": "FILE",
+		},
+		PreRunE:      gApp.CertPreRunE,
+		RunE:         gApp.CertInstallRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitCertInstallFlags(cmd)
+	return cmd
+}
+
+// newCertRotateCmd creates a new cert rotate command.
+func newCertRotateCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "rotate an existing certificate on the targets, signed by a local CA",
+		Annotations: map[string]string{
+			"--ca-cert":   "FILE",
+			"--ca-key":    "FILE",
+			"--ca-bundle": "FILE",
+		},
+		PreRunE:      gApp.CertPreRunE,
+		RunE:         gApp.CertRotateRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitCertRotateFlags(cmd)
+	return cmd
+}
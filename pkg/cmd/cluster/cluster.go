@@ -0,0 +1,37 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New create the cluster command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "plan the distribution of the targets over gNMIc instances",
+	}
+	cmd.AddCommand(newClusterPlanCmd(gApp))
+	return cmd
+}
+
+// newClusterPlanCmd creates a new cluster plan command.
+func newClusterPlanCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "plan",
+		Short:        "suggest a number of instances and generate their configuration",
+		PreRunE:      gApp.ClusterPlanPreRunE,
+		RunE:         gApp.ClusterPlanRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitClusterPlanFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,49 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New create the config command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "manage gNMIc configuration files",
+	}
+	cmd.AddCommand(newConfigImportCmd(gApp))
+	cmd.AddCommand(newConfigSchemaCmd(gApp))
+	return cmd
+}
+
+// newConfigImportCmd creates a new config import command.
+func newConfigImportCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "import",
+		Short:        "convert another collector configuration into a gNMIc configuration",
+		RunE:         gApp.ConfigImportRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitConfigImportFlags(cmd)
+	return cmd
+}
+
+// newConfigSchemaCmd creates a new config schema command.
+func newConfigSchemaCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "schema",
+		Short:        "print the configuration options of the supported outputs, inputs and processors",
+		RunE:         gApp.ConfigSchemaRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitConfigSchemaFlags(cmd)
+	return cmd
+}
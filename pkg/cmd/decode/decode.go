@@ -0,0 +1,34 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package decode
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New creates the decode command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "decode a file of gNMI messages recorded with --record",
+		Annotations: map[string]string{
+			"--input": "FILE",
+		},
+		PreRunE: gApp.DecodePreRunE,
+		RunE:    gApp.DecodeRunE,
+		PostRun: func(cmd *cobra.Command, _ []string) {
+			cmd.ResetFlags()
+			gApp.InitDecodeFlags(cmd)
+		},
+		SilenceUsage: true,
+	}
+	gApp.InitDecodeFlags(cmd)
+	return cmd
+}
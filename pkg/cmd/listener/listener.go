@@ -106,7 +106,10 @@ func New(gApp *app.App) *cobra.Command {
 			for name, outConf := range outCfgs {
 				if outType, ok := outConf["type"]; ok {
 					if initializer, ok := outputs.Outputs[outType.(string)]; ok {
-						out := initializer()
+						out, err := outputs.WrapOutput(initializer(), outConf, gApp.Logger)
+						if err != nil {
+							return fmt.Errorf("output %q: %w", name, err)
+						}
 						go out.Init(ctx, name, outConf,
 							outputs.WithLogger(gApp.Logger),
 							outputs.WithEventProcessors(procCfg, gApp.Logger, nil, actCfg),
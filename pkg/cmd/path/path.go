@@ -30,6 +30,27 @@ func New(gApp *app.App) *cobra.Command {
 		},
 		SilenceUsage: true,
 	}
+	cmd.AddCommand(newPathFromJSONCmd(gApp))
 	gApp.InitPathFlags(cmd)
 	return cmd
 }
+
+// newPathFromJSONCmd creates the path from-json command.
+func newPathFromJSONCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "from-json",
+		Short: "map a JSON value to the leaf paths and values it sets",
+		Annotations: map[string]string{
+			"--file": "YANG",
+			"--dir":  "DIR",
+		},
+		PreRunE: gApp.PathFromJSONPreRunE,
+		RunE:    gApp.PathFromJSONRunE,
+		PostRun: func(cmd *cobra.Command, _ []string) {
+			gApp.InitPathFromJSONFlags(cmd)
+		},
+		SilenceUsage: true,
+	}
+	gApp.InitPathFromJSONFlags(cmd)
+	return cmd
+}
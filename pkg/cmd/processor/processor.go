@@ -19,5 +19,22 @@ func New(gApp *app.App) *cobra.Command {
 		SilenceUsage: true,
 	}
 	gApp.InitProcessorFlags(cmd)
+	cmd.AddCommand(newProcessorTestCmd(gApp))
+	return cmd
+}
+
+// newProcessorTestCmd creates a new processor test command.
+func newProcessorTestCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "test",
+		Short:   "test a list of processors against an input and an expected output",
+		PreRunE: gApp.ProcessorTestPreRunE,
+		RunE:    gApp.ProcessorTestRunE,
+		PostRun: func(cmd *cobra.Command, args []string) {
+			gApp.CleanupPlugins()
+		},
+		SilenceUsage: true,
+	}
+	gApp.InitProcessorTestFlags(cmd)
 	return cmd
 }
@@ -19,17 +19,26 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/openconfig/gnmic/pkg/cmd/apply"
 	"github.com/openconfig/gnmic/pkg/cmd/capabilities"
+	"github.com/openconfig/gnmic/pkg/cmd/cert"
+	"github.com/openconfig/gnmic/pkg/cmd/cluster"
+	"github.com/openconfig/gnmic/pkg/cmd/config"
+	"github.com/openconfig/gnmic/pkg/cmd/decode"
 	"github.com/openconfig/gnmic/pkg/cmd/diff"
 	"github.com/openconfig/gnmic/pkg/cmd/generate"
 	"github.com/openconfig/gnmic/pkg/cmd/get"
 	"github.com/openconfig/gnmic/pkg/cmd/getset"
+	"github.com/openconfig/gnmic/pkg/cmd/lint"
 	"github.com/openconfig/gnmic/pkg/cmd/listener"
 	"github.com/openconfig/gnmic/pkg/cmd/path"
 	"github.com/openconfig/gnmic/pkg/cmd/processor"
 	"github.com/openconfig/gnmic/pkg/cmd/proxy"
+	"github.com/openconfig/gnmic/pkg/cmd/service"
 	"github.com/openconfig/gnmic/pkg/cmd/set"
+	"github.com/openconfig/gnmic/pkg/cmd/snapshot"
 	"github.com/openconfig/gnmic/pkg/cmd/subscribe"
+	"github.com/openconfig/gnmic/pkg/cmd/target"
 	"github.com/openconfig/gnmic/pkg/cmd/version"
 )
 
@@ -79,6 +88,15 @@ func newRootCmd() *cobra.Command {
 	gApp.RootCmd.AddCommand(version.New(gApp))
 	gApp.RootCmd.AddCommand(proxy.New(gApp))
 	gApp.RootCmd.AddCommand(processor.New(gApp))
+	gApp.RootCmd.AddCommand(snapshot.New(gApp))
+	gApp.RootCmd.AddCommand(service.New(gApp))
+	gApp.RootCmd.AddCommand(config.New(gApp))
+	gApp.RootCmd.AddCommand(decode.New(gApp))
+	gApp.RootCmd.AddCommand(lint.New(gApp))
+	gApp.RootCmd.AddCommand(target.New(gApp))
+	gApp.RootCmd.AddCommand(apply.New(gApp))
+	gApp.RootCmd.AddCommand(cert.New(gApp))
+	gApp.RootCmd.AddCommand(cluster.New(gApp))
 	return gApp.RootCmd
 }
 
@@ -86,7 +104,9 @@ func newRootCmd() *cobra.Command {
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	setupCloseHandler(gApp.Cfn)
-	if err := newRootCmd().Execute(); err != nil {
+	err := newRootCmd().Execute()
+	gApp.SaveTLSSessions()
+	if err != nil {
 		//fmt.Println(err)
 		os.Exit(1)
 	}
@@ -113,10 +133,12 @@ func initConfig() {
 func setupCloseHandler(cancelFn context.CancelFunc) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	app.NotifyServiceStop(c)
 	go func() {
 		sig := <-c
 		fmt.Printf("\nreceived signal '%s'. terminating...\n", sig.String())
 		gApp.CleanupPlugins()
+		gApp.SaveTLSSessions()
 		cancelFn()
 		os.Exit(0)
 	}()
@@ -0,0 +1,62 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New create the service command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "manage gNMIc as a Windows service",
+	}
+	cmd.AddCommand(
+		newServiceInstallCmd(gApp),
+		newServiceUninstallCmd(gApp),
+		newServiceRunCmd(gApp),
+	)
+	return cmd
+}
+
+func newServiceInstallCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "install [flags] -- [gnmic command]",
+		Short:        "install gNMIc as a service running the given command",
+		RunE:         gApp.ServiceInstallRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitServiceFlags(cmd)
+	return cmd
+}
+
+func newServiceUninstallCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "uninstall",
+		Short:        "uninstall the gNMIc service",
+		RunE:         gApp.ServiceUninstallRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitServiceFlags(cmd)
+	return cmd
+}
+
+func newServiceRunCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "run [flags] -- [gnmic command]",
+		Short:        "run the gNMIc service, called by the service manager",
+		Hidden:       true,
+		RunE:         gApp.ServiceRunRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitServiceFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,44 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New create the snapshot command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "save the state of a set of targets to per target files",
+		Annotations: map[string]string{
+			"--path":   "XPATH",
+			"--prefix": "PREFIX",
+		},
+		PreRunE:      gApp.SnapshotPreRunE,
+		RunE:         gApp.SnapshotRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitSnapshotFlags(cmd)
+	cmd.AddCommand(newSnapshotDiffCmd(gApp))
+	return cmd
+}
+
+// newSnapshotDiffCmd creates a new snapshot diff command.
+func newSnapshotDiffCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "diff",
+		Short:        "compare two snapshots and report the added, removed and changed values",
+		RunE:         gApp.SnapshotDiffRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitSnapshotDiffFlags(cmd)
+	return cmd
+}
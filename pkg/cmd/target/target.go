@@ -0,0 +1,37 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+// New create the target command tree.
+func New(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "target",
+		Short: "manage the configured targets",
+	}
+	cmd.AddCommand(newTargetAddCmd(gApp))
+	return cmd
+}
+
+// newTargetAddCmd creates a new target add command.
+func newTargetAddCmd(gApp *app.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "add",
+		Short:        "probe a target and add it to the configuration file",
+		PreRunE:      gApp.TargetAddPreRunE,
+		RunE:         gApp.TargetAddRunE,
+		SilenceUsage: true,
+	}
+	gApp.InitTargetAddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,109 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+// channelOutput is the output used to send the received
+// notifications to the collector channels.
+// By default, sending to a full channel blocks until the channel is read from,
+// which also delays the other outputs since each message is exported to all
+// outputs before the next one is processed.
+// If drop is set, the messages that do not fit in a full channel are dropped
+// and counted instead.
+type channelOutput struct {
+	events    chan *formatters.EventMsg
+	responses chan *Response
+
+	drop    bool
+	dropped *atomic.Uint64
+}
+
+func (o *channelOutput) Init(context.Context, string, map[string]interface{}, ...outputs.Option) error {
+	return nil
+}
+
+func (o *channelOutput) Write(ctx context.Context, msg proto.Message, meta outputs.Meta) {
+	rsp, ok := msg.(*gnmi.SubscribeResponse)
+	if !ok || rsp == nil {
+		return
+	}
+	if o.responses != nil {
+		if !send(ctx, o.responses, &Response{Meta: meta, Response: rsp}, o.drop) {
+			o.dropped.Add(1)
+		}
+	}
+	if o.events == nil {
+		return
+	}
+	evs, err := formatters.ResponseToEventMsgs(meta["subscription-name"], rsp, meta)
+	if err != nil {
+		return
+	}
+	for _, ev := range evs {
+		o.WriteEvent(ctx, ev)
+	}
+}
+
+func (o *channelOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if o.events == nil || ev == nil {
+		return
+	}
+	if !send(ctx, o.events, ev, o.drop) {
+		o.dropped.Add(1)
+	}
+}
+
+// send writes v to ch, if drop is true it returns false
+// instead of blocking when ch is full.
+func send[T any](ctx context.Context, ch chan T, v T, drop bool) bool {
+	if drop {
+		select {
+		case ch <- v:
+		default:
+			return false
+		}
+		return true
+	}
+	select {
+	case <-ctx.Done():
+	case ch <- v:
+	}
+	return true
+}
+
+func (o *channelOutput) Close() error { return nil }
+
+func (o *channelOutput) RegisterMetrics(*prometheus.Registry) {}
+
+func (o *channelOutput) String() string { return "" }
+
+func (o *channelOutput) SetLogger(*log.Logger) {}
+
+func (o *channelOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (o *channelOutput) SetName(string) {}
+
+func (o *channelOutput) SetClusterName(string) {}
+
+func (o *channelOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
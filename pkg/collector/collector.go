@@ -0,0 +1,187 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package collector allows embedding gNMIc's collector in other Go programs.
+// A Collector subscribes to a set of gNMI targets and writes the received
+// notifications to its outputs, the built-in gNMIc outputs as well as
+// any type implementing the outputs.Output interface.
+// The received notifications can also be consumed from Go channels.
+package collector
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/app"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	eventsOutputName    = "collector-events"
+	responsesOutputName = "collector-responses"
+)
+
+// Response is a gNMI SubscribeResponse received by the collector,
+// along with its metadata (source, subscription-name,...).
+type Response struct {
+	Meta     map[string]string
+	Response *gnmi.SubscribeResponse
+}
+
+// Collector is an embeddable gNMIc collector.
+type Collector struct {
+	a *app.App
+
+	m       *sync.Mutex
+	started bool
+
+	events    chan *formatters.EventMsg
+	responses chan *Response
+	// drop messages instead of blocking when the channels are full
+	drop    bool
+	dropped *atomic.Uint64
+}
+
+// New creates a new Collector configured with the given options.
+// The collector stops when ctx is canceled or when Stop is called.
+func New(ctx context.Context, opts ...Option) (*Collector, error) {
+	c := &Collector{
+		a:       app.New(),
+		m:       new(sync.Mutex),
+		dropped: new(atomic.Uint64),
+	}
+	c.a.InitCollector()
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.a.Cfn()
+		case <-c.a.Context().Done():
+		}
+	}()
+	return c, nil
+}
+
+// Start initializes the configured outputs, starts the collector
+// and subscribes to the configured targets.
+func (c *Collector) Start() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.started {
+		return errors.New("collector already started")
+	}
+	ctx := c.a.Context()
+	go c.a.StartCollector(ctx)
+	c.a.InitOutputs(ctx)
+	if c.events != nil {
+		c.a.AddOutput(eventsOutputName, &channelOutput{events: c.events, drop: c.drop, dropped: c.dropped})
+	}
+	if c.responses != nil {
+		c.a.AddOutput(responsesOutputName, &channelOutput{responses: c.responses, drop: c.drop, dropped: c.dropped})
+	}
+	for name := range c.a.Config.Targets {
+		err := c.a.StartTarget(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+	c.started = true
+	return nil
+}
+
+// Stop stops all the targets subscriptions and closes the outputs.
+// The events and responses channels are not closed.
+func (c *Collector) Stop() {
+	c.a.Cfn()
+	c.a.CloseOutputs()
+}
+
+// AddTarget adds a target to the collector.
+// If the collector is already started, the target subscriptions are started as well.
+func (c *Collector) AddTarget(tc *types.TargetConfig) error {
+	if tc == nil {
+		return errors.New("missing target config")
+	}
+	if tc.Name == "" {
+		tc.Name = tc.Address
+	}
+	if tc.Name == "" {
+		return errors.New("missing target name and address")
+	}
+	err := c.a.Config.SetTargetConfigDefaults(tc)
+	if err != nil {
+		return err
+	}
+	err = c.a.CreateTargetConfig(tc)
+	if err != nil {
+		return err
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if !c.started {
+		return nil
+	}
+	return c.a.StartTarget(c.a.Context(), tc.Name)
+}
+
+// DeleteTarget stops the target subscriptions and removes it from the collector.
+func (c *Collector) DeleteTarget(name string) error {
+	return c.a.DeleteTarget(c.a.Context(), name)
+}
+
+// AddSubscription adds a subscription to the collector.
+// Subscriptions are applied to the targets that reference them by name
+// and to the targets without explicit subscriptions, started after the
+// subscription is added.
+func (c *Collector) AddSubscription(sc *types.SubscriptionConfig) error {
+	if sc == nil {
+		return errors.New("missing subscription config")
+	}
+	return c.a.AddSubscriptionConfig(sc)
+}
+
+// AddOutput adds an initialized output to the collector.
+// It can be a built-in gNMIc output or a custom one.
+func (c *Collector) AddOutput(name string, o outputs.Output) {
+	c.a.AddOutput(name, o)
+}
+
+// Events returns the channel the received notifications are sent to
+// as event messages, it is nil unless the collector was created
+// with the WithEventsChannel option.
+func (c *Collector) Events() <-chan *formatters.EventMsg {
+	return c.events
+}
+
+// Responses returns the channel the received SubscribeResponses are sent to,
+// it is nil unless the collector was created with the WithResponsesChannel option.
+func (c *Collector) Responses() <-chan *Response {
+	return c.responses
+}
+
+// Dropped returns the number of messages dropped because the events
+// or responses channels were full, see WithDropOnFullChannels.
+func (c *Collector) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+// Logger returns the collector logger.
+func (c *Collector) Logger() *log.Logger {
+	return c.a.Logger
+}
@@ -0,0 +1,135 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+func TestNew(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := New(ctx,
+		WithTargets(&types.TargetConfig{Address: "10.0.0.1"}),
+		WithSubscriptions(&types.SubscriptionConfig{Name: "sub1", Paths: []string{"/interface"}}),
+		WithEventsChannel(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create collector: %v", err)
+	}
+	tc, ok := c.a.Config.Targets["10.0.0.1"]
+	if !ok {
+		t.Fatalf("target not added")
+	}
+	if tc.Address != "10.0.0.1:57400" {
+		t.Errorf("unexpected target address: %s", tc.Address)
+	}
+	if sc := c.a.Config.Subscriptions["sub1"]; sc == nil || sc.Mode != "STREAM" {
+		t.Errorf("unexpected subscription config: %+v", sc)
+	}
+	err = c.AddTarget(&types.TargetConfig{Name: "10.0.0.1", Address: "10.0.0.2"})
+	if err == nil {
+		t.Errorf("expected an error adding a duplicate target")
+	}
+	err = c.AddSubscription(&types.SubscriptionConfig{Name: "sub2"})
+	if err == nil {
+		t.Errorf("expected an error adding a subscription without paths")
+	}
+}
+
+func TestChannelOutput(t *testing.T) {
+	o := &channelOutput{events: make(chan *formatters.EventMsg, 10), dropped: new(atomic.Uint64)}
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: 42,
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "counter"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 1}},
+				}},
+			},
+		},
+	}
+	o.Write(context.Background(), rsp, outputs.Meta{"source": "t1", "subscription-name": "sub1"})
+	select {
+	case ev := <-o.events:
+		if ev.Name != "sub1" || ev.Tags["source"] != "t1" || ev.Values["/counter"] != int64(1) {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatalf("no event received")
+	}
+}
+
+func TestChannelOutputDrop(t *testing.T) {
+	o := &channelOutput{
+		events:  make(chan *formatters.EventMsg, 1),
+		drop:    true,
+		dropped: new(atomic.Uint64),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			o.WriteEvent(context.Background(), &formatters.EventMsg{Name: "sub1"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("WriteEvent blocked on a full channel")
+	}
+	if len(o.events) != 1 {
+		t.Errorf("expected 1 queued event, got %d", len(o.events))
+	}
+	if d := o.dropped.Load(); d != 2 {
+		t.Errorf("expected 2 dropped events, got %d", d)
+	}
+}
+
+type closeOutput struct {
+	channelOutput
+	closed bool
+}
+
+func (o *closeOutput) Close() error {
+	o.closed = true
+	return nil
+}
+
+func TestStop(t *testing.T) {
+	o := &closeOutput{}
+	c, err := New(context.Background(), WithOutput("o1", o))
+	if err != nil {
+		t.Fatalf("failed to create collector: %v", err)
+	}
+	c.Stop()
+	if !o.closed {
+		t.Errorf("output not closed")
+	}
+	if len(c.a.Outputs) != 0 {
+		t.Errorf("outputs not removed: %v", c.a.Outputs)
+	}
+	select {
+	case <-c.a.Context().Done():
+	default:
+		t.Errorf("collector context not canceled")
+	}
+	// idempotent
+	c.Stop()
+}
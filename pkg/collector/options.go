@@ -0,0 +1,121 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+type Option func(*Collector) error
+
+// WithLogger sets the collector logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Collector) error {
+		if logger != nil {
+			c.a.Logger = logger
+		}
+		return nil
+	}
+}
+
+// WithTargets adds the targets configs to the collector.
+func WithTargets(tcs ...*types.TargetConfig) Option {
+	return func(c *Collector) error {
+		for _, tc := range tcs {
+			if err := c.AddTarget(tc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithSubscriptions adds the subscriptions configs to the collector.
+func WithSubscriptions(scs ...*types.SubscriptionConfig) Option {
+	return func(c *Collector) error {
+		for _, sc := range scs {
+			if err := c.AddSubscription(sc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithOutputConfig adds a built-in output configuration to the collector,
+// the output is initialized when the collector starts.
+// The config must include the output `type`.
+func WithOutputConfig(name string, cfg map[string]interface{}) Option {
+	return func(c *Collector) error {
+		if _, ok := cfg["type"]; !ok {
+			return fmt.Errorf("output %q is missing a type", name)
+		}
+		return c.a.AddOutputConfig(name, cfg)
+	}
+}
+
+// WithOutput adds an initialized output to the collector.
+func WithOutput(name string, o outputs.Output) Option {
+	return func(c *Collector) error {
+		if o == nil {
+			return errors.New("nil output")
+		}
+		c.a.AddOutput(name, o)
+		return nil
+	}
+}
+
+// WithProcessors sets the event processors configurations
+// referenced by the outputs configs.
+func WithProcessors(ps map[string]map[string]interface{}) Option {
+	return func(c *Collector) error {
+		for n, p := range ps {
+			c.a.Config.Processors[n] = p
+		}
+		return nil
+	}
+}
+
+// WithEventsChannel makes the collector send the received notifications
+// as event messages to a channel of the given size, see Collector.Events.
+// Unless WithDropOnFullChannels is set, a full channel blocks
+// the collector, including the writes to the other outputs.
+func WithEventsChannel(size int) Option {
+	return func(c *Collector) error {
+		c.events = make(chan *formatters.EventMsg, size)
+		return nil
+	}
+}
+
+// WithResponsesChannel makes the collector send the received SubscribeResponses
+// to a channel of the given size, see Collector.Responses.
+// Unless WithDropOnFullChannels is set, a full channel blocks
+// the collector, including the writes to the other outputs.
+func WithResponsesChannel(size int) Option {
+	return func(c *Collector) error {
+		c.responses = make(chan *Response, size)
+		return nil
+	}
+}
+
+// WithDropOnFullChannels makes the collector drop the messages
+// that do not fit in a full events or responses channel instead
+// of blocking until the channel is read from, see Collector.Dropped.
+func WithDropOnFullChannels() Option {
+	return func(c *Collector) error {
+		c.drop = true
+		return nil
+	}
+}
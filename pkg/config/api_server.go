@@ -9,17 +9,21 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
 )
 
 const (
-	defaultAPIServerAddress = ":7890"
-	defaultAPIServerTimeout = 10 * time.Second
-	trueString              = "true"
+	defaultAPIServerAddress   = ":7890"
+	defaultAPIServerPort      = "7890"
+	defaultAPIServerAdminPort = "7891"
+	defaultAPIServerTimeout   = 10 * time.Second
+	trueString                = "true"
 )
 
 type APIServer struct {
@@ -28,6 +32,19 @@ type APIServer struct {
 	TLS           *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
 	EnableMetrics bool             `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
 	Debug         bool             `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+	IPFilter      *types.IPFilter  `mapstructure:"ip-filter,omitempty" json:"ip-filter,omitempty"`
+	Admin         *APIServerAdmin  `mapstructure:"admin,omitempty" json:"admin,omitempty"`
+	// directory the admin cache dumps are written to,
+	// dumps to files are disabled if empty
+	DumpDir string `mapstructure:"dump-dir,omitempty" json:"dump-dir,omitempty"`
+}
+
+// APIServerAdmin defines a separate listener serving the mutating API endpoints,
+// the main API server address serves the read-only endpoints only.
+type APIServerAdmin struct {
+	Address  string           `mapstructure:"address,omitempty" json:"address,omitempty"`
+	TLS      *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	IPFilter *types.IPFilter  `mapstructure:"ip-filter,omitempty" json:"ip-filter,omitempty"`
 }
 
 func (c *Config) GetAPIServer() error {
@@ -41,22 +58,69 @@ func (c *Config) GetAPIServer() error {
 	}
 	c.APIServer.Timeout = c.FileConfig.GetDuration("api-server/timeout")
 	if c.FileConfig.IsSet("api-server/tls") {
-		c.APIServer.TLS = new(types.TLSConfig)
-		c.APIServer.TLS.CaFile = os.ExpandEnv(c.FileConfig.GetString("api-server/tls/ca-file"))
-		c.APIServer.TLS.CertFile = os.ExpandEnv(c.FileConfig.GetString("api-server/tls/cert-file"))
-		c.APIServer.TLS.KeyFile = os.ExpandEnv(c.FileConfig.GetString("api-server/tls/key-file"))
-		c.APIServer.TLS.ClientAuth = os.ExpandEnv(c.FileConfig.GetString("api-server/tls/client-auth"))
+		c.APIServer.TLS = c.getAPIServerTLS("api-server/tls")
 		if err := c.APIServer.TLS.Validate(); err != nil {
 			return fmt.Errorf("api-server TLS config error: %w", err)
 		}
 	}
 
+	ipFilter, err := c.getIPFilter("api-server/ip-filter")
+	if err != nil {
+		return fmt.Errorf("api-server ip-filter config error: %w", err)
+	}
+	c.APIServer.IPFilter = ipFilter
+
+	if c.FileConfig.IsSet("api-server/admin") {
+		c.APIServer.Admin = &APIServerAdmin{
+			Address: os.ExpandEnv(c.FileConfig.GetString("api-server/admin/address")),
+		}
+		if c.APIServer.Admin.Address == "" {
+			return errors.New("api-server admin address is required")
+		}
+		// the admin listener inherits the main listener TLS config and ip-filter
+		// unless they are explicitly set.
+		c.APIServer.Admin.TLS = c.APIServer.TLS
+		if c.FileConfig.IsSet("api-server/admin/tls") {
+			c.APIServer.Admin.TLS = c.getAPIServerTLS("api-server/admin/tls")
+			if err := c.APIServer.Admin.TLS.Validate(); err != nil {
+				return fmt.Errorf("api-server admin TLS config error: %w", err)
+			}
+		}
+		c.APIServer.Admin.IPFilter = c.APIServer.IPFilter
+		if c.FileConfig.IsSet("api-server/admin/ip-filter") {
+			c.APIServer.Admin.IPFilter, err = c.getIPFilter("api-server/admin/ip-filter")
+			if err != nil {
+				return fmt.Errorf("api-server admin ip-filter config error: %w", err)
+			}
+		}
+		addr, err := utils.ParseAddress(c.APIServer.Admin.Address, defaultAPIServerAdminPort)
+		if err != nil {
+			return fmt.Errorf("api-server admin address error: %w", err)
+		}
+		c.APIServer.Admin.Address = addr.String()
+	}
+
 	c.APIServer.EnableMetrics = os.ExpandEnv(c.FileConfig.GetString("api-server/enable-metrics")) == trueString
 	c.APIServer.Debug = os.ExpandEnv(c.FileConfig.GetString("api-server/debug")) == trueString
+	c.APIServer.DumpDir = os.ExpandEnv(c.FileConfig.GetString("api-server/dump-dir"))
 	c.setAPIServerDefaults()
+	addr, err := utils.ParseAddress(c.APIServer.Address, defaultAPIServerPort)
+	if err != nil {
+		return fmt.Errorf("api-server address error: %w", err)
+	}
+	c.APIServer.Address = addr.String()
 	return nil
 }
 
+func (c *Config) getAPIServerTLS(key string) *types.TLSConfig {
+	return &types.TLSConfig{
+		CaFile:     os.ExpandEnv(c.FileConfig.GetString(key + "/ca-file")),
+		CertFile:   os.ExpandEnv(c.FileConfig.GetString(key + "/cert-file")),
+		KeyFile:    os.ExpandEnv(c.FileConfig.GetString(key + "/key-file")),
+		ClientAuth: os.ExpandEnv(c.FileConfig.GetString(key + "/client-auth")),
+	}
+}
+
 func (c *Config) setAPIServerDefaults() {
 	if c.APIServer.Address == "" {
 		c.APIServer.Address = defaultAPIServerAddress
@@ -65,3 +129,25 @@ func (c *Config) setAPIServerDefaults() {
 		c.APIServer.Timeout = defaultAPIServerTimeout
 	}
 }
+
+// getIPFilter reads the allow/deny lists set under key,
+// it returns nil if key is not set.
+func (c *Config) getIPFilter(key string) (*types.IPFilter, error) {
+	if !c.FileConfig.IsSet(key) {
+		return nil, nil
+	}
+	f := &types.IPFilter{
+		Allow: c.FileConfig.GetStringSlice(key + "/allow"),
+		Deny:  c.FileConfig.GetStringSlice(key + "/deny"),
+	}
+	for i := range f.Allow {
+		f.Allow[i] = os.ExpandEnv(f.Allow[i])
+	}
+	for i := range f.Deny {
+		f.Deny[i] = os.ExpandEnv(f.Deny[i])
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,205 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+//go:embed bundles/*.yaml
+var bundlesFS embed.FS
+
+// SubscriptionBundle is a curated set of subscriptions for a vendor/OS,
+// selectable by name under a target's `bundles`.
+type SubscriptionBundle struct {
+	Name          string                               `yaml:"name,omitempty" json:"name,omitempty"`
+	Version       string                               `yaml:"version,omitempty" json:"version,omitempty"`
+	Vendor        string                               `yaml:"vendor,omitempty" json:"vendor,omitempty"`
+	OS            string                               `yaml:"os,omitempty" json:"os,omitempty"`
+	Description   string                               `yaml:"description,omitempty" json:"description,omitempty"`
+	Subscriptions map[string]*types.SubscriptionConfig `yaml:"-" json:"subscriptions,omitempty"`
+}
+
+var (
+	bundlesOnce sync.Once
+	bundles     map[string]*SubscriptionBundle
+	bundlesErr  error
+)
+
+// Bundles returns the built-in subscription bundles by name.
+func Bundles() (map[string]*SubscriptionBundle, error) {
+	bundlesOnce.Do(func() {
+		bundles, bundlesErr = loadBundles()
+	})
+	return bundles, bundlesErr
+}
+
+func loadBundles() (map[string]*SubscriptionBundle, error) {
+	entries, err := bundlesFS.ReadDir("bundles")
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]*SubscriptionBundle, len(entries))
+	for _, e := range entries {
+		b, err := bundlesFS.ReadFile(path.Join("bundles", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sb, err := parseBundle(b)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s: %w", e.Name(), err)
+		}
+		r[sb.Name] = sb
+	}
+	return r, nil
+}
+
+func parseBundle(b []byte) (*SubscriptionBundle, error) {
+	raw := struct {
+		SubscriptionBundle `yaml:",inline"`
+		Subscriptions      map[string]any `yaml:"subscriptions,omitempty"`
+	}{}
+	err := yaml.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+	sb := &raw.SubscriptionBundle
+	if sb.Name == "" {
+		return nil, fmt.Errorf("missing bundle name")
+	}
+	sb.Subscriptions = make(map[string]*types.SubscriptionConfig, len(raw.Subscriptions))
+	for sn, s := range raw.Subscriptions {
+		sc := new(types.SubscriptionConfig)
+		decoder, err := mapstructure.NewDecoder(
+			&mapstructure.DecoderConfig{
+				DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+				Result:     sc,
+			})
+		if err != nil {
+			return nil, err
+		}
+		err = decoder.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("subscription %s: %w", sn, err)
+		}
+		sc.Name = bundleSubscriptionName(sb.Name, sn)
+		err = validateAndSetDefaults(sc)
+		if err != nil {
+			return nil, err
+		}
+		sb.Subscriptions[sc.Name] = sc
+	}
+	return sb, nil
+}
+
+// bundleSubscriptionName is the name a bundle subscription is known as,
+// in the target subscriptions and in the exported messages metadata.
+func bundleSubscriptionName(bundle, sub string) string {
+	return bundle + "-" + sub
+}
+
+// getBundle returns the built-in bundle referenced by ref,
+// in the form `name` or `name@version`.
+func getBundle(ref string) (*SubscriptionBundle, error) {
+	all, err := Bundles()
+	if err != nil {
+		return nil, err
+	}
+	name, version, _ := strings.Cut(ref, "@")
+	sb, ok := all[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown subscription bundle %q, available bundles: %s",
+			name, strings.Join(sortedKeys(all), ", "))
+	}
+	if version != "" && version != sb.Version {
+		return nil, fmt.Errorf("subscription bundle %q version %q requested, version %q available",
+			name, version, sb.Version)
+	}
+	return sb, nil
+}
+
+// AutoBundle is the bundle reference replaced by the bundle matching
+// the vendor and OS detected on a target with auto-detect enabled.
+const AutoBundle = "auto"
+
+// openconfigBundleVendor is the vendor of the bundles using the OpenConfig models only.
+const openconfigBundleVendor = "openconfig"
+
+// PlatformBundle returns the name of the built-in bundle for the vendor and OS,
+// or of the OpenConfig bundle if none matches.
+func PlatformBundle(vendor, os string) (string, error) {
+	all, err := Bundles()
+	if err != nil {
+		return "", err
+	}
+	names := sortedKeys(all)
+	for _, name := range names {
+		sb := all[name]
+		if sb.Vendor == vendor && sb.OS == os {
+			return name, nil
+		}
+	}
+	for _, name := range names {
+		if all[name].Vendor == openconfigBundleVendor {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no subscription bundle for vendor %q and OS %q", vendor, os)
+}
+
+// BundlesSubscriptions returns the subscriptions of the bundles referenced in refs.
+// A subscription defined under `subscriptions` with the same name as a bundle subscription
+// takes precedence over it.
+// The AutoBundle reference is skipped, it is resolved once the target platform is detected.
+func (c *Config) BundlesSubscriptions(refs ...string) (map[string]*types.SubscriptionConfig, error) {
+	r := make(map[string]*types.SubscriptionConfig)
+	for _, ref := range refs {
+		if ref == AutoBundle {
+			continue
+		}
+		sb, err := getBundle(ref)
+		if err != nil {
+			return nil, err
+		}
+		for n, sc := range sb.Subscriptions {
+			if usc, ok := c.Subscriptions[n]; ok {
+				r[n] = usc
+				continue
+			}
+			r[n] = sc
+		}
+	}
+	return r, nil
+}
+
+// BundlesList returns the built-in subscription bundles sorted by name.
+func BundlesList() ([]*SubscriptionBundle, error) {
+	all, err := Bundles()
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*SubscriptionBundle, 0, len(all))
+	for _, sb := range all {
+		r = append(r, sb)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+	return r, nil
+}
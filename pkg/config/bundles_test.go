@@ -0,0 +1,110 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestBundles(t *testing.T) {
+	c := New()
+	c.Encoding = "json"
+	all, err := Bundles()
+	if err != nil {
+		t.Fatalf("failed to load the built-in bundles: %v", err)
+	}
+	for _, name := range []string{"openconfig-core", "srlinux-core", "sros-core"} {
+		sb, ok := all[name]
+		if !ok {
+			t.Errorf("missing bundle %q", name)
+			continue
+		}
+		if sb.Version == "" {
+			t.Errorf("bundle %q: missing version", name)
+		}
+		for _, sub := range []string{"interfaces", "bgp", "platform", "qos"} {
+			sc, ok := sb.Subscriptions[name+"-"+sub]
+			if !ok {
+				t.Errorf("bundle %q: missing subscription %q", name, sub)
+				continue
+			}
+			if sc.Mode != subscriptionDefaultMode {
+				t.Errorf("bundle %q: subscription %q: expected defaults to be set, got mode %q", name, sub, sc.Mode)
+			}
+			_, err := c.CreateSubscribeRequest(sc, &types.TargetConfig{Name: "router1"})
+			if err != nil {
+				t.Errorf("bundle %q: subscription %q: failed to create a subscribe request: %v", name, sub, err)
+			}
+		}
+	}
+}
+
+func TestGetBundle(t *testing.T) {
+	sb, err := getBundle("srlinux-core")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = getBundle("srlinux-core@" + sb.Version); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err = getBundle("srlinux-core@0.0.0"); err == nil {
+		t.Error("expected an error for an unavailable version")
+	}
+	if _, err = getBundle("unknown-core"); err == nil {
+		t.Error("expected an error for an unknown bundle")
+	}
+}
+
+func TestBundlesSubscriptions(t *testing.T) {
+	c := New()
+	override := &types.SubscriptionConfig{
+		Name:  "srlinux-core-interfaces",
+		Paths: []string{"/interface[name=ethernet-1/1]/statistics"},
+	}
+	c.Subscriptions[override.Name] = override
+	subs, err := c.BundlesSubscriptions("srlinux-core")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs) != 4 {
+		t.Errorf("expected 4 subscriptions, got %d", len(subs))
+	}
+	if subs["srlinux-core-interfaces"] != override {
+		t.Errorf("expected the user defined subscription to take precedence")
+	}
+	if _, err = c.BundlesSubscriptions("srlinux-core", "unknown"); err == nil {
+		t.Error("expected an error for an unknown bundle")
+	}
+}
+
+func TestPlatformBundle(t *testing.T) {
+	for _, tt := range []struct{ vendor, os, want string }{
+		{"nokia", "srlinux", "srlinux-core"},
+		{"nokia", "sros", "sros-core"},
+		{"arista", "eos", "openconfig-core"},
+	} {
+		got, err := PlatformBundle(tt.vendor, tt.os)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("%s/%s: expected bundle %q, got %q", tt.vendor, tt.os, tt.want, got)
+		}
+	}
+	c := New()
+	subs, err := c.BundlesSubscriptions(AutoBundle, "sros-core")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs) != 4 {
+		t.Errorf("expected the auto bundle to be skipped, got %d subscriptions", len(subs))
+	}
+}
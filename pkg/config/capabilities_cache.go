@@ -0,0 +1,46 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultCapabilitiesRefreshInterval = time.Hour
+	minCapabilitiesRefreshInterval     = 10 * time.Second
+)
+
+type capabilitiesCache struct {
+	// interval between two Capabilities requests sent to a target
+	RefreshInterval time.Duration `mapstructure:"refresh-interval,omitempty" json:"refresh-interval,omitempty"`
+}
+
+func (c *Config) GetCapabilitiesCache() error {
+	if !c.FileConfig.IsSet("capabilities-cache") {
+		return nil
+	}
+	c.CapabilitiesCache = new(capabilitiesCache)
+	c.CapabilitiesCache.RefreshInterval = c.FileConfig.GetDuration("capabilities-cache/refresh-interval")
+	if c.CapabilitiesCache.RefreshInterval < 0 {
+		return fmt.Errorf("invalid capabilities-cache refresh-interval %s", c.CapabilitiesCache.RefreshInterval)
+	}
+	c.setCapabilitiesCacheDefaults()
+	return nil
+}
+
+func (c *Config) setCapabilitiesCacheDefaults() {
+	if c.CapabilitiesCache.RefreshInterval == 0 {
+		c.CapabilitiesCache.RefreshInterval = defaultCapabilitiesRefreshInterval
+	}
+	if c.CapabilitiesCache.RefreshInterval < minCapabilitiesRefreshInterval {
+		c.CapabilitiesCache.RefreshInterval = minCapabilitiesRefreshInterval
+	}
+}
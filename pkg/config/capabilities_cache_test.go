@@ -0,0 +1,78 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCapabilitiesCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *capabilitiesCache
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "targets:\n  r1: {}\n",
+		},
+		{
+			name: "defaults",
+			in:   "capabilities-cache: {}\n",
+			want: &capabilitiesCache{RefreshInterval: defaultCapabilitiesRefreshInterval},
+		},
+		{
+			name: "refresh_interval",
+			in:   "capabilities-cache:\n  refresh-interval: 5m\n",
+			want: &capabilitiesCache{RefreshInterval: 5 * time.Minute},
+		},
+		{
+			name: "refresh_interval_too_low",
+			in:   "capabilities-cache:\n  refresh-interval: 1s\n",
+			want: &capabilitiesCache{RefreshInterval: minCapabilitiesRefreshInterval},
+		},
+		{
+			name:    "negative_refresh_interval",
+			in:      "capabilities-cache:\n  refresh-interval: -1m\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetCapabilitiesCache()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == nil {
+				if cfg.CapabilitiesCache != nil {
+					t.Errorf("expected no capabilities-cache config, got %+v", cfg.CapabilitiesCache)
+				}
+				return
+			}
+			if cfg.CapabilitiesCache == nil || *cfg.CapabilitiesCache != *tt.want {
+				t.Errorf("got %+v, expected %+v", cfg.CapabilitiesCache, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ const (
 	defaultTargetAssignmentTimeout = 10 * time.Second
 	defaultServicesWatchTimer      = 1 * time.Minute
 	defaultLeaderWaitTimer         = 5 * time.Second
+	defaultCacheBootstrapTimeout   = 10 * time.Second
+	defaultFederationTimeout       = 10 * time.Second
+	defaultFederationInstanceLabel = "gnmic_instance"
 )
 
 type clustering struct {
@@ -31,9 +34,20 @@ type clustering struct {
 	TargetAssignmentTimeout time.Duration          `mapstructure:"target-assignment-timeout,omitempty" json:"target-assignment-timeout,omitempty" yaml:"target-assignment-timeout,omitempty"`
 	LeaderWaitTimer         time.Duration          `mapstructure:"leader-wait-timer,omitempty" json:"leader-wait-timer,omitempty" yaml:"leader-wait-timer,omitempty"`
 	Tags                    []string               `mapstructure:"tags,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
+	CacheBootstrap          bool                   `mapstructure:"cache-bootstrap,omitempty" json:"cache-bootstrap,omitempty" yaml:"cache-bootstrap,omitempty"`
+	CacheBootstrapTimeout   time.Duration          `mapstructure:"cache-bootstrap-timeout,omitempty" json:"cache-bootstrap-timeout,omitempty" yaml:"cache-bootstrap-timeout,omitempty"`
+	Federation              *federation            `mapstructure:"federation,omitempty" json:"federation,omitempty" yaml:"federation,omitempty"`
 	Locker                  map[string]interface{} `mapstructure:"locker,omitempty" json:"locker,omitempty" yaml:"locker,omitempty"`
 }
 
+// federation configures the leader federated prometheus endpoint
+// aggregating the prometheus outputs of all the cluster members.
+type federation struct {
+	Enabled       bool          `mapstructure:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Timeout       time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	InstanceLabel string        `mapstructure:"instance-label,omitempty" json:"instance-label,omitempty" yaml:"instance-label,omitempty"`
+}
+
 func (c *Config) GetClustering() error {
 	if !c.FileConfig.IsSet("clustering") {
 		return nil
@@ -47,6 +61,15 @@ func (c *Config) GetClustering() error {
 	c.Clustering.ServicesWatchTimer = c.FileConfig.GetDuration("clustering/services-watch-timer")
 	c.Clustering.LeaderWaitTimer = c.FileConfig.GetDuration("clustering/leader-wait-timer")
 	c.Clustering.Tags = c.FileConfig.GetStringSlice("clustering/tags")
+	c.Clustering.CacheBootstrap = c.FileConfig.GetBool("clustering/cache-bootstrap")
+	c.Clustering.CacheBootstrapTimeout = c.FileConfig.GetDuration("clustering/cache-bootstrap-timeout")
+	if c.FileConfig.IsSet("clustering/federation") {
+		c.Clustering.Federation = &federation{
+			Enabled:       c.FileConfig.GetBool("clustering/federation/enabled"),
+			Timeout:       c.FileConfig.GetDuration("clustering/federation/timeout"),
+			InstanceLabel: c.FileConfig.GetString("clustering/federation/instance-label"),
+		}
+	}
 	for i := range c.Clustering.Tags {
 		c.Clustering.Tags[i] = os.ExpandEnv(c.Clustering.Tags[i])
 	}
@@ -86,4 +109,15 @@ func (c *Config) setClusteringDefaults() {
 	if c.Clustering.LeaderWaitTimer <= defaultLeaderWaitTimer {
 		c.Clustering.LeaderWaitTimer = defaultLeaderWaitTimer
 	}
+	if c.Clustering.CacheBootstrapTimeout <= 0 {
+		c.Clustering.CacheBootstrapTimeout = defaultCacheBootstrapTimeout
+	}
+	if c.Clustering.Federation != nil {
+		if c.Clustering.Federation.Timeout <= 0 {
+			c.Clustering.Federation.Timeout = defaultFederationTimeout
+		}
+		if c.Clustering.Federation.InstanceLabel == "" {
+			c.Clustering.Federation.InstanceLabel = defaultFederationInstanceLabel
+		}
+	}
 }
@@ -35,7 +35,9 @@ import (
 	"github.com/openconfig/gnmic/pkg/api"
 	"github.com/openconfig/gnmic/pkg/api/types"
 	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/faults"
 	gfile "github.com/openconfig/gnmic/pkg/file"
+	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
 const (
@@ -53,17 +55,22 @@ type Config struct {
 	LocalFlags  `mapstructure:",squash"`
 	FileConfig  *viper.Viper `mapstructure:"-" json:"-" yaml:"-" `
 
-	Targets       map[string]*types.TargetConfig       `mapstructure:"targets,omitempty" json:"targets,omitempty" yaml:"targets,omitempty"`
-	Subscriptions map[string]*types.SubscriptionConfig `mapstructure:"subscriptions,omitempty" json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
-	Outputs       map[string]map[string]interface{}    `mapstructure:"outputs,omitempty" json:"outputs,omitempty" yaml:"outputs,omitempty"`
-	Inputs        map[string]map[string]interface{}    `mapstructure:"inputs,omitempty" json:"inputs,omitempty" yaml:"inputs,omitempty"`
-	Processors    map[string]map[string]interface{}    `mapstructure:"processors,omitempty" json:"processors,omitempty" yaml:"processors,omitempty"`
-	Clustering    *clustering                          `mapstructure:"clustering,omitempty" json:"clustering,omitempty" yaml:"clustering,omitempty"`
-	GnmiServer    *gnmiServer                          `mapstructure:"gnmi-server,omitempty" json:"gnmi-server,omitempty" yaml:"gnmi-server,omitempty"`
-	APIServer     *APIServer                           `mapstructure:"api-server,omitempty" json:"api-server,omitempty" yaml:"api-server,omitempty"`
-	Loader        map[string]interface{}               `mapstructure:"loader,omitempty" json:"loader,omitempty" yaml:"loader,omitempty"`
-	Actions       map[string]map[string]interface{}    `mapstructure:"actions,omitempty" json:"actions,omitempty" yaml:"actions,omitempty"`
-	TunnelServer  *tunnelServer                        `mapstructure:"tunnel-server,omitempty" json:"tunnel-server,omitempty" yaml:"tunnel-server,omitempty"`
+	Targets           map[string]*types.TargetConfig       `mapstructure:"targets,omitempty" json:"targets,omitempty" yaml:"targets,omitempty"`
+	Subscriptions     map[string]*types.SubscriptionConfig `mapstructure:"subscriptions,omitempty" json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
+	Outputs           map[string]map[string]interface{}    `mapstructure:"outputs,omitempty" json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Inputs            map[string]map[string]interface{}    `mapstructure:"inputs,omitempty" json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Processors        map[string]map[string]interface{}    `mapstructure:"processors,omitempty" json:"processors,omitempty" yaml:"processors,omitempty"`
+	Clustering        *clustering                          `mapstructure:"clustering,omitempty" json:"clustering,omitempty" yaml:"clustering,omitempty"`
+	GnmiServer        *gnmiServer                          `mapstructure:"gnmi-server,omitempty" json:"gnmi-server,omitempty" yaml:"gnmi-server,omitempty"`
+	APIServer         *APIServer                           `mapstructure:"api-server,omitempty" json:"api-server,omitempty" yaml:"api-server,omitempty"`
+	Loader            map[string]interface{}               `mapstructure:"loader,omitempty" json:"loader,omitempty" yaml:"loader,omitempty"`
+	Actions           map[string]map[string]interface{}    `mapstructure:"actions,omitempty" json:"actions,omitempty" yaml:"actions,omitempty"`
+	TunnelServer      *tunnelServer                        `mapstructure:"tunnel-server,omitempty" json:"tunnel-server,omitempty" yaml:"tunnel-server,omitempty"`
+	TargetRewrite     *targetRewrite                       `mapstructure:"target-rewrite,omitempty" json:"target-rewrite,omitempty" yaml:"target-rewrite,omitempty"`
+	CapabilitiesCache *capabilitiesCache                   `mapstructure:"capabilities-cache,omitempty" json:"capabilities-cache,omitempty" yaml:"capabilities-cache,omitempty"`
+	InstanceLabels    map[string]string                    `mapstructure:"instance-labels,omitempty" json:"instance-labels,omitempty" yaml:"instance-labels,omitempty"`
+	FaultInjection    *faults.Config                       `mapstructure:"fault-injection,omitempty" json:"fault-injection,omitempty" yaml:"fault-injection,omitempty"`
+	ValueTypes        formatters.ValueTypes                `mapstructure:"value-types,omitempty" json:"value-types,omitempty" yaml:"value-types,omitempty"`
 	//
 	logger             *log.Logger
 	setRequestTemplate []*template.Template
@@ -74,6 +81,7 @@ var ValueTypes = []string{"json", "json_ietf", "string", "int", "uint", "bool",
 
 type GlobalFlags struct {
 	CfgFile       string
+	CfgOverlays   []string
 	Address       []string      `mapstructure:"address,omitempty" json:"address,omitempty" yaml:"address,omitempty"`
 	Username      string        `mapstructure:"username,omitempty" json:"username,omitempty" yaml:"username,omitempty"`
 	Password      string        `mapstructure:"password,omitempty" json:"password,omitempty" yaml:"password,omitempty"`
@@ -118,23 +126,36 @@ type GlobalFlags struct {
 	UseTunnelServer  bool          `mapstructure:"use-tunnel-server,omitempty" json:"use-tunnel-server,omitempty" yaml:"use-tunnel-server,omitempty"`
 	AuthScheme       string        `mapstructure:"auth-scheme,omitempty" json:"auth-scheme,omitempty" yaml:"auth-scheme,omitempty"`
 	CalculateLatency bool          `mapstructure:"calculate-latency,omitempty" json:"calculate-latency,omitempty" yaml:"calculate-latency,omitempty"`
+	Group            []string      `mapstructure:"group,omitempty" json:"group,omitempty" yaml:"group,omitempty"`
+	Concurrency      uint          `mapstructure:"concurrency,omitempty" json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	ExitPolicy       string        `mapstructure:"exit-policy,omitempty" json:"exit-policy,omitempty" yaml:"exit-policy,omitempty"`
+	ErrorReport      string        `mapstructure:"error-report,omitempty" json:"error-report,omitempty" yaml:"error-report,omitempty"`
+	Record           string        `mapstructure:"record,omitempty" json:"record,omitempty" yaml:"record,omitempty"`
+	Progress         bool          `mapstructure:"progress,omitempty" json:"progress,omitempty" yaml:"progress,omitempty"`
 
 	Metadata             map[string]string `mapstructure:"metadata,omitempty" json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	PluginProcessorsPath string            `mapstructure:"plugin-processors-path,omitempty" yaml:"plugin-processors-path,omitempty" json:"plugin-processors-path,omitempty"`
+	TLSSessionResumption bool              `mapstructure:"tls-session-resumption,omitempty" json:"tls-session-resumption,omitempty" yaml:"tls-session-resumption,omitempty"`
+	TLSSessionCacheFile  string            `mapstructure:"tls-session-cache-file,omitempty" json:"tls-session-cache-file,omitempty" yaml:"tls-session-cache-file,omitempty"`
+	DialRate             float64           `mapstructure:"dial-rate,omitempty" json:"dial-rate,omitempty" yaml:"dial-rate,omitempty"`
+	DialBurst            int               `mapstructure:"dial-burst,omitempty" json:"dial-burst,omitempty" yaml:"dial-burst,omitempty"`
+	DialJitter           time.Duration     `mapstructure:"dial-jitter,omitempty" json:"dial-jitter,omitempty" yaml:"dial-jitter,omitempty"`
 }
 
 type LocalFlags struct {
 	// Capabilities
-	CapabilitiesVersion bool `mapstructure:"capabilities-version,omitempty" json:"capabilities-version,omitempty" yaml:"capabilities-version,omitempty"`
+	CapabilitiesVersion              bool `mapstructure:"capabilities-version,omitempty" json:"capabilities-version,omitempty" yaml:"capabilities-version,omitempty"`
+	CapabilitiesMaxConcurrentTargets uint `mapstructure:"capabilities-max-concurrent-targets,omitempty" json:"capabilities-max-concurrent-targets,omitempty" yaml:"capabilities-max-concurrent-targets,omitempty"`
 	// Get
-	GetPath       []string `mapstructure:"get-path,omitempty" json:"get-path,omitempty" yaml:"get-path,omitempty"`
-	GetPrefix     string   `mapstructure:"get-prefix,omitempty" json:"get-prefix,omitempty" yaml:"get-prefix,omitempty"`
-	GetModel      []string `mapstructure:"get-model,omitempty" json:"get-model,omitempty" yaml:"get-model,omitempty"`
-	GetType       string   `mapstructure:"get-type,omitempty" json:"get-type,omitempty" yaml:"get-type,omitempty"`
-	GetTarget     string   `mapstructure:"get-target,omitempty" json:"get-target,omitempty" yaml:"get-target,omitempty"`
-	GetValuesOnly bool     `mapstructure:"get-values-only,omitempty" json:"get-values-only,omitempty" yaml:"get-values-only,omitempty"`
-	GetProcessor  []string `mapstructure:"get-processor,omitempty" json:"get-processor,omitempty" yaml:"get-processor,omitempty"`
-	GetDepth      uint32   `mapstructure:"get-depth,omitempty" yaml:"get-depth,omitempty" json:"get-depth,omitempty"`
+	GetPath                 []string `mapstructure:"get-path,omitempty" json:"get-path,omitempty" yaml:"get-path,omitempty"`
+	GetPrefix               string   `mapstructure:"get-prefix,omitempty" json:"get-prefix,omitempty" yaml:"get-prefix,omitempty"`
+	GetModel                []string `mapstructure:"get-model,omitempty" json:"get-model,omitempty" yaml:"get-model,omitempty"`
+	GetType                 string   `mapstructure:"get-type,omitempty" json:"get-type,omitempty" yaml:"get-type,omitempty"`
+	GetTarget               string   `mapstructure:"get-target,omitempty" json:"get-target,omitempty" yaml:"get-target,omitempty"`
+	GetValuesOnly           bool     `mapstructure:"get-values-only,omitempty" json:"get-values-only,omitempty" yaml:"get-values-only,omitempty"`
+	GetProcessor            []string `mapstructure:"get-processor,omitempty" json:"get-processor,omitempty" yaml:"get-processor,omitempty"`
+	GetDepth                uint32   `mapstructure:"get-depth,omitempty" yaml:"get-depth,omitempty" json:"get-depth,omitempty"`
+	GetMaxConcurrentTargets uint     `mapstructure:"get-max-concurrent-targets,omitempty" yaml:"get-max-concurrent-targets,omitempty" json:"get-max-concurrent-targets,omitempty"`
 	// Set
 	SetPrefix                 string        `mapstructure:"set-prefix,omitempty" json:"set-prefix,omitempty" yaml:"set-prefix,omitempty"`
 	SetDelete                 []string      `mapstructure:"set-delete,omitempty" json:"set-delete,omitempty" yaml:"set-delete,omitempty"`
@@ -165,29 +186,32 @@ type LocalFlags struct {
 	SetCommitRollbackDuration time.Duration `mapstructure:"set-commit-rollback-duration,omitempty" yaml:"set-commit-rollback-duration,omitempty" json:"set-commit-rollback-duration,omitempty"`
 	SetCommitCancel           bool          `mapstructure:"set-commit-cancel,omitempty" yaml:"set-commit-cancel,omitempty" json:"set-commit-cancel,omitempty"`
 	SetCommitConfirm          bool          `mapstructure:"set-commit-confirm,omitempty" yaml:"set-commit-confirm,omitempty" json:"set-commit-confirm,omitempty"`
+	SetMaxConcurrentTargets   uint          `mapstructure:"set-max-concurrent-targets,omitempty" yaml:"set-max-concurrent-targets,omitempty" json:"set-max-concurrent-targets,omitempty"`
+	SetValidate               bool          `mapstructure:"set-validate,omitempty" yaml:"set-validate,omitempty" json:"set-validate,omitempty"`
 	// Sub
-	SubscribePrefix            string        `mapstructure:"subscribe-prefix,omitempty" json:"subscribe-prefix,omitempty" yaml:"subscribe-prefix,omitempty"`
-	SubscribePath              []string      `mapstructure:"subscribe-path,omitempty" json:"subscribe-path,omitempty" yaml:"subscribe-path,omitempty"`
-	SubscribeQos               uint32        `mapstructure:"subscribe-qos,omitempty" json:"subscribe-qos,omitempty" yaml:"subscribe-qos,omitempty"`
-	SubscribeUpdatesOnly       bool          `mapstructure:"subscribe-updates-only,omitempty" json:"subscribe-updates-only,omitempty" yaml:"subscribe-updates-only,omitempty"`
-	SubscribeMode              string        `mapstructure:"subscribe-mode,omitempty" json:"subscribe-mode,omitempty" yaml:"subscribe-mode,omitempty"`
-	SubscribeStreamMode        string        `mapstructure:"subscribe-stream_mode,omitempty" json:"subscribe-stream-mode,omitempty" yaml:"subscribe-stream-mode,omitempty"`
-	SubscribeSampleInterval    time.Duration `mapstructure:"subscribe-sample-interval,omitempty" json:"subscribe-sample-interval,omitempty" yaml:"subscribe-sample-interval,omitempty"`
-	SubscribeSuppressRedundant bool          `mapstructure:"subscribe-suppress-redundant,omitempty" json:"subscribe-suppress-redundant,omitempty" yaml:"subscribe-suppress-redundant,omitempty"`
-	SubscribeHeartbeatInterval time.Duration `mapstructure:"subscribe-heartbeat-interval,omitempty" json:"subscribe-heartbeat-interval,omitempty" yaml:"subscribe-heartbeat-interval,omitempty"`
-	SubscribeModel             []string      `mapstructure:"subscribe-model,omitempty" json:"subscribe-model,omitempty" yaml:"subscribe-model,omitempty"`
-	SubscribeQuiet             bool          `mapstructure:"subscribe-quiet,omitempty" json:"subscribe-quiet,omitempty" yaml:"subscribe-quiet,omitempty"`
-	SubscribeTarget            string        `mapstructure:"subscribe-target,omitempty" json:"subscribe-target,omitempty" yaml:"subscribe-target,omitempty"`
-	SubscribeSetTarget         bool          `mapstructure:"subscribe-set-target,omitempty" json:"subscribe-set-target,omitempty" yaml:"subscribe-set-target,omitempty"`
-	SubscribeName              []string      `mapstructure:"subscribe-name,omitempty" json:"subscribe-name,omitempty" yaml:"subscribe-name,omitempty"`
-	SubscribeOutput            []string      `mapstructure:"subscribe-output,omitempty" json:"subscribe-output,omitempty" yaml:"subscribe-output,omitempty"`
-	SubscribeWatchConfig       bool          `mapstructure:"subscribe-watch-config,omitempty" json:"subscribe-watch-config,omitempty" yaml:"subscribe-watch-config,omitempty"`
-	SubscribeBackoff           time.Duration `mapstructure:"subscribe-backoff,omitempty" json:"subscribe-backoff,omitempty" yaml:"subscribe-backoff,omitempty"`
-	SubscribeLockRetry         time.Duration `mapstructure:"subscribe-lock-retry,omitempty" json:"subscribe-lock-retry,omitempty" yaml:"subscribe-lock-retry,omitempty"`
-	SubscribeHistorySnapshot   string        `mapstructure:"subscribe-history-snapshot,omitempty" json:"subscribe-history-snapshot,omitempty" yaml:"subscribe-history-snapshot,omitempty"`
-	SubscribeHistoryStart      string        `mapstructure:"subscribe-history-start,omitempty" json:"subscribe-history-start,omitempty" yaml:"subscribe-history-start,omitempty"`
-	SubscribeHistoryEnd        string        `mapstructure:"subscribe-history-end,omitempty" json:"subscribe-history-end,omitempty" yaml:"subscribe-history-end,omitempty"`
-	SubscribeDepth             uint32        `mapstructure:"subscribe-depth,omitempty" yaml:"subscribe-depth,omitempty" json:"subscribe-depth,omitempty"`
+	SubscribePrefix               string        `mapstructure:"subscribe-prefix,omitempty" json:"subscribe-prefix,omitempty" yaml:"subscribe-prefix,omitempty"`
+	SubscribePath                 []string      `mapstructure:"subscribe-path,omitempty" json:"subscribe-path,omitempty" yaml:"subscribe-path,omitempty"`
+	SubscribeQos                  uint32        `mapstructure:"subscribe-qos,omitempty" json:"subscribe-qos,omitempty" yaml:"subscribe-qos,omitempty"`
+	SubscribeUpdatesOnly          bool          `mapstructure:"subscribe-updates-only,omitempty" json:"subscribe-updates-only,omitempty" yaml:"subscribe-updates-only,omitempty"`
+	SubscribeMode                 string        `mapstructure:"subscribe-mode,omitempty" json:"subscribe-mode,omitempty" yaml:"subscribe-mode,omitempty"`
+	SubscribeStreamMode           string        `mapstructure:"subscribe-stream_mode,omitempty" json:"subscribe-stream-mode,omitempty" yaml:"subscribe-stream-mode,omitempty"`
+	SubscribeSampleInterval       time.Duration `mapstructure:"subscribe-sample-interval,omitempty" json:"subscribe-sample-interval,omitempty" yaml:"subscribe-sample-interval,omitempty"`
+	SubscribeSuppressRedundant    bool          `mapstructure:"subscribe-suppress-redundant,omitempty" json:"subscribe-suppress-redundant,omitempty" yaml:"subscribe-suppress-redundant,omitempty"`
+	SubscribeHeartbeatInterval    time.Duration `mapstructure:"subscribe-heartbeat-interval,omitempty" json:"subscribe-heartbeat-interval,omitempty" yaml:"subscribe-heartbeat-interval,omitempty"`
+	SubscribeModel                []string      `mapstructure:"subscribe-model,omitempty" json:"subscribe-model,omitempty" yaml:"subscribe-model,omitempty"`
+	SubscribeQuiet                bool          `mapstructure:"subscribe-quiet,omitempty" json:"subscribe-quiet,omitempty" yaml:"subscribe-quiet,omitempty"`
+	SubscribeTarget               string        `mapstructure:"subscribe-target,omitempty" json:"subscribe-target,omitempty" yaml:"subscribe-target,omitempty"`
+	SubscribeSetTarget            bool          `mapstructure:"subscribe-set-target,omitempty" json:"subscribe-set-target,omitempty" yaml:"subscribe-set-target,omitempty"`
+	SubscribeName                 []string      `mapstructure:"subscribe-name,omitempty" json:"subscribe-name,omitempty" yaml:"subscribe-name,omitempty"`
+	SubscribeOutput               []string      `mapstructure:"subscribe-output,omitempty" json:"subscribe-output,omitempty" yaml:"subscribe-output,omitempty"`
+	SubscribeWatchConfig          bool          `mapstructure:"subscribe-watch-config,omitempty" json:"subscribe-watch-config,omitempty" yaml:"subscribe-watch-config,omitempty"`
+	SubscribeBackoff              time.Duration `mapstructure:"subscribe-backoff,omitempty" json:"subscribe-backoff,omitempty" yaml:"subscribe-backoff,omitempty"`
+	SubscribeLockRetry            time.Duration `mapstructure:"subscribe-lock-retry,omitempty" json:"subscribe-lock-retry,omitempty" yaml:"subscribe-lock-retry,omitempty"`
+	SubscribeHistorySnapshot      string        `mapstructure:"subscribe-history-snapshot,omitempty" json:"subscribe-history-snapshot,omitempty" yaml:"subscribe-history-snapshot,omitempty"`
+	SubscribeHistoryStart         string        `mapstructure:"subscribe-history-start,omitempty" json:"subscribe-history-start,omitempty" yaml:"subscribe-history-start,omitempty"`
+	SubscribeHistoryEnd           string        `mapstructure:"subscribe-history-end,omitempty" json:"subscribe-history-end,omitempty" yaml:"subscribe-history-end,omitempty"`
+	SubscribeDepth                uint32        `mapstructure:"subscribe-depth,omitempty" yaml:"subscribe-depth,omitempty" json:"subscribe-depth,omitempty"`
+	SubscribeMaxConcurrentTargets uint          `mapstructure:"subscribe-max-concurrent-targets,omitempty" yaml:"subscribe-max-concurrent-targets,omitempty" json:"subscribe-max-concurrent-targets,omitempty"`
 	// Path
 	PathPathType   string `mapstructure:"path-path-type,omitempty" json:"path-path-type,omitempty" yaml:"path-path-type,omitempty"`
 	PathWithDescr  bool   `mapstructure:"path-descr,omitempty" json:"path-descr,omitempty" yaml:"path-descr,omitempty"`
@@ -196,6 +220,10 @@ type LocalFlags struct {
 	PathSearch     bool   `mapstructure:"path-search,omitempty" json:"path-search,omitempty" yaml:"path-search,omitempty"`
 	PathState      bool   `mapstructure:"path-state,omitempty" json:"path-state,omitempty" yaml:"path-state,omitempty"`
 	PathConfig     bool   `mapstructure:"path-config,omitempty" json:"path-config,omitempty" yaml:"path-config,omitempty"`
+	// path from-json
+	PathFromJSONInput    string   `mapstructure:"from-json-input,omitempty" json:"from-json-input,omitempty" yaml:"from-json-input,omitempty"`
+	PathFromJSONBasePath string   `mapstructure:"from-json-base-path,omitempty" json:"from-json-base-path,omitempty" yaml:"from-json-base-path,omitempty"`
+	PathFromJSONListKey  []string `mapstructure:"from-json-list-key,omitempty" json:"from-json-list-key,omitempty" yaml:"from-json-list-key,omitempty"`
 	// Prompt
 	PromptFile                  []string `mapstructure:"prompt-file,omitempty" json:"prompt-file,omitempty" yaml:"prompt-file,omitempty"`
 	PromptExclude               []string `mapstructure:"prompt-exclude,omitempty" json:"prompt-exclude,omitempty" yaml:"prompt-exclude,omitempty"`
@@ -266,6 +294,80 @@ type LocalFlags struct {
 	ProcessorInputDelimiter string   `mapstructure:"processor-input-delimiter,omitempty" yaml:"processor-input-delimiter,omitempty" json:"processor-input-delimiter,omitempty"`
 	ProcessorName           []string `mapstructure:"processor-name,omitempty" yaml:"processor-name,omitempty" json:"processor-name,omitempty"`
 	ProcessorOutput         string   `mapstructure:"processor-output,omitempty" yaml:"processor-output,omitempty" json:"processor-output,omitempty"`
+	// Processor test
+	ProcessorTestInput          string   `mapstructure:"processor-test-input,omitempty" yaml:"processor-test-input,omitempty" json:"processor-test-input,omitempty"`
+	ProcessorTestInputDelimiter string   `mapstructure:"processor-test-input-delimiter,omitempty" yaml:"processor-test-input-delimiter,omitempty" json:"processor-test-input-delimiter,omitempty"`
+	ProcessorTestName           []string `mapstructure:"processor-test-name,omitempty" yaml:"processor-test-name,omitempty" json:"processor-test-name,omitempty"`
+	ProcessorTestExpected       string   `mapstructure:"processor-test-expected,omitempty" yaml:"processor-test-expected,omitempty" json:"processor-test-expected,omitempty"`
+	// Lint
+	LintStrict bool `mapstructure:"lint-strict,omitempty" yaml:"lint-strict,omitempty" json:"lint-strict,omitempty"`
+	// Apply
+	ApplyIntentFile        string        `mapstructure:"apply-intent-file,omitempty" yaml:"apply-intent-file,omitempty" json:"apply-intent-file,omitempty"`
+	ApplyVars              string        `mapstructure:"apply-vars,omitempty" yaml:"apply-vars,omitempty" json:"apply-vars,omitempty"`
+	ApplyDryRun            bool          `mapstructure:"apply-dry-run,omitempty" yaml:"apply-dry-run,omitempty" json:"apply-dry-run,omitempty"`
+	ApplyReconcileInterval time.Duration `mapstructure:"apply-reconcile-interval,omitempty" yaml:"apply-reconcile-interval,omitempty" json:"apply-reconcile-interval,omitempty"`
+	ApplyReconcileAction   string        `mapstructure:"apply-reconcile-action,omitempty" yaml:"apply-reconcile-action,omitempty" json:"apply-reconcile-action,omitempty"`
+	// Cert
+	CertID         string        `mapstructure:"cert-id,omitempty" yaml:"cert-id,omitempty" json:"cert-id,omitempty"`
+	CertCACert     string        `mapstructure:"cert-ca-cert,omitempty" yaml:"cert-ca-cert,omitempty" json:"cert-ca-cert,omitempty"`
+	CertCAKey      string        `mapstructure:"cert-ca-key,omitempty" yaml:"cert-ca-key,omitempty" json:"cert-ca-key,omitempty"`
+	CertCABundle   string        `mapstructure:"cert-ca-bundle,omitempty" yaml:"cert-ca-bundle,omitempty" json:"cert-ca-bundle,omitempty"`
+	CertValidity   time.Duration `mapstructure:"cert-validity,omitempty" yaml:"cert-validity,omitempty" json:"cert-validity,omitempty"`
+	CertKeySize    uint32        `mapstructure:"cert-key-size,omitempty" yaml:"cert-key-size,omitempty" json:"cert-key-size,omitempty"`
+	CertCommonName string        `mapstructure:"cert-common-name,omitempty" yaml:"cert-common-name,omitempty" json:"cert-common-name,omitempty"`
+	CertCountry    string        `mapstructure:"cert-country,omitempty" yaml:"cert-country,omitempty" json:"cert-country,omitempty"`
+	CertState      string        `mapstructure:"cert-state,omitempty" yaml:"cert-state,omitempty" json:"cert-state,omitempty"`
+	CertCity       string        `mapstructure:"cert-city,omitempty" yaml:"cert-city,omitempty" json:"cert-city,omitempty"`
+	CertOrg        string        `mapstructure:"cert-org,omitempty" yaml:"cert-org,omitempty" json:"cert-org,omitempty"`
+	CertOrgUnit    string        `mapstructure:"cert-org-unit,omitempty" yaml:"cert-org-unit,omitempty" json:"cert-org-unit,omitempty"`
+	CertIPAddress  string        `mapstructure:"cert-ip-address,omitempty" yaml:"cert-ip-address,omitempty" json:"cert-ip-address,omitempty"`
+	CertEmailID    string        `mapstructure:"cert-email-id,omitempty" yaml:"cert-email-id,omitempty" json:"cert-email-id,omitempty"`
+	CertNoVerify   bool          `mapstructure:"cert-no-verify,omitempty" yaml:"cert-no-verify,omitempty" json:"cert-no-verify,omitempty"`
+	// Snapshot
+	SnapshotPath         []string `mapstructure:"snapshot-path,omitempty" yaml:"snapshot-path,omitempty" json:"snapshot-path,omitempty"`
+	SnapshotPrefix       string   `mapstructure:"snapshot-prefix,omitempty" yaml:"snapshot-prefix,omitempty" json:"snapshot-prefix,omitempty"`
+	SnapshotType         string   `mapstructure:"snapshot-type,omitempty" yaml:"snapshot-type,omitempty" json:"snapshot-type,omitempty"`
+	SnapshotSubscription []string `mapstructure:"snapshot-subscription,omitempty" yaml:"snapshot-subscription,omitempty" json:"snapshot-subscription,omitempty"`
+	SnapshotDir          string   `mapstructure:"snapshot-dir,omitempty" yaml:"snapshot-dir,omitempty" json:"snapshot-dir,omitempty"`
+	SnapshotFileFormat   string   `mapstructure:"snapshot-file-format,omitempty" yaml:"snapshot-file-format,omitempty" json:"snapshot-file-format,omitempty"`
+	SnapshotResume       bool     `mapstructure:"snapshot-resume,omitempty" yaml:"snapshot-resume,omitempty" json:"snapshot-resume,omitempty"`
+	// Snapshot diff
+	SnapshotDiffRef          string   `mapstructure:"snapshot-diff-ref,omitempty" yaml:"snapshot-diff-ref,omitempty" json:"snapshot-diff-ref,omitempty"`
+	SnapshotDiffCompare      string   `mapstructure:"snapshot-diff-compare,omitempty" yaml:"snapshot-diff-compare,omitempty" json:"snapshot-diff-compare,omitempty"`
+	SnapshotDiffPath         []string `mapstructure:"snapshot-diff-path,omitempty" yaml:"snapshot-diff-path,omitempty" json:"snapshot-diff-path,omitempty"`
+	SnapshotDiffReportFormat string   `mapstructure:"snapshot-diff-report-format,omitempty" yaml:"snapshot-diff-report-format,omitempty" json:"snapshot-diff-report-format,omitempty"`
+	SnapshotDiffOutput       []string `mapstructure:"snapshot-diff-output,omitempty" yaml:"snapshot-diff-output,omitempty" json:"snapshot-diff-output,omitempty"`
+	// Service
+	ServiceName        string `mapstructure:"service-name,omitempty" yaml:"service-name,omitempty" json:"service-name,omitempty"`
+	ServiceDisplayName string `mapstructure:"service-display-name,omitempty" yaml:"service-display-name,omitempty" json:"service-display-name,omitempty"`
+	ServiceDescription string `mapstructure:"service-description,omitempty" yaml:"service-description,omitempty" json:"service-description,omitempty"`
+	// Config import
+	ConfigImportFrom       string `mapstructure:"config-import-from,omitempty" yaml:"config-import-from,omitempty" json:"config-import-from,omitempty"`
+	ConfigImportFile       string `mapstructure:"config-import-file,omitempty" yaml:"config-import-file,omitempty" json:"config-import-file,omitempty"`
+	ConfigImportFileType   string `mapstructure:"config-import-file-type,omitempty" yaml:"config-import-file-type,omitempty" json:"config-import-file-type,omitempty"`
+	ConfigImportOutputFile string `mapstructure:"config-import-output-file,omitempty" yaml:"config-import-output-file,omitempty" json:"config-import-output-file,omitempty"`
+	// Config schema
+	ConfigSchemaKind string `mapstructure:"config-schema-kind,omitempty" yaml:"config-schema-kind,omitempty" json:"config-schema-kind,omitempty"`
+	ConfigSchemaType string `mapstructure:"config-schema-type,omitempty" yaml:"config-schema-type,omitempty" json:"config-schema-type,omitempty"`
+	// Target add
+	TargetAddInteractive bool   `mapstructure:"target-add-interactive,omitempty" yaml:"target-add-interactive,omitempty" json:"target-add-interactive,omitempty"`
+	TargetAddName        string `mapstructure:"target-add-name,omitempty" yaml:"target-add-name,omitempty" json:"target-add-name,omitempty"`
+	TargetAddFile        string `mapstructure:"target-add-file,omitempty" yaml:"target-add-file,omitempty" json:"target-add-file,omitempty"`
+	// Cluster plan
+	ClusterPlanRates            string  `mapstructure:"cluster-plan-rates,omitempty" yaml:"cluster-plan-rates,omitempty" json:"cluster-plan-rates,omitempty"`
+	ClusterPlanDefaultRate      float64 `mapstructure:"cluster-plan-default-rate,omitempty" yaml:"cluster-plan-default-rate,omitempty" json:"cluster-plan-default-rate,omitempty"`
+	ClusterPlanInstanceCapacity float64 `mapstructure:"cluster-plan-instance-capacity,omitempty" yaml:"cluster-plan-instance-capacity,omitempty" json:"cluster-plan-instance-capacity,omitempty"`
+	ClusterPlanMaxTargets       int     `mapstructure:"cluster-plan-max-targets,omitempty" yaml:"cluster-plan-max-targets,omitempty" json:"cluster-plan-max-targets,omitempty"`
+	ClusterPlanHeadroom         float64 `mapstructure:"cluster-plan-headroom,omitempty" yaml:"cluster-plan-headroom,omitempty" json:"cluster-plan-headroom,omitempty"`
+	ClusterPlanInstances        int     `mapstructure:"cluster-plan-instances,omitempty" yaml:"cluster-plan-instances,omitempty" json:"cluster-plan-instances,omitempty"`
+	ClusterPlanMode             string  `mapstructure:"cluster-plan-mode,omitempty" yaml:"cluster-plan-mode,omitempty" json:"cluster-plan-mode,omitempty"`
+	ClusterPlanInstancePrefix   string  `mapstructure:"cluster-plan-instance-prefix,omitempty" yaml:"cluster-plan-instance-prefix,omitempty" json:"cluster-plan-instance-prefix,omitempty"`
+	ClusterPlanOutputDir        string  `mapstructure:"cluster-plan-output-dir,omitempty" yaml:"cluster-plan-output-dir,omitempty" json:"cluster-plan-output-dir,omitempty"`
+	// Decode
+	DecodeInput       string   `mapstructure:"decode-input,omitempty" yaml:"decode-input,omitempty" json:"decode-input,omitempty"`
+	DecodeMethod      []string `mapstructure:"decode-method,omitempty" yaml:"decode-method,omitempty" json:"decode-method,omitempty"`
+	DecodeType        string   `mapstructure:"decode-type,omitempty" yaml:"decode-type,omitempty" json:"decode-type,omitempty"`
+	DecodeInputFormat string   `mapstructure:"decode-input-format,omitempty" yaml:"decode-input-format,omitempty" json:"decode-input-format,omitempty"`
 }
 
 func New() *Config {
@@ -284,6 +386,11 @@ func New() *Config {
 		nil,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 		log.New(io.Discard, configLogPrefix, utils.DefaultLoggingFlags),
 		nil,
 		make(map[string]interface{}),
@@ -324,7 +431,12 @@ func (c *Config) Load(ctx context.Context) error {
 		}
 	}
 
-	err := c.FileConfig.Unmarshal(c)
+	err := c.MergeIncludes(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.FileConfig.Unmarshal(c)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,28 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/openconfig/gnmic/pkg/faults"
+)
+
+// GetFaultInjection reads the faults injected in the messages
+// received from the targets, before they are written to the outputs.
+func (c *Config) GetFaultInjection() error {
+	if !c.FileConfig.IsSet("fault-injection") {
+		return nil
+	}
+	c.FaultInjection = new(faults.Config)
+	c.FaultInjection.Delay = c.FileConfig.GetDuration("fault-injection/delay")
+	c.FaultInjection.Jitter = c.FileConfig.GetDuration("fault-injection/jitter")
+	c.FaultInjection.DropProbability = c.FileConfig.GetFloat64("fault-injection/drop-probability")
+	c.FaultInjection.DuplicateProbability = c.FileConfig.GetFloat64("fault-injection/duplicate-probability")
+	c.FaultInjection.Seed = c.FileConfig.GetInt64("fault-injection/seed")
+	return c.FaultInjection.Validate()
+}
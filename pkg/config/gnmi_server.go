@@ -9,18 +9,27 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/path"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
 	"github.com/openconfig/gnmic/pkg/cache"
 	"google.golang.org/grpc/keepalive"
 )
 
 const (
 	defaultAddress           = ":57400"
+	defaultGNMIServerPort    = "57400"
 	defaultMaxSubscriptions  = 64
 	defaultMaxUnaryRPC       = 64
 	minimumSampleInterval    = 1 * time.Millisecond
@@ -30,6 +39,16 @@ const (
 	defaultServiceRegistrationAddress = "localhost:8500"
 	defaultRegistrationCheckInterval  = 5 * time.Second
 	defaultMaxServiceFail             = 3
+	//
+	defaultSlowConsumerQueueSize = 1000
+	defaultSlowConsumerDuration  = 30 * time.Second
+	//
+	defaultBatchingMaxUpdates = 500
+	defaultBatchingMaxDelay   = 100 * time.Millisecond
+	//
+	defaultSetRetryMaxAttempts = 3
+	defaultSetRetryBackoff     = 500 * time.Millisecond
+	defaultSetRetryMaxBackoff  = 5 * time.Second
 )
 
 type gnmiServer struct {
@@ -39,6 +58,7 @@ type gnmiServer struct {
 	MinHeartbeatInterval  time.Duration        `mapstructure:"min-heartbeat-interval,omitempty" json:"min-heartbeat-interval,omitempty"`
 	MaxSubscriptions      int64                `mapstructure:"max-subscriptions,omitempty" json:"max-subscriptions,omitempty"`
 	MaxUnaryRPC           int64                `mapstructure:"max-unary-rpc,omitempty" json:"max-unary-rpc,omitempty"`
+	MaxConcurrentTargets  uint                 `mapstructure:"max-concurrent-targets,omitempty" json:"max-concurrent-targets,omitempty"`
 	MaxRecvMsgSize        int                  `mapstructure:"max-recv-msg-size,omitempty" json:"max-recv-msg-size,omitempty"`
 	MaxSendMsgSize        int                  `mapstructure:"max-send-msg-size,omitempty" json:"max-send-msg-size,omitempty"`
 	MaxConcurrentStreams  uint32               `mapstructure:"max-concurrent-streams,omitempty" json:"max-concurrent-streams,omitempty"`
@@ -52,6 +72,129 @@ type gnmiServer struct {
 	ServiceRegistration *serviceRegistration `mapstructure:"service-registration,omitempty" json:"service-registration,omitempty"`
 	// cache config
 	Cache *cache.Config `mapstructure:"cache,omitempty" json:"cache,omitempty"`
+	// collector extension config
+	CollectorExtension *types.CollectorExtensionConfig `mapstructure:"collector-extension,omitempty" json:"collector-extension,omitempty"`
+	// file to record the exchanged gNMI messages to
+	Record string `mapstructure:"record,omitempty" json:"record,omitempty"`
+	// per client max subscriptions, the default one and the per client identity ones
+	MaxSubscriptionsPerClient int64            `mapstructure:"max-subscriptions-per-client,omitempty" json:"max-subscriptions-per-client,omitempty"`
+	ClientMaxSubscriptions    map[string]int64 `mapstructure:"client-max-subscriptions,omitempty" json:"client-max-subscriptions,omitempty"`
+	// STREAM subscriptions slow consumer detection
+	SlowConsumer *slowConsumer `mapstructure:"slow-consumer,omitempty" json:"slow-consumer,omitempty"`
+	// STREAM subscriptions responses send rate limit
+	MaxSendRate *MaxSendRate `mapstructure:"max-send-rate,omitempty" json:"max-send-rate,omitempty"`
+	// Subscribe responses batching
+	Batching *notificationBatching `mapstructure:"batching,omitempty" json:"batching,omitempty"`
+	// listener socket options
+	SocketOptions *types.SocketOptions `mapstructure:"socket-options,omitempty" json:"socket-options,omitempty"`
+	// unix socket file permissions, if the address is a unix socket
+	UnixSocket *types.UnixSocket `mapstructure:"unix-socket,omitempty" json:"unix-socket,omitempty"`
+	// listen on the socket passed by systemd socket activation
+	SocketActivation bool `mapstructure:"socket-activation,omitempty" json:"socket-activation,omitempty"`
+	// clients addresses allow/deny lists
+	IPFilter *types.IPFilter `mapstructure:"ip-filter,omitempty" json:"ip-filter,omitempty"`
+	// validate Set requests against the YANG models loaded with --file
+	ValidateSet bool `mapstructure:"validate-set,omitempty" json:"validate-set,omitempty"`
+	// accept the Set requests with the `gnmic` origin,
+	// changing the targets and subscriptions configuration
+	ConfigSet bool `mapstructure:"config-set,omitempty" json:"config-set,omitempty"`
+	// retry policy of the Set RPCs sent to the targets
+	SetRetry *setRetry `mapstructure:"set-retry,omitempty" json:"set-retry,omitempty"`
+	// compressor used for the responses, gzip or zstd
+	Compression string `mapstructure:"compression,omitempty" json:"compression,omitempty"`
+	// per client identity access rules, no access control if empty
+	ACL map[string][]*ACLRule `mapstructure:"acl,omitempty" json:"acl,omitempty"`
+	// targets names globs each client may address, by client certificate common name.
+	// no restriction if empty
+	ClientCertTargets map[string][]string `mapstructure:"client-cert-targets,omitempty" json:"client-cert-targets,omitempty"`
+}
+
+// ACLRule grants a client access to a set of targets and paths.
+type ACLRule struct {
+	// RPCs the rule applies to: get, set or subscribe.
+	// all of them if empty
+	RPCs []string `mapstructure:"rpcs,omitempty" json:"rpcs,omitempty"`
+	// targets names globs, all targets if empty.
+	// `*` matches any sequence of characters
+	Targets []string `mapstructure:"targets,omitempty" json:"targets,omitempty"`
+	// allowed paths, including the paths below them.
+	// the elements names and keys values can be globs.
+	// all paths if empty
+	Paths []string `mapstructure:"paths,omitempty" json:"paths,omitempty"`
+}
+
+type setRetry struct {
+	// maximum number of Set RPCs sent to a target, including the first one
+	MaxAttempts int `mapstructure:"max-attempts,omitempty" json:"max-attempts,omitempty"`
+	// wait time before the first retry, doubled after each retry
+	Backoff time.Duration `mapstructure:"backoff,omitempty" json:"backoff,omitempty"`
+	// maximum wait time between two attempts
+	MaxBackoff time.Duration `mapstructure:"max-backoff,omitempty" json:"max-backoff,omitempty"`
+	// retry the Set requests that are not idempotent
+	// even if a failed attempt may have been applied
+	RetryNonIdempotent bool `mapstructure:"retry-non-idempotent,omitempty" json:"retry-non-idempotent,omitempty"`
+	// ID of the registered extension carrying the per target attempts in the SetResponse
+	ExtensionID int32 `mapstructure:"extension-id,omitempty" json:"extension-id,omitempty"`
+}
+
+type notificationBatching struct {
+	// maximum number of updates in a single notification
+	MaxUpdates int `mapstructure:"max-updates,omitempty" json:"max-updates,omitempty"`
+	// maximum time an update waits for the batch it belongs to be sent
+	MaxDelay time.Duration `mapstructure:"max-delay,omitempty" json:"max-delay,omitempty"`
+}
+
+type slowConsumer struct {
+	// size of the per subscriber queue of responses waiting to be sent
+	QueueSize int `mapstructure:"queue-size,omitempty" json:"queue-size,omitempty"`
+	// queue depth above which a subscriber is considered slow
+	Threshold int `mapstructure:"threshold,omitempty" json:"threshold,omitempty"`
+	// how long the queue depth must stay above the threshold
+	// before the subscriber is disconnected
+	Duration time.Duration `mapstructure:"duration,omitempty" json:"duration,omitempty"`
+}
+
+// SendRate limits the rate at which the responses of a
+// STREAM subscription are sent. A zero rate means no limit.
+type SendRate struct {
+	// maximum number of responses sent per second
+	MessagesPerSecond float64 `mapstructure:"messages-per-second,omitempty" json:"messages-per-second,omitempty"`
+	// maximum number of responses sent at once,
+	// defaults to messages-per-second
+	MessagesBurst int `mapstructure:"messages-burst,omitempty" json:"messages-burst,omitempty"`
+	// maximum number of bytes sent per second
+	BytesPerSecond float64 `mapstructure:"bytes-per-second,omitempty" json:"bytes-per-second,omitempty"`
+	// maximum number of bytes sent at once,
+	// defaults to bytes-per-second
+	BytesBurst int `mapstructure:"bytes-burst,omitempty" json:"bytes-burst,omitempty"`
+}
+
+// MaxSendRate is the send rate applied to each STREAM subscription,
+// the default one, the per client identity ones and the per subscription ones.
+type MaxSendRate struct {
+	SendRate `mapstructure:",squash"`
+	Clients  map[string]*SendRate `mapstructure:"clients,omitempty" json:"clients,omitempty"`
+	// the first entry with a path covering the path of a subscription
+	// applies to it instead of the client send rate.
+	Subscriptions []*SubscriptionSendRate `mapstructure:"subscriptions,omitempty" json:"subscriptions,omitempty"`
+}
+
+// SubscriptionSendRate is the send rate of the subscriptions
+// which paths are covered by one of Paths.
+type SubscriptionSendRate struct {
+	// paths the send rate applies to, including the paths below them.
+	// the elements names and keys values can be globs.
+	Paths    []string `mapstructure:"paths,omitempty" json:"paths,omitempty"`
+	SendRate `mapstructure:",squash"`
+}
+
+// ClientRate returns the send rate of the subscriptions of client.
+// The client identities are case insensitive.
+func (m *MaxSendRate) ClientRate(client string) *SendRate {
+	if r, ok := m.Clients[strings.ToLower(client)]; ok {
+		return r
+	}
+	return &m.SendRate
 }
 
 type serviceRegistration struct {
@@ -121,6 +264,25 @@ func (c *Config) GetGNMIServer() error {
 		}
 		c.GnmiServer.MaxSubscriptions = int64(maxSub)
 	}
+	maxSubPerClientVal := os.ExpandEnv(c.FileConfig.GetString("gnmi-server/max-subscriptions-per-client"))
+	if maxSubPerClientVal != "" {
+		maxSub, err := strconv.ParseInt(maxSubPerClientVal, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.GnmiServer.MaxSubscriptionsPerClient = maxSub
+	}
+	clientMaxSubs := c.FileConfig.GetStringMapString("gnmi-server/client-max-subscriptions")
+	if len(clientMaxSubs) > 0 {
+		c.GnmiServer.ClientMaxSubscriptions = make(map[string]int64, len(clientMaxSubs))
+		for client, v := range clientMaxSubs {
+			maxSub, err := strconv.ParseInt(os.ExpandEnv(v), 10, 64)
+			if err != nil {
+				return fmt.Errorf("gnmi-server client %q max subscriptions: %w", client, err)
+			}
+			c.GnmiServer.ClientMaxSubscriptions[client] = maxSub
+		}
+	}
 	maxRPCVal := os.ExpandEnv(c.FileConfig.GetString("gnmi-server/max-unary-rpc"))
 	if maxRPCVal != "" {
 		maxUnaryRPC, err := strconv.Atoi(os.ExpandEnv(c.FileConfig.GetString("gnmi-server/max-unary-rpc")))
@@ -129,6 +291,14 @@ func (c *Config) GetGNMIServer() error {
 		}
 		c.GnmiServer.MaxUnaryRPC = int64(maxUnaryRPC)
 	}
+	maxTargetsVal := os.ExpandEnv(c.FileConfig.GetString("gnmi-server/max-concurrent-targets"))
+	if maxTargetsVal != "" {
+		maxTargets, err := strconv.ParseUint(maxTargetsVal, 10, 0)
+		if err != nil {
+			return err
+		}
+		c.GnmiServer.MaxConcurrentTargets = uint(maxTargets)
+	}
 	if c.FileConfig.IsSet("gnmi-server/tls") {
 		c.GnmiServer.TLS = new(types.TLSConfig)
 		c.GnmiServer.TLS.CaFile = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/tls/ca-file"))
@@ -142,7 +312,18 @@ func (c *Config) GetGNMIServer() error {
 
 	c.GnmiServer.EnableMetrics = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/enable-metrics")) == trueString
 	c.GnmiServer.Debug = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/debug")) == trueString
+	c.GnmiServer.Record = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/record"))
+	c.GnmiServer.ValidateSet = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/validate-set")) == trueString
+	c.GnmiServer.Compression = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/compression"))
+	if err := types.ValidateCompression(c.GnmiServer.Compression); err != nil {
+		return fmt.Errorf("gnmi-server: %w", err)
+	}
 	c.setGnmiServerDefaults()
+	addr, err := utils.ParseAddress(c.GnmiServer.Address, defaultGNMIServerPort)
+	if err != nil {
+		return fmt.Errorf("gnmi-server address error: %w", err)
+	}
+	c.GnmiServer.Address = addr.String()
 
 	if c.FileConfig.IsSet("gnmi-server/service-registration") {
 		c.GnmiServer.ServiceRegistration = new(serviceRegistration)
@@ -173,10 +354,300 @@ func (c *Config) GetGNMIServer() error {
 		//
 		c.GnmiServer.Cache.FetchBatchSize = c.FileConfig.GetInt("gnmi-server/cache/fetch-batch-size")
 		c.GnmiServer.Cache.FetchWaitTime = c.FileConfig.GetDuration("gnmi-server/cache/fetch-wait-time")
+		//
+		c.GnmiServer.Cache.Bucket = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/cache/bucket"))
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/slow-consumer") {
+		c.GnmiServer.SlowConsumer = new(slowConsumer)
+		c.GnmiServer.SlowConsumer.QueueSize = c.FileConfig.GetInt("gnmi-server/slow-consumer/queue-size")
+		c.GnmiServer.SlowConsumer.Threshold = c.FileConfig.GetInt("gnmi-server/slow-consumer/threshold")
+		c.GnmiServer.SlowConsumer.Duration = c.FileConfig.GetDuration("gnmi-server/slow-consumer/duration")
+		c.setGnmiServerSlowConsumerDefaults()
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/max-send-rate") {
+		c.GnmiServer.MaxSendRate, err = c.getGNMIServerMaxSendRate()
+		if err != nil {
+			return fmt.Errorf("gnmi-server max-send-rate: %w", err)
+		}
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/batching") {
+		c.GnmiServer.Batching = new(notificationBatching)
+		c.GnmiServer.Batching.MaxUpdates = c.FileConfig.GetInt("gnmi-server/batching/max-updates")
+		c.GnmiServer.Batching.MaxDelay = c.FileConfig.GetDuration("gnmi-server/batching/max-delay")
+		c.setGnmiServerBatchingDefaults()
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/set-retry") {
+		c.GnmiServer.SetRetry = new(setRetry)
+		c.GnmiServer.SetRetry.MaxAttempts = c.FileConfig.GetInt("gnmi-server/set-retry/max-attempts")
+		c.GnmiServer.SetRetry.Backoff = c.FileConfig.GetDuration("gnmi-server/set-retry/backoff")
+		c.GnmiServer.SetRetry.MaxBackoff = c.FileConfig.GetDuration("gnmi-server/set-retry/max-backoff")
+		c.GnmiServer.SetRetry.RetryNonIdempotent = c.FileConfig.GetBool("gnmi-server/set-retry/retry-non-idempotent")
+		c.GnmiServer.SetRetry.ExtensionID = c.FileConfig.GetInt32("gnmi-server/set-retry/extension-id")
+		c.setGnmiServerSetRetryDefaults()
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/socket-options") {
+		c.GnmiServer.SocketOptions = new(types.SocketOptions)
+		if c.FileConfig.IsSet("gnmi-server/socket-options/dscp") {
+			dscp := c.FileConfig.GetInt("gnmi-server/socket-options/dscp")
+			c.GnmiServer.SocketOptions.DSCP = &dscp
+		}
+		if c.FileConfig.IsSet("gnmi-server/socket-options/tos") {
+			tos := c.FileConfig.GetInt("gnmi-server/socket-options/tos")
+			c.GnmiServer.SocketOptions.TOS = &tos
+		}
+		c.GnmiServer.SocketOptions.BindToDevice = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/socket-options/bind-to-device"))
+		if c.FileConfig.IsSet("gnmi-server/socket-options/source-address") {
+			return fmt.Errorf("gnmi-server socket-options: source-address does not apply to the server listener")
+		}
+		if err := c.GnmiServer.SocketOptions.Validate(); err != nil {
+			return fmt.Errorf("gnmi-server socket-options: %w", err)
+		}
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/unix-socket") {
+		if addr.Network != "unix" {
+			return fmt.Errorf("gnmi-server unix-socket: address %q is not a unix socket", c.GnmiServer.Address)
+		}
+		c.GnmiServer.UnixSocket = &types.UnixSocket{
+			Mode:  os.ExpandEnv(c.FileConfig.GetString("gnmi-server/unix-socket/mode")),
+			Owner: os.ExpandEnv(c.FileConfig.GetString("gnmi-server/unix-socket/owner")),
+			Group: os.ExpandEnv(c.FileConfig.GetString("gnmi-server/unix-socket/group")),
+		}
+		if err := c.GnmiServer.UnixSocket.Validate(); err != nil {
+			return fmt.Errorf("gnmi-server unix-socket: %w", err)
+		}
+	}
+	c.GnmiServer.SocketActivation = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/socket-activation")) == trueString
+
+	ipFilter, err := c.getIPFilter("gnmi-server/ip-filter")
+	if err != nil {
+		return fmt.Errorf("gnmi-server ip-filter: %w", err)
+	}
+	c.GnmiServer.IPFilter = ipFilter
+
+	if c.FileConfig.IsSet("gnmi-server/acl") {
+		c.GnmiServer.ACL, err = c.getGNMIServerACL()
+		if err != nil {
+			return fmt.Errorf("gnmi-server acl: %w", err)
+		}
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/client-cert-targets") {
+		c.GnmiServer.ClientCertTargets, err = c.getGNMIServerClientCertTargets()
+		if err != nil {
+			return fmt.Errorf("gnmi-server client-cert-targets: %w", err)
+		}
+	}
+
+	c.GnmiServer.ConfigSet = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/config-set")) == trueString
+	if c.GnmiServer.ConfigSet && len(c.GnmiServer.ACL) == 0 && len(c.GnmiServer.ClientCertTargets) == 0 {
+		return errors.New("gnmi-server config-set requires the clients to be authenticated with acl or client-cert-targets")
+	}
+
+	if c.FileConfig.IsSet("gnmi-server/collector-extension") {
+		c.GnmiServer.CollectorExtension = new(types.CollectorExtensionConfig)
+		c.GnmiServer.CollectorExtension.ID = c.FileConfig.GetInt32("gnmi-server/collector-extension/id")
+		c.GnmiServer.CollectorExtension.CollectorID = os.ExpandEnv(c.FileConfig.GetString("gnmi-server/collector-extension/collector-id"))
+	}
+	return nil
+}
+
+func (c *Config) getGNMIServerMaxSendRate() (*MaxSendRate, error) {
+	msr := new(MaxSendRate)
+	err := mapstructure.WeakDecode(c.FileConfig.Get("gnmi-server/max-send-rate"), msr)
+	if err != nil {
+		return nil, err
+	}
+	if err = setSendRateDefaults(&msr.SendRate); err != nil {
+		return nil, err
+	}
+	clients := make(map[string]*SendRate, len(msr.Clients))
+	for client, r := range msr.Clients {
+		if r == nil {
+			return nil, fmt.Errorf("client %q: empty send rate", client)
+		}
+		if err = setSendRateDefaults(r); err != nil {
+			return nil, fmt.Errorf("client %q: %w", client, err)
+		}
+		clients[strings.ToLower(client)] = r
+	}
+	msr.Clients = clients
+	for i, sr := range msr.Subscriptions {
+		if sr == nil {
+			return nil, fmt.Errorf("subscription send rate %d: empty send rate", i)
+		}
+		if len(sr.Paths) == 0 {
+			return nil, fmt.Errorf("subscription send rate %d: missing paths", i)
+		}
+		for _, p := range sr.Paths {
+			if _, err = path.ParsePath(p); err != nil {
+				return nil, fmt.Errorf("subscription send rate %d: invalid path %q: %w", i, p, err)
+			}
+		}
+		if err = setSendRateDefaults(&sr.SendRate); err != nil {
+			return nil, fmt.Errorf("subscription send rate %d: %w", i, err)
+		}
+	}
+	return msr, nil
+}
+
+func setSendRateDefaults(r *SendRate) error {
+	if r.MessagesPerSecond < 0 || r.BytesPerSecond < 0 {
+		return errors.New("negative send rate")
+	}
+	if r.MessagesBurst < 0 || r.BytesBurst < 0 {
+		return errors.New("negative send burst")
+	}
+	if r.MessagesBurst == 0 {
+		r.MessagesBurst = int(math.Max(1, math.Ceil(r.MessagesPerSecond)))
+	}
+	if r.BytesBurst == 0 {
+		r.BytesBurst = int(math.Max(1, math.Ceil(r.BytesPerSecond)))
+	}
+	return nil
+}
+
+// getGNMIServerACL reads the per client access rules.
+// It requires the clients certificates to be verified.
+func (c *Config) getGNMIServerACL() (map[string][]*ACLRule, error) {
+	err := c.verifiedClientCerts()
+	if err != nil {
+		return nil, err
+	}
+	acl := make(map[string][]*ACLRule)
+	err = mapstructure.Decode(c.FileConfig.Get("gnmi-server/acl"), &acl)
+	if err != nil {
+		return nil, err
+	}
+	for client, rules := range acl {
+		for i, r := range rules {
+			if r == nil {
+				return nil, fmt.Errorf("client %q rule %d: empty rule", client, i)
+			}
+			if err := validateACLRule(r); err != nil {
+				return nil, fmt.Errorf("client %q rule %d: %w", client, i, err)
+			}
+		}
+	}
+	return acl, nil
+}
+
+// getGNMIServerClientCertTargets reads the client certificate common name to targets mapping.
+// It requires the clients certificates to be verified.
+func (c *Config) getGNMIServerClientCertTargets() (map[string][]string, error) {
+	err := c.verifiedClientCerts()
+	if err != nil {
+		return nil, err
+	}
+	cts := make(map[string][]string)
+	err = mapstructure.Decode(c.FileConfig.Get("gnmi-server/client-cert-targets"), &cts)
+	if err != nil {
+		return nil, err
+	}
+	mapping := make(map[string][]string, len(cts))
+	for cn, targets := range cts {
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("client %q: no targets", cn)
+		}
+		for i, t := range targets {
+			targets[i] = os.ExpandEnv(t)
+			if targets[i] == "" {
+				return nil, fmt.Errorf("client %q: empty target", cn)
+			}
+		}
+		mapping[strings.ToLower(cn)] = targets
+	}
+	return mapping, nil
+}
+
+// verifiedClientCerts returns an error if the gnmi-server
+// does not verify the certificates presented by the clients.
+func (c *Config) verifiedClientCerts() error {
+	if c.GnmiServer.TLS == nil {
+		return errors.New("requires tls with client-auth verify-if-given or require-verify")
+	}
+	switch c.GnmiServer.TLS.ClientAuth {
+	case "verify-if-given", "require-verify":
+		return nil
+	default:
+		return fmt.Errorf("requires tls client-auth verify-if-given or require-verify, got %q", c.GnmiServer.TLS.ClientAuth)
+	}
+}
+
+func validateACLRule(r *ACLRule) error {
+	for i, rpc := range r.RPCs {
+		r.RPCs[i] = strings.ToLower(os.ExpandEnv(rpc))
+		switch r.RPCs[i] {
+		case "get", "set", "subscribe":
+		default:
+			return fmt.Errorf("unknown rpc %q, expected one of get, set or subscribe", rpc)
+		}
+	}
+	for i, t := range r.Targets {
+		r.Targets[i] = os.ExpandEnv(t)
+		if r.Targets[i] == "" {
+			return errors.New("empty target")
+		}
+	}
+	for i, p := range r.Paths {
+		r.Paths[i] = os.ExpandEnv(p)
+		if r.Paths[i] == "" {
+			return errors.New("empty path")
+		}
+		if _, err := path.ParsePath(r.Paths[i]); err != nil {
+			return fmt.Errorf("invalid path %q: %w", p, err)
+		}
 	}
 	return nil
 }
 
+func (c *Config) setGnmiServerSlowConsumerDefaults() {
+	if c.GnmiServer.SlowConsumer.QueueSize <= 0 {
+		c.GnmiServer.SlowConsumer.QueueSize = defaultSlowConsumerQueueSize
+	}
+	if c.GnmiServer.SlowConsumer.Threshold <= 0 || c.GnmiServer.SlowConsumer.Threshold > c.GnmiServer.SlowConsumer.QueueSize {
+		c.GnmiServer.SlowConsumer.Threshold = c.GnmiServer.SlowConsumer.QueueSize * 8 / 10
+		if c.GnmiServer.SlowConsumer.Threshold == 0 {
+			c.GnmiServer.SlowConsumer.Threshold = 1
+		}
+	}
+	if c.GnmiServer.SlowConsumer.Duration <= 0 {
+		c.GnmiServer.SlowConsumer.Duration = defaultSlowConsumerDuration
+	}
+}
+
+func (c *Config) setGnmiServerBatchingDefaults() {
+	if c.GnmiServer.Batching.MaxUpdates <= 0 {
+		c.GnmiServer.Batching.MaxUpdates = defaultBatchingMaxUpdates
+	}
+	if c.GnmiServer.Batching.MaxDelay <= 0 {
+		c.GnmiServer.Batching.MaxDelay = defaultBatchingMaxDelay
+	}
+}
+
+func (c *Config) setGnmiServerSetRetryDefaults() {
+	if c.GnmiServer.SetRetry.MaxAttempts <= 0 {
+		c.GnmiServer.SetRetry.MaxAttempts = defaultSetRetryMaxAttempts
+	}
+	if c.GnmiServer.SetRetry.Backoff <= 0 {
+		c.GnmiServer.SetRetry.Backoff = defaultSetRetryBackoff
+	}
+	if c.GnmiServer.SetRetry.MaxBackoff <= 0 {
+		c.GnmiServer.SetRetry.MaxBackoff = defaultSetRetryMaxBackoff
+	}
+	if c.GnmiServer.SetRetry.MaxBackoff < c.GnmiServer.SetRetry.Backoff {
+		c.GnmiServer.SetRetry.MaxBackoff = c.GnmiServer.SetRetry.Backoff
+	}
+	if c.GnmiServer.SetRetry.ExtensionID <= 0 {
+		c.GnmiServer.SetRetry.ExtensionID = int32(api.DefaultSetAttemptsExtensionID)
+	}
+}
+
 func (c *Config) setGnmiServerDefaults() {
 	if c.GnmiServer.Address == "" {
 		c.GnmiServer.Address = defaultAddress
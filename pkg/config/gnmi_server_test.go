@@ -0,0 +1,532 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestGetGNMIServerCollectorExtension(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want *types.CollectorExtensionConfig
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: :57400\n",
+		},
+		{
+			name: "defaults",
+			in:   "gnmi-server:\n  collector-extension: {}\n",
+			want: &types.CollectorExtensionConfig{},
+		},
+		{
+			name: "id_and_collector_id",
+			in:   "gnmi-server:\n  collector-extension:\n    id: 1000\n    collector-id: gnmic1\n",
+			want: &types.CollectorExtensionConfig{ID: 1000, CollectorID: "gnmic1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := cfg.GnmiServer.CollectorExtension
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("expected no collector-extension config, got %+v", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("got %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerSocketOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantDSCP *int
+		wantDev  string
+		wantErr  bool
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: :57400\n",
+		},
+		{
+			name:     "dscp_and_device",
+			in:       "gnmi-server:\n  socket-options:\n    dscp: 46\n    bind-to-device: mgmt\n",
+			wantDSCP: pointer.ToInt(46),
+			wantDev:  "mgmt",
+		},
+		{
+			name:    "dscp_out_of_range",
+			in:      "gnmi-server:\n  socket-options:\n    dscp: 64\n",
+			wantErr: true,
+		},
+		{
+			name:    "dscp_and_tos",
+			in:      "gnmi-server:\n  socket-options:\n    dscp: 10\n    tos: 40\n",
+			wantErr: true,
+		},
+		{
+			name:    "source_address",
+			in:      "gnmi-server:\n  socket-options:\n    source-address: 10.0.0.1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got socket options %+v", cfg.GnmiServer.SocketOptions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			so := cfg.GnmiServer.SocketOptions
+			if tt.wantDSCP == nil && tt.wantDev == "" {
+				if so != nil {
+					t.Errorf("expected no socket options, got %+v", so)
+				}
+				return
+			}
+			if so == nil {
+				t.Fatal("expected socket options, got nil")
+			}
+			if so.DSCP == nil || *so.DSCP != *tt.wantDSCP {
+				t.Errorf("got dscp %v, expected %d", so.DSCP, *tt.wantDSCP)
+			}
+			if so.BindToDevice != tt.wantDev {
+				t.Errorf("got bind-to-device %q, expected %q", so.BindToDevice, tt.wantDev)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerUnixSocket(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		want       *types.UnixSocket
+		activation bool
+		wantErr    bool
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: unix:///run/gnmic/gnmi.sock\n",
+		},
+		{
+			name: "mode_and_owner",
+			in:   "gnmi-server:\n  address: unix:///run/gnmic/gnmi.sock\n  unix-socket:\n    mode: \"0660\"\n    owner: gnmic\n    group: telemetry\n",
+			want: &types.UnixSocket{Mode: "0660", Owner: "gnmic", Group: "telemetry"},
+		},
+		{
+			name:    "invalid_mode",
+			in:      "gnmi-server:\n  address: unix:///run/gnmic/gnmi.sock\n  unix-socket:\n    mode: \"0999\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "tcp_address",
+			in:      "gnmi-server:\n  address: :57400\n  unix-socket:\n    mode: \"0660\"\n",
+			wantErr: true,
+		},
+		{
+			name:       "socket_activation",
+			in:         "gnmi-server:\n  socket-activation: true\n",
+			activation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got unix socket %+v", cfg.GnmiServer.UnixSocket)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.GnmiServer.UnixSocket, tt.want) {
+				t.Errorf("got unix socket %+v, expected %+v", cfg.GnmiServer.UnixSocket, tt.want)
+			}
+			if cfg.GnmiServer.SocketActivation != tt.activation {
+				t.Errorf("got socket-activation %v, expected %v", cfg.GnmiServer.SocketActivation, tt.activation)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerACL(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string][]*ACLRule
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: :57400\n",
+		},
+		{
+			name: "rules",
+			in: `
+gnmi-server:
+  tls:
+    ca-file: ca.pem
+    cert-file: server.pem
+    key-file: server.key
+    client-auth: require-verify
+  acl:
+    Grafana:
+      - rpcs: [Subscribe, get]
+        targets: [leaf*]
+        paths:
+          - /interfaces/interface[name=ethernet-1/*]
+    admin:
+      - {}
+`,
+			want: map[string][]*ACLRule{
+				"grafana": {{
+					RPCs:    []string{"subscribe", "get"},
+					Targets: []string{"leaf*"},
+					Paths:   []string{"/interfaces/interface[name=ethernet-1/*]"},
+				}},
+				"admin": {{}},
+			},
+		},
+		{
+			name:    "no_tls",
+			in:      "gnmi-server:\n  acl:\n    c1:\n      - {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "unverified_client_cert",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: request\n  acl:\n    c1:\n      - {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown_rpc",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: verify-if-given\n  acl:\n    c1:\n      - rpcs: [capabilities]\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty_target",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: verify-if-given\n  acl:\n    c1:\n      - targets: ['']\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_path",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: verify-if-given\n  acl:\n    c1:\n      - paths: ['/interfaces/interface[name=1']\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got acl %+v", cfg.GnmiServer.ACL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.GnmiServer.ACL, tt.want) {
+				t.Errorf("got acl %+v, expected %+v", cfg.GnmiServer.ACL, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerClientCertTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: :57400\n",
+		},
+		{
+			name: "mapping",
+			in: `
+gnmi-server:
+  tls:
+    ca-file: ca.pem
+    cert-file: server.pem
+    key-file: server.key
+    client-auth: require-verify
+  client-cert-targets:
+    Grafana: [leaf1, spine*]
+    noc: ['*']
+`,
+			want: map[string][]string{
+				"grafana": {"leaf1", "spine*"},
+				"noc":     {"*"},
+			},
+		},
+		{
+			name:    "no_tls",
+			in:      "gnmi-server:\n  client-cert-targets:\n    c1: [leaf1]\n",
+			wantErr: true,
+		},
+		{
+			name:    "unverified_client_cert",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: require\n  client-cert-targets:\n    c1: [leaf1]\n",
+			wantErr: true,
+		},
+		{
+			name:    "no_targets",
+			in:      "gnmi-server:\n  tls:\n    ca-file: ca.pem\n    client-auth: verify-if-given\n  client-cert-targets:\n    c1: []\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got client-cert-targets %+v", cfg.GnmiServer.ClientCertTargets)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.GnmiServer.ClientCertTargets, tt.want) {
+				t.Errorf("got client-cert-targets %+v, expected %+v", cfg.GnmiServer.ClientCertTargets, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerConfigSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "gnmi-server:\n  address: :57400\n",
+		},
+		{
+			name:    "no_authentication",
+			in:      "gnmi-server:\n  config-set: true\n",
+			wantErr: true,
+		},
+		{
+			name: "acl",
+			in: `
+gnmi-server:
+  config-set: true
+  tls:
+    ca-file: ca.pem
+    client-auth: require-verify
+  acl:
+    admin:
+      - {}
+`,
+			want: true,
+		},
+		{
+			name: "client_cert_targets",
+			in: `
+gnmi-server:
+  config-set: true
+  tls:
+    ca-file: ca.pem
+    client-auth: require-verify
+  client-cert-targets:
+    admin: ['*']
+`,
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.GnmiServer.ConfigSet != tt.want {
+				t.Errorf("got config-set %v, expected %v", cfg.GnmiServer.ConfigSet, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGNMIServerMaxSendRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *MaxSendRate
+		wantErr bool
+	}{
+		{
+			name: "defaults_and_clients",
+			in: `
+gnmi-server:
+  max-send-rate:
+    messages-per-second: 100
+    bytes-per-second: 1048576
+    clients:
+      Grafana:
+        messages-per-second: 2.5
+        messages-burst: 10
+    subscriptions:
+      - paths:
+          - /interfaces/interface[name=*]/state/counters
+          - openconfig:/network-instances
+        bytes-per-second: 1024
+`,
+			want: &MaxSendRate{
+				SendRate: SendRate{
+					MessagesPerSecond: 100,
+					MessagesBurst:     100,
+					BytesPerSecond:    1048576,
+					BytesBurst:        1048576,
+				},
+				Clients: map[string]*SendRate{
+					"grafana": {
+						MessagesPerSecond: 2.5,
+						MessagesBurst:     10,
+						BytesBurst:        1,
+					},
+				},
+				Subscriptions: []*SubscriptionSendRate{
+					{
+						Paths: []string{
+							"/interfaces/interface[name=*]/state/counters",
+							"openconfig:/network-instances",
+						},
+						SendRate: SendRate{
+							MessagesBurst:  1,
+							BytesPerSecond: 1024,
+							BytesBurst:     1024,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "negative_rate",
+			in:      "gnmi-server:\n  max-send-rate:\n    bytes-per-second: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative_client_burst",
+			in:      "gnmi-server:\n  max-send-rate:\n    clients:\n      c1:\n        messages-burst: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "subscription_without_paths",
+			in:      "gnmi-server:\n  max-send-rate:\n    subscriptions:\n      - messages-per-second: 10\n",
+			wantErr: true,
+		},
+		{
+			name:    "subscription_invalid_path",
+			in:      "gnmi-server:\n  max-send-rate:\n    subscriptions:\n      - paths: [\"/interfaces/interface[name=1\"]\n        messages-per-second: 10\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetGNMIServer()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got max-send-rate %+v", cfg.GnmiServer.MaxSendRate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.GnmiServer.MaxSendRate, tt.want) {
+				t.Errorf("got max-send-rate %+v, expected %+v", cfg.GnmiServer.MaxSendRate, tt.want)
+			}
+			if r := cfg.GnmiServer.MaxSendRate.ClientRate("GRAFANA"); !reflect.DeepEqual(r, tt.want.Clients["grafana"]) {
+				t.Errorf("unexpected client rate %+v", r)
+			}
+			if r := cfg.GnmiServer.MaxSendRate.ClientRate("other"); *r != tt.want.SendRate {
+				t.Errorf("unexpected default rate %+v", r)
+			}
+		})
+	}
+}
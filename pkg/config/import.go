@@ -0,0 +1,518 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	ImportFormatTelegraf = "telegraf"
+	ImportFormatPipeline = "pipeline"
+)
+
+// ImportedConfig is a gNMIc configuration converted
+// from another collector configuration.
+type ImportedConfig struct {
+	Targets       map[string]map[string]interface{} `yaml:"targets,omitempty" json:"targets,omitempty"`
+	Subscriptions map[string]map[string]interface{} `yaml:"subscriptions,omitempty" json:"subscriptions,omitempty"`
+	Outputs       map[string]map[string]interface{} `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+	// the parts of the source configuration that were not converted
+	Warnings []string `yaml:"-" json:"-"`
+}
+
+// ImportConfig converts the configuration read from r into a gNMIc configuration.
+// format is the source collector, one of `telegraf` or `pipeline`.
+// configType is the source file type (toml, ini, yaml,...),
+// it defaults to toml for telegraf and to ini for pipeline.
+func ImportConfig(format, configType string, r io.Reader) (*ImportedConfig, error) {
+	if configType == "" {
+		switch format {
+		case ImportFormatTelegraf:
+			configType = "toml"
+		case ImportFormatPipeline:
+			configType = "ini"
+		}
+	}
+	v := viper.NewWithOptions(viper.KeyDelimiter("/"))
+	v.SetConfigType(configType)
+	err := v.ReadConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	ic := &ImportedConfig{
+		Targets:       make(map[string]map[string]interface{}),
+		Subscriptions: make(map[string]map[string]interface{}),
+		Outputs:       make(map[string]map[string]interface{}),
+	}
+	switch format {
+	case ImportFormatTelegraf:
+		err = ic.importTelegraf(v.AllSettings())
+	case ImportFormatPipeline:
+		err = ic.importPipeline(v.AllSettings())
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ic.Targets) == 0 {
+		return nil, fmt.Errorf("no gNMI input found in the %s configuration", format)
+	}
+	return ic, nil
+}
+
+func (ic *ImportedConfig) warnf(format string, args ...interface{}) {
+	ic.Warnings = append(ic.Warnings, fmt.Sprintf(format, args...))
+}
+
+// telegraf
+
+func (ic *ImportedConfig) importTelegraf(m map[string]interface{}) error {
+	inputs := mapValue(m, "inputs")
+	for i, in := range mapsValue(inputs, "gnmi") {
+		err := ic.importTelegrafInput(i, in)
+		if err != nil {
+			return err
+		}
+	}
+	for name := range inputs {
+		if name != "gnmi" {
+			ic.warnf("input %q is not a gNMI input, skipped", name)
+		}
+	}
+	outs := mapValue(m, "outputs")
+	names := make([]string, 0, len(outs))
+	for name := range outs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, out := range mapsValue(outs, name) {
+			ic.importTelegrafOutput(name, out)
+		}
+	}
+	return nil
+}
+
+func (ic *ImportedConfig) importTelegrafInput(idx int, in map[string]interface{}) error {
+	addrs := stringsValue(in, "addresses")
+	if len(addrs) == 0 {
+		ic.warnf("gnmi input #%d has no addresses, skipped", idx)
+		return nil
+	}
+	tc := map[string]interface{}{}
+	setString(tc, "username", stringValue(in, "username"))
+	setString(tc, "password", stringValue(in, "password"))
+	setString(tc, "encoding", stringValue(in, "encoding"))
+	if d := stringValue(in, "redial"); d != "" {
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("gnmi input #%d: invalid redial %q: %v", idx, d, err)
+		}
+		tc["retry"] = d
+	}
+	tlsCA := stringValue(in, "tls_ca")
+	tlsCert := stringValue(in, "tls_cert")
+	tlsKey := stringValue(in, "tls_key")
+	if boolValue(in, "enable_tls") || tlsCA != "" || tlsCert != "" {
+		setString(tc, "tls-ca", tlsCA)
+		setString(tc, "tls-cert", tlsCert)
+		setString(tc, "tls-key", tlsKey)
+		setString(tc, "tls-server-name", stringValue(in, "tls_server_name"))
+		if boolValue(in, "insecure_skip_verify") {
+			tc["skip-verify"] = true
+		}
+	} else {
+		tc["insecure"] = true
+	}
+	if tags := mapValue(in, "tags"); len(tags) > 0 {
+		eventTags := make(map[string]string, len(tags))
+		for k, v := range tags {
+			eventTags[k] = fmt.Sprint(v)
+		}
+		tc["event-tags"] = eventTags
+	}
+	if len(mapValue(in, "aliases")) > 0 {
+		ic.warnf("gnmi input #%d: aliases are not converted, use event processors to rename the measurements", idx)
+	}
+
+	subs := make([]string, 0)
+	for _, key := range []string{"subscription", "tag_subscription"} {
+		for i, s := range mapsValue(in, key) {
+			name, sc, err := telegrafSubscription(s, stringValue(in, "prefix"), stringValue(in, "target"))
+			if err != nil {
+				return fmt.Errorf("gnmi input #%d %s #%d: %v", idx, key, i, err)
+			}
+			if key == "tag_subscription" {
+				ic.warnf("gnmi input #%d: tag_subscription %q is converted to a regular subscription, use event processors to tag the other subscriptions values", idx, name)
+			}
+			name = uniqueName(ic.Subscriptions, name)
+			ic.Subscriptions[name] = sc
+			subs = append(subs, name)
+		}
+	}
+	if len(subs) > 0 {
+		tc["subscriptions"] = subs
+	}
+	for _, addr := range addrs {
+		ic.addTarget(addr, tc)
+	}
+	return nil
+}
+
+func telegrafSubscription(s map[string]interface{}, prefix, target string) (string, map[string]interface{}, error) {
+	p := stringValue(s, "path")
+	if p == "" {
+		return "", nil, fmt.Errorf("missing path")
+	}
+	if origin := stringValue(s, "origin"); origin != "" {
+		p = origin + ":" + p
+	}
+	name := stringValue(s, "name")
+	if name == "" {
+		name = strings.Trim(strings.ReplaceAll(p, "/", "-"), "-")
+	}
+	sc := map[string]interface{}{
+		"paths": []string{p},
+		"mode":  "stream",
+	}
+	setString(sc, "prefix", prefix)
+	setString(sc, "target", target)
+	switch mode := stringValue(s, "subscription_mode"); mode {
+	case "", "target_defined", "sample", "on_change":
+		setString(sc, "stream-mode", mode)
+	default:
+		return "", nil, fmt.Errorf("unknown subscription_mode %q", mode)
+	}
+	for _, k := range [][2]string{
+		{"sample_interval", "sample-interval"},
+		{"heartbeat_interval", "heartbeat-interval"},
+	} {
+		d := stringValue(s, k[0])
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d); err != nil {
+			return "", nil, fmt.Errorf("invalid %s %q: %v", k[0], d, err)
+		}
+		sc[k[1]] = d
+	}
+	if boolValue(s, "suppress_redundant") {
+		sc["suppress-redundant"] = true
+	}
+	return name, sc, nil
+}
+
+func (ic *ImportedConfig) importTelegrafOutput(plugin string, out map[string]interface{}) {
+	var oc map[string]interface{}
+	switch plugin {
+	case "prometheus_client":
+		oc = map[string]interface{}{"type": "prometheus"}
+		setString(oc, "listen", stringValue(out, "listen"))
+		setString(oc, "path", stringValue(out, "path"))
+	case "kafka":
+		oc = map[string]interface{}{"type": "kafka"}
+		setString(oc, "address", strings.Join(stringsValue(out, "brokers"), ","))
+		setString(oc, "topic", stringValue(out, "topic"))
+	case "influxdb_v2":
+		oc = map[string]interface{}{"type": "influxdb"}
+		setString(oc, "url", firstString(stringsValue(out, "urls")))
+		setString(oc, "org", stringValue(out, "organization"))
+		setString(oc, "bucket", stringValue(out, "bucket"))
+		setString(oc, "token", stringValue(out, "token"))
+	case "influxdb":
+		oc = map[string]interface{}{"type": "influxdb"}
+		setString(oc, "url", firstString(stringsValue(out, "urls")))
+		db := stringValue(out, "database")
+		if rp := stringValue(out, "retention_policy"); rp != "" {
+			db = db + "/" + rp
+		}
+		setString(oc, "bucket", db)
+		if u := stringValue(out, "username"); u != "" {
+			oc["token"] = u + ":" + stringValue(out, "password")
+		}
+	case "file":
+		oc = map[string]interface{}{"type": "file"}
+		switch f := firstString(stringsValue(out, "files")); f {
+		case "", "stdout", "stderr":
+			oc["file-type"] = "stdout"
+			if f == "stderr" {
+				oc["file-type"] = "stderr"
+			}
+		default:
+			oc["filename"] = f
+		}
+	default:
+		ic.warnf("output %q has no gNMIc equivalent, skipped", plugin)
+		return
+	}
+	ic.Outputs[uniqueName(ic.Outputs, strings.ReplaceAll(plugin, "_", "-"))] = oc
+}
+
+// pipeline
+
+func (ic *ImportedConfig) importPipeline(m map[string]interface{}) error {
+	sections := make([]string, 0, len(m))
+	for name := range m {
+		sections = append(sections, name)
+	}
+	sort.Strings(sections)
+	for _, name := range sections {
+		sec, ok := m[name].(map[string]interface{})
+		if !ok || len(sec) == 0 {
+			continue
+		}
+		switch stage := stringValue(sec, "stage"); stage {
+		case "xport_input":
+			err := ic.importPipelineInput(name, sec)
+			if err != nil {
+				return err
+			}
+		case "xport_output":
+			ic.importPipelineOutput(name, sec)
+		case "":
+		default:
+			ic.warnf("section %q: stage %q is not converted", name, stage)
+		}
+	}
+	return nil
+}
+
+func (ic *ImportedConfig) importPipelineInput(name string, sec map[string]interface{}) error {
+	if typ := stringValue(sec, "type"); typ != "gnmi" {
+		ic.warnf("section %q: input type %q is not a gNMI input, skipped", name, typ)
+		return nil
+	}
+	addr := stringValue(sec, "server")
+	if addr == "" {
+		return fmt.Errorf("section %q: missing server", name)
+	}
+	tc := map[string]interface{}{
+		"address": addr,
+	}
+	setString(tc, "username", stringValue(sec, "username"))
+	setString(tc, "password", stringValue(sec, "password"))
+	if boolValue(sec, "tls") {
+		setString(tc, "tls-ca", stringValue(sec, "tls_pem"))
+		setString(tc, "tls-server-name", stringValue(sec, "tls_servername"))
+	} else {
+		tc["insecure"] = true
+	}
+	var heartbeat string
+	if hb := stringValue(sec, "heartbeat_interval"); hb != "" {
+		n, err := strconv.Atoi(hb)
+		if err != nil {
+			return fmt.Errorf("section %q: invalid heartbeat_interval %q: %v", name, hb, err)
+		}
+		heartbeat = (time.Duration(n) * time.Second).String()
+	}
+	// pipeline paths are set using keys `path1`, `path2`,...
+	// with an optional `@<seconds>` or `@change` suffix
+	keys := make([]string, 0)
+	for k := range sec {
+		if strings.HasPrefix(k, "path") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	subs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		p, mode, _ := strings.Cut(stringValue(sec, k), "@")
+		if p == "" {
+			continue
+		}
+		sc := map[string]interface{}{
+			"paths": []string{p},
+			"mode":  "stream",
+		}
+		switch mode {
+		case "":
+			sc["stream-mode"] = "target_defined"
+		case "change":
+			sc["stream-mode"] = "on_change"
+		default:
+			n, err := strconv.Atoi(mode)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("section %q: invalid %s interval %q", name, k, mode)
+			}
+			sc["stream-mode"] = "sample"
+			sc["sample-interval"] = (time.Duration(n) * time.Second).String()
+		}
+		if heartbeat != "" {
+			sc["heartbeat-interval"] = heartbeat
+			sc["suppress-redundant"] = true
+		}
+		sName := uniqueName(ic.Subscriptions, name+"-"+k)
+		ic.Subscriptions[sName] = sc
+		subs = append(subs, sName)
+	}
+	if len(stringsWithPrefix(sec, "select")) > 0 {
+		ic.warnf("section %q: select filters are not converted, use event processors to filter the values", name)
+	}
+	if len(subs) > 0 {
+		tc["subscriptions"] = subs
+	}
+	ic.addTarget(name, tc)
+	return nil
+}
+
+func (ic *ImportedConfig) importPipelineOutput(name string, sec map[string]interface{}) {
+	var oc map[string]interface{}
+	switch typ := stringValue(sec, "type"); typ {
+	case "kafka":
+		oc = map[string]interface{}{"type": "kafka"}
+		setString(oc, "address", stringValue(sec, "brokers"))
+		setString(oc, "topic", stringValue(sec, "topic"))
+	case "tap":
+		oc = map[string]interface{}{"type": "file"}
+		if f := stringValue(sec, "file"); f != "" {
+			oc["filename"] = f
+		} else {
+			oc["file-type"] = "stdout"
+		}
+	case "metrics":
+		switch out := stringValue(sec, "output"); out {
+		case "influx":
+			oc = map[string]interface{}{"type": "influxdb"}
+			setString(oc, "url", stringValue(sec, "influx"))
+			setString(oc, "bucket", stringValue(sec, "database"))
+		case "prometheus":
+			oc = map[string]interface{}{"type": "prometheus"}
+			ic.warnf("section %q: the prometheus push gateway is replaced by a scraped prometheus output", name)
+		default:
+			ic.warnf("section %q: metrics output %q has no gNMIc equivalent, skipped", name, out)
+			return
+		}
+	default:
+		ic.warnf("section %q: output type %q has no gNMIc equivalent, skipped", name, typ)
+		return
+	}
+	ic.Outputs[uniqueName(ic.Outputs, name)] = oc
+}
+
+// helpers
+
+// addTarget adds a target config, if a target with the same
+// name exists its subscriptions are merged with tc's.
+func (ic *ImportedConfig) addTarget(name string, tc map[string]interface{}) {
+	if old, ok := ic.Targets[name]; ok {
+		subs, _ := old["subscriptions"].([]string)
+		nsubs, _ := tc["subscriptions"].([]string)
+		old["subscriptions"] = append(subs, nsubs...)
+		ic.warnf("target %q is defined more than once, its subscriptions are merged", name)
+		return
+	}
+	ntc := make(map[string]interface{}, len(tc))
+	for k, v := range tc {
+		ntc[k] = v
+	}
+	ic.Targets[name] = ntc
+}
+
+func uniqueName(m map[string]map[string]interface{}, name string) string {
+	if _, ok := m[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		n := fmt.Sprintf("%s-%d", name, i)
+		if _, ok := m[n]; !ok {
+			return n
+		}
+	}
+}
+
+func setString(m map[string]interface{}, k, v string) {
+	if v != "" {
+		m[k] = v
+	}
+}
+
+func firstString(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
+
+func mapValue(m map[string]interface{}, k string) map[string]interface{} {
+	v, _ := m[k].(map[string]interface{})
+	return v
+}
+
+// mapsValue returns the tables under key k,
+// whether it is an array of tables or a single table.
+func mapsValue(m map[string]interface{}, k string) []map[string]interface{} {
+	switch v := m[k].(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		r := make([]map[string]interface{}, 0, len(v))
+		for _, e := range v {
+			if em, ok := e.(map[string]interface{}); ok {
+				r = append(r, em)
+			}
+		}
+		return r
+	case []map[string]interface{}:
+		return v
+	}
+	return nil
+}
+
+func stringValue(m map[string]interface{}, k string) string {
+	v, ok := m[k]
+	if !ok || v == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(v))
+}
+
+func stringsValue(m map[string]interface{}, k string) []string {
+	switch v := m[k].(type) {
+	case []interface{}:
+		r := make([]string, 0, len(v))
+		for _, e := range v {
+			r = append(r, fmt.Sprint(e))
+		}
+		return r
+	case []string:
+		return v
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+func stringsWithPrefix(m map[string]interface{}, prefix string) []string {
+	r := make([]string, 0)
+	for k := range m {
+		if strings.HasPrefix(k, prefix) {
+			r = append(r, k)
+		}
+	}
+	return r
+}
+
+func boolValue(m map[string]interface{}, k string) bool {
+	switch v := m[k].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImportConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		configType string
+		in         string
+		want       *ImportedConfig
+		wantErr    bool
+	}{
+		{
+			name:   "telegraf",
+			format: ImportFormatTelegraf,
+			in: `
+[[inputs.gnmi]]
+  addresses = ["10.0.0.1:57400", "10.0.0.2:57400"]
+  username = "admin"
+  password = "secret"
+  redial = "10s"
+  [inputs.gnmi.tags]
+    site = "dc1"
+  [[inputs.gnmi.subscription]]
+    name = "ifcounters"
+    origin = "openconfig"
+    path = "/interfaces/interface/state/counters"
+    subscription_mode = "sample"
+    sample_interval = "10s"
+[[outputs.prometheus_client]]
+  listen = ":9273"
+[[outputs.influxdb]]
+  urls = ["http://influx:8086"]
+  database = "telegraf"
+  username = "user"
+  password = "pass"
+[[outputs.http]]
+  url = "http://localhost"
+`,
+			want: &ImportedConfig{
+				Targets: map[string]map[string]interface{}{
+					"10.0.0.1:57400": {
+						"username":      "admin",
+						"password":      "secret",
+						"retry":         "10s",
+						"insecure":      true,
+						"event-tags":    map[string]string{"site": "dc1"},
+						"subscriptions": []string{"ifcounters"},
+					},
+					"10.0.0.2:57400": {
+						"username":      "admin",
+						"password":      "secret",
+						"retry":         "10s",
+						"insecure":      true,
+						"event-tags":    map[string]string{"site": "dc1"},
+						"subscriptions": []string{"ifcounters"},
+					},
+				},
+				Subscriptions: map[string]map[string]interface{}{
+					"ifcounters": {
+						"paths":           []string{"openconfig:/interfaces/interface/state/counters"},
+						"mode":            "stream",
+						"stream-mode":     "sample",
+						"sample-interval": "10s",
+					},
+				},
+				Outputs: map[string]map[string]interface{}{
+					"prometheus-client": {"type": "prometheus", "listen": ":9273"},
+					"influxdb": {
+						"type":   "influxdb",
+						"url":    "http://influx:8086",
+						"bucket": "telegraf",
+						"token":  "user:pass",
+					},
+				},
+				Warnings: []string{`output "http" has no gNMIc equivalent, skipped`},
+			},
+		},
+		{
+			name:   "telegraf_invalid_mode",
+			format: ImportFormatTelegraf,
+			in: `
+[[inputs.gnmi]]
+  addresses = ["10.0.0.1:57400"]
+  [[inputs.gnmi.subscription]]
+    path = "/interfaces"
+    subscription_mode = "poll"
+`,
+			wantErr: true,
+		},
+		{
+			name:    "telegraf_no_gnmi_input",
+			format:  ImportFormatTelegraf,
+			in:      "[[inputs.cpu]]\n",
+			wantErr: true,
+		},
+		{
+			name:   "pipeline",
+			format: ImportFormatPipeline,
+			in: `
+[my.router]
+stage = xport_input
+type = gnmi
+server = 10.0.0.3:57400
+tls = true
+tls_pem = /certs/ca.pem
+heartbeat_interval = 60
+path1 = Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters@10
+path2 = /interfaces/interface/state@change
+path3 = /system
+
+[kafka]
+stage = xport_output
+type = kafka
+brokers = kafka1:9092,kafka2:9092
+topic = telemetry
+`,
+			want: &ImportedConfig{
+				Targets: map[string]map[string]interface{}{
+					"my.router": {
+						"address":       "10.0.0.3:57400",
+						"tls-ca":        "/certs/ca.pem",
+						"subscriptions": []string{"my.router-path1", "my.router-path2", "my.router-path3"},
+					},
+				},
+				Subscriptions: map[string]map[string]interface{}{
+					"my.router-path1": {
+						"paths":              []string{"Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters"},
+						"mode":               "stream",
+						"stream-mode":        "sample",
+						"sample-interval":    "10s",
+						"heartbeat-interval": "1m0s",
+						"suppress-redundant": true,
+					},
+					"my.router-path2": {
+						"paths":              []string{"/interfaces/interface/state"},
+						"mode":               "stream",
+						"stream-mode":        "on_change",
+						"heartbeat-interval": "1m0s",
+						"suppress-redundant": true,
+					},
+					"my.router-path3": {
+						"paths":              []string{"/system"},
+						"mode":               "stream",
+						"stream-mode":        "target_defined",
+						"heartbeat-interval": "1m0s",
+						"suppress-redundant": true,
+					},
+				},
+				Outputs: map[string]map[string]interface{}{
+					"kafka": {"type": "kafka", "address": "kafka1:9092,kafka2:9092", "topic": "telemetry"},
+				},
+			},
+		},
+		{
+			name:       "pipeline_yaml",
+			format:     ImportFormatPipeline,
+			configType: "yaml",
+			in: `
+r1:
+  stage: xport_input
+  type: gnmi
+  server: 10.0.0.4:57400
+  path1: /system@30
+`,
+			want: &ImportedConfig{
+				Targets: map[string]map[string]interface{}{
+					"r1": {
+						"address":       "10.0.0.4:57400",
+						"insecure":      true,
+						"subscriptions": []string{"r1-path1"},
+					},
+				},
+				Subscriptions: map[string]map[string]interface{}{
+					"r1-path1": {
+						"paths":           []string{"/system"},
+						"mode":            "stream",
+						"stream-mode":     "sample",
+						"sample-interval": "30s",
+					},
+				},
+				Outputs: map[string]map[string]interface{}{},
+			},
+		},
+		{
+			name:    "pipeline_invalid_interval",
+			format:  ImportFormatPipeline,
+			in:      "[r1]\nstage = xport_input\ntype = gnmi\nserver = 10.0.0.4:57400\npath1 = /system@fast\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown_format",
+			format:  "collectd",
+			in:      "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configType := tt.configType
+			if configType == "" && tt.format == "collectd" {
+				configType = "yaml"
+			}
+			got, err := ImportConfig(tt.format, configType, strings.NewReader(tt.in))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected imported config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
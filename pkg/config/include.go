@@ -0,0 +1,159 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	gfile "github.com/openconfig/gnmic/pkg/file"
+)
+
+// includeKey is the config file key listing the files it includes.
+const includeKey = "include"
+
+// MergeIncludes merges the files included by the main config file
+// and the config overlays into the file config.
+// The precedence, from lowest to highest, is:
+// the included files in the order they are listed, the file including them,
+// then the overlays in the order they are set.
+// Environment variables and flags take precedence over all files.
+func (c *Config) MergeIncludes(ctx context.Context) error {
+	if !c.FileConfig.IsSet(includeKey) && len(c.GlobalFlags.CfgOverlays) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{})
+	if cfgFile := c.FileConfig.ConfigFileUsed(); cfgFile != "" {
+		fm, err := readConfigFile(ctx, cfgFile, nil)
+		if err != nil {
+			return err
+		}
+		m = fm
+	}
+	for _, overlay := range c.GlobalFlags.CfgOverlays {
+		om, err := readConfigFile(ctx, overlay, nil)
+		if err != nil {
+			return err
+		}
+		mergeConfigMaps(m, om)
+	}
+	return c.FileConfig.MergeConfigMap(m)
+}
+
+// readConfigFile reads the config file at path merged on top of the files it includes.
+// parents are the files including it, used to detect include loops.
+func readConfigFile(ctx context.Context, path string, parents []string) (map[string]interface{}, error) {
+	for _, p := range parents {
+		if p == path {
+			return nil, fmt.Errorf("config include loop: %s -> %s", strings.Join(parents, " -> "), path)
+		}
+	}
+	b, err := gfile.ReadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	v := viper.NewWithOptions(viper.KeyDelimiter("/"))
+	v.SetConfigType(configFileType(path))
+	err = v.ReadConfig(bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	fm := v.AllSettings()
+	includes, err := includedFiles(path, v.GetStringSlice(includeKey))
+	if err != nil {
+		return nil, err
+	}
+	delete(fm, includeKey)
+	m := make(map[string]interface{})
+	for _, inc := range includes {
+		im, err := readConfigFile(ctx, inc, append(parents, path))
+		if err != nil {
+			return nil, err
+		}
+		mergeConfigMaps(m, im)
+	}
+	mergeConfigMaps(m, fm)
+	return m, nil
+}
+
+// includedFiles returns the files included by the config file at path.
+// Relative paths are relative to the directory of the including file,
+// local paths can be glob patterns, the matching files are included in lexical order.
+func includedFiles(path string, includes []string) ([]string, error) {
+	r := make([]string, 0, len(includes))
+	for _, inc := range includes {
+		if isRemotePath(inc) {
+			r = append(r, inc)
+			continue
+		}
+		if isRemotePath(path) && !filepath.IsAbs(inc) {
+			// relative to the including file URL
+			r = append(r, path[:strings.LastIndex(path, "/")+1]+inc)
+			continue
+		}
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(filepath.Dir(path), inc)
+		}
+		matches, err := filepath.Glob(inc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid include %q: %w", path, inc, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: included file %q not found", path, inc)
+		}
+		sort.Strings(matches)
+		r = append(r, matches...)
+	}
+	return r, nil
+}
+
+func isRemotePath(path string) bool {
+	for _, scheme := range []string{"http://", "https://", "ftp://", "sftp://"} {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// configFileType returns the config type of the file at path based on its extension,
+// defaults to yaml.
+func configFileType(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	for _, e := range viper.SupportedExts {
+		if e == ext {
+			return ext
+		}
+	}
+	return "yaml"
+}
+
+// mergeConfigMaps merges src into dst, the maps are merged recursively
+// and any other value in src replaces the one in dst, lists included.
+func mergeConfigMaps(dst, src map[string]interface{}) {
+	for k, sv := range src {
+		sm, ok := sv.(map[string]interface{})
+		if !ok {
+			dst[k] = sv
+			continue
+		}
+		dm, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dm = make(map[string]interface{}, len(sm))
+			dst[k] = dm
+		}
+		mergeConfigMaps(dm, sm)
+	}
+}
@@ -0,0 +1,101 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadIncludesAndOverlays(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"base.yaml": `
+include:
+  - targets/*.yaml
+timeout: 5s
+subscriptions:
+  sub1:
+    paths:
+      - /interfaces
+    sample-interval: 10s
+`,
+		"targets/a.yaml": `
+timeout: 1s
+targets:
+  router1:
+    address: router1:57400
+`,
+		"targets/b.yaml": `
+targets:
+  router2:
+    address: router2:57400
+`,
+		"prod.yaml": `
+subscriptions:
+  sub1:
+    sample-interval: 30s
+targets:
+  router2:
+    address: router2.prod:57400
+`,
+	})
+	c := New()
+	c.GlobalFlags.CfgFile = filepath.Join(dir, "base.yaml")
+	c.GlobalFlags.CfgOverlays = []string{filepath.Join(dir, "prod.yaml")}
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	fc := c.FileConfig
+	for k, want := range map[string]string{
+		// the including file takes precedence over the included ones
+		"timeout": "5s",
+		// the overlays take precedence over the main file
+		"subscriptions/sub1/sample-interval": "30s",
+		"targets/router1/address":            "router1:57400",
+		"targets/router2/address":            "router2.prod:57400",
+	} {
+		if got := fc.GetString(k); got != want {
+			t.Errorf("%s: expected %q, got %q", k, want, got)
+		}
+	}
+	if got := fc.GetStringSlice("subscriptions/sub1/paths"); len(got) != 1 || got[0] != "/interfaces" {
+		t.Errorf("expected the overlay to keep the base paths, got %v", got)
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"loop1.yaml":   "include: [loop2.yaml]\n",
+		"loop2.yaml":   "include: [loop1.yaml]\n",
+		"missing.yaml": "include: [nothere.yaml]\n",
+	})
+	for _, f := range []string{"loop1.yaml", "missing.yaml"} {
+		c := New()
+		c.GlobalFlags.CfgFile = filepath.Join(dir, f)
+		if err := c.Load(context.Background()); err == nil {
+			t.Errorf("%s: expected an error", f)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// instance label names are valid prometheus label names,
+// since they are added to the gNMIc metrics.
+var instanceLabelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// GetInstanceLabels reads the labels identifying this gNMIc instance,
+// their values can reference environment variables.
+func (c *Config) GetInstanceLabels() error {
+	if !c.FileConfig.IsSet("instance-labels") {
+		return nil
+	}
+	labels := c.FileConfig.GetStringMapString("instance-labels")
+	c.InstanceLabels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		if !instanceLabelNameRegex.MatchString(k) {
+			return fmt.Errorf("invalid instance label name %q", k)
+		}
+		c.InstanceLabels[k] = os.ExpandEnv(v)
+	}
+	return nil
+}
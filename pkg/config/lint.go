@@ -0,0 +1,311 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+const (
+	lintCheckUnusedOutput        = "unused-output"
+	lintCheckUnusedProcessor     = "unused-processor"
+	lintCheckMissingOutput       = "missing-output"
+	lintCheckMissingProcessor    = "missing-processor"
+	lintCheckMissingSubscription = "missing-subscription"
+	lintCheckDuplicateAddress    = "duplicate-address"
+	lintCheckRegex               = "regex"
+	lintCheckTemplate            = "template"
+)
+
+// processors config attributes holding lists of regular expressions.
+var lintRegexAttributes = []string{"value-names", "values", "tag-names", "tags", "deletes"}
+
+// xpath keys written in a regular expression without escaping the brackets,
+// e.g: interface[name=ethernet-1/1]
+var lintUnescapedXPathKeys = regexp.MustCompile(`(^|[^\\])\[[^\]]*=[^\]]*\]`)
+
+// LintFinding is an issue found in the configuration file.
+type LintFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	// the configuration element the finding refers to, e.g: outputs/out1
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Lint statically analyzes the configuration file and returns the findings,
+// sorted by path.
+// It checks for unused outputs and processors, references to unknown outputs,
+// processors or subscriptions, duplicate target addresses,
+// invalid or suspicious regular expressions in processors
+// and template syntax errors.
+func (c *Config) Lint() []*LintFinding {
+	l := &linter{
+		outputs:       lintMaps(c.FileConfig.GetStringMap("outputs")),
+		processors:    lintMaps(c.FileConfig.GetStringMap("processors")),
+		subscriptions: lintMaps(c.FileConfig.GetStringMap("subscriptions")),
+		targets:       lintMaps(c.FileConfig.GetStringMap("targets")),
+		inputs:        lintMaps(c.FileConfig.GetStringMap("inputs")),
+		actions:       lintMaps(c.FileConfig.GetStringMap("actions")),
+	}
+	l.checkOutputs(c.FileConfig.IsSet("loader"))
+	l.checkProcessors(lintStrings(c.FileConfig.Get("get-processor")))
+	l.checkSubscriptions()
+	l.checkAddresses(c.FileConfig.GetString("port"))
+	l.checkTemplates(map[string]string{
+		"loader/template":         c.FileConfig.GetString("loader/template"),
+		"target-rewrite/template": c.FileConfig.GetString("target-rewrite/template"),
+	})
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Path < l.findings[j].Path
+	})
+	return l.findings
+}
+
+type linter struct {
+	outputs       map[string]map[string]any
+	processors    map[string]map[string]any
+	subscriptions map[string]map[string]any
+	targets       map[string]map[string]any
+	inputs        map[string]map[string]any
+	actions       map[string]map[string]any
+
+	findings []*LintFinding
+}
+
+func (l *linter) add(severity, check, path, format string, args ...any) {
+	l.findings = append(l.findings, &LintFinding{
+		Severity: severity,
+		Check:    check,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkOutputs reports references to unknown outputs and the unused outputs.
+// Subscriptions, targets and inputs that do not list any output
+// send their messages to all the outputs.
+func (l *linter) checkOutputs(dynamicTargets bool) {
+	used := make(map[string]struct{})
+	subsUseAll := len(l.subscriptions) == 0
+	targetsUseAll := len(l.targets) == 0 || dynamicTargets
+	inputsUseAll := false
+	refs := func(kind string, cfgs map[string]map[string]any, useAll *bool) {
+		for _, name := range sortedKeys(cfgs) {
+			outs := lintStrings(cfgs[name]["outputs"])
+			if len(outs) == 0 {
+				*useAll = true
+			}
+			for _, o := range outs {
+				used[o] = struct{}{}
+				if _, ok := l.outputs[o]; !ok {
+					l.add(LintSeverityError, lintCheckMissingOutput, kind+"/"+name,
+						"references unknown output %q", o)
+				}
+			}
+		}
+	}
+	refs("subscriptions", l.subscriptions, &subsUseAll)
+	refs("targets", l.targets, &targetsUseAll)
+	refs("inputs", l.inputs, &inputsUseAll)
+	if (subsUseAll && targetsUseAll) || inputsUseAll {
+		return
+	}
+	for _, name := range sortedKeys(l.outputs) {
+		if _, ok := used[name]; !ok {
+			l.add(LintSeverityWarning, lintCheckUnusedOutput, "outputs/"+name,
+				"output is not referenced by any subscription, target or input")
+		}
+	}
+}
+
+// checkProcessors reports references to unknown processors, unused processors
+// and invalid or suspicious regular expressions.
+func (l *linter) checkProcessors(getProcessors []string) {
+	used := make(map[string]struct{})
+	ref := func(path string, names []string) {
+		for _, p := range names {
+			used[p] = struct{}{}
+			if _, ok := l.processors[p]; !ok {
+				l.add(LintSeverityError, lintCheckMissingProcessor, path,
+					"references unknown processor %q", p)
+			}
+		}
+	}
+	for _, name := range sortedKeys(l.outputs) {
+		ref("outputs/"+name, lintStrings(l.outputs[name]["event-processors"]))
+	}
+	for _, name := range sortedKeys(l.inputs) {
+		ref("inputs/"+name, lintStrings(l.inputs[name]["event-processors"]))
+	}
+	for _, name := range sortedKeys(l.subscriptions) {
+		oo, _ := l.subscriptions[name]["output-options"].(map[string]any)
+		ref("subscriptions/"+name, lintStrings(oo["event-processors"]))
+	}
+	ref("get-processor", getProcessors)
+
+	for _, name := range sortedKeys(l.processors) {
+		path := "processors/" + name
+		if _, ok := used[name]; !ok {
+			l.add(LintSeverityWarning, lintCheckUnusedProcessor, path,
+				"processor is not referenced by any output, input or subscription")
+		}
+		for pType, pCfg := range l.processors[name] {
+			cfg, ok := pCfg.(map[string]any)
+			if !ok || pType == "event-group-by" {
+				// event-group-by tags are tag names, not regular expressions.
+				continue
+			}
+			for _, attr := range lintRegexAttributes {
+				for _, re := range lintStrings(cfg[attr]) {
+					l.checkRegex(path+"/"+pType+"/"+attr, re)
+				}
+			}
+		}
+	}
+}
+
+func (l *linter) checkRegex(path, re string) {
+	if _, err := regexp.Compile(re); err != nil {
+		l.add(LintSeverityError, lintCheckRegex, path, "invalid regular expression %q: %v", re, err)
+		return
+	}
+	switch {
+	case re == "":
+		l.add(LintSeverityWarning, lintCheckRegex, path, "empty regular expression matches everything")
+	case strings.TrimSpace(re) != re:
+		l.add(LintSeverityWarning, lintCheckRegex, path, "regular expression %q has leading or trailing spaces", re)
+	}
+	if lintUnescapedXPathKeys.MatchString(re) {
+		l.add(LintSeverityWarning, lintCheckRegex, path,
+			"regular expression %q contains a character class that looks like xpath keys, escape the brackets: \\[name=value\\]", re)
+	}
+	if strings.Contains(re, "/*") {
+		l.add(LintSeverityWarning, lintCheckRegex, path,
+			"regular expression %q contains '/*' which matches zero or more '/', did you mean '/.*' ?", re)
+	}
+}
+
+// checkSubscriptions reports targets referencing unknown subscriptions.
+func (l *linter) checkSubscriptions() {
+	for _, name := range sortedKeys(l.targets) {
+		for _, s := range lintStrings(l.targets[name]["subscriptions"]) {
+			if _, ok := l.subscriptions[s]; !ok {
+				l.add(LintSeverityError, lintCheckMissingSubscription, "targets/"+name,
+					"references unknown subscription %q", s)
+			}
+		}
+	}
+}
+
+// checkAddresses reports targets sharing the same address.
+func (l *linter) checkAddresses(defaultPort string) {
+	if defaultPort == "" {
+		defaultPort = "57400"
+	}
+	seen := make(map[string]string)
+	for _, name := range sortedKeys(l.targets) {
+		addrs := lintStrings(l.targets[name]["address"])
+		if len(addrs) == 0 {
+			addrs = []string{name}
+		}
+		for _, addr := range addrs {
+			for _, a := range strings.Split(addr, ",") {
+				if pa, err := utils.ParseAddress(a, defaultPort); err == nil {
+					a = pa.String()
+				}
+				if other, ok := seen[a]; ok {
+					l.add(LintSeverityWarning, lintCheckDuplicateAddress, "targets/"+name,
+						"address %q is also used by target %q", a, other)
+					continue
+				}
+				seen[a] = name
+			}
+		}
+	}
+}
+
+// checkTemplates reports the templates that fail to parse.
+// The templates are read from the outputs and actions configurations,
+// as well as from the extra ones, indexed by path.
+func (l *linter) checkTemplates(extra map[string]string) {
+	tpls := make(map[string]string)
+	for k, v := range extra {
+		tpls[k] = v
+	}
+	for _, name := range sortedKeys(l.outputs) {
+		for _, attr := range []string{"target-template", "msg-template"} {
+			tpls["outputs/"+name+"/"+attr], _ = l.outputs[name][attr].(string)
+		}
+	}
+	for _, name := range sortedKeys(l.actions) {
+		for _, attr := range []string{"template", "url", "body", "target", "prefix"} {
+			tpls["actions/"+name+"/"+attr], _ = l.actions[name][attr].(string)
+		}
+		for _, attr := range []string{"paths", "values"} {
+			for i, v := range lintStrings(l.actions[name][attr]) {
+				tpls[fmt.Sprintf("actions/%s/%s/%d", name, attr, i)] = v
+			}
+		}
+	}
+	for path, text := range tpls {
+		if text == "" {
+			continue
+		}
+		if _, err := gtemplate.CreateTemplate(path, text); err != nil {
+			l.add(LintSeverityError, lintCheckTemplate, path, "template parsing failed: %v", err)
+		}
+	}
+}
+
+// lintMaps converts a map read from the configuration file
+// into a map of configuration maps, nil or malformed values become empty maps.
+func lintMaps(m map[string]any) map[string]map[string]any {
+	r := make(map[string]map[string]any, len(m))
+	for k, v := range m {
+		vm, _ := v.(map[string]any)
+		if vm == nil {
+			vm = make(map[string]any)
+		}
+		r[k] = vm
+	}
+	return r
+}
+
+// lintStrings returns v as a list of strings,
+// v can be a string, a list of strings or a list of any.
+func lintStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		r := make([]string, 0, len(v))
+		for _, e := range v {
+			r = append(r, fmt.Sprint(e))
+		}
+		return r
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "clean",
+			in: `
+targets:
+  r1:
+    address: 10.0.0.1
+subscriptions:
+  sub1:
+    paths:
+      - /interfaces
+outputs:
+  out1:
+    type: file
+    event-processors:
+      - proc1
+processors:
+  proc1:
+    event-delete:
+      value-names:
+        - "^/interface\\[name=mgmt0\\]"
+`,
+			want: []string{},
+		},
+		{
+			name: "references",
+			in: `
+targets:
+  r1:
+    subscriptions:
+      - sub2
+subscriptions:
+  sub1:
+    paths:
+      - /interfaces
+    outputs:
+      - out1
+      - out3
+    output-options:
+      event-processors:
+        - proc2
+outputs:
+  out1:
+    type: file
+  out2:
+    type: file
+processors:
+  proc1:
+    event-drop:
+      condition: "true"
+`,
+			want: []string{
+				"warning unused-output outputs/out2",
+				"warning unused-processor processors/proc1",
+				"error missing-output subscriptions/sub1",
+				"error missing-processor subscriptions/sub1",
+				"error missing-subscription targets/r1",
+			},
+		},
+		{
+			name: "duplicate_addresses",
+			in: `
+port: 57400
+targets:
+  r1:
+    address: 10.0.0.1
+  r2:
+    address: 10.0.0.1:57400
+  10.0.0.2:
+  r3:
+    address: 10.0.0.3,10.0.0.2
+`,
+			want: []string{
+				"warning duplicate-address targets/r2",
+				"warning duplicate-address targets/r3",
+			},
+		},
+		{
+			name: "regexes",
+			in: `
+outputs:
+  out1:
+    type: file
+    event-processors:
+      - proc1
+processors:
+  proc1:
+    event-add-tag:
+      value-names:
+        - "*bad"
+        - "/interface[name=mgmt0]/state"
+        - "/interfaces/*/counters"
+        - ""
+      tag-names:
+        - " source"
+      add:
+        site: dc1
+`,
+			want: []string{
+				"error regex processors/proc1/event-add-tag/value-names",
+				"warning regex processors/proc1/event-add-tag/value-names",
+				"warning regex processors/proc1/event-add-tag/value-names",
+				"warning regex processors/proc1/event-add-tag/value-names",
+				"warning regex processors/proc1/event-add-tag/tag-names",
+			},
+		},
+		{
+			name: "templates",
+			in: `
+outputs:
+  out1:
+    type: file
+    target-template: '{{ index . "source" }}'
+    msg-template: '{{ .Name '
+actions:
+  act1:
+    type: http
+    url: http://localhost/{{ .Input | unknownFunc }}
+target-rewrite:
+  template: '{{ if }}'
+`,
+			want: []string{
+				"error template actions/act1/url",
+				"error template outputs/out1/msg-template",
+				"error template target-rewrite/template",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.SetLogger()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed reading config: %v", err)
+			}
+			got := make([]string, 0)
+			for _, f := range cfg.Lint() {
+				got = append(got, fmt.Sprintf("%s %s %s", f.Severity, f.Check, f.Path))
+			}
+			gotSet := strings.Join(sortedStrings(got), "\n")
+			wantSet := strings.Join(sortedStrings(tt.want), "\n")
+			if gotSet != wantSet {
+				t.Errorf("got findings:\n%s\nwant:\n%s", gotSet, wantSet)
+			}
+		})
+	}
+}
+
+func sortedStrings(s []string) []string {
+	r := append([]string(nil), s...)
+	sort.Strings(r)
+	return r
+}
@@ -105,6 +105,23 @@ type outputSuggestion struct {
 	Types []string
 }
 
+// SetOutputConfigDefaults validates the output config cfg
+// and sets its format to the global one if it is missing.
+func (c *Config) SetOutputConfigDefaults(cfg map[string]interface{}) error {
+	outType, _ := cfg["type"].(string)
+	if outType == "" {
+		return fmt.Errorf("%w: missing output type", ErrConfig)
+	}
+	if _, ok := outputs.Outputs[outType]; !ok {
+		return fmt.Errorf("%w: unknown output type: %q", ErrConfig, outType)
+	}
+	if format, ok := cfg["format"]; !ok || format == "" {
+		cfg["format"] = c.FileConfig.GetString("format")
+	}
+	expandMapEnv(cfg, "msg-template", "target-template")
+	return nil
+}
+
 func (c *Config) GetOutputsSuggestions() []outputSuggestion {
 	outDef := c.FileConfig.GetStringMap("outputs")
 	suggestions := make([]outputSuggestion, 0, len(outDef))
@@ -6,6 +6,8 @@ import (
 
 type PluginsConfig struct {
 	Path         string        `mapstructure:"path,omitempty" json:"path,omitempty"`
+	OutputsPath  string        `mapstructure:"outputs-path,omitempty" json:"outputs-path,omitempty"`
+	InputsPath   string        `mapstructure:"inputs-path,omitempty" json:"inputs-path,omitempty"`
 	Glob         string        `mapstructure:"glob,omitempty" json:"glob,omitempty"`
 	StartTimeout time.Duration `mapstructure:"start-timeout,omitempty" json:"start-timeout,omitempty"`
 	Debug        bool          `mapstructure:"debug,omitempty" json:"debug,omitempty"`
@@ -20,6 +22,8 @@ func (c *Config) GetPluginsConfig() (*PluginsConfig, error) {
 	if pc.Path == "" {
 		pc.Path = c.FileConfig.GetString("plugins/path")
 	}
+	pc.OutputsPath = c.FileConfig.GetString("plugins/outputs-path")
+	pc.InputsPath = c.FileConfig.GetString("plugins/inputs-path")
 	pc.Glob = c.FileConfig.GetString("plugins/glob")
 	if pc.Glob == "" {
 		pc.Glob = "*"
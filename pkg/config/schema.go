@@ -0,0 +1,199 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/inputs"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	SchemaKindOutputs    = "outputs"
+	SchemaKindInputs     = "inputs"
+	SchemaKindProcessors = "processors"
+)
+
+// SchemaKinds lists the plugin kinds described by PluginsSchema.
+var SchemaKinds = []string{SchemaKindOutputs, SchemaKindInputs, SchemaKindProcessors}
+
+// PluginSchema describes the configuration options of a registered plugin type.
+type PluginSchema struct {
+	Type    string          `json:"type,omitempty" yaml:"type,omitempty"`
+	Options []*ConfigOption `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ConfigOption describes a single configuration option, built from
+// the `mapstructure` and `default` tags of the plugin config struct field.
+type ConfigOption struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// one of string, bool, int, uint, float, duration, object, list, map or any.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// type of the list items or the map values.
+	Elem    string `json:"elem,omitempty" yaml:"elem,omitempty"`
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// options of an object, or of the list items and map values if they are objects.
+	Options []*ConfigOption `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// PluginsSchema returns the configuration schema of the registered
+// outputs, inputs and processors types, indexed by kind and sorted by type.
+func PluginsSchema() map[string][]*PluginSchema {
+	schema := make(map[string][]*PluginSchema, len(SchemaKinds))
+	for typ, in := range outputs.Outputs {
+		schema[SchemaKindOutputs] = append(schema[SchemaKindOutputs], pluginSchema(typ, in()))
+	}
+	for typ, in := range inputs.Inputs {
+		schema[SchemaKindInputs] = append(schema[SchemaKindInputs], pluginSchema(typ, in()))
+	}
+	for typ, in := range formatters.EventProcessors {
+		schema[SchemaKindProcessors] = append(schema[SchemaKindProcessors], pluginSchema(typ, in()))
+	}
+	for _, ps := range schema {
+		sort.Slice(ps, func(i, j int) bool {
+			return ps[i].Type < ps[j].Type
+		})
+	}
+	return schema
+}
+
+// pluginSchema builds the schema of the plugin instance p.
+// The options are read from the plugin config struct, held in a `cfg`, `Cfg` or `config` field,
+// or from the plugin struct itself if it is decoded directly from the config, like most processors.
+func pluginSchema(typ string, p any) *PluginSchema {
+	ps := &PluginSchema{Type: typ}
+	t := reflect.TypeOf(p)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return ps
+	}
+	for _, name := range []string{"cfg", "Cfg", "config"} {
+		f, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			t = ft
+			break
+		}
+	}
+	ps.Options = structOptions(t, map[reflect.Type]bool{})
+	return ps
+}
+
+// structOptions returns the options of the struct type t fields with a `mapstructure` tag.
+// seen guards against recursive types.
+func structOptions(t reflect.Type, seen map[reflect.Type]bool) []*ConfigOption {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	opts := make([]*ConfigOption, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("mapstructure")
+		if !ok {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(flags, "squash") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				opts = append(opts, structOptions(ft, seen)...)
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		opt := &ConfigOption{Name: name, Default: f.Tag.Get("default")}
+		opt.Type, opt.Elem, opt.Options = typeOption(f.Type, seen)
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// typeOption returns the schema type of t, the type of its elements
+// if it is a list or a map, and its options if it is, or holds, an object.
+func typeOption(t reflect.Type, seen map[reflect.Type]bool) (string, string, []*ConfigOption) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return "duration", "", nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", "", nil
+	case reflect.Bool:
+		return "bool", "", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int", "", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint", "", nil
+	case reflect.Float32, reflect.Float64:
+		return "float", "", nil
+	case reflect.Struct:
+		return "object", "", structOptions(t, seen)
+	case reflect.Slice, reflect.Array:
+		elem, _, opts := typeOption(t.Elem(), seen)
+		return "list", elem, opts
+	case reflect.Map:
+		elem, _, opts := typeOption(t.Elem(), seen)
+		return "map", elem, opts
+	}
+	return "any", "", nil
+}
+
+// SelectPluginsSchema returns the schema of all the plugins if kind is empty,
+// of the plugins of the given kind if typ is empty, or of a single plugin type.
+func SelectPluginsSchema(kind, typ string) (any, error) {
+	schema := PluginsSchema()
+	if kind == "" {
+		if typ != "" {
+			return nil, fmt.Errorf("a plugin kind is required to select type %q", typ)
+		}
+		return schema, nil
+	}
+	ps, ok := schema[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin kind %q, must be one of %v", kind, SchemaKinds)
+	}
+	if typ == "" {
+		return ps, nil
+	}
+	for _, p := range ps {
+		if p.Type == typ {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown %s type %q", strings.TrimSuffix(kind, "s"), typ)
+}
@@ -0,0 +1,118 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testSchemaTLS struct {
+	CaFile string `mapstructure:"ca-file,omitempty"`
+}
+
+type testSchemaCommon struct {
+	Debug bool `mapstructure:"debug,omitempty"`
+}
+
+type testSchemaNode struct {
+	Name     string            `mapstructure:"name,omitempty"`
+	Children []*testSchemaNode `mapstructure:"children,omitempty"`
+}
+
+type testSchemaConfig struct {
+	Address          string                    `mapstructure:"address,omitempty" default:"localhost:9092"`
+	Timeout          time.Duration             `mapstructure:"timeout,omitempty" default:"5s"`
+	NumWorkers       int                       `mapstructure:"num-workers,omitempty" default:"1"`
+	BufferSize       uint                      `mapstructure:"buffer-size,omitempty"`
+	Ratio            float64                   `mapstructure:"ratio,omitempty"`
+	TLS              *testSchemaTLS            `mapstructure:"tls,omitempty"`
+	EventProcessors  []string                  `mapstructure:"event-processors,omitempty"`
+	Headers          map[string]string         `mapstructure:"headers,omitempty"`
+	Tree             *testSchemaNode           `mapstructure:"tree,omitempty"`
+	Extra            map[string]interface{}    `mapstructure:"extra,omitempty"`
+	Outputs          map[string]*testSchemaTLS `mapstructure:"outputs,omitempty"`
+	testSchemaCommon `mapstructure:",squash"`
+	Ignored          string `mapstructure:"-"`
+	internal         string
+}
+
+type testSchemaPlugin struct {
+	cfg    *testSchemaConfig
+	logger any
+}
+
+func Test_pluginSchema(t *testing.T) {
+	tlsOpts := []*ConfigOption{{Name: "ca-file", Type: "string"}}
+	want := &PluginSchema{
+		Type: "test",
+		Options: []*ConfigOption{
+			{Name: "address", Type: "string", Default: "localhost:9092"},
+			{Name: "timeout", Type: "duration", Default: "5s"},
+			{Name: "num-workers", Type: "int", Default: "1"},
+			{Name: "buffer-size", Type: "uint"},
+			{Name: "ratio", Type: "float"},
+			{Name: "tls", Type: "object", Options: tlsOpts},
+			{Name: "event-processors", Type: "list", Elem: "string"},
+			{Name: "headers", Type: "map", Elem: "string"},
+			{Name: "tree", Type: "object", Options: []*ConfigOption{
+				{Name: "name", Type: "string"},
+				{Name: "children", Type: "list", Elem: "object"},
+			}},
+			{Name: "extra", Type: "map", Elem: "any"},
+			{Name: "outputs", Type: "map", Elem: "object", Options: tlsOpts},
+			{Name: "debug", Type: "bool"},
+		},
+	}
+	got := pluginSchema("test", &testSchemaPlugin{})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected schema (-want +got):\n%s", diff)
+	}
+	// processors are decoded into the plugin struct itself
+	got = pluginSchema("test", &testSchemaCommon{})
+	if diff := cmp.Diff(&PluginSchema{Type: "test", Options: []*ConfigOption{{Name: "debug", Type: "bool"}}}, got); diff != "" {
+		t.Errorf("unexpected schema (-want +got):\n%s", diff)
+	}
+}
+
+func TestSelectPluginsSchema(t *testing.T) {
+	all, err := SelectPluginsSchema("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := all.(map[string][]*PluginSchema)
+	for _, kind := range SchemaKinds {
+		if len(schema[kind]) == 0 {
+			t.Errorf("no %s in the schema", kind)
+		}
+	}
+	s, err := SelectPluginsSchema(SchemaKindOutputs, "kafka")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, opt := range s.(*PluginSchema).Options {
+		if opt.Name == "topic" {
+			found = true
+			if opt.Type != "string" || opt.Default != "telemetry" {
+				t.Errorf("unexpected kafka topic option: %+v", opt)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("kafka output schema has no topic option")
+	}
+	for _, sel := range [][2]string{{"", "kafka"}, {"loaders", ""}, {SchemaKindInputs, "unknown"}} {
+		if _, err := SelectPluginsSchema(sel[0], sel[1]); err == nil {
+			t.Errorf("expected an error selecting kind=%q type=%q", sel[0], sel[1])
+		}
+	}
+}
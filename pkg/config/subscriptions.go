@@ -23,13 +23,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/path"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
 )
 
 const (
 	subscriptionDefaultMode       = "STREAM"
 	subscriptionDefaultStreamMode = "TARGET_DEFINED"
 	subscriptionDefaultEncoding   = "JSON"
+	defaultAbsenceAlarmEventName  = "subscription-absence"
+	defaultNeighborhoodMaxSubs    = 100
 )
 
 var ErrConfig = errors.New("config error")
@@ -60,6 +65,9 @@ func (c *Config) GetSubscriptions(cmd *cobra.Command) (map[string]*types.Subscri
 			return nil, fmt.Errorf("%w: subscriptions map: unexpected type %T", ErrConfig, s)
 		}
 	}
+	if err := validateNeighborhoodSubscriptions(c.Subscriptions); err != nil {
+		return nil, err
+	}
 
 	// named subscription
 	if len(c.LocalFlags.SubscribeName) == 0 {
@@ -416,6 +424,65 @@ func validateAndSetDefaults(sc *types.SubscriptionConfig) error {
 		}
 	}
 
+	// validate output options
+	if sc.OutputOptions != nil {
+		if err := validateOutputFormat(sc.OutputOptions.Format); err != nil {
+			return fmt.Errorf("%w: subscription %s: %v", ErrConfig, sc.Name, err)
+		}
+	}
+
+	// validate absence alarm
+	if sc.AbsenceAlarm != nil {
+		if strings.ToUpper(sc.Mode) != "STREAM" {
+			return fmt.Errorf("%w: subscription %s: absence-alarm is only supported with mode STREAM", ErrConfig, sc.Name)
+		}
+		if sc.AbsenceAlarm.Interval <= 0 {
+			return fmt.Errorf("%w: subscription %s: absence-alarm interval must be greater than zero", ErrConfig, sc.Name)
+		}
+		if sc.AbsenceAlarm.EventName == "" {
+			sc.AbsenceAlarm.EventName = defaultAbsenceAlarmEventName
+		}
+	}
+
+	// validate priority
+	switch strings.ToLower(sc.Priority) {
+	case "":
+	case types.SubscriptionPriorityHigh, types.SubscriptionPriorityNormal, types.SubscriptionPriorityLow:
+		sc.Priority = strings.ToLower(sc.Priority)
+	default:
+		return fmt.Errorf("%w: subscription %s: unknown priority %q", ErrConfig, sc.Name, sc.Priority)
+	}
+
+	// validate poll triggers
+	if sc.PollInterval != 0 || len(sc.PollTriggers) > 0 {
+		if strings.ToUpper(sc.Mode) != "POLL" {
+			return fmt.Errorf("%w: subscription %s: poll-interval and poll-triggers are only supported with mode POLL", ErrConfig, sc.Name)
+		}
+		if sc.PollInterval < 0 {
+			return fmt.Errorf("%w: subscription %s: poll-interval cannot be negative", ErrConfig, sc.Name)
+		}
+		for i, trigger := range sc.PollTriggers {
+			switch strings.ToLower(trigger) {
+			case types.PollTriggerAPI, types.PollTriggerSignal:
+				sc.PollTriggers[i] = strings.ToLower(trigger)
+			default:
+				return fmt.Errorf("%w: subscription %s: unknown poll trigger %q", ErrConfig, sc.Name, trigger)
+			}
+		}
+	}
+
+	// validate neighborhood rules
+	if len(sc.Neighborhood) > 0 {
+		if strings.ToUpper(sc.Mode) != "STREAM" {
+			return fmt.Errorf("%w: subscription %s: neighborhood is only supported with mode STREAM", ErrConfig, sc.Name)
+		}
+		for i, r := range sc.Neighborhood {
+			if err := validateNeighborhoodRule(r); err != nil {
+				return fmt.Errorf("%w: subscription %s: neighborhood rule %d: %v", ErrConfig, sc.Name, i, err)
+			}
+		}
+	}
+
 	// validate subscription stream mode
 	if strings.ToUpper(sc.Mode) == "STREAM" {
 		if len(sc.StreamSubscriptions) == 0 {
@@ -463,6 +530,9 @@ func validateAndSetDefaults(sc *types.SubscriptionConfig) error {
 			if scs.Qos != nil {
 				return fmt.Errorf("%w: subscription %s/%d: 'qos' attribute cannot be set", ErrConfig, sc.Name, i)
 			}
+			if scs.OutputOptions != nil {
+				return fmt.Errorf("%w: subscription %s/%d: 'output-options' attribute cannot be set", ErrConfig, sc.Name, i)
+			}
 
 			switch strings.ReplaceAll(strings.ToUpper(scs.StreamMode), "-", "_") {
 			case "":
@@ -478,6 +548,17 @@ func validateAndSetDefaults(sc *types.SubscriptionConfig) error {
 	return nil
 }
 
+// validateOutputFormat checks that format, when set,
+// is one of the formats supported by the outputs.
+func validateOutputFormat(format string) error {
+	switch format {
+	case "", "json", "protojson", "prototext", "event", "proto", "flat",
+		formatters.FormatInflux, formatters.FormatGraphite:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
 func validateSubscriptionsConfig(subs map[string]*types.SubscriptionConfig) error {
 	var hasPoll bool
 	var hasOnce bool
@@ -498,6 +579,52 @@ func validateSubscriptionsConfig(subs map[string]*types.SubscriptionConfig) erro
 	return nil
 }
 
+// validateNeighborhoodSubscriptions checks that the subscriptions
+// referenced by the neighborhood rules exist and have mode STREAM.
+func validateNeighborhoodSubscriptions(subs map[string]*types.SubscriptionConfig) error {
+	for _, sc := range subs {
+		for i, r := range sc.Neighborhood {
+			if r.Subscription == "" {
+				continue
+			}
+			tsc, ok := subs[r.Subscription]
+			if !ok {
+				return fmt.Errorf("%w: subscription %s: neighborhood rule %d: unknown subscription %q", ErrConfig, sc.Name, i, r.Subscription)
+			}
+			switch strings.ToUpper(tsc.Mode) {
+			case "", "STREAM":
+			default:
+				return fmt.Errorf("%w: subscription %s: neighborhood rule %d: subscription %q mode is not STREAM", ErrConfig, sc.Name, i, r.Subscription)
+			}
+		}
+	}
+	return nil
+}
+
+func validateNeighborhoodRule(r *types.NeighborhoodRule) error {
+	if r.Path == "" {
+		return errors.New("missing path")
+	}
+	if _, err := path.ParsePath(r.Path); err != nil {
+		return fmt.Errorf("invalid path %q: %v", r.Path, err)
+	}
+	if len(r.Subscribe) == 0 {
+		return errors.New("missing subscribe paths")
+	}
+	for _, p := range r.Subscribe {
+		if _, err := gtemplate.CreateTemplate("neighborhood", p); err != nil {
+			return fmt.Errorf("invalid subscribe path %q: %v", p, err)
+		}
+	}
+	switch {
+	case r.MaxSubscriptions < 0:
+		return errors.New("max-subscriptions cannot be negative")
+	case r.MaxSubscriptions == 0:
+		r.MaxSubscriptions = defaultNeighborhoodMaxSubs
+	}
+	return nil
+}
+
 func expandSubscriptionEnv(sc *types.SubscriptionConfig) {
 	sc.Name = os.ExpandEnv(sc.Name)
 	for i := range sc.Models {
@@ -514,3 +641,12 @@ func expandSubscriptionEnv(sc *types.SubscriptionConfig) {
 		sc.Encoding = pointer.ToString(os.ExpandEnv(*sc.Encoding))
 	}
 }
+
+// SetSubscriptionConfigDefaults validates the subscription config sc
+// and sets its missing fields to their default values.
+func (c *Config) SetSubscriptionConfigDefaults(sc *types.SubscriptionConfig) error {
+	if sc.Name == "" {
+		return fmt.Errorf("%w: missing subscription name", ErrConfig)
+	}
+	return validateAndSetDefaults(sc)
+}
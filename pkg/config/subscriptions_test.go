@@ -233,6 +233,30 @@ subscriptions:
 		},
 		outErr: nil,
 	},
+	"output_options": {
+		in: []byte(`
+subscriptions:
+  sub1:
+    paths:
+      - /valid/path
+    output-options:
+      format: proto
+      event-processors:
+        - proc1
+        - proc2
+`),
+		out: map[string]*types.SubscriptionConfig{
+			"sub1": {
+				Name:  "sub1",
+				Paths: []string{"/valid/path"},
+				OutputOptions: &types.OutputOptions{
+					Format:          "proto",
+					EventProcessors: []string{"proc1", "proc2"},
+				},
+			},
+		},
+		outErr: nil,
+	},
 }
 
 func TestGetSubscriptions(t *testing.T) {
@@ -965,3 +989,79 @@ func TestConfig_CreateSubscribeRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNeighborhood(t *testing.T) {
+	tests := map[string]struct {
+		sc      *types.SubscriptionConfig
+		wantErr bool
+	}{
+		"valid": {
+			sc: &types.SubscriptionConfig{
+				Paths: []string{"/network-instances/network-instance/state/name"},
+				Neighborhood: []*types.NeighborhoodRule{{
+					Path:      "/network-instances/network-instance[name=*]",
+					Subscribe: []string{"/network-instances/network-instance[name={{ .name }}]/protocols"},
+				}},
+			},
+		},
+		"once": {
+			sc: &types.SubscriptionConfig{
+				Paths: []string{"/network-instances/network-instance/state/name"},
+				Mode:  "once",
+				Neighborhood: []*types.NeighborhoodRule{{
+					Path:      "/network-instances/network-instance[name=*]",
+					Subscribe: []string{"/network-instances/network-instance[name={{ .name }}]/protocols"},
+				}},
+			},
+			wantErr: true,
+		},
+		"missing_subscribe": {
+			sc: &types.SubscriptionConfig{
+				Paths: []string{"/network-instances/network-instance/state/name"},
+				Neighborhood: []*types.NeighborhoodRule{{
+					Path: "/network-instances/network-instance[name=*]",
+				}},
+			},
+			wantErr: true,
+		},
+		"invalid_template": {
+			sc: &types.SubscriptionConfig{
+				Paths: []string{"/network-instances/network-instance/state/name"},
+				Neighborhood: []*types.NeighborhoodRule{{
+					Path:      "/network-instances/network-instance[name=*]",
+					Subscribe: []string{"/network-instances/network-instance[name={{ .name }]/protocols"},
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAndSetDefaults(tt.sc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && tt.sc.Neighborhood[0].MaxSubscriptions != defaultNeighborhoodMaxSubs {
+				t.Errorf("expected the default max-subscriptions, got %d", tt.sc.Neighborhood[0].MaxSubscriptions)
+			}
+		})
+	}
+
+	subs := map[string]*types.SubscriptionConfig{
+		"ni": {
+			Neighborhood: []*types.NeighborhoodRule{{Subscription: "bgp"}},
+		},
+		"bgp": {Mode: "poll"},
+	}
+	if err := validateNeighborhoodSubscriptions(subs); err == nil {
+		t.Errorf("expected an error with a POLL subscription")
+	}
+	subs["bgp"].Mode = ""
+	if err := validateNeighborhoodSubscriptions(subs); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	delete(subs, "bgp")
+	if err := validateNeighborhoodSubscriptions(subs); err == nil {
+		t.Errorf("expected an error with an unknown subscription")
+	}
+}
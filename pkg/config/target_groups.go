@@ -0,0 +1,80 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// GetTargetGroups reads the target groups from the config file.
+// A target group is a named list of target names.
+func (c *Config) GetTargetGroups() (map[string][]string, error) {
+	groups := make(map[string][]string)
+	for name := range c.FileConfig.GetStringMap("target-groups") {
+		members := c.FileConfig.GetStringSlice(fmt.Sprintf("target-groups/%s", name))
+		if len(members) == 0 {
+			return nil, fmt.Errorf("%w: target group %q has no members", ErrConfig, name)
+		}
+		for i := range members {
+			members[i] = strings.TrimSpace(os.ExpandEnv(members[i]))
+		}
+		groups[name] = members
+	}
+	if c.Debug {
+		c.logger.Printf("target-groups: %v", groups)
+	}
+	return groups, nil
+}
+
+// selectTargetGroups returns the targets belonging to the groups set with --group.
+// If no group is set, all the targets are returned.
+func (c *Config) selectTargetGroups(targets map[string]*types.TargetConfig) (map[string]*types.TargetConfig, error) {
+	if len(c.Group) == 0 {
+		return targets, nil
+	}
+	groups, err := c.GetTargetGroups()
+	if err != nil {
+		return nil, err
+	}
+	// group members can reference a target by its key or its name
+	index := make(map[string]string, len(targets))
+	for k, tc := range targets {
+		index[strings.ToLower(k)] = k
+		index[strings.ToLower(tc.Name)] = k
+	}
+	selected := make(map[string]*types.TargetConfig)
+	for _, g := range c.Group {
+		members, ok := groups[strings.ToLower(g)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown target group %q, known groups: %s", ErrConfig, g, strings.Join(sortedKeys(groups), ", "))
+		}
+		for _, m := range members {
+			k, ok := index[strings.ToLower(m)]
+			if !ok {
+				return nil, fmt.Errorf("%w: target group %q: unknown target %q", ErrConfig, g, m)
+			}
+			selected[k] = targets[k]
+		}
+	}
+	return selected, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSelectTargetGroups(t *testing.T) {
+	cfgData := `
+port: 57400
+targets:
+  spine1:
+  spine2:
+  leaf1:
+    name: Leaf1
+  leaf2:
+target-groups:
+  spines:
+    - spine1
+    - spine2
+  leaves:
+    - Leaf1
+    - leaf2
+  broken:
+    - leaf3
+`
+	tests := []struct {
+		name   string
+		groups []string
+		want   []string
+		err    error
+	}{
+		{name: "no_group", want: []string{"leaf1", "leaf2", "spine1", "spine2"}},
+		{name: "single_group", groups: []string{"spines"}, want: []string{"spine1", "spine2"}},
+		{name: "member_by_name", groups: []string{"leaves"}, want: []string{"leaf1", "leaf2"}},
+		{name: "multiple_groups", groups: []string{"spines", "leaves"}, want: []string{"leaf1", "leaf2", "spine1", "spine2"}},
+		{name: "unknown_group", groups: []string{"borders"}, err: ErrConfig},
+		{name: "unknown_member", groups: []string{"broken"}, err: ErrConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.SetLogger()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(cfgData))
+			if err != nil {
+				t.Fatalf("failed reading config: %v", err)
+			}
+			cfg.Group = tt.groups
+			targets, err := cfg.GetTargets()
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(targets))
+			for n := range targets {
+				got = append(got, n)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got targets %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	TargetRewriteModeName     = "name"
+	TargetRewriteModeHostname = "hostname"
+	TargetRewriteModeTemplate = "template"
+
+	defaultTargetRewriteHostnamePath = "/system/state/hostname"
+	defaultTargetRewriteTimeout      = 10 * time.Second
+)
+
+type targetRewrite struct {
+	// one of `name`, `hostname` or `template`
+	Mode string `mapstructure:"mode,omitempty" json:"mode,omitempty"`
+	// Go template used to build the target value when mode is `template`
+	Template string `mapstructure:"template,omitempty" json:"template,omitempty"`
+	// path queried using a gNMI Get RPC to learn the target hostname
+	HostnamePath string `mapstructure:"hostname-path,omitempty" json:"hostname-path,omitempty"`
+	// timeout of the hostname gNMI Get RPC
+	Timeout time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c *Config) GetTargetRewrite() error {
+	if !c.FileConfig.IsSet("target-rewrite") {
+		return nil
+	}
+	c.TargetRewrite = new(targetRewrite)
+	c.TargetRewrite.Mode = os.ExpandEnv(c.FileConfig.GetString("target-rewrite/mode"))
+	c.TargetRewrite.Template = c.FileConfig.GetString("target-rewrite/template")
+	c.TargetRewrite.HostnamePath = os.ExpandEnv(c.FileConfig.GetString("target-rewrite/hostname-path"))
+	c.TargetRewrite.Timeout = c.FileConfig.GetDuration("target-rewrite/timeout")
+
+	switch c.TargetRewrite.Mode {
+	case TargetRewriteModeName, TargetRewriteModeHostname:
+	case TargetRewriteModeTemplate:
+		if c.TargetRewrite.Template == "" {
+			return fmt.Errorf("target-rewrite mode %q requires a template", c.TargetRewrite.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown target-rewrite mode %q", c.TargetRewrite.Mode)
+	}
+	c.setTargetRewriteDefaults()
+	return nil
+}
+
+func (c *Config) setTargetRewriteDefaults() {
+	if c.TargetRewrite.Mode == TargetRewriteModeHostname && c.TargetRewrite.HostnamePath == "" {
+		c.TargetRewrite.HostnamePath = defaultTargetRewriteHostnamePath
+	}
+	if c.TargetRewrite.Timeout <= 0 {
+		c.TargetRewrite.Timeout = defaultTargetRewriteTimeout
+	}
+}
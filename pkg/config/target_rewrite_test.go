@@ -0,0 +1,105 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTargetRewrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *targetRewrite
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "targets:\n  r1: {}\n",
+		},
+		{
+			name: "name",
+			in:   "target-rewrite:\n  mode: name\n",
+			want: &targetRewrite{Mode: TargetRewriteModeName, Timeout: defaultTargetRewriteTimeout},
+		},
+		{
+			name: "hostname_defaults",
+			in:   "target-rewrite:\n  mode: hostname\n",
+			want: &targetRewrite{
+				Mode:         TargetRewriteModeHostname,
+				HostnamePath: defaultTargetRewriteHostnamePath,
+				Timeout:      defaultTargetRewriteTimeout,
+			},
+		},
+		{
+			name: "hostname",
+			in:   "target-rewrite:\n  mode: hostname\n  hostname-path: /system/name/host-name\n  timeout: 2s\n",
+			want: &targetRewrite{
+				Mode:         TargetRewriteModeHostname,
+				HostnamePath: "/system/name/host-name",
+				Timeout:      2 * time.Second,
+			},
+		},
+		{
+			name: "template",
+			in:   "target-rewrite:\n  mode: template\n  template: '{{ index . \"source\" }}'\n",
+			want: &targetRewrite{
+				Mode:     TargetRewriteModeTemplate,
+				Template: `{{ index . "source" }}`,
+				Timeout:  defaultTargetRewriteTimeout,
+			},
+		},
+		{
+			name:    "template_missing",
+			in:      "target-rewrite:\n  mode: template\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown_mode",
+			in:      "target-rewrite:\n  mode: address\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing_mode",
+			in:      "target-rewrite:\n  timeout: 2s\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetTargetRewrite()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == nil {
+				if cfg.TargetRewrite != nil {
+					t.Errorf("expected no target-rewrite config, got %+v", cfg.TargetRewrite)
+				}
+				return
+			}
+			if cfg.TargetRewrite == nil || *cfg.TargetRewrite != *tt.want {
+				t.Errorf("got %+v, expected %+v", cfg.TargetRewrite, tt.want)
+			}
+		})
+	}
+}
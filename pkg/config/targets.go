@@ -12,18 +12,20 @@ import (
 	"errors"
 	"fmt"
 	"maps"
-	"net"
 	"os"
 	"sort"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/openconfig/gnmic/pkg/api/path"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
 )
 
 const (
 	defaultTargetBufferSize = 100
+	defaultDNSPort          = "53"
 )
 
 var ErrNoTargetsFound = errors.New("no targets found")
@@ -32,6 +34,9 @@ func (c *Config) GetTargets() (map[string]*types.TargetConfig, error) {
 	var err error
 	// case address is defined in .Address
 	if len(c.Address) > 0 {
+		if len(c.Group) > 0 {
+			return nil, fmt.Errorf("%w: flags --address and --group are mutually exclusive", ErrConfig)
+		}
 		for _, addr := range c.Address {
 			tc := &types.TargetConfig{
 				Name:    addr,
@@ -117,7 +122,10 @@ func (c *Config) GetTargets() (map[string]*types.TargetConfig, error) {
 		expandTargetEnv(tc)
 		newTargetsConfig[name] = tc
 	}
-	c.Targets = newTargetsConfig
+	c.Targets, err = c.selectTargetGroups(newTargetsConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	subNames := c.FileConfig.GetStringSlice("subscribe-name")
 	if len(subNames) == 0 {
@@ -143,16 +151,12 @@ func (c *Config) SetTargetConfigDefaults(tc *types.TargetConfig) error {
 		for _, addr := range addrList {
 			addr = strings.TrimSpace(addr)
 			if !c.UseTunnelServer {
-				_, _, err := net.SplitHostPort(addr)
+				pa, err := utils.ParseAddress(addr, defGrpcPort)
 				if err != nil {
-					if strings.Contains(err.Error(), "missing port in address") ||
-						strings.Contains(err.Error(), "too many colons in address") {
-						addr = net.JoinHostPort(addr, defGrpcPort)
-					} else {
-						c.logger.Printf("error parsing address '%s': %v", addr, err)
-						return fmt.Errorf("error parsing address '%s': %v", addr, err)
-					}
+					c.logger.Printf("error parsing address '%s': %v", addr, err)
+					return fmt.Errorf("error parsing address '%s': %v", addr, err)
 				}
+				addr = pa.String()
 			}
 			addrs = append(addrs, addr)
 		}
@@ -210,6 +214,9 @@ func (c *Config) SetTargetConfigDefaults(tc *types.TargetConfig) error {
 	if tc.LogTLSSecret == nil {
 		tc.LogTLSSecret = &c.LogTLSSecret
 	}
+	if tc.TLSSessionResumption == nil {
+		tc.TLSSessionResumption = &c.TLSSessionResumption
+	}
 	if tc.Gzip == nil {
 		tc.Gzip = &c.Gzip
 	}
@@ -220,6 +227,51 @@ func (c *Config) SetTargetConfigDefaults(tc *types.TargetConfig) error {
 		tc.Metadata = make(map[string]string)
 		maps.Copy(tc.Metadata, c.Metadata)
 	}
+	if tc.DNS != nil {
+		switch tc.DNS.Prefer {
+		case "", types.DNSPreferIPv4, types.DNSPreferIPv6:
+		default:
+			return fmt.Errorf("%w: target %s: unknown dns prefer value %q", ErrConfig, tc.Name, tc.DNS.Prefer)
+		}
+		for i, s := range tc.DNS.Servers {
+			pa, err := utils.ParseAddress(s, defaultDNSPort)
+			if err != nil {
+				return fmt.Errorf("%w: target %s: dns server %q: %v", ErrConfig, tc.Name, s, err)
+			}
+			tc.DNS.Servers[i] = pa.String()
+		}
+	}
+	for _, ref := range tc.Bundles {
+		if _, err := getBundle(ref); err != nil {
+			return fmt.Errorf("%w: target %s: %v", ErrConfig, tc.Name, err)
+		}
+	}
+	if err := tc.SocketOptions.Validate(); err != nil {
+		return fmt.Errorf("%w: target %s: socket-options: %v", ErrConfig, tc.Name, err)
+	}
+	if err := types.ValidateCompression(tc.Compression); err != nil {
+		return fmt.Errorf("%w: target %s: %v", ErrConfig, tc.Name, err)
+	}
+	if err := path.ValidateNamespace(tc.PathNamespace); err != nil {
+		return fmt.Errorf("%w: target %s: %v", ErrConfig, tc.Name, err)
+	}
+	if err := tc.Budget.Validate(); err != nil {
+		return fmt.Errorf("%w: target %s: budget: %v", ErrConfig, tc.Name, err)
+	}
+	if err := tc.Schedule.Validate(); err != nil {
+		return fmt.Errorf("%w: target %s: schedule: %v", ErrConfig, tc.Name, err)
+	}
+	if err := tc.ResponseValidation.Validate(); err != nil {
+		return fmt.Errorf("%w: target %s: response-validation: %v", ErrConfig, tc.Name, err)
+	}
+	for name, oo := range tc.SubscriptionsOutputOptions {
+		if oo == nil {
+			continue
+		}
+		if err := validateOutputFormat(oo.Format); err != nil {
+			return fmt.Errorf("%w: target %s: subscription %s: %v", ErrConfig, tc.Name, name, err)
+		}
+	}
 	return nil
 }
 
@@ -288,6 +340,9 @@ func expandTargetEnv(tc *types.TargetConfig) {
 	for i := range tc.Subscriptions {
 		tc.Subscriptions[i] = os.ExpandEnv(tc.Subscriptions[i])
 	}
+	for i := range tc.Bundles {
+		tc.Bundles[i] = os.ExpandEnv(tc.Bundles[i])
+	}
 	for i := range tc.Outputs {
 		tc.Outputs[i] = os.ExpandEnv(tc.Outputs[i])
 	}
@@ -303,6 +358,9 @@ func expandTargetEnv(tc *types.TargetConfig) {
 	for i := range tc.Tags {
 		tc.Tags[i] = os.ExpandEnv(tc.Tags[i])
 	}
+	for k, v := range tc.Labels {
+		tc.Labels[k] = os.ExpandEnv(v)
+	}
 }
 
 func (c *Config) GetDiffTargets() (*types.TargetConfig, map[string]*types.TargetConfig, error) {
@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
 
@@ -35,18 +36,19 @@ address: 10.1.1.1
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(false),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 			},
 		},
 		outErr: nil,
@@ -60,18 +62,19 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(false),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 			},
 		},
 		outErr: nil,
@@ -91,18 +94,19 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 				Metadata: map[string]string{
 					"override1": "val2",
 				},
@@ -125,36 +129,38 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(false),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 				Metadata: map[string]string{
 					"key1": "val1",
 					"key2": "val2",
 				},
 			},
 			"10.1.1.2:57400": {
-				Address:      "10.1.1.2:57400",
-				Name:         "10.1.1.2:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(false),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.2:57400",
+				Name:                 "10.1.1.2:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 				Metadata: map[string]string{
 					"key1": "val1",
 					"key2": "val2",
@@ -176,32 +182,34 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 			},
 			"10.1.1.2:57400": {
-				Address:      "10.1.1.2:57400",
-				Name:         "10.1.1.2:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.2:57400",
+				Name:                 "10.1.1.2:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 			},
 		},
 		outErr: nil,
@@ -220,32 +228,34 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(true),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(true),
+				BufferSize:           uint(100),
 			},
 			"10.1.1.2:57400": {
-				Address:      "10.1.1.2:57400",
-				Name:         "10.1.1.2:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.2:57400",
+				Name:                 "10.1.1.2:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 			},
 		},
 		outErr: nil,
@@ -268,18 +278,19 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"10.1.1.1:57400": {
-				Address:      "10.1.1.1:57400",
-				Name:         "10.1.1.1:57400",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(true),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400",
+				Name:                 "10.1.1.1:57400",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(true),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
 				Subscriptions: []string{
 					"sub1",
 				},
@@ -301,18 +312,89 @@ targets:
 `),
 		out: map[string]*types.TargetConfig{
 			"target1": {
-				Address:      "10.1.1.1:57400,10.1.1.2:57400",
-				Name:         "target1",
-				Password:     pointer.ToString("admin"),
-				Username:     pointer.ToString("admin"),
-				Token:        pointer.ToString(""),
-				TLSCert:      pointer.ToString(""),
-				TLSKey:       pointer.ToString(""),
-				LogTLSSecret: pointer.ToBool(false),
-				Insecure:     pointer.ToBool(false),
-				SkipVerify:   pointer.ToBool(false),
-				Gzip:         pointer.ToBool(false),
-				BufferSize:   uint(100),
+				Address:              "10.1.1.1:57400,10.1.1.2:57400",
+				Name:                 "target1",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
+			},
+		},
+		outErr: nil,
+	},
+	"target_with_ipv6_addresses": {
+		envs: []string{
+			"GNMI_PORT=6030",
+		},
+		in: []byte(`
+port: 57400
+targets:
+  target1:
+    username: admin
+    password: admin
+    address: fe80::1%mgmt,[2001:db8::1],[fe80::2%eth0]:50051,[2001:db8::2]:${GNMI_PORT}
+`),
+		out: map[string]*types.TargetConfig{
+			"target1": {
+				Address:              "[fe80::1%mgmt]:57400,[2001:db8::1]:57400,[fe80::2%eth0]:50051,[2001:db8::2]:6030",
+				Name:                 "target1",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
+			},
+		},
+		outErr: nil,
+	},
+	"target_with_dns_config": {
+		in: []byte(`
+port: 57400
+targets:
+  target1:
+    address: router1.lab.net
+    username: admin
+    password: admin
+    dns:
+      prefer: ipv6
+      servers:
+        - 192.0.2.53
+        - "[2001:db8::53]:5353"
+      ttl: 30s
+`),
+		out: map[string]*types.TargetConfig{
+			"target1": {
+				Address:              "router1.lab.net:57400",
+				Name:                 "target1",
+				Password:             pointer.ToString("admin"),
+				Username:             pointer.ToString("admin"),
+				Token:                pointer.ToString(""),
+				TLSCert:              pointer.ToString(""),
+				TLSKey:               pointer.ToString(""),
+				LogTLSSecret:         pointer.ToBool(false),
+				TLSSessionResumption: pointer.ToBool(false),
+				Insecure:             pointer.ToBool(false),
+				SkipVerify:           pointer.ToBool(false),
+				Gzip:                 pointer.ToBool(false),
+				BufferSize:           uint(100),
+				DNS: &types.DNSConfig{
+					Prefer:  "ipv6",
+					Servers: []string{"192.0.2.53:53", "[2001:db8::53]:5353"},
+					TTL:     30 * time.Second,
+				},
 			},
 		},
 		outErr: nil,
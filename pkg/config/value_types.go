@@ -0,0 +1,35 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// GetValueTypes reads the types forced on the values of the events
+// built from the targets notifications, matched by value name.
+func (c *Config) GetValueTypes() error {
+	if !c.FileConfig.IsSet("value-types") {
+		return nil
+	}
+	var vts formatters.ValueTypes
+	err := mapstructure.Decode(c.FileConfig.Get("value-types"), &vts)
+	if err != nil {
+		return fmt.Errorf("failed to decode value-types: %w", err)
+	}
+	if err = vts.Init(); err != nil {
+		return err
+	}
+	c.ValueTypes = vts
+	return nil
+}
@@ -0,0 +1,72 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetValueTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "not_set",
+			in:   "targets:\n  r1: {}\n",
+		},
+		{
+			name: "value_types",
+			in: `
+value-types:
+  - paths: ["/counters/"]
+    type: int
+  - paths: ["/enabled$", "/up$"]
+    type: bool
+`,
+			want: 2,
+		},
+		{
+			name:    "unknown_type",
+			in:      "value-types:\n  - paths: [\"/x\"]\n    type: decimal\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad_regex",
+			in:      "value-types:\n  - paths: [\"(\"]\n    type: int\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.FileConfig.SetConfigType("yaml")
+			err := cfg.FileConfig.ReadConfig(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+			err = cfg.GetValueTypes()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(cfg.ValueTypes) != tt.want {
+				t.Errorf("got %d value types, expected %d", len(cfg.ValueTypes), tt.want)
+			}
+		})
+	}
+}
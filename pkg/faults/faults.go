@@ -0,0 +1,123 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package faults injects artificial latency, drops and duplicates
+// in the gNMIc pipeline, it is meant to test the resilience of
+// the systems consuming the telemetry, not for production use.
+package faults
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config defines the faults applied to the messages crossing a pipeline boundary.
+type Config struct {
+	// fixed delay added before a message is passed on.
+	Delay time.Duration `mapstructure:"delay,omitempty" json:"delay,omitempty"`
+	// maximum random delay added on top of delay.
+	Jitter time.Duration `mapstructure:"jitter,omitempty" json:"jitter,omitempty"`
+	// probability, between 0 and 1, of a message being dropped.
+	DropProbability float64 `mapstructure:"drop-probability,omitempty" json:"drop-probability,omitempty"`
+	// probability, between 0 and 1, of a message being passed on twice.
+	DuplicateProbability float64 `mapstructure:"duplicate-probability,omitempty" json:"duplicate-probability,omitempty"`
+	// seed of the random generator, for reproducible runs.
+	// a zero seed uses a time based one.
+	Seed int64 `mapstructure:"seed,omitempty" json:"seed,omitempty"`
+}
+
+// Validate checks the config values.
+func (c *Config) Validate() error {
+	if c.Delay < 0 || c.Jitter < 0 {
+		return errors.New("fault-injection delay and jitter must be positive")
+	}
+	if c.DropProbability < 0 || c.DropProbability > 1 ||
+		c.DuplicateProbability < 0 || c.DuplicateProbability > 1 {
+		return errors.New("fault-injection probabilities must be between 0 and 1")
+	}
+	return nil
+}
+
+// Enabled reports whether c injects any fault.
+func (c *Config) Enabled() bool {
+	return c != nil && (c.Delay > 0 || c.Jitter > 0 ||
+		c.DropProbability > 0 || c.DuplicateProbability > 0)
+}
+
+// Injector applies the faults of a Config to the messages, one at a time.
+type Injector struct {
+	cfg *Config
+
+	m   *sync.Mutex
+	rnd *rand.Rand
+
+	dropped    atomic.Uint64
+	duplicated atomic.Uint64
+}
+
+// NewInjector returns an Injector applying the faults defined in cfg.
+func NewInjector(cfg *Config) (*Injector, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		cfg: cfg,
+		m:   new(sync.Mutex),
+		rnd: rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Inject waits for the configured delay then returns the number of times
+// the message must be passed on: 0 if it is dropped, 2 if it is duplicated, 1 otherwise.
+// It returns 0 if ctx is done while waiting.
+func (i *Injector) Inject(ctx context.Context) int {
+	i.m.Lock()
+	delay := i.cfg.Delay
+	if i.cfg.Jitter > 0 {
+		delay += time.Duration(i.rnd.Int63n(int64(i.cfg.Jitter) + 1))
+	}
+	drop := i.rnd.Float64() < i.cfg.DropProbability
+	dup := i.rnd.Float64() < i.cfg.DuplicateProbability
+	i.m.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0
+		case <-timer.C:
+		}
+	}
+	if drop {
+		i.dropped.Add(1)
+		return 0
+	}
+	if dup {
+		i.duplicated.Add(1)
+		return 2
+	}
+	return 1
+}
+
+// Stats returns the number of messages dropped and duplicated so far.
+func (i *Injector) Stats() (dropped, duplicated uint64) {
+	return i.dropped.Load(), i.duplicated.Load()
+}
@@ -0,0 +1,90 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInjector(t *testing.T) {
+	tests := map[string]struct {
+		cfg  *Config
+		want map[int]bool
+	}{
+		"no_faults": {
+			cfg:  &Config{},
+			want: map[int]bool{1: true},
+		},
+		"drop_all": {
+			cfg:  &Config{DropProbability: 1},
+			want: map[int]bool{0: true},
+		},
+		"duplicate_all": {
+			cfg:  &Config{DuplicateProbability: 1},
+			want: map[int]bool{2: true},
+		},
+		"mixed": {
+			cfg:  &Config{DropProbability: 0.3, DuplicateProbability: 0.3, Seed: 42},
+			want: map[int]bool{0: true, 1: true, 2: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			i, err := NewInjector(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen := make(map[int]bool)
+			for k := 0; k < 1000; k++ {
+				seen[i.Inject(context.Background())] = true
+			}
+			if len(seen) != len(tt.want) {
+				t.Fatalf("expected results %v, got %v", tt.want, seen)
+			}
+			for n := range seen {
+				if !tt.want[n] {
+					t.Fatalf("expected results %v, got %v", tt.want, seen)
+				}
+			}
+		})
+	}
+}
+
+func TestInjectorDelay(t *testing.T) {
+	i, err := NewInjector(&Config{Delay: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if n := i.Inject(context.Background()); n != 1 {
+		t.Fatalf("unexpected result %d", n)
+	}
+	if d := time.Since(now); d < 20*time.Millisecond {
+		t.Fatalf("message not delayed: %s", d)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n := i.Inject(ctx); n != 0 {
+		t.Fatalf("expected a canceled message to be dropped, got %d", n)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []*Config{
+		{Delay: -time.Second},
+		{DropProbability: 1.5},
+		{DuplicateProbability: -0.1},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -100,9 +101,8 @@ func readFTPFile(ctx context.Context, path string) ([]byte, error) {
 
 	// Parse Host and Port
 	host := parsedUrl.Host
-	_, _, err = net.SplitHostPort(host)
-	if err != nil {
-		host = fmt.Sprintf("%s:%d", host, defaultFTPPort)
+	if parsedUrl.Port() == "" {
+		host = net.JoinHostPort(parsedUrl.Hostname(), strconv.Itoa(defaultFTPPort))
 	}
 	// connect to server
 
@@ -139,9 +139,8 @@ func readSFTPFile(_ context.Context, path string, checkHostKey bool) ([]byte, er
 
 	// Parse Host and Port
 	host := parsedUrl.Host
-	_, _, err = net.SplitHostPort(host)
-	if err != nil {
-		host = fmt.Sprintf("%s:%d", host, defaultSFTPPort)
+	if parsedUrl.Port() == "" {
+		host = net.JoinHostPort(parsedUrl.Hostname(), strconv.Itoa(defaultSFTPPort))
 	}
 
 	var auths []ssh.AuthMethod
@@ -9,6 +9,7 @@
 package all
 
 import (
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_adaptive_sample"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_add_tag"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_allow"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_combine"
@@ -19,15 +20,23 @@ import (
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_drop"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_duration_convert"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_extract_tags"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_fault_injection"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_group_by"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_ip_lookup"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_jq"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_math"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_merge"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_override_ts"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_rate_limit"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_redact"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_sequence"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_sequence_gap"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_starlark"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_strings"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_time"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_to_tag"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_trigger"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_value_tag"
+	_ "github.com/openconfig/gnmic/pkg/formatters/event_value_tag_v2"
 	_ "github.com/openconfig/gnmic/pkg/formatters/event_write"
 )
@@ -0,0 +1,95 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// bypasses holds the names of the processors temporarily bypassed
+// and the time until which they are.
+var bypasses = struct {
+	sync.RWMutex
+	// number of entries in m, checked before taking the lock
+	// so that the processors are not slowed down when nothing is bypassed.
+	n atomic.Int32
+	m map[string]time.Time
+}{m: make(map[string]time.Time)}
+
+// BypassProcessor makes the processors called name pass the events through
+// unchanged for the duration d. It returns the time the bypass ends.
+func BypassProcessor(name string, d time.Duration) time.Time {
+	until := time.Now().Add(d)
+	bypasses.Lock()
+	defer bypasses.Unlock()
+	bypasses.m[name] = until
+	bypasses.n.Store(int32(len(bypasses.m)))
+	return until
+}
+
+// RestoreProcessor ends the bypass of the processors called name.
+// It returns false if they were not bypassed.
+func RestoreProcessor(name string) bool {
+	bypasses.Lock()
+	defer bypasses.Unlock()
+	until, ok := bypasses.m[name]
+	delete(bypasses.m, name)
+	bypasses.n.Store(int32(len(bypasses.m)))
+	return ok && time.Now().Before(until)
+}
+
+// BypassedProcessors returns the names of the processors currently bypassed
+// and the time their bypass ends.
+func BypassedProcessors() map[string]time.Time {
+	now := time.Now()
+	bypasses.Lock()
+	defer bypasses.Unlock()
+	rs := make(map[string]time.Time, len(bypasses.m))
+	for name, until := range bypasses.m {
+		if !now.Before(until) {
+			delete(bypasses.m, name)
+			continue
+		}
+		rs[name] = until
+	}
+	bypasses.n.Store(int32(len(bypasses.m)))
+	return rs
+}
+
+func isBypassed(name string) bool {
+	if bypasses.n.Load() == 0 {
+		return false
+	}
+	bypasses.RLock()
+	until, ok := bypasses.m[name]
+	bypasses.RUnlock()
+	return ok && time.Now().Before(until)
+}
+
+// bypassableProcessor wraps the processor called name
+// and skips it while it is bypassed.
+type bypassableProcessor struct {
+	EventProcessor
+	name string
+}
+
+// Bypassable returns the processor ep called name, wrapped so that it can
+// be bypassed at runtime using BypassProcessor.
+func Bypassable(name string, ep EventProcessor) EventProcessor {
+	return &bypassableProcessor{EventProcessor: ep, name: name}
+}
+
+func (p *bypassableProcessor) Apply(es ...*EventMsg) []*EventMsg {
+	if isBypassed(p.name) {
+		return es
+	}
+	return p.EventProcessor.Apply(es...)
+}
@@ -0,0 +1,59 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// dropAllProcessor drops all the events.
+type dropAllProcessor struct{}
+
+func (p *dropAllProcessor) Init(interface{}, ...Option) error             { return nil }
+func (p *dropAllProcessor) Apply(...*EventMsg) []*EventMsg                { return nil }
+func (p *dropAllProcessor) WithTargets(map[string]*types.TargetConfig)    {}
+func (p *dropAllProcessor) WithLogger(*log.Logger)                        {}
+func (p *dropAllProcessor) WithActions(map[string]map[string]interface{}) {}
+func (p *dropAllProcessor) WithProcessors(map[string]map[string]any)      {}
+
+func TestBypassProcessor(t *testing.T) {
+	ep := Bypassable("drop-all", &dropAllProcessor{})
+	evs := []*EventMsg{{Name: "ev1"}}
+	if rs := ep.Apply(evs...); len(rs) != 0 {
+		t.Fatalf("expected the events to be dropped, got %d", len(rs))
+	}
+	BypassProcessor("drop-all", time.Minute)
+	if _, ok := BypassedProcessors()["drop-all"]; !ok {
+		t.Errorf("expected the processor to be listed as bypassed")
+	}
+	if rs := ep.Apply(evs...); len(rs) != 1 {
+		t.Errorf("expected the events to pass through the bypassed processor, got %d", len(rs))
+	}
+	if !RestoreProcessor("drop-all") {
+		t.Errorf("expected the processor to be restored")
+	}
+	if rs := ep.Apply(evs...); len(rs) != 0 {
+		t.Errorf("expected the events to be dropped after restore, got %d", len(rs))
+	}
+	if RestoreProcessor("drop-all") {
+		t.Errorf("expected a second restore to report the processor as not bypassed")
+	}
+	// expired bypass
+	BypassProcessor("drop-all", -time.Second)
+	if rs := ep.Apply(evs...); len(rs) != 0 {
+		t.Errorf("expected an expired bypass to be ignored, got %d", len(rs))
+	}
+	if len(BypassedProcessors()) != 0 {
+		t.Errorf("expected expired bypasses to be removed")
+	}
+}
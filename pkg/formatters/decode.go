@@ -0,0 +1,85 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"encoding/json"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+// DecodeGetResponse decodes a GetResponse written using format,
+// one of json, proto, protojson or prototext.
+// An empty format means json.
+func DecodeGetResponse(b []byte, format string) (*gnmi.GetResponse, error) {
+	rsp := new(gnmi.GetResponse)
+	var err error
+	switch format {
+	case "proto":
+		err = proto.Unmarshal(b, rsp)
+	case "protojson":
+		err = protojson.Unmarshal(b, rsp)
+	case "prototext":
+		err = prototext.Unmarshal(b, rsp)
+	default: // json
+		rsp, err = jsonGetResponse(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// jsonGetResponse converts a GetResponse formatted using gNMIc's json format
+// back to a GetResponse with JSON values.
+func jsonGetResponse(b []byte) (*gnmi.GetResponse, error) {
+	notifs := make([]NotificationRspMsg, 0)
+	err := json.Unmarshal(b, &notifs)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &gnmi.GetResponse{
+		Notification: make([]*gnmi.Notification, 0, len(notifs)),
+	}
+	for _, n := range notifs {
+		prefix, err := path.ParsePath(n.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		gn := &gnmi.Notification{
+			Timestamp: n.Timestamp,
+			Prefix:    prefix,
+			Update:    make([]*gnmi.Update, 0, len(n.Updates)),
+		}
+		for _, upd := range n.Updates {
+			p, err := path.ParsePath(upd.Path)
+			if err != nil {
+				return nil, err
+			}
+			// the values map of an update holds a single value.
+			for _, v := range upd.Values {
+				jv, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				gn.Update = append(gn.Update, &gnmi.Update{
+					Path: p,
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: jv}},
+				})
+			}
+		}
+		rsp.Notification = append(rsp.Notification, gn)
+	}
+	return rsp, nil
+}
@@ -18,6 +18,16 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 )
 
+// metadata keys set by the collector when a subscription overrides
+// the outputs format and event processors, and the per target sequence
+// number of the messages received from targets with ordered delivery.
+// They are not added to the events tags.
+const (
+	MetaOutputFormat          = "output-format"
+	MetaOutputEventProcessors = "output-event-processors"
+	MetaSequenceNumber        = "sequence-number"
+)
+
 // EventMsg represents a gNMI update message,
 // The name is derived from the subscription in case the update was received in a subscribeResponse
 // the tags are derived from the keys in gNMI path as well as some metadata from the subscription.
@@ -34,6 +44,34 @@ func (e *EventMsg) String() string {
 	return string(b)
 }
 
+// CopyEventMsg returns a copy of the event ev
+// that can be modified without affecting ev.
+func CopyEventMsg(ev *EventMsg) *EventMsg {
+	if ev == nil {
+		return nil
+	}
+	nev := &EventMsg{
+		Name:      ev.Name,
+		Timestamp: ev.Timestamp,
+	}
+	if ev.Tags != nil {
+		nev.Tags = make(map[string]string, len(ev.Tags))
+		for k, v := range ev.Tags {
+			nev.Tags[k] = v
+		}
+	}
+	if ev.Values != nil {
+		nev.Values = make(map[string]interface{}, len(ev.Values))
+		for k, v := range ev.Values {
+			nev.Values[k] = v
+		}
+	}
+	if ev.Deletes != nil {
+		nev.Deletes = append(make([]string, 0, len(ev.Deletes)), ev.Deletes...)
+	}
+	return nev
+}
+
 // ResponseToEventMsgs //
 func ResponseToEventMsgs(name string, rsp *gnmi.SubscribeResponse, meta map[string]string, eps ...EventProcessor) ([]*EventMsg, error) {
 	if rsp == nil {
@@ -129,6 +167,7 @@ func updateToEvent(name, prefix string, ts int64, upd *gnmi.Update, tags map[str
 	if err != nil {
 		return nil, err
 	}
+	applyValueTypes(e.Values)
 	return e, nil
 }
 
@@ -397,7 +436,8 @@ func num64(n interface{}) interface{} {
 
 func addMetaTags(e *EventMsg, meta map[string]string) {
 	for k, v := range meta {
-		if k == "format" {
+		switch k {
+		case "format", MetaOutputFormat, MetaOutputEventProcessors, MetaSequenceNumber:
 			continue
 		}
 		if _, ok := e.Tags[k]; ok {
@@ -0,0 +1,237 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_adaptive_sample
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType      = "event-adaptive-sample"
+	loggingPrefix      = "[" + processorType + "] "
+	defaultStableCount = 3
+	defaultFactor      = 2
+	defaultHeartbeat   = 5 * time.Minute
+	defaultCacheSize   = 10000
+)
+
+// adaptiveSample widens the export interval of the values
+// that do not change between samples, and goes back to exporting
+// every sample as soon as they change.
+type adaptiveSample struct {
+	ValueNames  []string      `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
+	StableCount int           `mapstructure:"stable-count,omitempty" json:"stable-count,omitempty" default:"3"`
+	Factor      float64       `mapstructure:"factor,omitempty" json:"factor,omitempty" default:"2"`
+	Heartbeat   time.Duration `mapstructure:"heartbeat,omitempty" json:"heartbeat,omitempty" default:"5m"`
+	CacheSize   int           `mapstructure:"cache-size,omitempty" json:"cache-size,omitempty" default:"10000"`
+	Debug       bool          `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	valueNames []*regexp.Regexp
+	leaves     *lru.Cache[string, leafState]
+	logger     *log.Logger
+}
+
+// leafState is the sampling state of a single value.
+type leafState struct {
+	value any
+	// timestamps of the last received and the last exported samples.
+	lastSeen   int64
+	lastExport int64
+	// number of consecutive unchanged samples.
+	stable int
+	// current export interval in nanoseconds, 0 means every sample.
+	interval int64
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &adaptiveSample{
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *adaptiveSample) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.valueNames = make([]*regexp.Regexp, 0, len(p.ValueNames))
+	for _, reg := range p.ValueNames {
+		re, err := regexp.Compile(reg)
+		if err != nil {
+			return err
+		}
+		p.valueNames = append(p.valueNames, re)
+	}
+	if p.StableCount <= 0 {
+		p.StableCount = defaultStableCount
+	}
+	if p.Factor == 0 {
+		p.Factor = defaultFactor
+	}
+	if p.Factor <= 1 {
+		return fmt.Errorf("factor must be greater than 1, got %v", p.Factor)
+	}
+	if p.Heartbeat <= 0 {
+		p.Heartbeat = defaultHeartbeat
+	}
+	if p.CacheSize <= 0 {
+		p.CacheSize = defaultCacheSize
+	}
+	p.leaves, err = lru.New[string, leafState](p.CacheSize)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *adaptiveSample) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	res := make([]*formatters.EventMsg, 0, len(es))
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		if len(e.Values) == 0 {
+			res = append(res, e)
+			continue
+		}
+		prefix := eventKey(e)
+		for k, v := range e.Values {
+			if !p.matchValueName(k) {
+				continue
+			}
+			if !p.export(prefix+k, v, e.Timestamp) {
+				delete(e.Values, k)
+			}
+		}
+		// drop the events left without values
+		if len(e.Values) == 0 && len(e.Deletes) == 0 {
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// export updates the sampling state of the value identified by key
+// and reports whether the sample received at ts should be exported.
+func (p *adaptiveSample) export(key string, v any, ts int64) bool {
+	st, ok := p.leaves.Get(key)
+	if !ok || !reflect.DeepEqual(st.value, v) {
+		if ok && st.interval > 0 {
+			p.logger.Printf("value %q changed, exporting every sample", key)
+		}
+		p.leaves.Add(key, leafState{value: v, lastSeen: ts, lastExport: ts})
+		return true
+	}
+	period := ts - st.lastSeen
+	if period <= 0 {
+		// same or older timestamp, keep the current decision
+		return st.lastExport == ts
+	}
+	st.lastSeen = ts
+	st.stable++
+	heartbeat := int64(p.Heartbeat)
+	switch {
+	case st.stable < p.StableCount:
+		st.lastExport = ts
+	case ts-st.lastExport >= heartbeat:
+		st.lastExport = ts
+		st.interval = heartbeat
+	case st.interval == 0:
+		// the value just became stable, start widening from the observed period.
+		st.interval = p.widen(period)
+		p.logger.Printf("value %q is stable, export interval set to %s", key, time.Duration(st.interval))
+	case ts-st.lastExport >= st.interval:
+		st.lastExport = ts
+		st.interval = p.widen(st.interval)
+	}
+	p.leaves.Add(key, st)
+	return st.lastExport == ts
+}
+
+func (p *adaptiveSample) widen(interval int64) int64 {
+	interval = int64(float64(interval) * p.Factor)
+	if heartbeat := int64(p.Heartbeat); interval > heartbeat {
+		return heartbeat
+	}
+	return interval
+}
+
+func (p *adaptiveSample) matchValueName(name string) bool {
+	if len(p.valueNames) == 0 {
+		return true
+	}
+	for _, re := range p.valueNames {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventKey identifies the source of an event using its name and tags.
+func eventKey(e *formatters.EventMsg) string {
+	tagKeys := make([]string, 0, len(e.Tags))
+	for k := range e.Tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+	sb := new(strings.Builder)
+	sb.WriteString(e.Name)
+	sb.WriteString("\n")
+	for _, k := range tagKeys {
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(e.Tags[k])
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (p *adaptiveSample) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *adaptiveSample) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *adaptiveSample) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *adaptiveSample) WithProcessors(procs map[string]map[string]any) {}
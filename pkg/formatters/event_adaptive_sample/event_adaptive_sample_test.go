@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_adaptive_sample
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func newProcessor(t *testing.T, cfg map[string]interface{}) formatters.EventProcessor {
+	t.Helper()
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(cfg); err != nil {
+		t.Fatalf("failed to initialize processor: %v", err)
+	}
+	return p
+}
+
+func sample(ts time.Duration, values map[string]interface{}) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      "sub1",
+		Timestamp: int64(ts),
+		Tags:      map[string]string{"source": "router1", "interface_name": "ethernet-1/1"},
+		Values:    values,
+	}
+}
+
+func TestAdaptiveSample(t *testing.T) {
+	p := newProcessor(t, map[string]interface{}{
+		"stable-count": 3,
+		"heartbeat":    "100s",
+	})
+	// a stable value sampled every 10s
+	exported := make([]time.Duration, 0)
+	for ts := time.Duration(0); ts <= 300*time.Second; ts += 10 * time.Second {
+		res := p.Apply(sample(ts, map[string]interface{}{"/interface/state/oper-status": "UP"}))
+		if len(res) == 1 {
+			exported = append(exported, ts)
+		}
+	}
+	want := []time.Duration{0, 10, 20, 40, 80, 160, 260}
+	if len(exported) != len(want) {
+		t.Fatalf("unexpected exported samples: %v", exported)
+	}
+	for i := range want {
+		if exported[i] != want[i]*time.Second {
+			t.Fatalf("unexpected exported samples: got %v, want %v (seconds)", exported, want)
+		}
+	}
+	// a change is exported immediately and resets the interval
+	res := p.Apply(sample(310*time.Second, map[string]interface{}{"/interface/state/oper-status": "DOWN"}))
+	if len(res) != 1 {
+		t.Fatal("changed value was not exported")
+	}
+	res = p.Apply(sample(320*time.Second, map[string]interface{}{"/interface/state/oper-status": "DOWN"}))
+	if len(res) != 1 {
+		t.Fatal("value was not exported after a change")
+	}
+}
+
+func TestAdaptiveSampleValueNames(t *testing.T) {
+	p := newProcessor(t, map[string]interface{}{
+		"value-names":  []string{"oper-status$"},
+		"stable-count": 1,
+	})
+	var res []*formatters.EventMsg
+	for i, counter := range []int{1, 2} {
+		res = p.Apply(sample(time.Duration(i)*10*time.Second, map[string]interface{}{
+			"/interface/state/oper-status":        "UP",
+			"/interface/state/counters/in-octets": counter,
+		}))
+	}
+	if len(res) != 1 {
+		t.Fatal("event with a changing value was dropped")
+	}
+	if _, ok := res[0].Values["/interface/state/oper-status"]; ok {
+		t.Error("stable value was not removed from the event")
+	}
+	if _, ok := res[0].Values["/interface/state/counters/in-octets"]; !ok {
+		t.Error("unmatched value was removed from the event")
+	}
+}
+
+func TestAdaptiveSampleInit(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"factor": 0.5}); err == nil {
+		t.Error("expected an error for a factor lower than 1")
+	}
+	p = formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"value-names": []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid value-names regex")
+	}
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
@@ -105,6 +105,7 @@ func (p *combine) Init(cfg any, opts ...formatters.Option) error {
 				if err != nil {
 					return fmt.Errorf("failed initializing event processor '%s' of type='%s': %v", proc.Name, epType, err)
 				}
+				proc.proc = formatters.Bypassable(proc.Name, proc.proc)
 				p.logger.Printf("added event processor '%s' of type=%s to combine processor", proc.Name, epType)
 				continue
 			}
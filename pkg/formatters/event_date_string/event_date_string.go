@@ -35,7 +35,7 @@ type dateString struct {
 	Tags      []string `mapstructure:"tag-names,omitempty" json:"tag-names,omitempty"`
 	Values    []string `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
 	Precision string   `mapstructure:"precision,omitempty" json:"precision,omitempty"`
-	Format    string   `mapstructure:"format,omitempty" json:"format,omitempty"`
+	Format    string   `mapstructure:"format,omitempty" json:"format,omitempty" default:"2006-01-02T15:04:05Z07:00"`
 	Location  string   `mapstructure:"location,omitempty" json:"location,omitempty"`
 	Debug     bool     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 
@@ -79,6 +79,9 @@ func (d *dateString) Init(cfg interface{}, opts ...formatters.Option) error {
 		}
 		d.tags = append(d.tags, re)
 	}
+	if d.Format == "" {
+		d.Format = time.RFC3339
+	}
 	// set tz
 	d.location = time.Local
 	if d.Location != "" {
@@ -124,9 +127,6 @@ func (d *dateString) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
 					case "ns", "nanosecond":
 						td = time.Unix(0, int64(iv))
 					}
-					if d.Format == "" {
-						d.Format = time.RFC3339
-					}
 					e.Values[k] = td.In(d.location).Format(d.Format)
 					break
 				}
@@ -151,9 +151,6 @@ func (d *dateString) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
 					case "ns", "nanosecond":
 						td = time.Unix(0, int64(iv))
 					}
-					if d.Format == "" {
-						d.Format = time.RFC3339
-					}
 					e.Values[k] = td.Format(d.Format)
 					break
 				}
@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
@@ -95,3 +96,13 @@ func TestEventDateString(t *testing.T) {
 		}
 	}
 }
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
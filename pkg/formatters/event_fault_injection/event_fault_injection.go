@@ -0,0 +1,125 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_fault_injection
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/faults"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType = "event-fault-injection"
+	loggingPrefix = "[" + processorType + "] "
+)
+
+// faultInjection delays, drops or duplicates the events
+// matching its condition, or all of them if it is not set.
+type faultInjection struct {
+	Condition     string `mapstructure:"condition,omitempty" json:"condition,omitempty"`
+	faults.Config `mapstructure:",squash"`
+	Debug         bool `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	code     *gojq.Code
+	injector *faults.Injector
+	logger   *log.Logger
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &faultInjection{
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *faultInjection) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.Condition = strings.TrimSpace(p.Condition)
+	q, err := gojq.Parse(p.Condition)
+	if err != nil {
+		return err
+	}
+	p.code, err = gojq.Compile(q)
+	if err != nil {
+		return err
+	}
+	p.injector, err = faults.NewInjector(&p.Config)
+	if err != nil {
+		return err
+	}
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *faultInjection) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	res := make([]*formatters.EventMsg, 0, len(es))
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		if p.Condition != "" {
+			ok, err := formatters.CheckCondition(p.code, e)
+			if err != nil {
+				p.logger.Printf("condition check failed: %v", err)
+			}
+			if !ok {
+				res = append(res, e)
+				continue
+			}
+		}
+		switch p.injector.Inject(context.Background()) {
+		case 0:
+			p.logger.Printf("dropping event: %v", e)
+		case 1:
+			res = append(res, e)
+		case 2:
+			p.logger.Printf("duplicating event: %v", e)
+			res = append(res, e, formatters.CopyEventMsg(e))
+		}
+	}
+	return res
+}
+
+func (p *faultInjection) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *faultInjection) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *faultInjection) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *faultInjection) WithProcessors(procs map[string]map[string]any) {}
@@ -0,0 +1,68 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_fault_injection
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestFaultInjection(t *testing.T) {
+	tests := map[string]struct {
+		processor map[string]interface{}
+		input     []*formatters.EventMsg
+		want      []string
+	}{
+		"drop_all": {
+			processor: map[string]interface{}{"drop-probability": 1},
+			input:     []*formatters.EventMsg{{Name: "a"}, {Name: "b"}},
+			want:      []string{},
+		},
+		"duplicate_all": {
+			processor: map[string]interface{}{"duplicate-probability": 1},
+			input:     []*formatters.EventMsg{{Name: "a"}, {Name: "b"}},
+			want:      []string{"a", "a", "b", "b"},
+		},
+		"drop_condition": {
+			processor: map[string]interface{}{
+				"condition":        `.name == "b"`,
+				"drop-probability": 1,
+			},
+			input: []*formatters.EventMsg{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			want:  []string{"a", "c"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pi := formatters.EventProcessors[processorType]
+			p := pi()
+			err := p.Init(tt.processor)
+			if err != nil {
+				t.Fatalf("failed to initialize processor: %v", err)
+			}
+			out := p.Apply(tt.input...)
+			if len(out) != len(tt.want) {
+				t.Fatalf("expected %d events, got %d", len(tt.want), len(out))
+			}
+			for i, e := range out {
+				if e.Name != tt.want[i] {
+					t.Errorf("event %d: expected %q, got %q", i, tt.want[i], e.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestFaultInjectionInitError(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"duplicate-probability": -1}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
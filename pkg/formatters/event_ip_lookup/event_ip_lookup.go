@@ -0,0 +1,257 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_ip_lookup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType     = "event-ip-lookup"
+	loggingPrefix     = "[" + processorType + "] "
+	defaultCacheSize  = 10000
+	defaultCacheTTL   = time.Hour
+	defaultDNSTimeout = time.Second
+	ptrField          = "ptr"
+)
+
+// default tags extracted from the GeoIP2/GeoLite2 City, Country and ASN databases.
+var defaultFields = map[string]string{
+	"country": "country/iso_code",
+	"city":    "city/names/en",
+	"asn":     "autonomous_system_number",
+	"as_org":  "autonomous_system_organization",
+}
+
+// ipLookup enriches the events containing IP addresses with reverse DNS and GeoIP/ASN tags
+type ipLookup struct {
+	ValueNames []string          `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
+	TagNames   []string          `mapstructure:"tag-names,omitempty" json:"tag-names,omitempty"`
+	ReverseDNS bool              `mapstructure:"reverse-dns,omitempty" json:"reverse-dns,omitempty"`
+	DNSTimeout time.Duration     `mapstructure:"dns-timeout,omitempty" json:"dns-timeout,omitempty" default:"1s"`
+	MMDBFiles  []string          `mapstructure:"mmdb-files,omitempty" json:"mmdb-files,omitempty"`
+	Fields     map[string]string `mapstructure:"fields,omitempty" json:"fields,omitempty"`
+	CacheSize  int               `mapstructure:"cache-size,omitempty" json:"cache-size,omitempty" default:"10000"`
+	CacheTTL   time.Duration     `mapstructure:"cache-ttl,omitempty" json:"cache-ttl,omitempty" default:"1h"`
+	Debug      bool              `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	valueNames []*regexp.Regexp
+	tagNames   []*regexp.Regexp
+	dbs        []*mmdbReader
+	cache      *ttlcache.Cache[string, map[string]string]
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+	logger     *log.Logger
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &ipLookup{
+			lookupAddr: net.DefaultResolver.LookupAddr,
+			logger:     log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *ipLookup) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(p.ValueNames) == 0 && len(p.TagNames) == 0 {
+		return errors.New("one of value-names or tag-names must be set")
+	}
+	if !p.ReverseDNS && len(p.MMDBFiles) == 0 {
+		return errors.New("one of reverse-dns or mmdb-files must be set")
+	}
+	p.valueNames = make([]*regexp.Regexp, 0, len(p.ValueNames))
+	for _, reg := range p.ValueNames {
+		re, err := regexp.Compile(reg)
+		if err != nil {
+			return err
+		}
+		p.valueNames = append(p.valueNames, re)
+	}
+	p.tagNames = make([]*regexp.Regexp, 0, len(p.TagNames))
+	for _, reg := range p.TagNames {
+		re, err := regexp.Compile(reg)
+		if err != nil {
+			return err
+		}
+		p.tagNames = append(p.tagNames, re)
+	}
+	p.dbs = make([]*mmdbReader, 0, len(p.MMDBFiles))
+	for _, name := range p.MMDBFiles {
+		db, err := openMMDB(name)
+		if err != nil {
+			return fmt.Errorf("failed to load MaxMind DB file %q: %w", name, err)
+		}
+		p.logger.Printf("loaded MaxMind DB %q: type=%s", name, db.dbType)
+		p.dbs = append(p.dbs, db)
+	}
+	if len(p.Fields) == 0 {
+		p.Fields = defaultFields
+	}
+	if p.DNSTimeout <= 0 {
+		p.DNSTimeout = defaultDNSTimeout
+	}
+	if p.CacheSize <= 0 {
+		p.CacheSize = defaultCacheSize
+	}
+	if p.CacheTTL <= 0 {
+		p.CacheTTL = defaultCacheTTL
+	}
+	p.cache = ttlcache.New(
+		ttlcache.WithTTL[string, map[string]string](p.CacheTTL),
+		ttlcache.WithCapacity[string, map[string]string](uint64(p.CacheSize)),
+		ttlcache.WithDisableTouchOnHit[string, map[string]string](),
+	)
+
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *ipLookup) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		add := make(map[string]string)
+		for k, v := range e.Values {
+			if !matchAny(p.valueNames, k) {
+				continue
+			}
+			if s, ok := v.(string); ok {
+				p.addLookupTags(add, k, s)
+			}
+		}
+		for k, v := range e.Tags {
+			if matchAny(p.tagNames, k) {
+				p.addLookupTags(add, k, v)
+			}
+		}
+		if len(add) == 0 {
+			continue
+		}
+		if e.Tags == nil {
+			e.Tags = make(map[string]string, len(add))
+		}
+		for k, v := range add {
+			e.Tags[k] = v
+		}
+	}
+	return es
+}
+
+func (p *ipLookup) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *ipLookup) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *ipLookup) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *ipLookup) WithProcessors(procs map[string]map[string]any) {}
+
+// addLookupTags looks up the IP address s and adds the resulting tags to tags,
+// named after the last element of the value or tag name.
+func (p *ipLookup) addLookupTags(tags map[string]string, name, s string) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for field, v := range p.lookup(addr) {
+		tags[name+"_"+field] = v
+	}
+}
+
+// lookup returns the reverse DNS and MMDB fields of addr.
+func (p *ipLookup) lookup(addr netip.Addr) map[string]string {
+	key := addr.String()
+	if item := p.cache.Get(key); item != nil {
+		return item.Value()
+	}
+	r := make(map[string]string)
+	for _, db := range p.dbs {
+		rec, err := db.lookup(addr)
+		if err != nil {
+			p.logger.Printf("failed to lookup %s in MaxMind DB %s: %v", key, db.dbType, err)
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+		for field, fp := range p.Fields {
+			if _, ok := r[field]; ok {
+				continue
+			}
+			if v, ok := recordField(rec, fp); ok {
+				r[field] = fmt.Sprint(v)
+			}
+		}
+	}
+	if p.ReverseDNS {
+		ctx, cancel := context.WithTimeout(context.Background(), p.DNSTimeout)
+		names, err := p.lookupAddr(ctx, key)
+		cancel()
+		if err != nil {
+			p.logger.Printf("reverse DNS lookup of %s failed: %v", key, err)
+		}
+		if len(names) > 0 {
+			r[ptrField] = strings.TrimSuffix(names[0], ".")
+		}
+	}
+	if p.Debug {
+		p.logger.Printf("lookup %s: %v", key, r)
+	}
+	p.cache.Set(key, r, ttlcache.DefaultTTL)
+	return r
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
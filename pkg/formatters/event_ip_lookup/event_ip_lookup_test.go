@@ -0,0 +1,456 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_ip_lookup
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type item struct {
+	input  []*formatters.EventMsg
+	output []*formatters.EventMsg
+}
+
+var testset = map[string]struct {
+	processor map[string]interface{}
+	tests     []item
+}{
+	"mmdb_value": {
+		processor: map[string]interface{}{
+			"value-names": []string{"/neighbor-address$"},
+			"mmdb-files":  []string{"geo.mmdb", "asn.mmdb"},
+		},
+		tests: []item{
+			{
+				input:  nil,
+				output: nil,
+			},
+			{
+				input: []*formatters.EventMsg{
+					{
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "192.0.2.1"},
+					},
+					{
+						Tags:   map[string]string{"source": "r1"},
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "10.1.1.1"},
+					},
+					{
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "172.16.0.1"},
+					},
+					{
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "not-an-ip"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Tags: map[string]string{
+							"neighbor-address_country": "FR",
+							"neighbor-address_city":    "Paris",
+							"neighbor-address_asn":     "64496",
+							"neighbor-address_as_org":  "Example AS",
+						},
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "192.0.2.1"},
+					},
+					{
+						Tags: map[string]string{
+							"source":                   "r1",
+							"neighbor-address_country": "US",
+						},
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "10.1.1.1"},
+					},
+					{
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "172.16.0.1"},
+					},
+					{
+						Values: map[string]interface{}{"/bgp/neighbors/neighbor/state/neighbor-address": "not-an-ip"},
+					},
+				},
+			},
+		},
+	},
+	"mmdb_tag_fields": {
+		processor: map[string]interface{}{
+			"tag-names":  []string{"^peer_address$"},
+			"mmdb-files": []string{"geo.mmdb"},
+			"fields": map[string]string{
+				"region": "subdivisions/0/iso_code",
+			},
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Tags:   map[string]string{"peer_address": "192.0.2.10"},
+						Values: map[string]interface{}{"counter": 1},
+					},
+					{
+						Tags:   map[string]string{"peer_address": "2001:db8::1"},
+						Values: map[string]interface{}{"counter": 1},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Tags:   map[string]string{"peer_address": "192.0.2.10", "peer_address_region": "IDF"},
+						Values: map[string]interface{}{"counter": 1},
+					},
+					{
+						Tags:   map[string]string{"peer_address": "2001:db8::1", "peer_address_region": "BY"},
+						Values: map[string]interface{}{"counter": 1},
+					},
+				},
+			},
+		},
+	},
+	"reverse_dns": {
+		processor: map[string]interface{}{
+			"tag-names":   []string{"^peer_address$"},
+			"reverse-dns": true,
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Tags: map[string]string{"peer_address": "192.0.2.1"},
+					},
+					{
+						Tags: map[string]string{"peer_address": "192.0.2.2"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Tags: map[string]string{"peer_address": "192.0.2.1", "peer_address_ptr": "r1.example.com"},
+					},
+					{
+						Tags: map[string]string{"peer_address": "192.0.2.2"},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestEventIPLookup(t *testing.T) {
+	dir := writeTestDBs(t)
+	for name, ts := range testset {
+		t.Run(name, func(t *testing.T) {
+			cfg := make(map[string]interface{}, len(ts.processor))
+			for k, v := range ts.processor {
+				cfg[k] = v
+			}
+			if files, ok := cfg["mmdb-files"].([]string); ok {
+				paths := make([]string, 0, len(files))
+				for _, f := range files {
+					paths = append(paths, filepath.Join(dir, f))
+				}
+				cfg["mmdb-files"] = paths
+			}
+			p := newTestProcessor(testLookupAddr)
+			err := p.Init(cfg)
+			if err != nil {
+				t.Fatalf("failed to initialize processor: %v", err)
+			}
+			for i, item := range ts.tests {
+				outs := p.Apply(item.input...)
+				if !reflect.DeepEqual(outs, item.output) {
+					t.Errorf("failed at %q item %d", name, i)
+					for j := range outs {
+						t.Logf("expected: %+v", item.output[j])
+						t.Logf("     got: %+v", outs[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEventIPLookupCache(t *testing.T) {
+	calls := 0
+	p := newTestProcessor(func(ctx context.Context, addr string) ([]string, error) {
+		calls++
+		return testLookupAddr(ctx, addr)
+	})
+	err := p.Init(map[string]interface{}{
+		"tag-names":   []string{"^peer_address$"},
+		"reverse-dns": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		p.Apply(
+			&formatters.EventMsg{Tags: map[string]string{"peer_address": "192.0.2.1"}},
+			&formatters.EventMsg{Tags: map[string]string{"peer_address": "192.0.2.2"}},
+		)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 reverse DNS lookups, got %d", calls)
+	}
+}
+
+func TestEventIPLookupInit(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"no_names": {
+			"reverse-dns": true,
+		},
+		"no_source": {
+			"value-names": []string{"address"},
+		},
+		"bad_regex": {
+			"value-names": []string{"("},
+			"reverse-dns": true,
+		},
+		"missing_file": {
+			"value-names": []string{"address"},
+			"mmdb-files":  []string{"/does/not/exist.mmdb"},
+		},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newTestProcessor(testLookupAddr)
+			if err := p.Init(cfg); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestMMDBReader(t *testing.T) {
+	w := newTestMMDBWriter(6)
+	w.insert("192.0.2.0/24", map[string]any{"n": uint32(1)})
+	w.insert("2001:db8::/32", map[string]any{"n": uint32(2), "l": []any{"a", "b"}})
+	r, err := newMMDBReader(w.bytes("Test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.dbType != "Test" {
+		t.Errorf("unexpected database type %q", r.dbType)
+	}
+	tests := map[string]any{
+		"192.0.2.200":      uint64(1),
+		"::ffff:192.0.2.1": uint64(1),
+		"2001:db8:1::1":    uint64(2),
+		"198.51.100.1":     nil,
+		"2001:db9::1":      nil,
+	}
+	for addr, want := range tests {
+		rec, err := r.lookup(netip.MustParseAddr(addr))
+		if err != nil {
+			t.Fatalf("%s: %v", addr, err)
+		}
+		var got any
+		if rec != nil {
+			got = rec["n"]
+		}
+		if got != want {
+			t.Errorf("%s: expected %v, got %v", addr, want, got)
+		}
+	}
+	rec, _ := r.lookup(netip.MustParseAddr("2001:db8::1"))
+	if v, ok := recordField(rec, "l/1"); !ok || v != "b" {
+		t.Errorf("unexpected field l/1: %v", v)
+	}
+}
+
+func newTestProcessor(lookupAddr func(ctx context.Context, addr string) ([]string, error)) *ipLookup {
+	return &ipLookup{
+		lookupAddr: lookupAddr,
+		logger:     log.New(io.Discard, "", 0),
+	}
+}
+
+func testLookupAddr(_ context.Context, addr string) ([]string, error) {
+	if addr == "192.0.2.1" {
+		return []string{"r1.example.com."}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func writeTestDBs(t *testing.T) string {
+	dir := t.TempDir()
+	geo := newTestMMDBWriter(6)
+	geo.insert("192.0.2.0/24", map[string]any{
+		"country":      map[string]any{"iso_code": "FR"},
+		"city":         map[string]any{"names": map[string]any{"en": "Paris"}},
+		"subdivisions": []any{map[string]any{"iso_code": "IDF"}},
+	})
+	geo.insert("10.0.0.0/8", map[string]any{
+		"country": map[string]any{"iso_code": "US"},
+	})
+	geo.insert("2001:db8::/32", map[string]any{
+		"subdivisions": []any{map[string]any{"iso_code": "BY"}},
+	})
+	asn := newTestMMDBWriter(4)
+	asn.insert("192.0.2.0/23", map[string]any{
+		"autonomous_system_number":       uint32(64496),
+		"autonomous_system_organization": "Example AS",
+	})
+	for name, w := range map[string]*testMMDBWriter{
+		"geo.mmdb": geo,
+		"asn.mmdb": asn,
+	} {
+		err := os.WriteFile(filepath.Join(dir, name), w.bytes(name), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// testMMDBWriter builds minimal MaxMind DB files with a 24 bit record size.
+type testMMDBWriter struct {
+	ipVersion int
+	// each record is either -1 (empty), a node index or
+	// -2-i for the data record i.
+	nodes   [][2]int
+	records []map[string]any
+}
+
+func newTestMMDBWriter(ipVersion int) *testMMDBWriter {
+	return &testMMDBWriter{ipVersion: ipVersion, nodes: [][2]int{{-1, -1}}}
+}
+
+func (w *testMMDBWriter) insert(prefix string, rec map[string]any) {
+	p := netip.MustParsePrefix(prefix)
+	ip := p.Addr().AsSlice()
+	bits := p.Bits()
+	if w.ipVersion == 6 && p.Addr().Is4() {
+		a16 := netip.AddrFrom16(p.Addr().As16()).As16()
+		// IPv4 addresses are stored in ::/96
+		for i := 10; i < 12; i++ {
+			a16[i] = 0
+		}
+		ip = a16[:]
+		bits += 96
+	}
+	w.records = append(w.records, rec)
+	node := 0
+	for i := 0; i < bits; i++ {
+		bit := int(ip[i/8]>>(7-i%8)) & 1
+		if i == bits-1 {
+			w.nodes[node][bit] = -2 - (len(w.records) - 1)
+			return
+		}
+		next := w.nodes[node][bit]
+		if next < 0 {
+			w.nodes = append(w.nodes, [2]int{-1, -1})
+			next = len(w.nodes) - 1
+			w.nodes[node][bit] = next
+		}
+		node = next
+	}
+}
+
+func (w *testMMDBWriter) bytes(dbType string) []byte {
+	nodeCount := len(w.nodes)
+	var data []byte
+	offsets := make([]int, 0, len(w.records))
+	for _, rec := range w.records {
+		offsets = append(offsets, len(data))
+		data = append(data, encodeTestMMDB(rec)...)
+	}
+	var b []byte
+	for _, n := range w.nodes {
+		for _, rec := range n {
+			v := rec
+			switch {
+			case rec == -1:
+				v = nodeCount
+			case rec <= -2:
+				v = nodeCount + 16 + offsets[-2-rec]
+			}
+			b = append(b, byte(v>>16), byte(v>>8), byte(v))
+		}
+	}
+	b = append(b, make([]byte, 16)...)
+	b = append(b, data...)
+	b = append(b, mmdbMetadataMarker...)
+	b = append(b, encodeTestMMDB(map[string]any{
+		"node_count":    uint32(nodeCount),
+		"record_size":   uint32(24),
+		"ip_version":    uint32(w.ipVersion),
+		"database_type": dbType,
+	})...)
+	return b
+}
+
+func encodeTestMMDB(v any) []byte {
+	ctrl := func(typ, size int) []byte {
+		var b []byte
+		if typ <= 7 {
+			b = []byte{byte(typ << 5)}
+		} else {
+			b = []byte{0, byte(typ - 7)}
+		}
+		if size < 29 {
+			b[0] |= byte(size)
+			return b
+		}
+		// sizes up to 284 only
+		b[0] |= 29
+		return append(b, byte(size-29))
+	}
+	switch v := v.(type) {
+	case string:
+		return append(ctrl(mmdbString, len(v)), v...)
+	case uint32:
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], v)
+		i := 0
+		for i < 4 && buf[i] == 0 {
+			i++
+		}
+		return append(ctrl(mmdbUint32, 4-i), buf[i:]...)
+	case []any:
+		b := ctrl(mmdbArray, len(v))
+		for _, e := range v {
+			b = append(b, encodeTestMMDB(e)...)
+		}
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := ctrl(mmdbMap, len(keys))
+		for _, k := range keys {
+			b = append(b, encodeTestMMDB(k)...)
+			b = append(b, encodeTestMMDB(v[k])...)
+		}
+		return b
+	}
+	panic("unsupported type")
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	err := p.Init(map[string]interface{}{
+		"value-names": []string{"ip"},
+		"reverse-dns": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p, "reverse-dns") {
+		t.Error(m)
+	}
+}
@@ -31,8 +31,8 @@ const (
 
 // jq runs a jq expression on the received event messages
 type jq struct {
-	Condition  string `mapstructure:"condition,omitempty"`
-	Expression string `mapstructure:"expression,omitempty"`
+	Condition  string `mapstructure:"condition,omitempty" default:"all([true])"`
+	Expression string `mapstructure:"expression,omitempty" default:"."`
 	Debug      bool   `mapstructure:"debug,omitempty"`
 
 	cond   *gojq.Code
@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/api/utils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
@@ -783,3 +784,13 @@ func TestEventJQ(t *testing.T) {
 		}
 	}
 }
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
@@ -0,0 +1,399 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_math
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType     = "event-math"
+	loggingPrefix     = "[" + processorType + "] "
+	defaultExpiration = time.Hour
+)
+
+// matches the stateful functions calls, e.g: rate(in_octets) or delta([in-octets]).
+// they are replaced by the escaped parameter [rate(in_octets)] before parsing the expression.
+var statefulFuncRe = regexp.MustCompile(`\b(rate|delta)\(\s*(\[[^\]]+\]|[A-Za-z_][A-Za-z0-9_.]*)\s*\)`)
+
+// eventMath computes new values from the event values using arithmetic expressions
+type eventMath struct {
+	Vars        map[string]string `mapstructure:"vars,omitempty" json:"vars,omitempty"`
+	Expressions []*expression     `mapstructure:"expressions,omitempty" json:"expressions,omitempty"`
+	KeyTags     []string          `mapstructure:"key-tags,omitempty" json:"key-tags,omitempty"`
+	Expiration  time.Duration     `mapstructure:"expiration,omitempty" json:"expiration,omitempty" default:"1h"`
+	Debug       bool              `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	vars        map[string]*regexp.Regexp
+	state       map[string]*keyState
+	lastCleanup time.Time
+	logger      *log.Logger
+}
+
+type expression struct {
+	// name of the resulting value
+	Name string `mapstructure:"name,omitempty" json:"name,omitempty"`
+	// arithmetic expression
+	Expression string `mapstructure:"expression,omitempty" json:"expression,omitempty"`
+
+	expr *govaluate.EvaluableExpression
+	// variables the expression depends on
+	deps []string
+}
+
+// keyState holds the last samples of the variables sharing the same key.
+type keyState struct {
+	vars     map[string]*sample
+	lastSeen time.Time
+}
+
+type sample struct {
+	value   float64
+	ts      int64
+	prev    float64
+	prevTs  int64
+	hasPrev bool
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &eventMath{
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *eventMath) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(p.Expressions) == 0 {
+		return errors.New("missing expressions")
+	}
+	p.vars = make(map[string]*regexp.Regexp, len(p.Vars))
+	for name, reg := range p.Vars {
+		re, err := regexp.Compile(reg)
+		if err != nil {
+			return fmt.Errorf("var %q: %w", name, err)
+		}
+		p.vars[name] = re
+	}
+	functions := map[string]govaluate.ExpressionFunction{
+		"abs":   mathFunc1(math.Abs),
+		"ceil":  mathFunc1(math.Ceil),
+		"floor": mathFunc1(math.Floor),
+		"round": mathFunc1(math.Round),
+		"min":   mathFunc2(math.Min),
+		"max":   mathFunc2(math.Max),
+	}
+	for i, e := range p.Expressions {
+		if e.Name == "" {
+			return fmt.Errorf("expression %d: missing name", i)
+		}
+		e.expr, err = govaluate.NewEvaluableExpressionWithFunctions(
+			statefulFuncRe.ReplaceAllStringFunc(e.Expression, normalizeStatefulFunc),
+			functions,
+		)
+		if err != nil {
+			return fmt.Errorf("expression %q: %w", e.Name, err)
+		}
+		for _, v := range e.expr.Vars() {
+			_, name := splitStatefulFunc(v)
+			e.deps = append(e.deps, name)
+		}
+	}
+	if p.Expiration <= 0 {
+		p.Expiration = defaultExpiration
+	}
+	p.state = make(map[string]*keyState)
+	p.lastCleanup = time.Now()
+
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *eventMath) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	now := time.Now()
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		st := p.keyState(e)
+		st.lastSeen = now
+		updated := make(map[string]struct{})
+		for k, v := range e.Values {
+			for name, re := range p.vars {
+				if !re.MatchString(k) {
+					continue
+				}
+				f, err := toFloat(v)
+				if err != nil {
+					p.logger.Printf("var %q: %v", name, err)
+					continue
+				}
+				st.update(name, f, e.Timestamp)
+				updated[name] = struct{}{}
+			}
+			updated[k] = struct{}{}
+		}
+		for _, expr := range p.Expressions {
+			if !expr.dependsOn(updated) {
+				continue
+			}
+			r, err := expr.expr.Eval(&params{st: st, e: e})
+			if err != nil {
+				if p.Debug {
+					p.logger.Printf("expression %q not evaluated: %v", expr.Name, err)
+				}
+				continue
+			}
+			if f, ok := r.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				if p.Debug {
+					p.logger.Printf("expression %q: invalid result %v", expr.Name, f)
+				}
+				continue
+			}
+			if e.Values == nil {
+				e.Values = make(map[string]interface{})
+			}
+			e.Values[expr.Name] = r
+		}
+	}
+	if now.Sub(p.lastCleanup) >= p.Expiration {
+		p.cleanup(now)
+	}
+	return es
+}
+
+func (p *eventMath) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *eventMath) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *eventMath) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *eventMath) WithProcessors(procs map[string]map[string]any) {}
+
+// keyState returns the state of the event key,
+// built from the event name and its tags, or from the configured key-tags.
+func (p *eventMath) keyState(e *formatters.EventMsg) *keyState {
+	var sb strings.Builder
+	if len(p.KeyTags) > 0 {
+		for _, t := range p.KeyTags {
+			sb.WriteString(t)
+			sb.WriteString("=")
+			sb.WriteString(e.Tags[t])
+			sb.WriteString(",")
+		}
+	} else {
+		sb.WriteString(e.Name)
+		sb.WriteString(",")
+		keys := make([]string, 0, len(e.Tags))
+		for k := range e.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(e.Tags[k])
+			sb.WriteString(",")
+		}
+	}
+	key := sb.String()
+	st, ok := p.state[key]
+	if !ok {
+		st = &keyState{vars: make(map[string]*sample)}
+		p.state[key] = st
+	}
+	return st
+}
+
+// cleanup removes the keys not seen for longer than the expiration.
+func (p *eventMath) cleanup(now time.Time) {
+	for k, st := range p.state {
+		if now.Sub(st.lastSeen) >= p.Expiration {
+			delete(p.state, k)
+		}
+	}
+	p.lastCleanup = now
+}
+
+func (st *keyState) update(name string, v float64, ts int64) {
+	s, ok := st.vars[name]
+	if !ok {
+		st.vars[name] = &sample{value: v, ts: ts}
+		return
+	}
+	s.prev, s.prevTs, s.hasPrev = s.value, s.ts, true
+	s.value, s.ts = v, ts
+}
+
+func (e *expression) dependsOn(updated map[string]struct{}) bool {
+	for _, d := range e.deps {
+		if _, ok := updated[d]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// params resolves the expression parameters from the key state,
+// then from the event values and tags.
+type params struct {
+	st *keyState
+	e  *formatters.EventMsg
+}
+
+func (p *params) Get(name string) (interface{}, error) {
+	fn, name := splitStatefulFunc(name)
+	if fn != "" {
+		s, ok := p.st.vars[name]
+		if !ok || !s.hasPrev {
+			return nil, fmt.Errorf("%s(%s): not enough samples", fn, name)
+		}
+		switch fn {
+		case "delta":
+			return s.value - s.prev, nil
+		case "rate":
+			if s.ts <= s.prevTs {
+				return nil, fmt.Errorf("rate(%s): invalid timestamps", name)
+			}
+			if s.value < s.prev {
+				return nil, fmt.Errorf("rate(%s): counter reset", name)
+			}
+			return (s.value - s.prev) / time.Duration(s.ts-s.prevTs).Seconds(), nil
+		}
+	}
+	if s, ok := p.st.vars[name]; ok {
+		return s.value, nil
+	}
+	if v, ok := p.e.Values[name]; ok {
+		if f, err := toFloat(v); err == nil {
+			return f, nil
+		}
+		return v, nil
+	}
+	if v, ok := p.e.Tags[name]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("unknown parameter %q", name)
+}
+
+// normalizeStatefulFunc turns a stateful function call into an escaped parameter name.
+func normalizeStatefulFunc(s string) string {
+	m := statefulFuncRe.FindStringSubmatch(s)
+	return "[" + m[1] + "(" + strings.Trim(m[2], "[]") + ")]"
+}
+
+// splitStatefulFunc splits a parameter name like rate(x) into its function and variable names.
+func splitStatefulFunc(s string) (string, string) {
+	for _, fn := range []string{"rate", "delta"} {
+		if strings.HasPrefix(s, fn+"(") && strings.HasSuffix(s, ")") {
+			return fn, s[len(fn)+1 : len(s)-1]
+		}
+	}
+	return "", s
+}
+
+func mathFunc1(f func(float64) float64) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		x, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected argument type %T", args[0])
+		}
+		return f(x), nil
+	}
+}
+
+func mathFunc2(f func(float64, float64) float64) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		x, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected argument type %T", args[0])
+		}
+		y, ok := args[1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected argument type %T", args[1])
+		}
+		return f(x, y), nil
+	}
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %v to float64, type %T", v, v)
+	}
+}
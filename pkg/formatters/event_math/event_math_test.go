@@ -0,0 +1,298 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_math
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type item struct {
+	input  []*formatters.EventMsg
+	output []*formatters.EventMsg
+}
+
+var testset = map[string]struct {
+	processorType string
+	processor     map[string]interface{}
+	tests         []item
+}{
+	"same_event": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"expressions": []map[string]interface{}{
+				{
+					"name":       "cpu-used",
+					"expression": "100 - [/cpu/idle]",
+				},
+			},
+		},
+		tests: []item{
+			{
+				input:  nil,
+				output: nil,
+			},
+			{
+				input: []*formatters.EventMsg{
+					{
+						Tags:   map[string]string{"cpu": "0"},
+						Values: map[string]interface{}{"/cpu/idle": uint64(80)},
+					},
+					{
+						Tags:   map[string]string{"cpu": "1"},
+						Values: map[string]interface{}{"/cpu/user": uint64(80)},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Tags:   map[string]string{"cpu": "0"},
+						Values: map[string]interface{}{"/cpu/idle": uint64(80), "cpu-used": float64(20)},
+					},
+					{
+						Tags:   map[string]string{"cpu": "1"},
+						Values: map[string]interface{}{"/cpu/user": uint64(80)},
+					},
+				},
+			},
+		},
+	},
+	"utilization": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"vars": map[string]string{
+				"in_octets": "/in-octets$",
+				"speed":     "/port-speed$",
+			},
+			"expressions": []map[string]interface{}{
+				{
+					"name":       "in-utilization",
+					"expression": "round(rate(in_octets) * 8 * 100 / (speed * 1000000))",
+				},
+			},
+			"key-tags": []string{"source", "interface_name"},
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Name:      "sub1",
+						Timestamp: 0,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/ethernet/port-speed": "1000"},
+					},
+					{
+						Name:      "sub2",
+						Timestamp: 1_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(1000)},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Name:      "sub1",
+						Timestamp: 0,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/ethernet/port-speed": "1000"},
+					},
+					{
+						Name:      "sub2",
+						Timestamp: 1_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(1000)},
+					},
+				},
+			},
+			{
+				input: []*formatters.EventMsg{
+					{
+						Name:      "sub2",
+						Timestamp: 11_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(250_001_000)},
+					},
+					{
+						Name:      "sub2",
+						Timestamp: 11_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e2"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(250_001_000)},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Name:      "sub2",
+						Timestamp: 11_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values: map[string]interface{}{
+							"/interface/statistics/in-octets": uint64(250_001_000),
+							"in-utilization":                  float64(20),
+						},
+					},
+					{
+						Name:      "sub2",
+						Timestamp: 11_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e2"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(250_001_000)},
+					},
+				},
+			},
+			{
+				// counter reset
+				input: []*formatters.EventMsg{
+					{
+						Name:      "sub2",
+						Timestamp: 21_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(10)},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Name:      "sub2",
+						Timestamp: 21_000_000_000,
+						Tags:      map[string]string{"source": "r1", "interface_name": "e1"},
+						Values:    map[string]interface{}{"/interface/statistics/in-octets": uint64(10)},
+					},
+				},
+			},
+		},
+	},
+	"delta_escaped": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"vars": map[string]string{
+				"temp": "/temperature/instant$",
+			},
+			"expressions": []map[string]interface{}{
+				{
+					"name":       "temperature-change",
+					"expression": "delta(temp)",
+				},
+				{
+					"name":       "temperature-high",
+					"expression": "temp > 70 || [/temperature/alarm-status] == 'true'",
+				},
+			},
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"component_name": "cpu"},
+						Values:    map[string]interface{}{"/temperature/instant": 60.5, "/temperature/alarm-status": "false"},
+					},
+					{
+						Timestamp: 2,
+						Tags:      map[string]string{"component_name": "cpu"},
+						Values:    map[string]interface{}{"/temperature/instant": 75.0, "/temperature/alarm-status": "false"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"component_name": "cpu"},
+						Values: map[string]interface{}{
+							"/temperature/instant":      60.5,
+							"/temperature/alarm-status": "false",
+							"temperature-high":          false,
+						},
+					},
+					{
+						Timestamp: 2,
+						Tags:      map[string]string{"component_name": "cpu"},
+						Values: map[string]interface{}{
+							"/temperature/instant":      75.0,
+							"/temperature/alarm-status": "false",
+							"temperature-change":        14.5,
+							"temperature-high":          true,
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestEventMath(t *testing.T) {
+	for name, ts := range testset {
+		if pi, ok := formatters.EventProcessors[ts.processorType]; ok {
+			t.Log("found processor")
+			p := pi()
+			err := p.Init(ts.processor)
+			if err != nil {
+				t.Errorf("failed to initialize processors: %v", err)
+				return
+			}
+			t.Logf("processor: %+v", p)
+			for i, item := range ts.tests {
+				t.Run(name, func(t *testing.T) {
+					t.Logf("running test item %d", i)
+					outs := p.Apply(item.input...)
+					if !reflect.DeepEqual(outs, item.output) {
+						t.Errorf("failed at %q item %d", name, i)
+						for j := range outs {
+							t.Logf("expected: %+v", item.output[j])
+							t.Logf("     got: %+v", outs[j])
+						}
+					}
+				})
+			}
+		} else {
+			t.Errorf("event processor %s not found", ts.processorType)
+		}
+	}
+}
+
+func TestEventMathInit(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"no_expressions": {},
+		"no_name": {
+			"expressions": []map[string]interface{}{
+				{"expression": "1 + 1"},
+			},
+		},
+		"bad_expression": {
+			"expressions": []map[string]interface{}{
+				{"name": "x", "expression": "1 +"},
+			},
+		},
+		"bad_var": {
+			"vars": map[string]string{"x": "("},
+			"expressions": []map[string]interface{}{
+				{"name": "x", "expression": "x"},
+			},
+		},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := formatters.EventProcessors[processorType]()
+			if err := p.Init(cfg); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	err := p.Init(map[string]interface{}{
+		"expressions": []interface{}{
+			map[string]interface{}{"name": "sum", "expression": "1 + 1"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
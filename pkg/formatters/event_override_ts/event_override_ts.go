@@ -29,7 +29,7 @@ const (
 type overrideTS struct {
 	//formatters.EventProcessor
 
-	Precision string `mapstructure:"precision,omitempty" json:"precision,omitempty"`
+	Precision string `mapstructure:"precision,omitempty" json:"precision,omitempty" default:"ns"`
 	Debug     bool   `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 
 	logger *log.Logger
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
@@ -171,3 +172,13 @@ func TestEventDateString(t *testing.T) {
 		}
 	}
 }
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
@@ -77,10 +77,21 @@ func (s *eventProcessorRPCServer) WithLogger() error {
 
 type EventProcessorRPC struct {
 	client *rpc.Client
+	// set if the plugin could not be started
+	err    error
 	logger *log.Logger
 }
 
+// Unavailable returns a processor plugin client which fails to initialize with err.
+// It is used when the plugin process could not be started.
+func Unavailable(err error) *EventProcessorRPC {
+	return &EventProcessorRPC{err: err}
+}
+
 func (g *EventProcessorRPC) Init(cfg interface{}, opts ...formatters.Option) error {
+	if g.err != nil {
+		return g.err
+	}
 	for _, opt := range opts {
 		opt(g)
 	}
@@ -35,7 +35,7 @@ type rateLimit struct {
 	// formatters.EventProcessor
 
 	PerSecondLimit float64 `mapstructure:"per-second,omitempty" json:"per-second,omitempty"`
-	CacheSize      int     `mapstructure:"cache-size,omitempty" json:"cache-size,omitempty"`
+	CacheSize      int     `mapstructure:"cache-size,omitempty" json:"cache-size,omitempty" default:"1000"`
 	Debug          bool    `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 
 	// eventIndex is an lru cache used to compare the events hash with known value.
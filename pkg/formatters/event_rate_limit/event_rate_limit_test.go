@@ -3,6 +3,7 @@ package event_rate_limit
 import (
 	"testing"
 
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
@@ -361,3 +362,13 @@ func TestRateLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"per-second": 1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p, "per-second") {
+		t.Error(m)
+	}
+}
@@ -0,0 +1,303 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType      = "event-redact"
+	loggingPrefix      = "[" + processorType + "] "
+	defaultReplacement = "[REDACTED]"
+	// name of the regular expression group replaced
+	// when a pattern matches, the whole match is replaced if absent.
+	secretGroupName = "secret"
+)
+
+// redact replaces the values and tags matching a set of secret rules
+// with a fixed replacement string.
+type redact struct {
+	BuiltinRules        []string `mapstructure:"builtin-rules,omitempty" json:"builtin-rules,omitempty"`
+	DisableBuiltinRules bool     `mapstructure:"disable-builtin-rules,omitempty" json:"disable-builtin-rules,omitempty"`
+	Rules               []*rule  `mapstructure:"rules,omitempty" json:"rules,omitempty"`
+	Replacement         string   `mapstructure:"replacement,omitempty" json:"replacement,omitempty" default:"[REDACTED]"`
+	Debug               bool     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	rules  []*compiledRule
+	logger *log.Logger
+}
+
+// rule defines which values and tags are secrets.
+type rule struct {
+	Name string `mapstructure:"name,omitempty" json:"name,omitempty"`
+	// regexes matched against the value names, the whole value is replaced.
+	ValueNames []string `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
+	// regexes matched against the tag names, the whole tag value is replaced.
+	TagNames []string `mapstructure:"tag-names,omitempty" json:"tag-names,omitempty"`
+	// regexes matched against the string values and the tag values,
+	// the group named `secret` or the whole match is replaced.
+	Patterns []string `mapstructure:"patterns,omitempty" json:"patterns,omitempty"`
+}
+
+type compiledRule struct {
+	name       string
+	valueNames []*regexp.Regexp
+	tagNames   []*regexp.Regexp
+	patterns   []*regexp.Regexp
+}
+
+// builtinRules are the rules applied unless disable-builtin-rules is set.
+var builtinRules = map[string]*rule{
+	"password": {
+		ValueNames: []string{
+			`(?i)(^|[/:])(password|passwd|password-hashed|hashed-password|encrypted-password|auth-password|priv-password|secret|shared-secret|secret-key)$`,
+		},
+	},
+	"snmp-community": {
+		ValueNames: []string{
+			`(?i)(^|[/:])(community|community-name|community-string|read-community|write-community)$`,
+			`(?i)/communit(y|ies)/community/(config|state)/name$`,
+		},
+	},
+	"pre-shared-key": {
+		ValueNames: []string{
+			`(?i)(^|[/:])(pre-shared-key|psk|shared-key|auth-key|authentication-key|md5-key|key-string|tcp-ao-key|cak)$`,
+		},
+	},
+	"private-key": {
+		Patterns: []string{
+			`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+		},
+	},
+	"cli-secrets": {
+		Patterns: []string{
+			`(?i)\b(password|secret|community|key-string|pre-shared-key|authentication-key)\s+([0-9]\s+)?(?P<secret>[^\s"]+)`,
+		},
+	},
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &redact{
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (r *redact) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, r)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.Replacement == "" {
+		r.Replacement = defaultReplacement
+	}
+	if !r.DisableBuiltinRules {
+		names := r.BuiltinRules
+		if len(names) == 0 {
+			names = builtinRuleNames()
+		}
+		for _, name := range names {
+			br, ok := builtinRules[name]
+			if !ok {
+				return fmt.Errorf("unknown builtin rule %q", name)
+			}
+			cr, err := compileRule(name, br)
+			if err != nil {
+				return err
+			}
+			r.rules = append(r.rules, cr)
+		}
+	}
+	for i, ru := range r.Rules {
+		name := ru.Name
+		if name == "" {
+			name = fmt.Sprintf("rule%d", i)
+		}
+		cr, err := compileRule(name, ru)
+		if err != nil {
+			return err
+		}
+		r.rules = append(r.rules, cr)
+	}
+	if len(r.rules) == 0 {
+		return fmt.Errorf("no redaction rules configured")
+	}
+	if r.logger.Writer() != io.Discard {
+		b, err := json.Marshal(r)
+		if err != nil {
+			r.logger.Printf("initialized processor '%s': %+v", processorType, r)
+			return nil
+		}
+		r.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func builtinRuleNames() []string {
+	return []string{"password", "snmp-community", "pre-shared-key", "private-key", "cli-secrets"}
+}
+
+func compileRule(name string, ru *rule) (*compiledRule, error) {
+	cr := &compiledRule{name: name}
+	var err error
+	cr.valueNames, err = compileRegexes(ru.ValueNames)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %v", name, err)
+	}
+	cr.tagNames, err = compileRegexes(ru.TagNames)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %v", name, err)
+	}
+	cr.patterns, err = compileRegexes(ru.Patterns)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %v", name, err)
+	}
+	return cr, nil
+}
+
+func compileRegexes(exprs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func (r *redact) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		for k, v := range e.Values {
+			if r.matchValueName(k) {
+				r.logger.Printf("value %q redacted", k)
+				e.Values[k] = r.Replacement
+				continue
+			}
+			if vs, ok := v.(string); ok {
+				if nv, ok := r.redactString(vs); ok {
+					r.logger.Printf("value %q redacted", k)
+					e.Values[k] = nv
+				}
+			}
+		}
+		for k, v := range e.Tags {
+			if r.matchTagName(k) {
+				r.logger.Printf("tag %q redacted", k)
+				e.Tags[k] = r.Replacement
+				continue
+			}
+			if nv, ok := r.redactString(v); ok {
+				r.logger.Printf("tag %q redacted", k)
+				e.Tags[k] = nv
+			}
+		}
+	}
+	return es
+}
+
+func (r *redact) matchValueName(name string) bool {
+	for _, cr := range r.rules {
+		for _, re := range cr.valueNames {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *redact) matchTagName(name string) bool {
+	for _, cr := range r.rules {
+		for _, re := range cr.tagNames {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactString replaces the secrets found in s,
+// it returns false if s does not contain any.
+func (r *redact) redactString(s string) (string, bool) {
+	var redacted bool
+	for _, cr := range r.rules {
+		for _, re := range cr.patterns {
+			ns, ok := replaceSecrets(re, s, r.Replacement)
+			if ok {
+				s = ns
+				redacted = true
+			}
+		}
+	}
+	return s, redacted
+}
+
+// replaceSecrets replaces the matches of re in s with repl.
+// If re has a group named secret, only that group is replaced.
+func replaceSecrets(re *regexp.Regexp, s, repl string) (string, bool) {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, false
+	}
+	gi := re.SubexpIndex(secretGroupName)
+	sb := new(strings.Builder)
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if gi > 0 {
+			start, end = m[2*gi], m[2*gi+1]
+			if start < 0 {
+				continue
+			}
+		}
+		sb.WriteString(s[last:start])
+		sb.WriteString(repl)
+		last = end
+	}
+	if last == 0 {
+		return s, false
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), true
+}
+
+func (r *redact) WithLogger(l *log.Logger) {
+	if r.Debug && l != nil {
+		r.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if r.Debug {
+		r.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (r *redact) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (r *redact) WithActions(act map[string]map[string]interface{}) {}
+
+func (r *redact) WithProcessors(procs map[string]map[string]any) {}
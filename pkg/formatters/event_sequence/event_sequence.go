@@ -0,0 +1,107 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_sequence
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType    = "event-sequence"
+	loggingPrefix    = "[" + processorType + "] "
+	defaultSourceTag = "source"
+	defaultTagName   = "sequence-number"
+)
+
+// sequence adds a per source monotonically increasing
+// sequence number to the events as a tag.
+type sequence struct {
+	SourceTag string `mapstructure:"source-tag,omitempty" json:"source-tag,omitempty" default:"source"`
+	TagName   string `mapstructure:"tag-name,omitempty" json:"tag-name,omitempty" default:"sequence-number"`
+	Debug     bool   `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	m      *sync.Mutex
+	last   map[string]uint64
+	logger *log.Logger
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &sequence{
+			m:      new(sync.Mutex),
+			last:   make(map[string]uint64),
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *sequence) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.SourceTag == "" {
+		p.SourceTag = defaultSourceTag
+	}
+	if p.TagName == "" {
+		p.TagName = defaultTagName
+	}
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *sequence) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+		source := e.Tags[p.SourceTag]
+		p.last[source]++
+		e.Tags[p.TagName] = strconv.FormatUint(p.last[source], 10)
+	}
+	return es
+}
+
+func (p *sequence) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *sequence) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *sequence) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *sequence) WithProcessors(procs map[string]map[string]any) {}
@@ -0,0 +1,50 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_sequence
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestSequence(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatalf("failed to initialize processor: %v", err)
+	}
+	es := []*formatters.EventMsg{
+		{Name: "sub1", Tags: map[string]string{"source": "router1"}},
+		{Name: "sub1", Tags: map[string]string{"source": "router2"}},
+		{Name: "sub1", Tags: map[string]string{"source": "router1"}},
+		{Name: "sub1"},
+	}
+	res := p.Apply(es...)
+	res = append(res, p.Apply(&formatters.EventMsg{Name: "sub1", Tags: map[string]string{"source": "router1"}})...)
+	want := []string{"1", "1", "2", "1", "3"}
+	if len(res) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(res))
+	}
+	for i, e := range res {
+		if e.Tags[defaultTagName] != want[i] {
+			t.Errorf("event %d: expected sequence number %s, got %q", i, want[i], e.Tags[defaultTagName])
+		}
+	}
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
@@ -0,0 +1,218 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_sequence_gap
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType    = "event-sequence-gap"
+	loggingPrefix    = "[" + processorType + "] "
+	defaultSourceTag = "source"
+	defaultTagName   = "sequence-number"
+	defaultEventName = "sequence-gap"
+)
+
+// sequenceGap checks the sequence numbers added to the events
+// by the event-sequence processor and emits an event
+// for each gap found in the sequence of a source.
+type sequenceGap struct {
+	SourceTag     string `mapstructure:"source-tag,omitempty" json:"source-tag,omitempty" default:"source"`
+	TagName       string `mapstructure:"tag-name,omitempty" json:"tag-name,omitempty" default:"sequence-number"`
+	ReorderWindow int    `mapstructure:"reorder-window,omitempty" json:"reorder-window,omitempty"`
+	EventName     string `mapstructure:"event-name,omitempty" json:"event-name,omitempty" default:"sequence-gap"`
+	KeepTag       bool   `mapstructure:"keep-tag,omitempty" json:"keep-tag,omitempty"`
+	Debug         bool   `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	m       *sync.Mutex
+	sources map[string]*sourceState
+	logger  *log.Logger
+}
+
+// sourceState is the sequence state of a single source.
+type sourceState struct {
+	// next expected sequence number.
+	next uint64
+	// sequence numbers received ahead of next,
+	// waiting for the missing ones to arrive out of order.
+	ahead map[uint64]struct{}
+}
+
+// gap is a range of missing sequence numbers.
+type gap struct {
+	first uint64
+	last  uint64
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &sequenceGap{
+			m:       new(sync.Mutex),
+			sources: make(map[string]*sourceState),
+			logger:  log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *sequenceGap) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.SourceTag == "" {
+		p.SourceTag = defaultSourceTag
+	}
+	if p.TagName == "" {
+		p.TagName = defaultTagName
+	}
+	if p.EventName == "" {
+		p.EventName = defaultEventName
+	}
+	if p.ReorderWindow < 0 {
+		p.ReorderWindow = 0
+	}
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *sequenceGap) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	p.m.Lock()
+	defer p.m.Unlock()
+	res := make([]*formatters.EventMsg, 0, len(es))
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		res = append(res, e)
+		v, ok := e.Tags[p.TagName]
+		if !ok {
+			continue
+		}
+		if !p.KeepTag {
+			delete(e.Tags, p.TagName)
+		}
+		seq, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			p.logger.Printf("invalid sequence number %q: %v", v, err)
+			continue
+		}
+		source := e.Tags[p.SourceTag]
+		for _, g := range p.check(source, seq) {
+			p.logger.Printf("source %q: missing sequence numbers %d to %d", source, g.first, g.last)
+			res = append(res, p.gapEvent(source, g, e.Timestamp))
+		}
+	}
+	return res
+}
+
+// check records the sequence number seq received from source
+// and returns the gaps it reveals.
+func (p *sequenceGap) check(source string, seq uint64) []gap {
+	st, ok := p.sources[source]
+	switch {
+	case !ok:
+		// first sequence number of the source, the previous ones
+		// were sent before the processor started.
+		p.sources[source] = &sourceState{next: seq + 1}
+		return nil
+	case seq == 1 && st.next > 1:
+		// the source sequence restarted
+		p.logger.Printf("source %q: sequence restarted", source)
+		p.sources[source] = &sourceState{next: seq + 1}
+		return nil
+	case seq < st.next:
+		// duplicate, or late event already reported missing
+		p.logger.Printf("source %q: late or duplicate sequence number %d", source, seq)
+		return nil
+	case seq == st.next:
+		st.next++
+		st.advance()
+		return nil
+	}
+	if st.ahead == nil {
+		st.ahead = make(map[uint64]struct{})
+	}
+	st.ahead[seq] = struct{}{}
+	var gaps []gap
+	for len(st.ahead) > p.ReorderWindow {
+		lowest := st.lowest()
+		gaps = append(gaps, gap{first: st.next, last: lowest - 1})
+		st.next = lowest
+		st.advance()
+	}
+	return gaps
+}
+
+// advance moves next past the sequence numbers already received ahead.
+func (st *sourceState) advance() {
+	for {
+		if _, ok := st.ahead[st.next]; !ok {
+			return
+		}
+		delete(st.ahead, st.next)
+		st.next++
+	}
+}
+
+func (st *sourceState) lowest() uint64 {
+	seqs := make([]uint64, 0, len(st.ahead))
+	for s := range st.ahead {
+		seqs = append(seqs, s)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs[0]
+}
+
+func (p *sequenceGap) gapEvent(source string, g gap, ts int64) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      p.EventName,
+		Timestamp: ts,
+		Tags:      map[string]string{p.SourceTag: source},
+		Values: map[string]interface{}{
+			"missing": g.last - g.first + 1,
+			"first":   g.first,
+			"last":    g.last,
+		},
+	}
+}
+
+func (p *sequenceGap) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *sequenceGap) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *sequenceGap) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *sequenceGap) WithProcessors(procs map[string]map[string]any) {}
@@ -0,0 +1,139 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_sequence_gap
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func event(source string, seq uint64) *formatters.EventMsg {
+	return &formatters.EventMsg{
+		Name:      "sub1",
+		Timestamp: int64(seq),
+		Tags:      map[string]string{"source": source, "sequence-number": strconv.FormatUint(seq, 10)},
+		Values:    map[string]interface{}{"value": seq},
+	}
+}
+
+func TestSequenceGap(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    map[string]interface{}
+		seqs   []uint64
+		output []gap
+	}{
+		{
+			name:   "in_order",
+			seqs:   []uint64{1, 2, 3, 4},
+			output: nil,
+		},
+		{
+			name:   "starts_mid_sequence",
+			seqs:   []uint64{10, 11, 12},
+			output: nil,
+		},
+		{
+			name:   "gaps",
+			seqs:   []uint64{1, 2, 5, 6, 9},
+			output: []gap{{first: 3, last: 4}, {first: 7, last: 8}},
+		},
+		{
+			name:   "out_of_order_without_window",
+			seqs:   []uint64{1, 3, 2, 4},
+			output: []gap{{first: 2, last: 2}},
+		},
+		{
+			name:   "out_of_order_within_window",
+			cfg:    map[string]interface{}{"reorder-window": 2},
+			seqs:   []uint64{1, 3, 4, 2, 5},
+			output: nil,
+		},
+		{
+			name:   "gap_beyond_window",
+			cfg:    map[string]interface{}{"reorder-window": 2},
+			seqs:   []uint64{1, 3, 4, 5, 6},
+			output: []gap{{first: 2, last: 2}},
+		},
+		{
+			name:   "restart",
+			seqs:   []uint64{1, 2, 3, 1, 2},
+			output: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if cfg == nil {
+				cfg = map[string]interface{}{}
+			}
+			p := formatters.EventProcessors[processorType]()
+			if err := p.Init(cfg); err != nil {
+				t.Fatalf("failed to initialize processor: %v", err)
+			}
+			var gaps []gap
+			for _, seq := range tt.seqs {
+				res := p.Apply(event("router1", seq))
+				if _, ok := res[0].Tags[defaultTagName]; ok {
+					t.Errorf("sequence number tag was not removed")
+				}
+				for _, e := range res[1:] {
+					if e.Name != defaultEventName || e.Tags["source"] != "router1" {
+						t.Errorf("unexpected gap event: %+v", e)
+					}
+					if e.Values["missing"] != e.Values["last"].(uint64)-e.Values["first"].(uint64)+1 {
+						t.Errorf("unexpected missing count: %+v", e)
+					}
+					gaps = append(gaps, gap{first: e.Values["first"].(uint64), last: e.Values["last"].(uint64)})
+				}
+			}
+			if !reflect.DeepEqual(gaps, tt.output) {
+				t.Errorf("unexpected gaps: got %+v, want %+v", gaps, tt.output)
+			}
+		})
+	}
+}
+
+func TestSequenceGapPerSource(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"keep-tag": true}); err != nil {
+		t.Fatalf("failed to initialize processor: %v", err)
+	}
+	res := p.Apply(
+		event("router1", 1),
+		event("router2", 1),
+		event("router1", 2),
+		event("router2", 3),
+		&formatters.EventMsg{Name: "sub1", Tags: map[string]string{"source": "router3"}},
+	)
+	if len(res) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(res))
+	}
+	if res[0].Tags[defaultTagName] != "1" {
+		t.Errorf("sequence number tag was removed")
+	}
+	g := res[4]
+	if g.Name != defaultEventName || g.Tags["source"] != "router2" || g.Values["first"] != uint64(2) {
+		t.Errorf("unexpected gap event: %+v", g)
+	}
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
@@ -0,0 +1,189 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_time
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType = "event-time"
+	loggingPrefix = "[" + processorType + "] "
+)
+
+const (
+	methodNearest = "nearest"
+	methodFloor   = "floor"
+	methodCeil    = "ceil"
+
+	outOfBoundsDrop = "drop"
+	outOfBoundsNow  = "now"
+)
+
+// eventTime aligns the events timestamps to fixed boundaries
+// and drops or fixes the events with timestamps too far in the past or in the future.
+type eventTime struct {
+	Round       time.Duration `mapstructure:"round,omitempty" json:"round,omitempty"`
+	Method      string        `mapstructure:"method,omitempty" json:"method,omitempty" default:"nearest"`
+	MaxPast     time.Duration `mapstructure:"max-past,omitempty" json:"max-past,omitempty"`
+	MaxFuture   time.Duration `mapstructure:"max-future,omitempty" json:"max-future,omitempty"`
+	OutOfBounds string        `mapstructure:"out-of-bounds,omitempty" json:"out-of-bounds,omitempty" default:"drop"`
+	Precision   string        `mapstructure:"precision,omitempty" json:"precision,omitempty" default:"ns"`
+	Debug       bool          `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	// duration of one timestamp unit
+	unit   time.Duration
+	now    func() time.Time
+	logger *log.Logger
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &eventTime{
+			now:    time.Now,
+			logger: log.New(io.Discard, "", 0),
+		}
+	})
+}
+
+func (p *eventTime) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.Round < 0 || p.MaxPast < 0 || p.MaxFuture < 0 {
+		return errors.New("round, max-past and max-future must be positive")
+	}
+	if p.Round == 0 && p.MaxPast == 0 && p.MaxFuture == 0 {
+		return errors.New("one of round, max-past or max-future must be set")
+	}
+	switch p.Method {
+	case "":
+		p.Method = methodNearest
+	case methodNearest, methodFloor, methodCeil:
+	default:
+		return fmt.Errorf("unknown method %q, must be one of %q, %q or %q", p.Method, methodNearest, methodFloor, methodCeil)
+	}
+	switch p.OutOfBounds {
+	case "":
+		p.OutOfBounds = outOfBoundsDrop
+	case outOfBoundsDrop, outOfBoundsNow:
+	default:
+		return fmt.Errorf("unknown out-of-bounds action %q, must be one of %q or %q", p.OutOfBounds, outOfBoundsDrop, outOfBoundsNow)
+	}
+	switch p.Precision {
+	case "", "ns":
+		p.Precision = "ns"
+		p.unit = time.Nanosecond
+	case "us":
+		p.unit = time.Microsecond
+	case "ms":
+		p.unit = time.Millisecond
+	case "s":
+		p.unit = time.Second
+	default:
+		return fmt.Errorf("unknown precision %q", p.Precision)
+	}
+	if p.Round > 0 && p.Round < p.unit {
+		return fmt.Errorf("round %s is smaller than the timestamp precision %s", p.Round, p.Precision)
+	}
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *eventTime) Apply(es ...*formatters.EventMsg) []*formatters.EventMsg {
+	now := p.now().UnixNano() / int64(p.unit)
+	i := 0
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		if p.outOfBounds(e.Timestamp, now) {
+			if p.OutOfBounds == outOfBoundsDrop {
+				p.logger.Printf("dropping event %q with timestamp %d", e.Name, e.Timestamp)
+				continue
+			}
+			p.logger.Printf("overriding timestamp %d of event %q", e.Timestamp, e.Name)
+			e.Timestamp = now
+		}
+		e.Timestamp = p.round(e.Timestamp)
+		es[i] = e
+		i++
+	}
+	for j := i; j < len(es); j++ {
+		es[j] = nil
+	}
+	return es[:i]
+}
+
+func (p *eventTime) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *eventTime) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *eventTime) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *eventTime) WithProcessors(procs map[string]map[string]any) {}
+
+func (p *eventTime) outOfBounds(ts, now int64) bool {
+	if p.MaxPast > 0 && ts < now-int64(p.MaxPast/p.unit) {
+		return true
+	}
+	return p.MaxFuture > 0 && ts > now+int64(p.MaxFuture/p.unit)
+}
+
+func (p *eventTime) round(ts int64) int64 {
+	r := int64(p.Round / p.unit)
+	if r <= 1 {
+		return ts
+	}
+	floor := ts - ts%r
+	if ts%r < 0 {
+		floor -= r
+	}
+	switch p.Method {
+	case methodFloor:
+		return floor
+	case methodCeil:
+		if floor == ts {
+			return ts
+		}
+		return floor + r
+	default:
+		if ts-floor >= r-(ts-floor) {
+			return floor + r
+		}
+		return floor
+	}
+}
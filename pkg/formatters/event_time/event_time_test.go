@@ -0,0 +1,184 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_time
+
+import (
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type item struct {
+	input  []*formatters.EventMsg
+	output []*formatters.EventMsg
+}
+
+var now = time.Unix(1700000000, 0)
+
+var testset = map[string]struct {
+	processor map[string]interface{}
+	tests     []item
+}{
+	"round_nearest": {
+		processor: map[string]interface{}{
+			"round": "10s",
+		},
+		tests: []item{
+			{
+				input:  nil,
+				output: nil,
+			},
+			{
+				input: []*formatters.EventMsg{
+					{Timestamp: 1700000004_999999999},
+					{Timestamp: 1700000005_000000000},
+					{Timestamp: 1700000010_000000000},
+				},
+				output: []*formatters.EventMsg{
+					{Timestamp: 1700000000_000000000},
+					{Timestamp: 1700000010_000000000},
+					{Timestamp: 1700000010_000000000},
+				},
+			},
+		},
+	},
+	"round_floor_ms": {
+		processor: map[string]interface{}{
+			"round":     "1m",
+			"method":    "floor",
+			"precision": "ms",
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{Timestamp: 1700000059_999},
+					{Timestamp: 1700000040_000},
+				},
+				output: []*formatters.EventMsg{
+					{Timestamp: 1700000040_000},
+					{Timestamp: 1700000040_000},
+				},
+			},
+		},
+	},
+	"round_ceil": {
+		processor: map[string]interface{}{
+			"round":  "5s",
+			"method": "ceil",
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{Timestamp: 1700000000_000000001},
+					{Timestamp: 1700000005_000000000},
+				},
+				output: []*formatters.EventMsg{
+					{Timestamp: 1700000005_000000000},
+					{Timestamp: 1700000005_000000000},
+				},
+			},
+		},
+	},
+	"guardrails_drop": {
+		processor: map[string]interface{}{
+			"max-past":   "1h",
+			"max-future": "1m",
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{Name: "old", Timestamp: now.Add(-2 * time.Hour).UnixNano()},
+					{Name: "ok", Timestamp: now.Add(-time.Minute).UnixNano()},
+					nil,
+					{Name: "zero", Timestamp: 0},
+					{Name: "future", Timestamp: now.Add(time.Hour).UnixNano()},
+				},
+				output: []*formatters.EventMsg{
+					{Name: "ok", Timestamp: now.Add(-time.Minute).UnixNano()},
+				},
+			},
+		},
+	},
+	"guardrails_now": {
+		processor: map[string]interface{}{
+			"max-future":    "1m",
+			"out-of-bounds": "now",
+			"round":         "1m",
+			"precision":     "s",
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{Name: "future", Timestamp: now.Add(24 * time.Hour).Unix()},
+					{Name: "past", Timestamp: 0},
+				},
+				output: []*formatters.EventMsg{
+					{Name: "future", Timestamp: now.Round(time.Minute).Unix()},
+					{Name: "past", Timestamp: 0},
+				},
+			},
+		},
+	},
+}
+
+func TestEventTime(t *testing.T) {
+	for name, ts := range testset {
+		t.Run(name, func(t *testing.T) {
+			p := &eventTime{
+				now:    func() time.Time { return now },
+				logger: log.New(io.Discard, "", 0),
+			}
+			err := p.Init(ts.processor)
+			if err != nil {
+				t.Fatalf("failed to initialize processor: %v", err)
+			}
+			for i, item := range ts.tests {
+				outs := p.Apply(item.input...)
+				if !reflect.DeepEqual(outs, item.output) {
+					t.Errorf("failed at %q item %d", name, i)
+					t.Logf("expected: %+v", item.output)
+					t.Logf("     got: %+v", outs)
+				}
+			}
+		})
+	}
+}
+
+func TestEventTimeInit(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"nothing_set":    {},
+		"bad_method":     {"round": "10s", "method": "up"},
+		"bad_action":     {"max-past": "1h", "out-of-bounds": "keep"},
+		"bad_precision":  {"round": "10s", "precision": "m"},
+		"round_too_fine": {"round": "10ms", "precision": "s"},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := formatters.EventProcessors[processorType]()
+			if err := p.Init(cfg); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{"round": "1s"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p, "round") {
+		t.Error(m)
+	}
+}
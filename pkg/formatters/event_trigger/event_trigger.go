@@ -17,6 +17,8 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -29,31 +31,56 @@ import (
 	"github.com/openconfig/gnmic/pkg/api/utils"
 	gfile "github.com/openconfig/gnmic/pkg/file"
 	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
 )
 
 const (
 	processorType    = "event-trigger"
 	loggingPrefix    = "[" + processorType + "] "
 	defaultCondition = "any([true])"
+
+	defaultApprovalTimeout = time.Hour
+)
+
+// trigger modes
+const (
+	// the actions are run when the trigger fires
+	modeRun = "run"
+	// the actions are not run, the executions are only recorded
+	modeDryRun = "dry-run"
+	// the actions are run once approved using the API
+	modeApproval = "approval"
 )
 
 // trigger triggers an action when certain conditions are met
 type trigger struct {
-	Condition      string                 `mapstructure:"condition,omitempty"`
-	MinOccurrences int                    `mapstructure:"min-occurrences,omitempty"`
-	MaxOccurrences int                    `mapstructure:"max-occurrences,omitempty"`
-	Window         time.Duration          `mapstructure:"window,omitempty"`
+	Name           string                 `mapstructure:"name,omitempty"`
+	Condition      string                 `mapstructure:"condition,omitempty" default:"any([true])"`
+	MinOccurrences int                    `mapstructure:"min-occurrences,omitempty" default:"1"`
+	MaxOccurrences int                    `mapstructure:"max-occurrences,omitempty" default:"1"`
+	Window         time.Duration          `mapstructure:"window,omitempty" default:"1m"`
 	Actions        []string               `mapstructure:"actions,omitempty"`
 	Vars           map[string]interface{} `mapstructure:"vars,omitempty"`
 	VarsFile       string                 `mapstructure:"vars-file,omitempty"`
 	Debug          bool                   `mapstructure:"debug,omitempty"`
 	Async          bool                   `mapstructure:"async,omitempty"`
+	// Go template executed with the event, the executions with the same key
+	// are not run during the cooldown period nor while one of them is pending approval.
+	DedupKey        string        `mapstructure:"dedup-key,omitempty"`
+	Cooldown        time.Duration `mapstructure:"cooldown,omitempty"`
+	Mode            string        `mapstructure:"mode,omitempty" default:"run"`
+	ApprovalTimeout time.Duration `mapstructure:"approval-timeout,omitempty" default:"1h"`
+	// file the executions are appended to, in JSON lines format
+	AuditFile string `mapstructure:"audit-file,omitempty"`
 
 	occurrencesTimes []time.Time
 	lastTrigger      time.Time
 	code             *gojq.Code
 	actions          []actions.Action
 	vars             map[string]interface{}
+	dedupKey         *template.Template
+	auditMu          *sync.Mutex
+	auditFile        *os.File
 
 	targets map[string]*types.TargetConfig
 	acts    map[string]map[string]interface{}
@@ -106,6 +133,19 @@ func (p *trigger) Init(cfg interface{}, opts ...formatters.Option) error {
 	if err != nil {
 		return err
 	}
+	if p.DedupKey != "" {
+		p.dedupKey, err = gtemplate.CreateTemplate("dedup-key", p.DedupKey)
+		if err != nil {
+			return err
+		}
+	}
+	if p.AuditFile != "" {
+		p.auditFile, err = os.OpenFile(p.AuditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		p.auditMu = new(sync.Mutex)
+	}
 
 	p.logger.Printf("%q initialized: %+v", processorType, p)
 
@@ -209,6 +249,22 @@ func (p *trigger) setDefaults() error {
 	if p.Window <= 0 {
 		p.Window = time.Minute
 	}
+	if p.Name == "" {
+		p.Name = strings.Join(p.Actions, ",")
+	}
+	if p.Cooldown < 0 {
+		return errors.New("cooldown cannot be negative")
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = modeRun
+	case modeRun, modeDryRun, modeApproval:
+	default:
+		return fmt.Errorf("unknown mode %q", p.Mode)
+	}
+	if p.ApprovalTimeout <= 0 {
+		p.ApprovalTimeout = defaultApprovalTimeout
+	}
 	return nil
 }
 
@@ -231,16 +287,104 @@ func (p *trigger) readVars() error {
 }
 
 func (p *trigger) triggerActions(e *formatters.EventMsg) {
+	now := time.Now()
 	actx := &actions.Context{Input: e, Env: make(map[string]interface{}), Vars: p.vars}
+	key, err := p.executionKey(actx)
+	if err != nil {
+		p.logger.Printf("failed to execute dedup-key template: %v", err)
+		return
+	}
+	ex := &actions.Execution{
+		Trigger: p.Name,
+		Key:     key,
+		Actions: p.Actions,
+		Input:   copyEvent(e),
+		Created: now,
+	}
+	if !actions.Claim(p.Name, key, p.Cooldown, now) {
+		p.logger.Printf("trigger %q: actions suppressed for key %q", p.Name, key)
+		ex.Status = actions.ExecutionSuppressed
+		actions.Record(ex, nil, p.audit)
+		return
+	}
+	switch p.Mode {
+	case modeDryRun:
+		p.logger.Printf("trigger %q: dry-run, actions not run for key %q", p.Name, key)
+		ex.Status = actions.ExecutionDryRun
+		actions.Record(ex, nil, p.audit)
+	case modeApproval:
+		expires := now.Add(p.ApprovalTimeout)
+		ex.Status = actions.ExecutionPending
+		ex.Expires = &expires
+		actions.Record(ex, func(ex *actions.Execution) { p.runActions(ex, actx) }, p.audit)
+		p.logger.Printf("trigger %q: execution %s pending approval", p.Name, ex.ID)
+	default:
+		ex.Status = actions.ExecutionRunning
+		actions.Record(ex, nil, p.audit)
+		p.runActions(ex, actx)
+	}
+}
+
+func (p *trigger) runActions(ex *actions.Execution, actx *actions.Context) {
 	for _, act := range p.actions {
 		res, err := act.Run(context.TODO(), actx)
 		if err != nil {
 			p.logger.Printf("trigger action %q failed: %+v", act.NName(), err)
+			actions.Complete(ex, actx.Env, fmt.Errorf("action %q failed: %v", act.NName(), err))
 			return
 		}
 		actx.Env[act.NName()] = res
 		p.logger.Printf("action %q result: %+v", act.NName(), res)
 	}
+	actions.Complete(ex, actx.Env, nil)
+}
+
+func (p *trigger) executionKey(actx *actions.Context) (string, error) {
+	if p.dedupKey == nil {
+		return "", nil
+	}
+	b := new(strings.Builder)
+	err := p.dedupKey.Execute(b, actx)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// audit appends execution ex to the audit file.
+func (p *trigger) audit(ex *actions.Execution) {
+	if p.auditFile == nil {
+		return
+	}
+	b, err := json.Marshal(ex)
+	if err != nil {
+		p.logger.Printf("failed to marshal execution %s: %v", ex.ID, err)
+		return
+	}
+	p.auditMu.Lock()
+	defer p.auditMu.Unlock()
+	_, err = p.auditFile.Write(append(b, '\n'))
+	if err != nil {
+		p.logger.Printf("failed to write execution %s to audit file: %v", ex.ID, err)
+	}
+}
+
+// copyEvent returns a copy of e, not modified by the next processors.
+func copyEvent(e *formatters.EventMsg) *formatters.EventMsg {
+	c := &formatters.EventMsg{
+		Name:      e.Name,
+		Timestamp: e.Timestamp,
+		Tags:      make(map[string]string, len(e.Tags)),
+		Values:    make(map[string]interface{}, len(e.Values)),
+		Deletes:   append([]string(nil), e.Deletes...),
+	}
+	for k, v := range e.Tags {
+		c.Tags[k] = v
+	}
+	for k, v := range e.Values {
+		c.Values[k] = v
+	}
+	return c
 }
 
 func (p *trigger) evalOccurrencesWithinWindow(now time.Time) bool {
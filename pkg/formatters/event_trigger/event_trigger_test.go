@@ -9,12 +9,16 @@
 package event_trigger
 
 import (
+	"encoding/json"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmic/pkg/actions"
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/api/utils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
@@ -366,3 +370,140 @@ func TestOccurrenceTrigger(t *testing.T) {
 		})
 	}
 }
+
+func triggerStatuses(name string) []string {
+	rs := make([]string, 0)
+	for _, ex := range actions.AuditTrail("") {
+		if ex.Trigger == name {
+			rs = append(rs, ex.Status)
+		}
+	}
+	return rs
+}
+
+func TestTriggerExecutions(t *testing.T) {
+	acts := map[string]map[string]interface{}{
+		"port": {
+			"name":     "port",
+			"type":     "template",
+			"template": `{{ index .Input.Tags "interface_name" }}`,
+		},
+	}
+	events := []*formatters.EventMsg{
+		{Name: "sub1", Tags: map[string]string{"interface_name": "ethernet-1/1"}},
+		{Name: "sub1", Tags: map[string]string{"interface_name": "ethernet-1/1"}},
+		{Name: "sub1", Tags: map[string]string{"interface_name": "ethernet-1/2"}},
+	}
+	tests := map[string]struct {
+		cfg  map[string]interface{}
+		want []string
+	}{
+		"run_dedup_cooldown": {
+			cfg: map[string]interface{}{
+				"name":      "run_dedup_cooldown",
+				"dedup-key": `{{ index .Input.Tags "interface_name" }}`,
+				"cooldown":  "1m",
+			},
+			want: []string{actions.ExecutionSucceeded, actions.ExecutionSuppressed, actions.ExecutionSucceeded},
+		},
+		"dry_run": {
+			cfg: map[string]interface{}{
+				"name": "dry_run",
+				"mode": "dry-run",
+			},
+			want: []string{actions.ExecutionDryRun, actions.ExecutionDryRun, actions.ExecutionDryRun},
+		},
+		"approval": {
+			cfg: map[string]interface{}{
+				"name":      "approval",
+				"mode":      "approval",
+				"dedup-key": `{{ index .Input.Tags "interface_name" }}`,
+			},
+			want: []string{actions.ExecutionPending, actions.ExecutionSuppressed, actions.ExecutionPending},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.cfg["actions"] = []string{"port"}
+			tt.cfg["max-occurrences"] = 10
+			p := formatters.EventProcessors[processorType]()
+			err := p.Init(tt.cfg, formatters.WithActions(acts))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range events {
+				p.Apply(e)
+			}
+			if got := triggerStatuses(name); !cmp.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	pending := actions.AuditTrail(actions.ExecutionPending)
+	var id string
+	for _, ex := range pending {
+		if ex.Trigger == "approval" && ex.Key == "ethernet-1/1" {
+			id = ex.ID
+		}
+	}
+	if id == "" {
+		t.Fatal("expected a pending execution")
+	}
+	if _, err := actions.Approve(id, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		ex, err := actions.GetExecution(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ex.Status == actions.ExecutionSucceeded {
+			if ex.Results["port"] != "ethernet-1/1" || ex.User != "alice" {
+				t.Errorf("unexpected execution %+v", ex)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected the approved execution to succeed")
+}
+
+func TestTriggerAuditFile(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "audit.jsonl")
+	p := formatters.EventProcessors[processorType]()
+	err := p.Init(map[string]interface{}{
+		"name":       "audit_file",
+		"mode":       "dry-run",
+		"audit-file": f,
+		"actions":    []string{"port"},
+	}, formatters.WithActions(map[string]map[string]interface{}{
+		"port": {"name": "port", "type": "template"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Apply(&formatters.EventMsg{Name: "sub1"})
+	b, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := new(actions.Execution)
+	if err := json.Unmarshal(b, ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.Trigger != "audit_file" || ex.Status != actions.ExecutionDryRun {
+		t.Errorf("unexpected audit entry %s", b)
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	p := &trigger{}
+	if err := p.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
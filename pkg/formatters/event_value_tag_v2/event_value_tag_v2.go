@@ -0,0 +1,219 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_value_tag_v2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	processorType = "event-value-tag-v2"
+	loggingPrefix = "[" + processorType + "] "
+)
+
+// valueTagV2 extracts multiple tags from a value or a value name using regex named groups,
+// and applies them to the event and to the other events sharing its tags.
+type valueTagV2 struct {
+	ValueNames     []string `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
+	ValueRegex     string   `mapstructure:"value-regex,omitempty" json:"value-regex,omitempty"`
+	ValueNameRegex string   `mapstructure:"value-name-regex,omitempty" json:"value-name-regex,omitempty"`
+	Overwrite      bool     `mapstructure:"overwrite,omitempty" json:"overwrite,omitempty"`
+	Consume        bool     `mapstructure:"consume,omitempty" json:"consume,omitempty"`
+	Debug          bool     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+
+	valueNames     []*regexp.Regexp
+	valueRegex     *regexp.Regexp
+	valueNameRegex *regexp.Regexp
+	logger         *log.Logger
+}
+
+type extracted struct {
+	tags map[string]string
+	// tags of the event the tags were extracted from
+	sourceTags map[string]string
+}
+
+func init() {
+	formatters.Register(processorType, func() formatters.EventProcessor {
+		return &valueTagV2{logger: log.New(io.Discard, "", 0)}
+	})
+}
+
+func (p *valueTagV2) Init(cfg interface{}, opts ...formatters.Option) error {
+	err := formatters.DecodeConfig(cfg, p)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(p.ValueNames) == 0 {
+		return errors.New("missing value-names")
+	}
+	if p.ValueRegex == "" && p.ValueNameRegex == "" {
+		return errors.New("one of value-regex or value-name-regex must be set")
+	}
+	p.valueNames = make([]*regexp.Regexp, 0, len(p.ValueNames))
+	for _, reg := range p.ValueNames {
+		re, err := regexp.Compile(reg)
+		if err != nil {
+			return err
+		}
+		p.valueNames = append(p.valueNames, re)
+	}
+	if p.ValueRegex != "" {
+		p.valueRegex, err = compileWithNamedGroups(p.ValueRegex)
+		if err != nil {
+			return fmt.Errorf("value-regex: %w", err)
+		}
+	}
+	if p.ValueNameRegex != "" {
+		p.valueNameRegex, err = compileWithNamedGroups(p.ValueNameRegex)
+		if err != nil {
+			return fmt.Errorf("value-name-regex: %w", err)
+		}
+	}
+
+	if p.logger.Writer() != io.Discard {
+		b, err := json.Marshal(p)
+		if err != nil {
+			p.logger.Printf("initialized processor '%s': %+v", processorType, p)
+			return nil
+		}
+		p.logger.Printf("initialized processor '%s': %s", processorType, string(b))
+	}
+	return nil
+}
+
+func (p *valueTagV2) Apply(evs ...*formatters.EventMsg) []*formatters.EventMsg {
+	toApply := make([]*extracted, 0)
+	for _, ev := range evs {
+		if ev == nil {
+			continue
+		}
+		for k, v := range ev.Values {
+			if !p.matchValueName(k) {
+				continue
+			}
+			tags := make(map[string]string)
+			if p.valueNameRegex != nil {
+				extractNamedGroups(p.valueNameRegex, k, tags)
+			}
+			if p.valueRegex != nil {
+				extractNamedGroups(p.valueRegex, fmt.Sprint(v), tags)
+			}
+			if len(tags) == 0 {
+				continue
+			}
+			if p.Debug {
+				p.logger.Printf("value %q: extracted tags: %v", k, tags)
+			}
+			toApply = append(toApply, &extracted{tags: tags, sourceTags: copyTags(ev.Tags)})
+			if p.Consume {
+				delete(ev.Values, k)
+			}
+		}
+	}
+	for _, ex := range toApply {
+		for _, ev := range evs {
+			if ev == nil || !checkKeys(ex.sourceTags, ev.Tags) {
+				continue
+			}
+			if ev.Tags == nil {
+				ev.Tags = make(map[string]string)
+			}
+			for tn, tv := range ex.tags {
+				if _, ok := ev.Tags[tn]; ok && !p.Overwrite {
+					continue
+				}
+				ev.Tags[tn] = tv
+			}
+		}
+	}
+	return evs
+}
+
+func (p *valueTagV2) WithLogger(l *log.Logger) {
+	if p.Debug && l != nil {
+		p.logger = log.New(l.Writer(), loggingPrefix, l.Flags())
+	} else if p.Debug {
+		p.logger = log.New(os.Stderr, loggingPrefix, utils.DefaultLoggingFlags)
+	}
+}
+
+func (p *valueTagV2) WithTargets(tcs map[string]*types.TargetConfig) {}
+
+func (p *valueTagV2) WithActions(act map[string]map[string]interface{}) {}
+
+func (p *valueTagV2) WithProcessors(procs map[string]map[string]any) {}
+
+func (p *valueTagV2) matchValueName(name string) bool {
+	for _, re := range p.valueNames {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileWithNamedGroups(s string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return re, nil
+		}
+	}
+	return nil, fmt.Errorf("regex %q has no named groups", s)
+}
+
+// extractNamedGroups adds the named groups of re matching s to tags.
+// Groups that did not participate in the match are ignored.
+func extractNamedGroups(re *regexp.Regexp, s string, tags map[string]string) {
+	idx := re.FindStringSubmatchIndex(s)
+	if idx == nil {
+		return
+	}
+	for i, name := range re.SubexpNames() {
+		if i == 0 || name == "" || idx[2*i] < 0 {
+			continue
+		}
+		tags[name] = s[idx[2*i]:idx[2*i+1]]
+	}
+}
+
+// checkKeys returns true if all the tags in a are present in b with the same value.
+func checkKeys(a map[string]string, b map[string]string) bool {
+	for k, v := range a {
+		if vv, ok := b[k]; !ok || v != vv {
+			return false
+		}
+	}
+	return true
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	r := make(map[string]string, len(tags))
+	for k, v := range tags {
+		r[k] = v
+	}
+	return r
+}
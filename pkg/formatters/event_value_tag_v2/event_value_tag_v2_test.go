@@ -0,0 +1,215 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package event_value_tag_v2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+type item struct {
+	input  []*formatters.EventMsg
+	output []*formatters.EventMsg
+}
+
+var testset = map[string]struct {
+	processorType string
+	processor     map[string]interface{}
+	tests         []item
+}{
+	"value-regex": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"value-names": []string{"/state/name$"},
+			"value-regex": `^Ethernet(?P<slot>\d+)/(?P<port>\d+)(/(?P<breakout>\d+))?$`,
+		},
+		tests: []item{
+			{
+				input:  nil,
+				output: nil,
+			},
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"interface_name": "if1"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/counters/in-octets": 100},
+					},
+					{
+						Timestamp: 2,
+						Tags:      map[string]string{"interface_name": "if1"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "Ethernet1/1/3"},
+					},
+					{
+						Timestamp: 3,
+						Tags:      map[string]string{"interface_name": "if2"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "Ethernet2/4"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"interface_name": "if1", "slot": "1", "port": "1", "breakout": "3"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/counters/in-octets": 100},
+					},
+					{
+						Timestamp: 2,
+						Tags:      map[string]string{"interface_name": "if1", "slot": "1", "port": "1", "breakout": "3"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "Ethernet1/1/3"},
+					},
+					{
+						Timestamp: 3,
+						Tags:      map[string]string{"interface_name": "if2", "slot": "2", "port": "4"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "Ethernet2/4"},
+					},
+				},
+			},
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"interface_name": "if1"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "mgmt0"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"interface_name": "if1"},
+						Values:    map[string]interface{}{"/interfaces/interface/state/name": "mgmt0"},
+					},
+				},
+			},
+		},
+	},
+	"value-name-regex-consume": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"value-names":      []string{"^/queues/"},
+			"value-name-regex": `^/queues/(?P<queue>[^/]+)/(?P<direction>in|out)/`,
+			"consume":          true,
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"source": "r1"},
+						Values:    map[string]interface{}{"/queues/q1/in/drops": 3},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"source": "r1", "queue": "q1", "direction": "in"},
+						Values:    map[string]interface{}{},
+					},
+				},
+			},
+		},
+	},
+	"overwrite": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"value-names": []string{"^name$"},
+			"value-regex": `^(?P<kind>[a-z]+)-(?P<id>\d+)$`,
+			"overwrite":   true,
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"id": "old"},
+						Values:    map[string]interface{}{"name": "lag-10"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"id": "10", "kind": "lag"},
+						Values:    map[string]interface{}{"name": "lag-10"},
+					},
+				},
+			},
+		},
+	},
+	"no-overwrite": {
+		processorType: processorType,
+		processor: map[string]interface{}{
+			"value-names": []string{"^name$"},
+			"value-regex": `^(?P<kind>[a-z]+)-(?P<id>\d+)$`,
+		},
+		tests: []item{
+			{
+				input: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"id": "old"},
+						Values:    map[string]interface{}{"name": "lag-10"},
+					},
+				},
+				output: []*formatters.EventMsg{
+					{
+						Timestamp: 1,
+						Tags:      map[string]string{"id": "old", "kind": "lag"},
+						Values:    map[string]interface{}{"name": "lag-10"},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestEventValueTagV2(t *testing.T) {
+	for name, ts := range testset {
+		if pi, ok := formatters.EventProcessors[ts.processorType]; ok {
+			p := pi()
+			err := p.Init(ts.processor)
+			if err != nil {
+				t.Errorf("failed to initialize processors: %v", err)
+				return
+			}
+			for i, item := range ts.tests {
+				t.Run(name, func(t *testing.T) {
+					outs := p.Apply(item.input...)
+					if len(outs) != len(item.output) {
+						t.Fatalf("failed at %s item %d, expected %d events, got %d", name, i, len(item.output), len(outs))
+					}
+					for j := range outs {
+						if !reflect.DeepEqual(outs[j], item.output[j]) {
+							t.Errorf("failed at %s item %d, index %d, expected %+v, got: %+v", name, i, j, item.output[j], outs[j])
+						}
+					}
+				})
+			}
+		} else {
+			t.Errorf("event processor %s not found", ts.processorType)
+		}
+	}
+}
+
+func TestEventValueTagV2Init(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing-value-names": {"value-regex": `(?P<a>.*)`},
+		"missing-regex":       {"value-names": []string{"foo"}},
+		"no-named-group":      {"value-names": []string{"foo"}, "value-regex": `(.*)`},
+		"invalid-regex":       {"value-names": []string{"foo"}, "value-name-regex": `(?P<a>`},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := formatters.EventProcessors[processorType]()
+			if err := p.Init(cfg); err == nil {
+				t.Errorf("expected an init error")
+			}
+		})
+	}
+}
@@ -34,8 +34,8 @@ type write struct {
 	Values     []string `mapstructure:"values,omitempty" json:"values,omitempty"`
 	TagNames   []string `mapstructure:"tag-names,omitempty" json:"tag-names,omitempty"`
 	ValueNames []string `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
-	Dst        string   `mapstructure:"dst,omitempty" json:"dst,omitempty"`
-	Separator  string   `mapstructure:"separator,omitempty" json:"separator,omitempty"`
+	Dst        string   `mapstructure:"dst,omitempty" json:"dst,omitempty" default:"stdout"`
+	Separator  string   `mapstructure:"separator,omitempty" json:"separator,omitempty" default:"\n"`
 	Indent     string   `mapstructure:"indent,omitempty" json:"indent,omitempty"`
 	Debug      bool     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 
@@ -75,10 +75,9 @@ func (p *write) Init(cfg interface{}, opts ...formatters.Option) error {
 		return err
 	}
 	if p.Separator == "" {
-		p.sep = []byte("\n")
-	} else {
-		p.sep = []byte(p.Separator)
+		p.Separator = "\n"
 	}
+	p.sep = []byte(p.Separator)
 	p.tags = make([]*regexp.Regexp, 0, len(p.Tags))
 	for _, reg := range p.Tags {
 		re, err := regexp.Compile(reg)
@@ -114,8 +113,11 @@ func (p *write) Init(cfg interface{}, opts ...formatters.Option) error {
 		}
 		p.valueNames = append(p.valueNames, re)
 	}
+	if p.Dst == "" {
+		p.Dst = "stdout"
+	}
 	switch p.Dst {
-	case "", "stdout":
+	case "stdout":
 		p.dst = os.Stdout
 	case "stderr":
 		p.dst = os.Stderr
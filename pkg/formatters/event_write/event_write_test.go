@@ -14,6 +14,7 @@ import (
 	"log"
 	"testing"
 
+	"github.com/openconfig/gnmic/pkg/api/testutils"
 	"github.com/openconfig/gnmic/pkg/formatters"
 )
 
@@ -236,3 +237,13 @@ func TestEventWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestInitDefaultsTags(t *testing.T) {
+	p := formatters.EventProcessors[processorType]()
+	if err := p.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(p) {
+		t.Error(m)
+	}
+}
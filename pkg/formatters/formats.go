@@ -31,6 +31,36 @@ type MarshalOptions struct {
 	CalculateLatency bool
 }
 
+// responseEvents converts a SubscribeResponse update or a GetResponse to events,
+// it returns no events for the other SubscribeResponse types.
+func responseEvents(msg proto.Message, meta map[string]string, eps ...EventProcessor) ([]*EventMsg, error) {
+	switch msg := msg.ProtoReflect().Interface().(type) {
+	case *gnmi.SubscribeResponse:
+		var subscriptionName string
+		var ok bool
+		if subscriptionName, ok = meta["subscription-name"]; !ok {
+			subscriptionName = "default"
+		}
+		switch msg.GetResponse().(type) {
+		case *gnmi.SubscribeResponse_Update:
+			events, err := ResponseToEventMsgs(subscriptionName, msg, meta, eps...)
+			if err != nil {
+				return nil, fmt.Errorf("failed converting response to events: %v", err)
+			}
+			return events, nil
+		}
+		return nil, nil
+	case *gnmi.GetResponse:
+		events, err := GetResponseToEventMsgs(msg, meta, eps...)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting response to events: %v", err)
+		}
+		return events, nil
+	default:
+		return nil, fmt.Errorf("msg type %T cannot be converted to events", msg)
+	}
+}
+
 // Marshal //
 func (o *MarshalOptions) Marshal(msg proto.Message, meta map[string]string, eps ...EventProcessor) ([]byte, error) {
 	msg = o.OverrideTimestamp(msg)
@@ -89,6 +119,15 @@ func (o *MarshalOptions) Marshal(msg proto.Message, meta map[string]string, eps
 		default:
 			return nil, fmt.Errorf("format 'event' not supported for msg type %T", msg.ProtoReflect().Interface())
 		}
+	case FormatInflux, FormatGraphite:
+		events, err := responseEvents(msg, meta, eps...)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			return nil, nil
+		}
+		return EventsToLines(o.Format, events...)
 	case "flat":
 		flatMsg, err := responseFlat(msg)
 		if err != nil {
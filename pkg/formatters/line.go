@@ -0,0 +1,224 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatInflux   = "influx"
+	FormatGraphite = "graphite"
+)
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	influxKeyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+	influxStringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	graphiteTagEscaper       = strings.NewReplacer(";", "_", "~", "_", " ", "_", "\n", "_")
+)
+
+// IsLineFormat returns true if format is one of the
+// line oriented formats built from event messages.
+func IsLineFormat(format string) bool {
+	return format == FormatInflux || format == FormatGraphite
+}
+
+// EventsToLines formats the events as Influx line protocol or Graphite
+// plaintext lines, separated by a new line.
+func EventsToLines(format string, evs ...*EventMsg) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for _, ev := range evs {
+		var err error
+		switch format {
+		case FormatInflux:
+			err = writeInfluxLine(buf, ev)
+		case FormatGraphite:
+			writeGraphiteLines(buf, ev)
+		default:
+			return nil, fmt.Errorf("unknown line format %q", format)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// writeInfluxLine writes the event ev as a single Influx line protocol point:
+// the event name is the measurement, the event values are the fields.
+// Events without values are skipped.
+func writeInfluxLine(buf *bytes.Buffer, ev *EventMsg) error {
+	if len(ev.Values) == 0 {
+		return nil
+	}
+	buf.WriteString(influxMeasurementEscaper.Replace(ev.Name))
+	for _, k := range sortedKeys(ev.Tags) {
+		if ev.Tags[k] == "" {
+			continue
+		}
+		buf.WriteByte(',')
+		buf.WriteString(influxKeyEscaper.Replace(k))
+		buf.WriteByte('=')
+		buf.WriteString(influxKeyEscaper.Replace(ev.Tags[k]))
+	}
+	sep := byte(' ')
+	for _, k := range sortedKeys(ev.Values) {
+		v, err := influxFieldValue(ev.Values[k])
+		if err != nil {
+			return fmt.Errorf("field %q: %v", k, err)
+		}
+		buf.WriteByte(sep)
+		sep = ','
+		buf.WriteString(influxKeyEscaper.Replace(k))
+		buf.WriteByte('=')
+		buf.WriteString(v)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(ev.Timestamp, 10))
+	buf.WriteByte('\n')
+	return nil
+}
+
+func influxFieldValue(v any) (string, error) {
+	switch v := v.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10) + "i", nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10) + "i", nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10) + "i", nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10) + "i", nil
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10) + "u", nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10) + "u", nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10) + "u", nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10) + "u", nil
+	case uint64:
+		return strconv.FormatUint(v, 10) + "u", nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return `"` + influxStringEscaper.Replace(v) + `"`, nil
+	default:
+		// leaf-lists and other composite values are written as JSON strings
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return `"` + influxStringEscaper.Replace(string(b)) + `"`, nil
+	}
+}
+
+// writeGraphiteLines writes a Graphite plaintext line per numeric value of ev.
+// The metric path is the event name followed by the value name with its
+// `/` replaced by `.`, the event tags are appended using the Graphite
+// tags syntax. Non numeric values are skipped.
+func writeGraphiteLines(buf *bytes.Buffer, ev *EventMsg) {
+	var tags strings.Builder
+	for _, k := range sortedKeys(ev.Tags) {
+		v := graphiteTagEscaper.Replace(ev.Tags[k])
+		if v == "" {
+			continue
+		}
+		tags.WriteByte(';')
+		tags.WriteString(graphiteTagEscaper.Replace(strings.ReplaceAll(k, "=", "_")))
+		tags.WriteByte('=')
+		tags.WriteString(v)
+	}
+	ts := strconv.FormatInt(ev.Timestamp/int64(1e9), 10)
+	for _, k := range sortedKeys(ev.Values) {
+		v, ok := graphiteValue(ev.Values[k])
+		if !ok {
+			continue
+		}
+		buf.WriteString(graphiteMetricPath(ev.Name, k))
+		buf.WriteString(tags.String())
+		buf.WriteByte(' ')
+		buf.WriteString(v)
+		buf.WriteByte(' ')
+		buf.WriteString(ts)
+		buf.WriteByte('\n')
+	}
+}
+
+func graphiteMetricPath(name, valueName string) string {
+	elems := make([]string, 0)
+	for _, s := range []string{name, strings.ReplaceAll(valueName, "/", ".")} {
+		for _, e := range strings.Split(s, ".") {
+			if e == "" {
+				continue
+			}
+			elems = append(elems, strings.Map(graphiteRune, e))
+		}
+	}
+	return strings.Join(elems, ".")
+}
+
+// graphiteRune replaces the characters not allowed in
+// a Graphite metric path element by `_`.
+func graphiteRune(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return r
+	case r == '-', r == '_', r == ':':
+		return r
+	}
+	return '_'
+}
+
+func graphiteValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), true
+	case float32:
+		return graphiteValue(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case bool:
+		if v {
+			return "1", true
+		}
+		return "0", true
+	case string:
+		// numbers encoded as strings, e.g: 64bit counters
+		if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
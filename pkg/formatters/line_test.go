@@ -0,0 +1,88 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestEventsToLines(t *testing.T) {
+	evs := []*EventMsg{
+		{
+			Name:      "sub 1",
+			Timestamp: 1700000000123456789,
+			Tags: map[string]string{
+				"source":         "r1:57400",
+				"interface_name": "ethernet-1/1",
+			},
+			Values: map[string]interface{}{
+				"/interface/statistics/in-octets": uint64(42),
+				"/interface/oper-state":           `up "now"`,
+				"/interface/mtu":                  int64(1500),
+				"/interface/load":                 0.5,
+				"/interface/admin-enabled":        true,
+			},
+		},
+		{
+			Name: "empty",
+			Tags: map[string]string{"source": "r1"},
+		},
+	}
+	tests := map[string]string{
+		FormatInflux: `sub\ 1,interface_name=ethernet-1/1,source=r1:57400 ` +
+			`/interface/admin-enabled=true,/interface/load=0.5,/interface/mtu=1500i,` +
+			`/interface/oper-state="up \"now\"",/interface/statistics/in-octets=42u 1700000000123456789`,
+		FormatGraphite: "sub_1.interface.admin-enabled;interface_name=ethernet-1/1;source=r1:57400 1 1700000000\n" +
+			"sub_1.interface.load;interface_name=ethernet-1/1;source=r1:57400 0.5 1700000000\n" +
+			"sub_1.interface.mtu;interface_name=ethernet-1/1;source=r1:57400 1500 1700000000\n" +
+			"sub_1.interface.statistics.in-octets;interface_name=ethernet-1/1;source=r1:57400 42 1700000000",
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			b, err := EventsToLines(format, evs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != want {
+				t.Errorf("unexpected lines:\n got: %s\nwant: %s", b, want)
+			}
+		})
+	}
+	if _, err := EventsToLines("json", evs...); err == nil {
+		t.Errorf("expected an error for a non line format")
+	}
+}
+
+func TestMarshalLineFormat(t *testing.T) {
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: 2e9,
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "mtu"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1500}},
+				}},
+			},
+		},
+	}
+	mo := &MarshalOptions{Format: FormatInflux}
+	b, err := mo.Marshal(rsp, map[string]string{"subscription-name": "sys", "source": "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "sys,source=r1,subscription-name=sys /system/mtu=1500u 2000000000"; string(b) != want {
+		t.Errorf("unexpected line:\n got: %s\nwant: %s", b, want)
+	}
+	b, err = mo.Marshal(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}}, nil)
+	if err != nil || len(b) != 0 {
+		t.Errorf("expected no line for a sync response, got %q, %v", b, err)
+	}
+}
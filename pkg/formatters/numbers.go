@@ -0,0 +1,195 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// NumberFormat normalizes the numeric values of the events written by an output,
+// so that the same value is rendered identically regardless of the output type
+// or the encoding used by the target.
+// It is appended to the output's event processors.
+type NumberFormat struct {
+	// convert the values encoded as strings (e.g: JSON_IETF 64 bit integers and decimal64)
+	// to numbers.
+	NumericStrings bool `mapstructure:"numeric-strings,omitempty" json:"numeric-strings,omitempty"`
+	// number of decimal digits the float values are rounded to.
+	FloatPrecision *int `mapstructure:"float-precision,omitempty" json:"float-precision,omitempty"`
+	// unit conversions applied to the values matching the rules value names.
+	Conversions []*NumberConversion `mapstructure:"conversions,omitempty" json:"conversions,omitempty"`
+}
+
+// NumberConversion multiplies the values whose names match one of ValueNames by Factor,
+// e.g: a factor of 8 converts bytes to bits.
+type NumberConversion struct {
+	ValueNames []string `mapstructure:"value-names,omitempty" json:"value-names,omitempty"`
+	Factor     float64  `mapstructure:"factor,omitempty" json:"factor,omitempty"`
+
+	valueNames []*regexp.Regexp
+}
+
+// Processors validates the number format and returns it as an event processors chain,
+// nil if n is not set.
+func (n *NumberFormat) Processors() ([]EventProcessor, error) {
+	if n == nil {
+		return nil, nil
+	}
+	if err := n.Init(nil); err != nil {
+		return nil, err
+	}
+	return []EventProcessor{n}, nil
+}
+
+func (n *NumberFormat) Init(cfg interface{}, opts ...Option) error {
+	if cfg != nil {
+		if err := DecodeConfig(cfg, n); err != nil {
+			return err
+		}
+	}
+	if n.FloatPrecision != nil && *n.FloatPrecision < 0 {
+		return fmt.Errorf("number-format: invalid float-precision %d", *n.FloatPrecision)
+	}
+	for i, c := range n.Conversions {
+		if c == nil {
+			return fmt.Errorf("number-format: conversion %d is empty", i)
+		}
+		if c.Factor == 0 {
+			return fmt.Errorf("number-format: conversion %d: missing factor", i)
+		}
+		c.valueNames = make([]*regexp.Regexp, 0, len(c.ValueNames))
+		for _, reg := range c.ValueNames {
+			re, err := regexp.Compile(reg)
+			if err != nil {
+				return fmt.Errorf("number-format: conversion %d: %w", i, err)
+			}
+			c.valueNames = append(c.valueNames, re)
+		}
+	}
+	return nil
+}
+
+func (n *NumberFormat) Apply(es ...*EventMsg) []*EventMsg {
+	for _, e := range es {
+		if e == nil {
+			continue
+		}
+		for k, v := range e.Values {
+			e.Values[k] = n.format(k, v)
+		}
+	}
+	return es
+}
+
+func (n *NumberFormat) WithTargets(map[string]*types.TargetConfig) {}
+
+func (n *NumberFormat) WithLogger(*log.Logger) {}
+
+func (n *NumberFormat) WithActions(map[string]map[string]interface{}) {}
+
+func (n *NumberFormat) WithProcessors(map[string]map[string]any) {}
+
+func (n *NumberFormat) format(name string, v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		if !n.NumericStrings {
+			return v
+		}
+		nv, ok := parseNumber(s)
+		if !ok {
+			return v
+		}
+		v = nv
+	}
+	for _, c := range n.Conversions {
+		if c.match(name) {
+			if f, ok := toFloat64(v); ok {
+				v = f * c.Factor
+			}
+			break
+		}
+	}
+	if n.FloatPrecision == nil {
+		return v
+	}
+	switch f := v.(type) {
+	case float64:
+		return roundFloat(f, *n.FloatPrecision)
+	case float32:
+		return roundFloat(float64(f), *n.FloatPrecision)
+	}
+	return v
+}
+
+func (c *NumberConversion) match(name string) bool {
+	for _, re := range c.valueNames {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumber parses s as an int64, a uint64 or a float64, in that order.
+func parseNumber(s string) (interface{}, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f, true
+	}
+	return nil, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func roundFloat(f float64, precision int) float64 {
+	p := math.Pow10(precision)
+	r := math.Round(f*p) / p
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return f
+	}
+	return r
+}
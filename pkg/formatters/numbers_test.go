@@ -0,0 +1,114 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	precision := 2
+	tests := map[string]struct {
+		nf     *NumberFormat
+		input  map[string]interface{}
+		output map[string]interface{}
+	}{
+		"numeric_strings": {
+			nf: &NumberFormat{NumericStrings: true},
+			input: map[string]interface{}{
+				"in-octets":   "18446744073709551615",
+				"out-octets":  "42",
+				"temperature": "-3.5",
+				"oper-status": "UP",
+				"counter":     uint64(1),
+			},
+			output: map[string]interface{}{
+				"in-octets":   uint64(18446744073709551615),
+				"out-octets":  int64(42),
+				"temperature": -3.5,
+				"oper-status": "UP",
+				"counter":     uint64(1),
+			},
+		},
+		"strings_untouched": {
+			nf: &NumberFormat{FloatPrecision: &precision},
+			input: map[string]interface{}{
+				"in-octets": "42",
+				"load":      0.123456,
+				"load32":    float32(1.005),
+			},
+			output: map[string]interface{}{
+				"in-octets": "42",
+				"load":      0.12,
+				"load32":    1.0,
+			},
+		},
+		"conversions": {
+			nf: &NumberFormat{
+				NumericStrings: true,
+				FloatPrecision: &precision,
+				Conversions: []*NumberConversion{
+					{ValueNames: []string{"octets$"}, Factor: 8},
+					{ValueNames: []string{"octets$", "/speed$"}, Factor: 1e6},
+				},
+			},
+			input: map[string]interface{}{
+				"/in-octets": "1000",
+				"/speed":     uint32(25),
+				"/mtu":       uint32(1500),
+				"/rate":      1.0 / 3,
+			},
+			output: map[string]interface{}{
+				"/in-octets": float64(8000),
+				"/speed":     float64(25000000),
+				"/mtu":       uint32(1500),
+				"/rate":      0.33,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			evps, err := tt.nf.Processors()
+			if err != nil {
+				t.Fatal(err)
+			}
+			es := []*EventMsg{nil, {Values: tt.input}}
+			for _, p := range evps {
+				es = p.Apply(es...)
+			}
+			if !reflect.DeepEqual(es[1].Values, tt.output) {
+				t.Errorf("expected: %v", tt.output)
+				t.Errorf("     got: %v", es[1].Values)
+			}
+		})
+	}
+}
+
+func TestNumberFormatInit(t *testing.T) {
+	precision := -1
+	tests := map[string]*NumberFormat{
+		"negative_precision": {FloatPrecision: &precision},
+		"missing_factor":     {Conversions: []*NumberConversion{{ValueNames: []string{"octets"}}}},
+		"bad_regex":          {Conversions: []*NumberConversion{{ValueNames: []string{"("}, Factor: 8}}},
+		"nil_conversion":     {Conversions: []*NumberConversion{nil}},
+	}
+	for name, nf := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := nf.Processors(); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+	var nf *NumberFormat
+	evps, err := nf.Processors()
+	if err != nil || evps != nil {
+		t.Errorf("unexpected result for unset number format: %v, %v", evps, err)
+	}
+}
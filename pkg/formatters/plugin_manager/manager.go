@@ -3,7 +3,6 @@ package plugin_manager
 import (
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"sync"
@@ -14,9 +13,16 @@ import (
 	"github.com/openconfig/gnmic/pkg/config"
 	"github.com/openconfig/gnmic/pkg/formatters"
 	"github.com/openconfig/gnmic/pkg/formatters/event_plugin"
+	"github.com/openconfig/gnmic/pkg/inputs"
+	"github.com/openconfig/gnmic/pkg/inputs/input_plugin"
+	"github.com/openconfig/gnmic/pkg/outputs"
+	"github.com/openconfig/gnmic/pkg/outputs/output_plugin"
 )
 
-var handshakeConfig = plugin.HandshakeConfig{
+// HandshakeConfig is the handshake shared by gNMIc and its plugins.
+// The ProtocolVersion is incremented when the plugins RPC interfaces change,
+// a plugin built for a different version is refused by gNMIc.
+var HandshakeConfig = plugin.HandshakeConfig{
 	ProtocolVersion:  1,
 	MagicCookieKey:   "GNMIC_PLUGIN",
 	MagicCookieValue: "gnmic",
@@ -57,19 +63,51 @@ func (p *PluginManager) Load() error {
 	if p.config == nil {
 		return nil
 	}
-	// discover plugins in the supplied path
-	pluginPaths, err := plugin.Discover(p.config.Glob, p.config.Path)
-	if err != nil {
-		return err
+	// discover processor plugins in the supplied path
+	if p.config.Path != "" {
+		pluginPaths, err := plugin.Discover(p.config.Glob, p.config.Path)
+		if err != nil {
+			return err
+		}
+		// initialize plugins clients and register plugin processors
+		for _, pluginPath := range pluginPaths {
+			name := filepath.Base(pluginPath)
+			formatters.EventProcessorTypes = append(formatters.EventProcessorTypes, name)
+			formatters.Register(name, p.initProcessorFn(name, pluginPath))
+		}
 	}
-
-	// initialize plugins clients and register plugin processors
-	for _, pluginPath := range pluginPaths {
-		name := filepath.Base(pluginPath)
-		formatters.EventProcessorTypes = append(formatters.EventProcessorTypes, name)
-		formatters.Register(name, p.initProcessorFn(name, pluginPath))
+	// discover output plugins
+	if p.config.OutputsPath != "" {
+		pluginPaths, err := plugin.Discover(p.config.Glob, p.config.OutputsPath)
+		if err != nil {
+			return err
+		}
+		for _, pluginPath := range pluginPaths {
+			name := filepath.Base(pluginPath)
+			if _, ok := outputs.Outputs[name]; ok {
+				p.logger.Warn("output plugin name conflicts with an existing output type, ignoring it", "name", name)
+				continue
+			}
+			outputs.OutputTypes[name] = struct{}{}
+			outputs.Register(name, p.initOutputFn(name, pluginPath))
+		}
+	}
+	// discover input plugins
+	if p.config.InputsPath != "" {
+		pluginPaths, err := plugin.Discover(p.config.Glob, p.config.InputsPath)
+		if err != nil {
+			return err
+		}
+		for _, pluginPath := range pluginPaths {
+			name := filepath.Base(pluginPath)
+			if _, ok := inputs.Inputs[name]; ok {
+				p.logger.Warn("input plugin name conflicts with an existing input type, ignoring it", "name", name)
+				continue
+			}
+			inputs.InputTypes = append(inputs.InputTypes, name)
+			inputs.Register(name, p.initInputFn(name, pluginPath))
+		}
 	}
-
 	return nil
 }
 
@@ -83,34 +121,69 @@ func (p *PluginManager) Cleanup() {
 
 func (p *PluginManager) initProcessorFn(name, pluginPath string) func() formatters.EventProcessor {
 	return func() formatters.EventProcessor {
-		client := plugin.NewClient(&plugin.ClientConfig{
-			HandshakeConfig: handshakeConfig,
-			Plugins:         map[string]plugin.Plugin{name: &event_plugin.EventProcessorPlugin{}},
-			Cmd:             exec.Command(pluginPath),
-			StartTimeout:    p.config.StartTimeout,
-			SyncStdout:      p.logOutput,
-			SyncStderr:      p.logOutput,
-			Logger:          p.logger,
-		})
-		p.m.Lock()
-		p.pluginClients = append(p.pluginClients, client)
-		p.m.Unlock()
-		rpcClient, err := client.Client()
+		raw, err := p.dispense("processor", name, pluginPath, &event_plugin.EventProcessorPlugin{})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to initialize plugin processor %s: %v\n", name, err)
-			os.Exit(1)
-		}
-		raw, err := rpcClient.Dispense(name)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to dispense plugin processor %s: %v\n", name, err)
-			os.Exit(1)
+			return event_plugin.Unavailable(err)
 		}
 		eventPlugin, ok := raw.(formatters.EventProcessor)
 		if !ok {
-			err := fmt.Errorf("plugin %s dispensed an unexpected interface: %T", name, raw)
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			os.Exit(1)
+			return event_plugin.Unavailable(fmt.Errorf("plugin %s dispensed an unexpected interface: %T", name, raw))
 		}
 		return eventPlugin
 	}
 }
+
+func (p *PluginManager) initOutputFn(name, pluginPath string) outputs.Initializer {
+	return func() outputs.Output {
+		raw, err := p.dispense("output", name, pluginPath, &output_plugin.OutputPlugin{})
+		if err != nil {
+			return output_plugin.Unavailable(err)
+		}
+		outputPlugin, ok := raw.(outputs.Output)
+		if !ok {
+			return output_plugin.Unavailable(fmt.Errorf("plugin %s dispensed an unexpected interface: %T", name, raw))
+		}
+		return outputPlugin
+	}
+}
+
+func (p *PluginManager) initInputFn(name, pluginPath string) inputs.Initializer {
+	return func() inputs.Input {
+		raw, err := p.dispense("input", name, pluginPath, &input_plugin.InputPlugin{})
+		if err != nil {
+			return input_plugin.Unavailable(err)
+		}
+		inputPlugin, ok := raw.(inputs.Input)
+		if !ok {
+			return input_plugin.Unavailable(fmt.Errorf("plugin %s dispensed an unexpected interface: %T", name, raw))
+		}
+		return inputPlugin
+	}
+}
+
+// dispense starts the plugin binary found at pluginPath and
+// returns the plugin's client side implementation.
+// The returned error is reported when the plugin is initialized.
+func (p *PluginManager) dispense(kind, name, pluginPath string, pl plugin.Plugin) (interface{}, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: HandshakeConfig,
+		Plugins:         map[string]plugin.Plugin{name: pl},
+		Cmd:             exec.Command(pluginPath),
+		StartTimeout:    p.config.StartTimeout,
+		SyncStdout:      p.logOutput,
+		SyncStderr:      p.logOutput,
+		Logger:          p.logger,
+	})
+	p.m.Lock()
+	p.pluginClients = append(p.pluginClients, client)
+	p.m.Unlock()
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize plugin %s %s: %v", kind, name, err)
+	}
+	raw, err := rpcClient.Dispense(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense plugin %s %s: %v", kind, name, err)
+	}
+	return raw, nil
+}
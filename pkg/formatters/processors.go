@@ -40,8 +40,16 @@ var EventProcessorTypes = []string{
 	"event-group-by",
 	"event-data-convert",
 	"event-value-tag",
+	"event-value-tag-v2",
+	"event-ip-lookup",
+	"event-math",
+	"event-time",
 	"event-starlark",
 	"event-combine",
+	"event-redact",
+	"event-adaptive-sample",
+	"event-sequence",
+	"event-sequence-gap",
 }
 
 type Initializer func() EventProcessor
@@ -159,7 +167,7 @@ func MakeEventProcessors(
 				if err != nil {
 					return nil, fmt.Errorf("failed initializing event processor '%s' of type='%s': %w", epName, epType, err)
 				}
-				evps[i] = ep
+				evps[i] = Bypassable(epName, ep)
 				logger.Printf("added event processor '%s' of type=%s to output", epName, epType)
 				continue
 			}
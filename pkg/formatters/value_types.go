@@ -0,0 +1,208 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+)
+
+const (
+	valueTypeInt    = "int"
+	valueTypeFloat  = "float"
+	valueTypeBool   = "bool"
+	valueTypeString = "string"
+)
+
+// ValueType forces the type of the event values whose names match one of Paths,
+// regardless of the type sent by the target, e.g: counters encoded as strings.
+type ValueType struct {
+	// regular expressions matched against the values names, e.g: `/counters/in-octets$`.
+	Paths []string `mapstructure:"paths,omitempty" json:"paths,omitempty"`
+	// one of int, float, bool or string.
+	Type string `mapstructure:"type,omitempty" json:"type,omitempty"`
+
+	paths []*regexp.Regexp
+}
+
+// ValueTypes is an ordered list of value type overrides,
+// the first one matching a value name applies.
+type ValueTypes []*ValueType
+
+// valueTypes holds the overrides applied when
+// converting notifications to events.
+var valueTypes atomic.Pointer[ValueTypes]
+
+// SetValueTypes validates vts and applies them to the values of the events
+// built from gNMI notifications from then on. An empty vts removes the overrides.
+func SetValueTypes(vts ValueTypes) error {
+	if len(vts) == 0 {
+		valueTypes.Store(nil)
+		return nil
+	}
+	if err := vts.Init(); err != nil {
+		return err
+	}
+	valueTypes.Store(&vts)
+	return nil
+}
+
+// Init validates the value types and compiles their paths.
+func (vts ValueTypes) Init() error {
+	for i, vt := range vts {
+		if vt == nil {
+			return fmt.Errorf("value-types: entry %d is empty", i)
+		}
+		switch vt.Type {
+		case valueTypeInt, valueTypeFloat, valueTypeBool, valueTypeString:
+		default:
+			return fmt.Errorf("value-types: entry %d: unknown type %q, must be one of %q, %q, %q or %q",
+				i, vt.Type, valueTypeInt, valueTypeFloat, valueTypeBool, valueTypeString)
+		}
+		if len(vt.Paths) == 0 {
+			return fmt.Errorf("value-types: entry %d: missing paths", i)
+		}
+		vt.paths = make([]*regexp.Regexp, 0, len(vt.Paths))
+		for _, p := range vt.Paths {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("value-types: entry %d: %w", i, err)
+			}
+			vt.paths = append(vt.paths, re)
+		}
+	}
+	return nil
+}
+
+// applyValueTypes converts the values matching the configured value types.
+func applyValueTypes(values map[string]interface{}) {
+	vts := valueTypes.Load()
+	if vts == nil {
+		return
+	}
+	for k, v := range values {
+		for _, vt := range *vts {
+			if vt.match(k) {
+				values[k] = vt.convert(v)
+				break
+			}
+		}
+	}
+}
+
+func (vt *ValueType) match(name string) bool {
+	for _, re := range vt.paths {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// convert returns v converted to the value type,
+// or v unchanged if it cannot be converted.
+func (vt *ValueType) convert(v interface{}) interface{} {
+	switch vt.Type {
+	case valueTypeInt:
+		if i, ok := toInt(v); ok {
+			return i
+		}
+	case valueTypeFloat:
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+			return v
+		}
+		if f, ok := toFloat64(v); ok {
+			return f
+		}
+	case valueTypeBool:
+		switch v := v.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+			return v
+		}
+		if f, ok := toFloat64(v); ok {
+			return f != 0
+		}
+	case valueTypeString:
+		switch v := v.(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case float32:
+			return strconv.FormatFloat(float64(v), 'f', -1, 32)
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return fmt.Sprint(v)
+		}
+	}
+	return v
+}
+
+// toInt converts v to an int64, or to an uint64 if it is above the int64 range.
+// Floats are truncated.
+func toInt(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case int64, uint64:
+		return v, true
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, true
+		}
+		if u, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return u, true
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, false
+		}
+		return toInt(f)
+	case bool:
+		if v {
+			return int64(1), true
+		}
+		return int64(0), true
+	case float32:
+		return toInt(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < math.MinInt64 || v >= math.MaxUint64 {
+			return nil, false
+		}
+		if v >= math.MaxInt64 {
+			return uint64(v), true
+		}
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case uint:
+		return uint64(v), true
+	case uint8:
+		return uint64(v), true
+	case uint16:
+		return uint64(v), true
+	case uint32:
+		return uint64(v), true
+	}
+	return nil, false
+}
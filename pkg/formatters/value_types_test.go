@@ -0,0 +1,90 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formatters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestValueTypes(t *testing.T) {
+	err := SetValueTypes(ValueTypes{
+		{Paths: []string{`/counters/`}, Type: "int"},
+		{Paths: []string{`/load$`}, Type: "float"},
+		{Paths: []string{`/enabled$`}, Type: "bool"},
+		{Paths: []string{`/index$`}, Type: "string"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetValueTypes(nil)
+
+	strVal := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: s}}
+	}
+	upd := func(name string, v *gnmi.TypedValue) *gnmi.Update {
+		return &gnmi.Update{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "if"}, {Name: name}}}, Val: v}
+	}
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "counters"}}},
+				Update: []*gnmi.Update{
+					upd("in-octets", strVal("18446744073709551615")),
+					upd("out-octets", strVal("42")),
+					upd("errors", strVal("n/a")),
+				},
+			},
+		},
+	}
+	evs, err := ResponseToEventMsgs("sub1", rsp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]interface{})
+	for _, ev := range evs {
+		for k, v := range ev.Values {
+			got[k] = v
+		}
+	}
+	rsp.GetUpdate().Prefix = nil
+	rsp.GetUpdate().Update = []*gnmi.Update{
+		upd("load", strVal("0.5")),
+		upd("enabled", &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1}}),
+		upd("index", &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 3}}),
+		upd("mtu", strVal("1500")),
+	}
+	evs, err = ResponseToEventMsgs("sub1", rsp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range evs {
+		for k, v := range ev.Values {
+			got[k] = v
+		}
+	}
+	want := map[string]interface{}{
+		"/counters/if/in-octets":  uint64(18446744073709551615),
+		"/counters/if/out-octets": int64(42),
+		"/counters/if/errors":     "n/a",
+		"/if/load":                0.5,
+		"/if/enabled":             true,
+		"/if/index":               "3",
+		"/if/mtu":                 "1500",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected values:\n got: %#v\nwant: %#v", got, want)
+	}
+
+	if err = SetValueTypes(ValueTypes{{Paths: []string{"x"}, Type: "decimal"}}); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+}
@@ -0,0 +1,186 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmitest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// schema is built from the YANG modules loaded with WithYANG.
+type schema struct {
+	// the modules entries, by module name
+	modules map[string]*yang.Entry
+	// the modules returned in the Capabilities responses
+	models []*gnmi.ModelData
+	// the leaves default values
+	defaults []*gnmi.Update
+}
+
+func loadSchema(files, dirs []string) (*schema, error) {
+	ms := yang.NewModules()
+	for _, dir := range dirs {
+		expanded, err := yang.PathsWithModules(dir)
+		if err != nil {
+			return nil, err
+		}
+		ms.AddPath(expanded...)
+	}
+	for _, f := range files {
+		if err := ms.Read(f); err != nil {
+			return nil, err
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		return nil, fmt.Errorf("yang processing failed: %w", errors.Join(errs...))
+	}
+	s := &schema{
+		modules: make(map[string]*yang.Entry),
+	}
+	// the modules are indexed by name and by name@revision
+	names := make([]string, 0, len(ms.Modules))
+	for _, m := range ms.Modules {
+		if _, ok := s.modules[m.Name]; ok {
+			continue
+		}
+		s.modules[m.Name] = yang.ToEntry(m)
+		names = append(names, m.Name)
+		md := &gnmi.ModelData{
+			Name:    m.Name,
+			Version: m.Current(),
+		}
+		if m.Organization != nil {
+			md.Organization = m.Organization.Name
+		}
+		s.models = append(s.models, md)
+	}
+	sort.Slice(s.models, func(i, j int) bool {
+		return s.models[i].Name < s.models[j].Name
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		s.walkDefaults(s.modules[name], new(gnmi.Path))
+	}
+	return s, nil
+}
+
+// walkDefaults adds the default values of the leaves below e to the schema defaults.
+// The lists are skipped, their entries paths require the keys values.
+func (s *schema) walkDefaults(e *yang.Entry, p *gnmi.Path) {
+	switch {
+	case e.IsList():
+	case e.IsLeaf(), e.IsLeafList():
+		vals := e.DefaultValues()
+		if len(vals) == 0 {
+			return
+		}
+		tv := defaultTypedValue(e, vals)
+		s.defaults = append(s.defaults, &gnmi.Update{Path: p, Val: tv})
+	default:
+		names := make([]string, 0, len(e.Dir))
+		for name := range e.Dir {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := e.Dir[name]
+			cp := p
+			// choices and cases are not part of the data tree
+			if !child.IsChoice() && !child.IsCase() {
+				cp = &gnmi.Path{Elem: append(append([]*gnmi.PathElem{}, p.GetElem()...), &gnmi.PathElem{Name: child.Name})}
+			}
+			s.walkDefaults(child, cp)
+		}
+	}
+}
+
+// listKeys returns the key names of the list at path p.
+// It implements path.ListKeysFunc.
+func (s *schema) listKeys(p *gnmi.Path) []string {
+	if s == nil || len(p.GetElem()) == 0 {
+		return nil
+	}
+	var e *yang.Entry
+	for _, m := range s.modules {
+		if e = findChild(m, p.GetElem()[0].GetName()); e != nil {
+			break
+		}
+	}
+	for _, pe := range p.GetElem()[1:] {
+		if e == nil {
+			return nil
+		}
+		e = findChild(e, pe.GetName())
+	}
+	if e == nil || !e.IsList() {
+		return nil
+	}
+	return strings.Fields(e.Key)
+}
+
+// findChild returns the child of e called name,
+// looking through the choices and cases.
+func findChild(e *yang.Entry, name string) *yang.Entry {
+	name = stripModulePrefix(name)
+	if c, ok := e.Dir[name]; ok {
+		return c
+	}
+	for _, c := range e.Dir {
+		if c.IsChoice() || c.IsCase() {
+			if r := findChild(c, name); r != nil {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+func defaultTypedValue(e *yang.Entry, vals []string) *gnmi.TypedValue {
+	if e.IsLeafList() {
+		ll := &gnmi.ScalarArray{Element: make([]*gnmi.TypedValue, 0, len(vals))}
+		for _, v := range vals {
+			ll.Element = append(ll.Element, scalarTypedValue(e.Type, v))
+		}
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_LeaflistVal{LeaflistVal: ll}}
+	}
+	return scalarTypedValue(e.Type, vals[0])
+}
+
+// scalarTypedValue converts the default value v of a leaf of type t.
+// Values that cannot be converted are returned as strings.
+func scalarTypedValue(t *yang.YangType, v string) *gnmi.TypedValue {
+	if t == nil {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: v}}
+	}
+	switch t.Kind {
+	case yang.Ybool:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: b}}
+		}
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yint64:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: i}}
+		}
+	case yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Yuint64:
+		if u, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: u}}
+		}
+	case yang.Ydecimal64:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_DoubleVal{DoubleVal: f}}
+		}
+	}
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: v}}
+}
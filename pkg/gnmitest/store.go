@@ -0,0 +1,239 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmitest
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+// store holds the target leaves, keyed by their xpath.
+type store struct {
+	schema *schema
+
+	m      sync.RWMutex
+	leaves map[string]*gnmi.Update
+}
+
+// change is a leaf update, or a leaf deletion if val is nil.
+type change struct {
+	path *gnmi.Path
+	val  *gnmi.TypedValue
+}
+
+func newStore() *store {
+	return &store{leaves: make(map[string]*gnmi.Update)}
+}
+
+// get returns the leaves matching p, sorted by path.
+func (s *store) get(p *gnmi.Path) []*gnmi.Update {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	keys := make([]string, 0)
+	for k, upd := range s.leaves {
+		if pathMatch(p, upd.GetPath()) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	upds := make([]*gnmi.Update, 0, len(keys))
+	for _, k := range keys {
+		upds = append(upds, s.leaves[k])
+	}
+	return upds
+}
+
+// apply stores the leaves upds.
+func (s *store) apply(upds []*gnmi.Update) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, upd := range upds {
+		s.leaves[xpath(upd.GetPath())] = upd
+	}
+}
+
+// load stores the values of notification n.
+func (s *store) load(n *gnmi.Notification) error {
+	_, err := s.update(n)
+	return err
+}
+
+// update applies the deletes then the updates of notification n.
+func (s *store) update(n *gnmi.Notification) ([]*change, error) {
+	upds := make([]*gnmi.Update, 0, len(n.GetUpdate()))
+	for _, upd := range n.GetUpdate() {
+		leaves, err := s.leafUpdates(joinPaths(n.GetPrefix(), upd.GetPath()), upd.GetVal())
+		if err != nil {
+			return nil, err
+		}
+		upds = append(upds, leaves...)
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	changes := make([]*change, 0, len(upds))
+	for _, p := range n.GetDelete() {
+		changes = append(changes, s.delete(joinPaths(n.GetPrefix(), p))...)
+	}
+	for _, upd := range upds {
+		changes = append(changes, s.put(upd))
+	}
+	return changes, nil
+}
+
+// set applies the SetRequest req, all its values are decoded
+// before the target data is changed.
+func (s *store) set(req *gnmi.SetRequest) ([]*change, []*gnmi.UpdateResult, error) {
+	prefix := req.GetPrefix()
+	replaces := make([][]*gnmi.Update, 0, len(req.GetReplace()))
+	for _, upd := range req.GetReplace() {
+		leaves, err := s.leafUpdates(joinPaths(prefix, upd.GetPath()), upd.GetVal())
+		if err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "replace %q: %v", xpath(upd.GetPath()), err)
+		}
+		replaces = append(replaces, leaves)
+	}
+	updates := make([][]*gnmi.Update, 0, len(req.GetUpdate()))
+	for _, upd := range req.GetUpdate() {
+		leaves, err := s.leafUpdates(joinPaths(prefix, upd.GetPath()), upd.GetVal())
+		if err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "update %q: %v", xpath(upd.GetPath()), err)
+		}
+		updates = append(updates, leaves)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	changes := make([]*change, 0)
+	results := make([]*gnmi.UpdateResult, 0, len(req.GetDelete())+len(replaces)+len(updates))
+	for _, p := range req.GetDelete() {
+		changes = append(changes, s.delete(joinPaths(prefix, p))...)
+		results = append(results, &gnmi.UpdateResult{Path: p, Op: gnmi.UpdateResult_DELETE})
+	}
+	for i, leaves := range replaces {
+		upd := req.GetReplace()[i]
+		changes = append(changes, s.delete(joinPaths(prefix, upd.GetPath()))...)
+		for _, l := range leaves {
+			changes = append(changes, s.put(l))
+		}
+		results = append(results, &gnmi.UpdateResult{Path: upd.GetPath(), Op: gnmi.UpdateResult_REPLACE})
+	}
+	for i, leaves := range updates {
+		for _, l := range leaves {
+			changes = append(changes, s.put(l))
+		}
+		results = append(results, &gnmi.UpdateResult{Path: req.GetUpdate()[i].GetPath(), Op: gnmi.UpdateResult_UPDATE})
+	}
+	return changes, results, nil
+}
+
+// put stores upd, it must be called with the lock held.
+func (s *store) put(upd *gnmi.Update) *change {
+	s.leaves[xpath(upd.GetPath())] = upd
+	return &change{path: upd.GetPath(), val: upd.GetVal()}
+}
+
+// delete removes the leaves matching p, it must be called with the lock held.
+func (s *store) delete(p *gnmi.Path) []*change {
+	changes := make([]*change, 0)
+	for k, upd := range s.leaves {
+		if pathMatch(p, upd.GetPath()) {
+			delete(s.leaves, k)
+			changes = append(changes, &change{path: upd.GetPath()})
+		}
+	}
+	return changes
+}
+
+// leafUpdates splits the JSON value val into leaf updates,
+// the lists entries paths are built using the schema lists keys.
+// A value containing a list with unknown keys is kept as is.
+func (s *store) leafUpdates(p *gnmi.Path, val *gnmi.TypedValue) ([]*gnmi.Update, error) {
+	var b []byte
+	switch v := val.GetValue().(type) {
+	case *gnmi.TypedValue_JsonVal:
+		b = v.JsonVal
+	case *gnmi.TypedValue_JsonIetfVal:
+		b = v.JsonIetfVal
+	default:
+		return []*gnmi.Update{{Path: p, Val: val}}, nil
+	}
+	upds, err := path.FromJSON(p, b, s.schema.listKeys)
+	if errors.Is(err, path.ErrUnknownListKeys) {
+		return []*gnmi.Update{{Path: p, Val: val}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if val.GetJsonIetfVal() != nil {
+		for _, upd := range upds {
+			upd.Val = &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: upd.GetVal().GetJsonVal()}}
+		}
+	}
+	return upds, nil
+}
+
+// pathMatch returns true if the leaf path lp is p or is below p.
+// The elements names and keys values of p can be `*`.
+func pathMatch(p, lp *gnmi.Path) bool {
+	if p.GetOrigin() != "" && lp.GetOrigin() != "" && p.GetOrigin() != lp.GetOrigin() {
+		return false
+	}
+	if len(p.GetElem()) > len(lp.GetElem()) {
+		return false
+	}
+	for i, pe := range p.GetElem() {
+		le := lp.GetElem()[i]
+		if pe.GetName() != "*" && stripModulePrefix(pe.GetName()) != stripModulePrefix(le.GetName()) {
+			return false
+		}
+		for k, v := range pe.GetKey() {
+			if v != "*" && le.GetKey()[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// joinPaths returns the path p prefixed by prefix, without its target.
+func joinPaths(prefix, p *gnmi.Path) *gnmi.Path {
+	jp := &gnmi.Path{
+		Origin: p.GetOrigin(),
+		Elem:   make([]*gnmi.PathElem, 0, len(prefix.GetElem())+len(p.GetElem())),
+	}
+	if jp.Origin == "" {
+		jp.Origin = prefix.GetOrigin()
+	}
+	jp.Elem = append(jp.Elem, prefix.GetElem()...)
+	jp.Elem = append(jp.Elem, p.GetElem()...)
+	return jp
+}
+
+func xpath(p *gnmi.Path) string {
+	xp := "/" + path.GnmiPathToXPath(&gnmi.Path{Elem: p.GetElem()}, false)
+	if p.GetOrigin() != "" {
+		return p.GetOrigin() + ":" + xp
+	}
+	return xp
+}
+
+func stripModulePrefix(name string) string {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
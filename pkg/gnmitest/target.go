@@ -0,0 +1,449 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gnmitest provides an in-process gNMI target for tests.
+//
+// The target data is loaded from gNMIc snapshot files or GetResponses,
+// and from the default values of YANG models.
+// It answers the Capabilities, Get, Set and Subscribe RPCs from that data,
+// the Set RPCs changes are streamed to the STREAM subscriptions.
+package gnmitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	defaultAddress = "127.0.0.1:0"
+	gnmiVersion    = "0.10.0"
+	// number of notifications queued per STREAM subscription
+	subscriberQueueSize = 1000
+)
+
+// Target is an in-process gNMI target.
+type Target struct {
+	gnmi.UnimplementedGNMIServer
+
+	address   string
+	yangFiles []string
+	yangDirs  []string
+	rsps      []*gnmi.GetResponse
+	models    []*gnmi.ModelData
+
+	s *store
+
+	m           sync.Mutex
+	subscribers map[*subscriber]struct{}
+
+	srv *grpc.Server
+	l   net.Listener
+}
+
+// Option configures a Target.
+type Option func(*Target) error
+
+// WithAddress sets the address the target listens on,
+// defaults to a random port on the loopback interface.
+func WithAddress(addr string) Option {
+	return func(t *Target) error {
+		t.address = addr
+		return nil
+	}
+}
+
+// WithSnapshot loads the data of the snapshot file written using format,
+// one of json, proto, protojson or prototext.
+// It can be used multiple times, the files are loaded in order.
+func WithSnapshot(file, format string) Option {
+	return func(t *Target) error {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rsp, err := formatters.DecodeGetResponse(b, format)
+		if err != nil {
+			return fmt.Errorf("failed to decode snapshot file %q: %w", file, err)
+		}
+		t.rsps = append(t.rsps, rsp)
+		return nil
+	}
+}
+
+// WithGetResponse loads the data of rsp.
+func WithGetResponse(rsp *gnmi.GetResponse) Option {
+	return func(t *Target) error {
+		t.rsps = append(t.rsps, rsp)
+		return nil
+	}
+}
+
+// WithYANG loads the YANG modules files, the modules they import are looked up in dirs.
+// The leaves default values outside of the lists are loaded,
+// the loaded modules are returned in the Capabilities responses
+// and the schema lists keys are used to split the JSON values into leaves.
+func WithYANG(files []string, dirs ...string) Option {
+	return func(t *Target) error {
+		t.yangFiles = append(t.yangFiles, files...)
+		t.yangDirs = append(t.yangDirs, dirs...)
+		return nil
+	}
+}
+
+// WithModels adds models to the Capabilities responses.
+func WithModels(models ...*gnmi.ModelData) Option {
+	return func(t *Target) error {
+		t.models = append(t.models, models...)
+		return nil
+	}
+}
+
+// New creates a Target and loads its data.
+func New(opts ...Option) (*Target, error) {
+	t := &Target{
+		address:     defaultAddress,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	for _, o := range opts {
+		if err := o(t); err != nil {
+			return nil, err
+		}
+	}
+	t.s = newStore()
+	if len(t.yangFiles) > 0 {
+		sch, err := loadSchema(t.yangFiles, t.yangDirs)
+		if err != nil {
+			return nil, err
+		}
+		t.s.schema = sch
+		t.models = append(sch.models, t.models...)
+		t.s.apply(sch.defaults)
+	}
+	for _, rsp := range t.rsps {
+		for _, n := range rsp.GetNotification() {
+			if err := t.s.load(n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+// Start starts serving the gNMI RPCs.
+func (t *Target) Start() error {
+	var err error
+	t.l, err = net.Listen("tcp", t.address)
+	if err != nil {
+		return err
+	}
+	t.srv = grpc.NewServer()
+	gnmi.RegisterGNMIServer(t.srv, t)
+	go t.srv.Serve(t.l)
+	return nil
+}
+
+// Address returns the address the target listens on.
+func (t *Target) Address() string {
+	if t.l == nil {
+		return t.address
+	}
+	return t.l.Addr().String()
+}
+
+// Stop stops the target, closing the active RPCs.
+func (t *Target) Stop() {
+	if t.srv != nil {
+		t.srv.Stop()
+	}
+}
+
+// Update applies the notifications to the target data
+// and sends them to the matching STREAM subscriptions.
+func (t *Target) Update(ns ...*gnmi.Notification) error {
+	changes := make([]*change, 0, len(ns))
+	for _, n := range ns {
+		chs, err := t.s.update(n)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, chs...)
+	}
+	t.notify(changes)
+	return nil
+}
+
+// StartTarget creates and starts a Target, it is stopped when the test ends.
+// The test fails if the target cannot be started.
+func StartTarget(tb testing.TB, opts ...Option) *Target {
+	tb.Helper()
+	t, err := New(opts...)
+	if err != nil {
+		tb.Fatalf("failed to create gNMI target: %v", err)
+	}
+	if err = t.Start(); err != nil {
+		tb.Fatalf("failed to start gNMI target: %v", err)
+	}
+	tb.Cleanup(t.Stop)
+	return t
+}
+
+func (t *Target) Capabilities(ctx context.Context, req *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	return &gnmi.CapabilityResponse{
+		SupportedModels: t.models,
+		SupportedEncodings: []gnmi.Encoding{
+			gnmi.Encoding_JSON,
+			gnmi.Encoding_JSON_IETF,
+		},
+		GNMIVersion: gnmiVersion,
+	}, nil
+}
+
+func (t *Target) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	paths := req.GetPath()
+	if len(paths) == 0 {
+		paths = []*gnmi.Path{{}}
+	}
+	rsp := &gnmi.GetResponse{
+		Notification: make([]*gnmi.Notification, 0, len(paths)),
+	}
+	now := time.Now().UnixNano()
+	for _, p := range paths {
+		fp := joinPaths(req.GetPrefix(), p)
+		upds := t.s.get(fp)
+		if len(upds) == 0 {
+			return nil, status.Errorf(codes.NotFound, "path %q not found", xpath(fp))
+		}
+		rsp.Notification = append(rsp.Notification, &gnmi.Notification{
+			Timestamp: now,
+			Update:    upds,
+		})
+	}
+	return rsp, nil
+}
+
+func (t *Target) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+	changes, results, err := t.s.set(req)
+	if err != nil {
+		return nil, err
+	}
+	t.notify(changes)
+	return &gnmi.SetResponse{
+		Prefix:    req.GetPrefix(),
+		Response:  results,
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+func (t *Target) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	sl := req.GetSubscribe()
+	if sl == nil {
+		return status.Errorf(codes.InvalidArgument, "the first SubscribeRequest must be a SubscriptionList")
+	}
+	paths := make([]*gnmi.Path, 0, len(sl.GetSubscription()))
+	for _, sub := range sl.GetSubscription() {
+		paths = append(paths, joinPaths(sl.GetPrefix(), sub.GetPath()))
+	}
+	if len(paths) == 0 {
+		paths = append(paths, joinPaths(sl.GetPrefix(), nil))
+	}
+
+	switch sl.GetMode() {
+	case gnmi.SubscriptionList_ONCE:
+		return t.sendAll(stream, paths, sl.GetUpdatesOnly())
+	case gnmi.SubscriptionList_POLL:
+		err = t.sendAll(stream, paths, sl.GetUpdatesOnly())
+		if err != nil {
+			return err
+		}
+		for {
+			req, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if req.GetPoll() == nil {
+				return status.Errorf(codes.InvalidArgument, "expecting a Poll request, got %v", req)
+			}
+			if err = t.sendAll(stream, paths, false); err != nil {
+				return err
+			}
+		}
+	default:
+		return t.stream(stream, sl, paths)
+	}
+}
+
+// stream handles a STREAM subscription: it sends the current values,
+// then the changes and the SAMPLE subscriptions values at their interval.
+func (t *Target) stream(stream gnmi.GNMI_SubscribeServer, sl *gnmi.SubscriptionList, paths []*gnmi.Path) error {
+	ctx := stream.Context()
+	sub := t.subscribe(ctx, paths)
+	defer t.unsubscribe(sub)
+
+	err := t.sendAll(stream, paths, sl.GetUpdatesOnly())
+	if err != nil {
+		return err
+	}
+	samples := make(chan *gnmi.Path)
+	for i, s := range sl.GetSubscription() {
+		if s.GetMode() != gnmi.SubscriptionMode_SAMPLE || s.GetSampleInterval() == 0 {
+			continue
+		}
+		go func(p *gnmi.Path, interval time.Duration) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					select {
+					case <-ctx.Done():
+						return
+					case samples <- p:
+					}
+				}
+			}
+		}(paths[i], time.Duration(s.GetSampleInterval()))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-sub.ch:
+			err = stream.Send(&gnmi.SubscribeResponse{
+				Response: &gnmi.SubscribeResponse_Update{Update: n},
+			})
+		case p := <-samples:
+			err = t.send(stream, p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sendAll sends the values matching paths, unless updatesOnly is true,
+// followed by a sync response.
+func (t *Target) sendAll(stream gnmi.GNMI_SubscribeServer, paths []*gnmi.Path, updatesOnly bool) error {
+	if !updatesOnly {
+		for _, p := range paths {
+			if err := t.send(stream, p); err != nil {
+				return err
+			}
+		}
+	}
+	return stream.Send(&gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+	})
+}
+
+// send sends the values matching p, a notification per leaf.
+func (t *Target) send(stream gnmi.GNMI_SubscribeServer, p *gnmi.Path) error {
+	now := time.Now().UnixNano()
+	for _, upd := range t.s.get(p) {
+		err := stream.Send(&gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: &gnmi.Notification{
+					Timestamp: now,
+					Update:    []*gnmi.Update{upd},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscriber is a STREAM subscription waiting for changes.
+type subscriber struct {
+	ctx   context.Context
+	paths []*gnmi.Path
+	ch    chan *gnmi.Notification
+}
+
+func (t *Target) subscribe(ctx context.Context, paths []*gnmi.Path) *subscriber {
+	sub := &subscriber{
+		ctx:   ctx,
+		paths: paths,
+		ch:    make(chan *gnmi.Notification, subscriberQueueSize),
+	}
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (t *Target) unsubscribe(sub *subscriber) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	delete(t.subscribers, sub)
+}
+
+// notify sends the changes to the subscribers with a matching path.
+func (t *Target) notify(changes []*change) {
+	if len(changes) == 0 {
+		return
+	}
+	t.m.Lock()
+	subs := make([]*subscriber, 0, len(t.subscribers))
+	for sub := range t.subscribers {
+		subs = append(subs, sub)
+	}
+	t.m.Unlock()
+
+	now := time.Now().UnixNano()
+	for _, sub := range subs {
+		for _, ch := range changes {
+			if !sub.matches(ch.path) {
+				continue
+			}
+			n := &gnmi.Notification{Timestamp: now}
+			if ch.val == nil {
+				n.Delete = []*gnmi.Path{ch.path}
+			} else {
+				n.Update = []*gnmi.Update{{Path: ch.path, Val: ch.val}}
+			}
+			select {
+			case <-sub.ctx.Done():
+			case sub.ch <- n:
+			}
+		}
+	}
+}
+
+func (sub *subscriber) matches(p *gnmi.Path) bool {
+	for _, sp := range sub.paths {
+		if pathMatch(sp, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmitest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const testModule = `
+module test {
+  namespace "urn:test";
+  prefix t;
+  organization "gNMIc";
+  revision 2024-01-01;
+
+  container system {
+    leaf hostname { type string; default "router"; }
+    leaf mtu { type uint16; default 1500; }
+  }
+  container interfaces {
+    list interface {
+      key name;
+      leaf name { type string; }
+      leaf enabled { type boolean; default true; }
+      leaf description { type string; }
+    }
+  }
+}
+`
+
+func mustPath(t *testing.T, s string) *gnmi.Path {
+	t.Helper()
+	p, err := path.ParsePath(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func testTarget(t *testing.T) (*Target, gnmi.GNMIClient) {
+	t.Helper()
+	dir := t.TempDir()
+	yangFile := filepath.Join(dir, "test.yang")
+	if err := os.WriteFile(yangFile, []byte(testModule), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mo := &formatters.MarshalOptions{Format: "json"}
+	b, err := mo.Marshal(&gnmi.GetResponse{
+		Notification: []*gnmi.Notification{{
+			Timestamp: 42,
+			Update: []*gnmi.Update{{
+				Path: mustPath(t, "/interfaces"),
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{
+					JsonVal: []byte(`{"interface":[{"name":"e1","description":"uplink"},{"name":"e2"}]}`),
+				}},
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotFile := filepath.Join(dir, "r1.json")
+	if err = os.WriteFile(snapshotFile, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tg := StartTarget(t, WithYANG([]string{yangFile}), WithSnapshot(snapshotFile, "json"))
+	conn, err := grpc.Dial(tg.Address(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return tg, gnmi.NewGNMIClient(conn)
+}
+
+func updatesXPaths(upds []*gnmi.Update) map[string]*gnmi.TypedValue {
+	m := make(map[string]*gnmi.TypedValue, len(upds))
+	for _, upd := range upds {
+		m[xpath(upd.GetPath())] = upd.GetVal()
+	}
+	return m
+}
+
+func TestTargetCapabilitiesGet(t *testing.T) {
+	_, c := testTarget(t)
+	ctx := context.Background()
+
+	caps, err := c.Capabilities(ctx, new(gnmi.CapabilityRequest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(caps.GetSupportedModels()) != 1 || caps.GetSupportedModels()[0].GetName() != "test" ||
+		caps.GetSupportedModels()[0].GetVersion() != "2024-01-01" || caps.GetSupportedModels()[0].GetOrganization() != "gNMIc" {
+		t.Errorf("unexpected models %v", caps.GetSupportedModels())
+	}
+
+	rsp, err := c.Get(ctx, &gnmi.GetRequest{
+		Path: []*gnmi.Path{
+			mustPath(t, "/system"),
+			mustPath(t, "/interfaces/interface[name=*]/description"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsp.GetNotification()) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(rsp.GetNotification()))
+	}
+	sys := updatesXPaths(rsp.GetNotification()[0].GetUpdate())
+	if len(sys) != 2 || sys["/system/hostname"].GetStringVal() != "router" || sys["/system/mtu"].GetUintVal() != 1500 {
+		t.Errorf("unexpected system defaults %v", sys)
+	}
+	descr := updatesXPaths(rsp.GetNotification()[1].GetUpdate())
+	if len(descr) != 1 || string(descr["/interfaces/interface[name=e1]/description"].GetJsonVal()) != `"uplink"` {
+		t.Errorf("unexpected interfaces descriptions %v", descr)
+	}
+
+	_, err = c.Get(ctx, &gnmi.GetRequest{Path: []*gnmi.Path{mustPath(t, "/interfaces/interface[name=e3]")}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected a NotFound error, got %v", err)
+	}
+}
+
+func TestTargetSetSubscribe(t *testing.T) {
+	_, c := testTarget(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{
+				Prefix: mustPath(t, "/interfaces"),
+				Subscription: []*gnmi.Subscription{
+					{Path: mustPath(t, "/interface[name=e2]"), Mode: gnmi.SubscriptionMode_ON_CHANGE},
+				},
+				Mode: gnmi.SubscriptionList_STREAM,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// e2 name, then the sync response
+	for i := 0; i < 2; i++ {
+		rsp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && !rsp.GetSyncResponse() {
+			t.Fatalf("expected a sync response, got %v", rsp)
+		}
+	}
+
+	_, err = c.Set(ctx, &gnmi.SetRequest{
+		Prefix: mustPath(t, "/interfaces"),
+		Update: []*gnmi.Update{{
+			Path: mustPath(t, "/interface[name=e2]/description"),
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "downlink"}},
+		}},
+		Delete: []*gnmi.Path{mustPath(t, "/interface[name=e1]")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upds := updatesXPaths(rsp.GetUpdate().GetUpdate())
+	if upds["/interfaces/interface[name=e2]/description"].GetStringVal() != "downlink" {
+		t.Errorf("unexpected update %v", rsp)
+	}
+
+	get, err := c.Get(ctx, &gnmi.GetRequest{Path: []*gnmi.Path{mustPath(t, "/interfaces")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := updatesXPaths(get.GetNotification()[0].GetUpdate()); len(got) != 2 {
+		t.Errorf("expected the e2 leaves only, got %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gtemplate
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+)
+
+// pathFuncs are gNMI specific template functions operating on xpaths.
+// The xpath is always the last argument so that it can be piped,
+// e.g: {{ index . "path" | pathElem -1 }}
+var pathFuncs = template.FuncMap{
+	"pathElem":     pathElem,
+	"keyValue":     keyValue,
+	"stripOrigin":  stripOrigin,
+	"xpathCompare": xpathCompare,
+}
+
+// pathElem returns the name of the path element at index idx.
+// A negative idx counts from the last element.
+// It returns an empty string if idx is out of range.
+func pathElem(idx int, p string) (string, error) {
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		return "", err
+	}
+	if idx < 0 {
+		idx += len(gp.GetElem())
+	}
+	if idx < 0 || idx >= len(gp.GetElem()) {
+		return "", nil
+	}
+	return gp.GetElem()[idx].GetName(), nil
+}
+
+// keyValue returns the value of the first key named key found in the path elements.
+// It returns an empty string if the path does not have such key.
+func keyValue(key string, p string) (string, error) {
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		return "", err
+	}
+	for _, pe := range gp.GetElem() {
+		if v, ok := pe.GetKey()[key]; ok {
+			return v, nil
+		}
+	}
+	return "", nil
+}
+
+// stripOrigin removes the origin from a path, if any.
+func stripOrigin(p string) (string, error) {
+	gp, err := path.ParsePath(p)
+	if err != nil {
+		return "", err
+	}
+	if gp.GetOrigin() == "" {
+		return p, nil
+	}
+	return p[len(gp.GetOrigin())+1:], nil
+}
+
+// xpathCompare returns true if the paths p1 and p2 are equivalent.
+// Origins, leading and trailing slashes as well as the keys order are ignored.
+// A `*` path element name or key value matches any name or value,
+// a missing key matches any value.
+func xpathCompare(p1, p2 string) (bool, error) {
+	gp1, err := path.ParsePath(strings.TrimSuffix(p1, "/"))
+	if err != nil {
+		return false, err
+	}
+	gp2, err := path.ParsePath(strings.TrimSuffix(p2, "/"))
+	if err != nil {
+		return false, err
+	}
+	if len(gp1.GetElem()) != len(gp2.GetElem()) {
+		return false, nil
+	}
+	for i, pe1 := range gp1.GetElem() {
+		if !pathElemMatch(pe1, gp2.GetElem()[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func pathElemMatch(pe1, pe2 *gnmi.PathElem) bool {
+	if pe1.GetName() != pe2.GetName() && pe1.GetName() != "*" && pe2.GetName() != "*" {
+		return false
+	}
+	for k, v1 := range pe1.GetKey() {
+		v2, ok := pe2.GetKey()[k]
+		if !ok {
+			continue
+		}
+		if v1 != v2 && v1 != "*" && v2 != "*" {
+			return false
+		}
+	}
+	return true
+}
@@ -12,6 +12,7 @@ import (
 	"context"
 	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
 	"github.com/hairyhenderson/gomplate/v3"
 	"github.com/hairyhenderson/gomplate/v3/data"
 )
@@ -26,6 +27,16 @@ func NewTemplateEngine() templateEngine {
 
 type gmplt struct{}
 
+// CreateFuncs returns the sprig functions, the gomplate functions and the gNMI path functions.
+// gomplate functions take precedence over sprig functions with the same name,
+// so that existing templates keep working.
 func (*gmplt) CreateFuncs() template.FuncMap {
-	return gomplate.CreateFuncs(context.TODO(), new(data.Data))
+	funcs := sprig.TxtFuncMap()
+	for n, f := range gomplate.CreateFuncs(context.TODO(), new(data.Data)) {
+		funcs[n] = f
+	}
+	for n, f := range pathFuncs {
+		funcs[n] = f
+	}
+	return funcs
 }
@@ -0,0 +1,57 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gtemplate
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	in := map[string]string{
+		"path": "openconfig:/interfaces/interface[name=ethernet-1/1]/subinterfaces/subinterface[index=0]/state/counters",
+	}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "sprig", text: `{{ "hello" | upper | repeat 2 }}`, want: "HELLOHELLO"},
+		{name: "sprig_math", text: `{{ add 1 2 | mul 3 }}`, want: "9"},
+		{name: "gomplate_precedence", text: `{{ strings.Trim "-" "-a-" }}`, want: "a"},
+		{name: "pathElem", text: `{{ index . "path" | pathElem 1 }}`, want: "interface"},
+		{name: "pathElem_negative", text: `{{ index . "path" | pathElem -1 }}`, want: "counters"},
+		{name: "pathElem_out_of_range", text: `{{ index . "path" | pathElem 10 }}`, want: ""},
+		{name: "keyValue", text: `{{ index . "path" | keyValue "name" }}`, want: "ethernet-1/1"},
+		{name: "keyValue_missing", text: `{{ index . "path" | keyValue "id" }}`, want: ""},
+		{name: "stripOrigin", text: `{{ index . "path" | stripOrigin }}`, want: "/interfaces/interface[name=ethernet-1/1]/subinterfaces/subinterface[index=0]/state/counters"},
+		{name: "stripOrigin_no_origin", text: `{{ stripOrigin "/a/b" }}`, want: "/a/b"},
+		{name: "xpathCompare", text: `{{ xpathCompare "oc:/a/b[k1=1][k2=2]/" "a/b[k2=2][k1=1]" }}`, want: "true"},
+		{name: "xpathCompare_wildcard", text: `{{ xpathCompare "/a/b[k1=1]/c" "/a/*/c" }}`, want: "true"},
+		{name: "xpathCompare_wildcard_key", text: `{{ xpathCompare "/a/b[k1=1]/c" "/a/b[k1=*]/c" }}`, want: "true"},
+		{name: "xpathCompare_different", text: `{{ xpathCompare "/a/b[k1=1]" "/a/b[k1=2]" }}`, want: "false"},
+		{name: "xpathCompare_different_length", text: `{{ xpathCompare "/a/b" "/a/b/c" }}`, want: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := CreateTemplate(tt.name, tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := new(bytes.Buffer)
+			err = tpl.Execute(b, in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inputs
+
+import (
+	"context"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	defaultCheckpointTimeout       = 10 * time.Second
+	defaultCheckpointRetryInterval = 2 * time.Second
+)
+
+// CheckpointConfig ties the acknowledgment of the messages received by an input
+// to their delivery by the outputs.
+// When enabled, a message is written to the outputs with an outputs.Delivery
+// tracking it, and the message is acknowledged only once all outputs confirmed
+// the delivery of the resulting messages.
+// Outputs that do not track the deliveries are considered to deliver
+// the messages as soon as they are written.
+type CheckpointConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty" json:"enabled,omitempty"`
+	// max time to wait for the outputs to confirm the delivery of a message.
+	Timeout time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+	// time to wait before writing a message again after a failed delivery.
+	RetryInterval time.Duration `mapstructure:"retry-interval,omitempty" json:"retry-interval,omitempty"`
+}
+
+// the config is shared by the input workers,
+// the defaults are applied without modifying it.
+func (c *CheckpointConfig) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultCheckpointTimeout
+	}
+	return c.Timeout
+}
+
+func (c *CheckpointConfig) retryInterval() time.Duration {
+	if c.RetryInterval <= 0 {
+		return defaultCheckpointRetryInterval
+	}
+	return c.RetryInterval
+}
+
+// IsEnabled returns true if checkpointing is configured and enabled.
+func (c *CheckpointConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Deliver calls write for each output with a context carrying a new outputs.Delivery,
+// then waits for the outputs to confirm the delivery of the written messages.
+// If any of them fails, onError is called and the message is written again
+// to all outputs after RetryInterval.
+// Deliver returns nil once all outputs confirmed the delivery,
+// or the context error if ctx is done before that.
+func (c *CheckpointConfig) Deliver(ctx context.Context, outs []outputs.Output, write func(context.Context, outputs.Output), onError func(error)) error {
+	for {
+		err := c.deliverOnce(ctx, outs, write)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.retryInterval()):
+		}
+	}
+}
+
+func (c *CheckpointConfig) deliverOnce(ctx context.Context, outs []outputs.Output, write func(context.Context, outputs.Output)) error {
+	d := outputs.NewDelivery()
+	wctx := outputs.WithDelivery(ctx, d)
+	for _, o := range outs {
+		write(wctx, o)
+	}
+	fctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	return d.Wait(fctx)
+}
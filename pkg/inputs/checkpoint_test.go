@@ -0,0 +1,158 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inputs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+// asyncOutput confirms the delivery of the written events asynchronously,
+// after delay, failing the first failures writes of each event name.
+type asyncOutput struct {
+	m        sync.Mutex
+	delay    time.Duration
+	failures map[string]int
+	writes   map[string]int
+}
+
+func newAsyncOutput(delay time.Duration, failures map[string]int) *asyncOutput {
+	return &asyncOutput{delay: delay, failures: failures, writes: make(map[string]int)}
+}
+
+func (a *asyncOutput) Init(context.Context, string, map[string]interface{}, ...outputs.Option) error {
+	return nil
+}
+func (a *asyncOutput) Write(context.Context, proto.Message, outputs.Meta) {}
+func (a *asyncOutput) Close() error                                       { return nil }
+func (a *asyncOutput) RegisterMetrics(*prometheus.Registry)               {}
+func (a *asyncOutput) String() string                                     { return "async" }
+func (a *asyncOutput) SetLogger(*log.Logger)                              {}
+func (a *asyncOutput) SetName(string)                                     {}
+func (a *asyncOutput) SetClusterName(string)                              {}
+func (a *asyncOutput) SetTargetsConfig(map[string]*types.TargetConfig)    {}
+func (a *asyncOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (a *asyncOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	a.m.Lock()
+	a.writes[ev.Name]++
+	var err error
+	if a.writes[ev.Name] <= a.failures[ev.Name] {
+		err = errors.New("delivery failed")
+	}
+	a.m.Unlock()
+	d := outputs.DeliveryFrom(ctx)
+	d.Add(1)
+	go func() {
+		time.Sleep(a.delay)
+		d.Done(err)
+	}()
+}
+
+func (a *asyncOutput) numWrites(name string) int {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.writes[name]
+}
+
+func writeEvent(name string) func(context.Context, outputs.Output) {
+	return func(ctx context.Context, o outputs.Output) {
+		o.WriteEvent(ctx, &formatters.EventMsg{Name: name})
+	}
+}
+
+func TestCheckpointDeliver(t *testing.T) {
+	c := &CheckpointConfig{Enabled: true, RetryInterval: time.Millisecond}
+	o1 := newAsyncOutput(time.Millisecond, nil)
+	o2 := newAsyncOutput(time.Millisecond, map[string]int{"ev": 2})
+	var retries int
+	err := c.Deliver(context.Background(), []outputs.Output{o1, o2}, writeEvent("ev"),
+		func(error) { retries++ },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retries != 2 {
+		t.Errorf("expected 2 retries, got %d", retries)
+	}
+	// the message is written again to all outputs on each attempt
+	if o1.numWrites("ev") != 3 || o2.numWrites("ev") != 3 {
+		t.Errorf("expected 3 writes per output, got %d and %d", o1.numWrites("ev"), o2.numWrites("ev"))
+	}
+}
+
+func TestCheckpointDeliverConcurrent(t *testing.T) {
+	c := &CheckpointConfig{Enabled: true, RetryInterval: time.Millisecond}
+	// both messages are written to the same output, the first delivery of
+	// the message "failed" fails while the message "ok" is still pending.
+	o := newAsyncOutput(50*time.Millisecond, map[string]int{"failed": 1})
+	var okRetries, failedRetries atomic.Int64
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for name, retries := range map[string]*atomic.Int64{"ok": &okRetries, "failed": &failedRetries} {
+		wg.Add(1)
+		go func(name string, retries *atomic.Int64) {
+			defer wg.Done()
+			errs <- c.Deliver(context.Background(), []outputs.Output{o}, writeEvent(name),
+				func(error) { retries.Add(1) },
+			)
+		}(name, retries)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := okRetries.Load(); n != 0 {
+		t.Errorf("the delivered message must not be retried, got %d retries", n)
+	}
+	if n := failedRetries.Load(); n != 1 {
+		t.Errorf("expected the failed message to be retried once, got %d retries", n)
+	}
+	if o.numWrites("ok") != 1 || o.numWrites("failed") != 2 {
+		t.Errorf("unexpected writes: ok=%d, failed=%d", o.numWrites("ok"), o.numWrites("failed"))
+	}
+}
+
+func TestCheckpointDeliverCanceled(t *testing.T) {
+	c := &CheckpointConfig{Enabled: true, RetryInterval: time.Millisecond}
+	o := newAsyncOutput(time.Millisecond, map[string]int{"ev": 1 << 30})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := c.Deliver(ctx, []outputs.Output{o}, writeEvent("ev"), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+}
+
+func TestCheckpointIsEnabled(t *testing.T) {
+	var c *CheckpointConfig
+	if c.IsEnabled() {
+		t.Errorf("a nil checkpoint config must be disabled")
+	}
+	if (&CheckpointConfig{}).IsEnabled() {
+		t.Errorf("expected a disabled checkpoint config")
+	}
+}
@@ -0,0 +1,21 @@
+package input_plugin
+
+import (
+	"net/rpc"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/openconfig/gnmic/pkg/inputs"
+)
+
+type InputPlugin struct {
+	Impl inputs.Input
+}
+
+func (p *InputPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return newInputRPCServer(p.Impl), nil
+}
+
+func (p *InputPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &InputRPC{client: c}, nil
+}
@@ -0,0 +1,325 @@
+package input_plugin
+
+import (
+	"context"
+	"encoding/gob"
+	"io"
+	"log"
+	"net/rpc"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/inputs"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	inputType       = "input-plugin"
+	loggingPrefix   = "[" + inputType + "] "
+	readWaitTime    = time.Second
+	readBatchSize   = 100
+	readChannelSize = 1000
+	readRetryWait   = time.Second
+)
+
+type StartArgs struct {
+	Name string
+	Cfg  map[string]interface{}
+}
+
+type ReadArgs struct{}
+
+type ReadResponse struct {
+	Messages []*Message
+}
+
+// Message is a single message received by the plugin input.
+// It is either a proto.Message marshaled as an anypb.Any
+// with its metadata, or an event message.
+type Message struct {
+	Msg   []byte
+	Meta  outputs.Meta
+	Event *formatters.EventMsg
+}
+
+type (
+	StartResponse struct{}
+	CloseResponse struct{}
+)
+
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// inputRPCServer runs in the plugin process.
+// It starts the input with a single output which queues the received
+// messages until they are read by gNMIc's main process.
+type inputRPCServer struct {
+	Impl inputs.Input
+	msgs chan *Message
+}
+
+func newInputRPCServer(impl inputs.Input) *inputRPCServer {
+	return &inputRPCServer{
+		Impl: impl,
+		msgs: make(chan *Message, readChannelSize),
+	}
+}
+
+func (s *inputRPCServer) Start(args *StartArgs, resp *StartResponse) error {
+	cfg := make(map[string]interface{}, len(args.Cfg))
+	for k, v := range args.Cfg {
+		switch k {
+		// outputs and event processors are handled by gNMIc's main process
+		case "outputs", "event-processors":
+		default:
+			cfg[k] = v
+		}
+	}
+	return s.Impl.Start(context.Background(), args.Name, cfg,
+		inputs.WithOutputs(map[string]outputs.Output{inputType: &queueOutput{msgs: s.msgs}}),
+	)
+}
+
+// Read returns the queued messages, it waits up to readWaitTime
+// for the first message to be available.
+func (s *inputRPCServer) Read(args *ReadArgs, resp *ReadResponse) error {
+	timer := time.NewTimer(readWaitTime)
+	defer timer.Stop()
+	select {
+	case m := <-s.msgs:
+		resp.Messages = append(resp.Messages, m)
+	case <-timer.C:
+		return nil
+	}
+	for len(resp.Messages) < readBatchSize {
+		select {
+		case m := <-s.msgs:
+			resp.Messages = append(resp.Messages, m)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *inputRPCServer) Close(args interface{}, resp *CloseResponse) error {
+	return s.Impl.Close()
+}
+
+// InputRPC runs in gNMIc's main process and implements the inputs.Input interface.
+// It periodically reads the messages received by the plugin and
+// writes them to the configured outputs.
+type InputRPC struct {
+	client *rpc.Client
+	// set if the plugin could not be started
+	err     error
+	name    string
+	cfg     *config
+	logger  *log.Logger
+	cfn     context.CancelFunc
+	outputs []outputs.Output
+	evps    []formatters.EventProcessor
+}
+
+type config struct {
+	Outputs         []string `mapstructure:"outputs,omitempty"`
+	EventProcessors []string `mapstructure:"event-processors,omitempty"`
+	Debug           bool     `mapstructure:"debug,omitempty"`
+}
+
+// Unavailable returns an input plugin client which fails to start with err.
+// It is used when the plugin process could not be started.
+func Unavailable(err error) *InputRPC {
+	return &InputRPC{err: err}
+}
+
+func (i *InputRPC) Start(ctx context.Context, name string, cfg map[string]interface{}, opts ...inputs.Option) error {
+	if i.err != nil {
+		return i.err
+	}
+	i.name = name
+	i.logger = log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags)
+	i.cfg = new(config)
+	err := outputs.DecodeConfig(cfg, i.cfg)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		if err := opt(i); err != nil {
+			return err
+		}
+	}
+	err = i.client.Call("Plugin.Start", &StartArgs{Name: name, Cfg: cfg}, &StartResponse{})
+	if err != nil {
+		return err
+	}
+	ctx, i.cfn = context.WithCancel(ctx)
+	go i.read(ctx)
+	return nil
+}
+
+func (i *InputRPC) read(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			rsp := new(ReadResponse)
+			err := i.client.Call("Plugin.Read", &ReadArgs{}, rsp)
+			if err != nil {
+				i.logger.Print("RPC client call error: ", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(readRetryWait):
+				}
+				continue
+			}
+			for _, m := range rsp.Messages {
+				i.handleMessage(ctx, m)
+			}
+		}
+	}
+}
+
+func (i *InputRPC) handleMessage(ctx context.Context, m *Message) {
+	if m.Event != nil {
+		evMsgs := []*formatters.EventMsg{m.Event}
+		for _, p := range i.evps {
+			evMsgs = p.Apply(evMsgs...)
+		}
+		for _, o := range i.outputs {
+			for _, ev := range evMsgs {
+				o.WriteEvent(ctx, ev)
+			}
+		}
+		return
+	}
+	anyMsg := new(anypb.Any)
+	err := proto.Unmarshal(m.Msg, anyMsg)
+	if err != nil {
+		if i.cfg.Debug {
+			i.logger.Printf("failed to unmarshal proto msg: %v", err)
+		}
+		return
+	}
+	protoMsg, err := anyMsg.UnmarshalNew()
+	if err != nil {
+		if i.cfg.Debug {
+			i.logger.Printf("failed to unmarshal proto msg: %v", err)
+		}
+		return
+	}
+	for _, o := range i.outputs {
+		o.Write(ctx, protoMsg, m.Meta)
+	}
+}
+
+func (i *InputRPC) Close() error {
+	if i.cfn != nil {
+		i.cfn()
+	}
+	if i.client == nil {
+		return nil
+	}
+	return i.client.Call("Plugin.Close", new(interface{}), &CloseResponse{})
+}
+
+func (i *InputRPC) SetLogger(logger *log.Logger) {
+	if logger != nil && i.logger != nil {
+		i.logger.SetOutput(logger.Writer())
+		i.logger.SetFlags(logger.Flags())
+	}
+}
+
+func (i *InputRPC) SetOutputs(outs map[string]outputs.Output) {
+	if len(i.cfg.Outputs) == 0 {
+		for _, o := range outs {
+			i.outputs = append(i.outputs, o)
+		}
+		return
+	}
+	for _, name := range i.cfg.Outputs {
+		if o, ok := outs[name]; ok {
+			i.outputs = append(i.outputs, o)
+		}
+	}
+}
+
+func (i *InputRPC) SetEventProcessors(ps map[string]map[string]interface{}, logger *log.Logger, tcs map[string]*types.TargetConfig, acts map[string]map[string]interface{}) error {
+	var err error
+	i.evps, err = formatters.MakeEventProcessors(
+		logger,
+		i.cfg.EventProcessors,
+		ps,
+		tcs,
+		acts,
+	)
+	return err
+}
+
+func (i *InputRPC) SetName(string) {}
+
+// queueOutput is the output given to the input running in the plugin process,
+// it queues the messages written to it until they are read by gNMIc's main process.
+type queueOutput struct {
+	msgs chan *Message
+}
+
+func (q *queueOutput) Init(context.Context, string, map[string]interface{}, ...outputs.Option) error {
+	return nil
+}
+
+func (q *queueOutput) Write(ctx context.Context, msg proto.Message, meta outputs.Meta) {
+	if msg == nil {
+		return
+	}
+	anyMsg, err := anypb.New(msg)
+	if err != nil {
+		return
+	}
+	b, err := proto.Marshal(anyMsg)
+	if err != nil {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case q.msgs <- &Message{Msg: b, Meta: meta}:
+	}
+}
+
+func (q *queueOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case q.msgs <- &Message{Event: ev}:
+	}
+}
+
+func (q *queueOutput) Close() error { return nil }
+
+func (q *queueOutput) RegisterMetrics(*prometheus.Registry) {}
+
+func (q *queueOutput) String() string { return inputType }
+
+func (q *queueOutput) SetLogger(*log.Logger) {}
+
+func (q *queueOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (q *queueOutput) SetName(string) {}
+
+func (q *queueOutput) SetClusterName(string) {}
+
+func (q *queueOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
@@ -0,0 +1,190 @@
+package input_plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/inputs"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+// testInput is the input implementation running on the plugin side.
+type testInput struct {
+	cfg     map[string]interface{}
+	outputs map[string]outputs.Output
+}
+
+func (i *testInput) Start(_ context.Context, _ string, cfg map[string]interface{}, opts ...inputs.Option) error {
+	i.cfg = cfg
+	for _, opt := range opts {
+		if err := opt(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *testInput) Close() error                              { return nil }
+func (i *testInput) SetLogger(*log.Logger)                     {}
+func (i *testInput) SetOutputs(outs map[string]outputs.Output) { i.outputs = outs }
+func (i *testInput) SetName(string)                            {}
+func (i *testInput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+// testOutput collects the messages written by the input in gNMIc's main process.
+type testOutput struct {
+	m      sync.Mutex
+	msgs   []proto.Message
+	events []*formatters.EventMsg
+}
+
+func (o *testOutput) Init(context.Context, string, map[string]interface{}, ...outputs.Option) error {
+	return nil
+}
+
+func (o *testOutput) Write(_ context.Context, m proto.Message, _ outputs.Meta) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.msgs = append(o.msgs, m)
+}
+
+func (o *testOutput) WriteEvent(_ context.Context, ev *formatters.EventMsg) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.events = append(o.events, ev)
+}
+
+func (o *testOutput) counts() (int, int) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	return len(o.msgs), len(o.events)
+}
+
+func (o *testOutput) Close() error                         { return nil }
+func (o *testOutput) RegisterMetrics(*prometheus.Registry) {}
+func (o *testOutput) String() string                       { return "test" }
+func (o *testOutput) SetLogger(*log.Logger)                {}
+func (o *testOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+func (o *testOutput) SetName(string)                                  {}
+func (o *testOutput) SetClusterName(string)                           {}
+func (o *testOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+
+func newTestInputRPC(t *testing.T, srv *inputRPCServer) *InputRPC {
+	t.Helper()
+	rs := rpc.NewServer()
+	err := rs.RegisterName("Plugin", srv)
+	if err != nil {
+		t.Fatalf("failed to register RPC server: %v", err)
+	}
+	sc, cc := net.Pipe()
+	go rs.ServeConn(sc)
+	client := rpc.NewClient(cc)
+	t.Cleanup(func() { client.Close() })
+	return &InputRPC{client: client}
+}
+
+func TestInputRPCRoundTrip(t *testing.T) {
+	impl := &testInput{}
+	in := newTestInputRPC(t, newInputRPCServer(impl))
+	out := &testOutput{}
+	err := in.Start(context.Background(), "in1",
+		map[string]interface{}{
+			"type":    "test",
+			"address": "localhost:4222",
+			"outputs": []string{"out1"},
+		},
+		inputs.WithOutputs(map[string]outputs.Output{"out1": out, "out2": &testOutput{}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to start input: %v", err)
+	}
+	defer in.Close()
+	if _, ok := impl.cfg["outputs"]; ok {
+		t.Errorf("outputs must not be sent to the plugin: %v", impl.cfg)
+	}
+	if impl.cfg["address"] != "localhost:4222" {
+		t.Errorf("unexpected plugin config %v", impl.cfg)
+	}
+	if len(in.outputs) != 1 {
+		t.Fatalf("expected 1 output, got %d", len(in.outputs))
+	}
+	qo := impl.outputs[inputType]
+	if qo == nil {
+		t.Fatalf("the plugin input was not given the queue output")
+	}
+	numMsgs := readBatchSize + readBatchSize/2
+	for i := 0; i < numMsgs; i++ {
+		qo.Write(context.Background(), &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+		}, outputs.Meta{"source": fmt.Sprintf("r%d", i)})
+	}
+	qo.WriteEvent(context.Background(), &formatters.EventMsg{Name: "ev1"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		nm, ne := out.counts()
+		if nm == numMsgs && ne == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d messages and %d events, expected %d and 1", nm, ne, numMsgs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInputRPCServerReadBatching(t *testing.T) {
+	srv := newInputRPCServer(&testInput{})
+	numMsgs := readBatchSize + readBatchSize/2
+	for i := 0; i < numMsgs; i++ {
+		srv.msgs <- &Message{Event: &formatters.EventMsg{Name: "ev"}}
+	}
+	for _, want := range []int{readBatchSize, readBatchSize / 2} {
+		rsp := new(ReadResponse)
+		if err := srv.Read(&ReadArgs{}, rsp); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if len(rsp.Messages) != want {
+			t.Errorf("got a batch of %d messages, expected %d", len(rsp.Messages), want)
+		}
+	}
+	start := time.Now()
+	rsp := new(ReadResponse)
+	if err := srv.Read(&ReadArgs{}, rsp); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(rsp.Messages) != 0 {
+		t.Errorf("expected an empty batch, got %d messages", len(rsp.Messages))
+	}
+	if d := time.Since(start); d < readWaitTime {
+		t.Errorf("empty read returned after %s, expected %s", d, readWaitTime)
+	}
+}
+
+func TestInputRPCUnavailable(t *testing.T) {
+	perr := errors.New("plugin not started")
+	in := Unavailable(perr)
+	err := in.Start(context.Background(), "in1", nil)
+	if !errors.Is(err, perr) {
+		t.Errorf("got error %v, expected %v", err, perr)
+	}
+	if err = in.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
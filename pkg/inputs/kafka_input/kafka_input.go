@@ -41,10 +41,9 @@ const (
 	defaultRecoveryWaitTime  = 2 * time.Second
 	defaultAddress           = "localhost:9092"
 	defaultGroupID           = "gnmic-consumers"
+	defaultVersion           = "2.5.0"
 )
 
-var defaultVersion = sarama.V2_5_0_0
-
 var openSquareBracket = []byte("[")
 var openCurlyBrace = []byte("{")
 
@@ -60,31 +59,34 @@ func init() {
 
 // KafkaInput //
 type KafkaInput struct {
-	Cfg     *Config
-	cfn     context.CancelFunc
-	logger  sarama.StdLogger
-	wg      *sync.WaitGroup
-	outputs []outputs.Output
-	evps    []formatters.EventProcessor
+	Cfg      *Config
+	cfn      context.CancelFunc
+	logger   sarama.StdLogger
+	wg       *sync.WaitGroup
+	outputs  []outputs.Output
+	evps     []formatters.EventProcessor
+	envelope *outputs.Envelope
 }
 
 // Config //
 type Config struct {
-	Name              string           `mapstructure:"name,omitempty"`
-	Address           string           `mapstructure:"address,omitempty"`
-	Topics            string           `mapstructure:"topics,omitempty"`
-	SASL              *types.SASL      `mapstructure:"sasl,omitempty"`
-	TLS               *types.TLSConfig `mapstructure:"tls,omitempty"`
-	GroupID           string           `mapstructure:"group-id,omitempty"`
-	SessionTimeout    time.Duration    `mapstructure:"session-timeout,omitempty"`
-	HeartbeatInterval time.Duration    `mapstructure:"heartbeat-interval,omitempty"`
-	RecoveryWaitTime  time.Duration    `mapstructure:"recovery-wait-time,omitempty"`
-	Version           string           `mapstructure:"version,omitempty"`
-	Format            string           `mapstructure:"format,omitempty"`
-	Debug             bool             `mapstructure:"debug,omitempty"`
-	NumWorkers        int              `mapstructure:"num-workers,omitempty"`
-	Outputs           []string         `mapstructure:"outputs,omitempty"`
-	EventProcessors   []string         `mapstructure:"event-processors,omitempty"`
+	Name              string                    `mapstructure:"name,omitempty"`
+	Address           string                    `mapstructure:"address,omitempty" default:"localhost:9092"`
+	Topics            string                    `mapstructure:"topics,omitempty" default:"telemetry"`
+	SASL              *types.SASL               `mapstructure:"sasl,omitempty"`
+	TLS               *types.TLSConfig          `mapstructure:"tls,omitempty"`
+	GroupID           string                    `mapstructure:"group-id,omitempty" default:"gnmic-consumers"`
+	SessionTimeout    time.Duration             `mapstructure:"session-timeout,omitempty" default:"10s"`
+	HeartbeatInterval time.Duration             `mapstructure:"heartbeat-interval,omitempty" default:"3s"`
+	RecoveryWaitTime  time.Duration             `mapstructure:"recovery-wait-time,omitempty" default:"2s"`
+	Version           string                    `mapstructure:"version,omitempty" default:"2.5.0"`
+	Format            string                    `mapstructure:"format,omitempty" default:"event"`
+	Debug             bool                      `mapstructure:"debug,omitempty"`
+	NumWorkers        int                       `mapstructure:"num-workers,omitempty" default:"1"`
+	Outputs           []string                  `mapstructure:"outputs,omitempty"`
+	EventProcessors   []string                  `mapstructure:"event-processors,omitempty"`
+	Encryption        *outputs.EncryptionConfig `mapstructure:"encryption,omitempty"`
+	Checkpoint        *inputs.CheckpointConfig  `mapstructure:"checkpoint,omitempty"`
 
 	kafkaVersion sarama.KafkaVersion
 }
@@ -106,6 +108,10 @@ func (k *KafkaInput) Start(ctx context.Context, name string, cfg map[string]inte
 	if err != nil {
 		return err
 	}
+	k.envelope, err = outputs.NewEnvelope(k.Cfg.Encryption)
+	if err != nil {
+		return err
+	}
 	config, err := k.createConfig()
 	if err != nil {
 		return err
@@ -134,8 +140,9 @@ START:
 	k.logger.Printf("%s started consumer group %s", workerLogPrefix, k.Cfg.GroupID)
 	defer consumerGrp.Close()
 	cons := &consumer{
-		ready:   make(chan bool),
-		msgChan: make(chan *sarama.ConsumerMessage),
+		ready:      make(chan bool),
+		msgChan:    make(chan *claimedMsg),
+		checkpoint: k.Cfg.Checkpoint.IsEnabled(),
 	}
 	go func() {
 		var err error
@@ -159,8 +166,18 @@ START:
 		select {
 		case <-ctx.Done():
 			return
-		case m := <-cons.msgChan:
+		case cm := <-cons.msgChan:
+			m := cm.ConsumerMessage
 			if len(m.Value) == 0 {
+				cm.markConsumed()
+				continue
+			}
+			m.Value, err = k.envelope.Open(m.Value)
+			if err != nil {
+				if k.Cfg.Debug {
+					k.logger.Printf("%s failed to decrypt msg, topic=%s, partition=%d: %v", workerLogPrefix, m.Topic, m.Partition, err)
+				}
+				cm.markConsumed()
 				continue
 			}
 			if k.Cfg.Debug {
@@ -172,6 +189,7 @@ START:
 				evMsgs := make([]*formatters.EventMsg, 1)
 				switch {
 				case len(m.Value) == 0:
+					cm.markConsumed()
 					continue
 				case m.Value[0] == openSquareBracket[0]:
 					err = json.Unmarshal(m.Value, &evMsgs)
@@ -182,6 +200,7 @@ START:
 					if k.Cfg.Debug {
 						k.logger.Printf("%s failed to unmarshal event msg: %v", workerLogPrefix, err)
 					}
+					cm.markConsumed()
 					continue
 				}
 
@@ -189,13 +208,11 @@ START:
 					evMsgs = p.Apply(evMsgs...)
 				}
 
-				go func() {
-					for _, o := range k.outputs {
-						for _, ev := range evMsgs {
-							o.WriteEvent(ctx, ev)
-						}
+				k.deliver(ctx, workerLogPrefix, cm, func(ctx context.Context, o outputs.Output) {
+					for _, ev := range evMsgs {
+						o.WriteEvent(ctx, ev)
 					}
-				}()
+				})
 			case "proto":
 				var protoMsg proto.Message
 				err = proto.Unmarshal(m.Value, protoMsg)
@@ -203,14 +220,13 @@ START:
 					if k.Cfg.Debug {
 						k.logger.Printf("%s failed to unmarshal proto msg: %v", workerLogPrefix, err)
 					}
+					cm.markConsumed()
 					continue
 				}
 				meta := outputs.Meta{}
-				go func() {
-					for _, o := range k.outputs {
-						o.Write(ctx, protoMsg, meta)
-					}
-				}()
+				k.deliver(ctx, workerLogPrefix, cm, func(ctx context.Context, o outputs.Output) {
+					o.Write(ctx, protoMsg, meta)
+				})
 			}
 		case err := <-consumerGrp.Errors():
 			k.logger.Printf("%s client=%s, consumer-group=%s error: %v", workerLogPrefix, config.ClientID, k.Cfg.GroupID, err)
@@ -220,6 +236,31 @@ START:
 	}
 }
 
+// deliver writes a received message to the outputs using write.
+// If checkpointing is enabled, it blocks until all the outputs confirmed
+// the delivery before marking the message as consumed, so that its offset
+// is committed only after it was durably written.
+func (k *KafkaInput) deliver(ctx context.Context, workerLogPrefix string, cm *claimedMsg, write func(context.Context, outputs.Output)) {
+	if !k.Cfg.Checkpoint.IsEnabled() {
+		go func() {
+			for _, o := range k.outputs {
+				write(ctx, o)
+			}
+		}()
+		return
+	}
+	err := k.Cfg.Checkpoint.Deliver(ctx, k.outputs, write, func(err error) {
+		k.logger.Printf("%s failed to deliver msg, topic=%s, partition=%d, offset=%d, retrying: %v",
+			workerLogPrefix, cm.Topic, cm.Partition, cm.Offset, err)
+	})
+	if err != nil {
+		// the input is stopping, the message is not marked
+		// and will be consumed again.
+		return
+	}
+	cm.markConsumed()
+}
+
 func (k *KafkaInput) Close() error {
 	k.cfn()
 	k.wg.Wait()
@@ -277,14 +318,12 @@ func (k *KafkaInput) SetEventProcessors(ps map[string]map[string]interface{}, lo
 
 func (k *KafkaInput) setDefaults() error {
 	var err error
-	if k.Cfg.Version != "" {
-		k.Cfg.kafkaVersion, err = sarama.ParseKafkaVersion(k.Cfg.Version)
-		if err != nil {
-			return err
-		}
-	} else {
-		k.Cfg.kafkaVersion = defaultVersion
-
+	if k.Cfg.Version == "" {
+		k.Cfg.Version = defaultVersion
+	}
+	k.Cfg.kafkaVersion, err = sarama.ParseKafkaVersion(k.Cfg.Version)
+	if err != nil {
+		return err
 	}
 	if k.Cfg.Format == "" {
 		k.Cfg.Format = defaultFormat
@@ -380,7 +419,24 @@ func (k *KafkaInput) createConfig() (*sarama.Config, error) {
 // consumer represents a Sarama consumer group consumer
 type consumer struct {
 	ready   chan bool
-	msgChan chan *sarama.ConsumerMessage
+	msgChan chan *claimedMsg
+	// if true, the messages are marked by the worker
+	// once delivered instead of when claimed.
+	checkpoint bool
+}
+
+// claimedMsg is a message claimed by the consumer along with
+// the session it belongs to, if it must be marked by the worker.
+type claimedMsg struct {
+	*sarama.ConsumerMessage
+	session sarama.ConsumerGroupSession
+}
+
+// markConsumed marks the message as consumed if it was not marked when claimed.
+func (cm *claimedMsg) markConsumed() {
+	if cm.session != nil {
+		cm.session.MarkMessage(cm.ConsumerMessage, "")
+	}
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim
@@ -398,7 +454,11 @@ func (consumer *consumer) Cleanup(sarama.ConsumerGroupSession) error {
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages().
 func (consumer *consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for message := range claim.Messages() {
-		consumer.msgChan <- message
+		if consumer.checkpoint {
+			consumer.msgChan <- &claimedMsg{ConsumerMessage: message, session: session}
+			continue
+		}
+		consumer.msgChan <- &claimedMsg{ConsumerMessage: message}
 		session.MarkMessage(message, "")
 	}
 	return nil
@@ -11,6 +11,7 @@ package nats_input
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -58,27 +59,30 @@ type NatsInput struct {
 	cfn    context.CancelFunc
 	logger *log.Logger
 
-	wg      *sync.WaitGroup
-	outputs []outputs.Output
-	evps    []formatters.EventProcessor
+	wg       *sync.WaitGroup
+	outputs  []outputs.Output
+	evps     []formatters.EventProcessor
+	envelope *outputs.Envelope
 }
 
 // Config //
 type Config struct {
-	Name            string           `mapstructure:"name,omitempty"`
-	Address         string           `mapstructure:"address,omitempty"`
-	Subject         string           `mapstructure:"subject,omitempty"`
-	Queue           string           `mapstructure:"queue,omitempty"`
-	Username        string           `mapstructure:"username,omitempty"`
-	Password        string           `mapstructure:"password,omitempty"`
-	ConnectTimeWait time.Duration    `mapstructure:"connect-time-wait,omitempty"`
-	TLS             *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	Format          string           `mapstructure:"format,omitempty"`
-	Debug           bool             `mapstructure:"debug,omitempty"`
-	NumWorkers      int              `mapstructure:"num-workers,omitempty"`
-	BufferSize      int              `mapstructure:"buffer-size,omitempty"`
-	Outputs         []string         `mapstructure:"outputs,omitempty"`
-	EventProcessors []string         `mapstructure:"event-processors,omitempty"`
+	Name            string                    `mapstructure:"name,omitempty"`
+	Address         string                    `mapstructure:"address,omitempty" default:"localhost:4222"`
+	Subject         string                    `mapstructure:"subject,omitempty" default:"telemetry"`
+	Queue           string                    `mapstructure:"queue,omitempty"`
+	Username        string                    `mapstructure:"username,omitempty"`
+	Password        string                    `mapstructure:"password,omitempty"`
+	ConnectTimeWait time.Duration             `mapstructure:"connect-time-wait,omitempty" default:"2s"`
+	TLS             *types.TLSConfig          `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	Format          string                    `mapstructure:"format,omitempty" default:"event"`
+	Debug           bool                      `mapstructure:"debug,omitempty"`
+	NumWorkers      int                       `mapstructure:"num-workers,omitempty" default:"1"`
+	BufferSize      int                       `mapstructure:"buffer-size,omitempty" default:"100"`
+	Outputs         []string                  `mapstructure:"outputs,omitempty"`
+	EventProcessors []string                  `mapstructure:"event-processors,omitempty"`
+	Encryption      *outputs.EncryptionConfig `mapstructure:"encryption,omitempty"`
+	Checkpoint      *inputs.CheckpointConfig  `mapstructure:"checkpoint,omitempty"`
 }
 
 // Init //
@@ -99,6 +103,10 @@ func (n *NatsInput) Start(ctx context.Context, name string, cfg map[string]inter
 	if err != nil {
 		return err
 	}
+	n.envelope, err = outputs.NewEnvelope(n.Cfg.Encryption)
+	if err != nil {
+		return err
+	}
 	n.ctx, n.cfn = context.WithCancel(ctx)
 	n.logger.Printf("input starting with config: %+v", n.Cfg)
 	n.wg.Add(n.Cfg.NumWorkers)
@@ -147,6 +155,15 @@ START:
 				goto START
 			}
 			if len(m.Data) == 0 {
+				n.ack(m)
+				continue
+			}
+			m.Data, err = n.envelope.Open(m.Data)
+			if err != nil {
+				if n.Cfg.Debug {
+					n.logger.Printf("%s failed to decrypt msg, subject=%s: %v", workerLogPrefix, m.Subject, err)
+				}
+				n.ack(m)
 				continue
 			}
 			if n.Cfg.Debug {
@@ -161,6 +178,7 @@ START:
 					if n.Cfg.Debug {
 						n.logger.Printf("%s failed to unmarshal event msg: %v", workerLogPrefix, err)
 					}
+					n.ack(m)
 					continue
 				}
 
@@ -168,13 +186,11 @@ START:
 					evMsgs = p.Apply(evMsgs...)
 				}
 
-				go func() {
-					for _, o := range n.outputs {
-						for _, ev := range evMsgs {
-							o.WriteEvent(ctx, ev)
-						}
+				n.deliver(ctx, workerLogPrefix, m, func(ctx context.Context, o outputs.Output) {
+					for _, ev := range evMsgs {
+						o.WriteEvent(ctx, ev)
 					}
-				}()
+				})
 			case "proto":
 				var protoMsg proto.Message
 				err = proto.Unmarshal(m.Data, protoMsg)
@@ -182,6 +198,7 @@ START:
 					if n.Cfg.Debug {
 						n.logger.Printf("failed to unmarshal proto msg: %v", err)
 					}
+					n.ack(m)
 					continue
 				}
 				meta := outputs.Meta{}
@@ -190,17 +207,53 @@ START:
 					meta["source"] = strings.ReplaceAll(subjectSections[1], "-", ".")
 					meta["subscription-name"] = subjectSections[2]
 				}
-				go func() {
-					for _, o := range n.outputs {
-						o.Write(ctx, protoMsg, meta)
-					}
-				}()
+				n.deliver(ctx, workerLogPrefix, m, func(ctx context.Context, o outputs.Output) {
+					o.Write(ctx, protoMsg, meta)
+				})
 			}
 
 		}
 	}
 }
 
+// deliver writes a received message to the outputs using write.
+// If checkpointing is enabled, it blocks until all the outputs confirmed
+// the delivery before acknowledging the message.
+// Only messages delivered by a JetStream consumer can be acknowledged,
+// messages published on core NATS subjects are not redelivered.
+func (n *NatsInput) deliver(ctx context.Context, workerLogPrefix string, m *nats.Msg, write func(context.Context, outputs.Output)) {
+	if !n.Cfg.Checkpoint.IsEnabled() {
+		go func() {
+			for _, o := range n.outputs {
+				write(ctx, o)
+			}
+		}()
+		return
+	}
+	err := n.Cfg.Checkpoint.Deliver(ctx, n.outputs, write, func(err error) {
+		n.logger.Printf("%s failed to deliver msg, subject=%s, retrying: %v", workerLogPrefix, m.Subject, err)
+	})
+	if err != nil {
+		// the input is stopping, ask for a redelivery.
+		if err := m.Nak(); err != nil && !errors.Is(err, nats.ErrMsgNoReply) && !errors.Is(err, nats.ErrNotJSMessage) {
+			n.logger.Printf("%s failed to nak msg, subject=%s: %v", workerLogPrefix, m.Subject, err)
+		}
+		return
+	}
+	n.ack(m)
+}
+
+// ack acknowledges a JetStream message if checkpointing is enabled.
+func (n *NatsInput) ack(m *nats.Msg) {
+	if !n.Cfg.Checkpoint.IsEnabled() {
+		return
+	}
+	err := m.Ack()
+	if err != nil && !errors.Is(err, nats.ErrMsgNoReply) && !errors.Is(err, nats.ErrNotJSMessage) {
+		n.logger.Printf("failed to ack msg, subject=%s: %v", m.Subject, err)
+	}
+}
+
 // Close //
 func (n *NatsInput) Close() error {
 	n.cfn()
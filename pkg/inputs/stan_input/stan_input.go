@@ -67,19 +67,19 @@ type StanInput struct {
 // Config //
 type Config struct {
 	Name            string           `mapstructure:"name,omitempty"`
-	Address         string           `mapstructure:"address,omitempty"`
-	Subject         string           `mapstructure:"subject,omitempty"`
+	Address         string           `mapstructure:"address,omitempty" default:"localhost:4222"`
+	Subject         string           `mapstructure:"subject,omitempty" default:"telemetry"`
 	Queue           string           `mapstructure:"queue,omitempty"`
 	Username        string           `mapstructure:"username,omitempty"`
 	Password        string           `mapstructure:"password,omitempty"`
-	ConnectTimeWait time.Duration    `mapstructure:"connect-time-wait,omitempty"`
+	ConnectTimeWait time.Duration    `mapstructure:"connect-time-wait,omitempty" default:"2s"`
 	TLS             *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	ClusterName     string           `mapstructure:"cluster-name,omitempty"`
-	PingInterval    int              `mapstructure:"ping-interval,omitempty"`
-	PingRetry       int              `mapstructure:"ping-retry,omitempty"`
-	Format          string           `mapstructure:"format,omitempty"`
+	ClusterName     string           `mapstructure:"cluster-name,omitempty" default:"test-cluster"`
+	PingInterval    int              `mapstructure:"ping-interval,omitempty" default:"5"`
+	PingRetry       int              `mapstructure:"ping-retry,omitempty" default:"2"`
+	Format          string           `mapstructure:"format,omitempty" default:"event"`
 	Debug           bool             `mapstructure:"debug,omitempty"`
-	NumWorkers      int              `mapstructure:"num-workers,omitempty"`
+	NumWorkers      int              `mapstructure:"num-workers,omitempty" default:"1"`
 	Outputs         []string         `mapstructure:"outputs,omitempty"`
 	EventProcessors []string         `mapstructure:"event-processors,omitempty"`
 }
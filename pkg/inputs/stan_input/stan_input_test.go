@@ -0,0 +1,26 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stan_input
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+)
+
+func TestSetDefaultsTags(t *testing.T) {
+	s := &StanInput{Cfg: &Config{}}
+	if err := s.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	// the queue defaults to the input name.
+	for _, m := range testutils.DefaultTagsMismatches(s.Cfg, "name", "queue") {
+		t.Error(m)
+	}
+}
@@ -351,11 +351,11 @@ func (d *dockerLoader) getTargets(ctx context.Context) (map[string]*types.Target
 						}
 						if fl.port != "" {
 							if !strings.Contains(fl.port, "=") {
-								tc.Address = fmt.Sprintf("%s:%s", tc.Address, fl.port)
+								tc.Address = net.JoinHostPort(tc.Address, fl.port)
 							} else {
 								portLabel := strings.Replace(fl.port, "label=", "", 1)
 								if p, ok := cont.Labels[portLabel]; ok {
-									tc.Address = fmt.Sprintf("%s:%s", tc.Address, p)
+									tc.Address = net.JoinHostPort(tc.Address, p)
 								}
 							}
 						}
@@ -377,11 +377,11 @@ func (d *dockerLoader) getTargets(ctx context.Context) (map[string]*types.Target
 							}
 							if fl.port != "" {
 								if !strings.Contains(fl.port, "=") {
-									tc.Address = fmt.Sprintf("%s:%s", tc.Address, fl.port)
+									tc.Address = net.JoinHostPort(tc.Address, fl.port)
 								} else {
 									portLabel := strings.Replace(fl.port, "label=", "", 1)
 									if p, ok := cont.Labels[portLabel]; ok {
-										tc.Address = fmt.Sprintf("%s:%s", tc.Address, p)
+										tc.Address = net.JoinHostPort(tc.Address, p)
 									}
 								}
 							}
@@ -408,7 +408,7 @@ func (d *dockerLoader) getTargets(ctx context.Context) (map[string]*types.Target
 										}
 									}
 									if ipAddr != "" && p.PublicPort != 0 {
-										tc.Address = fmt.Sprintf("%s:%d", ipAddr, p.PublicPort)
+										tc.Address = net.JoinHostPort(ipAddr, strconv.Itoa(int(p.PublicPort)))
 									}
 								}
 							}
@@ -430,7 +430,7 @@ func (d *dockerLoader) getTargets(ctx context.Context) (map[string]*types.Target
 									continue
 								}
 								if port != 0 {
-									tc.Address = fmt.Sprintf("%s:%d", tc.Address, port)
+									tc.Address = net.JoinHostPort(tc.Address, strconv.Itoa(int(port)))
 								}
 							}
 						}
@@ -11,6 +11,8 @@ package k8s_locker
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -134,7 +136,7 @@ func parseEndpoint(endpoint *corev1.Endpoints) ([]*lockers.Service, error) {
 			}
 			ls := &lockers.Service{
 				ID:      fmt.Sprintf("%s-api", targetName),
-				Address: fmt.Sprintf("%s:%d", addr.IP, port),
+				Address: net.JoinHostPort(addr.IP, strconv.Itoa(int(port))),
 				Tags: []string{
 					fmt.Sprintf("instance-name=%s", targetName),
 				},
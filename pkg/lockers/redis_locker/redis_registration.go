@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/go-redsync/redsync/v4"
@@ -215,10 +217,9 @@ func (k *redisLocker) GetServices(ctx context.Context, serviceName string, tags
 					discoveredServices[i] = &lockers.Service{
 						ID:   registration.ID,
 						Tags: registration.Tags,
-						Address: fmt.Sprintf(
-							"%s:%d",
+						Address: net.JoinHostPort(
 							registration.Address,
-							registration.Port,
+							strconv.Itoa(registration.Port),
 						),
 					}
 				}
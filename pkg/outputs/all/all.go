@@ -10,6 +10,7 @@ package all
 
 import (
 	_ "github.com/openconfig/gnmic/pkg/outputs/asciigraph_output"
+	_ "github.com/openconfig/gnmic/pkg/outputs/failover_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/file"
 	_ "github.com/openconfig/gnmic/pkg/outputs/gnmi_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/influxdb_output"
@@ -17,9 +18,11 @@ import (
 	_ "github.com/openconfig/gnmic/pkg/outputs/nats_outputs/jetstream"
 	_ "github.com/openconfig/gnmic/pkg/outputs/nats_outputs/nats"
 	_ "github.com/openconfig/gnmic/pkg/outputs/nats_outputs/stan"
+	_ "github.com/openconfig/gnmic/pkg/outputs/notification_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/prometheus_output/prometheus_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/prometheus_output/prometheus_write_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/snmp_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/tcp_output"
+	_ "github.com/openconfig/gnmic/pkg/outputs/tee_output"
 	_ "github.com/openconfig/gnmic/pkg/outputs/udp_output"
 )
@@ -42,12 +42,9 @@ const (
 	defaultRefreshTimer = time.Second
 	defaultPrecision    = 2
 	defaultTimeout      = 10 * time.Second
-)
-
-var (
-	defaultLabelColor   = asciigraph.Blue
-	defaultCaptionColor = asciigraph.Default
-	defaultAxisColor    = asciigraph.Default
+	defaultLabelColor   = "blue"
+	defaultCaptionColor = "default"
+	defaultAxisColor    = "default"
 )
 
 func init() {
@@ -78,6 +75,7 @@ type asciigraphOutput struct {
 	axisColor    asciigraph.AnsiColor
 	labelColor   asciigraph.AnsiColor
 	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
 
 	targetTpl *template.Template
 }
@@ -103,21 +101,22 @@ type cfg struct {
 	// The graph offset
 	Offset int `mapstructure:"offset,omitempty" json:"offset,omitempty"`
 	// The decimal point precision of the label values
-	Precision uint `mapstructure:"precision,omitempty" json:"precision,omitempty"`
+	Precision uint `mapstructure:"precision,omitempty" json:"precision,omitempty" default:"2"`
 	// The caption color
-	CaptionColor string `mapstructure:"caption-color,omitempty" json:"caption-color,omitempty"`
+	CaptionColor string `mapstructure:"caption-color,omitempty" json:"caption-color,omitempty" default:"default"`
 	// The axis color
-	AxisColor string `mapstructure:"axis-color,omitempty" json:"axis-color,omitempty"`
+	AxisColor string `mapstructure:"axis-color,omitempty" json:"axis-color,omitempty" default:"default"`
 	// The label color
-	LabelColor string `mapstructure:"label-color,omitempty" json:"label-color,omitempty"`
+	LabelColor string `mapstructure:"label-color,omitempty" json:"label-color,omitempty" default:"blue"`
 	// The graph refresh timer
-	RefreshTimer time.Duration `mapstructure:"refresh-timer,omitempty" json:"refresh-timer,omitempty"`
+	RefreshTimer time.Duration `mapstructure:"refresh-timer,omitempty" json:"refresh-timer,omitempty" default:"1s"`
 	// Add target the received subscribe responses
 	AddTarget string `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
 	//
 	TargetTemplate string `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
 	// list of event processors
-	EventProcessors []string `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	EventProcessors []string                 `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat    *formatters.NumberFormat `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
 	// enable extra logging
 	Debug bool `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 }
@@ -134,7 +133,11 @@ func (a *asciigraphOutput) SetEventProcessors(ps map[string]map[string]interface
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := a.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	a.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	a.evps, err = formatters.MakeEventProcessors(
 		logger,
 		a.cfg.EventProcessors,
@@ -145,6 +148,7 @@ func (a *asciigraphOutput) SetEventProcessors(ps map[string]map[string]interface
 	if err != nil {
 		return err
 	}
+	a.evps = append(a.evps, nfps...)
 	return nil
 }
 
@@ -184,6 +188,10 @@ func (a *asciigraphOutput) Init(ctx context.Context, name string, cfg map[string
 	if err != nil {
 		return err
 	}
+	err = a.getTermSize()
+	if err != nil {
+		return err
+	}
 	//
 	go a.graph(ctx)
 	a.logger.Printf("initialized asciigraph output: %s", a.String())
@@ -191,33 +199,32 @@ func (a *asciigraphOutput) Init(ctx context.Context, name string, cfg map[string
 }
 
 func (a *asciigraphOutput) setDefaults() error {
-	a.labelColor = defaultLabelColor
-	if a.cfg.LabelColor != "" {
-		if lc, ok := asciigraph.ColorNames[a.cfg.LabelColor]; ok {
-			a.labelColor = lc
-		} else {
-			return fmt.Errorf("unknown label color %s", a.cfg.LabelColor)
-		}
+	if a.cfg.LabelColor == "" {
+		a.cfg.LabelColor = defaultLabelColor
 	}
-
-	a.captionColor = defaultCaptionColor
-	if a.cfg.CaptionColor != "" {
-		if lc, ok := asciigraph.ColorNames[a.cfg.CaptionColor]; ok {
-			a.captionColor = lc
-		} else {
-			return fmt.Errorf("unknown caption color %s", a.cfg.CaptionColor)
-		}
+	lc, ok := asciigraph.ColorNames[a.cfg.LabelColor]
+	if !ok {
+		return fmt.Errorf("unknown label color %s", a.cfg.LabelColor)
 	}
+	a.labelColor = lc
 
-	a.axisColor = defaultAxisColor
-	if a.cfg.AxisColor != "" {
-		if lc, ok := asciigraph.ColorNames[a.cfg.AxisColor]; ok {
-			a.axisColor = lc
-		} else {
-			return fmt.Errorf("unknown axis color %s", a.cfg.AxisColor)
-		}
+	if a.cfg.CaptionColor == "" {
+		a.cfg.CaptionColor = defaultCaptionColor
+	}
+	lc, ok = asciigraph.ColorNames[a.cfg.CaptionColor]
+	if !ok {
+		return fmt.Errorf("unknown caption color %s", a.cfg.CaptionColor)
+	}
+	a.captionColor = lc
 
+	if a.cfg.AxisColor == "" {
+		a.cfg.AxisColor = defaultAxisColor
+	}
+	lc, ok = asciigraph.ColorNames[a.cfg.AxisColor]
+	if !ok {
+		return fmt.Errorf("unknown axis color %s", a.cfg.AxisColor)
 	}
+	a.axisColor = lc
 
 	if a.cfg.RefreshTimer <= 0 {
 		a.cfg.RefreshTimer = defaultRefreshTimer
@@ -225,8 +232,7 @@ func (a *asciigraphOutput) setDefaults() error {
 	if a.cfg.Precision <= 0 {
 		a.cfg.Precision = defaultPrecision
 	}
-
-	return a.getTermSize()
+	return nil
 }
 
 // Write //
@@ -240,7 +246,7 @@ func (a *asciigraphOutput) Write(ctx context.Context, rsp proto.Message, meta ou
 		a.logger.Printf("failed to add target to the response: %v", err)
 		return
 	}
-	evs, err := formatters.ResponseToEventMsgs(meta["subscription-name"], subRsp, meta, a.evps...)
+	evs, err := formatters.ResponseToEventMsgs(meta["subscription-name"], subRsp, meta, a.evpOverrides.Select(meta, a.evps)...)
 	if err != nil {
 		a.logger.Printf("failed to convert messages to events: %v", err)
 		return
@@ -0,0 +1,296 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	binaryValuesConfigKey = "binary-values"
+
+	binaryEncodingBase64 = "base64"
+	binaryEncodingHex    = "hex"
+	binaryEncodingDrop   = "drop"
+
+	maxSizeActionTruncate = "truncate"
+	maxSizeActionDrop     = "drop"
+)
+
+// BinaryValuesConfig defines how the BYTES values, and the ASCII values
+// that are not valid UTF-8, are written to an output.
+// Such values are replaced by a string holding their encoded content,
+// so that they are not mangled by the processors and the text based formats.
+type BinaryValuesConfig struct {
+	// one of base64, hex or drop, defaults to base64.
+	Encoding string `mapstructure:"encoding,omitempty" json:"encoding,omitempty"`
+	// string prepended to the encoded values, e.g `base64:`.
+	Prefix string `mapstructure:"prefix,omitempty" json:"prefix,omitempty"`
+	// maximum size in bytes of a BYTES or ASCII value, 0 means no limit.
+	MaxSize int `mapstructure:"max-size,omitempty" json:"max-size,omitempty"`
+	// one of truncate or drop, defaults to truncate.
+	MaxSizeAction string `mapstructure:"max-size-action,omitempty" json:"max-size-action,omitempty"`
+}
+
+// binaryValuesOutput wraps an Output and encodes the binary values
+// of the written messages and events before forwarding them.
+type binaryValuesOutput struct {
+	Output
+	cfg *BinaryValuesConfig
+}
+
+// NewBinaryValuesOutput returns the output o wrapped with the binary values policy
+// found under the `binary-values` key of the output config cfg.
+// If the config does not define a policy, o is returned unchanged.
+func NewBinaryValuesOutput(o Output, cfg map[string]interface{}, _ *log.Logger) (Output, error) {
+	bvc, ok := cfg[binaryValuesConfigKey]
+	if !ok || bvc == nil {
+		return o, nil
+	}
+	bv := new(BinaryValuesConfig)
+	err := DecodeConfig(bvc, bv)
+	if err != nil {
+		return nil, err
+	}
+	switch bv.Encoding {
+	case "":
+		bv.Encoding = binaryEncodingBase64
+	case binaryEncodingBase64, binaryEncodingHex, binaryEncodingDrop:
+	default:
+		return nil, fmt.Errorf("unknown binary-values encoding %q, must be one of %q, %q or %q",
+			bv.Encoding, binaryEncodingBase64, binaryEncodingHex, binaryEncodingDrop)
+	}
+	switch bv.MaxSizeAction {
+	case "":
+		bv.MaxSizeAction = maxSizeActionTruncate
+	case maxSizeActionTruncate, maxSizeActionDrop:
+	default:
+		return nil, fmt.Errorf("unknown binary-values max-size-action %q, must be one of %q or %q",
+			bv.MaxSizeAction, maxSizeActionTruncate, maxSizeActionDrop)
+	}
+	if bv.MaxSize < 0 {
+		return nil, fmt.Errorf("binary-values max-size cannot be negative")
+	}
+	return &binaryValuesOutput{
+		Output: o,
+		cfg:    bv,
+	}, nil
+}
+
+func (b *binaryValuesOutput) Write(ctx context.Context, rsp proto.Message, meta Meta) {
+	if rsp == nil {
+		return
+	}
+	b.Output.Write(ctx, b.encodeMsg(rsp), meta)
+}
+
+func (b *binaryValuesOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	b.Output.WriteEvent(ctx, b.encodeEvent(ev))
+}
+
+// encodeMsg returns a copy of the message rsp with its binary values encoded.
+// The message is returned unchanged if it does not contain any.
+func (b *binaryValuesOutput) encodeMsg(rsp proto.Message) proto.Message {
+	var notifs []*gnmi.Notification
+	switch rsp := rsp.(type) {
+	case *gnmi.SubscribeResponse:
+		if rsp.GetUpdate() != nil {
+			notifs = []*gnmi.Notification{rsp.GetUpdate()}
+		}
+	case *gnmi.GetResponse:
+		notifs = rsp.GetNotification()
+	}
+	if !b.needsEncoding(notifs) {
+		return rsp
+	}
+	rsp = proto.Clone(rsp)
+	switch rsp := rsp.(type) {
+	case *gnmi.SubscribeResponse:
+		notifs = []*gnmi.Notification{rsp.GetUpdate()}
+	case *gnmi.GetResponse:
+		notifs = rsp.GetNotification()
+	}
+	for _, n := range notifs {
+		upds := n.Update[:0]
+		for _, upd := range n.GetUpdate() {
+			v, ok := b.encodeValue(upd.GetVal())
+			if !ok {
+				continue
+			}
+			upd.Val = v
+			upds = append(upds, upd)
+		}
+		n.Update = upds
+	}
+	return rsp
+}
+
+func (b *binaryValuesOutput) needsEncoding(notifs []*gnmi.Notification) bool {
+	for _, n := range notifs {
+		for _, upd := range n.GetUpdate() {
+			if b.isBinary(upd.GetVal()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBinary reports whether tv is a value handled by the binary values policy.
+func (b *binaryValuesOutput) isBinary(tv *gnmi.TypedValue) bool {
+	switch tv := tv.GetValue().(type) {
+	case *gnmi.TypedValue_BytesVal:
+		return true
+	case *gnmi.TypedValue_AsciiVal:
+		return !utf8.ValidString(tv.AsciiVal) || (b.cfg.MaxSize > 0 && len(tv.AsciiVal) > b.cfg.MaxSize)
+	}
+	return false
+}
+
+// encodeValue returns the value replacing tv and false if the update must be dropped.
+func (b *binaryValuesOutput) encodeValue(tv *gnmi.TypedValue) (*gnmi.TypedValue, bool) {
+	if !b.isBinary(tv) {
+		return tv, true
+	}
+	switch tv := tv.GetValue().(type) {
+	case *gnmi.TypedValue_BytesVal:
+		s, ok := b.encodeBytes(tv.BytesVal)
+		if !ok {
+			return nil, false
+		}
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: s}}, true
+	case *gnmi.TypedValue_AsciiVal:
+		s, ok := b.encodeString(tv.AsciiVal)
+		if !ok {
+			return nil, false
+		}
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: s}}, true
+	}
+	return tv, true
+}
+
+// encodeBytes encodes the binary value v.
+func (b *binaryValuesOutput) encodeBytes(v []byte) (string, bool) {
+	if b.cfg.MaxSize > 0 && len(v) > b.cfg.MaxSize {
+		if b.cfg.MaxSizeAction == maxSizeActionDrop {
+			return "", false
+		}
+		v = v[:b.cfg.MaxSize]
+	}
+	switch b.cfg.Encoding {
+	case binaryEncodingHex:
+		return b.cfg.Prefix + hex.EncodeToString(v), true
+	case binaryEncodingDrop:
+		return "", false
+	default:
+		return b.cfg.Prefix + base64.StdEncoding.EncodeToString(v), true
+	}
+}
+
+// encodeString returns the string v as is if it is valid UTF-8,
+// truncated on a character boundary if it is above max-size.
+// The invalid UTF-8 strings are encoded as binary values.
+func (b *binaryValuesOutput) encodeString(v string) (string, bool) {
+	if !utf8.ValidString(v) {
+		return b.encodeBytes([]byte(v))
+	}
+	if b.cfg.MaxSize > 0 && len(v) > b.cfg.MaxSize {
+		if b.cfg.MaxSizeAction == maxSizeActionDrop {
+			return "", false
+		}
+		n := b.cfg.MaxSize
+		for n > 0 && !utf8.RuneStart(v[n]) {
+			n--
+		}
+		v = v[:n]
+	}
+	return v, true
+}
+
+// encodeEvent returns a copy of the event ev with its binary values encoded.
+// The event is returned unchanged if it does not contain any.
+func (b *binaryValuesOutput) encodeEvent(ev *formatters.EventMsg) *formatters.EventMsg {
+	var found bool
+	for _, v := range ev.Values {
+		if b.isBinaryEventValue(v) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ev
+	}
+	ev = formatters.CopyEventMsg(ev)
+	for k, v := range ev.Values {
+		if !b.isBinaryEventValue(v) {
+			continue
+		}
+		var s string
+		var ok bool
+		switch v := v.(type) {
+		case []byte:
+			s, ok = b.encodeBytes(v)
+		case string:
+			s, ok = b.encodeString(v)
+		}
+		if !ok {
+			delete(ev.Values, k)
+			continue
+		}
+		ev.Values[k] = s
+	}
+	return ev
+}
+
+func (b *binaryValuesOutput) isBinaryEventValue(v interface{}) bool {
+	switch v := v.(type) {
+	case []byte:
+		return true
+	case string:
+		return !utf8.ValidString(v) || (b.cfg.MaxSize > 0 && len(v) > b.cfg.MaxSize)
+	}
+	return false
+}
+
+// Healthy forwards the health status of the wrapped output.
+func (b *binaryValuesOutput) Healthy() bool {
+	return IsHealthy(b.Output)
+}
+
+// Gatherer returns the prometheus gatherer of the wrapped output.
+func (b *binaryValuesOutput) Gatherer() prometheus.Gatherer {
+	return GathererOf(b.Output)
+}
+
+// Drain returns the messages buffered by the wrapped output, if it implements Drainer.
+func (b *binaryValuesOutput) Drain() []*ProtoMsg {
+	if d, ok := b.Output.(Drainer); ok {
+		return d.Drain()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped output.
+func (b *binaryValuesOutput) Flush(ctx context.Context) error {
+	return Flush(ctx, b.Output)
+}
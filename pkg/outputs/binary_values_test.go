@@ -0,0 +1,137 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func binaryValuesRsp(vals ...*gnmi.TypedValue) *gnmi.SubscribeResponse {
+	n := &gnmi.Notification{}
+	for _, v := range vals {
+		n.Update = append(n.Update, &gnmi.Update{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "a"}}}, Val: v})
+	}
+	return &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: n}}
+}
+
+func TestBinaryValuesOutput(t *testing.T) {
+	bytesVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_BytesVal{BytesVal: []byte{0xde, 0xad, 0xbe, 0xef}}}
+	invalidASCII := &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "a\xffb"}}
+	longASCII := &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "héllo"}}
+	intVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 1}}
+	tests := []struct {
+		name    string
+		cfg     map[string]interface{}
+		in      *gnmi.SubscribeResponse
+		want    []*gnmi.TypedValue
+		wantErr bool
+	}{
+		{
+			name: "base64",
+			cfg:  map[string]interface{}{},
+			in:   binaryValuesRsp(bytesVal, invalidASCII, intVal),
+			want: []*gnmi.TypedValue{
+				{Value: &gnmi.TypedValue_StringVal{StringVal: "3q2+7w=="}},
+				{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "Yf9i"}},
+				intVal,
+			},
+		},
+		{
+			name: "hex_prefix",
+			cfg:  map[string]interface{}{"encoding": "hex", "prefix": "hex:"},
+			in:   binaryValuesRsp(bytesVal),
+			want: []*gnmi.TypedValue{{Value: &gnmi.TypedValue_StringVal{StringVal: "hex:deadbeef"}}},
+		},
+		{
+			name: "drop",
+			cfg:  map[string]interface{}{"encoding": "drop"},
+			in:   binaryValuesRsp(bytesVal, intVal),
+			want: []*gnmi.TypedValue{intVal},
+		},
+		{
+			name: "truncate",
+			cfg:  map[string]interface{}{"max-size": 2},
+			in:   binaryValuesRsp(bytesVal, longASCII),
+			want: []*gnmi.TypedValue{
+				{Value: &gnmi.TypedValue_StringVal{StringVal: "3q0="}},
+				{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "h"}},
+			},
+		},
+		{
+			name: "max_size_drop",
+			cfg:  map[string]interface{}{"max-size": 2, "max-size-action": "drop"},
+			in:   binaryValuesRsp(bytesVal, longASCII, intVal),
+			want: []*gnmi.TypedValue{intVal},
+		},
+		{
+			name:    "invalid_encoding",
+			cfg:     map[string]interface{}{"encoding": "base32"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &stubOutput{}
+			got, err := NewBinaryValuesOutput(o, map[string]interface{}{"binary-values": tt.cfg}, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			in := proto.Clone(tt.in).(*gnmi.SubscribeResponse)
+			got.Write(context.Background(), in, Meta{})
+			if !proto.Equal(in, tt.in) {
+				t.Fatalf("the written message was modified")
+			}
+			rsp := o.msgs[0].(*gnmi.SubscribeResponse)
+			upds := rsp.GetUpdate().GetUpdate()
+			if len(upds) != len(tt.want) {
+				t.Fatalf("expected %d updates, got %d", len(tt.want), len(upds))
+			}
+			for i, upd := range upds {
+				if !proto.Equal(upd.GetVal(), tt.want[i]) {
+					t.Errorf("update %d: expected %v, got %v", i, tt.want[i], upd.GetVal())
+				}
+			}
+		})
+	}
+}
+
+func TestBinaryValuesOutputEvents(t *testing.T) {
+	o := &stubOutput{}
+	got, err := NewBinaryValuesOutput(o, map[string]interface{}{"binary-values": map[string]interface{}{}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := &formatters.EventMsg{Name: "ev1", Values: map[string]interface{}{
+		"bytes":   []byte{0xde, 0xad, 0xbe, 0xef},
+		"invalid": "a\xffb",
+		"string":  "ok",
+	}}
+	got.WriteEvent(context.Background(), ev)
+	if _, ok := ev.Values["bytes"].([]byte); !ok {
+		t.Fatalf("the written event was modified")
+	}
+	want := map[string]interface{}{"bytes": "3q2+7w==", "invalid": "Yf9i", "string": "ok"}
+	for k, v := range want {
+		if o.events[0].Values[k] != v {
+			t.Errorf("value %s: expected %v, got %v", k, v, o.events[0].Values[k])
+		}
+	}
+}
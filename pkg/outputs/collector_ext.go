@@ -0,0 +1,105 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+// CollectorExtension attaches the collector extension to the SubscribeResponses
+// sent by gnmic's gNMI servers.
+// It records, for each cached target, the original target name and the
+// last time data was received from it.
+// A nil *CollectorExtension is a no-op.
+type CollectorExtension struct {
+	id          gnmi_ext.ExtensionID
+	collectorID string
+
+	m       sync.RWMutex
+	targets map[string]*api.CollectorInfo
+}
+
+// NewCollectorExtension returns a CollectorExtension built from cfg,
+// or nil if cfg is nil. The collector ID defaults to defaultCollectorID
+// if set, or to the hostname.
+func NewCollectorExtension(cfg *types.CollectorExtensionConfig, defaultCollectorID string) *CollectorExtension {
+	if cfg == nil {
+		return nil
+	}
+	ce := &CollectorExtension{
+		id:          gnmi_ext.ExtensionID(cfg.ID),
+		collectorID: cfg.CollectorID,
+		targets:     make(map[string]*api.CollectorInfo),
+	}
+	if ce.id == gnmi_ext.ExtensionID_EID_UNSET {
+		ce.id = api.DefaultCollectorExtensionID
+	}
+	if ce.collectorID == "" {
+		ce.collectorID = defaultCollectorID
+	}
+	if ce.collectorID == "" {
+		ce.collectorID, _ = os.Hostname()
+	}
+	return ce
+}
+
+// Received records that data for the cached target was just received
+// from the target named source.
+func (ce *CollectorExtension) Received(target, source string) {
+	if ce == nil {
+		return
+	}
+	if source == "" {
+		source = target
+	}
+	ce.m.Lock()
+	defer ce.m.Unlock()
+	ce.targets[target] = &api.CollectorInfo{
+		CollectorID:      ce.collectorID,
+		Target:           source,
+		ReceiveTimestamp: time.Now().UnixNano(),
+	}
+}
+
+// Delete forgets the cached target.
+func (ce *CollectorExtension) Delete(target string) {
+	if ce == nil {
+		return
+	}
+	ce.m.Lock()
+	defer ce.m.Unlock()
+	delete(ce.targets, target)
+}
+
+// Add attaches the collector extension to the update rsp
+// based on its prefix target.
+func (ce *CollectorExtension) Add(rsp *gnmi.SubscribeResponse) error {
+	if ce == nil || rsp.GetUpdate() == nil {
+		return nil
+	}
+	target := rsp.GetUpdate().GetPrefix().GetTarget()
+	ce.m.RLock()
+	ci, ok := ce.targets[target]
+	ce.m.RUnlock()
+	if !ok {
+		ci = &api.CollectorInfo{
+			CollectorID: ce.collectorID,
+			Target:      target,
+		}
+	}
+	return api.Extension_CollectorInfo(ce.id, ci)(rsp)
+}
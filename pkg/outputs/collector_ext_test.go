@@ -0,0 +1,70 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/api"
+	"github.com/openconfig/gnmic/pkg/api/types"
+)
+
+func TestCollectorExtension(t *testing.T) {
+	newRsp := func(target string) *gnmi.SubscribeResponse {
+		return &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: &gnmi.Notification{Prefix: &gnmi.Path{Target: target}},
+			},
+		}
+	}
+	var nilExt *CollectorExtension
+	rsp := newRsp("r1")
+	if err := nilExt.Add(rsp); err != nil || len(rsp.GetExtension()) != 0 {
+		t.Fatalf("expected a nil collector extension to be a no-op")
+	}
+	if NewCollectorExtension(nil, "gnmic1") != nil {
+		t.Fatalf("expected a nil collector extension without config")
+	}
+
+	ce := NewCollectorExtension(&types.CollectorExtensionConfig{}, "gnmic1")
+	ce.Received("router1.example.com", "r1")
+	rsp = newRsp("router1.example.com")
+	if err := ce.Add(rsp); err != nil {
+		t.Fatal(err)
+	}
+	ci, err := api.CollectorInfoFromExtensions(api.DefaultCollectorExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci == nil || ci.CollectorID != "gnmic1" || ci.Target != "r1" || ci.ReceiveTimestamp == 0 {
+		t.Errorf("unexpected collector info: %+v", ci)
+	}
+
+	// unknown targets get no receive timestamp
+	ce.Delete("router1.example.com")
+	rsp = newRsp("router1.example.com")
+	if err := ce.Add(rsp); err != nil {
+		t.Fatal(err)
+	}
+	ci, err = api.CollectorInfoFromExtensions(api.DefaultCollectorExtensionID, rsp.GetExtension())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci == nil || ci.Target != "router1.example.com" || ci.ReceiveTimestamp != 0 {
+		t.Errorf("unexpected collector info: %+v", ci)
+	}
+
+	// sync responses are left untouched
+	rsp = &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}}
+	if err := ce.Add(rsp); err != nil || len(rsp.GetExtension()) != 0 {
+		t.Errorf("expected no extension on a sync response")
+	}
+}
@@ -0,0 +1,153 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const deliveryFlushCheckInterval = 10 * time.Millisecond
+
+// DeliveryTracker counts the messages accepted by an output that
+// are not yet confirmed by the remote system, and the delivery failures.
+// It allows outputs delivering messages asynchronously to implement Flusher.
+//
+// A message is added when it is accepted by the output and marked as done
+// once the remote system confirmed (or failed) its delivery.
+type DeliveryTracker struct {
+	pending atomic.Int64
+	failed  atomic.Int64
+}
+
+// Add adds n messages to the pending messages.
+func (d *DeliveryTracker) Add(n int) {
+	d.pending.Add(int64(n))
+}
+
+// Done marks a pending message as delivered if err is nil, as failed otherwise.
+func (d *DeliveryTracker) Done(err error) {
+	if err != nil {
+		d.failed.Add(1)
+	}
+	d.pending.Add(-1)
+}
+
+// Fail records a delivery failure of a message that was never added,
+// e.g a message rejected because the output queue is full.
+func (d *DeliveryTracker) Fail() {
+	d.failed.Add(1)
+}
+
+// Pending returns the number of messages waiting for a delivery confirmation.
+func (d *DeliveryTracker) Pending() int64 {
+	return d.pending.Load()
+}
+
+// Flush waits for the pending messages to be confirmed.
+// It returns an error if any delivery failed since the previous Flush call.
+func (d *DeliveryTracker) Flush(ctx context.Context) error {
+	if err := waitPending(ctx, &d.pending); err != nil {
+		return err
+	}
+	if n := d.failed.Swap(0); n > 0 {
+		return fmt.Errorf("%d message(s) failed to be delivered", n)
+	}
+	return nil
+}
+
+// Delivery tracks the delivery of the messages resulting from a single write
+// to the outputs, e.g a message received by an input with checkpointing enabled.
+// It is carried by the context passed to Write and WriteEvent, see WithDelivery.
+// Outputs confirming their deliveries add the messages they accept to the Delivery
+// of the write context and mark them as done once the remote system confirmed (or failed)
+// their delivery, so that the writer waits for its own messages only.
+// Outputs ignoring it are considered to deliver the messages as soon as they are written.
+// The methods of a nil Delivery are no-ops.
+type Delivery struct {
+	pending atomic.Int64
+	failed  atomic.Int64
+}
+
+// NewDelivery returns a Delivery without pending messages.
+func NewDelivery() *Delivery {
+	return new(Delivery)
+}
+
+// Add adds n messages to the pending messages.
+func (d *Delivery) Add(n int) {
+	if d == nil {
+		return
+	}
+	d.pending.Add(int64(n))
+}
+
+// Done marks a pending message as delivered if err is nil, as failed otherwise.
+func (d *Delivery) Done(err error) {
+	if d == nil {
+		return
+	}
+	if err != nil {
+		d.failed.Add(1)
+	}
+	d.pending.Add(-1)
+}
+
+// Fail records a delivery failure of a message that was never added,
+// e.g a message rejected because the output queue is full.
+func (d *Delivery) Fail() {
+	if d == nil {
+		return
+	}
+	d.failed.Add(1)
+}
+
+// Wait waits for the pending messages to be confirmed.
+// It returns an error if any of the messages failed to be delivered.
+func (d *Delivery) Wait(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+	if err := waitPending(ctx, &d.pending); err != nil {
+		return err
+	}
+	if n := d.failed.Load(); n > 0 {
+		return fmt.Errorf("%d message(s) failed to be delivered", n)
+	}
+	return nil
+}
+
+type deliveryKey struct{}
+
+// WithDelivery returns a copy of ctx carrying the Delivery d.
+// A nil d hides the Delivery ctx might carry.
+func WithDelivery(ctx context.Context, d *Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, d)
+}
+
+// DeliveryFrom returns the Delivery carried by ctx, or nil if there is none.
+func DeliveryFrom(ctx context.Context) *Delivery {
+	d, _ := ctx.Value(deliveryKey{}).(*Delivery)
+	return d
+}
+
+func waitPending(ctx context.Context, pending *atomic.Int64) error {
+	ticker := time.NewTicker(deliveryFlushCheckInterval)
+	defer ticker.Stop()
+	for pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeliveryTrackerFlush(t *testing.T) {
+	d := new(DeliveryTracker)
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error flushing an empty tracker: %v", err)
+	}
+
+	d.Add(2)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		d.Done(nil)
+		d.Done(nil)
+	}()
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Pending() != 0 {
+		t.Fatalf("expected no pending messages, got %d", d.Pending())
+	}
+
+	d.Add(2)
+	d.Done(nil)
+	d.Done(errors.New("broker unavailable"))
+	if err := d.Flush(context.Background()); err == nil {
+		t.Fatalf("expected a delivery error")
+	}
+	// failures are reported once
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error after failures were reported: %v", err)
+	}
+
+	d.Fail()
+	if err := d.Flush(context.Background()); err == nil {
+		t.Fatalf("expected a delivery error")
+	}
+}
+
+func TestDeliveryTrackerFlushTimeout(t *testing.T) {
+	d := new(DeliveryTracker)
+	d.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := d.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	d1, d2 := NewDelivery(), NewDelivery()
+	d1.Add(1)
+	d2.Add(2)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		d1.Done(errors.New("broker unavailable"))
+		d2.Done(nil)
+		d2.Done(nil)
+	}()
+	if err := d2.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a failure is not reported to the other deliveries and is kept
+	if err := d1.Wait(context.Background()); err == nil {
+		t.Fatalf("expected a delivery error")
+	}
+	if err := d1.Wait(context.Background()); err == nil {
+		t.Fatalf("expected the delivery error to be reported again")
+	}
+
+	d3 := NewDelivery()
+	d3.Fail()
+	if err := d3.Wait(context.Background()); err == nil {
+		t.Fatalf("expected a delivery error")
+	}
+
+	d4 := NewDelivery()
+	d4.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := d4.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+}
+
+func TestDeliveryContext(t *testing.T) {
+	if d := DeliveryFrom(context.Background()); d != nil {
+		t.Fatalf("unexpected delivery in an empty context")
+	}
+	// the methods of a nil delivery are no-ops
+	var nd *Delivery
+	nd.Add(1)
+	nd.Done(nil)
+	nd.Fail()
+	if err := nd.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := NewDelivery()
+	ctx := WithDelivery(context.Background(), d)
+	if DeliveryFrom(ctx) != d {
+		t.Fatalf("the context does not carry the delivery")
+	}
+	if DeliveryFrom(WithDelivery(ctx, nil)) != nil {
+		t.Fatalf("a nil delivery must hide the context delivery")
+	}
+}
@@ -0,0 +1,174 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const dataKeySize = 32
+
+// envelopeMagic prefixes the encrypted payloads, it identifies the envelope format version.
+var envelopeMagic = []byte("gNE1")
+
+// EncryptionConfig configures the envelope encryption of the payloads
+// written by an output, or the decryption of the payloads read by an input.
+// Exactly one of Key, KeyEnv or KeyFile must be set.
+type EncryptionConfig struct {
+	// base64 encoded AES key, 16, 24 or 32 bytes long.
+	Key string `mapstructure:"key,omitempty" json:"-"`
+	// name of an environment variable holding the base64 encoded key.
+	KeyEnv string `mapstructure:"key-env,omitempty" json:"key-env,omitempty"`
+	// path to a file holding the base64 encoded key.
+	KeyFile string `mapstructure:"key-file,omitempty" json:"key-file,omitempty"`
+}
+
+// Envelope seals and opens payloads using envelope encryption:
+// each payload is encrypted with a random data key using AES-GCM,
+// the data key is itself encrypted with the configured key (the key encryption key)
+// and sent along with the payload.
+//
+// The sealed payload format is:
+// magic | key nonce | encrypted data key | payload nonce | encrypted payload
+//
+// A nil *Envelope returns the payloads unchanged.
+type Envelope struct {
+	kek cipher.AEAD
+}
+
+// NewEnvelope returns nil if cfg is nil.
+func NewEnvelope(cfg *EncryptionConfig) (*Envelope, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	key, err := cfg.key()
+	if err != nil {
+		return nil, err
+	}
+	kek, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{kek: kek}, nil
+}
+
+func (c *EncryptionConfig) key() ([]byte, error) {
+	var n int
+	for _, s := range []string{c.Key, c.KeyEnv, c.KeyFile} {
+		if s != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return nil, errors.New("encryption: exactly one of key, key-env or key-file must be set")
+	}
+	var s string
+	switch {
+	case c.Key != "":
+		s = c.Key
+	case c.KeyEnv != "":
+		s = os.Getenv(c.KeyEnv)
+		if s == "" {
+			return nil, fmt.Errorf("encryption: environment variable %q is not set", c.KeyEnv)
+		}
+	default:
+		b, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("encryption: %v", err)
+		}
+		s = string(b)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("encryption: invalid key: %v", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("encryption: invalid key length %d, must be 16, 24 or 32 bytes", len(key))
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts b.
+func (e *Envelope) Seal(b []byte) ([]byte, error) {
+	if e == nil {
+		return b, nil
+	}
+	dek := make([]byte, dataKeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	kn := e.kek.NonceSize()
+	pn := aead.NonceSize()
+	out := make([]byte, 0, len(envelopeMagic)+kn+dataKeySize+e.kek.Overhead()+pn+len(b)+aead.Overhead())
+	out = append(out, envelopeMagic...)
+	// key nonce
+	out = out[:len(out)+kn]
+	if _, err := rand.Read(out[len(out)-kn:]); err != nil {
+		return nil, err
+	}
+	out = e.kek.Seal(out, out[len(out)-kn:], dek, envelopeMagic)
+	// payload nonce
+	out = out[:len(out)+pn]
+	if _, err := rand.Read(out[len(out)-pn:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out[len(out)-pn:], b, envelopeMagic), nil
+}
+
+// Open decrypts b, a payload sealed by an Envelope using the same key.
+func (e *Envelope) Open(b []byte) ([]byte, error) {
+	if e == nil {
+		return b, nil
+	}
+	if !bytes.HasPrefix(b, envelopeMagic) {
+		return nil, errors.New("not an encrypted payload")
+	}
+	b = b[len(envelopeMagic):]
+	kn := e.kek.NonceSize()
+	wrappedLen := dataKeySize + e.kek.Overhead()
+	if len(b) < kn+wrappedLen {
+		return nil, errors.New("truncated encrypted payload")
+	}
+	dek, err := e.kek.Open(nil, b[:kn], b[kn:kn+wrappedLen], envelopeMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %v", err)
+	}
+	b = b[kn+wrappedLen:]
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	pn := aead.NonceSize()
+	if len(b) < pn {
+		return nil, errors.New("truncated encrypted payload")
+	}
+	return aead.Open(nil, b[:pn], b[pn:], envelopeMagic)
+}
@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	e, err := NewEnvelope(&EncryptionConfig{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"name":"sub1","tags":{"source":"router1"}}`)
+	sealed, err := e.Seal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, payload) {
+		t.Fatal("sealed payload contains the plain text")
+	}
+	sealed2, err := e.Seal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sealed, sealed2) {
+		t.Error("expected different sealed payloads for the same plain text")
+	}
+	opened, err := e.Open(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, payload) {
+		t.Errorf("expected %q, got %q", payload, opened)
+	}
+	// tampered payload
+	sealed[len(sealed)-1] ^= 0xff
+	if _, err := e.Open(sealed); err == nil {
+		t.Error("expected an error opening a tampered payload")
+	}
+	// wrong key
+	other, err := NewEnvelope(&EncryptionConfig{Key: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 16))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Open(sealed2); err == nil {
+		t.Error("expected an error opening a payload with the wrong key")
+	}
+	if _, err := e.Open(payload); err == nil {
+		t.Error("expected an error opening a plain text payload")
+	}
+	if _, err := e.Open(envelopeMagic); err == nil {
+		t.Error("expected an error opening a truncated payload")
+	}
+}
+
+func TestNilEnvelope(t *testing.T) {
+	e, err := NewEnvelope(nil)
+	if err != nil || e != nil {
+		t.Fatalf("expected a nil envelope, got %v, %v", e, err)
+	}
+	b := []byte("msg")
+	if got, _ := e.Seal(b); !bytes.Equal(got, b) {
+		t.Errorf("expected an unchanged payload, got %q", got)
+	}
+	if got, _ := e.Open(b); !bytes.Equal(got, b) {
+		t.Errorf("expected an unchanged payload, got %q", got)
+	}
+}
+
+func TestEncryptionConfigKey(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 24))
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(key+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GNMIC_TEST_ENCRYPTION_KEY", key)
+	tests := []struct {
+		name    string
+		cfg     *EncryptionConfig
+		wantErr bool
+	}{
+		{name: "key", cfg: &EncryptionConfig{Key: key}},
+		{name: "key_env", cfg: &EncryptionConfig{KeyEnv: "GNMIC_TEST_ENCRYPTION_KEY"}},
+		{name: "key_file", cfg: &EncryptionConfig{KeyFile: keyFile}},
+		{name: "none", cfg: &EncryptionConfig{}, wantErr: true},
+		{name: "both", cfg: &EncryptionConfig{Key: key, KeyFile: keyFile}, wantErr: true},
+		{name: "unset_env", cfg: &EncryptionConfig{KeyEnv: "GNMIC_TEST_ENCRYPTION_KEY_UNSET"}, wantErr: true},
+		{name: "missing_file", cfg: &EncryptionConfig{KeyFile: keyFile + ".missing"}, wantErr: true},
+		{name: "not_base64", cfg: &EncryptionConfig{Key: "not-base64!"}, wantErr: true},
+		{name: "bad_length", cfg: &EncryptionConfig{Key: base64.StdEncoding.EncodeToString([]byte("short"))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEnvelope(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
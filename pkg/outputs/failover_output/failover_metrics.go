@@ -0,0 +1,36 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover_output
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var failoverActiveMember = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "gnmic",
+	Subsystem: "failover_output",
+	Name:      "active_member",
+	Help:      "Index of the member output currently receiving messages",
+}, []string{"name"})
+
+var failoverNumberOfSwitchovers = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "failover_output",
+	Name:      "number_of_switchovers_total",
+	Help:      "Number of times the failover output switched its active member output",
+}, []string{"name"})
+
+func registerMetrics(reg *prometheus.Registry) error {
+	var err error
+	if err = reg.Register(failoverActiveMember); err != nil {
+		return err
+	}
+	if err = reg.Register(failoverNumberOfSwitchovers); err != nil {
+		return err
+	}
+	return nil
+}
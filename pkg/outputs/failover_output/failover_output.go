@@ -0,0 +1,318 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover_output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	loggingPrefix        = "[failover_output:%s] "
+	outputType           = "failover"
+	defaultCheckInterval = 5 * time.Second
+)
+
+func init() {
+	outputs.Register(outputType, func() outputs.Output {
+		return &failoverOutput{
+			cfg:    &config{},
+			logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
+		}
+	})
+}
+
+// failoverOutput writes the received messages to the first healthy
+// output in its ordered list of member outputs.
+type failoverOutput struct {
+	cfg      *config
+	name     string
+	logger   *log.Logger
+	cancelFn context.CancelFunc
+	members  []outputs.Output
+	// index of the member currently receiving the messages
+	active atomic.Int64
+	// serializes the members selection
+	selectMu sync.Mutex
+}
+
+type config struct {
+	// ordered list of member outputs configurations,
+	// the first one has the highest priority.
+	Outputs []map[string]interface{} `mapstructure:"outputs,omitempty" json:"outputs,omitempty"`
+	// interval at which the health of the members is evaluated.
+	CheckInterval time.Duration `mapstructure:"check-interval,omitempty" json:"check-interval,omitempty" default:"5s"`
+	Format        string        `mapstructure:"format,omitempty" json:"format,omitempty"`
+	Debug         bool          `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+	EnableMetrics bool          `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+}
+
+func (f *failoverOutput) String() string {
+	b, err := json.Marshal(f.cfg)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (f *failoverOutput) SetLogger(logger *log.Logger) {
+	if logger != nil && f.logger != nil {
+		f.logger.SetOutput(logger.Writer())
+		f.logger.SetFlags(logger.Flags())
+	}
+}
+
+// SetEventProcessors is a noop, the event processors
+// are configured under each member output.
+func (f *failoverOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (f *failoverOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	err := outputs.DecodeConfig(cfg, f.cfg)
+	if err != nil {
+		return err
+	}
+	f.name = name
+	f.logger.SetPrefix(fmt.Sprintf(loggingPrefix, name))
+	for _, opt := range opts {
+		if err := opt(f); err != nil {
+			return err
+		}
+	}
+	err = f.setDefaults()
+	if err != nil {
+		return err
+	}
+	ctx, f.cancelFn = context.WithCancel(ctx)
+	// init members
+	f.members = make([]outputs.Output, 0, len(f.cfg.Outputs))
+	for i, mcfg := range f.cfg.Outputs {
+		mType := mcfg["type"].(string)
+		initializer, ok := outputs.Outputs[mType]
+		if !ok {
+			return fmt.Errorf("member output %d has an unknown type %q", i, mType)
+		}
+		if format, ok := mcfg["format"]; !ok || format == "" {
+			mcfg["format"] = f.cfg.Format
+		}
+		mName := fmt.Sprintf("%s-%d", name, i)
+		out, err := outputs.WrapOutput(initializer(), mcfg, f.logger)
+		if err != nil {
+			return fmt.Errorf("member output %d: %w", i, err)
+		}
+		err = out.Init(ctx, mName, mcfg, opts...)
+		if err != nil {
+			for _, m := range f.members {
+				m.Close()
+			}
+			f.members = nil
+			return fmt.Errorf("failed to init member output %d of type %q: %w", i, mType, err)
+		}
+		f.members = append(f.members, out)
+		f.logger.Printf("initialized member output %q of type %q with priority %d", mName, mType, i)
+	}
+	if len(f.members) == 0 {
+		return errors.New("no member output initialized")
+	}
+	if f.cfg.EnableMetrics {
+		failoverActiveMember.WithLabelValues(f.name).Set(0)
+	}
+	go f.watchMembers(ctx)
+	return nil
+}
+
+func (f *failoverOutput) setDefaults() error {
+	if len(f.cfg.Outputs) == 0 {
+		return errors.New("missing member outputs")
+	}
+	for i, mcfg := range f.cfg.Outputs {
+		mType, ok := mcfg["type"].(string)
+		if !ok || mType == "" {
+			return fmt.Errorf("member output %d is missing a type", i)
+		}
+		if mType == outputType {
+			return fmt.Errorf("member output %d: nested %q outputs are not supported", i, outputType)
+		}
+	}
+	if f.cfg.CheckInterval <= 0 {
+		f.cfg.CheckInterval = defaultCheckInterval
+	}
+	return nil
+}
+
+// watchMembers periodically selects the highest priority healthy member.
+func (f *failoverOutput) watchMembers(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.selectMember(ctx)
+		}
+	}
+}
+
+// selectMember makes the highest priority healthy member the active one.
+// The messages buffered by the previously active member are handed over
+// to the new active member.
+func (f *failoverOutput) selectMember(ctx context.Context) outputs.Output {
+	f.selectMu.Lock()
+	defer f.selectMu.Unlock()
+	current := f.active.Load()
+	next := current
+	for i, m := range f.members {
+		if outputs.IsHealthy(m) {
+			next = int64(i)
+			break
+		}
+	}
+	if next == current {
+		return f.members[current]
+	}
+	f.logger.Printf("switching from member output %d to member output %d", current, next)
+	f.active.Store(next)
+	if f.cfg.EnableMetrics {
+		failoverActiveMember.WithLabelValues(f.name).Set(float64(next))
+		failoverNumberOfSwitchovers.WithLabelValues(f.name).Inc()
+	}
+	if d, ok := f.members[current].(outputs.Drainer); ok {
+		msgs := d.Drain()
+		if len(msgs) > 0 {
+			f.logger.Printf("moving %d pending message(s) from member output %d to member output %d", len(msgs), current, next)
+		}
+		for _, m := range msgs {
+			f.members[next].Write(outputs.WithDelivery(ctx, m.GetDelivery()), m.GetMsg(), m.GetMeta())
+			m.GetDelivery().Done(nil)
+		}
+	}
+	return f.members[next]
+}
+
+// activeMember returns the member to write messages to.
+// If the active member became unhealthy since the last check,
+// a new member is selected right away.
+func (f *failoverOutput) activeMember(ctx context.Context) outputs.Output {
+	if len(f.members) == 0 {
+		return nil
+	}
+	m := f.members[f.active.Load()]
+	if outputs.IsHealthy(m) {
+		return m
+	}
+	return f.selectMember(ctx)
+}
+
+func (f *failoverOutput) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta) {
+	if rsp == nil {
+		return
+	}
+	m := f.activeMember(ctx)
+	if m == nil {
+		f.logger.Printf("failed to write message: no member output")
+		return
+	}
+	m.Write(ctx, rsp, meta)
+}
+
+func (f *failoverOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	m := f.activeMember(ctx)
+	if m == nil {
+		f.logger.Printf("failed to write event: no member output")
+		return
+	}
+	m.WriteEvent(ctx, ev)
+}
+
+// Flush flushes all the member outputs,
+// the previously active ones might still hold messages.
+func (f *failoverOutput) Flush(ctx context.Context) error {
+	var errs []error
+	for _, m := range f.members {
+		if err := outputs.Flush(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Healthy returns true if at least one of the member outputs is healthy.
+func (f *failoverOutput) Healthy() bool {
+	for _, m := range f.members {
+		if outputs.IsHealthy(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *failoverOutput) Close() error {
+	if f.cancelFn != nil {
+		f.cancelFn()
+	}
+	for i, m := range f.members {
+		err := m.Close()
+		if err != nil {
+			f.logger.Printf("failed to close member output %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (f *failoverOutput) RegisterMetrics(reg *prometheus.Registry) {
+	if !f.cfg.EnableMetrics {
+		return
+	}
+	if reg == nil {
+		f.logger.Printf("ERR: output metrics enabled but main registry is not initialized, enable main metrics under `api-server`")
+		return
+	}
+	if err := registerMetrics(reg); err != nil {
+		f.logger.Printf("failed to register metric: %v", err)
+	}
+}
+
+// SetName forwards name to the member outputs.
+// The options passed to Init are applied to each member when it is initialized.
+func (f *failoverOutput) SetName(name string) {
+	for _, m := range f.members {
+		m.SetName(name)
+	}
+}
+
+// SetClusterName forwards name to the member outputs.
+func (f *failoverOutput) SetClusterName(name string) {
+	for _, m := range f.members {
+		m.SetClusterName(name)
+	}
+}
+
+// SetTargetsConfig forwards tcs to the member outputs.
+func (f *failoverOutput) SetTargetsConfig(tcs map[string]*types.TargetConfig) {
+	for _, m := range f.members {
+		m.SetTargetsConfig(tcs)
+	}
+}
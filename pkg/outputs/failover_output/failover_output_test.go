@@ -0,0 +1,310 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover_output
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const stubType = "failover-test-stub"
+
+var (
+	stubsMu sync.Mutex
+	stubs   = map[string]*stubOutput{}
+)
+
+func init() {
+	outputs.Register(stubType, func() outputs.Output {
+		return &stubOutput{}
+	})
+}
+
+// stubOutput is a member output with a controllable health status,
+// it buffers messages while unhealthy.
+type stubOutput struct {
+	healthy atomic.Bool
+
+	m       sync.Mutex
+	written int
+	events  int
+	pending []*outputs.ProtoMsg
+
+	name        string
+	clusterName string
+	tcs         map[string]*types.TargetConfig
+}
+
+func (s *stubOutput) Init(_ context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	if fail, _ := cfg["fail-init"].(bool); fail {
+		return errors.New("init failed")
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return err
+		}
+	}
+	s.healthy.Store(true)
+	stubsMu.Lock()
+	stubs[name] = s
+	stubsMu.Unlock()
+	return nil
+}
+
+func (s *stubOutput) Write(_ context.Context, m proto.Message, meta outputs.Meta) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if !s.healthy.Load() {
+		s.pending = append(s.pending, outputs.NewProtoMsg(m, meta))
+		return
+	}
+	s.written++
+}
+
+func (s *stubOutput) WriteEvent(context.Context, *formatters.EventMsg) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.events++
+}
+
+func (s *stubOutput) Drain() []*outputs.ProtoMsg {
+	s.m.Lock()
+	defer s.m.Unlock()
+	p := s.pending
+	s.pending = nil
+	return p
+}
+
+func (s *stubOutput) counts() (int, int, int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.written, s.events, len(s.pending)
+}
+
+func (s *stubOutput) Healthy() bool                        { return s.healthy.Load() }
+func (s *stubOutput) Close() error                         { return nil }
+func (s *stubOutput) RegisterMetrics(*prometheus.Registry) {}
+func (s *stubOutput) String() string                       { return stubType }
+func (s *stubOutput) SetLogger(*log.Logger)                {}
+func (s *stubOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+func (s *stubOutput) SetName(name string)                                 { s.name = name }
+func (s *stubOutput) SetClusterName(name string)                          { s.clusterName = name }
+func (s *stubOutput) SetTargetsConfig(tcs map[string]*types.TargetConfig) { s.tcs = tcs }
+
+func getStub(t *testing.T, name string) *stubOutput {
+	stubsMu.Lock()
+	defer stubsMu.Unlock()
+	s, ok := stubs[name]
+	if !ok {
+		t.Fatalf("member %q not initialized", name)
+	}
+	return s
+}
+
+func newFailover() *failoverOutput {
+	return &failoverOutput{
+		cfg:    &config{},
+		logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
+	}
+}
+
+func TestFailoverSwitch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := newFailover()
+	err := f.Init(ctx, "fo", map[string]interface{}{
+		"check-interval": "1h",
+		"outputs": []interface{}{
+			map[string]interface{}{"type": stubType},
+			map[string]interface{}{"type": stubType},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to init failover output: %v", err)
+	}
+	primary := getStub(t, "fo-0")
+	secondary := getStub(t, "fo-1")
+	msg := &gnmi.SubscribeResponse{}
+
+	f.Write(ctx, msg, outputs.Meta{})
+	f.WriteEvent(ctx, &formatters.EventMsg{})
+	if w, e, _ := primary.counts(); w != 1 || e != 1 {
+		t.Fatalf("expected primary to receive the messages, got %d, %d", w, e)
+	}
+	// primary goes down with pending messages
+	primary.healthy.Store(false)
+	primary.Write(ctx, msg, outputs.Meta{})
+	primary.Write(ctx, msg, outputs.Meta{})
+	// the switch happens on the next write, without waiting for the check interval
+	f.Write(ctx, msg, outputs.Meta{})
+	if !f.Healthy() {
+		t.Errorf("expected failover output to be healthy")
+	}
+	if w, _, p := secondary.counts(); w != 3 || p != 0 {
+		t.Fatalf("expected secondary to receive the pending and new messages, got %d written", w)
+	}
+	if _, _, p := primary.counts(); p != 0 {
+		t.Fatalf("expected primary pending messages to be drained, got %d", p)
+	}
+	// primary comes back
+	primary.healthy.Store(true)
+	f.selectMember(ctx)
+	f.Write(ctx, msg, outputs.Meta{})
+	if w, _, _ := primary.counts(); w != 2 {
+		t.Fatalf("expected primary to receive messages after recovery, got %d", w)
+	}
+	// all members down
+	primary.healthy.Store(false)
+	secondary.healthy.Store(false)
+	if f.Healthy() {
+		t.Errorf("expected failover output to be unhealthy")
+	}
+}
+
+func TestFailoverForwardsOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := newFailover()
+	tcs := map[string]*types.TargetConfig{"leaf1": {Name: "leaf1"}}
+	err := f.Init(ctx, "fo-opts", map[string]interface{}{
+		"check-interval": "1h",
+		"outputs": []interface{}{
+			map[string]interface{}{"type": stubType},
+			map[string]interface{}{"type": stubType},
+		},
+	},
+		outputs.WithName("gnmic1"),
+		outputs.WithClusterName("cluster1"),
+		outputs.WithTargetsConfig(tcs),
+	)
+	if err != nil {
+		t.Fatalf("failed to init failover output: %v", err)
+	}
+	defer f.Close()
+	members := []*stubOutput{getStub(t, "fo-opts-0"), getStub(t, "fo-opts-1")}
+	for i, m := range members {
+		if m.name != "gnmic1" || m.clusterName != "cluster1" || len(m.tcs) != 1 {
+			t.Errorf("member %d: options not applied: %q, %q, %v", i, m.name, m.clusterName, m.tcs)
+		}
+	}
+
+	f.SetName("gnmic2")
+	f.SetClusterName("cluster2")
+	f.SetTargetsConfig(map[string]*types.TargetConfig{})
+	for i, m := range members {
+		if m.name != "gnmic2" || m.clusterName != "cluster2" || len(m.tcs) != 0 {
+			t.Errorf("member %d: setters not forwarded: %q, %q, %v", i, m.name, m.clusterName, m.tcs)
+		}
+	}
+}
+
+func TestFailoverWatchMembers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := newFailover()
+	err := f.Init(ctx, "fo-watch", map[string]interface{}{
+		"check-interval": "10ms",
+		"outputs": []interface{}{
+			map[string]interface{}{"type": stubType},
+			map[string]interface{}{"type": stubType},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to init failover output: %v", err)
+	}
+	primary := getStub(t, "fo-watch-0")
+	primary.healthy.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for f.active.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("failover output did not switch to the secondary member")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	primary.healthy.Store(true)
+	for f.active.Load() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("failover output did not switch back to the primary member")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFailoverInitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]interface{}
+	}{
+		{
+			name: "no_members",
+			cfg:  map[string]interface{}{},
+		},
+		{
+			name: "missing_type",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{}},
+			},
+		},
+		{
+			name: "nested_failover",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{"type": outputType}},
+			},
+		},
+		{
+			name: "member_init_failure",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{"type": stubType, "fail-init": true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFailover()
+			err := f.Init(context.Background(), "fo-err", tt.cfg)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			f.Close()
+			// writing to a failover output without members must not panic
+			f.Write(context.Background(), &gnmi.SubscribeResponse{}, outputs.Meta{})
+			f.WriteEvent(context.Background(), &formatters.EventMsg{})
+		})
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	f := &failoverOutput{cfg: &config{
+		Outputs: []map[string]interface{}{{"type": stubType}},
+	}}
+	if err := f.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(f.cfg) {
+		t.Error(m)
+	}
+}
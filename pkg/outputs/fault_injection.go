@@ -0,0 +1,124 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/faults"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const faultInjectionConfigKey = "fault-injection"
+
+// faultInjectionOutput wraps an Output and delays, drops or duplicates
+// the messages and events written to it.
+type faultInjectionOutput struct {
+	Output
+	name     string
+	injector *faults.Injector
+	logger   *log.Logger
+}
+
+// NewFaultInjectionOutput returns the output o wrapped with the faults found
+// under the `fault-injection` key of the output config cfg.
+// If the config does not define any fault, o is returned unchanged.
+func NewFaultInjectionOutput(o Output, cfg map[string]interface{}, logger *log.Logger) (Output, error) {
+	fic, ok := cfg[faultInjectionConfigKey]
+	if !ok || fic == nil {
+		return o, nil
+	}
+	fc := new(faults.Config)
+	err := DecodeConfig(fic, fc)
+	if err != nil {
+		return nil, err
+	}
+	if !fc.Enabled() {
+		return o, nil
+	}
+	injector, err := faults.NewInjector(fc)
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	return &faultInjectionOutput{
+		Output:   o,
+		injector: injector,
+		logger:   logger,
+	}, nil
+}
+
+func (f *faultInjectionOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...Option) error {
+	f.name = name
+	f.logger.Printf("output %q fault injection enabled", name)
+	return f.Output.Init(ctx, name, cfg, opts...)
+}
+
+// Close logs the number of injected faults and closes the wrapped output.
+func (f *faultInjectionOutput) Close() error {
+	dropped, duplicated := f.injector.Stats()
+	f.logger.Printf("output %q fault injection: dropped %d, duplicated %d messages", f.name, dropped, duplicated)
+	return f.Output.Close()
+}
+
+func (f *faultInjectionOutput) Write(ctx context.Context, rsp proto.Message, meta Meta) {
+	if rsp == nil {
+		return
+	}
+	n := f.injector.Inject(ctx)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			rsp = proto.Clone(rsp)
+		}
+		f.Output.Write(ctx, rsp, meta)
+	}
+}
+
+func (f *faultInjectionOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	n := f.injector.Inject(ctx)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			ev = formatters.CopyEventMsg(ev)
+		}
+		f.Output.WriteEvent(ctx, ev)
+	}
+}
+
+// Healthy forwards the health status of the wrapped output.
+func (f *faultInjectionOutput) Healthy() bool {
+	return IsHealthy(f.Output)
+}
+
+// Gatherer returns the prometheus gatherer of the wrapped output.
+func (f *faultInjectionOutput) Gatherer() prometheus.Gatherer {
+	return GathererOf(f.Output)
+}
+
+// Drain returns the messages buffered by the wrapped output, if it implements Drainer.
+func (f *faultInjectionOutput) Drain() []*ProtoMsg {
+	if d, ok := f.Output.(Drainer); ok {
+		return d.Drain()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped output.
+func (f *faultInjectionOutput) Flush(ctx context.Context) error {
+	return Flush(ctx, f.Output)
+}
@@ -0,0 +1,85 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestFaultInjectionOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        map[string]interface{}
+		wrapped    bool
+		wantErr    bool
+		wantMsgs   int
+		wantEvents int
+	}{
+		{
+			name:       "no_faults",
+			cfg:        map[string]interface{}{},
+			wantMsgs:   1,
+			wantEvents: 1,
+		},
+		{
+			name: "drop",
+			cfg: map[string]interface{}{
+				"fault-injection": map[string]interface{}{"drop-probability": 1},
+			},
+			wrapped: true,
+		},
+		{
+			name: "duplicate",
+			cfg: map[string]interface{}{
+				"fault-injection": map[string]interface{}{"duplicate-probability": 1},
+			},
+			wrapped:    true,
+			wantMsgs:   2,
+			wantEvents: 2,
+		},
+		{
+			name: "invalid_probability",
+			cfg: map[string]interface{}{
+				"fault-injection": map[string]interface{}{"drop-probability": 2},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &stubOutput{}
+			got, err := NewFaultInjectionOutput(o, tt.cfg, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, ok := got.(*faultInjectionOutput)
+			if ok != tt.wrapped {
+				t.Errorf("wrapped=%v, expected %v", ok, tt.wrapped)
+			}
+			got.Write(context.Background(), &gnmi.SubscribeResponse{}, Meta{})
+			got.WriteEvent(context.Background(), &formatters.EventMsg{Name: "ev1"})
+			msgs, events := o.counts()
+			if msgs != tt.wantMsgs || events != tt.wantEvents {
+				t.Errorf("got %d messages and %d events, expected %d and %d",
+					msgs, events, tt.wantMsgs, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ package file
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,6 +22,7 @@ import (
 	"time"
 
 	"golang.org/x/sync/semaphore"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -49,12 +52,14 @@ func init() {
 
 // File //
 type File struct {
-	cfg    *Config
-	file   *os.File
-	logger *log.Logger
-	mo     *formatters.MarshalOptions
-	sem    *semaphore.Weighted
-	evps   []formatters.EventProcessor
+	cfg          *Config
+	file         *os.File
+	logger       *log.Logger
+	mo           *formatters.MarshalOptions
+	sem          *semaphore.Weighted
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	envelope     *outputs.Envelope
 
 	targetTpl *template.Template
 	msgTpl    *template.Template
@@ -62,22 +67,24 @@ type File struct {
 
 // Config //
 type Config struct {
-	FileName           string   `mapstructure:"filename,omitempty"`
-	FileType           string   `mapstructure:"file-type,omitempty"`
-	Format             string   `mapstructure:"format,omitempty"`
-	Multiline          bool     `mapstructure:"multiline,omitempty"`
-	Indent             string   `mapstructure:"indent,omitempty"`
-	Separator          string   `mapstructure:"separator,omitempty"`
-	SplitEvents        bool     `mapstructure:"split-events,omitempty"`
-	OverrideTimestamps bool     `mapstructure:"override-timestamps,omitempty"`
-	AddTarget          string   `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string   `mapstructure:"target-template,omitempty"`
-	EventProcessors    []string `mapstructure:"event-processors,omitempty"`
-	MsgTemplate        string   `mapstructure:"msg-template,omitempty"`
-	ConcurrencyLimit   int      `mapstructure:"concurrency-limit,omitempty"`
-	EnableMetrics      bool     `mapstructure:"enable-metrics,omitempty"`
-	Debug              bool     `mapstructure:"debug,omitempty"`
-	CalculateLatency   bool     `mapstructure:"calculate-latency,omitempty"`
+	FileName           string                    `mapstructure:"filename,omitempty"`
+	FileType           string                    `mapstructure:"file-type,omitempty"`
+	Format             string                    `mapstructure:"format,omitempty" default:"json"`
+	Multiline          bool                      `mapstructure:"multiline,omitempty"`
+	Indent             string                    `mapstructure:"indent,omitempty"`
+	Separator          string                    `mapstructure:"separator,omitempty" default:"\n"`
+	SplitEvents        bool                      `mapstructure:"split-events,omitempty"`
+	OverrideTimestamps bool                      `mapstructure:"override-timestamps,omitempty"`
+	AddTarget          string                    `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                    `mapstructure:"target-template,omitempty"`
+	EventProcessors    []string                  `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat  `mapstructure:"number-format,omitempty"`
+	MsgTemplate        string                    `mapstructure:"msg-template,omitempty"`
+	ConcurrencyLimit   int                       `mapstructure:"concurrency-limit,omitempty" default:"1000"`
+	EnableMetrics      bool                      `mapstructure:"enable-metrics,omitempty"`
+	Debug              bool                      `mapstructure:"debug,omitempty"`
+	CalculateLatency   bool                      `mapstructure:"calculate-latency,omitempty"`
+	Encryption         *outputs.EncryptionConfig `mapstructure:"encryption,omitempty"`
 }
 
 func (f *File) String() string {
@@ -92,7 +99,11 @@ func (f *File) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := f.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	f.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	f.evps, err = formatters.MakeEventProcessors(
 		logger,
 		f.cfg.EventProcessors,
@@ -103,6 +114,7 @@ func (f *File) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	f.evps = append(f.evps, nfps...)
 	return nil
 }
 
@@ -127,15 +139,14 @@ func (f *File) Init(ctx context.Context, name string, cfg map[string]interface{}
 			return err
 		}
 	}
-	if f.cfg.Format == "proto" {
-		return fmt.Errorf("proto format not supported in output type 'file'")
-	}
-	if f.cfg.Separator == "" {
-		f.cfg.Separator = defaultSeparator
+	if f.cfg.Format == "proto" && f.cfg.MsgTemplate != "" {
+		return fmt.Errorf("msg-template is not supported with format 'proto' in output type 'file'")
 	}
-	if f.cfg.FileName == "" && f.cfg.FileType == "" {
-		f.cfg.FileType = "stdout"
+	f.envelope, err = outputs.NewEnvelope(f.cfg.Encryption)
+	if err != nil {
+		return err
 	}
+	f.setDefaults()
 
 	switch f.cfg.FileType {
 	case "stdout":
@@ -152,25 +163,6 @@ func (f *File) Init(ctx context.Context, name string, cfg map[string]interface{}
 		}
 	}
 
-	if f.cfg.Format == "" {
-		f.cfg.Format = defaultFormat
-	}
-	if f.cfg.FileType == "stdout" || f.cfg.FileType == "stderr" {
-		f.cfg.Indent = "  "
-		f.cfg.Multiline = true
-	}
-	if f.cfg.Multiline && f.cfg.Indent == "" {
-		f.cfg.Indent = "  "
-	}
-	if f.cfg.ConcurrencyLimit < 1 {
-		switch f.cfg.FileType {
-		case "stdout", "stderr":
-			f.cfg.ConcurrencyLimit = 1
-		default:
-			f.cfg.ConcurrencyLimit = defaultWriteConcurrency
-		}
-	}
-
 	f.sem = semaphore.NewWeighted(int64(f.cfg.ConcurrencyLimit))
 
 	f.mo = &formatters.MarshalOptions{
@@ -206,6 +198,33 @@ func (f *File) Init(ctx context.Context, name string, cfg map[string]interface{}
 	return nil
 }
 
+func (f *File) setDefaults() {
+	if f.cfg.Separator == "" {
+		f.cfg.Separator = defaultSeparator
+	}
+	if f.cfg.FileName == "" && f.cfg.FileType == "" {
+		f.cfg.FileType = "stdout"
+	}
+	if f.cfg.Format == "" {
+		f.cfg.Format = defaultFormat
+	}
+	if f.cfg.FileType == "stdout" || f.cfg.FileType == "stderr" {
+		f.cfg.Indent = "  "
+		f.cfg.Multiline = true
+	}
+	if f.cfg.Multiline && f.cfg.Indent == "" {
+		f.cfg.Indent = "  "
+	}
+	if f.cfg.ConcurrencyLimit < 1 {
+		switch f.cfg.FileType {
+		case "stdout", "stderr":
+			f.cfg.ConcurrencyLimit = 1
+		default:
+			f.cfg.ConcurrencyLimit = defaultWriteConcurrency
+		}
+	}
+}
+
 // Write //
 func (f *File) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta) {
 	if rsp == nil {
@@ -226,7 +245,7 @@ func (f *File) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta)
 	if err != nil {
 		f.logger.Printf("failed to add target to the response: %v", err)
 	}
-	bb, err := outputs.Marshal(rsp, meta, f.mo, f.cfg.SplitEvents, f.evps...)
+	bb, err := outputs.Marshal(rsp, meta, f.mo, f.cfg.SplitEvents, f.evpOverrides.Select(meta, f.evps)...)
 	if err != nil {
 		if f.cfg.Debug {
 			f.logger.Printf("failed marshaling proto msg: %v", err)
@@ -237,8 +256,10 @@ func (f *File) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta)
 	if len(bb) == 0 {
 		return
 	}
+	// proto messages are written length delimited
+	delimited := outputs.OverrideMarshalOptions(f.mo, meta).Format == "proto"
 	for _, b := range bb {
-		if f.msgTpl != nil {
+		if f.msgTpl != nil && !delimited {
 			b, err = outputs.ExecTemplate(b, f.msgTpl)
 			if err != nil {
 				if f.cfg.Debug {
@@ -248,8 +269,20 @@ func (f *File) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta)
 				continue
 			}
 		}
-
-		n, err := f.file.Write(append(b, []byte(f.cfg.Separator)...))
+		b, err = f.seal(b)
+		if err != nil {
+			if f.cfg.Debug {
+				f.logger.Printf("failed to encrypt msg: %v", err)
+			}
+			numberOfFailWriteMsgs.WithLabelValues(f.file.Name(), "encryption_error").Inc()
+			continue
+		}
+		if delimited {
+			b = append(protowire.AppendVarint(make([]byte, 0, len(b)+binary.MaxVarintLen64), uint64(len(b))), b...)
+		} else {
+			b = append(b, []byte(f.cfg.Separator)...)
+		}
+		n, err := f.file.Write(b)
 		if err != nil {
 			if f.cfg.Debug {
 				f.logger.Printf("failed to write to file '%s': %v", f.file.Name(), err)
@@ -268,6 +301,10 @@ func (f *File) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
 		return
 	default:
 	}
+	// events cannot be written to a stream of proto messages
+	if f.cfg.Format == "proto" {
+		return
+	}
 	var evs = []*formatters.EventMsg{ev}
 	for _, proc := range f.evps {
 		evs = proc.Apply(evs...)
@@ -277,7 +314,9 @@ func (f *File) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
 		for _, pev := range evs {
 			var err error
 			var b []byte
-			if f.cfg.Multiline {
+			if formatters.IsLineFormat(f.cfg.Format) {
+				b, err = formatters.EventsToLines(f.cfg.Format, pev)
+			} else if f.cfg.Multiline {
 				b, err = json.MarshalIndent(pev, "", f.cfg.Indent)
 			} else {
 				b, err = json.Marshal(pev)
@@ -287,13 +326,21 @@ func (f *File) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
 				numberOfFailWriteMsgs.WithLabelValues(f.file.Name(), "marshal_error").Inc()
 				return
 			}
+			b, err = f.seal(b)
+			if err != nil {
+				fmt.Printf("failed to WriteEvent: %v", err)
+				numberOfFailWriteMsgs.WithLabelValues(f.file.Name(), "encryption_error").Inc()
+				return
+			}
 			toWrite = append(toWrite, b...)
 			toWrite = append(toWrite, []byte(f.cfg.Separator)...)
 		}
 	} else {
 		var err error
 		var b []byte
-		if f.cfg.Multiline {
+		if formatters.IsLineFormat(f.cfg.Format) {
+			b, err = formatters.EventsToLines(f.cfg.Format, evs...)
+		} else if f.cfg.Multiline {
 			b, err = json.MarshalIndent(evs, "", f.cfg.Indent)
 		} else {
 			b, err = json.Marshal(evs)
@@ -303,6 +350,12 @@ func (f *File) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
 			numberOfFailWriteMsgs.WithLabelValues(f.file.Name(), "marshal_error").Inc()
 			return
 		}
+		b, err = f.seal(b)
+		if err != nil {
+			fmt.Printf("failed to WriteEvent: %v", err)
+			numberOfFailWriteMsgs.WithLabelValues(f.file.Name(), "encryption_error").Inc()
+			return
+		}
 		toWrite = append(toWrite, b...)
 		toWrite = append(toWrite, []byte(f.cfg.Separator)...)
 	}
@@ -317,6 +370,21 @@ func (f *File) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
 	numberOfWrittenMsgs.WithLabelValues(f.file.Name()).Inc()
 }
 
+// seal encrypts b if an encryption key is configured,
+// the result is base64 encoded to keep one message per line.
+func (f *File) seal(b []byte) ([]byte, error) {
+	if f.envelope == nil {
+		return b, nil
+	}
+	eb, err := f.envelope.Seal(b)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]byte, base64.StdEncoding.EncodedLen(len(eb)))
+	base64.StdEncoding.Encode(r, eb)
+	return r, nil
+}
+
 // Close //
 func (f *File) Close() error {
 	f.logger.Printf("closing file '%s' output", f.file.Name())
@@ -0,0 +1,25 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+)
+
+func TestSetDefaultsTags(t *testing.T) {
+	// the default values of a stdout or stderr file-type differ,
+	// the tags describe the defaults of a regular file.
+	f := &File{cfg: &Config{FileName: "gnmic.log"}}
+	f.setDefaults()
+	for _, m := range testutils.DefaultTagsMismatches(f.cfg, "filename") {
+		t.Error(m)
+	}
+}
@@ -64,13 +64,15 @@ type gNMIOutput struct {
 
 type config struct {
 	//Name             string `mapstructure:"name,omitempty"`
-	Address          string           `mapstructure:"address,omitempty"`
+	Address          string           `mapstructure:"address,omitempty" default:":57400"`
 	TargetTemplate   string           `mapstructure:"target-template,omitempty"`
-	MaxSubscriptions int64            `mapstructure:"max-subscriptions,omitempty"`
-	MaxUnaryRPC      int64            `mapstructure:"max-unary-rpc,omitempty"`
+	MaxSubscriptions int64            `mapstructure:"max-subscriptions,omitempty" default:"64"`
+	MaxUnaryRPC      int64            `mapstructure:"max-unary-rpc,omitempty" default:"64"`
 	TLS              *types.TLSConfig `mapstructure:"tls,omitempty"`
 	EnableMetrics    bool             `mapstructure:"enable-metrics,omitempty"`
 	Debug            bool             `mapstructure:"debug,omitempty"`
+	// attach the collector extension to the sent SubscribeResponses
+	CollectorExtension *types.CollectorExtensionConfig `mapstructure:"collector-extension,omitempty"`
 }
 
 func (g *gNMIOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
@@ -128,6 +130,7 @@ func (g *gNMIOutput) Write(ctx context.Context, rsp proto.Message, meta outputs.
 			if g.cfg.Debug {
 				g.logger.Printf("updating target %q local cache", target)
 			}
+			g.srv.ext.Received(target, meta["source"])
 			err = g.c.GnmiUpdate(rsp.Update)
 			if err != nil {
 				g.logger.Printf("failed to update gNMI cache: %v", err)
@@ -225,6 +228,7 @@ func (g *gNMIOutput) setDefaults() error {
 func (g *gNMIOutput) startGRPCServer() error {
 	g.srv.subscribeRPCsem = semaphore.NewWeighted(g.cfg.MaxSubscriptions)
 	g.srv.unaryRPCsem = semaphore.NewWeighted(g.cfg.MaxUnaryRPC)
+	g.srv.ext = outputs.NewCollectorExtension(g.cfg.CollectorExtension, "")
 	g.c.SetClient(g.srv.Update)
 
 	var l net.Listener
@@ -35,6 +35,7 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/gnmi/subscribe"
 	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/outputs"
 )
 
 type streamClient struct {
@@ -53,6 +54,8 @@ type server struct {
 	m               *match.Match
 	subscribeRPCsem *semaphore.Weighted
 	unaryRPCsem     *semaphore.Weighted
+	// collector extension, nil if not enabled
+	ext *outputs.CollectorExtension
 	//
 	mu      *sync.RWMutex
 	targets map[string]*types.TargetConfig
@@ -225,6 +228,10 @@ func (s *server) sendSubscribeResponse(r *resp, _ *streamClient) error {
 	if err != nil {
 		return status.Errorf(codes.Unknown, "unknown error: %v", err)
 	}
+	err = s.ext.Add(notif)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to add collector extension: %v", err)
+	}
 	// No acls
 	return r.stream.Send(notif)
 }
@@ -59,45 +59,50 @@ func init() {
 }
 
 type influxDBOutput struct {
-	Cfg       *Config
-	client    influxdb2.Client
-	logger    *log.Logger
-	cancelFn  context.CancelFunc
-	eventChan chan *formatters.EventMsg
-	reset     chan struct{}
-	startSig  chan struct{}
-	wasUP     bool
-	evps      []formatters.EventProcessor
-	dbVersion string
+	Cfg          *Config
+	client       influxdb2.Client
+	logger       *log.Logger
+	cancelFn     context.CancelFunc
+	eventChan    chan *formatters.EventMsg
+	reset        chan struct{}
+	startSig     chan struct{}
+	wasUP        bool
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	dbVersion    string
 
 	targetTpl *template.Template
 
 	gnmiCache   cache.Cache
 	cacheTicker *time.Ticker
 	done        chan struct{}
+
+	rollup *rollup
 }
 
 type Config struct {
-	URL                string           `mapstructure:"url,omitempty"`
-	Org                string           `mapstructure:"org,omitempty"`
-	Bucket             string           `mapstructure:"bucket,omitempty"`
-	Token              string           `mapstructure:"token,omitempty"`
-	BatchSize          uint             `mapstructure:"batch-size,omitempty"`
-	FlushTimer         time.Duration    `mapstructure:"flush-timer,omitempty"`
-	UseGzip            bool             `mapstructure:"use-gzip,omitempty"`
-	EnableTLS          bool             `mapstructure:"enable-tls,omitempty"`
-	TLS                *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	HealthCheckPeriod  time.Duration    `mapstructure:"health-check-period,omitempty"`
-	Debug              bool             `mapstructure:"debug,omitempty"`
-	AddTarget          string           `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string           `mapstructure:"target-template,omitempty"`
-	EventProcessors    []string         `mapstructure:"event-processors,omitempty"`
-	EnableMetrics      bool             `mapstructure:"enable-metrics,omitempty"`
-	OverrideTimestamps bool             `mapstructure:"override-timestamps,omitempty"`
-	TimestampPrecision string           `mapstructure:"timestamp-precision,omitempty"`
-	CacheConfig        *cache.Config    `mapstructure:"cache,omitempty"`
-	CacheFlushTimer    time.Duration    `mapstructure:"cache-flush-timer,omitempty"`
-	DeleteTag          string           `mapstructure:"delete-tag,omitempty"`
+	URL                string                   `mapstructure:"url,omitempty" default:"http://localhost:8086"`
+	Org                string                   `mapstructure:"org,omitempty"`
+	Bucket             string                   `mapstructure:"bucket,omitempty"`
+	Token              string                   `mapstructure:"token,omitempty"`
+	BatchSize          uint                     `mapstructure:"batch-size,omitempty" default:"1000"`
+	FlushTimer         time.Duration            `mapstructure:"flush-timer,omitempty" default:"10s"`
+	UseGzip            bool                     `mapstructure:"use-gzip,omitempty"`
+	EnableTLS          bool                     `mapstructure:"enable-tls,omitempty"`
+	TLS                *types.TLSConfig         `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	HealthCheckPeriod  time.Duration            `mapstructure:"health-check-period,omitempty"`
+	Debug              bool                     `mapstructure:"debug,omitempty"`
+	AddTarget          string                   `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                   `mapstructure:"target-template,omitempty"`
+	EventProcessors    []string                 `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat `mapstructure:"number-format,omitempty"`
+	EnableMetrics      bool                     `mapstructure:"enable-metrics,omitempty"`
+	OverrideTimestamps bool                     `mapstructure:"override-timestamps,omitempty"`
+	TimestampPrecision string                   `mapstructure:"timestamp-precision,omitempty"`
+	CacheConfig        *cache.Config            `mapstructure:"cache,omitempty"`
+	CacheFlushTimer    time.Duration            `mapstructure:"cache-flush-timer,omitempty" default:"5s"`
+	DeleteTag          string                   `mapstructure:"delete-tag,omitempty"`
+	Rollup             *RollupConfig            `mapstructure:"rollup,omitempty" json:"rollup,omitempty"`
 }
 
 func (k *influxDBOutput) String() string {
@@ -119,7 +124,11 @@ func (i *influxDBOutput) SetEventProcessors(ps map[string]map[string]interface{}
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := i.Cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	i.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	i.evps, err = formatters.MakeEventProcessors(
 		logger,
 		i.Cfg.EventProcessors,
@@ -130,6 +139,7 @@ func (i *influxDBOutput) SetEventProcessors(ps map[string]map[string]interface{}
 	if err != nil {
 		return err
 	}
+	i.evps = append(i.evps, nfps...)
 	return nil
 }
 
@@ -147,6 +157,14 @@ func (i *influxDBOutput) Init(ctx context.Context, name string, cfg map[string]i
 	}
 	i.setDefaults()
 
+	if i.Cfg.Rollup != nil {
+		err = i.Cfg.Rollup.validate(i.Cfg.Bucket)
+		if err != nil {
+			return err
+		}
+		i.rollup = newRollup(i.Cfg.Rollup, i.logger)
+	}
+
 	if i.Cfg.CacheConfig != nil {
 		err = i.initCache(ctx, name)
 		if err != nil {
@@ -187,6 +205,9 @@ CRCLIENT:
 	for k := 0; k < numWorkers; k++ {
 		go i.worker(ctx, k)
 	}
+	if i.rollup != nil {
+		go i.runRollup(ctx)
+	}
 	go func() {
 		<-ctx.Done()
 		i.Close()
@@ -233,7 +254,7 @@ func (i *influxDBOutput) Write(ctx context.Context, rsp proto.Message, meta outp
 			i.gnmiCache.Write(ctx, measName, rsp)
 			return
 		}
-		events, err := formatters.ResponseToEventMsgs(measName, rsp, meta, i.evps...)
+		events, err := formatters.ResponseToEventMsgs(measName, rsp, meta, i.evpOverrides.Select(meta, i.evps)...)
 		if err != nil {
 			i.logger.Printf("failed to convert message to event: %v", err)
 			return
@@ -365,6 +386,9 @@ START:
 			}
 
 			if len(ev.Values) > 0 {
+				if i.rollup != nil {
+					i.rollup.add(ev)
+				}
 				i.convertUints(ev)
 				writer.WritePoint(influxdb2.NewPoint(ev.Name, ev.Tags, ev.Values, time.Unix(0, ev.Timestamp)))
 			}
@@ -421,9 +445,7 @@ func (i *influxDBOutput) clientOpts() (*influxdb2.Options, error) {
 		SetBatchSize(i.Cfg.BatchSize).
 		SetFlushInterval(uint(i.Cfg.FlushTimer.Milliseconds()))
 	if i.Cfg.TLS != nil {
-		tlsConfig, err := utils.NewTLSConfig(
-			i.Cfg.TLS.CaFile, i.Cfg.TLS.CertFile, i.Cfg.TLS.KeyFile, "", i.Cfg.TLS.SkipVerify,
-			false)
+		tlsConfig, err := i.Cfg.TLS.ClientConfig()
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,25 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_output
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/cache"
+)
+
+func TestSetDefaultsTags(t *testing.T) {
+	// the cache-flush-timer is only defaulted if a cache is configured.
+	i := &influxDBOutput{Cfg: &Config{CacheConfig: &cache.Config{}}}
+	i.setDefaults()
+	for _, m := range testutils.DefaultTagsMismatches(i.Cfg, "cache") {
+		t.Error(m)
+	}
+}
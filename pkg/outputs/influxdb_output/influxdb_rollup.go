@@ -0,0 +1,313 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	defaultRollupInterval = time.Minute
+	minRollupTick         = time.Second
+)
+
+var rollupAggregations = map[string]struct{}{
+	"mean":  {},
+	"min":   {},
+	"max":   {},
+	"sum":   {},
+	"count": {},
+	"first": {},
+	"last":  {},
+}
+
+// RollupConfig configures the pre-aggregated points written
+// to a secondary bucket and/or measurement.
+type RollupConfig struct {
+	// bucket the rollups are written to, defaults to the output bucket.
+	Bucket string `mapstructure:"bucket,omitempty" json:"bucket,omitempty"`
+	// suffix appended to the measurement name of the rollup points.
+	MeasurementSuffix string `mapstructure:"measurement-suffix,omitempty" json:"measurement-suffix,omitempty"`
+	// duration of the aggregation windows.
+	Interval time.Duration `mapstructure:"interval,omitempty" json:"interval,omitempty" default:"1m"`
+	// aggregations computed for each numeric field.
+	Aggregations []string `mapstructure:"aggregations,omitempty" json:"aggregations,omitempty"`
+	// time waited after the end of a window before writing it,
+	// to account for late events.
+	Delay time.Duration `mapstructure:"delay,omitempty" json:"delay,omitempty"`
+}
+
+// rollup aggregates the numeric fields of the points written by the output
+// per series (measurement and tags) and per time window.
+type rollup struct {
+	cfg    *RollupConfig
+	logger *log.Logger
+
+	m sync.Mutex
+	// open windows indexed by series key and window start
+	windows map[rollupKey]*rollupWindow
+	// start of the oldest window that can still be updated
+	watermark int64
+	dropped   int
+}
+
+type rollupKey struct {
+	series string
+	start  int64
+}
+
+type rollupWindow struct {
+	name   string
+	tags   map[string]string
+	fields map[string]*rollupField
+}
+
+type rollupField struct {
+	count       int64
+	sum         float64
+	min         float64
+	max         float64
+	first, last float64
+	firstTS     int64
+	lastTS      int64
+}
+
+func (c *RollupConfig) validate(bucket string) error {
+	if c.Interval <= 0 {
+		c.Interval = defaultRollupInterval
+	}
+	if c.Delay < 0 {
+		return errors.New("rollup delay must be positive")
+	}
+	if len(c.Aggregations) == 0 {
+		c.Aggregations = []string{"mean"}
+	}
+	for _, agg := range c.Aggregations {
+		if _, ok := rollupAggregations[agg]; !ok {
+			return fmt.Errorf("unknown rollup aggregation %q", agg)
+		}
+	}
+	if (c.Bucket == "" || c.Bucket == bucket) && c.MeasurementSuffix == "" {
+		return errors.New("rollup requires a bucket different from the output bucket or a measurement-suffix")
+	}
+	if c.Bucket == "" {
+		c.Bucket = bucket
+	}
+	return nil
+}
+
+func newRollup(cfg *RollupConfig, logger *log.Logger) *rollup {
+	return &rollup{
+		cfg:     cfg,
+		logger:  logger,
+		windows: make(map[rollupKey]*rollupWindow),
+	}
+}
+
+// add aggregates the numeric values of ev in its time window.
+func (r *rollup) add(ev *formatters.EventMsg) {
+	interval := r.cfg.Interval.Nanoseconds()
+	start := ev.Timestamp - ev.Timestamp%interval
+	if ev.Timestamp < 0 && ev.Timestamp%interval != 0 {
+		start -= interval
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	if start < r.watermark {
+		r.dropped++
+		return
+	}
+	key := rollupKey{series: seriesKey(ev), start: start}
+	w, ok := r.windows[key]
+	if !ok {
+		w = &rollupWindow{
+			name:   ev.Name + r.cfg.MeasurementSuffix,
+			tags:   make(map[string]string, len(ev.Tags)),
+			fields: make(map[string]*rollupField),
+		}
+		for k, v := range ev.Tags {
+			w.tags[k] = v
+		}
+		r.windows[key] = w
+	}
+	for k, v := range ev.Values {
+		f, ok := toFloat(v)
+		if !ok {
+			continue
+		}
+		w.add(k, f, ev.Timestamp)
+	}
+}
+
+func (w *rollupWindow) add(name string, v float64, ts int64) {
+	f, ok := w.fields[name]
+	if !ok {
+		w.fields[name] = &rollupField{
+			count: 1, sum: v, min: v, max: v,
+			first: v, last: v, firstTS: ts, lastTS: ts,
+		}
+		return
+	}
+	f.count++
+	f.sum += v
+	f.min = math.Min(f.min, v)
+	f.max = math.Max(f.max, v)
+	if ts < f.firstTS {
+		f.first, f.firstTS = v, ts
+	}
+	if ts >= f.lastTS {
+		f.last, f.lastTS = v, ts
+	}
+}
+
+// flush returns the points of the windows ended before now minus the configured delay,
+// and stops accepting events for them.
+func (r *rollup) flush(now time.Time) []*write.Point {
+	interval := r.cfg.Interval.Nanoseconds()
+	cutoff := now.Add(-r.cfg.Delay).UnixNano()
+	// start of the first window not ended at cutoff
+	cutoff -= cutoff % interval
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if cutoff > r.watermark {
+		r.watermark = cutoff
+	}
+	if r.dropped > 0 {
+		r.logger.Printf("rollup: dropped %d late events", r.dropped)
+		r.dropped = 0
+	}
+	points := make([]*write.Point, 0)
+	for k, w := range r.windows {
+		if k.start+interval > cutoff {
+			continue
+		}
+		delete(r.windows, k)
+		if len(w.fields) == 0 {
+			continue
+		}
+		points = append(points, influxdb2.NewPoint(w.name, w.tags, w.aggregate(r.cfg.Aggregations), time.Unix(0, k.start)))
+	}
+	return points
+}
+
+func (w *rollupWindow) aggregate(aggs []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(w.fields)*len(aggs))
+	for name, f := range w.fields {
+		for _, agg := range aggs {
+			var v interface{}
+			switch agg {
+			case "mean":
+				v = f.sum / float64(f.count)
+			case "min":
+				v = f.min
+			case "max":
+				v = f.max
+			case "sum":
+				v = f.sum
+			case "count":
+				v = f.count
+			case "first":
+				v = f.first
+			case "last":
+				v = f.last
+			}
+			values[name+"_"+agg] = v
+		}
+	}
+	return values
+}
+
+// runRollup periodically writes the ended rollup windows to the rollup bucket.
+func (i *influxDBOutput) runRollup(ctx context.Context) {
+	tick := i.rollup.cfg.Interval / 4
+	if tick < minRollupTick {
+		tick = minRollupTick
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	writer := i.client.WriteAPI(i.Cfg.Org, i.rollup.cfg.Bucket)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-writer.Errors():
+			i.logger.Printf("rollup write error: %v", err)
+		case now := <-ticker.C:
+			points := i.rollup.flush(now)
+			if i.Cfg.Debug && len(points) > 0 {
+				i.logger.Printf("writing %d rollup points to bucket %q", len(points), i.rollup.cfg.Bucket)
+			}
+			for _, p := range points {
+				writer.WritePoint(p)
+			}
+		}
+	}
+}
+
+// seriesKey identifies the series of an event by its name and sorted tags.
+func seriesKey(ev *formatters.EventMsg) string {
+	keys := make([]string, 0, len(ev.Tags))
+	for k := range ev.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sb := new(strings.Builder)
+	sb.WriteString(ev.Name)
+	for _, k := range keys {
+		sb.WriteString(",")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(ev.Tags[k])
+	}
+	return sb.String()
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,146 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_output
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestRollup(t *testing.T) {
+	cfg := &RollupConfig{
+		MeasurementSuffix: "_1m",
+		Aggregations:      []string{"mean", "max", "count", "last"},
+		Delay:             5 * time.Second,
+	}
+	if err := cfg.validate("telemetry"); err != nil {
+		t.Fatal(err)
+	}
+	r := newRollup(cfg, log.New(io.Discard, "", 0))
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ev := func(ts time.Duration, intf string, v interface{}) *formatters.EventMsg {
+		return &formatters.EventMsg{
+			Name:      "sub1",
+			Timestamp: t0.Add(ts).UnixNano(),
+			Tags:      map[string]string{"interface_name": intf},
+			Values:    map[string]interface{}{"in-octets": v, "oper-status": "UP"},
+		}
+	}
+	r.add(ev(10*time.Second, "e1", uint64(10)))
+	r.add(ev(40*time.Second, "e1", int64(30)))
+	r.add(ev(20*time.Second, "e2", 5.5))
+	r.add(ev(70*time.Second, "e1", uint64(100)))
+
+	// first window not ended yet
+	if points := r.flush(t0.Add(62 * time.Second)); len(points) != 0 {
+		t.Fatalf("expected no points, got %d", len(points))
+	}
+	points := r.flush(t0.Add(65 * time.Second))
+	if len(points) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(points))
+	}
+	for _, p := range points {
+		if p.Name() != "sub1_1m" {
+			t.Errorf("unexpected measurement %q", p.Name())
+		}
+		if !p.Time().Equal(t0) {
+			t.Errorf("unexpected point time %s", p.Time())
+		}
+		fields := make(map[string]interface{})
+		for _, f := range p.FieldList() {
+			fields[f.Key] = f.Value
+		}
+		var expected map[string]interface{}
+		switch p.TagList()[0].Value {
+		case "e1":
+			expected = map[string]interface{}{
+				"in-octets_mean":  20.0,
+				"in-octets_max":   30.0,
+				"in-octets_count": int64(2),
+				"in-octets_last":  30.0,
+			}
+		case "e2":
+			expected = map[string]interface{}{
+				"in-octets_mean":  5.5,
+				"in-octets_max":   5.5,
+				"in-octets_count": int64(1),
+				"in-octets_last":  5.5,
+			}
+		}
+		if len(fields) != len(expected) {
+			t.Errorf("unexpected fields %v", fields)
+		}
+		for k, v := range expected {
+			if fields[k] != v {
+				t.Errorf("%s: field %s: expected %v, got %v", p.TagList()[0].Value, k, v, fields[k])
+			}
+		}
+	}
+	// late event for a written window
+	r.add(ev(50*time.Second, "e1", uint64(1)))
+	points = r.flush(t0.Add(125 * time.Second))
+	if len(points) != 1 {
+		t.Fatalf("expected 1 point, got %d", len(points))
+	}
+	for _, f := range points[0].FieldList() {
+		if f.Key == "in-octets_count" && f.Value != int64(1) {
+			t.Errorf("expected the late event to be dropped, got count %v", f.Value)
+		}
+	}
+}
+
+func TestRollupConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		cfg *RollupConfig
+		err bool
+	}{
+		"defaults": {
+			cfg: &RollupConfig{Bucket: "telemetry_1m"},
+		},
+		"same_bucket_and_measurement": {
+			cfg: &RollupConfig{Bucket: "telemetry"},
+			err: true,
+		},
+		"unknown_aggregation": {
+			cfg: &RollupConfig{MeasurementSuffix: "_1m", Aggregations: []string{"median"}},
+			err: true,
+		},
+		"negative_delay": {
+			cfg: &RollupConfig{MeasurementSuffix: "_1m", Delay: -time.Second},
+			err: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.cfg.validate("telemetry")
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && (tt.cfg.Interval != defaultRollupInterval || len(tt.cfg.Aggregations) != 1) {
+				t.Errorf("unexpected defaults: %+v", tt.cfg)
+			}
+		})
+	}
+}
+
+func TestRollupConfigDefaultsTags(t *testing.T) {
+	cfg := &RollupConfig{MeasurementSuffix: "_1m"}
+	if err := cfg.validate("telemetry"); err != nil {
+		t.Fatal(err)
+	}
+	// the bucket defaults to the output bucket.
+	for _, m := range testutils.DefaultTagsMismatches(cfg, "measurement-suffix", "bucket") {
+		t.Error(m)
+	}
+}
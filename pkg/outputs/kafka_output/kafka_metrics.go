@@ -31,6 +31,13 @@ var kafkaNumberOfFailSendMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Help:      "Number of failed msgs sent by gnmic kafka output",
 }, []string{"producer_id", "reason"})
 
+var kafkaNumberOfInvalidTopicMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "kafka_output",
+	Name:      "number_of_kafka_msgs_invalid_topic_total",
+	Help:      "Number of msgs with an invalid topic name, routed to the fallback topic or dropped",
+}, []string{"producer_id", "action"})
+
 var kafkaSendDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	Namespace: "gnmic",
 	Subsystem: "kafka_output",
@@ -42,6 +49,7 @@ func initMetrics() {
 	kafkaNumberOfSentMsgs.WithLabelValues("").Add(0)
 	kafkaNumberOfSentBytes.WithLabelValues("").Add(0)
 	kafkaNumberOfFailSendMsgs.WithLabelValues("", "").Add(0)
+	kafkaNumberOfInvalidTopicMsgs.WithLabelValues("", "").Add(0)
 	kafkaSendDuration.WithLabelValues("").Set(0)
 }
 
@@ -57,6 +65,9 @@ func registerMetrics(reg *prometheus.Registry) error {
 	if err = reg.Register(kafkaNumberOfFailSendMsgs); err != nil {
 		return err
 	}
+	if err = reg.Register(kafkaNumberOfInvalidTopicMsgs); err != nil {
+		return err
+	}
 	if err = reg.Register(kafkaSendDuration); err != nil {
 		return err
 	}
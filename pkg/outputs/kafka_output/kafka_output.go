@@ -16,8 +16,10 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -53,6 +55,7 @@ func init() {
 	outputs.Register("kafka", func() outputs.Output {
 		return &kafkaOutput{
 			cfg:    &config{},
+			seq:    outputs.NewSequencer(),
 			wg:     new(sync.WaitGroup),
 			logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
 		}
@@ -61,46 +64,82 @@ func init() {
 
 // kafkaOutput //
 type kafkaOutput struct {
-	cfg      *config
-	logger   sarama.StdLogger
-	mo       *formatters.MarshalOptions
-	cancelFn context.CancelFunc
-	msgChan  chan *outputs.ProtoMsg
-	wg       *sync.WaitGroup
-	evps     []formatters.EventProcessor
+	cfg          *config
+	logger       sarama.StdLogger
+	mo           *formatters.MarshalOptions
+	cancelFn     context.CancelFunc
+	msgChan      chan *outputs.ProtoMsg
+	seq          *outputs.Sequencer
+	wg           *sync.WaitGroup
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	envelope     *outputs.Envelope
+	evChan       chan *eventMsg
+	// tracks the messages not yet acknowledged by the brokers
+	delivery outputs.DeliveryTracker
+	// per worker health status
+	workersHealth []atomic.Bool
+	// true if at least one target has ordered-delivery enabled
+	orderedDelivery bool
+	// logs once that sequenced messages are sent
+	// without the producer settings required to keep them in order
+	orderedWarnOnce sync.Once
+
+	targetTpl  *template.Template
+	msgTpl     *template.Template
+	headersTpl map[string]*template.Template
+	// sorted headers keys
+	headersKeys []string
+}
+
+// eventMsg is an event written to the output
+// and the delivery it is accounted to.
+type eventMsg struct {
+	ev       *formatters.EventMsg
+	delivery *outputs.Delivery
+}
 
-	targetTpl *template.Template
-	msgTpl    *template.Template
+// producerMsgMeta is set as the metadata of the messages
+// sent by the async producers.
+type producerMsgMeta struct {
+	start    time.Time
+	delivery *outputs.Delivery
 }
 
 // config //
 type config struct {
-	Address            string           `mapstructure:"address,omitempty"`
-	Topic              string           `mapstructure:"topic,omitempty"`
-	TopicPrefix        string           `mapstructure:"topic-prefix,omitempty"`
-	Name               string           `mapstructure:"name,omitempty"`
-	SASL               *types.SASL      `mapstructure:"sasl,omitempty"`
-	TLS                *types.TLSConfig `mapstructure:"tls,omitempty"`
-	MaxRetry           int              `mapstructure:"max-retry,omitempty"`
-	Timeout            time.Duration    `mapstructure:"timeout,omitempty"`
-	RecoveryWaitTime   time.Duration    `mapstructure:"recovery-wait-time,omitempty"`
-	FlushFrequency     time.Duration    `mapstructure:"flush-frequency,omitempty"`
-	SyncProducer       bool             `mapstructure:"sync-producer,omitempty"`
-	RequiredAcks       string           `mapstructure:"required-acks,omitempty"`
-	Format             string           `mapstructure:"format,omitempty"`
-	InsertKey          bool             `mapstructure:"insert-key,omitempty"`
-	AddTarget          string           `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string           `mapstructure:"target-template,omitempty"`
-	MsgTemplate        string           `mapstructure:"msg-template,omitempty"`
-	SplitEvents        bool             `mapstructure:"split-events,omitempty"`
-	NumWorkers         int              `mapstructure:"num-workers,omitempty"`
-	CompressionCodec   string           `mapstructure:"compression-codec,omitempty"`
-	KafkaVersion       string           `mapstructure:"kafka-version,omitempty"`
-	Debug              bool             `mapstructure:"debug,omitempty"`
-	BufferSize         int              `mapstructure:"buffer-size,omitempty"`
-	OverrideTimestamps bool             `mapstructure:"override-timestamps,omitempty"`
-	EnableMetrics      bool             `mapstructure:"enable-metrics,omitempty"`
-	EventProcessors    []string         `mapstructure:"event-processors,omitempty"`
+	Address            string                    `mapstructure:"address,omitempty" default:"localhost:9092"`
+	Topic              string                    `mapstructure:"topic,omitempty" default:"telemetry"`
+	TopicPrefix        string                    `mapstructure:"topic-prefix,omitempty"`
+	FallbackTopic      string                    `mapstructure:"fallback-topic,omitempty"`
+	Name               string                    `mapstructure:"name,omitempty"`
+	SASL               *types.SASL               `mapstructure:"sasl,omitempty"`
+	TLS                *types.TLSConfig          `mapstructure:"tls,omitempty"`
+	SharedConnection   bool                      `mapstructure:"shared-connection,omitempty"`
+	MaxRetry           int                       `mapstructure:"max-retry,omitempty" default:"2"`
+	Timeout            time.Duration             `mapstructure:"timeout,omitempty" default:"5s"`
+	RecoveryWaitTime   time.Duration             `mapstructure:"recovery-wait-time,omitempty" default:"10s"`
+	FlushFrequency     time.Duration             `mapstructure:"flush-frequency,omitempty"`
+	SyncProducer       bool                      `mapstructure:"sync-producer,omitempty"`
+	Idempotent         bool                      `mapstructure:"idempotent,omitempty"`
+	RequiredAcks       string                    `mapstructure:"required-acks,omitempty"`
+	Format             string                    `mapstructure:"format,omitempty" default:"event"`
+	InsertKey          bool                      `mapstructure:"insert-key,omitempty"`
+	AddTarget          string                    `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                    `mapstructure:"target-template,omitempty"`
+	MsgTemplate        string                    `mapstructure:"msg-template,omitempty"`
+	SplitEvents        bool                      `mapstructure:"split-events,omitempty"`
+	NumWorkers         int                       `mapstructure:"num-workers,omitempty" default:"1"`
+	CompressionCodec   string                    `mapstructure:"compression-codec,omitempty"`
+	KafkaVersion       string                    `mapstructure:"kafka-version,omitempty"`
+	Debug              bool                      `mapstructure:"debug,omitempty"`
+	BufferSize         int                       `mapstructure:"buffer-size,omitempty"`
+	OverrideTimestamps bool                      `mapstructure:"override-timestamps,omitempty"`
+	EnableMetrics      bool                      `mapstructure:"enable-metrics,omitempty"`
+	EventProcessors    []string                  `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat  `mapstructure:"number-format,omitempty"`
+	Headers            map[string]string         `mapstructure:"headers,omitempty"`
+	Encryption         *outputs.EncryptionConfig `mapstructure:"encryption,omitempty"`
 }
 
 func (k *kafkaOutput) String() string {
@@ -122,7 +161,11 @@ func (k *kafkaOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := k.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	k.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	k.evps, err = formatters.MakeEventProcessors(
 		logger,
 		k.cfg.EventProcessors,
@@ -133,6 +176,7 @@ func (k *kafkaOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	k.evps = append(k.evps, nfps...)
 	return nil
 }
 
@@ -154,7 +198,13 @@ func (k *kafkaOutput) Init(ctx context.Context, name string, cfg map[string]inte
 	if err != nil {
 		return err
 	}
+	k.envelope, err = outputs.NewEnvelope(k.cfg.Encryption)
+	if err != nil {
+		return err
+	}
 	k.msgChan = make(chan *outputs.ProtoMsg, uint(k.cfg.BufferSize))
+	k.evChan = make(chan *eventMsg, uint(k.cfg.BufferSize))
+	k.workersHealth = make([]atomic.Bool, k.cfg.NumWorkers)
 	k.mo = &formatters.MarshalOptions{
 		Format:     k.cfg.Format,
 		OverrideTS: k.cfg.OverrideTimestamps,
@@ -178,6 +228,11 @@ func (k *kafkaOutput) Init(ctx context.Context, name string, cfg map[string]inte
 		k.msgTpl = k.msgTpl.Funcs(outputs.TemplateFuncs)
 	}
 
+	err = k.initHeadersTemplates()
+	if err != nil {
+		return err
+	}
+
 	config, err := k.createConfig()
 	if err != nil {
 		return err
@@ -189,6 +244,7 @@ func (k *kafkaOutput) Init(ctx context.Context, name string, cfg map[string]inte
 		cfg.ClientID = fmt.Sprintf("%s-%d", config.ClientID, i)
 		go k.worker(ctx, i, &cfg)
 	}
+	go k.watchHealth(ctx, config)
 	go func() {
 		<-ctx.Done()
 		k.Close()
@@ -200,7 +256,8 @@ func (k *kafkaOutput) setDefaults() error {
 	if k.cfg.Format == "" {
 		k.cfg.Format = defaultFormat
 	}
-	if !(k.cfg.Format == "event" || k.cfg.Format == "protojson" || k.cfg.Format == "prototext" || k.cfg.Format == "proto" || k.cfg.Format == "json") {
+	if !(k.cfg.Format == "event" || k.cfg.Format == "protojson" || k.cfg.Format == "prototext" || k.cfg.Format == "proto" || k.cfg.Format == "json" ||
+		formatters.IsLineFormat(k.cfg.Format)) {
 		return fmt.Errorf("unsupported output format '%s' for output type kafka", k.cfg.Format)
 	}
 	if k.cfg.Address == "" {
@@ -209,6 +266,11 @@ func (k *kafkaOutput) setDefaults() error {
 	if k.cfg.Topic == "" {
 		k.cfg.Topic = defaultKafkaTopic
 	}
+	if k.cfg.FallbackTopic != "" {
+		if err := outputs.ValidateKafkaTopic(k.cfg.FallbackTopic); err != nil {
+			return fmt.Errorf("invalid fallback-topic: %w", err)
+		}
+	}
 	if k.cfg.MaxRetry == 0 {
 		k.cfg.MaxRetry = defaultKafkaMaxRetry
 	}
@@ -224,6 +286,14 @@ func (k *kafkaOutput) setDefaults() error {
 	if k.cfg.Name == "" {
 		k.cfg.Name = "gnmic-" + uuid.New().String()
 	}
+	if k.cfg.Idempotent {
+		switch k.cfg.RequiredAcks {
+		case "", requiredAcksWaitForAll:
+			k.cfg.RequiredAcks = requiredAcksWaitForAll
+		default:
+			return fmt.Errorf("an idempotent kafka producer requires `required-acks` to be %q", requiredAcksWaitForAll)
+		}
+	}
 	if k.cfg.SASL == nil {
 		return nil
 	}
@@ -258,11 +328,21 @@ func (k *kafkaOutput) Write(ctx context.Context, rsp proto.Message, meta outputs
 	wctx, cancel := context.WithTimeout(ctx, k.cfg.Timeout)
 	defer cancel()
 
+	d := outputs.DeliveryFrom(ctx)
+	k.seq.Add(meta)
+	k.delivery.Add(1)
+	d.Add(1)
 	select {
 	case <-ctx.Done():
+		k.seq.Done(meta)
+		k.delivery.Done(ctx.Err())
+		d.Done(ctx.Err())
 		return
-	case k.msgChan <- outputs.NewProtoMsg(rsp, meta):
+	case k.msgChan <- outputs.NewProtoMsg(rsp, meta).WithDelivery(d):
 	case <-wctx.Done():
+		k.seq.Done(meta)
+		k.delivery.Done(wctx.Err())
+		d.Done(wctx.Err())
 		if k.cfg.Debug {
 			k.logger.Printf("writing expired after %s, Kafka output might not be initialized", k.cfg.Timeout)
 		}
@@ -273,7 +353,47 @@ func (k *kafkaOutput) Write(ctx context.Context, rsp proto.Message, meta outputs
 	}
 }
 
-func (k *kafkaOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {}
+// WriteEvent //
+func (k *kafkaOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	d := outputs.DeliveryFrom(ctx)
+	// events cannot be written as proto messages
+	if k.cfg.Format == "proto" {
+		if k.cfg.Debug {
+			k.logger.Printf("events cannot be written with format %q", k.cfg.Format)
+		}
+		d.Fail()
+		return
+	}
+
+	wctx, cancel := context.WithTimeout(ctx, k.cfg.Timeout)
+	defer cancel()
+
+	k.delivery.Add(1)
+	d.Add(1)
+	// the event is copied since it might be written to other outputs
+	// while the event processors of this one modify it.
+	em := &eventMsg{ev: formatters.CopyEventMsg(ev), delivery: d}
+	select {
+	case <-ctx.Done():
+		k.delivery.Done(ctx.Err())
+		d.Done(ctx.Err())
+		return
+	case k.evChan <- em:
+	case <-wctx.Done():
+		k.delivery.Done(wctx.Err())
+		d.Done(wctx.Err())
+		if k.cfg.Debug {
+			k.logger.Printf("writing expired after %s, Kafka output might not be initialized", k.cfg.Timeout)
+		}
+		if k.cfg.EnableMetrics {
+			kafkaNumberOfFailSendMsgs.WithLabelValues(k.cfg.Name, "timeout").Inc()
+		}
+		return
+	}
+}
 
 // Close //
 func (k *kafkaOutput) Close() error {
@@ -306,18 +426,21 @@ func (k *kafkaOutput) worker(ctx context.Context, idx int, config *sarama.Config
 
 func (k *kafkaOutput) asyncProducerWorker(ctx context.Context, idx int, config *sarama.Config) {
 	var producer sarama.AsyncProducer
+	var closeProducer func()
 	var err error
 	defer k.wg.Done()
 	workerLogPrefix := fmt.Sprintf("worker-%d", idx)
 	k.logger.Printf("%s starting", workerLogPrefix)
 CRPROD:
-	producer, err = sarama.NewAsyncProducer(strings.Split(k.cfg.Address, ","), config)
+	producer, closeProducer, err = k.newAsyncProducer(config)
 	if err != nil {
 		k.logger.Printf("%s failed to create kafka producer: %v", workerLogPrefix, err)
+		k.workersHealth[idx].Store(false)
 		time.Sleep(k.cfg.RecoveryWaitTime)
 		goto CRPROD
 	}
-	defer producer.Close()
+	defer closeProducer()
+	k.workersHealth[idx].Store(true)
 	k.logger.Printf("%s initialized kafka producer: %s", workerLogPrefix, k.String())
 
 	go func() {
@@ -329,10 +452,15 @@ CRPROD:
 				if !ok {
 					return
 				}
+				k.workersHealth[idx].Store(true)
+				k.delivery.Done(nil)
+				pm, _ := msg.Metadata.(*producerMsgMeta)
+				if pm != nil {
+					pm.delivery.Done(nil)
+				}
 				if k.cfg.EnableMetrics {
-					start, ok := msg.Metadata.(time.Time)
-					if ok {
-						kafkaSendDuration.WithLabelValues(config.ClientID).Set(float64(time.Since(start).Nanoseconds()))
+					if pm != nil {
+						kafkaSendDuration.WithLabelValues(config.ClientID).Set(float64(time.Since(pm.start).Nanoseconds()))
 					}
 					kafkaNumberOfSentMsgs.WithLabelValues(config.ClientID).Inc()
 					kafkaNumberOfSentBytes.WithLabelValues(config.ClientID).Add(float64(msg.Value.Length()))
@@ -341,6 +469,11 @@ CRPROD:
 				if !ok {
 					return
 				}
+				k.workersHealth[idx].Store(false)
+				k.delivery.Done(err.Err)
+				if pm, ok := err.Msg.Metadata.(*producerMsgMeta); ok {
+					pm.delivery.Done(err.Err)
+				}
 				if k.cfg.Debug {
 					k.logger.Printf("%s failed to send a kafka msg to topic '%s': %v", workerLogPrefix, err.Msg.Topic, err.Err)
 				}
@@ -357,69 +490,57 @@ CRPROD:
 			k.logger.Printf("%s shutting down", workerLogPrefix)
 			return
 		case m := <-k.msgChan:
-			pmsg := m.GetMsg()
-			pmsg, err = outputs.AddSubscriptionTarget(pmsg, m.GetMeta(), k.cfg.AddTarget, k.targetTpl)
-			if err != nil {
-				k.logger.Printf("failed to add target to the response: %v", err)
-			}
-			bb, err := outputs.Marshal(pmsg, m.GetMeta(), k.mo, k.cfg.SplitEvents, k.evps...)
-			if err != nil {
-				if k.cfg.Debug {
-					k.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
-				}
-				if k.cfg.EnableMetrics {
-					kafkaNumberOfFailSendMsgs.WithLabelValues(config.ClientID, "marshal_error").Inc()
-				}
-				continue
-			}
+			bb := k.marshalMsg(m, workerLogPrefix, config.ClientID)
 			if len(bb) == 0 {
+				k.seq.Done(m.GetMeta())
+				k.delivery.Done(nil)
+				m.GetDelivery().Done(nil)
 				continue
 			}
-			for _, b := range bb {
-				if k.msgTpl != nil {
-					b, err = outputs.ExecTemplate(b, k.msgTpl)
-					if err != nil {
-						if k.cfg.Debug {
-							log.Printf("failed to execute template: %v", err)
-						}
-						kafkaNumberOfFailSendMsgs.WithLabelValues(config.ClientID, "template_error").Inc()
-						continue
-					}
-				}
-
-				topic := k.selectTopic(m.GetMeta())
-				msg := &sarama.ProducerMessage{
-					Topic: topic,
-					Value: sarama.ByteEncoder(b),
-				}
-				if k.cfg.InsertKey {
-					msg.Key = sarama.ByteEncoder(k.partitionKey(m.GetMeta()))
-				}
-				var start time.Time
-				if k.cfg.EnableMetrics {
-					start = time.Now()
-					msg.Metadata = start
-				}
-				producer.Input() <- msg
+			k.seq.Wait(ctx, m.GetMeta())
+			for _, msg := range k.producerMessages(bb, m.GetMeta(), workerLogPrefix, config.ClientID) {
+				k.produce(producer, msg, m.GetDelivery())
+			}
+			k.seq.Done(m.GetMeta())
+			k.delivery.Done(nil)
+			m.GetDelivery().Done(nil)
+		case em := <-k.evChan:
+			bb, meta := k.marshalEvent(em.ev, workerLogPrefix, config.ClientID)
+			for _, msg := range k.producerMessages(bb, meta, workerLogPrefix, config.ClientID) {
+				k.produce(producer, msg, em.delivery)
 			}
+			k.delivery.Done(nil)
+			em.delivery.Done(nil)
 		}
 	}
 }
 
+// produce sends msg using the async producer, the delivery d is confirmed
+// once the message is returned on the producer successes or errors channels.
+func (k *kafkaOutput) produce(producer sarama.AsyncProducer, msg *sarama.ProducerMessage, d *outputs.Delivery) {
+	msg.Metadata = &producerMsgMeta{start: time.Now(), delivery: d}
+	k.delivery.Add(1)
+	d.Add(1)
+	producer.Input() <- msg
+}
+
 func (k *kafkaOutput) syncProducerWorker(ctx context.Context, idx int, config *sarama.Config) {
 	var producer sarama.SyncProducer
+	var closeProducer func()
 	var err error
 	defer k.wg.Done()
 	workerLogPrefix := fmt.Sprintf("worker-%d", idx)
 	k.logger.Printf("%s starting", workerLogPrefix)
 CRPROD:
-	producer, err = sarama.NewSyncProducer(strings.Split(k.cfg.Address, ","), config)
+	producer, closeProducer, err = k.newSyncProducer(config)
 	if err != nil {
 		k.logger.Printf("%s failed to create kafka producer: %v", workerLogPrefix, err)
+		k.workersHealth[idx].Store(false)
 		time.Sleep(k.cfg.RecoveryWaitTime)
 		goto CRPROD
 	}
-	defer producer.Close()
+	defer closeProducer()
+	k.workersHealth[idx].Store(true)
 	k.logger.Printf("%s initialized kafka producer: %s", workerLogPrefix, k.String())
 	for {
 		select {
@@ -427,65 +548,241 @@ CRPROD:
 			k.logger.Printf("%s shutting down", workerLogPrefix)
 			return
 		case m := <-k.msgChan:
-			pmsg := m.GetMsg()
-			pmsg, err = outputs.AddSubscriptionTarget(pmsg, m.GetMeta(), k.cfg.AddTarget, k.targetTpl)
-			if err != nil {
-				k.logger.Printf("failed to add target to the response: %v", err)
+			bb := k.marshalMsg(m, workerLogPrefix, config.ClientID)
+			if len(bb) == 0 {
+				k.seq.Done(m.GetMeta())
+				k.delivery.Done(nil)
+				m.GetDelivery().Done(nil)
+				continue
+			}
+			k.seq.Wait(ctx, m.GetMeta())
+			err = k.sendSync(producer, k.producerMessages(bb, m.GetMeta(), workerLogPrefix, config.ClientID), workerLogPrefix, config.ClientID)
+			k.seq.Done(m.GetMeta())
+			k.delivery.Done(err)
+			m.GetDelivery().Done(err)
+		case em := <-k.evChan:
+			bb, meta := k.marshalEvent(em.ev, workerLogPrefix, config.ClientID)
+			err = k.sendSync(producer, k.producerMessages(bb, meta, workerLogPrefix, config.ClientID), workerLogPrefix, config.ClientID)
+			k.delivery.Done(err)
+			em.delivery.Done(err)
+		}
+		if err != nil {
+			k.workersHealth[idx].Store(false)
+			closeProducer()
+			time.Sleep(k.cfg.RecoveryWaitTime)
+			goto CRPROD
+		}
+	}
+}
+
+// sendSync sends msgs using the sync producer,
+// it stops at the first message that fails to be sent.
+func (k *kafkaOutput) sendSync(producer sarama.SyncProducer, msgs []*sarama.ProducerMessage, workerLogPrefix, clientID string) error {
+	for _, msg := range msgs {
+		start := time.Now()
+		_, _, err := producer.SendMessage(msg)
+		if err != nil {
+			if k.cfg.Debug {
+				k.logger.Printf("%s failed to send a kafka msg to topic '%s': %v", workerLogPrefix, msg.Topic, err)
+			}
+			if k.cfg.EnableMetrics {
+				kafkaNumberOfFailSendMsgs.WithLabelValues(clientID, "send_error").Inc()
 			}
-			bb, err := outputs.Marshal(pmsg, m.GetMeta(), k.mo, k.cfg.SplitEvents, k.evps...)
+			return err
+		}
+		if k.cfg.EnableMetrics {
+			kafkaSendDuration.WithLabelValues(clientID).Set(float64(time.Since(start).Nanoseconds()))
+			kafkaNumberOfSentMsgs.WithLabelValues(clientID).Inc()
+			kafkaNumberOfSentBytes.WithLabelValues(clientID).Add(float64(msg.Value.Length()))
+		}
+	}
+	return nil
+}
+
+// marshalMsg returns the kafka messages values of the proto message m.
+func (k *kafkaOutput) marshalMsg(m *outputs.ProtoMsg, workerLogPrefix, clientID string) [][]byte {
+	pmsg, err := outputs.AddSubscriptionTarget(m.GetMsg(), m.GetMeta(), k.cfg.AddTarget, k.targetTpl)
+	if err != nil {
+		k.logger.Printf("failed to add target to the response: %v", err)
+	}
+	bb, err := outputs.Marshal(pmsg, m.GetMeta(), k.mo, k.cfg.SplitEvents, k.evpOverrides.Select(m.GetMeta(), k.evps)...)
+	if err != nil {
+		if k.cfg.Debug {
+			k.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
+		}
+		if k.cfg.EnableMetrics {
+			kafkaNumberOfFailSendMsgs.WithLabelValues(clientID, "marshal_error").Inc()
+		}
+		return nil
+	}
+	return bb
+}
+
+// marshalEvent applies the event processors to the event ev and returns the resulting
+// kafka messages values, along with the metadata used to select their topic, key and headers.
+func (k *kafkaOutput) marshalEvent(ev *formatters.EventMsg, workerLogPrefix, clientID string) ([][]byte, outputs.Meta) {
+	meta := outputs.Meta{"subscription-name": ev.Name}
+	if source, ok := ev.Tags["source"]; ok {
+		meta["source"] = source
+	}
+	evs := []*formatters.EventMsg{ev}
+	for _, p := range k.evps {
+		evs = p.Apply(evs...)
+	}
+	if len(evs) == 0 {
+		return nil, meta
+	}
+	marshal := func(evs ...*formatters.EventMsg) ([]byte, error) {
+		if formatters.IsLineFormat(k.cfg.Format) {
+			return formatters.EventsToLines(k.cfg.Format, evs...)
+		}
+		return json.Marshal(evs)
+	}
+	groups := [][]*formatters.EventMsg{evs}
+	if k.cfg.SplitEvents {
+		groups = make([][]*formatters.EventMsg, 0, len(evs))
+		for _, ev := range evs {
+			groups = append(groups, []*formatters.EventMsg{ev})
+		}
+	}
+	bb := make([][]byte, 0, len(groups))
+	for _, g := range groups {
+		b, err := marshal(g...)
+		if err != nil {
+			if k.cfg.Debug {
+				k.logger.Printf("%s failed marshaling event msg: %v", workerLogPrefix, err)
+			}
+			if k.cfg.EnableMetrics {
+				kafkaNumberOfFailSendMsgs.WithLabelValues(clientID, "marshal_error").Inc()
+			}
+			continue
+		}
+		if len(b) == 0 {
+			continue
+		}
+		bb = append(bb, b)
+	}
+	return bb, meta
+}
+
+// producerMessages returns the kafka messages with values bb after applying
+// the message template and the encryption, their topic, key and headers are selected using meta.
+func (k *kafkaOutput) producerMessages(bb [][]byte, meta outputs.Meta, workerLogPrefix, clientID string) []*sarama.ProducerMessage {
+	msgs := make([]*sarama.ProducerMessage, 0, len(bb))
+	var err error
+	for _, b := range bb {
+		if k.msgTpl != nil {
+			b, err = outputs.ExecTemplate(b, k.msgTpl)
 			if err != nil {
 				if k.cfg.Debug {
-					k.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
-				}
-				if k.cfg.EnableMetrics {
-					kafkaNumberOfFailSendMsgs.WithLabelValues(config.ClientID, "marshal_error").Inc()
+					log.Printf("failed to execute template: %v", err)
 				}
+				kafkaNumberOfFailSendMsgs.WithLabelValues(clientID, "template_error").Inc()
 				continue
 			}
-			if len(bb) == 0 {
-				continue
+		}
+		b, err = k.envelope.Seal(b)
+		if err != nil {
+			if k.cfg.Debug {
+				k.logger.Printf("%s failed to encrypt msg: %v", workerLogPrefix, err)
 			}
-			for _, b := range bb {
-				if k.msgTpl != nil {
-					b, err = outputs.ExecTemplate(b, k.msgTpl)
-					if err != nil {
-						if k.cfg.Debug {
-							log.Printf("failed to execute template: %v", err)
-						}
-						kafkaNumberOfFailSendMsgs.WithLabelValues(config.ClientID, "template_error").Inc()
-						continue
-					}
-				}
+			if k.cfg.EnableMetrics {
+				kafkaNumberOfFailSendMsgs.WithLabelValues(clientID, "encryption_error").Inc()
+			}
+			continue
+		}
+		topic, ok := k.routeTopic(meta, clientID)
+		if !ok {
+			continue
+		}
+		msg := &sarama.ProducerMessage{
+			Topic: topic,
+			Value: sarama.ByteEncoder(b),
+		}
+		switch {
+		case isSequenced(meta):
+			// the messages of an ordered target must land on the same partition
+			msg.Key = sarama.StringEncoder(meta["source"])
+			if !k.orderedDelivery && !k.cfg.Idempotent {
+				k.orderedWarnOnce.Do(func() {
+					k.logger.Printf("received messages from an ordered-delivery target, enable `idempotent` to keep them in order when retried")
+				})
+			}
+		case k.cfg.InsertKey:
+			msg.Key = sarama.ByteEncoder(k.partitionKey(meta))
+		}
+		msg.Headers = k.recordHeaders(meta)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
 
-				topic := k.selectTopic(m.GetMeta())
-				msg := &sarama.ProducerMessage{
-					Topic: topic,
-					Value: sarama.ByteEncoder(b),
-				}
-				if k.cfg.InsertKey {
-					msg.Key = sarama.ByteEncoder(k.partitionKey(m.GetMeta()))
-				}
-				var start time.Time
-				if k.cfg.EnableMetrics {
-					start = time.Now()
-				}
-				_, _, err = producer.SendMessage(msg)
-				if err != nil {
-					if k.cfg.Debug {
-						k.logger.Printf("%s failed to send a kafka msg to topic '%s': %v", workerLogPrefix, topic, err)
-					}
-					if k.cfg.EnableMetrics {
-						kafkaNumberOfFailSendMsgs.WithLabelValues(config.ClientID, "send_error").Inc()
-					}
-					producer.Close()
-					time.Sleep(k.cfg.RecoveryWaitTime)
-					goto CRPROD
-				}
-				if k.cfg.EnableMetrics {
-					kafkaSendDuration.WithLabelValues(config.ClientID).Set(float64(time.Since(start).Nanoseconds()))
-					kafkaNumberOfSentMsgs.WithLabelValues(config.ClientID).Inc()
-					kafkaNumberOfSentBytes.WithLabelValues(config.ClientID).Add(float64(len(b)))
+// Healthy returns true if, for every worker, the last attempt to create
+// a producer or to deliver a message to the Kafka brokers succeeded.
+func (k *kafkaOutput) Healthy() bool {
+	if len(k.workersHealth) == 0 {
+		return false
+	}
+	for i := range k.workersHealth {
+		if !k.workersHealth[i].Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// Drain removes and returns the messages waiting to be sent by the workers.
+// It is used to hand over the pending messages to another output,
+// e.g when the output is a member of a failover output.
+// The events waiting to be sent are not drained.
+func (k *kafkaOutput) Drain() []*outputs.ProtoMsg {
+	msgs := make([]*outputs.ProtoMsg, 0)
+	for {
+		select {
+		case m := <-k.msgChan:
+			k.seq.Done(m.GetMeta())
+			k.delivery.Done(nil)
+			msgs = append(msgs, m)
+		default:
+			return msgs
+		}
+	}
+}
+
+// Flush waits for the messages written so far to be acknowledged by the brokers.
+// It returns an error if any of them failed to be delivered.
+func (k *kafkaOutput) Flush(ctx context.Context) error {
+	return k.delivery.Flush(ctx)
+}
+
+// watchHealth periodically checks if the brokers are reachable
+// while the output is unhealthy.
+// This allows detecting a recovery of the brokers even if no messages
+// are written to the output (e.g when it's part of a failover output).
+func (k *kafkaOutput) watchHealth(ctx context.Context, config *sarama.Config) {
+	ticker := time.NewTicker(k.cfg.RecoveryWaitTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if k.Healthy() {
+				continue
+			}
+			cfg := *config
+			cfg.ClientID = fmt.Sprintf("%s-health", config.ClientID)
+			client, err := sarama.NewClient(strings.Split(k.cfg.Address, ","), &cfg)
+			if err != nil {
+				if k.cfg.Debug {
+					k.logger.Printf("health check failed: %v", err)
 				}
+				continue
+			}
+			client.Close()
+			k.logger.Printf("kafka brokers %q are reachable", k.cfg.Address)
+			for i := range k.workersHealth {
+				k.workersHealth[i].Store(true)
 			}
 		}
 	}
@@ -504,7 +801,16 @@ func (k *kafkaOutput) SetName(name string) {
 
 func (k *kafkaOutput) SetClusterName(name string) {}
 
-func (k *kafkaOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+// SetTargetsConfig records whether any of the targets requires an ordered delivery,
+// in which case the producers are configured to keep the messages of a target in order.
+func (k *kafkaOutput) SetTargetsConfig(tcs map[string]*types.TargetConfig) {
+	for _, tc := range tcs {
+		if tc != nil && tc.OrderedDelivery {
+			k.orderedDelivery = true
+			return
+		}
+	}
+}
 
 func (k *kafkaOutput) createConfig() (*sarama.Config, error) {
 	cfg := sarama.NewConfig()
@@ -515,7 +821,7 @@ func (k *kafkaOutput) createConfig() (*sarama.Config, error) {
 		if err != nil {
 			return nil, err
 		}
-	}	
+	}
 	// SASL_PLAINTEXT or SASL_SSL
 	if k.cfg.SASL != nil {
 		cfg.Net.SASL.Enable = true
@@ -539,13 +845,7 @@ func (k *kafkaOutput) createConfig() (*sarama.Config, error) {
 	if k.cfg.TLS != nil {
 		var err error
 		cfg.Net.TLS.Enable = true
-		cfg.Net.TLS.Config, err = utils.NewTLSConfig(
-			k.cfg.TLS.CaFile,
-			k.cfg.TLS.CertFile,
-			k.cfg.TLS.KeyFile,
-			"",
-			k.cfg.TLS.SkipVerify,
-			false)
+		cfg.Net.TLS.Config, err = k.cfg.TLS.ClientConfig()
 		if err != nil {
 			return nil, err
 		}
@@ -562,6 +862,19 @@ func (k *kafkaOutput) createConfig() (*sarama.Config, error) {
 	case requiredAcksWaitForAll:
 		cfg.Producer.RequiredAcks = sarama.WaitForAll
 	}
+	if k.cfg.Idempotent {
+		cfg.Producer.Idempotent = true
+		cfg.Net.MaxOpenRequests = 1
+		if !cfg.Version.IsAtLeast(sarama.V0_11_0_0) {
+			cfg.Version = sarama.V0_11_0_0
+		}
+	}
+	// a retried request must not be overtaken by the next one
+	// for the messages of an ordered target to stay in order.
+	if k.orderedDelivery && !k.cfg.Idempotent {
+		k.logger.Printf("ordered-delivery target(s) found, setting the max open requests per broker connection to 1")
+		cfg.Net.MaxOpenRequests = 1
+	}
 
 	cfg.Metadata.Full = false
 
@@ -581,12 +894,87 @@ func (k *kafkaOutput) createConfig() (*sarama.Config, error) {
 	return cfg, nil
 }
 
+// isSequenced returns true if the message was received from an ordered-delivery target.
+func isSequenced(m outputs.Meta) bool {
+	_, ok := m[formatters.MetaSequenceNumber]
+	return ok
+}
+
 func (k *kafkaOutput) partitionKey(m outputs.Meta) []byte {
 	b := new(bytes.Buffer)
 	fmt.Fprintf(b, "%s_%s", m["source"], m["subscription-name"])
 	return b.Bytes()
 }
 
+func (k *kafkaOutput) initHeadersTemplates() error {
+	if len(k.cfg.Headers) == 0 {
+		return nil
+	}
+	k.headersTpl = make(map[string]*template.Template, len(k.cfg.Headers))
+	k.headersKeys = make([]string, 0, len(k.cfg.Headers))
+	for hk, hv := range k.cfg.Headers {
+		tpl, err := gtemplate.CreateTemplate(fmt.Sprintf("header-%s", hk), hv)
+		if err != nil {
+			return fmt.Errorf("failed to parse header %q template: %v", hk, err)
+		}
+		k.headersTpl[hk] = tpl.Funcs(outputs.TemplateFuncs)
+		k.headersKeys = append(k.headersKeys, hk)
+	}
+	sort.Strings(k.headersKeys)
+	return nil
+}
+
+// recordHeaders executes the configured headers templates
+// using the message metadata as input.
+// Headers rendering to an empty value are not added to the record.
+func (k *kafkaOutput) recordHeaders(m outputs.Meta) []sarama.RecordHeader {
+	if len(k.headersTpl) == 0 {
+		return nil
+	}
+	hdrs := make([]sarama.RecordHeader, 0, len(k.headersTpl))
+	for _, hk := range k.headersKeys {
+		tpl := k.headersTpl[hk]
+		sb := new(strings.Builder)
+		err := tpl.Execute(sb, m)
+		if err != nil {
+			if k.cfg.Debug {
+				k.logger.Printf("failed to execute header %q template: %v", hk, err)
+			}
+			continue
+		}
+		if sb.Len() == 0 {
+			continue
+		}
+		hdrs = append(hdrs, sarama.RecordHeader{
+			Key:   []byte(hk),
+			Value: []byte(sb.String()),
+		})
+	}
+	return hdrs
+}
+
+// routeTopic returns the topic a message with meta m is sent to.
+// If the selected topic is not a valid topic name, the message is routed to
+// the fallback topic if configured, otherwise it is dropped and false is returned.
+func (k *kafkaOutput) routeTopic(m outputs.Meta, clientID string) (string, bool) {
+	topic := k.selectTopic(m)
+	err := outputs.ValidateKafkaTopic(topic)
+	if err == nil {
+		return topic, true
+	}
+	action := "drop"
+	if k.cfg.FallbackTopic != "" {
+		action = "fallback"
+	}
+	if k.cfg.Debug {
+		k.logger.Printf("%v: %s msg", err, action)
+	}
+	if k.cfg.EnableMetrics {
+		kafkaNumberOfInvalidTopicMsgs.WithLabelValues(clientID, action).Inc()
+	}
+	return k.cfg.FallbackTopic, k.cfg.FallbackTopic != ""
+}
+
 func (k *kafkaOutput) selectTopic(m outputs.Meta) string {
 	if k.cfg.TopicPrefix == "" {
 		return k.cfg.Topic
@@ -0,0 +1,265 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka_output
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+func TestRecordHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		meta    outputs.Meta
+		want    []sarama.RecordHeader
+	}{
+		{
+			name: "no_headers",
+			meta: outputs.Meta{"source": "r1"},
+			want: nil,
+		},
+		{
+			name: "sorted_headers",
+			headers: map[string]string{
+				"z-sub":    "{{ index . \"subscription-name\" }}",
+				"a-source": "{{ .source }}",
+				"m-static": "gnmic",
+			},
+			meta: outputs.Meta{"source": "r1", "subscription-name": "sub1"},
+			want: []sarama.RecordHeader{
+				{Key: []byte("a-source"), Value: []byte("r1")},
+				{Key: []byte("m-static"), Value: []byte("gnmic")},
+				{Key: []byte("z-sub"), Value: []byte("sub1")},
+			},
+		},
+		{
+			name: "empty_headers_skipped",
+			headers: map[string]string{
+				"source": "{{ .source }}",
+				"site":   "{{ with .site }}{{ . }}{{ end }}",
+			},
+			meta: outputs.Meta{"source": "r1"},
+			want: []sarama.RecordHeader{
+				{Key: []byte("source"), Value: []byte("r1")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &kafkaOutput{
+				cfg:    &config{Headers: tt.headers},
+				logger: log.New(io.Discard, "", 0),
+			}
+			err := k.initHeadersTemplates()
+			if err != nil {
+				t.Fatalf("failed to init headers templates: %v", err)
+			}
+			// headers order must be stable across records
+			for i := 0; i < 5; i++ {
+				got := k.recordHeaders(tt.meta)
+				if !cmp.Equal(got, tt.want) {
+					t.Fatalf("unexpected headers: %s", cmp.Diff(tt.want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestRouteTopic(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config
+		meta      outputs.Meta
+		wantTopic string
+		wantOK    bool
+	}{
+		{
+			name:      "static_topic",
+			cfg:       &config{Topic: "telemetry", FallbackTopic: "fallback"},
+			meta:      outputs.Meta{"source": "[2001:db8::1]:57400"},
+			wantTopic: "telemetry",
+			wantOK:    true,
+		},
+		{
+			name:      "valid_prefixed_topic",
+			cfg:       &config{TopicPrefix: "gnmic", FallbackTopic: "fallback"},
+			meta:      outputs.Meta{"source": "router1:57400", "subscription-name": "sub1"},
+			wantTopic: "gnmic_sub1_router1_57400",
+			wantOK:    true,
+		},
+		{
+			name:      "invalid_topic_fallback",
+			cfg:       &config{TopicPrefix: "gnmic", FallbackTopic: "fallback"},
+			meta:      outputs.Meta{"source": "[2001:db8::1]:57400", "subscription-name": "sub1"},
+			wantTopic: "fallback",
+			wantOK:    true,
+		},
+		{
+			name:   "invalid_topic_dropped",
+			cfg:    &config{TopicPrefix: "gnmic", EnableMetrics: true},
+			meta:   outputs.Meta{"source": "router 1", "subscription-name": "sub1"},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &kafkaOutput{
+				cfg:    tt.cfg,
+				logger: log.New(io.Discard, "", 0),
+			}
+			topic, ok := k.routeTopic(tt.meta, "test")
+			if topic != tt.wantTopic || ok != tt.wantOK {
+				t.Errorf("got (%q, %v), expected (%q, %v)", topic, ok, tt.wantTopic, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestProducerMessagesKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config
+		meta    outputs.Meta
+		wantKey sarama.Encoder
+	}{
+		{
+			name: "no_key",
+			cfg:  &config{Topic: "telemetry"},
+			meta: outputs.Meta{"source": "router1:57400", "subscription-name": "sub1"},
+		},
+		{
+			name:    "insert_key",
+			cfg:     &config{Topic: "telemetry", InsertKey: true},
+			meta:    outputs.Meta{"source": "router1:57400", "subscription-name": "sub1"},
+			wantKey: sarama.ByteEncoder("router1:57400_sub1"),
+		},
+		{
+			name:    "ordered_target",
+			cfg:     &config{Topic: "telemetry"},
+			meta:    outputs.Meta{"source": "router1:57400", "subscription-name": "sub1", formatters.MetaSequenceNumber: "1"},
+			wantKey: sarama.StringEncoder("router1:57400"),
+		},
+		{
+			name:    "ordered_target_insert_key",
+			cfg:     &config{Topic: "telemetry", InsertKey: true},
+			meta:    outputs.Meta{"source": "router1:57400", "subscription-name": "sub1", formatters.MetaSequenceNumber: "1"},
+			wantKey: sarama.StringEncoder("router1:57400"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &kafkaOutput{
+				cfg:    tt.cfg,
+				logger: log.New(io.Discard, "", 0),
+			}
+			msgs := k.producerMessages([][]byte{[]byte("{}")}, tt.meta, "test", "test")
+			if len(msgs) != 1 {
+				t.Fatalf("got %d messages, expected 1", len(msgs))
+			}
+			if !cmp.Equal(msgs[0].Key, tt.wantKey) {
+				t.Errorf("got key %v, expected %v", msgs[0].Key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestCreateConfigOrderedDelivery(t *testing.T) {
+	k := &kafkaOutput{
+		cfg:    &config{Name: "test"},
+		logger: log.New(io.Discard, "", 0),
+	}
+	cfg, err := k.createConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Net.MaxOpenRequests == 1 {
+		t.Fatalf("unexpected max open requests without ordered targets")
+	}
+	k.SetTargetsConfig(map[string]*types.TargetConfig{
+		"router1": {Name: "router1"},
+		"router2": {Name: "router2", OrderedDelivery: true},
+	})
+	cfg, err = k.createConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Net.MaxOpenRequests != 1 {
+		t.Errorf("got max open requests %d, expected 1", cfg.Net.MaxOpenRequests)
+	}
+}
+
+func TestMarshalEvent(t *testing.T) {
+	ev := &formatters.EventMsg{
+		Name:      "sub1",
+		Timestamp: 42,
+		Tags:      map[string]string{"source": "router1:57400"},
+		Values:    map[string]interface{}{"counter": 1},
+	}
+	tests := []struct {
+		name string
+		cfg  *config
+		want []string
+	}{
+		{
+			name: "event",
+			cfg:  &config{Format: "event"},
+			want: []string{`[{"name":"sub1","timestamp":42,"tags":{"source":"router1:57400"},"values":{"counter":1}}]`},
+		},
+		{
+			name: "split_events",
+			cfg:  &config{Format: "event", SplitEvents: true},
+			want: []string{`[{"name":"sub1","timestamp":42,"tags":{"source":"router1:57400"},"values":{"counter":1}}]`},
+		},
+		{
+			name: "influx",
+			cfg:  &config{Format: formatters.FormatInflux},
+			want: []string{"sub1,source=router1:57400 counter=1i 42"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &kafkaOutput{
+				cfg:    tt.cfg,
+				logger: log.New(io.Discard, "", 0),
+			}
+			bb, meta := k.marshalEvent(ev, "test", "test")
+			got := make([]string, 0, len(bb))
+			for _, b := range bb {
+				got = append(got, string(b))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected values (-want +got):\n%s", diff)
+			}
+			wantMeta := outputs.Meta{"source": "router1:57400", "subscription-name": "sub1"}
+			if diff := cmp.Diff(wantMeta, meta); diff != "" {
+				t.Errorf("unexpected meta (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	k := &kafkaOutput{cfg: &config{}}
+	if err := k.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(k.cfg, "name") {
+		t.Error(m)
+	}
+}
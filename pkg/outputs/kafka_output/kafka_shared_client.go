@@ -0,0 +1,109 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka_output
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const sharedClientID = "gnmic-shared"
+
+// sharedClients holds the Kafka clients shared by the kafka outputs
+// configured with `shared-connection: true`.
+// The producers created from a client use its config, so the outputs
+// share a client only if they have the same brokers, security and producer settings.
+var sharedClients = outputs.NewSharedConns(func(c sarama.Client) { c.Close() })
+
+func (k *kafkaOutput) sharedClientKey() string {
+	return outputs.ConnKey(
+		k.cfg.Address,
+		k.cfg.KafkaVersion,
+		k.cfg.SASL,
+		k.cfg.TLS,
+		k.cfg.MaxRetry,
+		k.cfg.Timeout,
+		k.cfg.FlushFrequency,
+		k.cfg.RequiredAcks,
+		k.cfg.Idempotent,
+		k.cfg.CompressionCodec,
+	)
+}
+
+// acquireClient returns the client shared by the outputs with the same settings
+// and the function releasing it.
+func (k *kafkaOutput) acquireClient(config *sarama.Config) (sarama.Client, func(), error) {
+	return sharedClients.Acquire(k.sharedClientKey(), func() (sarama.Client, error) {
+		cfg := *config
+		cfg.ClientID = sharedClientID
+		k.logger.Printf("opening shared client to %s", k.cfg.Address)
+		return sarama.NewClient(strings.Split(k.cfg.Address, ","), &cfg)
+	})
+}
+
+// newAsyncProducer returns a new async producer and the function closing it.
+// With shared-connection, the producer uses the shared client.
+func (k *kafkaOutput) newAsyncProducer(config *sarama.Config) (sarama.AsyncProducer, func(), error) {
+	if !k.cfg.SharedConnection {
+		p, err := sarama.NewAsyncProducer(strings.Split(k.cfg.Address, ","), config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, closeOnce(p.Close, nil), nil
+	}
+	client, release, err := k.acquireClient(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return p, closeOnce(p.Close, release), nil
+}
+
+// newSyncProducer returns a new sync producer and the function closing it.
+// With shared-connection, the producer uses the shared client.
+func (k *kafkaOutput) newSyncProducer(config *sarama.Config) (sarama.SyncProducer, func(), error) {
+	if !k.cfg.SharedConnection {
+		p, err := sarama.NewSyncProducer(strings.Split(k.cfg.Address, ","), config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, closeOnce(p.Close, nil), nil
+	}
+	client, release, err := k.acquireClient(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return p, closeOnce(p.Close, release), nil
+}
+
+// closeOnce returns a function closing a producer then releasing its client, only once.
+func closeOnce(close func() error, release func()) func() {
+	once := new(sync.Once)
+	return func() {
+		once.Do(func() {
+			close()
+			if release != nil {
+				release()
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/openconfig/gnmic/pkg/formatters"
 	"github.com/openconfig/gnmic/pkg/gtemplate"
 	"github.com/openconfig/gnmic/pkg/outputs"
+	"github.com/openconfig/gnmic/pkg/outputs/nats_outputs"
 )
 
 const (
@@ -51,6 +52,7 @@ func init() {
 		return &jetstreamOutput{
 			Cfg:     &config{},
 			msgChan: make(chan *outputs.ProtoMsg),
+			seq:     outputs.NewSequencer(),
 			wg:      new(sync.WaitGroup),
 			logger:  log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
 		}
@@ -68,33 +70,37 @@ const (
 )
 
 type config struct {
-	Name               string              `mapstructure:"name,omitempty" json:"name,omitempty"`
-	Address            string              `mapstructure:"address,omitempty" json:"address,omitempty"`
-	Stream             string              `mapstructure:"stream,omitempty" json:"stream,omitempty"`
-	Subject            string              `mapstructure:"subject,omitempty" json:"subject,omitempty"`
-	SubjectFormat      subjectFormat       `mapstructure:"subject-format,omitempty" json:"subject-format,omitempty"`
-	CreateStream       *createStreamConfig `mapstructure:"create-stream,omitempty" json:"create-stream,omitempty"`
-	Username           string              `mapstructure:"username,omitempty" json:"username,omitempty"`
-	Password           string              `mapstructure:"password,omitempty" json:"password,omitempty"`
-	ConnectTimeWait    time.Duration       `mapstructure:"connect-time-wait,omitempty" json:"connect-time-wait,omitempty"`
-	TLS                *types.TLSConfig    `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	Format             string              `mapstructure:"format,omitempty" json:"format,omitempty"`
-	SplitEvents        bool                `mapstructure:"split-events,omitempty"`
-	AddTarget          string              `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
-	TargetTemplate     string              `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
-	MsgTemplate        string              `mapstructure:"msg-template,omitempty" json:"msg-template,omitempty"`
-	OverrideTimestamps bool                `mapstructure:"override-timestamps,omitempty" json:"override-timestamps,omitempty"`
-	NumWorkers         int                 `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty"`
-	WriteTimeout       time.Duration       `mapstructure:"write-timeout,omitempty" json:"write-timeout,omitempty"`
-	Debug              bool                `mapstructure:"debug,omitempty" json:"debug,omitempty"`
-	EnableMetrics      bool                `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
-	EventProcessors    []string            `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	Name               string                    `mapstructure:"name,omitempty" json:"name,omitempty"`
+	Address            string                    `mapstructure:"address,omitempty" json:"address,omitempty" default:"localhost:4222"`
+	Stream             string                    `mapstructure:"stream,omitempty" json:"stream,omitempty"`
+	Subject            string                    `mapstructure:"subject,omitempty" json:"subject,omitempty" default:"telemetry"`
+	FallbackSubject    string                    `mapstructure:"fallback-subject,omitempty" json:"fallback-subject,omitempty"`
+	SubjectFormat      subjectFormat             `mapstructure:"subject-format,omitempty" json:"subject-format,omitempty" default:"static"`
+	CreateStream       *createStreamConfig       `mapstructure:"create-stream,omitempty" json:"create-stream,omitempty"`
+	Username           string                    `mapstructure:"username,omitempty" json:"username,omitempty"`
+	Password           string                    `mapstructure:"password,omitempty" json:"password,omitempty"`
+	ConnectTimeWait    time.Duration             `mapstructure:"connect-time-wait,omitempty" json:"connect-time-wait,omitempty" default:"2s"`
+	TLS                *types.TLSConfig          `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	SharedConnection   bool                      `mapstructure:"shared-connection,omitempty" json:"shared-connection,omitempty"`
+	Format             string                    `mapstructure:"format,omitempty" json:"format,omitempty" default:"event"`
+	SplitEvents        bool                      `mapstructure:"split-events,omitempty"`
+	AddTarget          string                    `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
+	TargetTemplate     string                    `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
+	MsgTemplate        string                    `mapstructure:"msg-template,omitempty" json:"msg-template,omitempty"`
+	OverrideTimestamps bool                      `mapstructure:"override-timestamps,omitempty" json:"override-timestamps,omitempty"`
+	NumWorkers         int                       `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty" default:"1"`
+	WriteTimeout       time.Duration             `mapstructure:"write-timeout,omitempty" json:"write-timeout,omitempty" default:"5s"`
+	Debug              bool                      `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+	EnableMetrics      bool                      `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+	EventProcessors    []string                  `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat  `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
+	Encryption         *outputs.EncryptionConfig `mapstructure:"encryption,omitempty" json:"encryption,omitempty"`
 }
 
 type createStreamConfig struct {
-	Description string        `mapstructure:"description,omitempty" json:"description,omitempty"`
+	Description string        `mapstructure:"description,omitempty" json:"description,omitempty" default:"created by gNMIc"`
 	Subjects    []string      `mapstructure:"subjects,omitempty" json:"subjects,omitempty"`
-	Storage     string        `mapstructure:"storage,omitempty" json:"storage,omitempty"`
+	Storage     string        `mapstructure:"storage,omitempty" json:"storage,omitempty" default:"memory"`
 	MaxMsgs     int64         `mapstructure:"max-msgs,omitempty" json:"max-msgs,omitempty"`
 	MaxBytes    int64         `mapstructure:"max-bytes,omitempty" json:"max-bytes,omitempty"`
 	MaxAge      time.Duration `mapstructure:"max-age,omitempty" json:"max-age,omitempty"`
@@ -103,14 +109,17 @@ type createStreamConfig struct {
 
 // jetstreamOutput //
 type jetstreamOutput struct {
-	Cfg      *config
-	ctx      context.Context
-	cancelFn context.CancelFunc
-	msgChan  chan *outputs.ProtoMsg
-	wg       *sync.WaitGroup
-	logger   *log.Logger
-	mo       *formatters.MarshalOptions
-	evps     []formatters.EventProcessor
+	Cfg          *config
+	ctx          context.Context
+	cancelFn     context.CancelFunc
+	msgChan      chan *outputs.ProtoMsg
+	seq          *outputs.Sequencer
+	wg           *sync.WaitGroup
+	logger       *log.Logger
+	mo           *formatters.MarshalOptions
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	envelope     *outputs.Envelope
 
 	targetTpl *template.Template
 	msgTpl    *template.Template
@@ -136,6 +145,10 @@ func (n *jetstreamOutput) Init(ctx context.Context, name string, cfg map[string]
 		return err
 	}
 
+	n.envelope, err = outputs.NewEnvelope(n.Cfg.Encryption)
+	if err != nil {
+		return err
+	}
 	n.msgChan = make(chan *outputs.ProtoMsg)
 	initMetrics()
 	n.mo = &formatters.MarshalOptions{
@@ -198,6 +211,11 @@ func (n *jetstreamOutput) setDefaults() error {
 	if n.Cfg.Subject == "" {
 		n.Cfg.Subject = defaultSubjectName
 	}
+	if n.Cfg.FallbackSubject != "" {
+		if err := outputs.ValidateNATSSubject(n.Cfg.Stream + "." + n.Cfg.FallbackSubject); err != nil {
+			return fmt.Errorf("invalid fallback-subject: %w", err)
+		}
+	}
 	if n.Cfg.Address == "" {
 		n.Cfg.Address = defaultAddress
 	}
@@ -235,11 +253,14 @@ func (n *jetstreamOutput) Write(ctx context.Context, rsp proto.Message, meta out
 	wctx, cancel := context.WithTimeout(ctx, n.Cfg.WriteTimeout)
 	defer cancel()
 
+	n.seq.Add(meta)
 	select {
 	case <-ctx.Done():
+		n.seq.Done(meta)
 		return
 	case n.msgChan <- outputs.NewProtoMsg(rsp, meta):
 	case <-wctx.Done():
+		n.seq.Done(meta)
 		if n.Cfg.Debug {
 			n.logger.Printf("writing expired after %s, JetStream output might not be initialized", n.Cfg.WriteTimeout)
 		}
@@ -298,7 +319,11 @@ func (n *jetstreamOutput) SetEventProcessors(ps map[string]map[string]interface{
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := n.Cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	n.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	n.evps, err = formatters.MakeEventProcessors(
 		logger,
 		n.Cfg.EventProcessors,
@@ -309,6 +334,7 @@ func (n *jetstreamOutput) SetEventProcessors(ps map[string]map[string]interface{
 	if err != nil {
 		return err
 	}
+	n.evps = append(n.evps, nfps...)
 	return nil
 }
 
@@ -329,18 +355,18 @@ func (n *jetstreamOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
 func (n *jetstreamOutput) worker(ctx context.Context, i int, cfg *config) {
 	defer n.wg.Done()
 	var natsConn *nats.Conn
+	var release func()
 	var err error
-	var subject string
 	workerLogPrefix := fmt.Sprintf("worker-%d", i)
 	n.logger.Printf("%s starting", workerLogPrefix)
 CRCONN:
-	natsConn, err = n.createNATSConn(cfg)
+	natsConn, release, err = n.connect(cfg)
 	if err != nil {
 		n.logger.Printf("%s failed to create connection: %v", workerLogPrefix, err)
 		time.Sleep(n.Cfg.ConnectTimeWait)
 		goto CRCONN
 	}
-	defer natsConn.Close()
+	defer release()
 	js, err := natsConn.JetStream()
 	if err != nil {
 		if n.Cfg.Debug {
@@ -349,7 +375,7 @@ CRCONN:
 		if n.Cfg.EnableMetrics {
 			jetStreamNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "jetstream_context_error").Inc()
 		}
-		natsConn.Close()
+		release()
 		time.Sleep(cfg.ConnectTimeWait)
 		goto CRCONN
 	}
@@ -364,7 +390,7 @@ CRCONN:
 			if n.Cfg.EnableMetrics {
 				jetStreamNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "create_stream_error").Inc()
 			}
-			natsConn.Close()
+			release()
 			time.Sleep(cfg.ConnectTimeWait)
 			goto CRCONN
 		}
@@ -394,7 +420,7 @@ CRCONN:
 				}
 			}
 			for _, r := range rs {
-				bb, err := outputs.Marshal(r, m.GetMeta(), n.mo, n.Cfg.SplitEvents, n.evps...)
+				bb, err := outputs.Marshal(r, m.GetMeta(), n.mo, n.Cfg.SplitEvents, n.evpOverrides.Select(m.GetMeta(), n.evps)...)
 				if err != nil {
 					if n.Cfg.Debug {
 						n.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
@@ -418,17 +444,23 @@ CRCONN:
 							continue
 						}
 					}
-
-					subject, err = n.subjectName(r, m.GetMeta())
+					b, err = n.envelope.Seal(b)
 					if err != nil {
 						if n.Cfg.Debug {
-							n.logger.Printf("%s failed to get subject name: %v", workerLogPrefix, err)
+							n.logger.Printf("%s failed to encrypt msg: %v", workerLogPrefix, err)
 						}
 						if n.Cfg.EnableMetrics {
-							jetStreamNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "subject_name_error").Inc()
+							jetStreamNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "encryption_error").Inc()
 						}
 						continue
 					}
+					subject, ok := n.routeSubject(cfg, r, m.GetMeta())
+					if !ok {
+						continue
+					}
+					// the message is processed, wait for its turn
+					// if its target has ordered delivery enabled.
+					n.seq.Wait(ctx, m.GetMeta())
 					var start time.Time
 					if n.Cfg.EnableMetrics {
 						start = time.Now()
@@ -441,7 +473,8 @@ CRCONN:
 						if n.Cfg.EnableMetrics {
 							jetStreamNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "publish_error").Inc()
 						}
-						natsConn.Close()
+						n.seq.Done(m.GetMeta())
+						release()
 						time.Sleep(cfg.ConnectTimeWait)
 						goto CRCONN
 					}
@@ -452,10 +485,38 @@ CRCONN:
 					}
 				}
 			}
+			n.seq.Done(m.GetMeta())
 		}
 	}
 }
 
+// routeSubject returns the subject a message with meta is published to.
+// If the subject cannot be built or is not valid, the message is routed to
+// the fallback subject if configured, otherwise it is dropped and false is returned.
+func (n *jetstreamOutput) routeSubject(c *config, m proto.Message, meta outputs.Meta) (string, bool) {
+	subject, err := n.subjectName(m, meta)
+	if err == nil {
+		err = outputs.ValidateNATSSubject(subject)
+	}
+	if err == nil {
+		return subject, true
+	}
+	action := "drop"
+	if n.Cfg.FallbackSubject != "" {
+		action = "fallback"
+	}
+	if n.Cfg.Debug {
+		n.logger.Printf("failed to get subject name: %v: %s msg", err, action)
+	}
+	if n.Cfg.EnableMetrics {
+		jetStreamNumberOfInvalidSubjectMsgs.WithLabelValues(c.Name, action).Inc()
+	}
+	if n.Cfg.FallbackSubject == "" {
+		return "", false
+	}
+	return n.Cfg.Stream + "." + n.Cfg.FallbackSubject, true
+}
+
 // Dial //
 func (n *jetstreamOutput) Dial(network, address string) (net.Conn, error) {
 	ctx, cancel := context.WithCancel(n.ctx)
@@ -481,6 +542,26 @@ func (n *jetstreamOutput) Dial(network, address string) (net.Conn, error) {
 	}
 }
 
+// connect returns the worker NATS connection and the function releasing it.
+// With shared-connection, the connection is shared with the other
+// NATS based outputs connecting to the same server.
+func (n *jetstreamOutput) connect(c *config) (*nats.Conn, func(), error) {
+	if n.Cfg.SharedConnection {
+		return nats_outputs.AcquireConn(&nats_outputs.SharedConnConfig{
+			Address:       c.Address,
+			Username:      c.Username,
+			Password:      c.Password,
+			TLS:           c.TLS,
+			ReconnectWait: c.ConnectTimeWait,
+		}, n.logger)
+	}
+	nc, err := n.createNATSConn(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nc, nc.Close, nil
+}
+
 func (n *jetstreamOutput) createNATSConn(c *config) (*nats.Conn, error) {
 	opts := []nats.Option{
 		nats.Name(c.Name),
@@ -498,13 +579,7 @@ func (n *jetstreamOutput) createNATSConn(c *config) (*nats.Conn, error) {
 		}),
 	}
 	if n.Cfg.TLS != nil {
-		tlsConfig, err := utils.NewTLSConfig(
-			n.Cfg.TLS.CaFile,
-			n.Cfg.TLS.CertFile,
-			n.Cfg.TLS.KeyFile,
-			"",
-			n.Cfg.TLS.SkipVerify,
-			false)
+		tlsConfig, err := n.Cfg.TLS.ClientConfig()
 		if err != nil {
 			return nil, err
 		}
@@ -31,6 +31,13 @@ var jetStreamNumberOfFailSendMsgs = prometheus.NewCounterVec(prometheus.CounterO
 	Help:      "Number of failed msgs sent by gnmic jetstream output",
 }, []string{"publisher_id", "reason"})
 
+var jetStreamNumberOfInvalidSubjectMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "jetstream_output",
+	Name:      "number_of_jetstream_msgs_invalid_subject_total",
+	Help:      "Number of msgs with an invalid subject name, dropped or sent to the fallback subject by gnmic jetstream output",
+}, []string{"publisher_id", "action"})
+
 var jetStreamSendDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	Namespace: "gnmic",
 	Subsystem: "jetstream_output",
@@ -42,6 +49,7 @@ func initMetrics() {
 	jetStreamNumberOfSentMsgs.WithLabelValues("", "").Add(0)
 	jetStreamNumberOfSentBytes.WithLabelValues("", "").Add(0)
 	jetStreamNumberOfFailSendMsgs.WithLabelValues("", "").Add(0)
+	jetStreamNumberOfInvalidSubjectMsgs.WithLabelValues("", "").Add(0)
 	jetStreamSendDuration.WithLabelValues("").Set(0)
 }
 
@@ -57,6 +65,9 @@ func registerMetrics(reg *prometheus.Registry) error {
 	if err = reg.Register(jetStreamNumberOfFailSendMsgs); err != nil {
 		return err
 	}
+	if err = reg.Register(jetStreamNumberOfInvalidSubjectMsgs); err != nil {
+		return err
+	}
 	if err = reg.Register(jetStreamSendDuration); err != nil {
 		return err
 	}
@@ -31,6 +31,13 @@ var NatsNumberOfFailSendMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Help:      "Number of failed msgs sent by gnmic nats output",
 }, []string{"publisher_id", "reason"})
 
+var NatsNumberOfInvalidSubjectMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "nats_output",
+	Name:      "number_of_nats_msgs_invalid_subject_total",
+	Help:      "Number of msgs with an invalid subject, routed to the fallback subject or dropped",
+}, []string{"publisher_id", "action"})
+
 var NatsSendDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	Namespace: "gnmic",
 	Subsystem: "nats_output",
@@ -42,6 +49,7 @@ func initMetrics() {
 	NatsNumberOfSentMsgs.WithLabelValues("", "").Add(0)
 	NatsNumberOfSentBytes.WithLabelValues("", "").Add(0)
 	NatsNumberOfFailSendMsgs.WithLabelValues("", "").Add(0)
+	NatsNumberOfInvalidSubjectMsgs.WithLabelValues("", "").Add(0)
 	NatsSendDuration.WithLabelValues("").Set(0)
 }
 
@@ -57,6 +65,9 @@ func registerMetrics(reg *prometheus.Registry) error {
 	if err = reg.Register(NatsNumberOfFailSendMsgs); err != nil {
 		return err
 	}
+	if err = reg.Register(NatsNumberOfInvalidSubjectMsgs); err != nil {
+		return err
+	}
 	if err = reg.Register(NatsSendDuration); err != nil {
 		return err
 	}
@@ -30,6 +30,7 @@ import (
 	"github.com/openconfig/gnmic/pkg/formatters"
 	"github.com/openconfig/gnmic/pkg/gtemplate"
 	"github.com/openconfig/gnmic/pkg/outputs"
+	"github.com/openconfig/gnmic/pkg/outputs/nats_outputs"
 )
 
 const (
@@ -47,6 +48,7 @@ func init() {
 	outputs.Register("nats", func() outputs.Output {
 		return &NatsOutput{
 			Cfg:    &Config{},
+			seq:    outputs.NewSequencer(),
 			wg:     new(sync.WaitGroup),
 			logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
 		}
@@ -55,14 +57,17 @@ func init() {
 
 // NatsOutput //
 type NatsOutput struct {
-	Cfg      *Config
-	ctx      context.Context
-	cancelFn context.CancelFunc
-	msgChan  chan *outputs.ProtoMsg
-	wg       *sync.WaitGroup
-	logger   *log.Logger
-	mo       *formatters.MarshalOptions
-	evps     []formatters.EventProcessor
+	Cfg          *Config
+	ctx          context.Context
+	cancelFn     context.CancelFunc
+	msgChan      chan *outputs.ProtoMsg
+	seq          *outputs.Sequencer
+	wg           *sync.WaitGroup
+	logger       *log.Logger
+	mo           *formatters.MarshalOptions
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	envelope     *outputs.Envelope
 
 	targetTpl *template.Template
 	msgTpl    *template.Template
@@ -70,25 +75,29 @@ type NatsOutput struct {
 
 // Config //
 type Config struct {
-	Name               string           `mapstructure:"name,omitempty"`
-	Address            string           `mapstructure:"address,omitempty"`
-	SubjectPrefix      string           `mapstructure:"subject-prefix,omitempty"`
-	Subject            string           `mapstructure:"subject,omitempty"`
-	Username           string           `mapstructure:"username,omitempty"`
-	Password           string           `mapstructure:"password,omitempty"`
-	ConnectTimeWait    time.Duration    `mapstructure:"connect-time-wait,omitempty"`
-	TLS                *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	Format             string           `mapstructure:"format,omitempty"`
-	SplitEvents        bool             `mapstructure:"split-events,omitempty"`
-	AddTarget          string           `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string           `mapstructure:"target-template,omitempty"`
-	MsgTemplate        string           `mapstructure:"msg-template,omitempty"`
-	OverrideTimestamps bool             `mapstructure:"override-timestamps,omitempty"`
-	NumWorkers         int              `mapstructure:"num-workers,omitempty"`
-	WriteTimeout       time.Duration    `mapstructure:"write-timeout,omitempty"`
-	Debug              bool             `mapstructure:"debug,omitempty"`
-	EnableMetrics      bool             `mapstructure:"enable-metrics,omitempty"`
-	EventProcessors    []string         `mapstructure:"event-processors,omitempty"`
+	Name               string                    `mapstructure:"name,omitempty"`
+	Address            string                    `mapstructure:"address,omitempty" default:"localhost:4222"`
+	SubjectPrefix      string                    `mapstructure:"subject-prefix,omitempty"`
+	Subject            string                    `mapstructure:"subject,omitempty" default:"telemetry"`
+	FallbackSubject    string                    `mapstructure:"fallback-subject,omitempty"`
+	Username           string                    `mapstructure:"username,omitempty"`
+	Password           string                    `mapstructure:"password,omitempty"`
+	ConnectTimeWait    time.Duration             `mapstructure:"connect-time-wait,omitempty" default:"2s"`
+	TLS                *types.TLSConfig          `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	SharedConnection   bool                      `mapstructure:"shared-connection,omitempty" json:"shared-connection,omitempty"`
+	Format             string                    `mapstructure:"format,omitempty" default:"event"`
+	SplitEvents        bool                      `mapstructure:"split-events,omitempty"`
+	AddTarget          string                    `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                    `mapstructure:"target-template,omitempty"`
+	MsgTemplate        string                    `mapstructure:"msg-template,omitempty"`
+	OverrideTimestamps bool                      `mapstructure:"override-timestamps,omitempty"`
+	NumWorkers         int                       `mapstructure:"num-workers,omitempty" default:"1"`
+	WriteTimeout       time.Duration             `mapstructure:"write-timeout,omitempty" default:"5s"`
+	Debug              bool                      `mapstructure:"debug,omitempty"`
+	EnableMetrics      bool                      `mapstructure:"enable-metrics,omitempty"`
+	EventProcessors    []string                  `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat  `mapstructure:"number-format,omitempty"`
+	Encryption         *outputs.EncryptionConfig `mapstructure:"encryption,omitempty"`
 }
 
 func (n *NatsOutput) String() string {
@@ -110,7 +119,11 @@ func (n *NatsOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := n.Cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	n.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	n.evps, err = formatters.MakeEventProcessors(
 		logger,
 		n.Cfg.EventProcessors,
@@ -121,6 +134,7 @@ func (n *NatsOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	n.evps = append(n.evps, nfps...)
 	return nil
 }
 
@@ -145,6 +159,10 @@ func (n *NatsOutput) Init(ctx context.Context, name string, cfg map[string]inter
 		return err
 	}
 
+	n.envelope, err = outputs.NewEnvelope(n.Cfg.Encryption)
+	if err != nil {
+		return err
+	}
 	n.msgChan = make(chan *outputs.ProtoMsg)
 	initMetrics()
 	n.mo = &formatters.MarshalOptions{
@@ -200,6 +218,11 @@ func (n *NatsOutput) setDefaults() error {
 	if n.Cfg.Subject == "" && n.Cfg.SubjectPrefix == "" {
 		n.Cfg.Subject = defaultSubjectName
 	}
+	if n.Cfg.FallbackSubject != "" {
+		if err := outputs.ValidateNATSSubject(n.Cfg.FallbackSubject); err != nil {
+			return fmt.Errorf("invalid fallback-subject: %w", err)
+		}
+	}
 	if n.Cfg.Name == "" {
 		n.Cfg.Name = "gnmic-" + uuid.New().String()
 	}
@@ -221,11 +244,14 @@ func (n *NatsOutput) Write(ctx context.Context, rsp proto.Message, meta outputs.
 	wctx, cancel := context.WithTimeout(ctx, n.Cfg.WriteTimeout)
 	defer cancel()
 
+	n.seq.Add(meta)
 	select {
 	case <-ctx.Done():
+		n.seq.Done(meta)
 		return
 	case n.msgChan <- outputs.NewProtoMsg(rsp, meta):
 	case <-wctx.Done():
+		n.seq.Done(meta)
 		if n.Cfg.Debug {
 			n.logger.Printf("writing expired after %s, NATS output might not be initialized", n.Cfg.WriteTimeout)
 		}
@@ -260,6 +286,26 @@ func (n *NatsOutput) RegisterMetrics(reg *prometheus.Registry) {
 	}
 }
 
+// connect returns the worker NATS connection and the function releasing it.
+// With shared-connection, the connection is shared with the other
+// NATS based outputs connecting to the same server.
+func (n *NatsOutput) connect(c *Config) (*nats.Conn, func(), error) {
+	if n.Cfg.SharedConnection {
+		return nats_outputs.AcquireConn(&nats_outputs.SharedConnConfig{
+			Address:       c.Address,
+			Username:      c.Username,
+			Password:      c.Password,
+			TLS:           c.TLS,
+			ReconnectWait: c.ConnectTimeWait,
+		}, n.logger)
+	}
+	nc, err := n.createNATSConn(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nc, nc.Close, nil
+}
+
 func (n *NatsOutput) createNATSConn(c *Config) (*nats.Conn, error) {
 	opts := []nats.Option{
 		nats.Name(c.Name),
@@ -280,13 +326,7 @@ func (n *NatsOutput) createNATSConn(c *Config) (*nats.Conn, error) {
 		opts = append(opts, nats.UserInfo(c.Username, c.Password))
 	}
 	if n.Cfg.TLS != nil {
-		tlsConfig, err := utils.NewTLSConfig(
-			n.Cfg.TLS.CaFile,
-			n.Cfg.TLS.CertFile,
-			n.Cfg.TLS.KeyFile,
-			"",
-			n.Cfg.TLS.SkipVerify,
-			false)
+		tlsConfig, err := n.Cfg.TLS.ClientConfig()
 		if err != nil {
 			return nil, err
 		}
@@ -329,17 +369,18 @@ func (n *NatsOutput) Dial(network, address string) (net.Conn, error) {
 func (n *NatsOutput) worker(ctx context.Context, i int, cfg *Config) {
 	defer n.wg.Done()
 	var natsConn *nats.Conn
+	var release func()
 	var err error
 	workerLogPrefix := fmt.Sprintf("worker-%d", i)
 	n.logger.Printf("%s starting", workerLogPrefix)
 CRCONN:
-	natsConn, err = n.createNATSConn(cfg)
+	natsConn, release, err = n.connect(cfg)
 	if err != nil {
 		n.logger.Printf("%s failed to create connection: %v", workerLogPrefix, err)
 		time.Sleep(n.Cfg.ConnectTimeWait)
 		goto CRCONN
 	}
-	defer natsConn.Close()
+	defer release()
 	n.logger.Printf("%s initialized nats producer: %+v", workerLogPrefix, cfg)
 	for {
 		select {
@@ -354,7 +395,7 @@ CRCONN:
 			if err != nil {
 				n.logger.Printf("failed to add target to the response: %v", err)
 			}
-			bb, err := outputs.Marshal(pmsg, m.GetMeta(), n.mo, n.Cfg.SplitEvents, n.evps...)
+			bb, err := outputs.Marshal(pmsg, m.GetMeta(), n.mo, n.Cfg.SplitEvents, n.evpOverrides.Select(m.GetMeta(), n.evps)...)
 			if err != nil {
 				if n.Cfg.Debug {
 					n.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
@@ -362,11 +403,14 @@ CRCONN:
 				if n.Cfg.EnableMetrics {
 					NatsNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "marshal_error").Inc()
 				}
+				n.seq.Done(m.GetMeta())
 				continue
 			}
 			if len(bb) == 0 {
+				n.seq.Done(m.GetMeta())
 				return
 			}
+			n.seq.Wait(ctx, m.GetMeta())
 			for _, b := range bb {
 				if n.msgTpl != nil {
 					b, err = outputs.ExecTemplate(b, n.msgTpl)
@@ -378,8 +422,20 @@ CRCONN:
 						continue
 					}
 				}
-
-				subject := n.subjectName(cfg, m.GetMeta())
+				b, err = n.envelope.Seal(b)
+				if err != nil {
+					if n.Cfg.Debug {
+						n.logger.Printf("%s failed to encrypt msg: %v", workerLogPrefix, err)
+					}
+					if n.Cfg.EnableMetrics {
+						NatsNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "encryption_error").Inc()
+					}
+					continue
+				}
+				subject, ok := n.routeSubject(cfg, m.GetMeta())
+				if !ok {
+					continue
+				}
 				var start time.Time
 				if n.Cfg.EnableMetrics {
 					start = time.Now()
@@ -392,7 +448,8 @@ CRCONN:
 					if n.Cfg.EnableMetrics {
 						NatsNumberOfFailSendMsgs.WithLabelValues(cfg.Name, "publish_error").Inc()
 					}
-					natsConn.Close()
+					n.seq.Done(m.GetMeta())
+					release()
 					time.Sleep(cfg.ConnectTimeWait)
 					goto CRCONN
 				}
@@ -402,10 +459,33 @@ CRCONN:
 					NatsNumberOfSentBytes.WithLabelValues(cfg.Name, subject).Add(float64(len(b)))
 				}
 			}
+			n.seq.Done(m.GetMeta())
 		}
 	}
 }
 
+// routeSubject returns the subject a message with meta is published to.
+// If the subject is not valid, the message is routed to the fallback subject
+// if configured, otherwise it is dropped and false is returned.
+func (n *NatsOutput) routeSubject(c *Config, meta outputs.Meta) (string, bool) {
+	subject := n.subjectName(c, meta)
+	err := outputs.ValidateNATSSubject(subject)
+	if err == nil {
+		return subject, true
+	}
+	action := "drop"
+	if n.Cfg.FallbackSubject != "" {
+		action = "fallback"
+	}
+	if n.Cfg.Debug {
+		n.logger.Printf("%v: %s msg", err, action)
+	}
+	if n.Cfg.EnableMetrics {
+		NatsNumberOfInvalidSubjectMsgs.WithLabelValues(c.Name, action).Inc()
+	}
+	return n.Cfg.FallbackSubject, n.Cfg.FallbackSubject != ""
+}
+
 func (n *NatsOutput) subjectName(c *Config, meta outputs.Meta) string {
 	if c.SubjectPrefix != "" {
 		ssb := strings.Builder{}
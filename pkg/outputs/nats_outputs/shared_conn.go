@@ -0,0 +1,82 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats_outputs holds the code common to the NATS based outputs.
+package nats_outputs
+
+import (
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const sharedConnLoggingPrefix = "[nats_shared_conn] "
+
+// sharedConns holds the NATS connections shared by the nats and jetstream outputs
+// configured with `shared-connection: true`.
+var sharedConns = outputs.NewSharedConns(func(nc *nats.Conn) {
+	nc.FlushTimeout(time.Second)
+	nc.Close()
+})
+
+// SharedConnConfig defines the settings identifying a shared NATS connection.
+type SharedConnConfig struct {
+	Address       string
+	Username      string
+	Password      string
+	TLS           *types.TLSConfig
+	ReconnectWait time.Duration
+}
+
+func (c *SharedConnConfig) key() string {
+	return outputs.ConnKey(c.Address, c.Username, c.Password, c.TLS, c.ReconnectWait)
+}
+
+// AcquireConn returns the NATS connection shared by the outputs with the same config c
+// and the function releasing it.
+// The connection is opened on first use, it reconnects until it is closed by its last user.
+// Its events are logged to the writer of logger.
+func AcquireConn(c *SharedConnConfig, logger *log.Logger) (*nats.Conn, func(), error) {
+	return sharedConns.Acquire(c.key(), func() (*nats.Conn, error) {
+		l := log.New(logger.Writer(), sharedConnLoggingPrefix, logger.Flags())
+		opts := []nats.Option{
+			nats.Name("gnmic-shared"),
+			nats.ReconnectWait(c.ReconnectWait),
+			nats.MaxReconnects(-1),
+			nats.RetryOnFailedConnect(true),
+			nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+				l.Printf("NATS error: %v", err)
+			}),
+			nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+				l.Printf("Disconnected from NATS %s: %v", c.Address, err)
+			}),
+			nats.ReconnectHandler(func(*nats.Conn) {
+				l.Printf("Reconnected to NATS %s", c.Address)
+			}),
+			nats.ClosedHandler(func(*nats.Conn) {
+				l.Printf("NATS connection to %s is closed", c.Address)
+			}),
+		}
+		if c.Username != "" && c.Password != "" {
+			opts = append(opts, nats.UserInfo(c.Username, c.Password))
+		}
+		tlsConfig, err := c.TLS.ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			opts = append(opts, nats.Secure(tlsConfig))
+		}
+		l.Printf("opening shared connection to %s", c.Address)
+		return nats.Connect(c.Address, opts...)
+	})
+}
@@ -58,38 +58,40 @@ func init() {
 
 // StanOutput //
 type StanOutput struct {
-	Cfg      *Config
-	cancelFn context.CancelFunc
-	logger   *log.Logger
-	msgChan  chan *outputs.ProtoMsg
-	wg       *sync.WaitGroup
-	mo       *formatters.MarshalOptions
-	evps     []formatters.EventProcessor
+	Cfg          *Config
+	cancelFn     context.CancelFunc
+	logger       *log.Logger
+	msgChan      chan *outputs.ProtoMsg
+	wg           *sync.WaitGroup
+	mo           *formatters.MarshalOptions
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
 
 	targetTpl *template.Template
 }
 
 // Config //
 type Config struct {
-	Name               string        `mapstructure:"name,omitempty"`
-	Address            string        `mapstructure:"address,omitempty"`
-	SubjectPrefix      string        `mapstructure:"subject-prefix,omitempty"`
-	Subject            string        `mapstructure:"subject,omitempty"`
-	Username           string        `mapstructure:"username,omitempty"`
-	Password           string        `mapstructure:"password,omitempty"`
-	ClusterName        string        `mapstructure:"cluster-name,omitempty"`
-	PingInterval       int           `mapstructure:"ping-interval,omitempty"`
-	PingRetry          int           `mapstructure:"ping-retry,omitempty"`
-	Format             string        `mapstructure:"format,omitempty"`
-	AddTarget          string        `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string        `mapstructure:"target-template,omitempty"`
-	OverrideTimestamps bool          `mapstructure:"override-timestamps,omitempty"`
-	RecoveryWaitTime   time.Duration `mapstructure:"recovery-wait-time,omitempty"`
-	NumWorkers         int           `mapstructure:"num-workers,omitempty"`
-	Debug              bool          `mapstructure:"debug,omitempty"`
-	WriteTimeout       time.Duration `mapstructure:"write-timeout,omitempty"`
-	EnableMetrics      bool          `mapstructure:"enable-metrics,omitempty"`
-	EventProcessors    []string      `mapstructure:"event-processors,omitempty"`
+	Name               string                   `mapstructure:"name,omitempty"`
+	Address            string                   `mapstructure:"address,omitempty" default:"localhost:4222"`
+	SubjectPrefix      string                   `mapstructure:"subject-prefix,omitempty"`
+	Subject            string                   `mapstructure:"subject,omitempty" default:"telemetry"`
+	Username           string                   `mapstructure:"username,omitempty"`
+	Password           string                   `mapstructure:"password,omitempty"`
+	ClusterName        string                   `mapstructure:"cluster-name,omitempty" default:"test-cluster"`
+	PingInterval       int                      `mapstructure:"ping-interval,omitempty" default:"5"`
+	PingRetry          int                      `mapstructure:"ping-retry,omitempty" default:"2"`
+	Format             string                   `mapstructure:"format,omitempty" default:"event"`
+	AddTarget          string                   `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                   `mapstructure:"target-template,omitempty"`
+	OverrideTimestamps bool                     `mapstructure:"override-timestamps,omitempty"`
+	RecoveryWaitTime   time.Duration            `mapstructure:"recovery-wait-time,omitempty" default:"2s"`
+	NumWorkers         int                      `mapstructure:"num-workers,omitempty" default:"1"`
+	Debug              bool                     `mapstructure:"debug,omitempty"`
+	WriteTimeout       time.Duration            `mapstructure:"write-timeout,omitempty" default:"5s"`
+	EnableMetrics      bool                     `mapstructure:"enable-metrics,omitempty"`
+	EventProcessors    []string                 `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat `mapstructure:"number-format,omitempty"`
 }
 
 func (s *StanOutput) String() string {
@@ -111,7 +113,11 @@ func (s *StanOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := s.Cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	s.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	s.evps, err = formatters.MakeEventProcessors(
 		logger,
 		s.Cfg.EventProcessors,
@@ -122,6 +128,7 @@ func (s *StanOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	s.evps = append(s.evps, nfps...)
 	return nil
 }
 
@@ -319,7 +326,7 @@ CRCONN:
 			if err != nil {
 				s.logger.Printf("failed to add target to the response: %v", err)
 			}
-			b, err := s.mo.Marshal(pmsg, m.GetMeta(), s.evps...)
+			b, err := outputs.OverrideMarshalOptions(s.mo, m.GetMeta()).Marshal(pmsg, m.GetMeta(), s.evpOverrides.Select(m.GetMeta(), s.evps)...)
 			if err != nil {
 				if s.Cfg.Debug {
 					s.logger.Printf("%s failed marshaling proto msg: %v", workerLogPrefix, err)
@@ -0,0 +1,53 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package notification_output
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var notificationNumberOfSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "notification_output",
+	Name:      "number_of_notifications_sent_success_total",
+	Help:      "Number of notifications successfully sent by gnmic notification output, by channel",
+}, []string{"name", "channel"})
+
+var notificationNumberOfFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "notification_output",
+	Name:      "number_of_notifications_sent_fail_total",
+	Help:      "Number of notifications gnmic notification output failed to send, by channel",
+}, []string{"name", "channel"})
+
+var notificationNumberOfDroppedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "notification_output",
+	Name:      "number_of_events_dropped_total",
+	Help:      "Number of events dropped by gnmic notification output, by reason",
+}, []string{"name", "reason"})
+
+func initMetrics() {
+	notificationNumberOfSent.WithLabelValues("", "").Add(0)
+	notificationNumberOfFailed.WithLabelValues("", "").Add(0)
+	notificationNumberOfDroppedEvents.WithLabelValues("", "").Add(0)
+}
+
+func registerMetrics(reg *prometheus.Registry) error {
+	initMetrics()
+	var err error
+	if err = reg.Register(notificationNumberOfSent); err != nil {
+		return err
+	}
+	if err = reg.Register(notificationNumberOfFailed); err != nil {
+		return err
+	}
+	if err = reg.Register(notificationNumberOfDroppedEvents); err != nil {
+		return err
+	}
+	return nil
+}
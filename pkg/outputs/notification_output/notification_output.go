@@ -0,0 +1,488 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package notification_output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	loggingPrefix        = "[notification_output:%s] "
+	defaultBufferSize    = 1000
+	defaultBatchSize     = 10
+	defaultBatchInterval = 10 * time.Second
+	defaultRateInterval  = time.Minute
+	defaultTimeout       = 10 * time.Second
+
+	defaultTitle = `gNMIc {{ .Name }}: {{ len .Events }} event(s)`
+	defaultBody  = `
+{{- range .Events }}
+- {{ .Name }}{{ with index .Tags "source" }} from {{ . }}{{ end }}:
+{{- range $k, $v := .Values }}
+    {{ $k }}: {{ $v }}
+{{- end }}
+{{- end }}
+{{- if .Dropped }}
+{{ .Dropped }} event(s) dropped by the rate limit.
+{{- end }}`
+
+	dropReasonBufferFull = "buffer_full"
+	dropReasonRateLimit  = "rate_limit"
+)
+
+func init() {
+	outputs.Register("notification", func() outputs.Output {
+		return &notificationOutput{
+			cfg:    &Config{},
+			logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
+		}
+	})
+}
+
+// notificationOutput sends the events it receives, grouped in batches,
+// as templated messages to Slack or Teams webhooks or by email.
+type notificationOutput struct {
+	name         string
+	cfg          *Config
+	logger       *log.Logger
+	cancelFn     context.CancelFunc
+	eventChan    chan *formatters.EventMsg
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+
+	condition *gojq.Code
+	title     *template.Template
+	body      *template.Template
+	notifiers []notifier
+	// nil if the number of notifications is not limited
+	limiter *rate.Limiter
+	// number of events dropped by the rate limit
+	// since the last notification sent
+	dropped int
+}
+
+type Config struct {
+	Slack *webhookConfig `mapstructure:"slack,omitempty" json:"slack,omitempty"`
+	Teams *webhookConfig `mapstructure:"teams,omitempty" json:"teams,omitempty"`
+	Email *emailConfig   `mapstructure:"email,omitempty" json:"email,omitempty"`
+	// jq expression selecting the events to notify
+	Condition string `mapstructure:"condition,omitempty" json:"condition,omitempty"`
+	// Go templates of the notification title and body
+	Title    string `mapstructure:"title,omitempty" json:"title,omitempty" default:"gNMIc {{ .Name }}: {{ len .Events }} event(s)"`
+	Template string `mapstructure:"template,omitempty" json:"template,omitempty"`
+	// maximum number of events in a notification
+	BatchSize int `mapstructure:"batch-size,omitempty" json:"batch-size,omitempty" default:"10"`
+	// maximum time an event waits for its batch to fill up
+	BatchInterval time.Duration `mapstructure:"batch-interval,omitempty" json:"batch-interval,omitempty" default:"10s"`
+	// maximum number of notifications sent per rate-interval
+	MaxNotifications int           `mapstructure:"max-notifications,omitempty" json:"max-notifications,omitempty"`
+	RateInterval     time.Duration `mapstructure:"rate-interval,omitempty" json:"rate-interval,omitempty" default:"1m"`
+	Timeout          time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty" default:"10s"`
+	BufferSize       int           `mapstructure:"buffer-size,omitempty" json:"buffer-size,omitempty" default:"1000"`
+
+	Debug           bool                     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+	EnableMetrics   bool                     `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+	EventProcessors []string                 `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat    *formatters.NumberFormat `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
+}
+
+type webhookConfig struct {
+	// the webhook URL holds its credentials
+	URL string           `mapstructure:"url,omitempty" json:"-"`
+	TLS *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+}
+
+type emailConfig struct {
+	// SMTP server address, host:port
+	Address  string           `mapstructure:"address,omitempty" json:"address,omitempty"`
+	Username string           `mapstructure:"username,omitempty" json:"username,omitempty"`
+	Password string           `mapstructure:"password,omitempty" json:"-"`
+	From     string           `mapstructure:"from,omitempty" json:"from,omitempty"`
+	To       []string         `mapstructure:"to,omitempty" json:"to,omitempty"`
+	TLS      *types.TLSConfig `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+}
+
+// templateInput is the input of the title and body templates.
+type templateInput struct {
+	// output name
+	Name    string
+	Events  []*formatters.EventMsg
+	Dropped int
+}
+
+func (n *notificationOutput) SetLogger(logger *log.Logger) {
+	if logger != nil && n.logger != nil {
+		n.logger.SetOutput(logger.Writer())
+		n.logger.SetFlags(logger.Flags())
+	}
+}
+
+func (n *notificationOutput) SetEventProcessors(ps map[string]map[string]interface{},
+	logger *log.Logger,
+	tcs map[string]*types.TargetConfig,
+	acts map[string]map[string]interface{}) error {
+	nfps, err := n.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	n.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
+	n.evps, err = formatters.MakeEventProcessors(
+		logger,
+		n.cfg.EventProcessors,
+		ps,
+		tcs,
+		acts,
+	)
+	if err != nil {
+		return err
+	}
+	n.evps = append(n.evps, nfps...)
+	return nil
+}
+
+func (n *notificationOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	n.name = name
+	err := outputs.DecodeConfig(cfg, n.cfg)
+	if err != nil {
+		return err
+	}
+	n.logger.SetPrefix(fmt.Sprintf(loggingPrefix, name))
+
+	for _, opt := range opts {
+		if err := opt(n); err != nil {
+			return err
+		}
+	}
+	err = n.setDefaults()
+	if err != nil {
+		return err
+	}
+	err = n.initNotifiers()
+	if err != nil {
+		return err
+	}
+	if n.cfg.Condition != "" {
+		q, err := gojq.Parse(strings.TrimSpace(n.cfg.Condition))
+		if err != nil {
+			return fmt.Errorf("invalid condition: %v", err)
+		}
+		n.condition, err = gojq.Compile(q)
+		if err != nil {
+			return fmt.Errorf("invalid condition: %v", err)
+		}
+	}
+	n.title, err = gtemplate.CreateTemplate(fmt.Sprintf("%s-title", name), n.cfg.Title)
+	if err != nil {
+		return fmt.Errorf("invalid title: %v", err)
+	}
+	n.body, err = gtemplate.CreateTemplate(fmt.Sprintf("%s-template", name), n.cfg.Template)
+	if err != nil {
+		return fmt.Errorf("invalid template: %v", err)
+	}
+	if n.cfg.MaxNotifications > 0 {
+		n.limiter = rate.NewLimiter(rate.Every(n.cfg.RateInterval/time.Duration(n.cfg.MaxNotifications)), n.cfg.MaxNotifications)
+	}
+	n.eventChan = make(chan *formatters.EventMsg, n.cfg.BufferSize)
+
+	ctx, n.cancelFn = context.WithCancel(ctx)
+	go n.start(ctx)
+	n.logger.Printf("initialized notification output: %s", n.String())
+	return nil
+}
+
+func (n *notificationOutput) setDefaults() error {
+	if n.cfg.BatchSize < 0 || n.cfg.MaxNotifications < 0 || n.cfg.BufferSize < 0 {
+		return errors.New("batch-size, max-notifications and buffer-size must be positive")
+	}
+	if n.cfg.Title == "" {
+		n.cfg.Title = defaultTitle
+	}
+	if n.cfg.Template == "" {
+		n.cfg.Template = defaultBody
+	}
+	if n.cfg.BatchSize == 0 {
+		n.cfg.BatchSize = defaultBatchSize
+	}
+	if n.cfg.BatchInterval <= 0 {
+		n.cfg.BatchInterval = defaultBatchInterval
+	}
+	if n.cfg.RateInterval <= 0 {
+		n.cfg.RateInterval = defaultRateInterval
+	}
+	if n.cfg.Timeout <= 0 {
+		n.cfg.Timeout = defaultTimeout
+	}
+	if n.cfg.BufferSize == 0 {
+		n.cfg.BufferSize = defaultBufferSize
+	}
+	return nil
+}
+
+func (n *notificationOutput) initNotifiers() error {
+	n.notifiers = make([]notifier, 0, 3)
+	if n.cfg.Slack != nil {
+		if n.cfg.Slack.URL == "" {
+			return errors.New("missing slack url")
+		}
+		client, err := newHTTPClient(n.cfg.Slack, n.cfg.Timeout)
+		if err != nil {
+			return err
+		}
+		n.notifiers = append(n.notifiers, &slackNotifier{url: n.cfg.Slack.URL, client: client})
+	}
+	if n.cfg.Teams != nil {
+		if n.cfg.Teams.URL == "" {
+			return errors.New("missing teams url")
+		}
+		client, err := newHTTPClient(n.cfg.Teams, n.cfg.Timeout)
+		if err != nil {
+			return err
+		}
+		n.notifiers = append(n.notifiers, &teamsNotifier{url: n.cfg.Teams.URL, client: client})
+	}
+	if n.cfg.Email != nil {
+		err := validateEmailConfig(n.cfg.Email)
+		if err != nil {
+			return err
+		}
+		en := &emailNotifier{cfg: n.cfg.Email, timeout: n.cfg.Timeout}
+		if n.cfg.Email.TLS != nil {
+			en.tlsCfg, err = n.cfg.Email.TLS.ClientConfig()
+			if err != nil {
+				return err
+			}
+		}
+		n.notifiers = append(n.notifiers, en)
+	}
+	if len(n.notifiers) == 0 {
+		return errors.New("missing slack, teams or email config")
+	}
+	return nil
+}
+
+func (n *notificationOutput) Write(ctx context.Context, m proto.Message, meta outputs.Meta) {
+	if m == nil {
+		return
+	}
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+	rsp, err := outputs.AddSubscriptionTarget(m, meta, "if-not-present", outputs.DefaultTargetTemplate)
+	if err != nil {
+		n.logger.Printf("failed to add target to the response: %v", err)
+		return
+	}
+	subName := meta["subscription-name"]
+	if subName == "" {
+		subName = "default"
+	}
+	events, err := formatters.ResponseToEventMsgs(subName, rsp, meta, n.evpOverrides.Select(meta, n.evps)...)
+	if err != nil {
+		n.logger.Printf("failed to convert message to event: %v", err)
+		return
+	}
+	n.enqueue(events)
+}
+
+func (n *notificationOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	var evs = []*formatters.EventMsg{ev}
+	for _, proc := range n.evps {
+		evs = proc.Apply(evs...)
+	}
+	n.enqueue(evs)
+}
+
+// enqueue adds the selected events to the buffer,
+// they are dropped if it is full.
+func (n *notificationOutput) enqueue(evs []*formatters.EventMsg) {
+	for _, ev := range evs {
+		ok, err := n.selected(ev)
+		if err != nil {
+			n.logger.Printf("failed to evaluate condition: %v", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		select {
+		case n.eventChan <- ev:
+		default:
+			n.logger.Printf("buffer full, dropping event %s", ev)
+			if n.cfg.EnableMetrics {
+				notificationNumberOfDroppedEvents.WithLabelValues(n.name, dropReasonBufferFull).Inc()
+			}
+		}
+	}
+}
+
+// selected reports whether event ev matches the configured condition.
+func (n *notificationOutput) selected(ev *formatters.EventMsg) (bool, error) {
+	if n.condition == nil {
+		return true, nil
+	}
+	iter := n.condition.Run(ev.ToMap())
+	r, ok := iter.Next()
+	if !ok {
+		return false, nil
+	}
+	switch r := r.(type) {
+	case error:
+		return false, r
+	case bool:
+		return r, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected condition result type: %T", r)
+}
+
+func (n *notificationOutput) Close() error {
+	if n.cancelFn != nil {
+		n.cancelFn()
+	}
+	return nil
+}
+
+func (n *notificationOutput) RegisterMetrics(reg *prometheus.Registry) {
+	if !n.cfg.EnableMetrics {
+		return
+	}
+	if reg == nil {
+		n.logger.Printf("ERR: output metrics enabled but main registry is not initialized, enable main metrics under `api-server`")
+		return
+	}
+	if err := registerMetrics(reg); err != nil {
+		n.logger.Printf("failed to register metrics: %+v", err)
+	}
+}
+
+func (n *notificationOutput) String() string {
+	b, err := json.Marshal(n.cfg)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (n *notificationOutput) SetName(name string)                             {}
+func (n *notificationOutput) SetClusterName(name string)                      {}
+func (n *notificationOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+
+// start groups the buffered events in batches of at most batch-size events.
+// A batch is sent once full or batch-interval after its first event.
+func (n *notificationOutput) start(ctx context.Context) {
+	batch := make([]*formatters.EventMsg, 0, n.cfg.BatchSize)
+	timer := time.NewTimer(n.cfg.BatchInterval)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-n.eventChan:
+			batch = append(batch, ev)
+			if len(batch) == 1 {
+				timer.Reset(n.cfg.BatchInterval)
+			}
+			if len(batch) < n.cfg.BatchSize {
+				continue
+			}
+			timer.Stop()
+		case <-timer.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		n.flush(ctx, batch)
+		batch = make([]*formatters.EventMsg, 0, n.cfg.BatchSize)
+	}
+}
+
+// flush sends a notification for the events of batch to all the channels,
+// unless the rate limit is reached, in which case the events are dropped.
+func (n *notificationOutput) flush(ctx context.Context, batch []*formatters.EventMsg) {
+	if n.limiter != nil && !n.limiter.Allow() {
+		n.dropped += len(batch)
+		if n.cfg.Debug {
+			n.logger.Printf("rate limit reached, dropping %d event(s)", len(batch))
+		}
+		if n.cfg.EnableMetrics {
+			notificationNumberOfDroppedEvents.WithLabelValues(n.name, dropReasonRateLimit).Add(float64(len(batch)))
+		}
+		return
+	}
+	title, body, err := n.render(batch)
+	if err != nil {
+		n.logger.Printf("failed to render notification: %v", err)
+		return
+	}
+	n.dropped = 0
+	for _, nt := range n.notifiers {
+		err = nt.notify(ctx, title, body)
+		if err != nil {
+			n.logger.Printf("failed to send %s notification: %v", nt.channel(), err)
+			if n.cfg.EnableMetrics {
+				notificationNumberOfFailed.WithLabelValues(n.name, nt.channel()).Inc()
+			}
+			continue
+		}
+		if n.cfg.Debug {
+			n.logger.Printf("%s notification sent: %s", nt.channel(), title)
+		}
+		if n.cfg.EnableMetrics {
+			notificationNumberOfSent.WithLabelValues(n.name, nt.channel()).Inc()
+		}
+	}
+}
+
+// render executes the title and body templates with the events of batch.
+func (n *notificationOutput) render(batch []*formatters.EventMsg) (string, string, error) {
+	in := &templateInput{
+		Name:    n.name,
+		Events:  batch,
+		Dropped: n.dropped,
+	}
+	b := new(bytes.Buffer)
+	err := n.title.Execute(b, in)
+	if err != nil {
+		return "", "", err
+	}
+	title := strings.TrimSpace(b.String())
+	b.Reset()
+	err = n.body.Execute(b, in)
+	if err != nil {
+		return "", "", err
+	}
+	return title, strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,303 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package notification_output
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+func newTestOutput(t *testing.T, cfg map[string]interface{}) *notificationOutput {
+	t.Helper()
+	n := &notificationOutput{
+		cfg:    &Config{},
+		logger: log.New(io.Discard, "", 0),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	err := n.Init(ctx, "alerts", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func webhookServer(t *testing.T) (*httptest.Server, chan map[string]interface{}) {
+	t.Helper()
+	payloads := make(chan map[string]interface{}, 10)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payloads <- m
+	}))
+	t.Cleanup(s.Close)
+	return s, payloads
+}
+
+func TestNotificationOutputWebhooks(t *testing.T) {
+	slack, slackPayloads := webhookServer(t)
+	teams, teamsPayloads := webhookServer(t)
+	n := newTestOutput(t, map[string]interface{}{
+		"slack":          map[string]interface{}{"url": slack.URL},
+		"teams":          map[string]interface{}{"url": teams.URL},
+		"condition":      `.tags.severity == "critical"`,
+		"batch-size":     2,
+		"batch-interval": "1m",
+	})
+	ctx := context.Background()
+	n.WriteEvent(ctx, &formatters.EventMsg{
+		Name:   "cpu",
+		Tags:   map[string]string{"source": "r1", "severity": "critical"},
+		Values: map[string]interface{}{"cpu_usage": 97},
+	})
+	// not selected by the condition
+	n.WriteEvent(ctx, &formatters.EventMsg{
+		Name:   "cpu",
+		Tags:   map[string]string{"source": "r2", "severity": "minor"},
+		Values: map[string]interface{}{"cpu_usage": 80},
+	})
+	n.WriteEvent(ctx, &formatters.EventMsg{
+		Name:   "memory",
+		Tags:   map[string]string{"source": "r3", "severity": "critical"},
+		Values: map[string]interface{}{"memory_usage": 99},
+	})
+
+	select {
+	case p := <-slackPayloads:
+		text, _ := p["text"].(string)
+		if !strings.HasPrefix(text, "*gNMIc alerts: 2 event(s)*") ||
+			!strings.Contains(text, "- cpu from r1:\n    cpu_usage: 97") ||
+			!strings.Contains(text, "- memory from r3:\n    memory_usage: 99") ||
+			strings.Contains(text, "r2") {
+			t.Errorf("unexpected slack message %q", text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slack notification not received")
+	}
+	select {
+	case p := <-teamsPayloads:
+		if p["@type"] != "MessageCard" || p["title"] != "gNMIc alerts: 2 event(s)" {
+			t.Errorf("unexpected teams message card %v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("teams notification not received")
+	}
+}
+
+// recorder records the notifications it is sent.
+type recorder struct {
+	bodies []string
+}
+
+func (r *recorder) channel() string { return "recorder" }
+
+func (r *recorder) notify(ctx context.Context, title, body string) error {
+	r.bodies = append(r.bodies, body)
+	return nil
+}
+
+func TestNotificationOutputRateLimit(t *testing.T) {
+	rec := new(recorder)
+	n := &notificationOutput{
+		name:      "alerts",
+		cfg:       &Config{},
+		logger:    log.New(io.Discard, "", 0),
+		notifiers: []notifier{rec},
+		limiter:   rate.NewLimiter(rate.Every(time.Hour), 1),
+	}
+	err := n.setDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.title, err = gtemplate.CreateTemplate("title", n.cfg.Title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.body, err = gtemplate.CreateTemplate("body", n.cfg.Template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := []*formatters.EventMsg{
+		{Name: "e1", Values: map[string]interface{}{"v": 1}},
+		{Name: "e2", Values: map[string]interface{}{"v": 2}},
+	}
+	ctx := context.Background()
+	n.flush(ctx, batch)
+	n.flush(ctx, batch)
+	if len(rec.bodies) != 1 || n.dropped != 2 {
+		t.Fatalf("expected 1 notification and 2 dropped events, got %d notifications and %d dropped events",
+			len(rec.bodies), n.dropped)
+	}
+	n.limiter = nil
+	n.flush(ctx, batch)
+	if len(rec.bodies) != 2 || !strings.HasSuffix(rec.bodies[1], "2 event(s) dropped by the rate limit.") {
+		t.Errorf("unexpected notifications %q", rec.bodies)
+	}
+	if n.dropped != 0 {
+		t.Errorf("expected the dropped events count to be reset, got %d", n.dropped)
+	}
+}
+
+// fakeSMTPServer accepts a single SMTP session on ln
+// and sends the received message to data.
+func fakeSMTPServer(ln net.Listener, data chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			fmt.Fprint(conn, "250-localhost\r\n250 AUTH PLAIN\r\n")
+		case strings.HasPrefix(cmd, "AUTH"):
+			fmt.Fprint(conn, "235 authenticated\r\n")
+		case cmd == "DATA":
+			fmt.Fprint(conn, "354 go ahead\r\n")
+			sb := new(strings.Builder)
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			data <- sb.String()
+			fmt.Fprint(conn, "250 queued\r\n")
+		case cmd == "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	data := make(chan string, 1)
+	go fakeSMTPServer(ln, data)
+
+	en := &emailNotifier{
+		cfg: &emailConfig{
+			Address:  ln.Addr().String(),
+			Username: "gnmic",
+			Password: "secret",
+			From:     "gnmic@example.com",
+			To:       []string{"noc@example.com", "oncall@example.com"},
+		},
+		timeout: 5 * time.Second,
+	}
+	err = en.notify(context.Background(), "gNMIc alerts: 1 event(s)", "- cpu from r1:\n    cpu_usage: 97")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-data:
+		for _, s := range []string{
+			"From: gnmic@example.com\r\n",
+			"To: noc@example.com, oncall@example.com\r\n",
+			"Subject: gNMIc alerts: 1 event(s)\r\n",
+			"\r\n\r\n- cpu from r1:\r\n    cpu_usage: 97\r\n",
+		} {
+			if !strings.Contains(msg, s) {
+				t.Errorf("message %q does not contain %q", msg, s)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("email not received")
+	}
+}
+
+func TestNotificationOutputInitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]interface{}
+	}{
+		{
+			name: "no_channel",
+			cfg:  map[string]interface{}{},
+		},
+		{
+			name: "missing_url",
+			cfg:  map[string]interface{}{"slack": map[string]interface{}{}},
+		},
+		{
+			name: "missing_email_to",
+			cfg: map[string]interface{}{"email": map[string]interface{}{
+				"address": "smtp.example.com:587",
+				"from":    "gnmic@example.com",
+			}},
+		},
+		{
+			name: "invalid_condition",
+			cfg: map[string]interface{}{
+				"slack":     map[string]interface{}{"url": "http://localhost"},
+				"condition": ".tags.severity ==",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &notificationOutput{
+				cfg:    &Config{},
+				logger: log.New(io.Discard, "", 0),
+			}
+			err := n.Init(context.Background(), "alerts", tt.cfg)
+			if err == nil {
+				n.Close()
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	n := &notificationOutput{cfg: &Config{}}
+	if err := n.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	// the multi-line default body template is not held in a tag.
+	for _, m := range testutils.DefaultTagsMismatches(n.cfg, "template") {
+		t.Error(m)
+	}
+}
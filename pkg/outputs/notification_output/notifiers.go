@@ -0,0 +1,212 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package notification_output
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const (
+	channelSlack = "slack"
+	channelTeams = "teams"
+	channelEmail = "email"
+)
+
+// notifier sends a notification to a channel.
+type notifier interface {
+	channel() string
+	notify(ctx context.Context, title, body string) error
+}
+
+func newHTTPClient(cfg *webhookConfig, timeout time.Duration) (*http.Client, error) {
+	c := &http.Client{
+		Timeout: timeout,
+	}
+	if cfg.TLS != nil {
+		tlsCfg, err := cfg.TLS.ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		c.Transport = &http.Transport{
+			TLSClientConfig: tlsCfg,
+		}
+	}
+	return c, nil
+}
+
+// postJSON sends payload to the webhook url.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(rsp.Body, 512))
+		return fmt.Errorf("unexpected response status %q: %s", rsp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// slackNotifier posts messages to a Slack incoming webhook.
+type slackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (s *slackNotifier) channel() string { return channelSlack }
+
+func (s *slackNotifier) notify(ctx context.Context, title, body string) error {
+	return postJSON(ctx, s.client, s.url, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", title, body),
+	})
+}
+
+// teamsNotifier posts message cards to a Microsoft Teams incoming webhook.
+type teamsNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (t *teamsNotifier) channel() string { return channelTeams }
+
+func (t *teamsNotifier) notify(ctx context.Context, title, body string) error {
+	return postJSON(ctx, t.client, t.url, map[string]string{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  title,
+		"title":    title,
+		// the card text is rendered as markdown,
+		// a fenced block keeps the body lines as is.
+		"text": "```\n" + body + "\n```",
+	})
+}
+
+// emailNotifier sends emails through an SMTP server.
+// STARTTLS is used if the server supports it.
+type emailNotifier struct {
+	cfg     *emailConfig
+	tlsCfg  *tls.Config
+	timeout time.Duration
+}
+
+func (e *emailNotifier) channel() string { return channelEmail }
+
+func (e *emailNotifier) notify(ctx context.Context, title, body string) error {
+	host, _, err := net.SplitHostPort(e.cfg.Address)
+	if err != nil {
+		return err
+	}
+	d := &net.Dialer{Timeout: e.timeout}
+	conn, err := d.DialContext(ctx, "tcp", e.cfg.Address)
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(e.timeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		tlsCfg := &tls.Config{ServerName: host}
+		if e.tlsCfg != nil {
+			tlsCfg = e.tlsCfg.Clone()
+			if tlsCfg.ServerName == "" {
+				tlsCfg.ServerName = host
+			}
+		}
+		if err = c.StartTLS(tlsCfg); err != nil {
+			return err
+		}
+	}
+	if e.cfg.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(mailMessage(e.cfg.From, e.cfg.To, title, body, time.Now()))
+	if err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// mailMessage returns a plain text email with subject title and body.
+func mailMessage(from string, to []string, title, body string, now time.Time) []byte {
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "From: %s\r\n", from)
+	fmt.Fprintf(b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title))
+	fmt.Fprintf(b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	// the SMTP client converts the body line endings to CRLF
+	b.WriteString(body)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+func validateEmailConfig(cfg *emailConfig) error {
+	if cfg.Address == "" {
+		return errors.New("missing email address")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return fmt.Errorf("invalid email address %q: %v", cfg.Address, err)
+	}
+	if cfg.From == "" {
+		return errors.New("missing email from")
+	}
+	if len(cfg.To) == 0 {
+		return errors.New("missing email to")
+	}
+	return nil
+}
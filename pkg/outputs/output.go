@@ -44,6 +44,62 @@ type Output interface {
 	SetTargetsConfig(map[string]*types.TargetConfig)
 }
 
+// HealthChecker is an optional interface implemented by outputs
+// that are able to report whether they can currently deliver messages.
+type HealthChecker interface {
+	Healthy() bool
+}
+
+// IsHealthy returns the health status of the output.
+// Outputs that do not implement HealthChecker are always considered healthy.
+func IsHealthy(o Output) bool {
+	if hc, ok := o.(HealthChecker); ok {
+		return hc.Healthy()
+	}
+	return true
+}
+
+// MetricsGatherer is an optional interface implemented by outputs
+// exposing their data as prometheus metrics.
+type MetricsGatherer interface {
+	Gatherer() prometheus.Gatherer
+}
+
+// GathererOf returns the prometheus gatherer of the output
+// or nil if it does not implement MetricsGatherer.
+func GathererOf(o Output) prometheus.Gatherer {
+	if mg, ok := o.(MetricsGatherer); ok {
+		return mg.Gatherer()
+	}
+	return nil
+}
+
+// Drainer is an optional interface implemented by outputs buffering
+// messages before delivering them.
+// Drain removes and returns the buffered messages so that they can be
+// written to another output.
+// The deliveries of the drained messages (see ProtoMsg.GetDelivery) are left pending,
+// the caller marks them as done once the messages are written again.
+type Drainer interface {
+	Drain() []*ProtoMsg
+}
+
+// Flusher is an optional interface implemented by outputs
+// delivering messages asynchronously.
+// Flush blocks until the messages written so far are delivered
+// or until the context is done.
+type Flusher interface {
+	Flush(context.Context) error
+}
+
+// Flush flushes the output if it implements Flusher.
+func Flush(ctx context.Context, o Output) error {
+	if f, ok := o.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
 type Initializer func() Output
 
 var Outputs = map[string]Initializer{}
@@ -62,6 +118,9 @@ var OutputTypes = map[string]struct{}{
 	"jetstream":        {},
 	"snmp":             {},
 	"asciigraph":       {},
+	"failover":         {},
+	"tee":              {},
+	"notification":     {},
 }
 
 func Register(name string, initFn Initializer) {
@@ -158,8 +217,9 @@ const (
 )
 
 func Marshal(pmsg protoreflect.ProtoMessage, meta map[string]string, mo *formatters.MarshalOptions, splitEvents bool, evps ...formatters.EventProcessor) ([][]byte, error) {
+	mo = OverrideMarshalOptions(mo, meta)
 	switch mo.Format {
-	case "event":
+	case "event", formatters.FormatInflux, formatters.FormatGraphite:
 		if splitEvents {
 			return marshalSplit(pmsg, meta, mo, evps...)
 		}
@@ -196,7 +256,11 @@ func marshalSplit(pmsg protoreflect.ProtoMessage, meta map[string]string, mo *fo
 			}
 			rs := make([][]byte, 0, numEvents)
 			marshalFn := json.Marshal
-			if mo.Multiline {
+			if formatters.IsLineFormat(mo.Format) {
+				marshalFn = func(v any) ([]byte, error) {
+					return formatters.EventsToLines(mo.Format, v.(*formatters.EventMsg))
+				}
+			} else if mo.Multiline {
 				marshalFn = func(v any) ([]byte, error) {
 					return json.MarshalIndent(v, "", mo.Indent)
 				}
@@ -206,6 +270,9 @@ func marshalSplit(pmsg protoreflect.ProtoMessage, meta map[string]string, mo *fo
 				if err != nil {
 					return nil, err
 				}
+				if len(b) == 0 {
+					continue
+				}
 				rs = append(rs, b)
 			}
 			return rs, nil
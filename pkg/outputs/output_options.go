@@ -0,0 +1,98 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// OverrideMarshalOptions returns the marshal options mo with the format
+// overridden by the subscription the message meta belongs to, if any.
+func OverrideMarshalOptions(mo *formatters.MarshalOptions, meta map[string]string) *formatters.MarshalOptions {
+	format, ok := meta[formatters.MetaOutputFormat]
+	if !ok || format == "" || mo == nil || format == mo.Format {
+		return mo
+	}
+	nmo := *mo
+	nmo.Format = format
+	return &nmo
+}
+
+// EventProcessorsOverrides builds and caches the event processors chains
+// requested by the subscriptions overriding an output's event processors.
+// A nil *EventProcessorsOverrides never overrides the output's processors.
+type EventProcessorsOverrides struct {
+	logger *log.Logger
+	ps     map[string]map[string]interface{}
+	tcs    map[string]*types.TargetConfig
+	acts   map[string]map[string]interface{}
+	// processors appended to each chain, e.g: the output's number format.
+	tail []formatters.EventProcessor
+
+	m      sync.Mutex
+	chains map[string][]formatters.EventProcessor
+}
+
+func NewEventProcessorsOverrides(
+	ps map[string]map[string]interface{},
+	logger *log.Logger,
+	tcs map[string]*types.TargetConfig,
+	acts map[string]map[string]interface{},
+	tail ...formatters.EventProcessor,
+) *EventProcessorsOverrides {
+	return &EventProcessorsOverrides{
+		logger: logger,
+		ps:     ps,
+		tcs:    tcs,
+		acts:   acts,
+		tail:   tail,
+		chains: make(map[string][]formatters.EventProcessor),
+	}
+}
+
+// Select returns the event processors chain set in the message meta,
+// or evps if the message's subscription does not override them.
+// A chain that fails to initialize is logged once and replaced by evps.
+func (o *EventProcessorsOverrides) Select(meta map[string]string, evps []formatters.EventProcessor) []formatters.EventProcessor {
+	if o == nil {
+		return evps
+	}
+	names, ok := meta[formatters.MetaOutputEventProcessors]
+	if !ok {
+		return evps
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+	if chain, ok := o.chains[names]; ok {
+		if chain == nil {
+			return evps
+		}
+		return chain
+	}
+	var processorNames []string
+	if names != "" {
+		processorNames = strings.Split(names, ",")
+	}
+	chain, err := formatters.MakeEventProcessors(o.logger, processorNames, o.ps, o.tcs, o.acts)
+	if err != nil {
+		if o.logger != nil {
+			o.logger.Printf("failed to initialize subscription event processors %q: %v", names, err)
+		}
+		o.chains[names] = nil
+		return evps
+	}
+	chain = append(chain, o.tail...)
+	o.chains[names] = chain
+	return chain
+}
@@ -0,0 +1,163 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestOverrideMarshalOptions(t *testing.T) {
+	mo := &formatters.MarshalOptions{Format: "json", Multiline: true}
+	if got := OverrideMarshalOptions(mo, map[string]string{"source": "r1"}); got != mo {
+		t.Errorf("expected the output marshal options to be used")
+	}
+	got := OverrideMarshalOptions(mo, map[string]string{formatters.MetaOutputFormat: "proto"})
+	if got.Format != "proto" || !got.Multiline {
+		t.Errorf("unexpected marshal options: %+v", got)
+	}
+	if mo.Format != "json" {
+		t.Errorf("output marshal options modified: %+v", mo)
+	}
+}
+
+func TestEventProcessorsOverrides(t *testing.T) {
+	ps := map[string]map[string]interface{}{
+		"add-site": {
+			"event-add-tag": map[string]interface{}{
+				"value-names": []string{"."},
+				"add":         map[string]interface{}{"site": "dc1"},
+			},
+		},
+		"add-role": {
+			"event-add-tag": map[string]interface{}{
+				"value-names": []string{"."},
+				"add":         map[string]interface{}{"role": "spine"},
+			},
+		},
+	}
+	logger := log.New(io.Discard, "", 0)
+	evps, err := formatters.MakeEventProcessors(logger, []string{"add-site"}, ps, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NewEventProcessorsOverrides(ps, logger, nil, nil)
+	rsp := &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: 42,
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "counter"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 1}},
+				}},
+			},
+		},
+	}
+	mo := &formatters.MarshalOptions{Format: "json"}
+	tests := []struct {
+		name string
+		meta map[string]string
+		tags map[string]string
+	}{
+		{
+			name: "output_processors",
+			meta: map[string]string{formatters.MetaOutputFormat: "event"},
+			tags: map[string]string{"site": "dc1"},
+		},
+		{
+			name: "subscription_processors",
+			meta: map[string]string{
+				formatters.MetaOutputFormat:          "event",
+				formatters.MetaOutputEventProcessors: "add-role",
+			},
+			tags: map[string]string{"role": "spine"},
+		},
+		{
+			name: "no_processors",
+			meta: map[string]string{
+				formatters.MetaOutputFormat:          "event",
+				formatters.MetaOutputEventProcessors: "",
+			},
+			tags: map[string]string{},
+		},
+		{
+			name: "unknown_processor",
+			meta: map[string]string{
+				formatters.MetaOutputFormat:          "event",
+				formatters.MetaOutputEventProcessors: "unknown",
+			},
+			tags: map[string]string{"site": "dc1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bb, err := Marshal(rsp, tt.meta, mo, false, o.Select(tt.meta, evps)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(bb) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(bb))
+			}
+			evs := make([]*formatters.EventMsg, 0)
+			if err := json.Unmarshal(bb[0], &evs); err != nil {
+				t.Fatalf("expected event messages: %v", err)
+			}
+			if len(evs) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(evs))
+			}
+			if len(evs[0].Tags) != len(tt.tags) {
+				t.Errorf("got tags %v, expected %v", evs[0].Tags, tt.tags)
+			}
+			for k, v := range tt.tags {
+				if evs[0].Tags[k] != v {
+					t.Errorf("got tags %v, expected %v", evs[0].Tags, tt.tags)
+				}
+			}
+		})
+	}
+	var nilOverrides *EventProcessorsOverrides
+	got := nilOverrides.Select(map[string]string{formatters.MetaOutputEventProcessors: "add-role"}, evps)
+	if len(got) != 1 || got[0] != evps[0] {
+		t.Errorf("expected a nil overrides to keep the output processors")
+	}
+}
+
+func TestEventProcessorsOverridesTail(t *testing.T) {
+	ps := map[string]map[string]interface{}{
+		"add-role": {
+			"event-add-tag": map[string]interface{}{
+				"value-names": []string{"."},
+				"add":         map[string]interface{}{"role": "spine"},
+			},
+		},
+	}
+	nf := &formatters.NumberFormat{NumericStrings: true}
+	nfps, err := nf.Processors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NewEventProcessorsOverrides(ps, log.New(io.Discard, "", 0), nil, nil, nfps...)
+	chain := o.Select(map[string]string{formatters.MetaOutputEventProcessors: "add-role"}, nil)
+	if len(chain) != 2 || chain[1] != nfps[0] {
+		t.Fatalf("expected the number format to be appended to the subscription processors, got %v", chain)
+	}
+	evs := []*formatters.EventMsg{{Values: map[string]interface{}{"counter": "42"}}}
+	for _, p := range chain {
+		evs = p.Apply(evs...)
+	}
+	if evs[0].Values["counter"] != int64(42) || evs[0].Tags["role"] != "spine" {
+		t.Errorf("unexpected event: %v", evs[0])
+	}
+}
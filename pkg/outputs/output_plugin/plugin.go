@@ -0,0 +1,21 @@
+package output_plugin
+
+import (
+	"net/rpc"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+type OutputPlugin struct {
+	Impl outputs.Output
+}
+
+func (p *OutputPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return &outputRPCServer{Impl: p.Impl}, nil
+}
+
+func (p *OutputPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &OutputRPC{client: c}, nil
+}
@@ -0,0 +1,235 @@
+package output_plugin
+
+import (
+	"context"
+	"encoding/gob"
+	"io"
+	"log"
+	"net/rpc"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	outputType    = "output-plugin"
+	loggingPrefix = "[" + outputType + "] "
+)
+
+type InitArgs struct {
+	Name string
+	Cfg  map[string]interface{}
+}
+
+type WriteArgs struct {
+	// proto.Message marshaled as an anypb.Any
+	Msg  []byte
+	Meta outputs.Meta
+}
+
+type WriteEventArgs struct {
+	Event *formatters.EventMsg
+}
+
+type (
+	InitResponse       struct{}
+	WriteResponse      struct{}
+	WriteEventResponse struct{}
+	CloseResponse      struct{}
+)
+
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// outputRPCServer runs in the plugin process.
+type outputRPCServer struct {
+	Impl outputs.Output
+}
+
+func (s *outputRPCServer) Init(args *InitArgs, resp *InitResponse) error {
+	return s.Impl.Init(context.Background(), args.Name, args.Cfg)
+}
+
+func (s *outputRPCServer) Write(args *WriteArgs, resp *WriteResponse) error {
+	anyMsg := new(anypb.Any)
+	err := proto.Unmarshal(args.Msg, anyMsg)
+	if err != nil {
+		return err
+	}
+	msg, err := anyMsg.UnmarshalNew()
+	if err != nil {
+		return err
+	}
+	s.Impl.Write(context.Background(), msg, args.Meta)
+	return nil
+}
+
+func (s *outputRPCServer) WriteEvent(args *WriteEventArgs, resp *WriteEventResponse) error {
+	s.Impl.WriteEvent(context.Background(), args.Event)
+	return nil
+}
+
+func (s *outputRPCServer) Close(args interface{}, resp *CloseResponse) error {
+	return s.Impl.Close()
+}
+
+// OutputRPC runs in gNMIc's main process and implements the outputs.Output interface
+// by forwarding the calls to the plugin process.
+type OutputRPC struct {
+	client *rpc.Client
+	// set if the plugin could not be started
+	err          error
+	name         string
+	logger       *log.Logger
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	cfg          *config
+}
+
+// Unavailable returns an output plugin client which fails to initialize with err.
+// It is used when the plugin process could not be started.
+func Unavailable(err error) *OutputRPC {
+	return &OutputRPC{err: err}
+}
+
+type config struct {
+	EventProcessors []string                 `mapstructure:"event-processors,omitempty"`
+	NumberFormat    *formatters.NumberFormat `mapstructure:"number-format,omitempty"`
+}
+
+func (g *OutputRPC) String() string {
+	return g.name
+}
+
+func (g *OutputRPC) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	if g.err != nil {
+		return g.err
+	}
+	g.name = name
+	g.logger = log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags)
+	g.cfg = new(config)
+	err := outputs.DecodeConfig(cfg, g.cfg)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return err
+		}
+	}
+	return g.client.Call("Plugin.Init", &InitArgs{Name: name, Cfg: cfg}, &InitResponse{})
+}
+
+// Write sends the message to the plugin.
+// If the output has event processors, the message is converted to events
+// which are processed locally then sent to the plugin using WriteEvent.
+func (g *OutputRPC) Write(ctx context.Context, msg proto.Message, meta outputs.Meta) {
+	if msg == nil {
+		return
+	}
+	if len(g.evps) > 0 {
+		g.writeAsEvents(ctx, msg, meta)
+		return
+	}
+	anyMsg, err := anypb.New(msg)
+	if err != nil {
+		g.logger.Printf("failed to wrap message: %v", err)
+		return
+	}
+	b, err := proto.Marshal(anyMsg)
+	if err != nil {
+		g.logger.Printf("failed to marshal message: %v", err)
+		return
+	}
+	err = g.client.Call("Plugin.Write", &WriteArgs{Msg: b, Meta: meta}, &WriteResponse{})
+	if err != nil {
+		g.logger.Print("RPC client call error: ", err)
+	}
+}
+
+func (g *OutputRPC) writeAsEvents(ctx context.Context, msg proto.Message, meta outputs.Meta) {
+	rsp, ok := msg.(*gnmi.SubscribeResponse)
+	if !ok {
+		g.logger.Printf("event processors cannot be applied to message type %T, dropping it", msg)
+		return
+	}
+	evs, err := formatters.ResponseToEventMsgs(meta["subscription-name"], rsp, meta, g.evpOverrides.Select(meta, g.evps)...)
+	if err != nil {
+		g.logger.Printf("failed to convert message to events: %v", err)
+		return
+	}
+	g.writeEvents(evs)
+}
+
+// WriteEvent applies the output event processors locally
+// before sending the resulting events to the plugin.
+func (g *OutputRPC) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	evs := []*formatters.EventMsg{ev}
+	for _, proc := range g.evps {
+		evs = proc.Apply(evs...)
+	}
+	g.writeEvents(evs)
+}
+
+func (g *OutputRPC) writeEvents(evs []*formatters.EventMsg) {
+	for _, ev := range evs {
+		err := g.client.Call("Plugin.WriteEvent", &WriteEventArgs{Event: ev}, &WriteEventResponse{})
+		if err != nil {
+			g.logger.Print("RPC client call error: ", err)
+		}
+	}
+}
+
+func (g *OutputRPC) Close() error {
+	if g.client == nil {
+		return nil
+	}
+	return g.client.Call("Plugin.Close", new(interface{}), &CloseResponse{})
+}
+
+func (g *OutputRPC) RegisterMetrics(*prometheus.Registry) {}
+
+func (g *OutputRPC) SetLogger(logger *log.Logger) {
+	if logger != nil && g.logger != nil {
+		g.logger.SetOutput(logger.Writer())
+		g.logger.SetFlags(logger.Flags())
+	}
+}
+
+func (g *OutputRPC) SetEventProcessors(ps map[string]map[string]interface{},
+	logger *log.Logger,
+	tcs map[string]*types.TargetConfig,
+	acts map[string]map[string]interface{}) error {
+	nfps, err := g.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	g.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
+	g.evps, err = formatters.MakeEventProcessors(
+		logger,
+		g.cfg.EventProcessors,
+		ps,
+		tcs,
+		acts,
+	)
+	if err != nil {
+		return err
+	}
+	g.evps = append(g.evps, nfps...)
+	return nil
+}
+
+func (g *OutputRPC) SetName(string) {}
+
+func (g *OutputRPC) SetClusterName(string) {}
+
+func (g *OutputRPC) SetTargetsConfig(map[string]*types.TargetConfig) {}
@@ -0,0 +1,191 @@
+package output_plugin
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/rpc"
+	"sync"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+// testOutput is the output implementation running on the plugin side.
+type testOutput struct {
+	m      sync.Mutex
+	name   string
+	msgs   []proto.Message
+	metas  []outputs.Meta
+	events []*formatters.EventMsg
+	closed bool
+}
+
+func (o *testOutput) Init(_ context.Context, name string, _ map[string]interface{}, _ ...outputs.Option) error {
+	o.name = name
+	return nil
+}
+
+func (o *testOutput) Write(_ context.Context, m proto.Message, meta outputs.Meta) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.msgs = append(o.msgs, m)
+	o.metas = append(o.metas, meta)
+}
+
+func (o *testOutput) WriteEvent(_ context.Context, ev *formatters.EventMsg) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.events = append(o.events, ev)
+}
+
+func (o *testOutput) Close() error {
+	o.closed = true
+	return nil
+}
+
+func (o *testOutput) RegisterMetrics(*prometheus.Registry) {}
+func (o *testOutput) String() string                       { return "test" }
+func (o *testOutput) SetLogger(*log.Logger)                {}
+func (o *testOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+func (o *testOutput) SetName(string)                                  {}
+func (o *testOutput) SetClusterName(string)                           {}
+func (o *testOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+
+// addTagProcessor adds a static tag to the events.
+type addTagProcessor struct{}
+
+func (p *addTagProcessor) Init(interface{}, ...formatters.Option) error { return nil }
+func (p *addTagProcessor) Apply(evs ...*formatters.EventMsg) []*formatters.EventMsg {
+	for _, ev := range evs {
+		if ev.Tags == nil {
+			ev.Tags = make(map[string]string)
+		}
+		ev.Tags["processed"] = "true"
+	}
+	return evs
+}
+func (p *addTagProcessor) WithTargets(map[string]*types.TargetConfig)    {}
+func (p *addTagProcessor) WithLogger(*log.Logger)                        {}
+func (p *addTagProcessor) WithActions(map[string]map[string]interface{}) {}
+func (p *addTagProcessor) WithProcessors(map[string]map[string]any)      {}
+
+// newTestOutputRPC connects an OutputRPC to an outputRPCServer
+// wrapping impl over an in-memory connection.
+func newTestOutputRPC(t *testing.T, impl outputs.Output) *OutputRPC {
+	t.Helper()
+	srv := rpc.NewServer()
+	err := srv.RegisterName("Plugin", &outputRPCServer{Impl: impl})
+	if err != nil {
+		t.Fatalf("failed to register RPC server: %v", err)
+	}
+	sc, cc := net.Pipe()
+	go srv.ServeConn(sc)
+	client := rpc.NewClient(cc)
+	t.Cleanup(func() { client.Close() })
+	g := &OutputRPC{client: client}
+	err = g.Init(context.Background(), "out1", map[string]interface{}{"type": "test"})
+	if err != nil {
+		t.Fatalf("failed to init output: %v", err)
+	}
+	return g
+}
+
+func testResponse() *gnmi.SubscribeResponse {
+	return &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{
+				Timestamp: 42,
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "hostname"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "r1"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestOutputRPCWrite(t *testing.T) {
+	impl := &testOutput{}
+	g := newTestOutputRPC(t, impl)
+	if impl.name != "out1" {
+		t.Errorf("plugin output initialized with name %q", impl.name)
+	}
+	meta := outputs.Meta{"source": "r1", "subscription-name": "sub1"}
+	g.Write(context.Background(), testResponse(), meta)
+	if len(impl.msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(impl.msgs))
+	}
+	if !proto.Equal(impl.msgs[0], testResponse()) {
+		t.Errorf("got message %v, expected %v", impl.msgs[0], testResponse())
+	}
+	if impl.metas[0]["source"] != "r1" || impl.metas[0]["subscription-name"] != "sub1" {
+		t.Errorf("unexpected meta %v", impl.metas[0])
+	}
+	if err := g.Close(); err != nil {
+		t.Fatalf("failed to close output: %v", err)
+	}
+	if !impl.closed {
+		t.Errorf("plugin output not closed")
+	}
+}
+
+func TestOutputRPCWriteEvent(t *testing.T) {
+	impl := &testOutput{}
+	g := newTestOutputRPC(t, impl)
+	g.evps = []formatters.EventProcessor{&addTagProcessor{}}
+	g.WriteEvent(context.Background(), &formatters.EventMsg{
+		Name:   "ev1",
+		Values: map[string]interface{}{"value": int64(1)},
+	})
+	if len(impl.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(impl.events))
+	}
+	ev := impl.events[0]
+	if ev.Name != "ev1" || ev.Tags["processed"] != "true" || ev.Values["value"] != int64(1) {
+		t.Errorf("unexpected event %+v", ev)
+	}
+}
+
+func TestOutputRPCWriteWithEventProcessors(t *testing.T) {
+	impl := &testOutput{}
+	g := newTestOutputRPC(t, impl)
+	g.evps = []formatters.EventProcessor{&addTagProcessor{}}
+	g.Write(context.Background(), testResponse(), outputs.Meta{"source": "r1", "subscription-name": "sub1"})
+	if len(impl.msgs) != 0 {
+		t.Errorf("expected the message to be converted to events, got %d messages", len(impl.msgs))
+	}
+	if len(impl.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(impl.events))
+	}
+	ev := impl.events[0]
+	if ev.Name != "sub1" || ev.Tags["source"] != "r1" || ev.Tags["processed"] != "true" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+	if ev.Values["/hostname"] != "r1" {
+		t.Errorf("unexpected event values %v", ev.Values)
+	}
+}
+
+func TestOutputRPCUnavailable(t *testing.T) {
+	perr := errors.New("plugin not started")
+	g := Unavailable(perr)
+	err := g.Init(context.Background(), "out1", nil)
+	if !errors.Is(err, perr) {
+		t.Errorf("got error %v, expected %v", err, perr)
+	}
+	if err = g.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
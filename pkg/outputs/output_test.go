@@ -0,0 +1,91 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// stubOutput records the messages written to it.
+type stubOutput struct {
+	m      sync.Mutex
+	msgs   []proto.Message
+	events []*formatters.EventMsg
+}
+
+func (s *stubOutput) Init(context.Context, string, map[string]interface{}, ...Option) error {
+	return nil
+}
+
+func (s *stubOutput) Write(_ context.Context, m proto.Message, _ Meta) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.msgs = append(s.msgs, m)
+}
+
+func (s *stubOutput) WriteEvent(_ context.Context, ev *formatters.EventMsg) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.events = append(s.events, ev)
+}
+
+func (s *stubOutput) Close() error                         { return nil }
+func (s *stubOutput) RegisterMetrics(*prometheus.Registry) {}
+func (s *stubOutput) String() string                       { return "stub" }
+func (s *stubOutput) SetLogger(*log.Logger)                {}
+func (s *stubOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+func (s *stubOutput) SetName(string)                                  {}
+func (s *stubOutput) SetClusterName(string)                           {}
+func (s *stubOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
+
+type stubHealthOutput struct {
+	stubOutput
+	healthy bool
+}
+
+func (s *stubHealthOutput) Healthy() bool { return s.healthy }
+
+func TestIsHealthy(t *testing.T) {
+	if !IsHealthy(&stubOutput{}) {
+		t.Errorf("outputs without health reporting must be healthy")
+	}
+	o := &stubHealthOutput{healthy: true}
+	if !IsHealthy(o) {
+		t.Errorf("expected a healthy output")
+	}
+	o.healthy = false
+	if IsHealthy(o) {
+		t.Errorf("expected an unhealthy output")
+	}
+	// health is forwarded by the rate limited wrapper
+	rlo, err := NewRateLimitedOutput(o, map[string]interface{}{
+		"rate-limit": map[string]interface{}{"messages-per-second": 10},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to wrap output: %v", err)
+	}
+	if IsHealthy(rlo) {
+		t.Errorf("expected the wrapped output to be unhealthy")
+	}
+	o.healthy = true
+	if !IsHealthy(rlo) {
+		t.Errorf("expected the wrapped output to be healthy")
+	}
+}
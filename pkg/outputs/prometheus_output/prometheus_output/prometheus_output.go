@@ -71,13 +71,15 @@ type prometheusOutput struct {
 	eventChan chan *formatters.EventMsg
 	msgChan   chan *outputs.ProtoMsg
 
-	wg     *sync.WaitGroup
-	server *http.Server
+	wg       *sync.WaitGroup
+	server   *http.Server
+	registry *prometheus.Registry
 	sync.Mutex
 	entries map[uint64]*promcom.PromMetric
 
 	mb           *promcom.MetricBuilder
 	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
 	consulClient *api.Client
 
 	targetTpl *template.Template
@@ -87,25 +89,26 @@ type prometheusOutput struct {
 }
 
 type config struct {
-	Name                   string               `mapstructure:"name,omitempty" json:"name,omitempty"`
-	Listen                 string               `mapstructure:"listen,omitempty" json:"listen,omitempty"`
-	TLS                    *types.TLSConfig     `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	Path                   string               `mapstructure:"path,omitempty" json:"path,omitempty"`
-	Expiration             time.Duration        `mapstructure:"expiration,omitempty" json:"expiration,omitempty"`
-	MetricPrefix           string               `mapstructure:"metric-prefix,omitempty" json:"metric-prefix,omitempty"`
-	AppendSubscriptionName bool                 `mapstructure:"append-subscription-name,omitempty" json:"append-subscription-name,omitempty"`
-	ExportTimestamps       bool                 `mapstructure:"export-timestamps,omitempty" json:"export-timestamps,omitempty"`
-	OverrideTimestamps     bool                 `mapstructure:"override-timestamps,omitempty" json:"override-timestamps,omitempty"`
-	AddTarget              string               `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
-	TargetTemplate         string               `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
-	StringsAsLabels        bool                 `mapstructure:"strings-as-labels,omitempty" json:"strings-as-labels,omitempty"`
-	Debug                  bool                 `mapstructure:"debug,omitempty" json:"debug,omitempty"`
-	EventProcessors        []string             `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
-	ServiceRegistration    *serviceRegistration `mapstructure:"service-registration,omitempty" json:"service-registration,omitempty"`
-	Timeout                time.Duration        `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
-	CacheConfig            *cache.Config        `mapstructure:"cache,omitempty" json:"cache-config,omitempty"`
-	NumWorkers             int                  `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty"`
-	EnableMetrics          bool                 `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+	Name                   string                   `mapstructure:"name,omitempty" json:"name,omitempty"`
+	Listen                 string                   `mapstructure:"listen,omitempty" json:"listen,omitempty" default:":9804"`
+	TLS                    *types.TLSConfig         `mapstructure:"tls,omitempty" json:"tls,omitempty"`
+	Path                   string                   `mapstructure:"path,omitempty" json:"path,omitempty" default:"/metrics"`
+	Expiration             time.Duration            `mapstructure:"expiration,omitempty" json:"expiration,omitempty" default:"1m"`
+	MetricPrefix           string                   `mapstructure:"metric-prefix,omitempty" json:"metric-prefix,omitempty"`
+	AppendSubscriptionName bool                     `mapstructure:"append-subscription-name,omitempty" json:"append-subscription-name,omitempty"`
+	ExportTimestamps       bool                     `mapstructure:"export-timestamps,omitempty" json:"export-timestamps,omitempty"`
+	OverrideTimestamps     bool                     `mapstructure:"override-timestamps,omitempty" json:"override-timestamps,omitempty"`
+	AddTarget              string                   `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
+	TargetTemplate         string                   `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
+	StringsAsLabels        bool                     `mapstructure:"strings-as-labels,omitempty" json:"strings-as-labels,omitempty"`
+	Debug                  bool                     `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+	EventProcessors        []string                 `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat           *formatters.NumberFormat `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
+	ServiceRegistration    *serviceRegistration     `mapstructure:"service-registration,omitempty" json:"service-registration,omitempty"`
+	Timeout                time.Duration            `mapstructure:"timeout,omitempty" json:"timeout,omitempty" default:"10s"`
+	CacheConfig            *cache.Config            `mapstructure:"cache,omitempty" json:"cache-config,omitempty"`
+	NumWorkers             int                      `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty" default:"1"`
+	EnableMetrics          bool                     `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
 
 	clusterName string
 	address     string
@@ -131,7 +134,11 @@ func (p *prometheusOutput) SetEventProcessors(ps map[string]map[string]interface
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := p.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	p.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	p.evps, err = formatters.MakeEventProcessors(
 		logger,
 		p.cfg.EventProcessors,
@@ -142,6 +149,7 @@ func (p *prometheusOutput) SetEventProcessors(ps map[string]map[string]interface
 	if err != nil {
 		return err
 	}
+	p.evps = append(p.evps, nfps...)
 	return nil
 }
 
@@ -195,14 +203,14 @@ func (p *prometheusOutput) Init(ctx context.Context, name string, cfg map[string
 	}
 
 	// create prometheus registry
-	registry := prometheus.NewRegistry()
+	p.registry = prometheus.NewRegistry()
 
-	err = registry.Register(p)
+	err = p.registry.Register(p)
 	if err != nil {
 		return err
 	}
 	// create http server
-	promHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+	promHandler := promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
 
 	mux := http.NewServeMux()
 	mux.Handle(p.cfg.Path, promHandler)
@@ -321,6 +329,14 @@ func (p *prometheusOutput) Close() error {
 	return nil
 }
 
+// Gatherer returns the registry holding the metrics exposed by the output.
+func (p *prometheusOutput) Gatherer() prometheus.Gatherer {
+	if p.registry == nil {
+		return nil
+	}
+	return p.registry
+}
+
 func (p *prometheusOutput) RegisterMetrics(reg *prometheus.Registry) {
 	if !p.cfg.EnableMetrics {
 		return
@@ -392,7 +408,7 @@ func (p *prometheusOutput) workerHandleProto(ctx context.Context, m *outputs.Pro
 			p.targetsMeta.Set(measName+"/"+target, meta, ttlcache.DefaultTTL)
 			return
 		}
-		events, err := formatters.ResponseToEventMsgs(measName, pmsg, meta, p.evps...)
+		events, err := formatters.ResponseToEventMsgs(measName, pmsg, meta, p.evpOverrides.Select(meta, p.evps)...)
 		if err != nil {
 			p.logger.Printf("failed to convert message to event: %v", err)
 			return
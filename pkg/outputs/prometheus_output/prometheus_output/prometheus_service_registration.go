@@ -28,15 +28,15 @@ const (
 )
 
 type serviceRegistration struct {
-	Address    string `mapstructure:"address,omitempty" json:"address,omitempty"`
+	Address    string `mapstructure:"address,omitempty" json:"address,omitempty" default:"localhost:8500"`
 	Datacenter string `mapstructure:"datacenter,omitempty" json:"datacenter,omitempty"`
 	Username   string `mapstructure:"username,omitempty" json:"username,omitempty"`
 	Password   string `mapstructure:"password,omitempty" json:"password,omitempty"`
 	Token      string `mapstructure:"token,omitempty" json:"token,omitempty"`
 
 	Name             string        `mapstructure:"name,omitempty" json:"name,omitempty"`
-	CheckInterval    time.Duration `mapstructure:"check-interval,omitempty" json:"check-interval,omitempty"`
-	MaxFail          int           `mapstructure:"max-fail,omitempty" json:"max-fail,omitempty"`
+	CheckInterval    time.Duration `mapstructure:"check-interval,omitempty" json:"check-interval,omitempty" default:"5s"`
+	MaxFail          int           `mapstructure:"max-fail,omitempty" json:"max-fail,omitempty" default:"3"`
 	Tags             []string      `mapstructure:"tags,omitempty" json:"tags,omitempty"`
 	EnableHTTPCheck  bool          `mapstructure:"enable-http-check,omitempty" json:"enable-http-check,omitempty"`
 	HTTPCheckAddress string        `mapstructure:"http-check-address,omitempty" json:"http-check-address,omitempty"`
@@ -21,8 +21,6 @@ import (
 	gogoproto "github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
-
-	"github.com/openconfig/gnmic/pkg/api/utils"
 )
 
 var (
@@ -36,14 +34,7 @@ func (p *promWriteOutput) createHTTPClient() error {
 		Timeout: p.cfg.Timeout,
 	}
 	if p.cfg.TLS != nil {
-		tlsCfg, err := utils.NewTLSConfig(
-			p.cfg.TLS.CaFile,
-			p.cfg.TLS.CertFile,
-			p.cfg.TLS.KeyFile,
-			"",
-			p.cfg.TLS.SkipVerify,
-			false,
-		)
+		tlsCfg, err := p.cfg.TLS.ClientConfig()
 		if err != nil {
 			return err
 		}
@@ -79,9 +79,10 @@ type promWriteOutput struct {
 	m             *sync.Mutex
 	metadataCache map[string]prompb.MetricMetadata
 
-	evps      []formatters.EventProcessor
-	targetTpl *template.Template
-	cfn       context.CancelFunc
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	targetTpl    *template.Template
+	cfn          context.CancelFunc
 	// TODO:
 	// gnmiCache *cache.GnmiOutputCache
 }
@@ -89,27 +90,28 @@ type promWriteOutput struct {
 type config struct {
 	Name                  string            `mapstructure:"name,omitempty" json:"name,omitempty"`
 	URL                   string            `mapstructure:"url,omitempty" json:"url,omitempty"`
-	Timeout               time.Duration     `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+	Timeout               time.Duration     `mapstructure:"timeout,omitempty" json:"timeout,omitempty" default:"10s"`
 	Headers               map[string]string `mapstructure:"headers,omitempty" json:"headers,omitempty"`
 	Authentication        *auth             `mapstructure:"authentication,omitempty" json:"authentication,omitempty"`
 	Authorization         *authorization    `mapstructure:"authorization,omitempty" json:"authorization,omitempty"`
 	TLS                   *types.TLSConfig  `mapstructure:"tls,omitempty" json:"tls,omitempty"`
-	Interval              time.Duration     `mapstructure:"interval,omitempty" json:"interval,omitempty"`
-	BufferSize            int               `mapstructure:"buffer-size,omitempty" json:"buffer-size,omitempty"`
-	MaxTimeSeriesPerWrite int               `mapstructure:"max-time-series-per-write,omitempty" json:"max-time-series-per-write,omitempty"`
+	Interval              time.Duration     `mapstructure:"interval,omitempty" json:"interval,omitempty" default:"10s"`
+	BufferSize            int               `mapstructure:"buffer-size,omitempty" json:"buffer-size,omitempty" default:"1000"`
+	MaxTimeSeriesPerWrite int               `mapstructure:"max-time-series-per-write,omitempty" json:"max-time-series-per-write,omitempty" default:"500"`
 	MaxRetries            int               `mapstructure:"max-retries,omitempty" json:"max-retries,omitempty"`
 	Metadata              *metadata         `mapstructure:"metadata,omitempty" json:"metadata,omitempty"`
 	Debug                 bool              `mapstructure:"debug,omitempty" json:"debug,omitempty"`
 	//
-	MetricPrefix           string   `mapstructure:"metric-prefix,omitempty" json:"metric-prefix,omitempty"`
-	AppendSubscriptionName bool     `mapstructure:"append-subscription-name,omitempty" json:"append-subscription-name,omitempty"`
-	AddTarget              string   `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
-	TargetTemplate         string   `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
-	StringsAsLabels        bool     `mapstructure:"strings-as-labels,omitempty" json:"strings-as-labels,omitempty"`
-	EventProcessors        []string `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
-	NumWorkers             int      `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty"`
-	NumWriters             int      `mapstructure:"num-writers,omitempty" json:"num-writers,omitempty"`
-	EnableMetrics          bool     `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+	MetricPrefix           string                   `mapstructure:"metric-prefix,omitempty" json:"metric-prefix,omitempty"`
+	AppendSubscriptionName bool                     `mapstructure:"append-subscription-name,omitempty" json:"append-subscription-name,omitempty"`
+	AddTarget              string                   `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
+	TargetTemplate         string                   `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
+	StringsAsLabels        bool                     `mapstructure:"strings-as-labels,omitempty" json:"strings-as-labels,omitempty"`
+	EventProcessors        []string                 `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat           *formatters.NumberFormat `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
+	NumWorkers             int                      `mapstructure:"num-workers,omitempty" json:"num-workers,omitempty" default:"1"`
+	NumWriters             int                      `mapstructure:"num-writers,omitempty" json:"num-writers,omitempty" default:"1"`
+	EnableMetrics          bool                     `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
 }
 
 type auth struct {
@@ -123,9 +125,9 @@ type authorization struct {
 }
 
 type metadata struct {
-	Include            bool          `mapstructure:"include,omitempty" json:"include,omitempty"`
-	Interval           time.Duration `mapstructure:"interval,omitempty" json:"interval,omitempty"`
-	MaxEntriesPerWrite int           `mapstructure:"max-entries-per-write,omitempty" json:"max-entries-per-write,omitempty"`
+	Include            bool          `mapstructure:"include,omitempty" json:"include,omitempty" default:"true"`
+	Interval           time.Duration `mapstructure:"interval,omitempty" json:"interval,omitempty" default:"1m"`
+	MaxEntriesPerWrite int           `mapstructure:"max-entries-per-write,omitempty" json:"max-entries-per-write,omitempty" default:"500"`
 }
 
 func (p *promWriteOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
@@ -262,7 +264,11 @@ func (p *promWriteOutput) SetEventProcessors(ps map[string]map[string]interface{
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := p.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	p.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	p.evps, err = formatters.MakeEventProcessors(
 		logger,
 		p.cfg.EventProcessors,
@@ -273,6 +279,7 @@ func (p *promWriteOutput) SetEventProcessors(ps map[string]map[string]interface{
 	if err != nil {
 		return err
 	}
+	p.evps = append(p.evps, nfps...)
 	return nil
 }
 
@@ -315,7 +322,7 @@ func (p *promWriteOutput) workerHandleProto(ctx context.Context, m *outputs.Prot
 		if err != nil {
 			p.logger.Printf("failed to add target to the response: %v", err)
 		}
-		events, err := formatters.ResponseToEventMsgs(measName, pmsg, meta, p.evps...)
+		events, err := formatters.ResponseToEventMsgs(measName, pmsg, meta, p.evpOverrides.Select(meta, p.evps)...)
 		if err != nil {
 			p.logger.Printf("failed to convert message to event: %v", err)
 			return
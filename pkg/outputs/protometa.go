@@ -15,6 +15,8 @@ import (
 type ProtoMsg struct {
 	m    proto.Message
 	meta Meta
+	// delivery of the write the message comes from, if any.
+	delivery *Delivery
 }
 
 func NewProtoMsg(m proto.Message, meta Meta) *ProtoMsg {
@@ -37,3 +39,17 @@ func (m *ProtoMsg) GetMeta() Meta {
 	}
 	return m.meta
 }
+
+// WithDelivery sets the Delivery the message is accounted to and returns the message.
+func (m *ProtoMsg) WithDelivery(d *Delivery) *ProtoMsg {
+	m.delivery = d
+	return m
+}
+
+// GetDelivery returns the Delivery the message is accounted to, it might be nil.
+func (m *ProtoMsg) GetDelivery() *Delivery {
+	if m == nil {
+		return nil
+	}
+	return m.delivery
+}
@@ -0,0 +1,371 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+const (
+	rateLimitConfigKey = "rate-limit"
+
+	defaultRateLimitQueueSize    = 1000
+	defaultRateLimitBlockTimeout = time.Second
+	rateLimitDropReportInterval  = 10 * time.Second
+	rateLimitFlushCheckInterval  = 10 * time.Millisecond
+
+	overflowDrop  = "drop"
+	overflowBlock = "block"
+)
+
+var (
+	errRateLimitDropped = errors.New("rate-limit queue full")
+	errRateLimitClosed  = errors.New("rate-limit output closed")
+)
+
+// RateLimitConfig defines the write rate shaping applied to an output.
+// A zero rate disables the corresponding limit.
+type RateLimitConfig struct {
+	// maximum number of messages written per second.
+	MessagesPerSecond float64 `mapstructure:"messages-per-second,omitempty" json:"messages-per-second,omitempty"`
+	// maximum number of messages written in a single burst,
+	// defaults to the ceiling of messages-per-second.
+	MessagesBurst int `mapstructure:"messages-burst,omitempty" json:"messages-burst,omitempty"`
+	// maximum number of bytes written per second.
+	BytesPerSecond float64 `mapstructure:"bytes-per-second,omitempty" json:"bytes-per-second,omitempty"`
+	// maximum number of bytes written in a single burst,
+	// defaults to the ceiling of bytes-per-second.
+	BytesBurst int `mapstructure:"bytes-burst,omitempty" json:"bytes-burst,omitempty"`
+	// number of messages (and of events) queued while waiting for the limits.
+	QueueSize int `mapstructure:"queue-size,omitempty" json:"queue-size,omitempty"`
+	// behavior when the queue is full: drop or block.
+	Overflow string `mapstructure:"overflow,omitempty" json:"overflow,omitempty"`
+	// maximum time a write waits for room in a full queue
+	// when overflow is block, the message is dropped after that.
+	BlockTimeout time.Duration `mapstructure:"block-timeout,omitempty" json:"block-timeout,omitempty"`
+}
+
+// rateLimitedOutput wraps an Output and queues the calls to
+// Write and WriteEvent, a single goroutine dequeues them and
+// forwards them to the wrapped output so that the configured rates are not exceeded.
+type rateLimitedOutput struct {
+	Output
+	cfg          *RateLimitConfig
+	logger       *log.Logger
+	msgLimiter   *rate.Limiter
+	bytesLimiter *rate.Limiter
+
+	msgs    chan *ProtoMsg
+	events  chan *queuedEvent
+	dropped atomic.Uint64
+	// number of queued or being written messages and events
+	pending atomic.Int64
+
+	cfn context.CancelFunc
+	wg  sync.WaitGroup
+}
+
+// queuedEvent is an event waiting for the limits
+// and the delivery it is accounted to.
+type queuedEvent struct {
+	ev       *formatters.EventMsg
+	delivery *Delivery
+}
+
+// NewRateLimitedOutput returns the output o wrapped with the write rate limits found
+// under the `rate-limit` key of the output config cfg.
+// If the config does not define any limit, o is returned unchanged.
+func NewRateLimitedOutput(o Output, cfg map[string]interface{}, logger *log.Logger) (Output, error) {
+	rlc, ok := cfg[rateLimitConfigKey]
+	if !ok || rlc == nil {
+		return o, nil
+	}
+	rl := new(RateLimitConfig)
+	err := DecodeConfig(rlc, rl)
+	if err != nil {
+		return nil, err
+	}
+	if rl.MessagesPerSecond < 0 || rl.BytesPerSecond < 0 ||
+		rl.MessagesBurst < 0 || rl.BytesBurst < 0 ||
+		rl.QueueSize < 0 || rl.BlockTimeout < 0 {
+		return nil, errors.New("rate-limit values must be positive")
+	}
+	if rl.MessagesPerSecond == 0 && rl.BytesPerSecond == 0 {
+		return o, nil
+	}
+	switch rl.Overflow {
+	case "":
+		rl.Overflow = overflowDrop
+	case overflowDrop, overflowBlock:
+	default:
+		return nil, fmt.Errorf("unknown rate-limit overflow %q, must be one of %q or %q",
+			rl.Overflow, overflowDrop, overflowBlock)
+	}
+	if rl.QueueSize == 0 {
+		rl.QueueSize = defaultRateLimitQueueSize
+	}
+	if rl.BlockTimeout == 0 {
+		rl.BlockTimeout = defaultRateLimitBlockTimeout
+	}
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	rlo := &rateLimitedOutput{
+		Output: o,
+		cfg:    rl,
+		logger: logger,
+		msgs:   make(chan *ProtoMsg, rl.QueueSize),
+		events: make(chan *queuedEvent, rl.QueueSize),
+	}
+	if rl.MessagesPerSecond > 0 {
+		rlo.msgLimiter = rate.NewLimiter(rate.Limit(rl.MessagesPerSecond), burst(rl.MessagesBurst, rl.MessagesPerSecond))
+	}
+	if rl.BytesPerSecond > 0 {
+		rlo.bytesLimiter = rate.NewLimiter(rate.Limit(rl.BytesPerSecond), burst(rl.BytesBurst, rl.BytesPerSecond))
+	}
+	return rlo, nil
+}
+
+func burst(b int, r float64) int {
+	if b > 0 {
+		return b
+	}
+	if r < 1 {
+		return 1
+	}
+	return int(r + 0.999999)
+}
+
+// Init initializes the wrapped output then starts
+// the goroutine forwarding the queued messages to it.
+func (r *rateLimitedOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...Option) error {
+	err := r.Output.Init(ctx, name, cfg, opts...)
+	if err != nil {
+		return err
+	}
+	ctx, r.cfn = context.WithCancel(ctx)
+	r.wg.Add(1)
+	go r.start(ctx, name)
+	return nil
+}
+
+// Close stops the queue goroutine and closes the wrapped output.
+// Messages and events still queued are discarded,
+// their deliveries fail.
+func (r *rateLimitedOutput) Close() error {
+	if r.cfn != nil {
+		r.cfn()
+	}
+	r.wg.Wait()
+	r.discard(errRateLimitClosed)
+	return r.Output.Close()
+}
+
+// discard empties the queues, failing the deliveries
+// of the discarded messages and events with err.
+func (r *rateLimitedOutput) discard(err error) {
+	for {
+		select {
+		case m := <-r.msgs:
+			r.pending.Add(-1)
+			m.GetDelivery().Done(err)
+		case qe := <-r.events:
+			r.pending.Add(-1)
+			qe.delivery.Done(err)
+		default:
+			return
+		}
+	}
+}
+
+func (r *rateLimitedOutput) Write(ctx context.Context, rsp proto.Message, meta Meta) {
+	if rsp == nil {
+		return
+	}
+	d := DeliveryFrom(ctx)
+	m := NewProtoMsg(rsp, meta).WithDelivery(d)
+	r.pending.Add(1)
+	d.Add(1)
+	select {
+	case r.msgs <- m:
+		return
+	default:
+	}
+	if r.cfg.Overflow == overflowBlock && enqueue(ctx, r.msgs, m, r.cfg.BlockTimeout) {
+		return
+	}
+	r.pending.Add(-1)
+	r.dropped.Add(1)
+	d.Done(errRateLimitDropped)
+}
+
+func (r *rateLimitedOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	if ev == nil {
+		return
+	}
+	d := DeliveryFrom(ctx)
+	qe := &queuedEvent{ev: ev, delivery: d}
+	r.pending.Add(1)
+	d.Add(1)
+	select {
+	case r.events <- qe:
+		return
+	default:
+	}
+	if r.cfg.Overflow == overflowBlock && enqueue(ctx, r.events, qe, r.cfg.BlockTimeout) {
+		return
+	}
+	r.pending.Add(-1)
+	r.dropped.Add(1)
+	d.Done(errRateLimitDropped)
+}
+
+// enqueue waits up to timeout for room in the queue q.
+func enqueue[T any](ctx context.Context, q chan<- T, v T, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case q <- v:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Healthy forwards the health status of the wrapped output.
+func (r *rateLimitedOutput) Healthy() bool {
+	return IsHealthy(r.Output)
+}
+
+// Gatherer returns the prometheus gatherer of the wrapped output.
+func (r *rateLimitedOutput) Gatherer() prometheus.Gatherer {
+	return GathererOf(r.Output)
+}
+
+// Drain returns the messages buffered by the wrapped output,
+// if it implements Drainer, followed by the queued messages.
+// Queued events are not returned.
+func (r *rateLimitedOutput) Drain() []*ProtoMsg {
+	msgs := make([]*ProtoMsg, 0, len(r.msgs))
+	if d, ok := r.Output.(Drainer); ok {
+		msgs = append(msgs, d.Drain()...)
+	}
+DRAIN:
+	for {
+		select {
+		case m := <-r.msgs:
+			r.pending.Add(-1)
+			msgs = append(msgs, m)
+		default:
+			break DRAIN
+		}
+	}
+	return msgs
+}
+
+// Flush waits for the queued messages and events to be written
+// to the wrapped output, then flushes it.
+func (r *rateLimitedOutput) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(rateLimitFlushCheckInterval)
+	defer ticker.Stop()
+	for r.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return Flush(ctx, r.Output)
+}
+
+// start forwards the queued messages and events to the wrapped output,
+// waiting for the limiters before each write.
+func (r *rateLimitedOutput) start(ctx context.Context, name string) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(rateLimitDropReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := r.dropped.Swap(0); n > 0 {
+				r.logger.Printf("output %q rate-limit queue full: dropped %d messages in the last %s",
+					name, n, rateLimitDropReportInterval)
+			}
+		case m := <-r.msgs:
+			err := r.wait(ctx, func() int { return proto.Size(m.GetMsg()) })
+			if err != nil {
+				r.pending.Add(-1)
+				m.GetDelivery().Done(err)
+				return
+			}
+			r.Output.Write(WithDelivery(ctx, m.GetDelivery()), m.GetMsg(), m.GetMeta())
+			r.pending.Add(-1)
+			m.GetDelivery().Done(nil)
+		case qe := <-r.events:
+			err := r.wait(ctx, func() int {
+				b, err := json.Marshal(qe.ev)
+				if err != nil {
+					return 0
+				}
+				return len(b)
+			})
+			if err != nil {
+				r.pending.Add(-1)
+				qe.delivery.Done(err)
+				return
+			}
+			r.Output.WriteEvent(WithDelivery(ctx, qe.delivery), qe.ev)
+			r.pending.Add(-1)
+			qe.delivery.Done(nil)
+		}
+	}
+}
+
+// wait blocks until both limiters allow the message to be written.
+// The message size is only computed if a bytes limit is configured.
+// It returns an error if the context is done before that.
+func (r *rateLimitedOutput) wait(ctx context.Context, size func() int) error {
+	if r.msgLimiter != nil {
+		if err := r.msgLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if r.bytesLimiter != nil {
+		n := size()
+		// a single message bigger than the burst size
+		// consumes the whole burst.
+		if n > r.bytesLimiter.Burst() {
+			n = r.bytesLimiter.Burst()
+		}
+		if n > 0 {
+			if err := r.bytesLimiter.WaitN(ctx, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestNewRateLimitedOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]interface{}
+		wrapped bool
+		wantErr bool
+	}{
+		{
+			name: "no_rate_limit",
+			cfg:  map[string]interface{}{},
+		},
+		{
+			name: "zero_rates",
+			cfg: map[string]interface{}{
+				"rate-limit": map[string]interface{}{"messages-burst": 10},
+			},
+		},
+		{
+			name: "messages_rate",
+			cfg: map[string]interface{}{
+				"rate-limit": map[string]interface{}{"messages-per-second": 10},
+			},
+			wrapped: true,
+		},
+		{
+			name: "bytes_rate_block",
+			cfg: map[string]interface{}{
+				"rate-limit": map[string]interface{}{
+					"bytes-per-second": 1000,
+					"overflow":         "block",
+					"block-timeout":    "100ms",
+				},
+			},
+			wrapped: true,
+		},
+		{
+			name: "negative_rate",
+			cfg: map[string]interface{}{
+				"rate-limit": map[string]interface{}{"messages-per-second": -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown_overflow",
+			cfg: map[string]interface{}{
+				"rate-limit": map[string]interface{}{
+					"messages-per-second": 1,
+					"overflow":            "retry",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &stubOutput{}
+			got, err := NewRateLimitedOutput(o, tt.cfg, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, ok := got.(*rateLimitedOutput)
+			if ok != tt.wrapped {
+				t.Errorf("wrapped=%v, expected %v", ok, tt.wrapped)
+			}
+		})
+	}
+}
+
+func TestBurst(t *testing.T) {
+	tests := []struct {
+		b    int
+		r    float64
+		want int
+	}{
+		{b: 5, r: 100, want: 5},
+		{b: 0, r: 100, want: 100},
+		{b: 0, r: 2.5, want: 3},
+		{b: 0, r: 0.5, want: 1},
+	}
+	for _, tt := range tests {
+		if got := burst(tt.b, tt.r); got != tt.want {
+			t.Errorf("burst(%d, %v)=%d, expected %d", tt.b, tt.r, got, tt.want)
+		}
+	}
+}
+
+func newTestRateLimitedOutput(t *testing.T, rl map[string]interface{}) (*rateLimitedOutput, *stubOutput) {
+	t.Helper()
+	o := &stubOutput{}
+	out, err := NewRateLimitedOutput(o, map[string]interface{}{"rate-limit": rl}, nil)
+	if err != nil {
+		t.Fatalf("failed to wrap output: %v", err)
+	}
+	rlo := out.(*rateLimitedOutput)
+	if err = rlo.Init(context.Background(), "test", nil); err != nil {
+		t.Fatalf("failed to init output: %v", err)
+	}
+	t.Cleanup(func() { rlo.Close() })
+	return rlo, o
+}
+
+func (s *stubOutput) counts() (int, int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return len(s.msgs), len(s.events)
+}
+
+func TestRateLimitedOutputWait(t *testing.T) {
+	rlo, o := newTestRateLimitedOutput(t, map[string]interface{}{
+		"messages-per-second": 20,
+		"messages-burst":      2,
+	})
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		rlo.Write(context.Background(), &gnmi.SubscribeResponse{}, nil)
+	}
+	// the write calls must not block on the limiter.
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("writes blocked for %s", d)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		n, _ := o.counts()
+		if n == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 4 written messages, got %d", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// 2 messages in the burst, the other 2 at 20 msg/s.
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("messages written after %s, expected at least 100ms", d)
+	}
+}
+
+func TestRateLimitedOutputOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		rl       map[string]interface{}
+		minDelay time.Duration
+	}{
+		{
+			name: "drop",
+			rl: map[string]interface{}{
+				"messages-per-second": 0.1,
+				"queue-size":          1,
+			},
+		},
+		{
+			name: "block",
+			rl: map[string]interface{}{
+				"messages-per-second": 0.1,
+				"queue-size":          1,
+				"overflow":            "block",
+				"block-timeout":       "50ms",
+			},
+			minDelay: 50 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rlo, o := newTestRateLimitedOutput(t, tt.rl)
+			ev := &formatters.EventMsg{Name: "ev"}
+			// the first event consumes the burst, the second one
+			// waits for the limiter and the third one fills the queue.
+			rlo.WriteEvent(context.Background(), ev)
+			deadline := time.Now().Add(time.Second)
+			for {
+				if _, n := o.counts(); n == 1 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("first event not written")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			rlo.WriteEvent(context.Background(), ev)
+			time.Sleep(10 * time.Millisecond)
+			rlo.WriteEvent(context.Background(), ev)
+			start := time.Now()
+			rlo.WriteEvent(context.Background(), ev)
+			d := time.Since(start)
+			if d < tt.minDelay {
+				t.Errorf("write returned after %s, expected at least %s", d, tt.minDelay)
+			}
+			if d > tt.minDelay+500*time.Millisecond {
+				t.Errorf("write blocked for %s", d)
+			}
+			if n := rlo.dropped.Load(); n != 1 {
+				t.Errorf("expected 1 dropped event, got %d", n)
+			}
+		})
+	}
+}
+
+func TestRateLimitedOutputDrain(t *testing.T) {
+	rlo, o := newTestRateLimitedOutput(t, map[string]interface{}{
+		"messages-per-second": 0.1,
+	})
+	for i := 0; i < 3; i++ {
+		rlo.Write(context.Background(), &gnmi.SubscribeResponse{}, nil)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if n, _ := o.counts(); n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("first message not written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// the worker is waiting for the limiter with the second message,
+	// only the third one is still queued.
+	time.Sleep(10 * time.Millisecond)
+	if msgs := rlo.Drain(); len(msgs) != 1 {
+		t.Errorf("expected 1 drained message, got %d", len(msgs))
+	}
+}
+
+func TestRateLimitedOutputFlush(t *testing.T) {
+	rlo, o := newTestRateLimitedOutput(t, map[string]interface{}{
+		"messages-per-second": 50,
+		"messages-burst":      1,
+	})
+	for i := 0; i < 5; i++ {
+		rlo.WriteEvent(context.Background(), &formatters.EventMsg{Name: "ev"})
+	}
+	err := Flush(context.Background(), rlo)
+	if err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+	if _, n := o.counts(); n != 5 {
+		t.Errorf("expected 5 written events after flush, got %d", n)
+	}
+	rlo.WriteEvent(context.Background(), &formatters.EventMsg{Name: "ev"})
+	rlo.WriteEvent(context.Background(), &formatters.EventMsg{Name: "ev"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err = Flush(ctx, rlo); err == nil {
+		t.Errorf("expected the flush to time out")
+	}
+}
+
+func TestRateLimitedOutputClose(t *testing.T) {
+	o := &stubOutput{}
+	out, err := NewRateLimitedOutput(o, map[string]interface{}{
+		"rate-limit": map[string]interface{}{"messages-per-second": 0.1},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to wrap output: %v", err)
+	}
+	rlo := out.(*rateLimitedOutput)
+	if err = rlo.Init(context.Background(), "test", nil); err != nil {
+		t.Fatalf("failed to init output: %v", err)
+	}
+	d := NewDelivery()
+	ctx := WithDelivery(context.Background(), d)
+	for i := 0; i < 3; i++ {
+		rlo.Write(ctx, &gnmi.SubscribeResponse{}, nil)
+	}
+	rlo.WriteEvent(ctx, &formatters.EventMsg{Name: "ev"})
+	deadline := time.Now().Add(time.Second)
+	for {
+		if n, _ := o.counts(); n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("first message not written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// the second message is waiting for the limiter,
+	// the third one and the event are still queued.
+	time.Sleep(10 * time.Millisecond)
+	if err = rlo.Close(); err != nil {
+		t.Fatalf("failed to close output: %v", err)
+	}
+	if n := rlo.pending.Load(); n != 0 {
+		t.Errorf("expected no pending messages after close, got %d", n)
+	}
+	wctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = d.Wait(wctx)
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the discarded messages deliveries to fail, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/path"
+	"github.com/openconfig/gnmic/pkg/gtemplate"
+)
+
+const (
+	rewriteConfigKey = "rewrite"
+
+	addTargetOverwrite    = "overwrite"
+	addTargetIfNotPresent = "if-not-present"
+
+	prefixModePrepend = "prepend"
+	prefixModeReplace = "replace"
+)
+
+// RewriteConfig defines the changes applied to the notifications
+// of the messages written to an output, before the output itself handles them.
+type RewriteConfig struct {
+	// one of overwrite or if-not-present,
+	// sets the notifications prefix target using target-template.
+	AddTarget string `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
+	// template used to build the target, defaults to DefaultTargetTemplate.
+	TargetTemplate string `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
+	// replaces the notifications prefix origin.
+	Origin string `mapstructure:"origin,omitempty" json:"origin,omitempty"`
+	// gNMI path added to the notifications prefix.
+	Prefix string `mapstructure:"prefix,omitempty" json:"prefix,omitempty"`
+	// one of prepend or replace, defaults to prepend.
+	PrefixMode string `mapstructure:"prefix-mode,omitempty" json:"prefix-mode,omitempty"`
+}
+
+// rewriteOutput wraps an Output and rewrites the target, origin
+// and prefix of the written notifications before forwarding them.
+type rewriteOutput struct {
+	Output
+	cfg       *RewriteConfig
+	targetTpl *template.Template
+	prefix    *gnmi.Path
+	logger    *log.Logger
+}
+
+// NewRewriteOutput returns the output o wrapped with the rewrite rules found
+// under the `rewrite` key of the output config cfg.
+// If the config does not define any rule, o is returned unchanged.
+func NewRewriteOutput(o Output, cfg map[string]interface{}, logger *log.Logger) (Output, error) {
+	rwc, ok := cfg[rewriteConfigKey]
+	if !ok || rwc == nil {
+		return o, nil
+	}
+	rw := new(RewriteConfig)
+	err := DecodeConfig(rwc, rw)
+	if err != nil {
+		return nil, err
+	}
+	if rw.AddTarget == "" && rw.Origin == "" && rw.Prefix == "" {
+		return o, nil
+	}
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	rwo := &rewriteOutput{
+		Output: o,
+		cfg:    rw,
+		logger: logger,
+	}
+	switch rw.AddTarget {
+	case "":
+	case addTargetOverwrite, addTargetIfNotPresent:
+		if rw.TargetTemplate == "" {
+			rwo.targetTpl = DefaultTargetTemplate
+			break
+		}
+		rwo.targetTpl, err = gtemplate.CreateTemplate("rewrite-target-template", rw.TargetTemplate)
+		if err != nil {
+			return nil, err
+		}
+		rwo.targetTpl = rwo.targetTpl.Funcs(TemplateFuncs)
+	default:
+		return nil, fmt.Errorf("unknown rewrite add-target %q, must be one of %q or %q",
+			rw.AddTarget, addTargetOverwrite, addTargetIfNotPresent)
+	}
+	switch rw.PrefixMode {
+	case "":
+		rw.PrefixMode = prefixModePrepend
+	case prefixModePrepend, prefixModeReplace:
+	default:
+		return nil, fmt.Errorf("unknown rewrite prefix-mode %q, must be one of %q or %q",
+			rw.PrefixMode, prefixModePrepend, prefixModeReplace)
+	}
+	if rw.Prefix != "" {
+		rwo.prefix, err = path.ParsePath(rw.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite prefix %q: %w", rw.Prefix, err)
+		}
+	}
+	return rwo, nil
+}
+
+func (r *rewriteOutput) Write(ctx context.Context, rsp proto.Message, meta Meta) {
+	if rsp == nil {
+		return
+	}
+	nrsp, err := r.rewrite(rsp, meta)
+	if err != nil {
+		r.logger.Printf("failed to rewrite message: %v", err)
+		return
+	}
+	r.Output.Write(ctx, nrsp, meta)
+}
+
+// rewrite returns a copy of the message rsp with the rewrite rules applied.
+// Messages other than subscribe response updates are returned unchanged.
+func (r *rewriteOutput) rewrite(rsp proto.Message, meta Meta) (proto.Message, error) {
+	sr, ok := rsp.(*gnmi.SubscribeResponse)
+	if !ok || sr.GetUpdate() == nil {
+		return rsp, nil
+	}
+	sr = proto.Clone(sr).(*gnmi.SubscribeResponse)
+	notif := sr.GetUpdate()
+	if notif.Prefix == nil {
+		notif.Prefix = new(gnmi.Path)
+	}
+	if r.targetTpl != nil &&
+		(r.cfg.AddTarget == addTargetOverwrite || notif.Prefix.Target == "") {
+		sb := new(strings.Builder)
+		err := r.targetTpl.Execute(sb, meta)
+		if err != nil {
+			return nil, err
+		}
+		notif.Prefix.Target = sb.String()
+	}
+	if r.prefix != nil {
+		elems := make([]*gnmi.PathElem, 0, len(r.prefix.GetElem())+len(notif.Prefix.GetElem()))
+		for _, pe := range r.prefix.GetElem() {
+			elems = append(elems, proto.Clone(pe).(*gnmi.PathElem))
+		}
+		if r.cfg.PrefixMode == prefixModePrepend {
+			elems = append(elems, notif.Prefix.GetElem()...)
+		}
+		notif.Prefix.Elem = elems
+	}
+	if r.cfg.Origin != "" {
+		notif.Prefix.Origin = r.cfg.Origin
+	}
+	return sr, nil
+}
+
+// Healthy forwards the health status of the wrapped output.
+func (r *rewriteOutput) Healthy() bool {
+	return IsHealthy(r.Output)
+}
+
+// Gatherer returns the prometheus gatherer of the wrapped output.
+func (r *rewriteOutput) Gatherer() prometheus.Gatherer {
+	return GathererOf(r.Output)
+}
+
+// Drain returns the messages buffered by the wrapped output, if it implements Drainer.
+func (r *rewriteOutput) Drain() []*ProtoMsg {
+	if d, ok := r.Output.(Drainer); ok {
+		return d.Drain()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped output.
+func (r *rewriteOutput) Flush(ctx context.Context) error {
+	return Flush(ctx, r.Output)
+}
+
+// WrapOutput wraps the output o with the generic layers configured
+// in the output config cfg: the binary values policy, the write rate limits, the rewrite rules,
+// the hold-until-sync buffering, so that messages are held before
+// being rewritten and queued, then the fault injection.
+func WrapOutput(o Output, cfg map[string]interface{}, logger *log.Logger) (Output, error) {
+	o, err := NewBinaryValuesOutput(o, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	o, err = NewRateLimitedOutput(o, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	o, err = NewRewriteOutput(o, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	o, err = NewSyncHoldOutput(o, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return NewFaultInjectionOutput(o, cfg, logger)
+}
@@ -0,0 +1,134 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewRewriteOutput(t *testing.T) {
+	o := &stubOutput{}
+	out, err := NewRewriteOutput(o, map[string]interface{}{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != o {
+		t.Errorf("expected the output to be returned unchanged without rewrite rules")
+	}
+	for _, rw := range []map[string]interface{}{
+		{"add-target": "always"},
+		{"prefix": "/a", "prefix-mode": "append"},
+		{"prefix": "/a[b="},
+	} {
+		_, err = NewRewriteOutput(o, map[string]interface{}{"rewrite": rw}, nil)
+		if err == nil {
+			t.Errorf("expected an error for rewrite config %v", rw)
+		}
+	}
+}
+
+func TestRewriteOutputWrite(t *testing.T) {
+	newRsp := func(prefix *gnmi.Path) *gnmi.SubscribeResponse {
+		return &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: &gnmi.Notification{
+					Prefix: prefix,
+					Update: []*gnmi.Update{{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "c"}}}}},
+				},
+			},
+		}
+	}
+	meta := Meta{"source": "router1:57400"}
+	tests := []struct {
+		name   string
+		cfg    map[string]interface{}
+		in     *gnmi.Path
+		prefix *gnmi.Path
+	}{
+		{
+			name: "add_target_if_not_present",
+			cfg:  map[string]interface{}{"add-target": "if-not-present"},
+			in:   &gnmi.Path{Target: "t1"},
+			prefix: &gnmi.Path{
+				Target: "t1",
+			},
+		},
+		{
+			name: "add_target_overwrite",
+			cfg:  map[string]interface{}{"add-target": "overwrite"},
+			in:   &gnmi.Path{Target: "t1"},
+			prefix: &gnmi.Path{
+				Target: "router1",
+			},
+		},
+		{
+			name: "add_target_template",
+			cfg: map[string]interface{}{
+				"add-target":      "overwrite",
+				"target-template": `{{ index . "source" }}`,
+			},
+			prefix: &gnmi.Path{
+				Target: "router1:57400",
+			},
+		},
+		{
+			name: "origin_and_prefix_prepend",
+			cfg: map[string]interface{}{
+				"origin": "openconfig",
+				"prefix": "/a[k=v]",
+			},
+			in: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "b"}}},
+			prefix: &gnmi.Path{
+				Origin: "openconfig",
+				Elem: []*gnmi.PathElem{
+					{Name: "a", Key: map[string]string{"k": "v"}},
+					{Name: "b"},
+				},
+			},
+		},
+		{
+			name: "prefix_replace",
+			cfg: map[string]interface{}{
+				"prefix":      "/a",
+				"prefix-mode": "replace",
+			},
+			in: &gnmi.Path{Target: "t1", Elem: []*gnmi.PathElem{{Name: "b"}}},
+			prefix: &gnmi.Path{
+				Target: "t1",
+				Elem:   []*gnmi.PathElem{{Name: "a"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &stubOutput{}
+			out, err := NewRewriteOutput(o, map[string]interface{}{"rewrite": tt.cfg}, nil)
+			if err != nil {
+				t.Fatalf("failed to wrap output: %v", err)
+			}
+			in := newRsp(tt.in)
+			orig := proto.Clone(in)
+			out.Write(context.Background(), in, meta)
+			if len(o.msgs) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(o.msgs))
+			}
+			got := o.msgs[0].(*gnmi.SubscribeResponse).GetUpdate().GetPrefix()
+			if !proto.Equal(got, tt.prefix) {
+				t.Errorf("got prefix %v, want %v", got, tt.prefix)
+			}
+			if !proto.Equal(in, orig) {
+				t.Errorf("the written message was modified")
+			}
+		})
+	}
+}
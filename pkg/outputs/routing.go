@@ -0,0 +1,59 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const maxKafkaTopicLength = 249
+
+// ValidateKafkaTopic checks that topic is a legal Kafka topic name:
+// at most 249 characters from [a-zA-Z0-9._-], other than "." and "..".
+func ValidateKafkaTopic(topic string) error {
+	switch topic {
+	case "":
+		return errors.New("empty topic name")
+	case ".", "..":
+		return fmt.Errorf("topic name %q is not allowed", topic)
+	}
+	if len(topic) > maxKafkaTopicLength {
+		return fmt.Errorf("topic name %q is longer than %d characters", topic, maxKafkaTopicLength)
+	}
+	for _, c := range topic {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return fmt.Errorf("topic name %q contains an illegal character %q", topic, c)
+		}
+	}
+	return nil
+}
+
+// ValidateNATSSubject checks that subject is a valid NATS subject to publish to:
+// non empty '.' separated tokens, without whitespaces nor wildcards.
+func ValidateNATSSubject(subject string) error {
+	if subject == "" {
+		return errors.New("empty subject name")
+	}
+	if strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("subject %q contains a whitespace", subject)
+	}
+	for _, tok := range strings.Split(subject, ".") {
+		switch tok {
+		case "":
+			return fmt.Errorf("subject %q contains an empty token", subject)
+		case "*", ">":
+			return fmt.Errorf("subject %q contains a wildcard", subject)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateKafkaTopic(t *testing.T) {
+	tests := map[string]bool{
+		"telemetry":                       true,
+		"gnmic_sub1_router1_57400":        true,
+		"a.b-c_D9":                        true,
+		"":                                false,
+		".":                               false,
+		"..":                              false,
+		"gnmic_sub1_[2001:db8::1]_57400":  false,
+		"gnmic sub1":                      false,
+		"gnmic/sub1":                      false,
+		strings.Repeat("t", 249):          true,
+		strings.Repeat("t", 250):          false,
+		"gnmic_sub1_routeré_57400":        false,
+		"gnmic_sub1_router1.example.com_": true,
+	}
+	for topic, valid := range tests {
+		err := ValidateKafkaTopic(topic)
+		if (err == nil) != valid {
+			t.Errorf("topic %q: expected valid=%v, got err=%v", topic, valid, err)
+		}
+	}
+}
+
+func TestValidateNATSSubject(t *testing.T) {
+	tests := map[string]bool{
+		"telemetry":                  true,
+		"gnmic.router1:57400.sub1":   true,
+		"stream.sub1.[2001:db8::1]":  true,
+		"":                           false,
+		"gnmic..sub1":                false,
+		"gnmic.sub1.":                false,
+		".gnmic":                     false,
+		"gnmic.router 1.sub1":        false,
+		"gnmic.*.sub1":               false,
+		"gnmic.>":                    false,
+		"gnmic.router1\t57400.sub1":  false,
+		"gnmic.interface[name=e1].x": true,
+	}
+	for subject, valid := range tests {
+		err := ValidateNATSSubject(subject)
+		if (err == nil) != valid {
+			t.Errorf("subject %q: expected valid=%v, got err=%v", subject, valid, err)
+		}
+	}
+}
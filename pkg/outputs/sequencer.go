@@ -0,0 +1,116 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+// Sequencer orders the delivery of the messages of targets with
+// ordered delivery enabled when they are processed by concurrent workers.
+//
+// A message is added to the sequencer when it is queued by the output Write method,
+// a worker processes it concurrently with the other workers then waits for its turn
+// before delivering it, and marks it as done once delivered or dropped.
+// The messages of a source are delivered in the order they were added.
+// Messages without a sequence number in their metadata are not ordered.
+type Sequencer struct {
+	m *sync.Mutex
+	c *sync.Cond
+	// pending sequence numbers per source, in the order they were added.
+	pending map[string][]uint64
+}
+
+func NewSequencer() *Sequencer {
+	m := new(sync.Mutex)
+	return &Sequencer{
+		m:       m,
+		c:       sync.NewCond(m),
+		pending: make(map[string][]uint64),
+	}
+}
+
+// Add registers the message with metadata meta as pending.
+// It must be called before the message is queued to the workers.
+func (s *Sequencer) Add(meta Meta) {
+	source, seq, ok := sequenceOf(meta)
+	if !ok {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.pending[source] = append(s.pending[source], seq)
+}
+
+// Wait blocks until all the messages of the same source
+// added before the message with metadata meta are done,
+// or until ctx is done.
+func (s *Sequencer) Wait(ctx context.Context, meta Meta) {
+	source, seq, ok := sequenceOf(meta)
+	if !ok {
+		return
+	}
+	stop := context.AfterFunc(ctx, func() {
+		s.m.Lock()
+		defer s.m.Unlock()
+		s.c.Broadcast()
+	})
+	defer stop()
+	s.m.Lock()
+	defer s.m.Unlock()
+	for ctx.Err() == nil {
+		p := s.pending[source]
+		if len(p) == 0 || p[0] == seq {
+			return
+		}
+		s.c.Wait()
+	}
+}
+
+// Done removes the message with metadata meta from the pending messages,
+// releasing the next message of the same source.
+// It must be called for every added message, whether it was delivered or dropped.
+func (s *Sequencer) Done(meta Meta) {
+	source, seq, ok := sequenceOf(meta)
+	if !ok {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	p := s.pending[source]
+	for i, ps := range p {
+		if ps != seq {
+			continue
+		}
+		p = append(p[:i], p[i+1:]...)
+		break
+	}
+	if len(p) == 0 {
+		delete(s.pending, source)
+	} else {
+		s.pending[source] = p
+	}
+	s.c.Broadcast()
+}
+
+func sequenceOf(meta Meta) (string, uint64, bool) {
+	v, ok := meta[formatters.MetaSequenceNumber]
+	if !ok {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return meta["source"], seq, true
+}
@@ -0,0 +1,112 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestSequencer(t *testing.T) {
+	s := NewSequencer()
+	ctx := context.Background()
+	const numMsgs = 100
+	const numWorkers = 8
+	msgs := make(chan Meta, numMsgs)
+	for i := 1; i <= numMsgs; i++ {
+		m := Meta{"source": "router1", formatters.MetaSequenceNumber: strconv.Itoa(i)}
+		s.Add(m)
+		msgs <- m
+	}
+	close(msgs)
+
+	mu := new(sync.Mutex)
+	delivered := make([]string, 0, numMsgs)
+	wg := new(sync.WaitGroup)
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for m := range msgs {
+				// processing time
+				time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+				// drop some messages
+				if m[formatters.MetaSequenceNumber] == "42" {
+					s.Done(m)
+					continue
+				}
+				s.Wait(ctx, m)
+				mu.Lock()
+				delivered = append(delivered, m[formatters.MetaSequenceNumber])
+				mu.Unlock()
+				s.Done(m)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(delivered) != numMsgs-1 {
+		t.Fatalf("expected %d delivered messages, got %d", numMsgs-1, len(delivered))
+	}
+	prev := 0
+	for _, d := range delivered {
+		n, _ := strconv.Atoi(d)
+		if n <= prev {
+			t.Fatalf("message %d delivered after message %d", n, prev)
+		}
+		prev = n
+	}
+	if len(s.pending) != 0 {
+		t.Errorf("unexpected pending messages: %v", s.pending)
+	}
+}
+
+func TestSequencerUnordered(t *testing.T) {
+	s := NewSequencer()
+	m := Meta{"source": "router1"}
+	s.Add(m)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Wait(context.Background(), m)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a message without sequence number is waiting")
+	}
+	if len(s.pending) != 0 {
+		t.Errorf("unexpected pending messages: %v", s.pending)
+	}
+}
+
+func TestSequencerWaitCanceled(t *testing.T) {
+	s := NewSequencer()
+	first := Meta{"source": "router1", formatters.MetaSequenceNumber: "1"}
+	second := Meta{"source": "router1", formatters.MetaSequenceNumber: "2"}
+	s.Add(first)
+	s.Add(second)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Wait(ctx, second)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after its context was canceled")
+	}
+}
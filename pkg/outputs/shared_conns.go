@@ -0,0 +1,91 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// SharedConns holds the connections shared by the outputs writing to the same server.
+// A connection is opened by its first user and closed when its last user releases it.
+type SharedConns[C any] struct {
+	m     *sync.Mutex
+	conns map[string]*sharedConn[C]
+	close func(C)
+}
+
+type sharedConn[C any] struct {
+	conn C
+	refs int
+}
+
+// NewSharedConns returns an empty SharedConns, close is called
+// on the connections without users.
+func NewSharedConns[C any](close func(C)) *SharedConns[C] {
+	return &SharedConns[C]{
+		m:     new(sync.Mutex),
+		conns: make(map[string]*sharedConn[C]),
+		close: close,
+	}
+}
+
+// Acquire returns the connection identified by key, opening it with open if it does not exist,
+// and the function releasing it. The release function can be called more than once.
+func (s *SharedConns[C]) Acquire(key string, open func() (C, error)) (C, func(), error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	sc, ok := s.conns[key]
+	if !ok {
+		conn, err := open()
+		if err != nil {
+			var zero C
+			return zero, nil, err
+		}
+		sc = &sharedConn[C]{conn: conn}
+		s.conns[key] = sc
+	}
+	sc.refs++
+	once := new(sync.Once)
+	return sc.conn, func() { once.Do(func() { s.release(key, sc) }) }, nil
+}
+
+func (s *SharedConns[C]) release(key string, sc *sharedConn[C]) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	sc.refs--
+	if sc.refs > 0 {
+		return
+	}
+	if s.conns[key] == sc {
+		delete(s.conns, key)
+	}
+	s.close(sc.conn)
+}
+
+// Refs returns the number of users of the connection identified by key.
+func (s *SharedConns[C]) Refs(key string) int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if sc, ok := s.conns[key]; ok {
+		return sc.refs
+	}
+	return 0
+}
+
+// ConnKey returns a key identifying a connection from the settings v it is opened with,
+// e.g: address, credentials and TLS config.
+// The settings are hashed, so that the credentials are not kept in the keys.
+func ConnKey(v ...any) string {
+	b, _ := json.Marshal(v)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,95 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"errors"
+	"testing"
+)
+
+type testConn struct {
+	id     int
+	closed bool
+}
+
+func TestSharedConns(t *testing.T) {
+	opened := 0
+	open := func() (*testConn, error) {
+		opened++
+		return &testConn{id: opened}, nil
+	}
+	s := NewSharedConns(func(c *testConn) { c.closed = true })
+
+	c1, release1, err := s.Acquire("a", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, release2, err := s.Acquire("a", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1 != c2 || opened != 1 {
+		t.Fatalf("expected a single shared connection, opened %d", opened)
+	}
+	c3, release3, err := s.Acquire("b", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c3 == c1 {
+		t.Fatalf("expected a different connection for a different key")
+	}
+	if s.Refs("a") != 2 {
+		t.Errorf("expected 2 users, got %d", s.Refs("a"))
+	}
+
+	release1()
+	release1()
+	if c1.closed || s.Refs("a") != 1 {
+		t.Errorf("expected the connection to stay open with 1 user, got %d", s.Refs("a"))
+	}
+	release2()
+	if !c1.closed || s.Refs("a") != 0 {
+		t.Errorf("expected the connection to be closed after its last release")
+	}
+	release3()
+	if !c3.closed {
+		t.Errorf("expected the connection to be closed after its last release")
+	}
+
+	c4, release4, err := s.Acquire("a", open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release4()
+	if c4 == c1 || c4.closed {
+		t.Errorf("expected a new connection once the previous one is closed")
+	}
+}
+
+func TestSharedConnsOpenError(t *testing.T) {
+	s := NewSharedConns(func(*testConn) {})
+	_, _, err := s.Acquire("a", func() (*testConn, error) {
+		return nil, errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if s.Refs("a") != 0 {
+		t.Errorf("expected a failed connection not to be kept")
+	}
+}
+
+func TestConnKey(t *testing.T) {
+	if ConnKey("localhost:4222", "user", "pass") != ConnKey("localhost:4222", "user", "pass") {
+		t.Errorf("expected identical settings to have the same key")
+	}
+	if ConnKey("localhost:4222", "user", "pass") == ConnKey("localhost:4222", "user", "other") {
+		t.Errorf("expected different settings to have different keys")
+	}
+}
@@ -10,6 +10,7 @@ package snmpoutput
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -38,6 +39,7 @@ const (
 	loggingPrefix           = "[snmp_output:%s] "
 	defaultPort             = 162
 	defaultCommunity        = "public"
+	defaultVersion          = "v2c"
 	minStartDelay           = 5 * time.Second
 	initialEventsBufferSize = 1000
 	//
@@ -55,29 +57,50 @@ func init() {
 }
 
 type snmpOutput struct {
-	name       string
-	cfg        *Config
-	logger     *log.Logger
-	cancelFn   context.CancelFunc
-	snmpClient g.Handler
-	eventChan  chan *formatters.EventMsg
-	evps       []formatters.EventProcessor
-	targetTpl  *template.Template
+	name         string
+	cfg          *Config
+	logger       *log.Logger
+	cancelFn     context.CancelFunc
+	snmpClient   g.Handler
+	eventChan    chan *formatters.EventMsg
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
+	targetTpl    *template.Template
 
 	cache     cache.Cache
 	startTime time.Time
+	// SNMPv3 USM parameters, nil with SNMPv2c
+	usm *g.UsmSecurityParameters
 }
 
 type Config struct {
-	Address         string        `mapstructure:"address,omitempty" json:"address,omitempty"`
-	Port            uint16        `mapstructure:"port,omitempty" json:"port,omitempty"`
-	Community       string        `mapstructure:"community,omitempty" json:"community,omitempty"`
-	StartDelay      time.Duration `mapstructure:"start-delay,omitempty" json:"start-delay,omitempty"`
-	Traps           []*trap       `mapstructure:"traps,omitempty" json:"traps,omitempty"`
-	AddTarget       string        `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
-	TargetTemplate  string        `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
-	EnableMetrics   bool          `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
-	EventProcessors []string      `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	Address         string                   `mapstructure:"address,omitempty" json:"address,omitempty"`
+	Port            uint16                   `mapstructure:"port,omitempty" json:"port,omitempty" default:"162"`
+	Version         string                   `mapstructure:"version,omitempty" json:"version,omitempty" default:"v2c"`
+	Community       string                   `mapstructure:"community,omitempty" json:"community,omitempty" default:"public"`
+	V3              *v3Config                `mapstructure:"v3,omitempty" json:"v3,omitempty"`
+	StartDelay      time.Duration            `mapstructure:"start-delay,omitempty" json:"start-delay,omitempty" default:"5s"`
+	Traps           []*trap                  `mapstructure:"traps,omitempty" json:"traps,omitempty"`
+	AddTarget       string                   `mapstructure:"add-target,omitempty" json:"add-target,omitempty"`
+	TargetTemplate  string                   `mapstructure:"target-template,omitempty" json:"target-template,omitempty"`
+	EnableMetrics   bool                     `mapstructure:"enable-metrics,omitempty" json:"enable-metrics,omitempty"`
+	EventProcessors []string                 `mapstructure:"event-processors,omitempty" json:"event-processors,omitempty"`
+	NumberFormat    *formatters.NumberFormat `mapstructure:"number-format,omitempty" json:"number-format,omitempty"`
+}
+
+// SNMPv3 User-based Security Model config
+type v3Config struct {
+	Username string `mapstructure:"username,omitempty" json:"username,omitempty"`
+	// MD5, SHA, SHA224, SHA256, SHA384 or SHA512, no authentication if empty
+	AuthProtocol   string `mapstructure:"auth-protocol,omitempty" json:"auth-protocol,omitempty"`
+	AuthPassphrase string `mapstructure:"auth-passphrase,omitempty" json:"-"`
+	// DES, AES, AES192, AES256, AES192C or AES256C, no privacy if empty
+	PrivProtocol   string `mapstructure:"priv-protocol,omitempty" json:"priv-protocol,omitempty"`
+	PrivPassphrase string `mapstructure:"priv-passphrase,omitempty" json:"-"`
+	// hex encoded authoritative engine ID of the traps,
+	// the informs receivers engine ID is discovered.
+	EngineID    string `mapstructure:"engine-id,omitempty" json:"engine-id,omitempty"`
+	ContextName string `mapstructure:"context-name,omitempty" json:"context-name,omitempty"`
 }
 
 type binding struct {
@@ -92,9 +115,13 @@ type binding struct {
 }
 
 type trap struct {
-	InformPDU bool       `mapstructure:"inform,omitempty" json:"inform,omitempty"`
+	InformPDU bool `mapstructure:"inform,omitempty" json:"inform,omitempty"`
+	// jq expression selecting the events triggering the trap
+	Condition string     `mapstructure:"condition,omitempty" json:"condition,omitempty"`
 	Trigger   *binding   `mapstructure:"trigger,omitempty" json:"trigger,omitempty"`
 	Bindings  []*binding `mapstructure:"bindings,omitempty" json:"bindings,omitempty"`
+
+	condition *gojq.Code
 }
 
 func (s *snmpOutput) SetLogger(logger *log.Logger) {
@@ -108,7 +135,11 @@ func (s *snmpOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := s.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	s.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	s.evps, err = formatters.MakeEventProcessors(
 		logger,
 		s.cfg.EventProcessors,
@@ -119,6 +150,7 @@ func (s *snmpOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	s.evps = append(s.evps, nfps...)
 	return nil
 }
 
@@ -137,6 +169,10 @@ func (s *snmpOutput) Init(ctx context.Context, name string, cfg map[string]inter
 	}
 
 	s.setDefaults()
+	err = s.initSecurity()
+	if err != nil {
+		return err
+	}
 
 	if len(s.cfg.Traps) == 0 {
 		return errors.New("missing traps definition")
@@ -145,8 +181,14 @@ func (s *snmpOutput) Init(ctx context.Context, name string, cfg map[string]inter
 		if trap.Trigger == nil {
 			return fmt.Errorf("trap index %d missing \"trigger\"", i)
 		}
-		if trap.Trigger.Path == "" {
-			return fmt.Errorf("trap index %d missing \"path\"", i)
+		if trap.Trigger.Path == "" && trap.Condition == "" {
+			return fmt.Errorf("trap index %d missing \"path\" or \"condition\"", i)
+		}
+		if trap.Condition != "" {
+			trap.condition, err = parseJQ(trap.Condition)
+			if err != nil {
+				return fmt.Errorf("trap index %d: invalid condition: %v", i, err)
+			}
 		}
 		// init trap and bindings
 		trap.Trigger.oidTemplate, err = parseJQ(trap.Trigger.OID)
@@ -220,7 +262,7 @@ func (s *snmpOutput) Write(ctx context.Context, m proto.Message, meta outputs.Me
 
 		s.cache.Write(ctx, measName, rsp)
 
-		events, err := formatters.ResponseToEventMsgs(measName, rsp, meta, s.evps...)
+		events, err := formatters.ResponseToEventMsgs(measName, rsp, meta, s.evpOverrides.Select(meta, s.evps)...)
 		if err != nil {
 			s.logger.Printf("failed to convert message to event: %v", err)
 			return
@@ -235,7 +277,19 @@ func (s *snmpOutput) Write(ctx context.Context, m proto.Message, meta outputs.Me
 	}
 }
 
-func (s *snmpOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {}
+func (s *snmpOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	var evs = []*formatters.EventMsg{ev}
+	for _, proc := range s.evps {
+		evs = proc.Apply(evs...)
+	}
+	for _, ev := range evs {
+		select {
+		case <-ctx.Done():
+			return
+		case s.eventChan <- ev:
+		}
+	}
+}
 
 func (s *snmpOutput) Close() error {
 	s.cancelFn()
@@ -296,6 +350,9 @@ func (s *snmpOutput) setDefaults() {
 	if s.cfg.Port <= 0 {
 		s.cfg.Port = defaultPort
 	}
+	if s.cfg.Version == "" {
+		s.cfg.Version = defaultVersion
+	}
 	if s.cfg.Community == "" {
 		s.cfg.Community = defaultCommunity
 	}
@@ -307,9 +364,17 @@ func (s *snmpOutput) setDefaults() {
 func (s *snmpOutput) createSNMPHandler() {
 	s.snmpClient = g.NewHandler()
 	s.snmpClient.SetTarget(s.cfg.Address)
-	s.snmpClient.SetCommunity(s.cfg.Community)
 	s.snmpClient.SetPort(s.cfg.Port)
-	s.snmpClient.SetVersion(g.Version2c)
+	if s.usm != nil {
+		s.snmpClient.SetVersion(g.Version3)
+		s.snmpClient.SetSecurityModel(g.UserSecurityModel)
+		s.snmpClient.SetMsgFlags(msgFlags(s.usm))
+		s.snmpClient.SetSecurityParameters(s.usm)
+		s.snmpClient.SetContextName(s.cfg.V3.ContextName)
+	} else {
+		s.snmpClient.SetCommunity(s.cfg.Community)
+		s.snmpClient.SetVersion(g.Version2c)
+	}
 CONN:
 	err := s.snmpClient.Connect()
 	if err != nil {
@@ -320,6 +385,119 @@ CONN:
 	s.logger.Print("SNMP connected")
 }
 
+// initSecurity validates the SNMP version and builds the SNMPv3 USM parameters.
+func (s *snmpOutput) initSecurity() error {
+	switch s.cfg.Version {
+	case "v2c":
+		return nil
+	case "v3":
+	default:
+		return fmt.Errorf("unsupported SNMP version %q, expected v2c or v3", s.cfg.Version)
+	}
+	v3 := s.cfg.V3
+	if v3 == nil || v3.Username == "" {
+		return errors.New("SNMPv3 requires a v3 username")
+	}
+	usm := &g.UsmSecurityParameters{
+		UserName:                 v3.Username,
+		AuthenticationProtocol:   g.NoAuth,
+		PrivacyProtocol:          g.NoPriv,
+		AuthenticationPassphrase: v3.AuthPassphrase,
+		PrivacyPassphrase:        v3.PrivPassphrase,
+		AuthoritativeEngineBoots: 1,
+	}
+	var err error
+	if v3.AuthProtocol != "" {
+		usm.AuthenticationProtocol, err = authProtocol(v3.AuthProtocol)
+		if err != nil {
+			return err
+		}
+		if v3.AuthPassphrase == "" {
+			return errors.New("SNMPv3 authentication requires an auth-passphrase")
+		}
+	}
+	if v3.PrivProtocol != "" {
+		if v3.AuthProtocol == "" {
+			return errors.New("SNMPv3 privacy requires an auth-protocol")
+		}
+		usm.PrivacyProtocol, err = privProtocol(v3.PrivProtocol)
+		if err != nil {
+			return err
+		}
+		if v3.PrivPassphrase == "" {
+			return errors.New("SNMPv3 privacy requires a priv-passphrase")
+		}
+	}
+	if v3.EngineID != "" {
+		engineID, err := hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(v3.EngineID, ":", ""), "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid SNMPv3 engine-id: %v", err)
+		}
+		usm.AuthoritativeEngineID = string(engineID)
+	}
+	// the sender of a trap is the authoritative engine,
+	// while the receiver of an inform is.
+	for i, trap := range s.cfg.Traps {
+		if trap == nil {
+			continue
+		}
+		if !trap.InformPDU && usm.AuthoritativeEngineID == "" {
+			return fmt.Errorf("trap index %d: SNMPv3 traps require an engine-id", i)
+		}
+		if trap.InformPDU && usm.AuthoritativeEngineID != "" {
+			return fmt.Errorf("trap index %d: SNMPv3 informs use the receiver engine ID, engine-id must not be set", i)
+		}
+	}
+	s.usm = usm
+	return nil
+}
+
+func msgFlags(usm *g.UsmSecurityParameters) g.SnmpV3MsgFlags {
+	switch {
+	case usm.PrivacyProtocol != g.NoPriv:
+		return g.AuthPriv
+	case usm.AuthenticationProtocol != g.NoAuth:
+		return g.AuthNoPriv
+	}
+	return g.NoAuthNoPriv
+}
+
+func authProtocol(p string) (g.SnmpV3AuthProtocol, error) {
+	switch strings.ToUpper(p) {
+	case "MD5":
+		return g.MD5, nil
+	case "SHA":
+		return g.SHA, nil
+	case "SHA224":
+		return g.SHA224, nil
+	case "SHA256":
+		return g.SHA256, nil
+	case "SHA384":
+		return g.SHA384, nil
+	case "SHA512":
+		return g.SHA512, nil
+	}
+	return g.NoAuth, fmt.Errorf("unknown SNMPv3 auth-protocol %q", p)
+}
+
+func privProtocol(p string) (g.SnmpV3PrivProtocol, error) {
+	switch strings.ToUpper(p) {
+	case "DES":
+		return g.DES, nil
+	case "AES":
+		return g.AES, nil
+	case "AES192":
+		return g.AES192, nil
+	case "AES256":
+		return g.AES256, nil
+	case "AES192C":
+		return g.AES192C, nil
+	case "AES256C":
+		return g.AES256C, nil
+	}
+	return g.NoPriv, fmt.Errorf("unknown SNMPv3 priv-protocol %q", p)
+}
+
 func pduType(typ string) g.Asn1BER {
 	switch typ {
 	case "bool":
@@ -388,8 +566,20 @@ func (s *snmpOutput) runJQ(code *gojq.Code, ev map[string]interface{}) (interfac
 func (s *snmpOutput) handleEvent(ev *formatters.EventMsg, idx int) error {
 	trap := *s.cfg.Traps[idx]
 	// trigger ?
-	if _, ok := ev.Values[trap.Trigger.Path]; !ok {
-		return nil
+	if trap.Trigger.Path != "" {
+		if _, ok := ev.Values[trap.Trigger.Path]; !ok {
+			return nil
+		}
+	}
+	if trap.condition != nil {
+		ok, err := s.evalCondition(trap.condition, ev)
+		if err != nil {
+			snmpNumberOfFailedTrapGeneration.WithLabelValues(s.name, fmt.Sprintf("%d", idx), err.Error()).Inc()
+			return err
+		}
+		if !ok {
+			return nil
+		}
 	}
 	start := time.Now()
 	var err error
@@ -436,6 +626,9 @@ func (s *snmpOutput) handleEvent(ev *formatters.EventMsg, idx int) error {
 	}
 	//
 	snmpNumberOfSentTraps.WithLabelValues(s.name, fmt.Sprintf("%d", idx)).Add(1)
+	if s.usm != nil && !trap.InformPDU {
+		s.usm.AuthoritativeEngineTime = uint32(time.Since(s.startTime).Seconds())
+	}
 	_, err = s.snmpClient.SendTrap(g.SnmpTrap{
 		Variables: pdus,
 		IsInform:  trap.InformPDU,
@@ -448,6 +641,22 @@ func (s *snmpOutput) handleEvent(ev *formatters.EventMsg, idx int) error {
 	return nil
 }
 
+// evalCondition runs the jq condition code with event ev as input,
+// it must return a boolean.
+func (s *snmpOutput) evalCondition(code *gojq.Code, ev *formatters.EventMsg) (bool, error) {
+	r, err := s.runJQ(code, ev.ToMap())
+	if err != nil {
+		return false, fmt.Errorf("failed to run condition JQ: %v", err)
+	}
+	switch r := r.(type) {
+	case bool:
+		return r, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected condition result type: %T", r)
+}
+
 func (s *snmpOutput) buildTriggerPDU(bd *binding, targetName string, ev *formatters.EventMsg) (g.SnmpPDU, error) {
 	var oid string
 	var val interface{}
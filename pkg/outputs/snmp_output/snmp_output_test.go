@@ -0,0 +1,255 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snmpoutput
+
+import (
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	g "github.com/gosnmp/gosnmp"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+	"github.com/openconfig/gnmic/pkg/cache"
+	"github.com/openconfig/gnmic/pkg/formatters"
+)
+
+func TestInitSecurity(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		flags   g.SnmpV3MsgFlags
+		wantErr bool
+	}{
+		{
+			name: "v2c",
+			cfg:  &Config{Version: "v2c"},
+		},
+		{
+			name:    "unknown_version",
+			cfg:     &Config{Version: "v1"},
+			wantErr: true,
+		},
+		{
+			name:    "v3_missing_username",
+			cfg:     &Config{Version: "v3", V3: &v3Config{}},
+			wantErr: true,
+		},
+		{
+			name: "v3_auth_priv",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username:       "gnmic",
+				AuthProtocol:   "sha256",
+				AuthPassphrase: "authpass",
+				PrivProtocol:   "AES",
+				PrivPassphrase: "privpass",
+				EngineID:       "0x8000000001020304",
+			}, Traps: []*trap{{}}},
+			flags: g.AuthPriv,
+		},
+		{
+			name: "v3_informs_no_auth",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username: "gnmic",
+			}, Traps: []*trap{{InformPDU: true}}},
+			flags: g.NoAuthNoPriv,
+		},
+		{
+			name: "v3_priv_without_auth",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username:       "gnmic",
+				PrivProtocol:   "AES",
+				PrivPassphrase: "privpass",
+				EngineID:       "8000000001020304",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "v3_traps_without_engine_id",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username: "gnmic",
+			}, Traps: []*trap{{}}},
+			wantErr: true,
+		},
+		{
+			name: "v3_informs_with_engine_id",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username: "gnmic",
+				EngineID: "8000000001020304",
+			}, Traps: []*trap{{InformPDU: true}}},
+			wantErr: true,
+		},
+		{
+			name: "v3_unknown_auth_protocol",
+			cfg: &Config{Version: "v3", V3: &v3Config{
+				Username:       "gnmic",
+				AuthProtocol:   "SHA1024",
+				AuthPassphrase: "authpass",
+				EngineID:       "8000000001020304",
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &snmpOutput{cfg: tt.cfg}
+			err := s.initSecurity()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.cfg.Version == "v2c" {
+				if s.usm != nil {
+					t.Errorf("unexpected USM parameters with SNMPv2c")
+				}
+				return
+			}
+			if got := msgFlags(s.usm); got != tt.flags {
+				t.Errorf("got message flags %v, expected %v", got, tt.flags)
+			}
+		})
+	}
+}
+
+func TestHandleEventV3Trap(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	s := &snmpOutput{
+		name: "test",
+		cfg: &Config{
+			Address: "127.0.0.1",
+			Port:    uint16(port),
+			Version: "v3",
+			V3: &v3Config{
+				Username:       "gnmic",
+				AuthProtocol:   "SHA",
+				AuthPassphrase: "authpassphrase",
+				PrivProtocol:   "AES",
+				PrivPassphrase: "privpassphrase",
+				EngineID:       "8000000001020304",
+			},
+			Traps: []*trap{
+				{
+					Condition: `.tags.event == "state-change" and .values.state != "READY"`,
+					Trigger: &binding{
+						OID:   `"1.3.6.1.4.1.1.1"`,
+						Type:  "octetString",
+						Value: `.values.state`,
+					},
+				},
+			},
+		},
+		logger:    log.New(io.Discard, "", 0),
+		startTime: time.Now(),
+	}
+	err = s.initSecurity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := s.cfg.Traps[0]
+	tr.condition, err = parseJQ(tr.Condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Trigger.oidTemplate, err = parseJQ(tr.Trigger.OID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Trigger.valTemplate, err = parseJQ(tr.Trigger.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.cache, err = cache.New(&cache.Config{Expiration: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.createSNMPHandler()
+	defer s.snmpClient.Close()
+
+	// not selected by the condition
+	err = s.handleEvent(&formatters.EventMsg{
+		Tags:   map[string]string{"source": "r1", "event": "state-change"},
+		Values: map[string]interface{}{"state": "READY"},
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.handleEvent(&formatters.EventMsg{
+		Tags:   map[string]string{"source": "r1", "event": "state-change"},
+		Values: map[string]interface{}{"state": "TRANSIENT_FAILURE"},
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 65535)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usm := &g.UsmSecurityParameters{
+		UserName:                 "gnmic",
+		AuthenticationProtocol:   g.SHA,
+		AuthenticationPassphrase: "authpassphrase",
+		PrivacyProtocol:          g.AES,
+		PrivacyPassphrase:        "privpassphrase",
+		AuthoritativeEngineID:    string([]byte{0x80, 0, 0, 0, 1, 2, 3, 4}),
+	}
+	err = usm.InitSecurityKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := &g.GoSNMP{
+		Version:            g.Version3,
+		SecurityModel:      g.UserSecurityModel,
+		MsgFlags:           g.AuthPriv,
+		SecurityParameters: usm,
+		Logger:             g.NewLogger(log.New(io.Discard, "", 0)),
+	}
+	pkt, err := receiver.SnmpDecodePacket(buf[:n])
+	if err != nil {
+		t.Fatalf("failed to decode trap: %v", err)
+	}
+	if pkt.PDUType != g.SNMPv2Trap {
+		t.Errorf("unexpected PDU type %v", pkt.PDUType)
+	}
+	if len(pkt.Variables) != 2 {
+		t.Fatalf("expected 2 variable bindings, got %d: %v", len(pkt.Variables), pkt.Variables)
+	}
+	vb := pkt.Variables[1]
+	if vb.Name != ".1.3.6.1.4.1.1.1" || string(vb.Value.([]byte)) != "TRANSIENT_FAILURE" {
+		t.Errorf("unexpected variable binding %+v", vb)
+	}
+	// a single trap is sent
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, _, err = conn.ReadFrom(buf); err == nil {
+		t.Error("expected a single trap")
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	s := &snmpOutput{cfg: &Config{}}
+	s.setDefaults()
+	for _, m := range testutils.DefaultTagsMismatches(s.cfg) {
+		t.Error(m)
+	}
+}
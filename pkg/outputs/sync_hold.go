@@ -0,0 +1,184 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	holdUntilSyncConfigKey = "hold-until-sync"
+
+	defaultHoldUntilSyncTimeout     = time.Minute
+	defaultHoldUntilSyncMaxMessages = 10000
+)
+
+// HoldUntilSyncConfig makes an output hold the initial updates of each
+// subscription of each target until the target sends the sync_response
+// marking the end of the initial updates.
+// The held messages are then written in the order they were received.
+type HoldUntilSyncConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty" json:"enabled,omitempty"`
+	// max time to hold the messages of a subscription waiting for its sync_response.
+	Timeout time.Duration `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+	// max number of messages held per subscription.
+	MaxMessages int `mapstructure:"max-messages,omitempty" json:"max-messages,omitempty"`
+}
+
+// syncHoldOutput wraps an Output and holds the messages
+// of each source subscription until it is synced.
+type syncHoldOutput struct {
+	Output
+	cfg    *HoldUntilSyncConfig
+	logger *log.Logger
+
+	m *sync.Mutex
+	// held subscriptions, keyed by source and subscription name.
+	subs map[string]*heldSubscription
+}
+
+type heldSubscription struct {
+	m      *sync.Mutex
+	synced bool
+	msgs   []*ProtoMsg
+	timer  *time.Timer
+}
+
+// NewSyncHoldOutput returns the output o wrapped with the hold-until-sync
+// config found under the `hold-until-sync` key of the output config cfg.
+// If the config is absent or disabled, o is returned unchanged.
+func NewSyncHoldOutput(o Output, cfg map[string]interface{}, logger *log.Logger) (Output, error) {
+	hc, ok := cfg[holdUntilSyncConfigKey]
+	if !ok || hc == nil {
+		return o, nil
+	}
+	h := new(HoldUntilSyncConfig)
+	err := DecodeConfig(hc, h)
+	if err != nil {
+		return nil, err
+	}
+	if !h.Enabled {
+		return o, nil
+	}
+	if h.Timeout <= 0 {
+		h.Timeout = defaultHoldUntilSyncTimeout
+	}
+	if h.MaxMessages <= 0 {
+		h.MaxMessages = defaultHoldUntilSyncMaxMessages
+	}
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	return &syncHoldOutput{
+		Output: o,
+		cfg:    h,
+		logger: logger,
+		m:      new(sync.Mutex),
+		subs:   make(map[string]*heldSubscription),
+	}, nil
+}
+
+func (s *syncHoldOutput) Write(ctx context.Context, rsp proto.Message, meta Meta) {
+	sr, ok := rsp.(*gnmi.SubscribeResponse)
+	if !ok {
+		s.Output.Write(ctx, rsp, meta)
+		return
+	}
+	key := meta["source"] + "/" + meta["subscription-name"]
+	hs := s.subscription(key)
+	hs.m.Lock()
+	defer hs.m.Unlock()
+	if hs.synced {
+		s.Output.Write(ctx, rsp, meta)
+		return
+	}
+	if sr.GetSyncResponse() {
+		s.release(ctx, hs)
+		s.Output.Write(ctx, rsp, meta)
+		return
+	}
+	d := DeliveryFrom(ctx)
+	d.Add(1)
+	hs.msgs = append(hs.msgs, NewProtoMsg(rsp, meta).WithDelivery(d))
+	if len(hs.msgs) == 1 {
+		hs.timer = time.AfterFunc(s.cfg.Timeout, func() {
+			hs.m.Lock()
+			defer hs.m.Unlock()
+			if hs.synced {
+				return
+			}
+			s.logger.Printf("hold-until-sync: no sync response received for %q after %s, releasing %d messages",
+				key, s.cfg.Timeout, len(hs.msgs))
+			s.release(ctx, hs)
+		})
+	}
+	if len(hs.msgs) >= s.cfg.MaxMessages {
+		s.logger.Printf("hold-until-sync: max messages (%d) held for %q, releasing them", s.cfg.MaxMessages, key)
+		s.release(ctx, hs)
+	}
+}
+
+// subscription returns the held subscription for key, creating it if needed.
+func (s *syncHoldOutput) subscription(key string) *heldSubscription {
+	s.m.Lock()
+	defer s.m.Unlock()
+	hs, ok := s.subs[key]
+	if !ok {
+		hs = &heldSubscription{m: new(sync.Mutex)}
+		s.subs[key] = hs
+	}
+	return hs
+}
+
+// release writes the held messages to the wrapped output and marks the subscription as synced.
+// It must be called with the subscription lock held.
+func (s *syncHoldOutput) release(ctx context.Context, hs *heldSubscription) {
+	hs.synced = true
+	if hs.timer != nil {
+		hs.timer.Stop()
+		hs.timer = nil
+	}
+	for _, m := range hs.msgs {
+		s.Output.Write(WithDelivery(ctx, m.GetDelivery()), m.GetMsg(), m.GetMeta())
+		m.GetDelivery().Done(nil)
+	}
+	hs.msgs = nil
+}
+
+// Healthy forwards the health status of the wrapped output.
+func (s *syncHoldOutput) Healthy() bool {
+	return IsHealthy(s.Output)
+}
+
+// Gatherer returns the prometheus gatherer of the wrapped output.
+func (s *syncHoldOutput) Gatherer() prometheus.Gatherer {
+	return GathererOf(s.Output)
+}
+
+// Drain returns the messages buffered by the wrapped output, if it implements Drainer.
+// Held messages are not returned.
+func (s *syncHoldOutput) Drain() []*ProtoMsg {
+	if d, ok := s.Output.(Drainer); ok {
+		return d.Drain()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped output.
+func (s *syncHoldOutput) Flush(ctx context.Context) error {
+	return Flush(ctx, s.Output)
+}
@@ -0,0 +1,131 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outputs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func updateRsp(ts int64) *gnmi.SubscribeResponse {
+	return &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_Update{
+			Update: &gnmi.Notification{Timestamp: ts},
+		},
+	}
+}
+
+func syncRsp() *gnmi.SubscribeResponse {
+	return &gnmi.SubscribeResponse{
+		Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+	}
+}
+
+func (s *stubOutput) numMsgs() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return len(s.msgs)
+}
+
+func TestNewSyncHoldOutput(t *testing.T) {
+	o := &stubOutput{}
+	for _, cfg := range []map[string]interface{}{
+		{},
+		{"hold-until-sync": map[string]interface{}{"enabled": false}},
+	} {
+		out, err := NewSyncHoldOutput(o, cfg, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != o {
+			t.Errorf("expected the output to be returned unchanged for config %v", cfg)
+		}
+	}
+}
+
+func TestSyncHoldOutputWrite(t *testing.T) {
+	o := &stubOutput{}
+	out, err := NewSyncHoldOutput(o, map[string]interface{}{
+		"hold-until-sync": map[string]interface{}{"enabled": true},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	m1 := Meta{"source": "r1", "subscription-name": "sub1"}
+	m2 := Meta{"source": "r2", "subscription-name": "sub1"}
+
+	out.Write(ctx, updateRsp(1), m1)
+	out.Write(ctx, updateRsp(2), m1)
+	out.Write(ctx, updateRsp(3), m2)
+	if n := o.numMsgs(); n != 0 {
+		t.Fatalf("expected the messages to be held, got %d written", n)
+	}
+	// r1 is synced: its held messages are written, in order, followed by the sync response.
+	out.Write(ctx, syncRsp(), m1)
+	if n := o.numMsgs(); n != 3 {
+		t.Fatalf("expected 3 written messages, got %d", n)
+	}
+	for i, ts := range []int64{1, 2} {
+		if got := o.msgs[i].(*gnmi.SubscribeResponse).GetUpdate().GetTimestamp(); got != ts {
+			t.Errorf("message %d: expected timestamp %d, got %d", i, ts, got)
+		}
+	}
+	if !o.msgs[2].(*gnmi.SubscribeResponse).GetSyncResponse() {
+		t.Errorf("expected the sync response to be written last")
+	}
+	// messages of a synced subscription are not held
+	out.Write(ctx, updateRsp(4), m1)
+	if n := o.numMsgs(); n != 4 {
+		t.Fatalf("expected 4 written messages, got %d", n)
+	}
+	// r2 is still held
+	out.Write(ctx, syncRsp(), m2)
+	if n := o.numMsgs(); n != 6 {
+		t.Fatalf("expected 6 written messages, got %d", n)
+	}
+}
+
+func TestSyncHoldOutputRelease(t *testing.T) {
+	o := &stubOutput{}
+	out, err := NewSyncHoldOutput(o, map[string]interface{}{
+		"hold-until-sync": map[string]interface{}{
+			"enabled":      true,
+			"max-messages": 2,
+			"timeout":      "20ms",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	// released when max-messages is reached
+	m1 := Meta{"source": "r1", "subscription-name": "sub1"}
+	out.Write(ctx, updateRsp(1), m1)
+	out.Write(ctx, updateRsp(2), m1)
+	if n := o.numMsgs(); n != 2 {
+		t.Fatalf("expected 2 written messages, got %d", n)
+	}
+	// released after the timeout
+	m2 := Meta{"source": "r2", "subscription-name": "sub1"}
+	out.Write(ctx, updateRsp(3), m2)
+	if n := o.numMsgs(); n != 2 {
+		t.Fatalf("expected 2 written messages, got %d", n)
+	}
+	deadline := time.Now().Add(time.Second)
+	for o.numMsgs() != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("held message not released after the timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
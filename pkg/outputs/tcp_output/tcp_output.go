@@ -47,32 +47,34 @@ func init() {
 type tcpOutput struct {
 	cfg *config
 
-	cancelFn context.CancelFunc
-	buffer   chan []byte
-	limiter  *time.Ticker
-	logger   *log.Logger
-	mo       *formatters.MarshalOptions
-	evps     []formatters.EventProcessor
+	cancelFn     context.CancelFunc
+	buffer       chan []byte
+	limiter      *time.Ticker
+	logger       *log.Logger
+	mo           *formatters.MarshalOptions
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
 
 	targetTpl *template.Template
 	delimiter []byte
 }
 
 type config struct {
-	Address            string        `mapstructure:"address,omitempty"` // ip:port
-	Rate               time.Duration `mapstructure:"rate,omitempty"`
-	BufferSize         uint          `mapstructure:"buffer-size,omitempty"`
-	Format             string        `mapstructure:"format,omitempty"`
-	AddTarget          string        `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string        `mapstructure:"target-template,omitempty"`
-	OverrideTimestamps bool          `mapstructure:"override-timestamps,omitempty"`
-	SplitEvents        bool          `mapstructure:"split-events,omitempty"`
-	Delimiter          string        `mapstructure:"delimiter,omitempty"`
-	KeepAlive          time.Duration `mapstructure:"keep-alive,omitempty"`
-	RetryInterval      time.Duration `mapstructure:"retry-interval,omitempty"`
-	NumWorkers         int           `mapstructure:"num-workers,omitempty"`
-	EnableMetrics      bool          `mapstructure:"enable-metrics,omitempty"`
-	EventProcessors    []string      `mapstructure:"event-processors,omitempty"`
+	Address            string                   `mapstructure:"address,omitempty"` // ip:port
+	Rate               time.Duration            `mapstructure:"rate,omitempty"`
+	BufferSize         uint                     `mapstructure:"buffer-size,omitempty"`
+	Format             string                   `mapstructure:"format,omitempty"`
+	AddTarget          string                   `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                   `mapstructure:"target-template,omitempty"`
+	OverrideTimestamps bool                     `mapstructure:"override-timestamps,omitempty"`
+	SplitEvents        bool                     `mapstructure:"split-events,omitempty"`
+	Delimiter          string                   `mapstructure:"delimiter,omitempty"`
+	KeepAlive          time.Duration            `mapstructure:"keep-alive,omitempty"`
+	RetryInterval      time.Duration            `mapstructure:"retry-interval,omitempty" default:"2s"`
+	NumWorkers         int                      `mapstructure:"num-workers,omitempty" default:"1"`
+	EnableMetrics      bool                     `mapstructure:"enable-metrics,omitempty"`
+	EventProcessors    []string                 `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat `mapstructure:"number-format,omitempty"`
 }
 
 func (t *tcpOutput) SetLogger(logger *log.Logger) {
@@ -86,7 +88,11 @@ func (t *tcpOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := t.cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	t.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	t.evps, err = formatters.MakeEventProcessors(
 		logger,
 		t.cfg.EventProcessors,
@@ -97,6 +103,7 @@ func (t *tcpOutput) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	t.evps = append(t.evps, nfps...)
 	return nil
 }
 
@@ -120,12 +127,7 @@ func (t *tcpOutput) Init(ctx context.Context, name string, cfg map[string]interf
 	if t.cfg.Rate > 0 {
 		t.limiter = time.NewTicker(t.cfg.Rate)
 	}
-	if t.cfg.RetryInterval == 0 {
-		t.cfg.RetryInterval = defaultRetryTimer
-	}
-	if t.cfg.NumWorkers < 1 {
-		t.cfg.NumWorkers = defaultNumWorkers
-	}
+	t.setDefaults()
 	if len(t.cfg.Delimiter) > 0 {
 		t.delimiter = []byte(t.cfg.Delimiter)
 	}
@@ -155,6 +157,15 @@ func (t *tcpOutput) Init(ctx context.Context, name string, cfg map[string]interf
 	return nil
 }
 
+func (t *tcpOutput) setDefaults() {
+	if t.cfg.RetryInterval == 0 {
+		t.cfg.RetryInterval = defaultRetryTimer
+	}
+	if t.cfg.NumWorkers < 1 {
+		t.cfg.NumWorkers = defaultNumWorkers
+	}
+}
+
 func (t *tcpOutput) Write(ctx context.Context, m proto.Message, meta outputs.Meta) {
 	if m == nil {
 		return
@@ -167,7 +178,7 @@ func (t *tcpOutput) Write(ctx context.Context, m proto.Message, meta outputs.Met
 		if err != nil {
 			t.logger.Printf("failed to add target to the response: %v", err)
 		}
-		bb, err := outputs.Marshal(rsp, meta, t.mo, t.cfg.SplitEvents, t.evps...)
+		bb, err := outputs.Marshal(rsp, meta, t.mo, t.cfg.SplitEvents, t.evpOverrides.Select(meta, t.evps)...)
 		if err != nil {
 			t.logger.Printf("failed marshaling proto msg: %v", err)
 			return
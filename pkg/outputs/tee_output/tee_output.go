@@ -0,0 +1,215 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tee_output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/types"
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+)
+
+const (
+	loggingPrefix = "[tee_output:%s] "
+	outputType    = "tee"
+)
+
+func init() {
+	outputs.Register(outputType, func() outputs.Output {
+		return &teeOutput{
+			cfg:    &config{},
+			logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
+		}
+	})
+}
+
+// teeOutput writes the received messages to all its member outputs,
+// each one with its own format and event processors.
+type teeOutput struct {
+	cfg     *config
+	logger  *log.Logger
+	members []outputs.Output
+}
+
+type config struct {
+	// list of member outputs configurations.
+	Outputs []map[string]interface{} `mapstructure:"outputs,omitempty" json:"outputs,omitempty"`
+	// default format of the member outputs not setting one.
+	Format string `mapstructure:"format,omitempty" json:"format,omitempty"`
+	Debug  bool   `mapstructure:"debug,omitempty" json:"debug,omitempty"`
+}
+
+func (t *teeOutput) String() string {
+	b, err := json.Marshal(t.cfg)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (t *teeOutput) SetLogger(logger *log.Logger) {
+	if logger != nil && t.logger != nil {
+		t.logger.SetOutput(logger.Writer())
+		t.logger.SetFlags(logger.Flags())
+	}
+}
+
+// SetEventProcessors is a noop, the event processors
+// are configured under each member output.
+func (t *teeOutput) SetEventProcessors(map[string]map[string]interface{}, *log.Logger, map[string]*types.TargetConfig, map[string]map[string]interface{}) error {
+	return nil
+}
+
+func (t *teeOutput) Init(ctx context.Context, name string, cfg map[string]interface{}, opts ...outputs.Option) error {
+	err := outputs.DecodeConfig(cfg, t.cfg)
+	if err != nil {
+		return err
+	}
+	t.logger.SetPrefix(fmt.Sprintf(loggingPrefix, name))
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return err
+		}
+	}
+	if len(t.cfg.Outputs) == 0 {
+		return errors.New("missing member outputs")
+	}
+	for i, mcfg := range t.cfg.Outputs {
+		mType, ok := mcfg["type"].(string)
+		if !ok || mType == "" {
+			return fmt.Errorf("member output %d is missing a type", i)
+		}
+	}
+	// init members
+	t.members = make([]outputs.Output, 0, len(t.cfg.Outputs))
+	for i, mcfg := range t.cfg.Outputs {
+		mType := mcfg["type"].(string)
+		initializer, ok := outputs.Outputs[mType]
+		if !ok {
+			t.closeMembers()
+			return fmt.Errorf("member output %d has an unknown type %q", i, mType)
+		}
+		if format, ok := mcfg["format"]; !ok || format == "" {
+			mcfg["format"] = t.cfg.Format
+		}
+		mName := fmt.Sprintf("%s-%d", name, i)
+		out, err := outputs.WrapOutput(initializer(), mcfg, t.logger)
+		if err != nil {
+			t.closeMembers()
+			return fmt.Errorf("member output %d: %w", i, err)
+		}
+		err = out.Init(ctx, mName, mcfg, opts...)
+		if err != nil {
+			t.closeMembers()
+			return fmt.Errorf("failed to init member output %d of type %q: %w", i, mType, err)
+		}
+		t.members = append(t.members, out)
+		t.logger.Printf("initialized member output %q of type %q", mName, mType)
+	}
+	return nil
+}
+
+func (t *teeOutput) closeMembers() {
+	for _, m := range t.members {
+		m.Close()
+	}
+	t.members = nil
+}
+
+// Write writes the message to all the member outputs concurrently
+// and returns once they all accepted it.
+func (t *teeOutput) Write(ctx context.Context, rsp proto.Message, meta outputs.Meta) {
+	if rsp == nil {
+		return
+	}
+	switch len(t.members) {
+	case 0:
+		return
+	case 1:
+		t.members[0].Write(ctx, rsp, meta)
+		return
+	}
+	wg := new(sync.WaitGroup)
+	wg.Add(len(t.members))
+	for _, m := range t.members {
+		go func(m outputs.Output) {
+			defer wg.Done()
+			m.Write(ctx, rsp, meta)
+		}(m)
+	}
+	wg.Wait()
+}
+
+// WriteEvent writes the event to all the member outputs.
+// Each member gets its own copy since their event processors
+// might modify it.
+func (t *teeOutput) WriteEvent(ctx context.Context, ev *formatters.EventMsg) {
+	for i, m := range t.members {
+		if i == len(t.members)-1 {
+			m.WriteEvent(ctx, ev)
+			return
+		}
+		m.WriteEvent(ctx, formatters.CopyEventMsg(ev))
+	}
+}
+
+// Flush flushes all the member outputs.
+func (t *teeOutput) Flush(ctx context.Context) error {
+	var errs []error
+	for _, m := range t.members {
+		if err := outputs.Flush(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Healthy returns true if all the member outputs are healthy.
+func (t *teeOutput) Healthy() bool {
+	if len(t.members) == 0 {
+		return false
+	}
+	for _, m := range t.members {
+		if !outputs.IsHealthy(m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *teeOutput) Close() error {
+	for i, m := range t.members {
+		err := m.Close()
+		if err != nil {
+			t.logger.Printf("failed to close member output %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// RegisterMetrics is a noop, the member outputs
+// register their metrics when initialized.
+func (t *teeOutput) RegisterMetrics(*prometheus.Registry) {}
+
+func (t *teeOutput) SetName(string) {}
+
+func (t *teeOutput) SetClusterName(string) {}
+
+func (t *teeOutput) SetTargetsConfig(map[string]*types.TargetConfig) {}
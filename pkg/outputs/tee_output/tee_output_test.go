@@ -0,0 +1,187 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tee_output
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openconfig/gnmic/pkg/api/utils"
+	"github.com/openconfig/gnmic/pkg/formatters"
+	"github.com/openconfig/gnmic/pkg/outputs"
+	_ "github.com/openconfig/gnmic/pkg/outputs/file"
+)
+
+func newTee() *teeOutput {
+	return &teeOutput{
+		cfg:    &config{},
+		logger: log.New(io.Discard, loggingPrefix, utils.DefaultLoggingFlags),
+	}
+}
+
+func TestTeeFormats(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	protoFile := filepath.Join(dir, "capture.bin")
+	eventFile := filepath.Join(dir, "capture.json")
+	tee := newTee()
+	err := tee.Init(ctx, "tee1", map[string]interface{}{
+		"format": "event",
+		"outputs": []interface{}{
+			map[string]interface{}{"type": "file", "filename": protoFile, "format": "proto"},
+			map[string]interface{}{"type": "file", "filename": eventFile},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to init tee output: %v", err)
+	}
+	defer tee.Close()
+	if !tee.Healthy() {
+		t.Errorf("expected tee output to be healthy")
+	}
+	rsps := []*gnmi.SubscribeResponse{
+		{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{
+			Timestamp: 1,
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "a"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 1}},
+			}},
+		}}},
+		{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{
+			Timestamp: 2,
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "a"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: 2}},
+			}},
+		}}},
+	}
+	for _, rsp := range rsps {
+		tee.Write(ctx, rsp, outputs.Meta{"source": "router1", "subscription-name": "sub1"})
+	}
+
+	// the proto member writes length delimited messages
+	b, err := os.ReadFile(protoFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(bytes.NewReader(b))
+	for i, want := range rsps {
+		got := new(gnmi.SubscribeResponse)
+		err = protodelim.UnmarshalFrom(br, got)
+		if err != nil {
+			t.Fatalf("failed to read proto message %d: %v", i, err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("unexpected proto message %d: %v", i, got)
+		}
+	}
+	if err = protodelim.UnmarshalFrom(br, new(gnmi.SubscribeResponse)); !errors.Is(err, io.EOF) {
+		t.Errorf("expected the end of the proto capture, got %v", err)
+	}
+
+	// the event member uses the tee default format
+	b, err = os.ReadFile(eventFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	numEvents := 0
+	for dec.More() {
+		evs := make([]*formatters.EventMsg, 0)
+		if err = dec.Decode(&evs); err != nil {
+			t.Fatalf("failed to decode events: %v", err)
+		}
+		for _, ev := range evs {
+			if ev.Name != "sub1" || ev.Tags["source"] != "router1" {
+				t.Errorf("unexpected event: %+v", ev)
+			}
+		}
+		numEvents += len(evs)
+	}
+	if numEvents != len(rsps) {
+		t.Errorf("expected %d events, got %d", len(rsps), numEvents)
+	}
+}
+
+func TestTeeWriteEventCopies(t *testing.T) {
+	ev := &formatters.EventMsg{
+		Name:    "ev1",
+		Tags:    map[string]string{"source": "router1"},
+		Values:  map[string]interface{}{"v": 1},
+		Deletes: []string{"/a"},
+	}
+	cp := formatters.CopyEventMsg(ev)
+	cp.Tags["source"] = "router2"
+	cp.Values["v"] = 2
+	cp.Deletes[0] = "/b"
+	if ev.Tags["source"] != "router1" || ev.Values["v"] != 1 || ev.Deletes[0] != "/a" {
+		t.Errorf("the event copy modified the original event: %+v", ev)
+	}
+}
+
+func TestTeeInitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]interface{}
+	}{
+		{
+			name: "no_members",
+			cfg:  map[string]interface{}{},
+		},
+		{
+			name: "missing_type",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{}},
+			},
+		},
+		{
+			name: "unknown_type",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{"type": "not-an-output"}},
+			},
+		},
+		{
+			name: "member_init_failure",
+			cfg: map[string]interface{}{
+				"outputs": []interface{}{map[string]interface{}{
+					"type":         "file",
+					"file-type":    "stdout",
+					"format":       "proto",
+					"msg-template": "{{ . }}",
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tee := newTee()
+			err := tee.Init(context.Background(), "tee-err", tt.cfg)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			tee.Close()
+			// writing to a tee output without members must not panic
+			tee.Write(context.Background(), &gnmi.SubscribeResponse{}, outputs.Meta{})
+			tee.WriteEvent(context.Background(), &formatters.EventMsg{})
+		})
+	}
+}
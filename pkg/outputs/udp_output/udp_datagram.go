@@ -0,0 +1,95 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package udp_output
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const (
+	oversizeStrategyDrop     = "drop"
+	oversizeStrategyTruncate = "truncate"
+	oversizeStrategySplit    = "split"
+)
+
+// fitDatagrams returns the datagrams to send for payload b
+// given the maximum datagram size and the oversize strategy.
+// The returned bool is false if (part of) the payload was dropped.
+func fitDatagrams(b []byte, max int, strategy string) ([][]byte, bool) {
+	if max <= 0 || len(b) <= max {
+		return [][]byte{b}, true
+	}
+	switch strategy {
+	case oversizeStrategyTruncate:
+		return [][]byte{b[:max]}, true
+	case oversizeStrategySplit:
+		if dgs, ok := splitJSONArray(b, max); dgs != nil {
+			return dgs, ok
+		}
+		return chunk(b, max), true
+	default:
+		return nil, false
+	}
+}
+
+// splitJSONArray regroups the elements of the JSON array b into
+// JSON arrays of at most max bytes.
+// Elements that do not fit in a datagram on their own are dropped.
+// It returns nil if b is not a JSON array.
+func splitJSONArray(b []byte, max int) ([][]byte, bool) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, false
+	}
+	elems := make([]json.RawMessage, 0)
+	if err := json.Unmarshal(trimmed, &elems); err != nil {
+		return nil, false
+	}
+	complete := true
+	dgs := make([][]byte, 0)
+	buf := new(bytes.Buffer)
+	for _, e := range elems {
+		e = bytes.TrimSpace(e)
+		// 2 bytes for the enclosing brackets
+		if len(e)+2 > max {
+			complete = false
+			continue
+		}
+		if buf.Len() > 0 && buf.Len()+1+len(e)+1 > max {
+			buf.WriteByte(']')
+			dgs = append(dgs, buf.Bytes())
+			buf = new(bytes.Buffer)
+		}
+		if buf.Len() == 0 {
+			buf.WriteByte('[')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.Write(e)
+	}
+	if buf.Len() > 0 {
+		buf.WriteByte(']')
+		dgs = append(dgs, buf.Bytes())
+	}
+	return dgs, complete
+}
+
+// chunk splits b into fragments of at most max bytes.
+func chunk(b []byte, max int) [][]byte {
+	dgs := make([][]byte, 0, len(b)/max+1)
+	for len(b) > max {
+		dgs = append(dgs, b[:max])
+		b = b[max:]
+	}
+	if len(b) > 0 {
+		dgs = append(dgs, b)
+	}
+	return dgs
+}
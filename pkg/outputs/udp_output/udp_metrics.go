@@ -0,0 +1,64 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package udp_output
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var udpNumberOfSentMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "udp_output",
+	Name:      "number_of_udp_msgs_sent_success_total",
+	Help:      "Number of datagrams successfully sent by gnmic udp output",
+}, []string{"name"})
+
+var udpNumberOfSentBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "udp_output",
+	Name:      "number_of_written_udp_bytes_total",
+	Help:      "Number of bytes written by gnmic udp output",
+}, []string{"name"})
+
+var udpNumberOfOversizedMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "udp_output",
+	Name:      "number_of_udp_msgs_oversized_total",
+	Help:      "Number of msgs larger than max-datagram-size handled by gnmic udp output",
+}, []string{"name", "action"})
+
+var udpNumberOfSampledOutMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "gnmic",
+	Subsystem: "udp_output",
+	Name:      "number_of_udp_msgs_sampled_out_total",
+	Help:      "Number of msgs not sent by gnmic udp output due to sampling",
+}, []string{"name"})
+
+func initMetrics() {
+	udpNumberOfSentMsgs.WithLabelValues("").Add(0)
+	udpNumberOfSentBytes.WithLabelValues("").Add(0)
+	udpNumberOfOversizedMsgs.WithLabelValues("", "").Add(0)
+	udpNumberOfSampledOutMsgs.WithLabelValues("").Add(0)
+}
+
+func registerMetrics(reg *prometheus.Registry) error {
+	initMetrics()
+	var err error
+	if err = reg.Register(udpNumberOfSentMsgs); err != nil {
+		return err
+	}
+	if err = reg.Register(udpNumberOfSentBytes); err != nil {
+		return err
+	}
+	if err = reg.Register(udpNumberOfOversizedMsgs); err != nil {
+		return err
+	}
+	if err = reg.Register(udpNumberOfSampledOutMsgs); err != nil {
+		return err
+	}
+	return nil
+}
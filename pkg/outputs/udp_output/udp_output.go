@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"net"
 	"text/template"
 	"time"
@@ -31,7 +32,9 @@ import (
 
 const (
 	defaultRetryTimer = 2 * time.Second
-	loggingPrefix     = "[udp_output:%s] "
+	// maximum UDP payload over IPv4
+	defaultMaxDatagramSize = 65507
+	loggingPrefix          = "[udp_output:%s] "
 )
 
 func init() {
@@ -44,31 +47,38 @@ func init() {
 }
 
 type UDPSock struct {
-	Cfg *Config
+	Cfg  *Config
+	name string
 
-	conn     *net.UDPConn
-	cancelFn context.CancelFunc
-	buffer   chan []byte
-	limiter  *time.Ticker
-	logger   *log.Logger
-	mo       *formatters.MarshalOptions
-	evps     []formatters.EventProcessor
+	conn         *net.UDPConn
+	cancelFn     context.CancelFunc
+	buffer       chan []byte
+	limiter      *time.Ticker
+	logger       *log.Logger
+	mo           *formatters.MarshalOptions
+	evps         []formatters.EventProcessor
+	evpOverrides *outputs.EventProcessorsOverrides
 
 	targetTpl *template.Template
 }
 
 type Config struct {
-	Address            string        `mapstructure:"address,omitempty"` // ip:port
-	Rate               time.Duration `mapstructure:"rate,omitempty"`
-	BufferSize         uint          `mapstructure:"buffer-size,omitempty"`
-	Format             string        `mapstructure:"format,omitempty"`
-	AddTarget          string        `mapstructure:"add-target,omitempty"`
-	TargetTemplate     string        `mapstructure:"target-template,omitempty"`
-	OverrideTimestamps bool          `mapstructure:"override-timestamps,omitempty"`
-	SplitEvents        bool          `mapstructure:"split-events,omitempty"`
-	RetryInterval      time.Duration `mapstructure:"retry-interval,omitempty"`
-	EnableMetrics      bool          `mapstructure:"enable-metrics,omitempty"`
-	EventProcessors    []string      `mapstructure:"event-processors,omitempty"`
+	Address            string                   `mapstructure:"address,omitempty"` // ip:port
+	Rate               time.Duration            `mapstructure:"rate,omitempty"`
+	BufferSize         uint                     `mapstructure:"buffer-size,omitempty"`
+	Format             string                   `mapstructure:"format,omitempty"`
+	AddTarget          string                   `mapstructure:"add-target,omitempty"`
+	TargetTemplate     string                   `mapstructure:"target-template,omitempty"`
+	OverrideTimestamps bool                     `mapstructure:"override-timestamps,omitempty"`
+	SplitEvents        bool                     `mapstructure:"split-events,omitempty"`
+	RetryInterval      time.Duration            `mapstructure:"retry-interval,omitempty" default:"2s"`
+	MaxDatagramSize    int                      `mapstructure:"max-datagram-size,omitempty" default:"65507"`
+	OversizeStrategy   string                   `mapstructure:"oversize-strategy,omitempty" default:"drop"`
+	SampleRate         float64                  `mapstructure:"sample-rate,omitempty" default:"1"`
+	Debug              bool                     `mapstructure:"debug,omitempty"`
+	EnableMetrics      bool                     `mapstructure:"enable-metrics,omitempty"`
+	EventProcessors    []string                 `mapstructure:"event-processors,omitempty"`
+	NumberFormat       *formatters.NumberFormat `mapstructure:"number-format,omitempty"`
 }
 
 func (u *UDPSock) SetLogger(logger *log.Logger) {
@@ -82,7 +92,11 @@ func (u *UDPSock) SetEventProcessors(ps map[string]map[string]interface{},
 	logger *log.Logger,
 	tcs map[string]*types.TargetConfig,
 	acts map[string]map[string]interface{}) error {
-	var err error
+	nfps, err := u.Cfg.NumberFormat.Processors()
+	if err != nil {
+		return err
+	}
+	u.evpOverrides = outputs.NewEventProcessorsOverrides(ps, logger, tcs, acts, nfps...)
 	u.evps, err = formatters.MakeEventProcessors(
 		logger,
 		u.Cfg.EventProcessors,
@@ -93,6 +107,7 @@ func (u *UDPSock) SetEventProcessors(ps map[string]map[string]interface{},
 	if err != nil {
 		return err
 	}
+	u.evps = append(u.evps, nfps...)
 	return nil
 }
 
@@ -101,6 +116,7 @@ func (u *UDPSock) Init(ctx context.Context, name string, cfg map[string]interfac
 	if err != nil {
 		return err
 	}
+	u.name = name
 	u.logger.SetPrefix(fmt.Sprintf(loggingPrefix, name))
 
 	for _, opt := range opts {
@@ -112,8 +128,9 @@ func (u *UDPSock) Init(ctx context.Context, name string, cfg map[string]interfac
 	if err != nil {
 		return fmt.Errorf("wrong address format: %v", err)
 	}
-	if u.Cfg.RetryInterval == 0 {
-		u.Cfg.RetryInterval = defaultRetryTimer
+	err = u.setDefaults()
+	if err != nil {
+		return err
 	}
 
 	u.buffer = make(chan []byte, u.Cfg.BufferSize)
@@ -142,6 +159,29 @@ func (u *UDPSock) Init(ctx context.Context, name string, cfg map[string]interfac
 	return nil
 }
 
+func (u *UDPSock) setDefaults() error {
+	if u.Cfg.RetryInterval == 0 {
+		u.Cfg.RetryInterval = defaultRetryTimer
+	}
+	if u.Cfg.MaxDatagramSize <= 0 {
+		u.Cfg.MaxDatagramSize = defaultMaxDatagramSize
+	}
+	switch u.Cfg.OversizeStrategy {
+	case "":
+		u.Cfg.OversizeStrategy = oversizeStrategyDrop
+	case oversizeStrategyDrop, oversizeStrategyTruncate, oversizeStrategySplit:
+	default:
+		return fmt.Errorf("unknown oversize-strategy %q", u.Cfg.OversizeStrategy)
+	}
+	if u.Cfg.SampleRate == 0 {
+		u.Cfg.SampleRate = 1
+	}
+	if u.Cfg.SampleRate < 0 || u.Cfg.SampleRate > 1 {
+		return fmt.Errorf("sample-rate must be in the range (0, 1], got %v", u.Cfg.SampleRate)
+	}
+	return nil
+}
+
 func (u *UDPSock) Write(ctx context.Context, m proto.Message, meta outputs.Meta) {
 	if m == nil {
 		return
@@ -155,13 +195,34 @@ func (u *UDPSock) Write(ctx context.Context, m proto.Message, meta outputs.Meta)
 		if err != nil {
 			u.logger.Printf("failed to add target to the response: %v", err)
 		}
-		bb, err := outputs.Marshal(rsp, meta, u.mo, u.Cfg.SplitEvents, u.evps...)
+		bb, err := outputs.Marshal(rsp, meta, u.mo, u.Cfg.SplitEvents, u.evpOverrides.Select(meta, u.evps)...)
 		if err != nil {
 			u.logger.Printf("failed marshaling proto msg: %v", err)
 			return
 		}
 		for _, b := range bb {
-			u.buffer <- b
+			if u.Cfg.SampleRate < 1 && rand.Float64() >= u.Cfg.SampleRate {
+				if u.Cfg.EnableMetrics {
+					udpNumberOfSampledOutMsgs.WithLabelValues(u.name).Inc()
+				}
+				continue
+			}
+			dgs, complete := fitDatagrams(b, u.Cfg.MaxDatagramSize, u.Cfg.OversizeStrategy)
+			if len(b) > u.Cfg.MaxDatagramSize {
+				action := u.Cfg.OversizeStrategy
+				if u.Cfg.OversizeStrategy == oversizeStrategySplit && !complete {
+					action = "split_partial_drop"
+				}
+				if u.Cfg.Debug {
+					u.logger.Printf("msg size %d exceeds max-datagram-size %d: %s", len(b), u.Cfg.MaxDatagramSize, action)
+				}
+				if u.Cfg.EnableMetrics {
+					udpNumberOfOversizedMsgs.WithLabelValues(u.name, action).Inc()
+				}
+			}
+			for _, dg := range dgs {
+				u.buffer <- dg
+			}
 		}
 	}
 }
@@ -176,7 +237,18 @@ func (u *UDPSock) Close() error {
 	return nil
 }
 
-func (u *UDPSock) RegisterMetrics(reg *prometheus.Registry) {}
+func (u *UDPSock) RegisterMetrics(reg *prometheus.Registry) {
+	if !u.Cfg.EnableMetrics {
+		return
+	}
+	if reg == nil {
+		u.logger.Printf("ERR: output metrics enabled but main registry is not initialized, enable main metrics under `api-server`")
+		return
+	}
+	if err := registerMetrics(reg); err != nil {
+		u.logger.Printf("failed to register metric: %+v", err)
+	}
+}
 
 func (u *UDPSock) String() string {
 	b, err := json.Marshal(u)
@@ -221,6 +293,10 @@ DIAL:
 				time.Sleep(u.Cfg.RetryInterval)
 				goto DIAL
 			}
+			if u.Cfg.EnableMetrics {
+				udpNumberOfSentMsgs.WithLabelValues(u.name).Inc()
+				udpNumberOfSentBytes.WithLabelValues(u.name).Add(float64(len(b)))
+			}
 		}
 	}
 }
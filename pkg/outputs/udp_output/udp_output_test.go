@@ -0,0 +1,101 @@
+// © 2024 Nokia.
+//
+// This code is a Contribution to the gNMIc project (“Work”) made under the Google Software Grant and Corporate Contributor License Agreement (“CLA”) and governed by the Apache License 2.0.
+// No other rights or licenses in or to any of Nokia’s intellectual property are granted for any other purpose.
+// This code is provided on an “as is” basis without any warranties of any kind.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package udp_output
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmic/pkg/api/testutils"
+)
+
+func TestFitDatagrams(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		max      int
+		strategy string
+		want     []string
+		complete bool
+	}{
+		{
+			name:     "under_limit",
+			in:       `{"a":1}`,
+			max:      100,
+			strategy: oversizeStrategyDrop,
+			want:     []string{`{"a":1}`},
+			complete: true,
+		},
+		{
+			name:     "drop",
+			in:       `{"a":1}`,
+			max:      4,
+			strategy: oversizeStrategyDrop,
+			want:     nil,
+			complete: false,
+		},
+		{
+			name:     "truncate",
+			in:       `{"a":1}`,
+			max:      4,
+			strategy: oversizeStrategyTruncate,
+			want:     []string{`{"a"`},
+			complete: true,
+		},
+		{
+			name:     "split_raw",
+			in:       `abcdefghij`,
+			max:      4,
+			strategy: oversizeStrategySplit,
+			want:     []string{"abcd", "efgh", "ij"},
+			complete: true,
+		},
+		{
+			name:     "split_json_array",
+			in:       `[{"a":1},{"b":2},{"c":3}]`,
+			max:      17,
+			strategy: oversizeStrategySplit,
+			want:     []string{`[{"a":1},{"b":2}]`, `[{"c":3}]`},
+			complete: true,
+		},
+		{
+			name:     "split_json_array_drops_large_element",
+			in:       `[{"a":1},{"long":"value"},{"c":3}]`,
+			max:      17,
+			strategy: oversizeStrategySplit,
+			want:     []string{`[{"a":1},{"c":3}]`},
+			complete: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, complete := fitDatagrams([]byte(tt.in), tt.max, tt.strategy)
+			var gots []string
+			for _, g := range got {
+				gots = append(gots, string(g))
+			}
+			if !reflect.DeepEqual(gots, tt.want) {
+				t.Errorf("got %q, want %q", gots, tt.want)
+			}
+			if complete != tt.complete {
+				t.Errorf("got complete=%v, want %v", complete, tt.complete)
+			}
+		})
+	}
+}
+
+func TestSetDefaultsTags(t *testing.T) {
+	u := &UDPSock{Cfg: &Config{}}
+	if err := u.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range testutils.DefaultTagsMismatches(u.Cfg) {
+		t.Error(m)
+	}
+}